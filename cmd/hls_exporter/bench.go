@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/checker"
+	"github.com/iudanet/hls_exporter/internal/config"
+	client "github.com/iudanet/hls_exporter/internal/http"
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+)
+
+// benchOptions флаги подкоманды bench
+type benchOptions struct {
+	url             string
+	concurrency     int
+	duration        time.Duration
+	workers         int
+	checkMode       string
+	timeout         time.Duration
+	validateContent bool
+	output          string
+}
+
+// benchReport итоги нагрузочного прогона
+type benchReport struct {
+	URL         string         `json:"url"`
+	Concurrency int            `json:"concurrency"`
+	Elapsed     time.Duration  `json:"elapsed"`
+	Checks      int            `json:"checks"`
+	Failed      int            `json:"failed"`
+	Errors      map[string]int `json:"errors,omitempty"`
+	// Проверок, сегментов и байт в секунду за время прогона
+	ChecksPerSecond   float64 `json:"checks_per_second"`
+	SegmentsPerSecond float64 `json:"segments_per_second"`
+	BytesPerSecond    float64 `json:"bytes_per_second"`
+	// Перцентили длительности проверки
+	Latency benchLatency `json:"latency"`
+}
+
+type benchLatency struct {
+	Min time.Duration `json:"min"`
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+func newBenchCmd(configPath *string) *cobra.Command {
+	var opts benchOptions
+
+	cmd := &cobra.Command{
+		Use:   "bench --url <url>",
+		Short: "Repeatedly check a stream and report throughput and latency",
+		Long: `Run the full check pipeline against a playlist URL in a loop from
+--concurrency parallel checkers for --duration and report throughput and
+check latency percentiles. Useful both as an origin load test and to size
+exporter deployments: each parallel checker behaves like one more stream.
+
+With an explicit --config the HTTP client settings are taken from the file.
+Exits with status 1 if no check succeeded.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runBench(cmd, *configPath, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.url, "url", "", "Playlist URL to check")
+	flags.IntVar(&opts.concurrency, "concurrency", 1, "Number of parallel checkers")
+	flags.DurationVar(&opts.duration, "duration", time.Minute, "Benchmark duration")
+	flags.IntVar(&opts.workers, "workers", 10, "Segment download workers shared by all checkers (checks.workers)")
+	flags.StringVar(&opts.checkMode, "check-mode", models.CheckModeAll, "Segment selection: all, first_last, random, newest_n or playlist_only")
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "Timeout of a single check")
+	flags.BoolVar(&opts.validateContent, "validate-content", true, "Download and analyze segment content")
+	flags.StringVarP(&opts.output, "output", "o", "text", "Report format: text or json")
+	_ = cmd.MarkFlagRequired("url")
+	return cmd
+}
+
+// runBench выполняет нагрузочный прогон и печатает отчет
+func runBench(cmd *cobra.Command, configPath string, opts benchOptions) error {
+	if opts.output != "text" && opts.output != "json" {
+		return fmt.Errorf("invalid output format: %s", opts.output)
+	}
+	if opts.concurrency < 1 {
+		return fmt.Errorf("concurrency must be positive: %d", opts.concurrency)
+	}
+	if opts.workers < 1 {
+		return fmt.Errorf("workers must be positive: %d", opts.workers)
+	}
+	if opts.duration <= 0 {
+		return fmt.Errorf("duration must be positive: %s", opts.duration)
+	}
+
+	httpCfg := models.HTTPConfig{
+		Timeout:         opts.timeout,
+		MaxIdleConns:    opts.concurrency * 10,
+		TLSVerify:       true,
+		UserAgent:       "hls_exporter/" + version,
+		RequestIDHeader: "X-Request-ID",
+	}
+	if cmd.Flags().Changed("config") {
+		cfg, err := config.NewConfigManager().LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		httpCfg = cfg.HTTPClient
+	}
+
+	stream := models.StreamConfig{
+		Name:            "bench",
+		URL:             opts.url,
+		CheckMode:       opts.checkMode,
+		Timeout:         opts.timeout,
+		Interval:        opts.timeout + time.Second,
+		ValidateContent: opts.validateContent,
+	}
+	if err := config.NewValidator().ValidateStream(&stream, 0); err != nil {
+		return err
+	}
+
+	report, err := bench(cmd.Context(), httpCfg, stream, opts)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if opts.output == "json" {
+		err = writeJSONReport(out, report)
+	} else {
+		err = writeBenchReport(out, report)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	if report.Checks == report.Failed {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// bench проверяет стрим в цикле из opts.concurrency горутин общим чекером, как экспортер
+// проверяет opts.concurrency стримов. Новые проверки не начинаются после opts.duration,
+// начатые завершаются.
+func bench(ctx context.Context, httpCfg models.HTTPConfig, stream models.StreamConfig, opts benchOptions) (*benchReport, error) {
+	httpClient := client.NewClient(httpCfg)
+	defer httpClient.Close()
+
+	// Метрики прогона не публикуются
+	streamChecker := checker.NewStreamChecker(
+		httpClient,
+		checker.NewHLSValidator(),
+		metrics.NewCollector(prometheus.NewRegistry()),
+		opts.workers,
+	)
+	if err := streamChecker.Start(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = streamChecker.Stop() }()
+	httpClient.SetTimeout(stream.Timeout)
+
+	var (
+		mu        sync.Mutex
+		durations []time.Duration
+		report    = &benchReport{URL: stream.URL, Concurrency: opts.concurrency, Errors: make(map[string]int)}
+		segments  int
+		bytes     int64
+		wg        sync.WaitGroup
+	)
+	start := time.Now()
+	deadline := start.Add(opts.duration)
+	for i := range opts.concurrency {
+		// Каждый чекер - отдельный стрим: состояние между проверками не смешивается
+		cfg := stream
+		cfg.Name = fmt.Sprintf("bench_%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil && time.Now().Before(deadline) {
+				checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+				checkStart := time.Now()
+				result, err := streamChecker.Check(checkCtx, cfg)
+				elapsed := time.Since(checkStart)
+				cancel()
+
+				mu.Lock()
+				report.Checks++
+				durations = append(durations, elapsed)
+				if result != nil {
+					segments += result.Segments.Checked
+					bytes += result.BytesDownloaded
+				}
+				if err != nil || result == nil || !result.Success {
+					report.Failed++
+					report.Errors[benchErrorType(result)]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report.Elapsed = time.Since(start)
+	if seconds := report.Elapsed.Seconds(); seconds > 0 {
+		report.ChecksPerSecond = float64(report.Checks) / seconds
+		report.SegmentsPerSecond = float64(segments) / seconds
+		report.BytesPerSecond = float64(bytes) / seconds
+	}
+	report.Latency = latencyPercentiles(durations)
+	return report, nil
+}
+
+// benchErrorType тип ошибки неуспешной проверки для отчета
+func benchErrorType(result *models.CheckResult) string {
+	if result == nil || result.Error == nil {
+		return "unknown"
+	}
+	return string(result.Error.Type)
+}
+
+// latencyPercentiles вычисляет перцентили длительностей методом ближайшего ранга
+func latencyPercentiles(durations []time.Duration) benchLatency {
+	if len(durations) == 0 {
+		return benchLatency{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := func(p float64) time.Duration {
+		i := int(math.Ceil(float64(len(sorted))*p)) - 1
+		return sorted[max(i, 0)]
+	}
+	return benchLatency{
+		Min: sorted[0],
+		P50: rank(0.5),
+		P90: rank(0.9),
+		P99: rank(0.99),
+		Max: sorted[len(sorted)-1],
+	}
+}
+
+func writeBenchReport(w io.Writer, report *benchReport) error {
+	lines := []string{
+		fmt.Sprintf("Stream:       %s", report.URL),
+		fmt.Sprintf("Concurrency:  %d", report.Concurrency),
+		fmt.Sprintf("Elapsed:      %s", report.Elapsed.Round(time.Millisecond)),
+		fmt.Sprintf("Checks:       %d (%d failed)", report.Checks, report.Failed),
+		fmt.Sprintf("Throughput:   %.2f checks/s, %.2f segments/s, %.2f MB/s",
+			report.ChecksPerSecond, report.SegmentsPerSecond, report.BytesPerSecond/1e6),
+		fmt.Sprintf("Latency:      min %s, p50 %s, p90 %s, p99 %s, max %s",
+			report.Latency.Min.Round(time.Millisecond),
+			report.Latency.P50.Round(time.Millisecond),
+			report.Latency.P90.Round(time.Millisecond),
+			report.Latency.P99.Round(time.Millisecond),
+			report.Latency.Max.Round(time.Millisecond)),
+	}
+	errTypes := make([]string, 0, len(report.Errors))
+	for errType := range report.Errors {
+		errTypes = append(errTypes, errType)
+	}
+	sort.Strings(errTypes)
+	for _, errType := range errTypes {
+		lines = append(lines, fmt.Sprintf("  FAIL %s: %d", errType, report.Errors[errType]))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
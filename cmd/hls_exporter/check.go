@@ -0,0 +1,195 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/checker"
+	"github.com/iudanet/hls_exporter/internal/config"
+	client "github.com/iudanet/hls_exporter/internal/http"
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+)
+
+// checkOptions флаги подкоманды check
+type checkOptions struct {
+	checkMode       string
+	timeout         time.Duration
+	validateContent bool
+	output          string
+}
+
+func newCheckCmd(configPath *string) *cobra.Command {
+	var opts checkOptions
+
+	cmd := &cobra.Command{
+		Use:   "check <url|stream>",
+		Short: "Run a single stream check and print a report",
+		Long: `Run a single check of a playlist URL and print a report.
+Exits with status 1 if the check fails.
+
+With an explicit --config the HTTP client settings are taken from the file,
+and the argument may name a configured stream; its settings are used unless
+overridden by flags.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheck(cmd, *configPath, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.checkMode, "check-mode", models.CheckModeAll, "Segment selection: all, first_last, random, newest_n or playlist_only")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "Check timeout")
+	flags.BoolVar(&opts.validateContent, "validate-content", true, "Download and analyze segment content")
+	flags.StringVarP(&opts.output, "output", "o", "text", "Report format: text or json")
+	return cmd
+}
+
+// runCheck выполняет разовую проверку и печатает отчет
+func runCheck(cmd *cobra.Command, configPath, target string, opts checkOptions) error {
+	if opts.output != "text" && opts.output != "json" {
+		return fmt.Errorf("invalid output format: %s", opts.output)
+	}
+
+	httpCfg := models.HTTPConfig{
+		Timeout:      opts.timeout,
+		MaxIdleConns: 10,
+		TLSVerify:    true,
+		UserAgent:    "hls_exporter/" + version,
+		// Тот же заголовок, что и по умолчанию в конфигурации
+		RequestIDHeader: "X-Request-ID",
+	}
+	stream := models.StreamConfig{Name: "check", URL: target}
+	fromConfig := false
+
+	if cmd.Flags().Changed("config") {
+		cfg, err := config.NewConfigManager().LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		httpCfg = cfg.HTTPClient
+		stream, fromConfig = findStream(cfg.Streams, target)
+		stream.SegmentSample = cmp.Or(stream.SegmentSample, cfg.Checks.SegmentSample)
+	}
+
+	// Параметры настроенного стрима переопределяются только явно заданными флагами
+	override := func(name string) bool { return !fromConfig || cmd.Flags().Changed(name) }
+	if override("check-mode") {
+		stream.CheckMode = opts.checkMode
+	}
+	if override("timeout") {
+		stream.Timeout = opts.timeout
+	}
+	if override("validate-content") {
+		stream.ValidateContent = opts.validateContent
+	}
+	// Разовая проверка не использует расписания
+	stream.Interval = stream.Timeout + time.Second
+	stream.DeepCheck = nil
+
+	if err := config.NewValidator().ValidateStream(&stream, 0); err != nil {
+		return err
+	}
+
+	result, checkErr := checkOnce(cmd.Context(), httpCfg, stream)
+
+	out := cmd.OutOrStdout()
+	var err error
+	if opts.output == "json" {
+		err = writeJSONReport(out, result)
+	} else {
+		err = writeTextReport(out, stream, result)
+	}
+	if err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	if checkErr != nil || result == nil || !result.Success {
+		return &exitError{code: 1}
+	}
+	return nil
+}
+
+// findStream ищет стрим конфигурации по имени или URL
+func findStream(streams []models.StreamConfig, target string) (models.StreamConfig, bool) {
+	for _, stream := range streams {
+		if stream.Name == target || stream.URL == target {
+			return stream, true
+		}
+	}
+	return models.StreamConfig{Name: "check", URL: target}, false
+}
+
+func checkOnce(ctx context.Context, httpCfg models.HTTPConfig, stream models.StreamConfig) (*models.CheckResult, error) {
+	httpClient := client.NewClient(httpCfg)
+	defer httpClient.Close()
+
+	// Метрики разовой проверки не публикуются
+	streamChecker := checker.NewStreamChecker(
+		httpClient,
+		checker.NewHLSValidator(),
+		metrics.NewCollector(prometheus.NewRegistry()),
+		5,
+	)
+	if err := streamChecker.Start(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = streamChecker.Stop() }()
+	httpClient.SetTimeout(stream.Timeout)
+
+	ctx, cancel := context.WithTimeout(ctx, stream.Timeout)
+	defer cancel()
+	return streamChecker.Check(ctx, stream)
+}
+
+func writeJSONReport(w io.Writer, report any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeTextReport(w io.Writer, stream models.StreamConfig, result *models.CheckResult) error {
+	status := "OK"
+	if result == nil || !result.Success {
+		status = "FAILED"
+	}
+
+	lines := []string{
+		fmt.Sprintf("Stream:    %s", stream.URL),
+		fmt.Sprintf("Status:    %s", status),
+	}
+	if result != nil {
+		lines = append(lines, fmt.Sprintf("Check ID:  %s", result.CheckID))
+		if result.Error != nil {
+			lines = append(lines, fmt.Sprintf("Error:     %s: %s", result.Error.Type, result.Error.Message))
+		}
+		lines = append(lines,
+			fmt.Sprintf("Duration:  %s", result.Duration.Round(time.Millisecond)),
+			fmt.Sprintf("Variants:  %d", result.StreamStatus.VariantsCount),
+			fmt.Sprintf("Segments:  %d checked, %d failed", result.Segments.Checked, result.Segments.Failed),
+			fmt.Sprintf("Bytes:     %d", result.BytesDownloaded),
+		)
+		if !result.StreamStatus.IsLive {
+			length := time.Duration(result.StreamStatus.TotalDuration * float64(time.Second))
+			lines = append(lines, fmt.Sprintf("VOD:       %s", length.Round(time.Second)))
+		}
+		for _, seg := range result.Segments.Details {
+			if !seg.Success && seg.Error != nil {
+				lines = append(lines, fmt.Sprintf("  FAIL %s: %s", seg.URL, seg.Error.Message))
+			}
+		}
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// exitError завершает процесс с заданным кодом без вывода ошибки:
+// подкоманда уже сообщила результат
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func newRootCmd() *cobra.Command {
+	var configPath string
+
+	root := &cobra.Command{
+		Use:   "hls_exporter",
+		Short: "Prometheus exporter for HLS stream availability and quality",
+		Args:  cobra.NoArgs,
+		// Без подкоманды экспортер запускается как serve для совместимости
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runServe(configPath)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.PersistentFlags().StringVar(&configPath, "config", "config.yaml", "Path to configuration file")
+
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve",
+			Short: "Run periodic stream checks and serve metrics",
+			Args:  cobra.NoArgs,
+			RunE: func(_ *cobra.Command, _ []string) error {
+				return runServe(configPath)
+			},
+		},
+		newCheckCmd(&configPath),
+		newValidateConfigCmd(&configPath),
+		newImportM3UCmd(&configPath),
+		newBenchCmd(&configPath),
+		&cobra.Command{
+			Use:   "version",
+			Short: "Print version",
+			Args:  cobra.NoArgs,
+			Run: func(cmd *cobra.Command, _ []string) {
+				fmt.Fprintln(cmd.OutOrStdout(), version)
+			},
+		},
+		&cobra.Command{
+			Use:   "testorigin",
+			Short: "Run a synthetic HLS origin for manual testing",
+			// Флаги разбирает runTestOrigin
+			DisableFlagParsing: true,
+			RunE: func(_ *cobra.Command, args []string) error {
+				return runTestOrigin(args)
+			},
+		},
+	)
+	return root
+}
+
+// normalizeArgs переводит однодефисную форму -config из прежней версии
+// в --config, которую понимает cobra
+func normalizeArgs(args []string) []string {
+	normalized := make([]string, 0, len(args))
+	for i, arg := range args {
+		// Аргументы testorigin разбираются пакетом flag
+		if arg == "testorigin" {
+			return append(normalized, args[i:]...)
+		}
+		if arg == "-config" || strings.HasPrefix(arg, "-config=") {
+			arg = "-" + arg
+		}
+		normalized = append(normalized, arg)
+	}
+	return normalized
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/testorigin"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeRoot(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	root := newRootCmd()
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(normalizeArgs(args))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestNormalizeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "legacy flag",
+			args: []string{"-config", "a.yaml"},
+			want: []string{"--config", "a.yaml"},
+		},
+		{
+			name: "legacy flag with value",
+			args: []string{"-config=a.yaml", "serve"},
+			want: []string{"--config=a.yaml", "serve"},
+		},
+		{
+			name: "new flag",
+			args: []string{"check", "--config", "a.yaml", "-o", "json"},
+			want: []string{"check", "--config", "a.yaml", "-o", "json"},
+		},
+		{
+			name: "testorigin args untouched",
+			args: []string{"testorigin", "-config", "x"},
+			want: []string{"testorigin", "-config", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeArgs(tt.args))
+		})
+	}
+}
+
+func TestVersionCmd(t *testing.T) {
+	out, err := executeRoot(t, "version")
+	require.NoError(t, err)
+	assert.Equal(t, version+"\n", out)
+}
+
+func TestCheckCmd(t *testing.T) {
+	origin := testorigin.New(testorigin.DefaultConfig())
+	server := httptest.NewServer(origin)
+	defer server.Close()
+	url := server.URL + origin.MasterURL()
+
+	t.Run("json report", func(t *testing.T) {
+		out, err := executeRoot(t, "check", url, "--check-mode", models.CheckModeFirstLast, "-o", "json")
+		require.NoError(t, err, out)
+
+		var result models.CheckResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.True(t, result.Success)
+		assert.Equal(t, 2, result.StreamStatus.VariantsCount)
+		assert.Positive(t, result.Segments.Checked)
+	})
+
+	t.Run("text report", func(t *testing.T) {
+		out, err := executeRoot(t, "check", url, "--check-mode", models.CheckModePlaylistOnly)
+		require.NoError(t, err, out)
+		assert.Contains(t, out, "Status:    OK")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		origin.SetFaults(testorigin.Faults{PlaylistStatus: http.StatusNotFound})
+		defer origin.SetFaults(testorigin.Faults{})
+
+		out, err := executeRoot(t, "check", url, "--timeout", "5s")
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.code)
+		assert.Contains(t, out, "Status:    FAILED")
+	})
+
+	t.Run("stream from config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+streams:
+  - name: "origin"
+    url: "`+url+`"
+    check_mode: "playlist_only"
+    interval: "10s"
+    timeout: "5s"
+`), 0o600))
+
+		out, err := executeRoot(t, "check", "origin", "--config", path, "-o", "json")
+		require.NoError(t, err, out)
+
+		var result models.CheckResult
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+		assert.True(t, result.Success)
+		assert.Zero(t, result.Segments.Checked)
+		assert.Less(t, result.Duration, 5*time.Second)
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		_, err := executeRoot(t, "check", url, "-o", "xml")
+		assert.ErrorContains(t, err, "invalid output format")
+	})
+}
+
+func TestBenchCmd(t *testing.T) {
+	origin := testorigin.New(testorigin.DefaultConfig())
+	server := httptest.NewServer(origin)
+	defer server.Close()
+	url := server.URL + origin.MasterURL()
+
+	t.Run("json report", func(t *testing.T) {
+		out, err := executeRoot(t, "bench", "--url", url, "--concurrency", "3", "--duration", "300ms",
+			"--check-mode", models.CheckModeFirstLast, "-o", "json")
+		require.NoError(t, err, out)
+
+		var report benchReport
+		require.NoError(t, json.Unmarshal([]byte(out), &report))
+		assert.Equal(t, 3, report.Concurrency)
+		assert.GreaterOrEqual(t, report.Checks, 3)
+		assert.Zero(t, report.Failed)
+		assert.Positive(t, report.ChecksPerSecond)
+		assert.Positive(t, report.SegmentsPerSecond)
+		assert.Positive(t, report.Latency.P50)
+		assert.LessOrEqual(t, report.Latency.P50, report.Latency.P99)
+		assert.LessOrEqual(t, report.Latency.P99, report.Latency.Max)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		origin.SetFaults(testorigin.Faults{PlaylistStatus: http.StatusNotFound})
+		defer origin.SetFaults(testorigin.Faults{})
+
+		out, err := executeRoot(t, "bench", "--url", url, "--duration", "100ms")
+		var exitErr *exitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Contains(t, out, "FAIL playlist_download:")
+	})
+
+	t.Run("url required", func(t *testing.T) {
+		_, err := executeRoot(t, "bench")
+		assert.ErrorContains(t, err, `required flag(s) "url" not set`)
+	})
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	durations := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, benchLatency{
+		Min: time.Millisecond,
+		P50: 50 * time.Millisecond,
+		P90: 90 * time.Millisecond,
+		P99: 99 * time.Millisecond,
+		Max: 100 * time.Millisecond,
+	}, latencyPercentiles(durations))
+	assert.Equal(t, benchLatency{}, latencyPercentiles(nil))
+}
+
+func TestValidateConfigCmd(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+streams:
+  - name: "test"
+    url: "http://example.com/master.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+`), 0o600))
+
+	out, err := executeRoot(t, "validate-config", "-config", valid)
+	require.NoError(t, err)
+	assert.Equal(t, valid+": OK\n", out)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+server:
+  port: 70000
+streams:
+  - name: "a"
+    url: "http://example.com/a.m3u8"
+    check_mode: "sometimes"
+    interval: "30s"
+    timeout: "1m"
+  - name: ""
+    url: "http://example.com/b.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+`), 0o600))
+
+	out, err = executeRoot(t, "validate-config", "--config", invalid)
+	var exitErr *exitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 1, exitErr.code)
+	assert.Equal(t, invalid+`: 4 problem(s)
+  - invalid server port: 70000
+  - stream[0]: invalid check_mode: sometimes
+  - stream[0]: timeout must be less than interval
+  - stream[1]: name cannot be empty
+`, out)
+
+	out, err = executeRoot(t, "validate-config", "--config", filepath.Join(dir, "missing.yaml"))
+	require.ErrorAs(t, err, &exitErr)
+	assert.Contains(t, out, "1 problem(s)")
+}
+
+func TestImportM3UCmd(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "channels.m3u")
+	require.NoError(t, os.WriteFile(list, []byte(`#EXTM3U
+#EXTINF:-1 tvg-id="n1" group-title="News",News One
+http://cdn.example.com/news1/index.m3u8
+#EXTINF:-1 group-title="Sports",Sport "Live"
+http://cdn.example.com/sport/index.m3u8
+`), 0o600))
+
+	existing := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(existing, []byte(`
+streams:
+  - name: "news_one"
+    url: "http://example.com/master.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+`), 0o600))
+
+	out, err := executeRoot(t, "import-m3u", list, "--config", existing)
+	require.NoError(t, err, out)
+	assert.Contains(t, out, "  # News / News One\n  - name: \"news_one_2\"\n")
+	assert.Contains(t, out, `check_mode: "first_last"`)
+
+	// Результат импорта - корректная секция streams
+	imported := filepath.Join(dir, "imported.yaml")
+	require.NoError(t, os.WriteFile(imported, []byte(out), 0o600))
+	out, err = executeRoot(t, "validate-config", "--config", imported)
+	require.NoError(t, err, out)
+
+	out, err = executeRoot(t, "import-m3u", list, "--group", "Sports", "--profile", "iptv")
+	require.NoError(t, err, out)
+	assert.Equal(t, `streams:
+  # Sports / Sport "Live"
+  - name: "sport_live"
+    url: "http://cdn.example.com/sport/index.m3u8"
+    group: "Sports"
+    profile: "iptv"
+`, out)
+
+	_, err = executeRoot(t, "import-m3u", list, "--group", "Movies")
+	assert.ErrorContains(t, err, "no channels found")
+}
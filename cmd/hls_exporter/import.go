@@ -0,0 +1,158 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/config"
+	"github.com/iudanet/hls_exporter/internal/iptv"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// importOptions флаги подкоманды import-m3u
+type importOptions struct {
+	profile   string
+	checkMode string
+	interval  time.Duration
+	timeout   time.Duration
+	groups    []string
+}
+
+func newImportM3UCmd(configPath *string) *cobra.Command {
+	var opts importOptions
+
+	cmd := &cobra.Command{
+		Use:   "import-m3u <file|url>",
+		Short: "Convert an IPTV M3U channel list into stream configs",
+		Long: `Read an IPTV M3U channel list (a file, a URL or - for stdin) and print
+a streams section for the configuration file, one stream per channel.
+
+Stream names are derived from the channel names. With an explicit --config,
+names of already configured streams are not reused.
+
+Without --profile, check_mode, interval and timeout default to first_last,
+30s and 10s; with a profile they are printed only when set by flags.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImportM3U(cmd, *configPath, args[0], opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.profile, "profile", "", "Profile for imported streams")
+	flags.StringVar(&opts.checkMode, "check-mode", "", "Segment selection for imported streams")
+	flags.DurationVar(&opts.interval, "interval", 0, "Check interval for imported streams")
+	flags.DurationVar(&opts.timeout, "timeout", 0, "Check timeout for imported streams")
+	flags.StringSliceVar(&opts.groups, "group", nil, "Import only channels of these groups (group-title)")
+	return cmd
+}
+
+// runImportM3U читает список каналов и печатает секцию streams
+func runImportM3U(cmd *cobra.Command, configPath, source string, opts importOptions) error {
+	var taken []string
+	if cmd.Flags().Changed("config") {
+		cfg, err := config.NewConfigManager().LoadConfig(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		for _, stream := range cfg.Streams {
+			taken = append(taken, stream.Name)
+		}
+	}
+
+	body, err := readImportSource(cmd, source)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	channels, err := iptv.Parse(body)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", source, err)
+	}
+	if len(opts.groups) > 0 {
+		channels = slices.DeleteFunc(channels, func(ch iptv.Channel) bool {
+			return !slices.Contains(opts.groups, ch.Group)
+		})
+	}
+	if len(channels) == 0 {
+		return fmt.Errorf("no channels found in %s", source)
+	}
+
+	template := models.StreamConfig{
+		Profile:   opts.profile,
+		CheckMode: opts.checkMode,
+		Interval:  opts.interval,
+		Timeout:   opts.timeout,
+	}
+	if opts.profile == "" {
+		template.CheckMode = cmp.Or(template.CheckMode, models.CheckModeFirstLast)
+		template.Interval = cmp.Or(template.Interval, 30*time.Second)
+		template.Timeout = cmp.Or(template.Timeout, 10*time.Second)
+	}
+
+	writeImportedStreams(cmd.OutOrStdout(), channels, iptv.Streams(channels, template, taken))
+	return nil
+}
+
+// readImportSource открывает файл, URL или stdin ("-")
+func readImportSource(cmd *cobra.Command, source string) (io.ReadCloser, error) {
+	switch {
+	case source == "-":
+		return io.NopCloser(cmd.InOrStdin()), nil
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", source, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to download %s: status %d", source, resp.StatusCode)
+		}
+		return resp.Body, nil
+	}
+	return os.Open(source)
+}
+
+// writeImportedStreams печатает секцию streams в формате YAML
+func writeImportedStreams(out io.Writer, channels []iptv.Channel, streams []models.StreamConfig) {
+	fmt.Fprintln(out, "streams:")
+	for i, stream := range streams {
+		if group := channels[i].Group; group != "" {
+			fmt.Fprintf(out, "  # %s / %s\n", oneLine(group), oneLine(channels[i].Name))
+		} else {
+			fmt.Fprintf(out, "  # %s\n", oneLine(channels[i].Name))
+		}
+		fmt.Fprintf(out, "  - name: %s\n", strconv.Quote(stream.Name))
+		fmt.Fprintf(out, "    url: %s\n", strconv.Quote(stream.URL))
+		if stream.Group != "" {
+			fmt.Fprintf(out, "    group: %s\n", strconv.Quote(oneLine(stream.Group)))
+		}
+		if stream.Profile != "" {
+			fmt.Fprintf(out, "    profile: %s\n", strconv.Quote(stream.Profile))
+		}
+		if stream.CheckMode != "" {
+			fmt.Fprintf(out, "    check_mode: %s\n", strconv.Quote(stream.CheckMode))
+		}
+		if stream.Interval > 0 {
+			fmt.Fprintf(out, "    interval: %q\n", stream.Interval.String())
+		}
+		if stream.Timeout > 0 {
+			fmt.Fprintf(out, "    timeout: %q\n", stream.Timeout.String())
+		}
+	}
+}
+
+// oneLine убирает переводы строк из комментария
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
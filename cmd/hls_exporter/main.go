@@ -1,44 +1,76 @@
 package main
 
 import (
+	"cmp"
 	"context"
-	"flag"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/iudanet/hls_exporter/internal/api"
 	"github.com/iudanet/hls_exporter/internal/checker"
+	"github.com/iudanet/hls_exporter/internal/cluster"
 	"github.com/iudanet/hls_exporter/internal/config"
+	"github.com/iudanet/hls_exporter/internal/eventlog"
+	"github.com/iudanet/hls_exporter/internal/faults"
+	"github.com/iudanet/hls_exporter/internal/grpcapi"
+	"github.com/iudanet/hls_exporter/internal/history"
+	"github.com/iudanet/hls_exporter/internal/hook"
+	"github.com/iudanet/hls_exporter/internal/hostbudget"
 	client "github.com/iudanet/hls_exporter/internal/http"
+	"github.com/iudanet/hls_exporter/internal/labels"
 	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/internal/plugins"
+	"github.com/iudanet/hls_exporter/internal/scheduler"
+	"github.com/iudanet/hls_exporter/internal/silence"
+	"github.com/iudanet/hls_exporter/internal/soak"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/internal/webauth"
 	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-var (
-	configFile = flag.String("config", "config.yaml", "Path to configuration file")
-)
+// version задается при сборке: -ldflags "-X main.version=..."
+var version = "dev"
 
 func main() {
-	flag.Parse()
+	cmd := newRootCmd()
+	cmd.SetArgs(normalizeArgs(os.Args[1:]))
+	if err := cmd.Execute(); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runServe запускает экспортер: периодические проверки стримов, метрики и API
+func runServe(configPath string) error {
 	// Загрузка конфигурации
 	configLoader := config.NewConfigManager()
-	cfg, err := configLoader.LoadConfig(*configFile)
+	cfg, err := configLoader.LoadConfig(configPath)
 	if err != nil {
-		fmt.Printf("Failed to load configuration: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 	// Инициализация логгера
 	logger, err := initLogger(cfg.Logging)
 	if err != nil {
-		fmt.Printf("Failed to initialize logger: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to initialize logger: %w", err)
 	}
 	defer func() {
 		err := logger.Sync()
@@ -48,11 +80,32 @@ func main() {
 	}()
 
 	// Инициализация компонентов
-	metricsCollector := metrics.NewCollector(nil) // nil использует DefaultRegisterer
+	// nil использует DefaultRegisterer
+	metricsCollector := metrics.NewCollectorWithOptions(nil, metrics.Options{
+		CheckDurationBuckets: cfg.Checks.DurationBuckets,
+		SLAWindows:           cfg.Checks.SLAWindows,
+		SilencedLabel:        cfg.Checks.SilencedLabel,
+	})
 
-	httpClient := client.NewClient(cfg.HTTPClient)
+	httpClient := withFaultInjection(client.NewClient(cfg.HTTPClient), cfg.FaultInjection, logger)
+	// Общее ограничение частоты всех запросов. Оборачивается первым, чтобы запрос,
+	// ожидающий бюджета хоста, не занимал общий бюджет.
+	if rate := cfg.Checks.MaxRequestsPerSecond; rate > 0 {
+		limit := hostbudget.New(models.HostBudgetConfig{RequestsPerSecond: rate}, func(_ string, wait time.Duration) {
+			metricsCollector.AddRequestLimitWait(wait)
+		})
+		httpClient = hostbudget.WrapGlobal(httpClient, limit)
+	}
+	// Общее ограничение частоты запросов к хосту для всех его стримов
+	hostSpacing := cfg.Checks.HostBudget.RequestsPerSecond > 0
+	if hostSpacing {
+		httpClient = hostbudget.Wrap(httpClient, hostbudget.New(cfg.Checks.HostBudget, metricsCollector.AddHostBudgetWait))
+	}
 	defer httpClient.Close()
 	validator := checker.NewHLSValidator()
+	validator.SetMaxDurationCV(cfg.Checks.SegmentDurationMaxCV)
+	validator.SetTargetDurationCheck(cfg.Checks.TargetDurationCheck)
+	validator.SetDurationTolerance(cfg.Checks.SegmentDurationTolerance)
 
 	// Инициализация чекера
 	streamChecker := checker.NewStreamChecker(
@@ -61,20 +114,206 @@ func main() {
 		metricsCollector,
 		cfg.Checks.Workers,
 	)
+	streamChecker.SetMaxConcurrencyPerCheck(cfg.Checks.MaxConcurrencyPerCheck)
+	streamChecker.SetSegmentSample(cfg.Checks.SegmentSample)
+	streamChecker.SetKeyCacheTTL(cfg.Checks.KeyCacheTTL)
+	streamChecker.SetRetry(cfg.Checks.RetryAttempts, cfg.Checks.RetryDelay)
+	streamChecker.SetTracing(cfg.Tracing.Enabled)
+
+	// Журналы HTTP-транзакций неуспешных проверок для debug API
+	var transactions models.TransactionStore
+	if cfg.Checks.TransactionLog.Enabled {
+		txStore := store.NewTransactionStore()
+		streamChecker.SetTransactionLog(txStore, cfg.Checks.TransactionLog.MaxBodyBytes)
+		transactions = txStore
+	}
+
+	// Внешние валидаторы из Go plugin и модулей WASM
+	if len(cfg.Plugins) > 0 {
+		validators, err := plugins.Load(cfg.Plugins)
+		if err != nil {
+			return fmt.Errorf("failed to load plugins: %w", err)
+		}
+		defer plugins.Close(validators)
+		streamChecker.SetExternalValidators(validators)
+	}
+
+	// Режим длительного прогона: контроль ресурсов процесса и задач проверок
+	if cfg.Soak.Enabled {
+		monitor := soak.NewMonitor(cfg.Soak, nil, logger)
+		monitor.SetTaskSource(streamChecker.ActiveTasks)
+		streamChecker.SetLeakHandler(monitor.RecordLeak)
+		go monitor.Run(context.Background())
+	}
 
 	// Запуск чекера
 	if err := streamChecker.Start(); err != nil {
 		logger.Fatal("Failed to start stream checker", zap.Error(err))
 	}
 
+	// Временные переопределения параметров стримов через admin API
+	overrides := override.NewStore()
+	// Последние результаты проверок для /api/v1/results
+	results := store.NewResultStore()
+	// Текущие инциденты стримов для /api/v1/incidents
+	incidents := store.NewIncidentStore()
+
+	// История всех проверок в SQLite для /api/v1/streams/{name}/history
+	var (
+		checkHistory models.HistoryStore
+		historyStore *history.Store
+	)
+	if cfg.History.Enabled {
+		historyStore, err = history.Open(cfg.History.Path, cfg.History.Retention)
+		if err != nil {
+			return err
+		}
+		defer historyStore.Close()
+		checkHistory = historyStore
+	}
+
+	// Журнал результатов проверок в JSON Lines для SIEM
+	var eventLog models.ResultRecorder
+	if cfg.EventLog.Enabled {
+		writer, err := eventlog.Open(cfg.EventLog)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+		eventLog = writer
+	}
+
+	// В режиме кластера экземпляр проверяет и публикует метрики только своих стримов
+	shard, err := cluster.New(cfg.Cluster)
+	if err != nil {
+		return fmt.Errorf("failed to initialize cluster mode: %w", err)
+	}
+
+	// Правила подавления оповещений на время плановых работ
+	var silences models.Silencer
+	if len(cfg.Silences) > 0 {
+		set, err := silence.New(cfg.Silences, labels.Configured(cfg.Streams))
+		if err != nil {
+			return fmt.Errorf("failed to initialize silences: %w", err)
+		}
+		silences = set
+	}
+
+	// Планировщик периодических проверок; набор стримов можно менять через API
+	sched := scheduler.New(scheduler.Dependencies{
+		Checker:   streamChecker,
+		Metrics:   metricsCollector,
+		Results:   results,
+		Overrides: overrides,
+		Logger:    logger,
+		Shard:     shard,
+
+		CollectOnScrape:     cfg.Checks.CollectOnScrape,
+		ScrapeConcurrency:   cmp.Or(cfg.Checks.ScrapeConcurrency, cfg.Checks.Workers),
+		StaggerStart:        cfg.Checks.StaggerStart,
+		Jitter:              cfg.Checks.Jitter,
+		OverrunPolicy:       cfg.Checks.OverrunPolicy,
+		Silences:            silences,
+		Hook:                hook.New(),
+		Incidents:           incidents,
+		History:             checkHistory,
+		HostSpacing:         hostSpacing,
+		MaxFastRetryStreams: cfg.Checks.MaxFastRetryStreams,
+		EventLog:            eventLog,
+	})
+	owned := 0
+	for _, streamCfg := range cfg.Streams {
+		if err := sched.Add(streamCfg); err != nil {
+			logger.Fatal("Failed to schedule stream", zap.Error(err))
+		}
+		if sched.Owns(streamCfg.Name) {
+			owned++
+		}
+	}
+	if shard != nil {
+		logger.Info("Cluster mode enabled",
+			zap.Int("streams", len(cfg.Streams)),
+			zap.Int("owned", owned))
+	}
+	if historyStore != nil {
+		restoreLastSuccess(context.Background(), historyStore, sched, metricsCollector, logger)
+	}
+
 	// HTTP сервер для метрик
 	mux := http.NewServeMux()
-	mux.Handle(cfg.Server.MetricsPath, promhttp.Handler())
+	metricsEndpoint := metricsHandler(cfg.Server, cfg.Tracing.Enabled, metricsCollector)
+	if cfg.Checks.CollectOnScrape {
+		metricsEndpoint = collectOnScrapeHandler(sched, metricsEndpoint)
+	}
+	mux.Handle(cfg.Server.MetricsPath, metricsEndpoint)
 	mux.HandleFunc(cfg.Server.HealthPath, healthCheckHandler)
+	mux.HandleFunc(healthyPath, healthCheckHandler)
+	mux.Handle(readyPath, readinessHandler(sched, results, cfg.Server.ReadinessMode))
+	api.NewServer(api.Dependencies{
+		Manager:      sched,
+		Profiles:     cfg.Profiles,
+		Devices:      cfg.Devices,
+		Results:      results,
+		Incidents:    incidents,
+		History:      checkHistory,
+		Tags:         streamChecker,
+		Overrides:    overrides,
+		Journal:      store.NewJournal(),
+		Transactions: transactions,
+		Tokens:       cfg.Server.APITokens,
+		Logger:       logger,
+		Admin:        cfg.Server.AdminAPI,
+		MetricsPath:  cfg.Server.MetricsPath,
+	}).Register(mux)
+
+	var (
+		handler   http.Handler = mux
+		basicAuth *webauth.BasicAuth
+	)
+	if len(cfg.Server.BasicAuthUsers) > 0 {
+		// Запросы к API с токеном Bearer авторизует сам API, пробы открыты
+		bearerPrefix := ""
+		if len(cfg.Server.APITokens) > 0 {
+			bearerPrefix = api.Prefix + "/"
+		}
+		basicAuth = webauth.New(cfg.Server.BasicAuthUsers, bearerPrefix,
+			cfg.Server.HealthPath, healthyPath, readyPath)
+		handler = basicAuth.Wrap(mux)
+	}
+
+	// gRPC-сервер StatusService с теми же TLS и авторизацией, что у HTTP API
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCAddress != "" {
+		grpcServer, err = newGRPCServer(cfg.Server, grpcapi.Dependencies{
+			Manager:   sched,
+			Results:   results,
+			Feed:      results,
+			History:   checkHistory,
+			Tokens:    cfg.Server.APITokens,
+			BasicAuth: basicAuth,
+			Logger:    logger,
+			Admin:     cfg.Server.AdminAPI,
+		})
+		if err != nil {
+			return err
+		}
+		listener, err := net.Listen("tcp", cfg.Server.GRPCAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on grpc_address: %w", err)
+		}
+		go func() {
+			logger.Info("Starting gRPC server",
+				zap.String("address", listener.Addr().String()),
+				zap.Bool("tls", cfg.Server.TLSCertFile != ""))
+			if err := grpcServer.Serve(listener); err != nil {
+				logger.Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+	}
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:           mux,
+		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second, // Защита от Slowloris атак
 	}
 
@@ -84,19 +323,26 @@ func main() {
 
 	// Запуск HTTP сервера
 	go func() {
+		tls := cfg.Server.TLSCertFile != ""
 		logger.Info("Starting HTTP server",
 			zap.String("address", server.Addr),
-			zap.String("metrics_path", cfg.Server.MetricsPath))
+			zap.String("metrics_path", cfg.Server.MetricsPath),
+			zap.Bool("tls", tls),
+			zap.Bool("basic_auth", len(cfg.Server.BasicAuthUsers) > 0))
 
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tls {
+			err = server.ListenAndServeTLS(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			logger.Fatal("Failed to start HTTP server", zap.Error(err))
 		}
 	}()
 
 	// Запуск проверок стримов
-	for _, streamCfg := range cfg.Streams {
-		go runStreamChecks(context.Background(), streamChecker, streamCfg, logger)
-	}
+	sched.Start(context.Background())
 
 	// Ожидание сигнала завершения
 	<-stop
@@ -107,6 +353,7 @@ func main() {
 	defer cancel()
 
 	// Остановка компонентов
+	sched.Stop()
 	if err := streamChecker.Stop(); err != nil {
 		logger.Error("Error stopping stream checker", zap.Error(err))
 	}
@@ -114,48 +361,163 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Error shutting down HTTP server", zap.Error(err))
 	}
+	if grpcServer != nil {
+		stopGRPCServer(ctx, grpcServer)
+	}
 
 	logger.Info("Shutdown complete")
+	return nil
 }
 
-// runStreamChecks запускает периодические проверки для стрима
-func runStreamChecks(ctx context.Context, checker *checker.StreamChecker, cfg models.StreamConfig, logger *zap.Logger) {
-	ticker := time.NewTicker(cfg.Interval)
-	defer ticker.Stop()
-
-	for {
-		checkCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
-		result, err := checker.Check(checkCtx, cfg)
-		cancel()
-
+// newGRPCServer создает gRPC-сервер StatusService, с TLS при заданном сертификате
+func newGRPCServer(cfg models.ServerConfig, deps grpcapi.Dependencies) (*grpc.Server, error) {
+	var opts []grpc.ServerOption
+	if cfg.TLSCertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.TLSCertFile, cfg.TLSKeyFile)
 		if err != nil {
-			logger.Error("Stream check failed",
-				zap.String("stream", cfg.Name),
-				zap.Error(err))
-		} else {
-			logger.Debug("Stream check completed",
-				zap.String("stream", cfg.Name),
-				zap.Bool("success", result.Success))
+			return nil, fmt.Errorf("failed to load gRPC TLS certificate: %w", err)
 		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	return grpcapi.NewServer(deps).GRPCServer(opts...), nil
+}
+
+// stopGRPCServer дожидается завершения вызовов до отмены ctx. Потоки WatchResults
+// сами не завершаются, поэтому по истечении ctx оставшиеся вызовы прерываются.
+func stopGRPCServer(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
 
-		select {
-		case <-ticker.C:
-			continue
-		case <-checker.StopCh():
-			return
-		case <-ctx.Done():
-			return
+// withFaultInjection оборачивает клиент внедрением сбоев. Правила
+// учитываются только в debug-сборке, в обычной сборке игнорируются.
+func withFaultInjection(httpClient models.HTTPClient, rules []models.FaultRule, logger *zap.Logger) models.HTTPClient {
+	if len(rules) == 0 {
+		return httpClient
+	}
+	if !faults.Enabled {
+		logger.Warn("Fault injection rules ignored: build with -tags debug to enable",
+			zap.Int("rules", len(rules)))
+		return httpClient
+	}
+
+	logger.Warn("Fault injection enabled", zap.Int("rules", len(rules)))
+	return faults.Wrap(httpClient, faults.NewInjector(rules...))
+}
+
+// restoreLastSuccess восстанавливает из истории время последней успешной проверки
+// своих стримов, чтобы оповещения по hls_last_successful_check_timestamp
+// не сбрасывались перезапуском экспортера
+func restoreLastSuccess(
+	ctx context.Context,
+	store *history.Store,
+	sched *scheduler.Scheduler,
+	collector models.MetricsCollector,
+	logger *zap.Logger,
+) {
+	last, err := store.LastSuccess(ctx)
+	if err != nil {
+		logger.Warn("Failed to restore last successful checks from history", zap.Error(err))
+		return
+	}
+	for stream, timestamp := range last {
+		if sched.Owns(stream) {
+			collector.SetLastSuccessTime(stream, timestamp)
 		}
 	}
 }
 
-// healthCheckHandler для endpoint /health
+// metricsHandler отдает метрики с опциональным сжатием и кэшированием сбора.
+// Exemplars передаются только в формате OpenMetrics, он включается вместе с трассировкой.
+// Серии стримов дополняются их пользовательскими метками.
+func metricsHandler(cfg models.ServerConfig, openMetrics bool, collector *metrics.Collector) http.Handler {
+	gatherer := metrics.NewCachingGatherer(collector.WithStreamLabels(prometheus.DefaultGatherer), cfg.MetricsCacheTTL)
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+			DisableCompression: !cfg.MetricsCompression,
+			EnableOpenMetrics:  openMetrics,
+		}),
+	)
+}
+
+// scrapeTimeoutShare доля таймаута скрейпа Prometheus, отводимая на проверки
+const scrapeTimeoutShare = 0.9
+
+// collectOnScrapeHandler перед отдачей метрик запускает проверки стримов с устаревшими
+// результатами. Ожидание ограничено таймаутом скрейпа из заголовка Prometheus:
+// лучше отдать часть прежних результатов, чем не ответить вовсе.
+func collectOnScrapeHandler(sched *scheduler.Scheduler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); v != "" {
+			if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds*scrapeTimeoutShare*float64(time.Second)))
+				defer cancel()
+			}
+		}
+
+		sched.Collect(ctx)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Пробы liveness и readiness в стиле Prometheus
+const (
+	healthyPath = "/-/healthy"
+	readyPath   = "/-/ready"
+)
+
+// healthCheckHandler для endpoint /health и /-/healthy: процесс жив
 func healthCheckHandler(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	if _, err := w.Write([]byte("OK")); err != nil {
 		log.Printf("Error writing response: %v", err)
 	}
 }
+
+// readinessHandler для endpoint /-/ready: каждый проверяемый этим экземпляром стрим
+// прошел хотя бы одну проверку, а в режиме any_up хотя бы один из них доступен
+func readinessHandler(streams models.StreamManager, results models.ResultStore, mode string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var active, checked, up int
+		for _, stream := range streams.Streams() {
+			if !streams.Owns(stream.Name) || streams.Paused(stream.Name) {
+				continue
+			}
+			active++
+			if result, ok := results.Get(stream.Name); ok {
+				checked++
+				if result.Success {
+					up++
+				}
+			}
+		}
+
+		status, body := http.StatusOK, "OK"
+		switch {
+		case checked < active:
+			status = http.StatusServiceUnavailable
+			body = fmt.Sprintf("waiting for first check: %d of %d streams checked", checked, active)
+		case mode == models.ReadinessAnyUp && active > 0 && up == 0:
+			status = http.StatusServiceUnavailable
+			body = "all streams are down"
+		}
+		w.WriteHeader(status)
+		if _, err := w.Write([]byte(body)); err != nil {
+			log.Printf("Error writing response: %v", err)
+		}
+	})
+}
 func initLogger(cfg models.LoggingConfig) (*zap.Logger, error) {
 	var level zapcore.Level
 	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
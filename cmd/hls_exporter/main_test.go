@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -12,12 +13,20 @@ import (
 
 	"github.com/iudanet/hls_exporter/internal/checker"
 	"github.com/iudanet/hls_exporter/internal/config"
+	"github.com/iudanet/hls_exporter/internal/faults"
+	"github.com/iudanet/hls_exporter/internal/grpcapi"
 	client "github.com/iudanet/hls_exporter/internal/http"
 	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/scheduler"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/api/hlsexporterv1"
 	"github.com/iudanet/hls_exporter/pkg/models"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 const (
@@ -279,3 +288,182 @@ func setupTest(t *testing.T) (*prometheus.Registry, string, func()) {
 
 	return reg, testServerURL, cleanup
 }
+
+func TestMetricsHandler_Compression(t *testing.T) {
+	originalReg := prometheus.DefaultRegisterer
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	defer func() {
+		prometheus.DefaultRegisterer = originalReg
+	}()
+
+	tests := []struct {
+		name         string
+		compression  bool
+		wantEncoding string
+	}{
+		{name: "compression enabled", compression: true, wantEncoding: "gzip"},
+		{name: "compression disabled", compression: false, wantEncoding: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := metricsHandler(models.ServerConfig{
+				MetricsCompression: tt.compression,
+				MetricsCacheTTL:    time.Second,
+			}, false, metrics.NewCollectorWithOptions(prometheus.NewRegistry(), metrics.Options{}))
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantEncoding, rec.Header().Get("Content-Encoding"))
+		})
+	}
+}
+
+// delayedChecker выполняет проверку с задержкой
+type delayedChecker struct {
+	delay   time.Duration
+	checked chan string
+}
+
+func (c *delayedChecker) Check(ctx context.Context, stream models.StreamConfig) (*models.CheckResult, error) {
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+	}
+	c.checked <- stream.Name
+	return &models.CheckResult{StreamName: stream.Name, Success: true}, nil
+}
+
+func (c *delayedChecker) Start() error  { return nil }
+func (c *delayedChecker) Stop() error   { return nil }
+func (c *delayedChecker) Forget(string) {}
+
+func TestCollectOnScrapeHandler(t *testing.T) {
+	checker := &delayedChecker{delay: 300 * time.Millisecond, checked: make(chan string, 10)}
+	sched := scheduler.New(scheduler.Dependencies{
+		Checker:           checker,
+		Metrics:           metrics.NewCollector(prometheus.NewRegistry()),
+		CollectOnScrape:   true,
+		ScrapeConcurrency: 1,
+	})
+	require.NoError(t, sched.Add(models.StreamConfig{
+		Name:     "lazy",
+		URL:      "http://example.com/lazy.m3u8",
+		Interval: time.Hour,
+		Timeout:  time.Second,
+	}))
+	sched.Start(context.Background())
+	defer sched.Stop()
+
+	served := 0
+	handler := collectOnScrapeHandler(sched, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Ожидание проверок ограничено таймаутом скрейпа
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", "0.05")
+	started := time.Now()
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Less(t, time.Since(started), 250*time.Millisecond)
+	assert.Equal(t, 1, served)
+	assert.Empty(t, checker.checked)
+
+	// Без заголовка ответ дожидается начатой проверки
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, 2, served)
+	assert.Equal(t, "lazy", <-checker.checked)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	sched := scheduler.New(scheduler.Dependencies{
+		Metrics: metrics.NewCollector(prometheus.NewRegistry()),
+	})
+	for _, name := range []string{"news", "sport", "paused"} {
+		require.NoError(t, sched.Add(models.StreamConfig{
+			Name:     name,
+			URL:      "http://example.com/" + name + ".m3u8",
+			Interval: time.Hour,
+			Timeout:  time.Second,
+		}))
+	}
+	require.NoError(t, sched.SetPaused("paused", true))
+	results := store.NewResultStore()
+
+	probe := func(mode string) (int, string) {
+		rec := httptest.NewRecorder()
+		readinessHandler(sched, results, mode).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, readyPath, nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	// Не готов, пока не проверены все активные стримы; приостановленные не учитываются
+	results.Save(&models.CheckResult{StreamName: "news"})
+	code, body := probe(models.ReadinessChecked)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "waiting for first check: 1 of 2 streams checked", body)
+
+	results.Save(&models.CheckResult{StreamName: "sport"})
+	code, _ = probe(models.ReadinessChecked)
+	assert.Equal(t, http.StatusOK, code)
+
+	// В режиме any_up недоступность всех стримов снимает готовность
+	code, body = probe(models.ReadinessAnyUp)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "all streams are down", body)
+
+	results.Save(&models.CheckResult{StreamName: "sport", Success: true})
+	code, _ = probe(models.ReadinessAnyUp)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestWithFaultInjection(t *testing.T) {
+	httpClient := client.NewClient(models.HTTPConfig{})
+	defer httpClient.Close()
+
+	assert.Same(t, httpClient, withFaultInjection(httpClient, nil, zap.NewNop()))
+
+	rules := []models.FaultRule{{Stage: models.FaultStageSegment, StatusCode: http.StatusNotFound}}
+	wrapped := withFaultInjection(httpClient, rules, zap.NewNop())
+	if faults.Enabled {
+		assert.IsType(t, &faults.Client{}, wrapped)
+	} else {
+		assert.Same(t, httpClient, wrapped)
+	}
+}
+
+func TestNewGRPCServer(t *testing.T) {
+	_, err := newGRPCServer(models.ServerConfig{
+		TLSCertFile: filepath.Join(t.TempDir(), "missing.crt"),
+		TLSKeyFile:  filepath.Join(t.TempDir(), "missing.key"),
+	}, grpcapi.Dependencies{})
+	assert.ErrorContains(t, err, "failed to load gRPC TLS certificate")
+
+	results := store.NewResultStore()
+	server, err := newGRPCServer(models.ServerConfig{}, grpcapi.Dependencies{Results: results, Feed: results})
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	watch, err := hlsexporterv1.NewStatusServiceClient(conn).WatchResults(context.Background(), &hlsexporterv1.WatchResultsRequest{})
+	require.NoError(t, err)
+	_, err = watch.Header()
+	require.NoError(t, err)
+
+	// Открытый поток WatchResults не задерживает остановку дольше ctx
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	stopGRPCServer(ctx, server)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	_, err = watch.Recv()
+	assert.Error(t, err, "stream is closed on shutdown")
+}
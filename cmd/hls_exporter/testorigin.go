@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/testorigin"
+)
+
+// runTestOrigin запускает синтетический HLS-источник для ручной проверки экспортера
+func runTestOrigin(args []string) error {
+	fs := flag.NewFlagSet("testorigin", flag.ContinueOnError)
+	listen := fs.String("listen", ":8081", "Address to listen on")
+	variants := fs.String("variants", "720p:2000000:1280x720,360p:800000:640x360",
+		"Comma-separated variants in name:bandwidth[:resolution] form")
+	encrypted := fs.String("encrypted", "", "Comma-separated names of AES-128 encrypted variants")
+	segmentDuration := fs.Duration("segment-duration", 2*time.Second, "Segment duration")
+	window := fs.Int("window", 6, "Number of segments in the live window")
+	discontinuity := fs.Int("discontinuity-every", 0, "Insert a discontinuity every N segments (0 disables)")
+	lowLatency := fs.Bool("ll-hls", false, "Advertise LL-HLS partial segments")
+	partDuration := fs.Duration("part-duration", 500*time.Millisecond, "LL-HLS part duration")
+	stale := fs.Bool("stale-playlist", false, "Freeze media playlists at startup")
+	truncate := fs.Bool("truncate-segments", false, "Cut segment transfers in half")
+	playlistStatus := fs.Int("playlist-status", 0, "Force HTTP status for playlists")
+	segmentStatus := fs.Int("segment-status", 0, "Force HTTP status for segments")
+	latency := fs.Duration("latency", 0, "Delay before every response")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	parsed, err := parseVariants(*variants, *encrypted)
+	if err != nil {
+		return err
+	}
+
+	origin := testorigin.New(testorigin.Config{
+		Variants:           parsed,
+		SegmentDuration:    *segmentDuration,
+		WindowSize:         *window,
+		DiscontinuityEvery: *discontinuity,
+		LowLatency:         *lowLatency,
+		PartDuration:       *partDuration,
+	})
+	origin.SetFaults(testorigin.Faults{
+		StalePlaylist:    *stale,
+		TruncateSegments: *truncate,
+		PlaylistStatus:   *playlistStatus,
+		SegmentStatus:    *segmentStatus,
+		Latency:          *latency,
+	})
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           origin,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("Test origin listening on %s, master playlist at %s", *listen, origin.MasterURL())
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("test origin server: %w", err)
+	case <-stop:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// parseVariants разбирает описание вариантов вида name:bandwidth[:resolution]
+func parseVariants(spec, encrypted string) ([]testorigin.Variant, error) {
+	enc := make(map[string]bool)
+	for _, name := range strings.Split(encrypted, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			enc[name] = true
+		}
+	}
+
+	var variants []testorigin.Variant
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid variant %q: expected name:bandwidth[:resolution]", item)
+		}
+
+		v := testorigin.Variant{Name: parts[0], Encrypted: enc[parts[0]]}
+		if _, err := fmt.Sscanf(parts[1], "%d", &v.Bandwidth); err != nil || v.Bandwidth <= 0 {
+			return nil, fmt.Errorf("invalid variant %q: bad bandwidth", item)
+		}
+		if len(parts) == 3 {
+			v.Resolution = parts[2]
+		}
+		delete(enc, v.Name)
+		variants = append(variants, v)
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("no variants configured")
+	}
+	for name := range enc {
+		return nil, fmt.Errorf("encrypted variant %q is not defined", name)
+	}
+	return variants, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/internal/testorigin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariants(t *testing.T) {
+	variants, err := parseVariants("720p:2000000:1280x720, audio:128000", "audio")
+	require.NoError(t, err)
+	assert.Equal(t, []testorigin.Variant{
+		{Name: "720p", Bandwidth: 2000000, Resolution: "1280x720"},
+		{Name: "audio", Bandwidth: 128000, Encrypted: true},
+	}, variants)
+
+	tests := []struct {
+		name      string
+		spec      string
+		encrypted string
+	}{
+		{name: "empty", spec: ""},
+		{name: "missing bandwidth", spec: "720p"},
+		{name: "bad bandwidth", spec: "720p:fast"},
+		{name: "unknown encrypted", spec: "720p:1000", encrypted: "1080p"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseVariants(tt.spec, tt.encrypted)
+			assert.Error(t, err)
+		})
+	}
+}
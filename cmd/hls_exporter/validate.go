@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/iudanet/hls_exporter/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newValidateConfigCmd(configPath *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate-config",
+		Short: "Validate configuration file and print all problems",
+		Long: `Load the configuration file and run the full validation.
+Prints every problem found and exits with status 1 if there are any.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			out := cmd.OutOrStdout()
+
+			_, err := config.NewConfigManager().LoadConfig(*configPath)
+			if err == nil {
+				fmt.Fprintf(out, "%s: OK\n", *configPath)
+				return nil
+			}
+
+			problems := flattenErrors(err)
+			fmt.Fprintf(out, "%s: %d problem(s)\n", *configPath, len(problems))
+			for _, problem := range problems {
+				fmt.Fprintf(out, "  - %s\n", problem)
+			}
+			return &exitError{code: 1}
+		},
+	}
+}
+
+// flattenErrors раскрывает ошибки, объединенные errors.Join, в плоский список.
+// Префикс обертки (например, "config validation error") при этом отбрасывается.
+func flattenErrors(err error) []error {
+	for e := err; e != nil; {
+		if joined, ok := e.(interface{ Unwrap() []error }); ok {
+			var flat []error
+			for _, inner := range joined.Unwrap() {
+				flat = append(flat, flattenErrors(inner)...)
+			}
+			return flat
+		}
+		wrapped, ok := e.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+		e = wrapped.Unwrap()
+	}
+	return []error{err}
+}
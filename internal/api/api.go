@@ -0,0 +1,186 @@
+package api
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+
+	"github.com/iudanet/hls_exporter/internal/config"
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Prefix общий префикс путей API
+const Prefix = "/api/v1"
+
+// StreamRegistry источник актуального списка стримов
+type StreamRegistry interface {
+	Streams() []models.StreamConfig
+	Stream(name string) (models.StreamConfig, bool)
+}
+
+// StaticStreams реестр стримов из конфигурационного файла
+type StaticStreams []models.StreamConfig
+
+func (s StaticStreams) Streams() []models.StreamConfig {
+	return s
+}
+
+func (s StaticStreams) Stream(name string) (models.StreamConfig, bool) {
+	for _, stream := range s {
+		if stream.Name == name {
+			return stream, true
+		}
+	}
+	return models.StreamConfig{}, false
+}
+
+// Dependencies зависимости HTTP API
+type Dependencies struct {
+	Streams StreamRegistry
+	// Manager позволяет менять набор стримов во время работы.
+	// Если Streams не задан, используется как реестр стримов.
+	Manager   models.StreamManager
+	Validator models.ConfigValidator
+	// Profiles именованные профили для добавляемых стримов
+	Profiles map[string]models.ProfileConfig
+	// Devices именованные устройства для добавляемых стримов
+	Devices map[string]models.DeviceConfig
+	Results models.ResultStore
+	// Incidents текущие инциденты стримов (опционально)
+	Incidents models.IncidentStore
+	// History история проверок (опционально)
+	History models.HistoryStore
+	// Tags источник нестандартных тегов стримов (опционально)
+	Tags      models.TagInventory
+	Overrides *override.Store
+	// Journal журнал действий admin API (опционально)
+	Journal models.EventJournal
+	// Transactions журналы HTTP-транзакций неуспешных проверок (опционально)
+	Transactions models.TransactionStore
+	// Tokens токены доступа к API с областью видимости стримов (пусто - без авторизации)
+	Tokens []models.APIToken
+	Logger *zap.Logger
+	// Admin включает изменяющие состояние обработчики
+	Admin bool
+	// MetricsPath путь метрик для ссылки со страницы обзора
+	MetricsPath string
+}
+
+// Server HTTP API экспортера
+type Server struct {
+	streams      StreamRegistry
+	manager      models.StreamManager
+	validator    models.ConfigValidator
+	profiles     map[string]models.ProfileConfig
+	devices      map[string]models.DeviceConfig
+	results      models.ResultStore
+	incidents    models.IncidentStore
+	history      models.HistoryStore
+	tags         models.TagInventory
+	overrides    *override.Store
+	journal      models.EventJournal
+	transactions models.TransactionStore
+	logger       *zap.Logger
+	admin        bool
+	metricsPath  string
+	// auth требует токен для всех запросов API
+	auth   bool
+	tokens []apiToken
+}
+
+func NewServer(deps Dependencies) *Server {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	streams := deps.Streams
+	if streams == nil && deps.Manager != nil {
+		streams = deps.Manager
+	}
+	validator := deps.Validator
+	if validator == nil {
+		validator = config.NewValidator()
+	}
+
+	return &Server{
+		streams:      streams,
+		manager:      deps.Manager,
+		validator:    validator,
+		profiles:     deps.Profiles,
+		devices:      deps.Devices,
+		results:      deps.Results,
+		incidents:    deps.Incidents,
+		history:      deps.History,
+		tags:         deps.Tags,
+		overrides:    deps.Overrides,
+		journal:      deps.Journal,
+		transactions: deps.Transactions,
+		logger:       logger,
+		admin:        deps.Admin,
+		metricsPath:  cmp.Or(deps.MetricsPath, "/metrics"),
+		auth:         len(deps.Tokens) > 0,
+		tokens:       newTokens(deps.Tokens, logger),
+	}
+}
+
+// Register регистрирует обработчики API. Изменяющие состояние
+// обработчики доступны только при включенном admin API и, при настроенных
+// токенах, только токенам с admin: true.
+func (s *Server) Register(mux *http.ServeMux) {
+	// Страница обзора не учитывает области видимости токенов и при токенах не публикуется
+	if !s.auth && s.results != nil {
+		mux.HandleFunc("GET /{$}", s.landingPage)
+		mux.HandleFunc("GET /dashboard", s.dashboardPage)
+	}
+	s.handle(mux, "GET "+Prefix+"/results", s.listResults, false)
+	s.handle(mux, "GET "+Prefix+"/results/{stream}", s.getResult, false)
+	s.handle(mux, "GET "+Prefix+"/streams", s.listStreams, false)
+	s.handle(mux, "GET "+Prefix+"/streams/{name}", s.getStream, false)
+	if s.tags != nil {
+		s.handle(mux, "GET "+Prefix+"/streams/{name}/tags", s.getUnknownTags, false)
+	}
+	if s.incidents != nil {
+		s.handle(mux, "GET "+Prefix+"/incidents", s.listIncidents, false)
+		s.handle(mux, "GET "+Prefix+"/streams/{name}/incident", s.getIncident, false)
+	}
+	if s.history != nil {
+		s.handle(mux, "GET "+Prefix+"/streams/{name}/history", s.getHistory, false)
+	}
+	// Журнал содержит заголовки запросов стрима, поэтому при настроенных токенах доступен
+	// только токенам с admin: true
+	if s.transactions != nil {
+		s.handle(mux, "GET "+Prefix+"/debug/streams/{name}/transactions", s.getTransactions, true)
+	}
+
+	if s.admin {
+		s.handle(mux, "GET "+Prefix+"/streams/{name}/override", s.getOverride, true)
+		s.handle(mux, "PUT "+Prefix+"/streams/{name}/override", s.putOverride, true)
+		s.handle(mux, "DELETE "+Prefix+"/streams/{name}/override", s.deleteOverride, true)
+		if s.journal != nil {
+			s.handle(mux, "GET "+Prefix+"/events", s.listEvents, true)
+		}
+
+		// Управление набором стримов доступно только с планировщиком
+		if s.manager != nil {
+			s.handle(mux, "POST "+Prefix+"/streams", s.createStream, true)
+			s.handle(mux, "PATCH "+Prefix+"/streams/{name}", s.patchStream, true)
+			s.handle(mux, "DELETE "+Prefix+"/streams/{name}", s.deleteStream, true)
+			s.handle(mux, "POST "+Prefix+"/streams/{name}/check", s.triggerCheck, true)
+		}
+	}
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error("Failed to write API response", zap.Error(err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, msg string) {
+	s.writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/internal/labels"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// apiToken токен доступа с разобранным селектором видимых стримов
+type apiToken struct {
+	name     string
+	token    []byte
+	selector labels.Selector
+	admin    bool
+}
+
+type tokenKey struct{}
+
+// newTokens разбирает токены из конфигурации. Токены с некорректным селектором
+// не принимаются: конфигурация проверяется валидатором заранее.
+func newTokens(cfgs []models.APIToken, logger *zap.Logger) []apiToken {
+	tokens := make([]apiToken, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		selector, err := labels.ParseSelector(cfg.Selector, nil)
+		if err != nil {
+			logger.Error("Invalid API token selector", zap.String("token", cfg.Name), zap.Error(err))
+			continue
+		}
+		tokens = append(tokens, apiToken{
+			name:     cfg.Name,
+			token:    []byte(cfg.Token),
+			selector: selector,
+			admin:    cfg.Admin,
+		})
+	}
+	return tokens
+}
+
+// handle регистрирует обработчик API. При настроенных токенах запрос должен содержать
+// токен (Authorization: Bearer), а стрим из пути - входить в область видимости токена.
+// Обработчики admin доступны только токенам с admin: true.
+func (s *Server) handle(mux *http.ServeMux, pattern string, h http.HandlerFunc, admin bool) {
+	if !s.auth {
+		mux.HandleFunc(pattern, h)
+		return
+	}
+
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		tok, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="hls_exporter"`)
+			s.writeError(w, http.StatusUnauthorized, "invalid or missing API token")
+			return
+		}
+		if admin && !tok.admin {
+			s.writeError(w, http.StatusForbidden, "API token is not allowed to change state")
+			return
+		}
+
+		// Стримы вне области видимости неотличимы от несуществующих
+		if name := cmp.Or(r.PathValue("name"), r.PathValue("stream")); name != "" {
+			if stream, ok := s.streams.Stream(name); ok && !tok.selector.Matches(labels.StreamLabels(stream)) {
+				s.writeError(w, http.StatusNotFound, "stream not found")
+				return
+			}
+		}
+
+		h(w, r.WithContext(context.WithValue(r.Context(), tokenKey{}, tok)))
+	})
+}
+
+// authenticate ищет токен запроса среди настроенных
+func (s *Server) authenticate(r *http.Request) (*apiToken, bool) {
+	value, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || value == "" {
+		return nil, false
+	}
+	for i := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(value), s.tokens[i].token) == 1 {
+			return &s.tokens[i], true
+		}
+	}
+	return nil, false
+}
+
+// visible сообщает, что стрим входит в область видимости токена запроса
+func (s *Server) visible(r *http.Request, stream models.StreamConfig) bool {
+	tok, ok := r.Context().Value(tokenKey{}).(*apiToken)
+	return !ok || tok.selector.Matches(labels.StreamLabels(stream))
+}
+
+// visibleName сообщает, что стрим с именем name входит в область видимости токена запроса.
+// Для токена с областью видимости удаленные стримы не видны.
+func (s *Server) visibleName(r *http.Request, name string) bool {
+	if _, ok := r.Context().Value(tokenKey{}).(*apiToken); !ok {
+		return true
+	}
+	stream, ok := s.streams.Stream(name)
+	return ok && s.visible(r, stream)
+}
+
+// principal возвращает инициатора запроса: имя токена или пользователя Basic-авторизации
+func principal(r *http.Request) string {
+	if tok, ok := r.Context().Value(tokenKey{}).(*apiToken); ok {
+		return tok.name
+	}
+	user, _, _ := r.BasicAuth()
+	return user
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newAuthTestServer() (*http.ServeMux, *store.ResultStore) {
+	var streams StaticStreams
+	for _, name := range []string{"news_1", "sports_1"} {
+		streams = append(streams, models.StreamConfig{
+			Name:      name,
+			URL:       "http://example.com/" + name + ".m3u8",
+			CheckMode: models.CheckModeFirstLast,
+			Interval:  time.Minute,
+			Timeout:   10 * time.Second,
+		})
+	}
+	results := store.NewResultStore()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams:   streams,
+		Results:   results,
+		Overrides: override.NewStore(),
+		Journal:   store.NewJournal(),
+		Logger:    zap.NewNop(),
+		Admin:     true,
+		Tokens: []models.APIToken{
+			{Name: "news-team", Token: "news-secret", Selector: []string{`stream=~"news_.*"`}, Admin: true},
+			{Name: "viewer", Token: "viewer-secret"},
+			{Name: "broken", Token: "broken-secret", Selector: []string{`stream=~"("`}},
+		},
+	}).Register(mux)
+	return mux, results
+}
+
+func doTokenRequest(mux *http.ServeMux, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuth_Unauthorized(t *testing.T) {
+	mux, _ := newAuthTestServer()
+
+	rec := doTokenRequest(mux, "", http.MethodGet, "/api/v1/results", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+
+	rec = doTokenRequest(mux, "wrong", http.MethodGet, "/api/v1/results", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Токен с некорректным селектором не принимается
+	rec = doTokenRequest(mux, "broken-secret", http.MethodGet, "/api/v1/results", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAuth_Scope(t *testing.T) {
+	mux, results := newAuthTestServer()
+	results.Save(&models.CheckResult{StreamName: "news_1", Success: true})
+	results.Save(&models.CheckResult{StreamName: "sports_1", Success: true})
+
+	rec := doTokenRequest(mux, "news-secret", http.MethodGet, "/api/v1/results", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []models.CheckResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "news_1", list[0].StreamName)
+
+	// Стрим вне области видимости неотличим от несуществующего
+	rec = doTokenRequest(mux, "news-secret", http.MethodGet, "/api/v1/results/sports_1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doTokenRequest(mux, "news-secret", http.MethodGet, "/api/v1/results/news_1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Токен без селектора видит все стримы
+	rec = doTokenRequest(mux, "viewer-secret", http.MethodGet, "/api/v1/results/sports_1", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAuth_Admin(t *testing.T) {
+	mux, _ := newAuthTestServer()
+	body := `{"interval":"10s","ttl":"1h"}`
+
+	rec := doTokenRequest(mux, "viewer-secret", http.MethodPut, "/api/v1/streams/news_1/override", body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = doTokenRequest(mux, "news-secret", http.MethodPut, "/api/v1/streams/sports_1/override", body)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doTokenRequest(mux, "news-secret", http.MethodPut, "/api/v1/streams/news_1/override", body)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Инициатором в журнале записывается имя токена
+	rec = doTokenRequest(mux, "news-secret", http.MethodGet, "/api/v1/events", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []models.AuditEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "news-team", events[0].Principal)
+	assert.Equal(t, "news_1", events[0].Stream)
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Размеры графика последних проверок, пиксели
+const (
+	sparkBarWidth = 4
+	sparkBarGap   = 1
+	sparkHeight   = 24
+	// sparkMinBar высота столбца проверки, завершившейся мгновенно
+	sparkMinBar = 2
+)
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(pageFuncs).Funcs(template.FuncMap{
+	"bps":   formatBitrate,
+	"float": func(v uint32) float64 { return float64(v) },
+	"pct":   func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+}).Parse(dashboardHTML))
+
+// dashboardStream карточка стрима на панели мониторинга
+type dashboardStream struct {
+	Name      string
+	State     string
+	LastCheck time.Time
+	Duration  time.Duration
+	NextCheck time.Time
+	Segments  models.SegmentResults
+	Bitrate   float64
+	// Uptime доля успешных проверок среди последних Samples
+	Uptime  float64
+	Samples int
+	Spark   []sparkBar
+	Width   int
+	// Variants заявленный и измеренный битрейт вариантов последней проверки
+	Variants []models.VariantBitrate
+	// LastError ошибка последней неуспешной проверки среди последних
+	LastError   *models.CheckError
+	LastErrorAt time.Time
+}
+
+// sparkBar столбец графика: высота пропорциональна длительности проверки
+type sparkBar struct {
+	X, Y, Width, Height int
+	Failed              bool
+	Title               string
+}
+
+// dashboardPage отдает панель мониторинга: графики последних проверок, варианты
+// и последняя ошибка каждого стрима из хранилища результатов в памяти
+func (s *Server) dashboardPage(w http.ResponseWriter, _ *http.Request) {
+	streams := s.streams.Streams()
+	data := struct {
+		Streams     []dashboardStream
+		Up          int
+		MetricsPath string
+		APIPrefix   string
+		SparkHeight int
+	}{
+		Streams:     make([]dashboardStream, 0, len(streams)),
+		MetricsPath: s.metricsPath,
+		APIPrefix:   Prefix,
+		SparkHeight: sparkHeight,
+	}
+	for _, stream := range streams {
+		card := dashboardStream{Name: stream.Name}
+		result, ok := s.results.Get(stream.Name)
+		card.State, card.NextCheck = s.streamState(stream.Name, result, ok)
+		if ok {
+			card.LastCheck = result.Timestamp
+			card.Duration = result.Duration
+			card.Segments = result.Segments
+			card.Bitrate = result.Bitrate
+			card.Variants = result.Variants
+		}
+		samples := s.results.Recent(stream.Name)
+		card.Spark, card.Width = sparkline(samples)
+		card.Samples = len(samples)
+		up := 0
+		for _, sample := range samples {
+			if sample.Success {
+				up++
+			} else if sample.Error != nil {
+				card.LastError, card.LastErrorAt = sample.Error, sample.Timestamp
+			}
+		}
+		if len(samples) > 0 {
+			card.Uptime = float64(up) / float64(len(samples))
+		}
+		if card.State == landingUp {
+			data.Up++
+		}
+		data.Streams = append(data.Streams, card)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := dashboardTemplate.Execute(w, data); err != nil {
+		s.logger.Error("Failed to render dashboard", zap.Error(err))
+	}
+}
+
+// sparkline строит столбцы графика длительностей проверок и возвращает его ширину
+func sparkline(samples []models.ResultSample) ([]sparkBar, int) {
+	var longest time.Duration
+	for _, sample := range samples {
+		longest = max(longest, sample.Duration)
+	}
+	bars := make([]sparkBar, 0, len(samples))
+	for i, sample := range samples {
+		height := sparkHeight
+		if longest > 0 {
+			height = max(sparkMinBar, int(float64(sparkHeight)*float64(sample.Duration)/float64(longest)))
+		}
+		bars = append(bars, sparkBar{
+			X:      i * (sparkBarWidth + sparkBarGap),
+			Width:  sparkBarWidth,
+			Y:      sparkHeight - height,
+			Height: height,
+			Failed: !sample.Success,
+			Title:  sample.Timestamp.Format(time.RFC3339) + " " + sample.Duration.Truncate(time.Millisecond).String(),
+		})
+	}
+	return bars, len(samples) * (sparkBarWidth + sparkBarGap)
+}
+
+// formatBitrate форматирует битрейт в кбит/с или Мбит/с
+func formatBitrate(bps float64) string {
+	if bps >= 1e6 {
+		return fmt.Sprintf("%.2f Mbps", bps/1e6)
+	}
+	return fmt.Sprintf("%.0f kbps", bps/1e3)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDashboardPage(t *testing.T) {
+	results := store.NewResultStore()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams: StaticStreams{{Name: "news"}, {Name: "sport"}},
+		Results: results,
+		Logger:  zap.NewNop(),
+	}).Register(mux)
+
+	now := time.Now()
+	results.Save(&models.CheckResult{
+		StreamName: "news",
+		Timestamp:  now.Add(-time.Minute),
+		Duration:   400 * time.Millisecond,
+		Error:      &models.CheckError{Type: models.ErrSegmentDownload, Message: "unexpected status code: <404>", StatusCode: 404},
+	})
+	results.Save(&models.CheckResult{
+		StreamName: "news",
+		Success:    true,
+		Timestamp:  now,
+		Duration:   200 * time.Millisecond,
+		Bitrate:    2_500_000,
+		Segments:   models.SegmentResults{Checked: 3},
+		Variants: []models.VariantBitrate{{
+			URL:             "http://cdn.example.com/720p.m3u8",
+			Bandwidth:       3_000_000,
+			Resolution:      "1280x720",
+			MeasuredBitrate: 2_500_000,
+			DeviationRatio:  0.83,
+			SizeAnomaly:     true,
+		}},
+	})
+
+	rec := doRequest(mux, http.MethodGet, "/dashboard", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+
+	assert.Contains(t, body, "1 of 2 streams up")
+	assert.Contains(t, body, `<a href="/api/v1/results/news">news</a> <span class="up">up</span>`)
+	assert.Contains(t, body, `<span class="pending">pending</span>`)
+	assert.Contains(t, body, "Success: 50.0% of last 2")
+	assert.Contains(t, body, "Bitrate: 2.50 Mbps")
+
+	// График: неуспешная проверка выше и отмечена, высота по самой долгой проверке
+	assert.Contains(t, body, `<rect x="0" y="0" width="4" height="24" class="failed">`)
+	assert.Contains(t, body, `<rect x="5" y="12" width="4" height="12">`)
+
+	assert.Contains(t, body, "<td>1280x720</td>")
+	assert.Contains(t, body, "<td>3.00 Mbps</td>")
+	assert.Contains(t, body, `class="anomaly"`)
+
+	// Последняя ошибка показывается и после восстановления, текст экранируется
+	assert.Contains(t, body, "segment_download HTTP 404: unexpected status code: &lt;404&gt;")
+}
+
+func TestSparkline(t *testing.T) {
+	bars, width := sparkline([]models.ResultSample{
+		{Duration: time.Second, Success: true},
+		{Duration: 0},
+	})
+	require.Len(t, bars, 2)
+	assert.Equal(t, 10, width)
+	assert.Equal(t, sparkHeight, bars[0].Height)
+	assert.Equal(t, sparkMinBar, bars[1].Height)
+	assert.True(t, bars[1].Failed)
+
+	bars, width = sparkline(nil)
+	assert.Empty(t, bars)
+	assert.Zero(t, width)
+}
+
+func TestFormatBitrate(t *testing.T) {
+	assert.Equal(t, "850 kbps", formatBitrate(850_000))
+	assert.Equal(t, "1.20 Mbps", formatBitrate(1_200_000))
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// getTransactions отдает журнал HTTP-транзакций последней неуспешной проверки стрима
+// файлом JSON для передачи поставщику CDN
+func (s *Server) getTransactions(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.streams.Stream(name); !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+
+	bundle, ok := s.transactions.Get(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "no failed check recorded")
+		return
+	}
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="%s-%s.json"`, name, bundle.CheckID))
+	s.writeJSON(w, http.StatusOK, bundle)
+}
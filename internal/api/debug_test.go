@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionsAPI(t *testing.T) {
+	transactions := store.NewTransactionStore()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams:      StaticStreams{{Name: "test_stream"}, {Name: "healthy_stream"}},
+		Transactions: transactions,
+	}).Register(mux)
+
+	transactions.Save(&models.TransactionBundle{
+		Stream:    "test_stream",
+		CheckID:   "0123456789abcdef",
+		Timestamp: time.Now(),
+		Error:     &models.CheckError{Type: models.ErrPlaylistDownload, Message: "unexpected status code: 503"},
+		Transactions: []models.HTTPTransaction{{
+			Method:     http.MethodGet,
+			URL:        "http://example.com/master.m3u8",
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       "upstream unavailable",
+			BodyBytes:  20,
+		}},
+	})
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/debug/streams/test_stream/transactions", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `attachment; filename="test_stream-0123456789abcdef.json"`, rec.Header().Get("Content-Disposition"))
+	var bundle models.TransactionBundle
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &bundle))
+	require.Len(t, bundle.Transactions, 1)
+	assert.Equal(t, http.StatusServiceUnavailable, bundle.Transactions[0].StatusCode)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/debug/streams/healthy_stream/transactions", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/debug/streams/missing/transactions", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestTransactionsAPI_Disabled(t *testing.T) {
+	mux := http.NewServeMux()
+	NewServer(Dependencies{Streams: StaticStreams{{Name: "test_stream"}}}).Register(mux)
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/debug/streams/test_stream/transactions", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
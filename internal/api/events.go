@@ -0,0 +1,71 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// audit записывает действие admin API в журнал вместе с инициатором запроса:
+// именем токена API или пользователем Basic-авторизации (например, от прокси перед API)
+func (s *Server) audit(r *http.Request, action, stream, detail string) {
+	if s.journal == nil {
+		return
+	}
+
+	s.journal.Record(models.AuditEvent{
+		Time:       time.Now(),
+		Action:     action,
+		Stream:     stream,
+		Principal:  principal(r),
+		RemoteAddr: r.RemoteAddr,
+		Detail:     detail,
+	})
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid limit: "+v)
+			return
+		}
+		limit = n
+	}
+
+	// Лимит применяется после фильтрации по области видимости токена
+	events := []models.AuditEvent{}
+	for _, event := range s.journal.Events(r.URL.Query().Get("stream"), 0) {
+		if limit > 0 && len(events) == limit {
+			break
+		}
+		if s.visibleName(r, event.Stream) {
+			events = append(events, event)
+		}
+	}
+	s.writeJSON(w, http.StatusOK, events)
+}
+
+// triggerCheck запускает внеочередную проверку стрима
+func (s *Server) triggerCheck(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := s.manager.TriggerCheck(name); err != nil {
+		if errors.Is(err, models.ErrStreamInactive) {
+			s.writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		s.writeManagerError(w, err)
+		return
+	}
+
+	s.audit(r, models.AuditCheckTriggered, name, "")
+	s.logger.Info("Stream check triggered",
+		zap.String("stream", name),
+		zap.String("remote_addr", r.RemoteAddr))
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// defaultHistoryLimit число записей истории в ответе без параметра limit
+const defaultHistoryLimit = 100
+
+// getHistory возвращает историю проверок стрима, новые записи первыми.
+// Параметры: since и until (RFC 3339), limit (0 - без ограничения), failed=true.
+func (s *Server) getHistory(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.streams.Stream(name); !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+
+	params := r.URL.Query()
+	q := models.HistoryQuery{Stream: name, Limit: defaultHistoryLimit}
+	for _, bound := range []struct {
+		param string
+		dst   *time.Time
+	}{{"since", &q.Since}, {"until", &q.Until}} {
+		v := params.Get(bound.param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid "+bound.param+": "+v)
+			return
+		}
+		*bound.dst = t
+	}
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid limit: "+v)
+			return
+		}
+		q.Limit = n
+	}
+	if v := params.Get("failed"); v != "" {
+		failed, err := strconv.ParseBool(v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid failed: "+v)
+			return
+		}
+		q.FailedOnly = failed
+	}
+
+	records, err := s.history.Query(r.Context(), q)
+	if err != nil {
+		s.logger.Error("Failed to query check history", zap.String("stream", name), zap.Error(err))
+		s.writeError(w, http.StatusInternalServerError, "failed to query check history")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, records)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/history"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryAPI(t *testing.T) {
+	checks, err := history.Open(filepath.Join(t.TempDir(), "history.db"), 0)
+	require.NoError(t, err)
+	defer checks.Close()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams: StaticStreams{{Name: "test_stream"}},
+		History: checks,
+	}).Register(mux)
+
+	start := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		require.NoError(t, checks.Record(&models.CheckResult{
+			StreamName: "test_stream",
+			Success:    i != 1,
+			Timestamp:  start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	query := func(params string) []models.HistoryRecord {
+		t.Helper()
+		rec := doRequest(mux, http.MethodGet, "/api/v1/streams/test_stream/history"+params, "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var records []models.HistoryRecord
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+		return records
+	}
+
+	assert.Len(t, query(""), 3)
+	assert.Len(t, query("?limit=2"), 2)
+	assert.Len(t, query("?since=2026-10-15T10:01:00Z"), 2)
+	assert.Len(t, query("?until=2026-10-15T10:01:00Z"), 2)
+	failed := query("?failed=true")
+	require.Len(t, failed, 1)
+	assert.False(t, failed[0].Success)
+
+	for _, params := range []string{"?limit=-1", "?since=yesterday", "?failed=maybe"} {
+		rec := doRequest(mux, http.MethodGet, "/api/v1/streams/test_stream/history"+params, "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, params)
+	}
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/streams/missing/history", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// listIncidents возвращает текущие инциденты всех стримов
+func (s *Server) listIncidents(w http.ResponseWriter, r *http.Request) {
+	incidents := []*models.Incident{}
+	for _, incident := range s.incidents.List() {
+		if s.visibleName(r, incident.Stream) {
+			incidents = append(incidents, incident)
+		}
+	}
+	s.writeJSON(w, http.StatusOK, incidents)
+}
+
+// getIncident возвращает текущий инцидент стрима
+func (s *Server) getIncident(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.streams.Stream(name); !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+
+	incident, ok := s.incidents.Get(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "no current incident")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, incident)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentsAPI(t *testing.T) {
+	incidents := store.NewIncidentStore()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams:   StaticStreams{{Name: "test_stream"}, {Name: "healthy_stream"}},
+		Incidents: incidents,
+	}).Register(mux)
+
+	failed := &models.CheckResult{
+		StreamName: "test_stream",
+		Timestamp:  time.Now(),
+		Error:      &models.CheckError{Type: models.ErrPlaylistDownload, StatusCode: http.StatusServiceUnavailable},
+	}
+	incidents.Observe(failed)
+	incidents.Observe(failed)
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/streams/test_stream/incident", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var incident models.Incident
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &incident))
+	assert.Equal(t, 2, incident.Occurrences)
+	assert.Equal(t, models.ErrPlaylistDownload, incident.ErrorType)
+	assert.NotEmpty(t, incident.Fingerprint)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/streams/healthy_stream/incident", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doRequest(mux, http.MethodGet, "/api/v1/streams/missing/incident", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/incidents", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []models.Incident
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "test_stream", list[0].Stream)
+}
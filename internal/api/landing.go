@@ -0,0 +1,135 @@
+package api
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Состояния стрима на странице обзора
+const (
+	landingUp      = "up"
+	landingDown    = "down"
+	landingPending = "pending"
+	landingPaused  = "paused"
+	landingRemote  = "remote"
+)
+
+// landingRow строка таблицы стримов на странице обзора
+type landingRow struct {
+	Name      string
+	State     string
+	LastCheck time.Time
+	Duration  time.Duration
+	Error     string
+	NextCheck time.Time
+}
+
+// pageFuncs функции шаблонов страницы обзора и панели мониторинга
+var pageFuncs = template.FuncMap{
+	"since": func(t time.Time) string {
+		return time.Since(t).Truncate(time.Second).String() + " ago"
+	},
+	"until": func(t time.Time) string {
+		if d := time.Until(t); d > 0 {
+			return "in " + d.Truncate(time.Second).String()
+		}
+		return "due"
+	},
+	"ms": func(d time.Duration) string {
+		return d.Truncate(time.Millisecond).String()
+	},
+}
+
+var landingTemplate = template.Must(template.New("landing").Funcs(pageFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="30">
+<title>HLS Exporter</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #ddd; text-align: left; vertical-align: top; }
+.up { color: #1a7f37; } .down { color: #cf222e; font-weight: bold; }
+.pending, .paused, .remote { color: #6e7781; }
+.error { max-width: 60em; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>HLS Exporter</h1>
+<p>{{.Up}} of {{len .Rows}} streams up. <a href="/dashboard">Dashboard</a>, <a href="{{.MetricsPath}}">Metrics</a>, <a href="` + Prefix + `/results">results API</a>.</p>
+<table>
+<tr><th>Stream</th><th>State</th><th>Last check</th><th>Duration</th><th>Next check</th><th>Last error</th></tr>
+{{range .Rows}}<tr>
+<td><a href="` + Prefix + `/results/{{.Name}}">{{.Name}}</a></td>
+<td class="{{.State}}">{{.State}}</td>
+<td>{{if not .LastCheck.IsZero}}<span title="{{.LastCheck.Format "2006-01-02T15:04:05Z07:00"}}">{{since .LastCheck}}</span>{{end}}</td>
+<td>{{if .Duration}}{{ms .Duration}}{{end}}</td>
+<td>{{if not .NextCheck.IsZero}}{{until .NextCheck}}{{end}}</td>
+<td class="error">{{.Error}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// landingPage отдает HTML-страницу с состоянием стримов: последний результат,
+// последняя ошибка и время следующей проверки
+func (s *Server) landingPage(w http.ResponseWriter, _ *http.Request) {
+	streams := s.streams.Streams()
+	data := struct {
+		Rows        []landingRow
+		Up          int
+		MetricsPath string
+	}{
+		Rows:        make([]landingRow, 0, len(streams)),
+		MetricsPath: s.metricsPath,
+	}
+	for _, stream := range streams {
+		row := landingRow{Name: stream.Name}
+		result, ok := s.results.Get(stream.Name)
+		row.State, row.NextCheck = s.streamState(stream.Name, result, ok)
+		if ok {
+			row.LastCheck = result.Timestamp
+			row.Duration = result.Duration
+			if result.Error != nil {
+				row.Error = result.Error.Message
+			}
+		}
+		if row.State == landingUp {
+			data.Up++
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := landingTemplate.Execute(w, data); err != nil {
+		s.logger.Error("Failed to render landing page", zap.Error(err))
+	}
+}
+
+// streamState возвращает состояние стрима для страниц и время его следующей проверки
+func (s *Server) streamState(name string, result *models.CheckResult, checked bool) (string, time.Time) {
+	state := landingPending
+	if checked {
+		state = landingDown
+		if result.Success {
+			state = landingUp
+		}
+	}
+	if s.manager == nil {
+		return state, time.Time{}
+	}
+	switch {
+	case s.manager.Paused(name):
+		state = landingPaused
+	case !s.manager.Owns(name):
+		state = landingRemote
+	}
+	next, _ := s.manager.NextCheck(name)
+	return state, next
+}
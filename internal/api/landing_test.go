@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLandingPage(t *testing.T) {
+	mux, sched := newStreamsTestServer(t, false)
+
+	// Первая проверка планирует следующую через interval
+	require.Eventually(t, func() bool {
+		next, ok := sched.NextCheck("test_stream")
+		return ok && time.Until(next) > 30*time.Second
+	}, time.Second, 10*time.Millisecond)
+
+	rec := doRequest(mux, http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "1 of 1 streams up")
+	assert.Contains(t, body, `<a href="/api/v1/results/test_stream">test_stream</a>`)
+	assert.Contains(t, body, `<td class="up">up</td>`)
+	assert.Contains(t, body, "<td>in ")
+
+	// Время следующей проверки возвращается и в API стримов
+	rec = doRequest(mux, http.MethodGet, "/api/v1/streams/test_stream", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stream streamResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stream))
+	require.NotNil(t, stream.NextCheck)
+
+	// Страница отдается только по корневому пути
+	rec = doRequest(mux, http.MethodGet, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestLandingPage_Errors(t *testing.T) {
+	results := store.NewResultStore()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams: StaticStreams{{Name: "news"}, {Name: "sport"}},
+		Results: results,
+		Logger:  zap.NewNop(),
+	}).Register(mux)
+	results.Save(&models.CheckResult{
+		StreamName: "news",
+		Timestamp:  time.Now(),
+		Error:      &models.CheckError{Type: models.ErrPlaylistDownload, Message: "unexpected status code: <503>"},
+	})
+
+	body := doRequest(mux, http.MethodGet, "/", "").Body.String()
+	assert.Contains(t, body, "0 of 2 streams up")
+	assert.Contains(t, body, `<td class="down">down</td>`)
+	assert.Contains(t, body, `<td class="pending">pending</td>`)
+	// Текст ошибки экранируется
+	assert.Contains(t, body, "unexpected status code: &lt;503&gt;")
+}
+
+func TestLandingPage_DisabledWithTokens(t *testing.T) {
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams: StaticStreams{{Name: "news"}},
+		Results: store.NewResultStore(),
+		Tokens:  []models.APIToken{{Name: "noc", Token: "secret"}},
+		Logger:  zap.NewNop(),
+	}).Register(mux)
+
+	rec := doRequest(mux, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doRequest(mux, http.MethodGet, "/dashboard", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
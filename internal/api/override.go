@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// maxOverrideTTL ограничивает время жизни переопределения
+const maxOverrideTTL = 24 * time.Hour
+
+type overrideRequest struct {
+	Interval        string `json:"interval,omitempty"`
+	CheckMode       string `json:"check_mode,omitempty"`
+	ValidateContent *bool  `json:"validate_content,omitempty"`
+	TTL             string `json:"ttl"`
+}
+
+type overrideResponse struct {
+	Stream          string    `json:"stream"`
+	Interval        string    `json:"interval,omitempty"`
+	CheckMode       string    `json:"check_mode,omitempty"`
+	ValidateContent *bool     `json:"validate_content,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func newOverrideResponse(name string, o override.Override) overrideResponse {
+	resp := overrideResponse{
+		Stream:          name,
+		CheckMode:       o.CheckMode,
+		ValidateContent: o.ValidateContent,
+		ExpiresAt:       o.ExpiresAt,
+	}
+	if o.Interval > 0 {
+		resp.Interval = o.Interval.String()
+	}
+	return resp
+}
+
+func (s *Server) getOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	o, ok := s.overrides.Get(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "override not found")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newOverrideResponse(name, o))
+}
+
+func (s *Server) putOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.streams.Stream(name); !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+
+	var req overrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	o, err := req.toOverride(time.Now())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.overrides.Set(name, o)
+	s.audit(r, models.AuditOverrideSet, name, "expires_at="+o.ExpiresAt.Format(time.RFC3339))
+	s.logger.Info("Stream override set",
+		zap.String("stream", name),
+		zap.Time("expires_at", o.ExpiresAt))
+	s.writeJSON(w, http.StatusOK, newOverrideResponse(name, o))
+}
+
+func (s *Server) deleteOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !s.overrides.Delete(name) {
+		s.writeError(w, http.StatusNotFound, "override not found")
+		return
+	}
+	s.audit(r, models.AuditOverrideRemoved, name, "")
+	s.logger.Info("Stream override removed", zap.String("stream", name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toOverride проверяет запрос и преобразует его в переопределение
+func (req overrideRequest) toOverride(now time.Time) (override.Override, error) {
+	var o override.Override
+
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil {
+		return o, fmt.Errorf("invalid ttl: %q", req.TTL)
+	}
+	if ttl <= 0 || ttl > maxOverrideTTL {
+		return o, fmt.Errorf("ttl must be in range (0, %s]", maxOverrideTTL)
+	}
+	o.ExpiresAt = now.Add(ttl)
+
+	if req.Interval != "" {
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil || interval <= 0 {
+			return o, fmt.Errorf("invalid interval: %q", req.Interval)
+		}
+		o.Interval = interval
+	}
+
+	if req.CheckMode != "" {
+		switch req.CheckMode {
+		case models.CheckModeAll, models.CheckModeFirstLast, models.CheckModeRandom,
+			models.CheckModeNewestN, models.CheckModePlaylistOnly:
+			o.CheckMode = req.CheckMode
+		default:
+			return o, fmt.Errorf("invalid check_mode: %s", req.CheckMode)
+		}
+	}
+
+	o.ValidateContent = req.ValidateContent
+
+	if o.Interval == 0 && o.CheckMode == "" && o.ValidateContent == nil {
+		return o, fmt.Errorf("override must change at least one of interval, check_mode, validate_content")
+	}
+
+	return o, nil
+}
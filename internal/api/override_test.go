@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestServer(admin bool) (*http.ServeMux, *override.Store) {
+	mux, overrides, _ := newTestServerWithResults(admin)
+	return mux, overrides
+}
+
+func newTestServerWithResults(admin bool) (*http.ServeMux, *override.Store, *store.ResultStore) {
+	streams := StaticStreams{{
+		Name:      "test_stream",
+		URL:       "http://example.com/master.m3u8",
+		CheckMode: models.CheckModeFirstLast,
+		Interval:  time.Minute,
+		Timeout:   10 * time.Second,
+	}}
+	overrides := override.NewStore()
+	results := store.NewResultStore()
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams:   streams,
+		Results:   results,
+		Overrides: overrides,
+		Logger:    zap.NewNop(),
+		Admin:     admin,
+	}).Register(mux)
+	return mux, overrides, results
+}
+
+func doRequest(mux *http.ServeMux, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestOverrideAPI(t *testing.T) {
+	mux, overrides := newTestServer(true)
+	path := "/api/v1/streams/test_stream/override"
+
+	rec := doRequest(mux, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(mux, http.MethodPut, path, `{"interval":"10s","check_mode":"all","validate_content":true,"ttl":"1h"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp overrideResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "test_stream", resp.Stream)
+	assert.Equal(t, "10s", resp.Interval)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), resp.ExpiresAt, 5*time.Second)
+
+	o, ok := overrides.Get("test_stream")
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Second, o.Interval)
+	assert.Equal(t, models.CheckModeAll, o.CheckMode)
+
+	rec = doRequest(mux, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = doRequest(mux, http.MethodDelete, path, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	_, ok = overrides.Get("test_stream")
+	assert.False(t, ok)
+}
+
+func TestOverrideAPI_Validation(t *testing.T) {
+	mux, _ := newTestServer(true)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		code int
+	}{
+		{"unknown stream", "/api/v1/streams/missing/override", `{"interval":"10s","ttl":"1h"}`, http.StatusNotFound},
+		{"invalid json", "/api/v1/streams/test_stream/override", `{`, http.StatusBadRequest},
+		{"missing ttl", "/api/v1/streams/test_stream/override", `{"interval":"10s"}`, http.StatusBadRequest},
+		{"ttl too long", "/api/v1/streams/test_stream/override", `{"interval":"10s","ttl":"48h"}`, http.StatusBadRequest},
+		{"invalid check mode", "/api/v1/streams/test_stream/override", `{"check_mode":"bogus","ttl":"1h"}`, http.StatusBadRequest},
+		{"nothing to override", "/api/v1/streams/test_stream/override", `{"ttl":"1h"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(mux, http.MethodPut, tt.path, tt.body)
+			assert.Equal(t, tt.code, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestOverrideAPI_Disabled(t *testing.T) {
+	mux, _ := newTestServer(false)
+	rec := doRequest(mux, http.MethodPut, "/api/v1/streams/test_stream/override", `{"interval":"10s","ttl":"1h"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// listResults возвращает последние результаты проверок всех стримов
+func (s *Server) listResults(w http.ResponseWriter, r *http.Request) {
+	results := []*models.CheckResult{}
+	for _, result := range s.results.List() {
+		if s.visibleName(r, result.StreamName) {
+			results = append(results, result)
+		}
+	}
+	s.writeJSON(w, http.StatusOK, results)
+}
+
+// getResult возвращает последний результат проверки стрима
+func (s *Server) getResult(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("stream")
+	if _, ok := s.streams.Stream(name); !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+
+	result, ok := s.results.Get(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "no check result yet")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// getUnknownTags возвращает нестандартные теги, встреченные в плейлистах стрима
+func (s *Server) getUnknownTags(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := s.streams.Stream(name); !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+	tags := s.tags.UnknownTags(name)
+	if tags == nil {
+		tags = []models.UnknownTag{}
+	}
+	s.writeJSON(w, http.StatusOK, tags)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultsAPI(t *testing.T) {
+	mux, _, results := newTestServerWithResults(false)
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/results", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/results/test_stream", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/results/missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	results.Save(&models.CheckResult{
+		StreamName: "test_stream",
+		Success:    false,
+		Timestamp:  time.Now(),
+		Segments: models.SegmentResults{
+			Checked: 1,
+			Failed:  1,
+			Total:   1,
+			Details: []models.SegmentCheck{{
+				URL: "http://example.com/segment1.ts",
+				Error: &models.CheckError{
+					Type:       models.ErrSegmentDownload,
+					Message:    "unexpected status code: 404",
+					StatusCode: http.StatusNotFound,
+				},
+			}},
+		},
+		Error: &models.CheckError{
+			Type:    models.ErrSegmentValidate,
+			Message: "1 of 1 segments failed validation",
+		},
+	})
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/results/test_stream", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var result models.CheckResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "test_stream", result.StreamName)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrSegmentValidate, result.Error.Type)
+	require.Len(t, result.Segments.Details, 1)
+	assert.Equal(t, http.StatusNotFound, result.Segments.Details[0].Error.StatusCode)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/results", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []models.CheckResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list, 1)
+}
+
+// stubTags фиксированный набор нестандартных тегов
+type stubTags map[string][]models.UnknownTag
+
+func (s stubTags) UnknownTags(stream string) []models.UnknownTag {
+	return s[stream]
+}
+
+func TestUnknownTagsAPI(t *testing.T) {
+	seen := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Streams: StaticStreams{{Name: "test_stream"}, {Name: "clean_stream"}},
+		Tags: stubTags{"test_stream": {{
+			Tag:       "EXT-X-CUE-OUT",
+			FirstSeen: seen,
+			LastSeen:  seen,
+			Checks:    3,
+		}}},
+	}).Register(mux)
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/streams/test_stream/tags", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var tags []models.UnknownTag
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tags))
+	require.Len(t, tags, 1)
+	assert.Equal(t, "EXT-X-CUE-OUT", tags[0].Tag)
+	assert.Equal(t, int64(3), tags[0].Checks)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/streams/clean_stream/tags", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/streams/missing/tags", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,389 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/config"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// streamRequest тело POST и PATCH запросов /api/v1/streams.
+// В PATCH незаданные поля сохраняют текущие значения.
+type streamRequest struct {
+	Name            string                  `json:"name"`
+	URL             string                  `json:"url,omitempty"`
+	Profile         string                  `json:"profile,omitempty"`
+	Device          string                  `json:"device,omitempty"`
+	CheckMode       string                  `json:"check_mode,omitempty"`
+	Interval        string                  `json:"interval,omitempty"`
+	Timeout         string                  `json:"timeout,omitempty"`
+	ValidateContent *bool                   `json:"validate_content,omitempty"`
+	MediaValidation *models.MediaValidation `json:"media_validation,omitempty"`
+	DailyByteBudget *int64                  `json:"daily_byte_budget,omitempty"`
+	Strict          *bool                   `json:"strict,omitempty"`
+	ParseMode       string                  `json:"parse_mode,omitempty"`
+	// Заголовки заменяются целиком, пустой объект удаляет их
+	Headers   map[string]string `json:"headers,omitempty"`
+	BasicAuth *models.BasicAuth `json:"basic_auth,omitempty"`
+	// Токен заменяет basic_auth, пустая строка отключает авторизацию
+	BearerToken *string `json:"bearer_token,omitempty"`
+	// Подмена адресов заменяется целиком, пустой список удаляет ее
+	Resolve []string `json:"resolve,omitempty"`
+	// Пустая строка убирает стрим из группы
+	Group *string `json:"group,omitempty"`
+	// Метки заменяются целиком, пустой объект удаляет их
+	Labels map[string]string `json:"labels,omitempty"`
+	Paused *bool             `json:"paused,omitempty"`
+}
+
+type streamResponse struct {
+	Name            string                  `json:"name"`
+	URL             string                  `json:"url"`
+	Profile         string                  `json:"profile,omitempty"`
+	Device          string                  `json:"device,omitempty"`
+	CheckMode       string                  `json:"check_mode"`
+	Interval        string                  `json:"interval"`
+	Timeout         string                  `json:"timeout"`
+	ValidateContent bool                    `json:"validate_content"`
+	MediaValidation *models.MediaValidation `json:"media_validation,omitempty"`
+	DailyByteBudget int64                   `json:"daily_byte_budget,omitempty"`
+	Strict          bool                    `json:"strict"`
+	ParseMode       string                  `json:"parse_mode,omitempty"`
+	// Значения заголовков и учетные данные не возвращаются: они могут быть секретными
+	Headers []string          `json:"headers,omitempty"`
+	Auth    string            `json:"auth,omitempty"`
+	Resolve []string          `json:"resolve,omitempty"`
+	Group   string            `json:"group,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Paused  bool              `json:"paused"`
+	// Owned - стрим проверяется этим экземпляром (в режиме кластера)
+	Owned bool `json:"owned"`
+	// NextCheck время следующей плановой проверки
+	NextCheck *time.Time `json:"next_check,omitempty"`
+}
+
+func (s *Server) newStreamResponse(stream models.StreamConfig) streamResponse {
+	resp := streamResponse{
+		Name:            stream.Name,
+		URL:             stream.URL,
+		Profile:         stream.Profile,
+		Device:          stream.Device,
+		CheckMode:       stream.CheckMode,
+		Interval:        stream.Interval.String(),
+		Timeout:         stream.Timeout.String(),
+		ValidateContent: stream.ValidateContent,
+		MediaValidation: stream.MediaValidation,
+		DailyByteBudget: stream.DailyByteBudget,
+		Strict:          stream.Strict,
+		ParseMode:       stream.ParseMode,
+		Headers:         headerNames(stream.Headers),
+		Auth:            authMethod(stream),
+		Resolve:         stream.Resolve,
+		Group:           stream.Group,
+		Labels:          stream.Labels,
+		Paused:          s.manager != nil && s.manager.Paused(stream.Name),
+		Owned:           s.manager == nil || s.manager.Owns(stream.Name),
+	}
+	if s.manager != nil {
+		if next, ok := s.manager.NextCheck(stream.Name); ok {
+			resp.NextCheck = &next
+		}
+	}
+	return resp
+}
+
+// headerNames возвращает отсортированные имена заголовков стрима
+func headerNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// authMethod возвращает способ авторизации стрима: basic, bearer или пусто
+func authMethod(stream models.StreamConfig) string {
+	switch {
+	case stream.BasicAuth != nil:
+		return "basic"
+	case stream.BearerToken != "":
+		return "bearer"
+	}
+	return ""
+}
+
+// listStreams возвращает активный набор стримов
+func (s *Server) listStreams(w http.ResponseWriter, r *http.Request) {
+	streams := s.streams.Streams()
+	resp := make([]streamResponse, 0, len(streams))
+	for _, stream := range streams {
+		if s.visible(r, stream) {
+			resp = append(resp, s.newStreamResponse(stream))
+		}
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) getStream(w http.ResponseWriter, r *http.Request) {
+	stream, ok := s.streams.Stream(r.PathValue("name"))
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, s.newStreamResponse(stream))
+}
+
+// createStream добавляет стрим и запускает его проверки
+func (s *Server) createStream(w http.ResponseWriter, r *http.Request) {
+	var req streamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	stream := models.StreamConfig{Name: req.Name, Profile: req.Profile, Device: req.Device}
+	if err := req.apply(&stream); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if stream.Profile != "" {
+		// Viper приводит ключи профилей к нижнему регистру
+		profile, ok := s.profiles[strings.ToLower(stream.Profile)]
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown profile: %s", stream.Profile))
+			return
+		}
+		config.ApplyProfile(&stream, profile)
+	}
+	if stream.Device != "" {
+		device, ok := s.devices[strings.ToLower(stream.Device)]
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown device: %s", stream.Device))
+			return
+		}
+		config.ApplyDevice(&stream, device)
+	}
+	if !s.visible(r, stream) {
+		s.writeError(w, http.StatusForbidden, "stream is outside of the API token scope")
+		return
+	}
+	if err := s.validator.ValidateStream(&stream, len(s.manager.Streams())); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := config.ValidateLabelNames(append(s.manager.Streams(), stream)); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.manager.Add(stream); err != nil {
+		if errors.Is(err, models.ErrStreamExists) {
+			s.writeError(w, http.StatusConflict, "stream already exists")
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.Paused != nil && *req.Paused {
+		if err := s.manager.SetPaused(stream.Name, true); err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	s.audit(r, models.AuditStreamAdded, stream.Name, "url="+stream.URL)
+	s.logger.Info("Stream added", zap.String("stream", stream.Name), zap.String("url", stream.URL))
+	s.writeJSON(w, http.StatusCreated, s.newStreamResponse(stream))
+}
+
+// patchStream изменяет параметры стрима или приостанавливает его проверки
+func (s *Server) patchStream(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	stream, ok := s.manager.Stream(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+
+	var req streamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.Name != "" && req.Name != name {
+		s.writeError(w, http.StatusBadRequest, "stream name cannot be changed")
+		return
+	}
+	if req.Profile != "" {
+		s.writeError(w, http.StatusBadRequest, "profile cannot be changed")
+		return
+	}
+
+	updated := stream
+	if err := req.apply(&updated); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.visible(r, updated) {
+		s.writeError(w, http.StatusForbidden, "stream is outside of the API token scope")
+		return
+	}
+	if err := s.validator.ValidateStream(&updated, s.streamIndex(name)); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := config.ValidateLabelNames(s.replaceStream(updated)); err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.changesConfig() {
+		if err := s.manager.Update(updated); err != nil {
+			s.writeManagerError(w, err)
+			return
+		}
+		s.audit(r, models.AuditStreamUpdated, name, "")
+		s.logger.Info("Stream updated", zap.String("stream", name))
+	}
+	if req.Paused != nil {
+		if err := s.manager.SetPaused(name, *req.Paused); err != nil {
+			s.writeManagerError(w, err)
+			return
+		}
+		action := models.AuditStreamResumed
+		if *req.Paused {
+			action = models.AuditStreamPaused
+		}
+		s.audit(r, action, name, "")
+		s.logger.Info("Stream pause state changed",
+			zap.String("stream", name),
+			zap.Bool("paused", *req.Paused))
+	}
+
+	s.writeJSON(w, http.StatusOK, s.newStreamResponse(updated))
+}
+
+// deleteStream останавливает проверки стрима и удаляет его
+func (s *Server) deleteStream(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if err := s.manager.Remove(name); err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	s.audit(r, models.AuditStreamRemoved, name, "")
+	s.logger.Info("Stream removed", zap.String("stream", name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) writeManagerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, models.ErrStreamNotFound) {
+		s.writeError(w, http.StatusNotFound, "stream not found")
+		return
+	}
+	s.writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// streamIndex позиция стрима в наборе для сообщений валидации
+func (s *Server) streamIndex(name string) int {
+	for i, stream := range s.manager.Streams() {
+		if stream.Name == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// replaceStream возвращает набор стримов с измененной конфигурацией стрима
+func (s *Server) replaceStream(updated models.StreamConfig) []models.StreamConfig {
+	streams := s.manager.Streams()
+	for i := range streams {
+		if streams[i].Name == updated.Name {
+			streams[i] = updated
+		}
+	}
+	return streams
+}
+
+// apply переносит заданные в запросе поля в конфигурацию стрима
+func (req streamRequest) apply(stream *models.StreamConfig) error {
+	if req.URL != "" {
+		stream.URL = req.URL
+	}
+	if req.CheckMode != "" {
+		stream.CheckMode = req.CheckMode
+	}
+	if req.Interval != "" {
+		interval, err := time.ParseDuration(req.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid interval: %q", req.Interval)
+		}
+		stream.Interval = interval
+	}
+	if req.Timeout != "" {
+		timeout, err := time.ParseDuration(req.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %q", req.Timeout)
+		}
+		stream.Timeout = timeout
+	}
+	if req.ValidateContent != nil {
+		stream.ValidateContent = *req.ValidateContent
+	}
+	if req.MediaValidation != nil {
+		mv := *req.MediaValidation
+		stream.MediaValidation = &mv
+	}
+	if req.DailyByteBudget != nil {
+		stream.DailyByteBudget = *req.DailyByteBudget
+	}
+	if req.Strict != nil {
+		stream.Strict = *req.Strict
+	}
+	if req.ParseMode != "" {
+		stream.ParseMode = req.ParseMode
+	}
+	if req.Headers != nil {
+		stream.Headers = req.Headers
+	}
+	if req.BasicAuth != nil && req.BearerToken != nil {
+		return fmt.Errorf("basic_auth and bearer_token are mutually exclusive")
+	}
+	if req.BasicAuth != nil {
+		ba := *req.BasicAuth
+		stream.BasicAuth = &ba
+		stream.BearerToken = ""
+	}
+	if req.BearerToken != nil {
+		stream.BearerToken = *req.BearerToken
+		stream.BasicAuth = nil
+	}
+	if req.Resolve != nil {
+		stream.Resolve = req.Resolve
+	}
+	if req.Group != nil {
+		stream.Group = *req.Group
+	}
+	if req.Labels != nil {
+		stream.Labels = req.Labels
+		if len(req.Labels) == 0 {
+			stream.Labels = nil
+		}
+	}
+	return nil
+}
+
+// changesConfig сообщает, меняет ли запрос параметры проверки
+func (req streamRequest) changesConfig() bool {
+	return req.URL != "" || req.CheckMode != "" || req.Interval != "" || req.Timeout != "" ||
+		req.ValidateContent != nil || req.MediaValidation != nil || req.DailyByteBudget != nil ||
+		req.Strict != nil || req.ParseMode != "" || req.Headers != nil || req.BasicAuth != nil ||
+		req.BearerToken != nil || req.Resolve != nil || req.Group != nil || req.Labels != nil
+}
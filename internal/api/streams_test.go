@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/internal/scheduler"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubChecker успешно проверяет любой стрим
+type stubChecker struct{}
+
+func (stubChecker) Check(_ context.Context, stream models.StreamConfig) (*models.CheckResult, error) {
+	return &models.CheckResult{StreamName: stream.Name, Success: true}, nil
+}
+func (stubChecker) Start() error  { return nil }
+func (stubChecker) Stop() error   { return nil }
+func (stubChecker) Forget(string) {}
+
+func newStreamsTestServer(t *testing.T, admin bool) (*http.ServeMux, *scheduler.Scheduler) {
+	t.Helper()
+
+	results := store.NewResultStore()
+	overrides := override.NewStore()
+	sched := scheduler.New(scheduler.Dependencies{
+		Checker:   stubChecker{},
+		Metrics:   metrics.NewCollector(prometheus.NewRegistry()),
+		Results:   results,
+		Overrides: overrides,
+	})
+	require.NoError(t, sched.Add(models.StreamConfig{
+		Name:      "test_stream",
+		URL:       "http://example.com/master.m3u8",
+		CheckMode: models.CheckModeFirstLast,
+		Interval:  time.Minute,
+		Timeout:   10 * time.Second,
+	}))
+	sched.Start(context.Background())
+	t.Cleanup(sched.Stop)
+
+	mux := http.NewServeMux()
+	NewServer(Dependencies{
+		Manager: sched,
+		Profiles: map[string]models.ProfileConfig{
+			"sports": {CheckMode: models.CheckModeAll, Interval: 30 * time.Second, Timeout: 5 * time.Second},
+		},
+		Devices: map[string]models.DeviceConfig{
+			"smarttv": {UserAgent: "SmartTV/1.0", Headers: map[string]string{"origin": "https://tv.example.com"}},
+		},
+		Results:   results,
+		Overrides: overrides,
+		Journal:   store.NewJournal(),
+		Logger:    zap.NewNop(),
+		Admin:     admin,
+	}).Register(mux)
+	return mux, sched
+}
+
+func TestStreamsAPI_List(t *testing.T) {
+	mux, _ := newStreamsTestServer(t, false)
+
+	rec := doRequest(mux, http.MethodGet, "/api/v1/streams", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var streams []streamResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &streams))
+	require.Len(t, streams, 1)
+	assert.Equal(t, "test_stream", streams[0].Name)
+	assert.Equal(t, "1m0s", streams[0].Interval)
+	assert.False(t, streams[0].Paused)
+	assert.True(t, streams[0].Owned)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/streams/missing", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestStreamsAPI_AdminDisabled(t *testing.T) {
+	mux, _ := newStreamsTestServer(t, false)
+
+	rec := doRequest(mux, http.MethodPost, "/api/v1/streams", `{"name":"new"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	rec = doRequest(mux, http.MethodDelete, "/api/v1/streams/test_stream", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestStreamsAPI_TriggerCheck(t *testing.T) {
+	mux, sched := newStreamsTestServer(t, true)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/streams/test_stream/check", nil)
+	req.SetBasicAuth("news-team", "secret")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	rec = doRequest(mux, http.MethodPost, "/api/v1/streams/missing/check", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	require.NoError(t, sched.SetPaused("test_stream", true))
+	rec = doRequest(mux, http.MethodPost, "/api/v1/streams/test_stream/check", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Журнал содержит инициатора внеочередной проверки
+	rec = doRequest(mux, http.MethodGet, "/api/v1/events?stream=test_stream", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []models.AuditEvent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, models.AuditCheckTriggered, events[0].Action)
+	assert.Equal(t, "news-team", events[0].Principal)
+	assert.NotEmpty(t, events[0].RemoteAddr)
+
+	rec = doRequest(mux, http.MethodGet, "/api/v1/events?limit=x", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestStreamsAPI_Create(t *testing.T) {
+	mux, sched := newStreamsTestServer(t, true)
+
+	rec := doRequest(mux, http.MethodPost, "/api/v1/streams",
+		`{"name":"new","url":"http://example.com/new.m3u8","check_mode":"all","interval":"30s","timeout":"5s"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	stream, ok := sched.Stream("new")
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, stream.Interval)
+
+	rec = doRequest(mux, http.MethodPost, "/api/v1/streams",
+		`{"name":"new","url":"http://example.com/new.m3u8","check_mode":"all","interval":"30s","timeout":"5s"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Параметры профиля подставляются в незаданные поля
+	rec = doRequest(mux, http.MethodPost, "/api/v1/streams",
+		`{"name":"sport","url":"http://example.com/sport.m3u8","profile":"Sports","paused":true}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	stream, ok = sched.Stream("sport")
+	require.True(t, ok)
+	assert.Equal(t, models.CheckModeAll, stream.CheckMode)
+	assert.True(t, sched.Paused("sport"))
+
+	// Заголовки устройства добавляются к заголовкам стрима
+	rec = doRequest(mux, http.MethodPost, "/api/v1/streams",
+		`{"name":"tv","url":"http://example.com/tv.m3u8","profile":"sports","device":"SmartTV","headers":{"x-key":"k"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	stream, ok = sched.Stream("tv")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{
+		"User-Agent": "SmartTV/1.0",
+		"Origin":     "https://tv.example.com",
+		"X-Key":      "k",
+	}, stream.Headers)
+
+	// Пользовательские метки и группа стрима
+	rec = doRequest(mux, http.MethodPost, "/api/v1/streams",
+		`{"name":"labeled","url":"http://example.com/l.m3u8","profile":"sports","group":"news","labels":`+labelsJSON(0, 5)+`}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	stream, ok = sched.Stream("labeled")
+	require.True(t, ok)
+	assert.Equal(t, "news", stream.Group)
+	assert.Len(t, stream.Labels, 5)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{`},
+		{name: "missing url", body: `{"name":"x","check_mode":"all","interval":"30s","timeout":"5s"}`},
+		{name: "bad interval", body: `{"name":"x","url":"http://e/x.m3u8","check_mode":"all","interval":"soon","timeout":"5s"}`},
+		{name: "timeout above interval", body: `{"name":"x","url":"http://e/x.m3u8","check_mode":"all","interval":"5s","timeout":"30s"}`},
+		{name: "unknown profile", body: `{"name":"x","url":"http://e/x.m3u8","profile":"news"}`},
+		{name: "unknown device", body: `{"name":"x","url":"http://e/x.m3u8","profile":"sports","device":"phone"}`},
+		{name: "reserved label", body: `{"name":"x","url":"http://e/x.m3u8","profile":"sports","labels":{"stream":"y"}}`},
+		{name: "invalid label name", body: `{"name":"x","url":"http://e/x.m3u8","profile":"sports","labels":{"bad-name":"y"}}`},
+		{name: "empty label value", body: `{"name":"x","url":"http://e/x.m3u8","profile":"sports","labels":{"tenant":""}}`},
+		{name: "too many labels", body: `{"name":"x","url":"http://e/x.m3u8","profile":"sports","labels":` + labelsJSON(0, 11) + `}`},
+		// Метки разных стримов в сумме тоже ограничены
+		{name: "too many distinct labels", body: `{"name":"x","url":"http://e/x.m3u8","profile":"sports","labels":` + labelsJSON(5, 11) + `}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(mux, http.MethodPost, "/api/v1/streams", tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+// labelsJSON возвращает объект меток label_from..label_{to-1}
+func labelsJSON(from, to int) string {
+	values := make(map[string]string, to-from)
+	for i := from; i < to; i++ {
+		values[fmt.Sprintf("label_%d", i)] = "x"
+	}
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+func TestStreamsAPI_Patch(t *testing.T) {
+	mux, sched := newStreamsTestServer(t, true)
+
+	rec := doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"paused":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(t, sched.Paused("test_stream"))
+
+	var resp streamResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Paused)
+
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"interval":"2m","paused":false}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ := sched.Stream("test_stream")
+	assert.Equal(t, 2*time.Minute, stream.Interval)
+	assert.Equal(t, "http://example.com/master.m3u8", stream.URL)
+	assert.False(t, sched.Paused("test_stream"))
+
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"strict":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.True(t, stream.Strict)
+	assert.Equal(t, 2*time.Minute, stream.Interval)
+
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"parse_mode":"warn"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Equal(t, models.ParseModeWarn, stream.ParseMode)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"parse_mode":"pedantic"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream",
+		`{"headers":{"X-Origin-Key":"signed"},"basic_auth":{"username":"user","password":"secret"}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Equal(t, map[string]string{"X-Origin-Key": "signed"}, stream.Headers)
+	require.NotNil(t, stream.BasicAuth)
+	assert.Equal(t, "secret", stream.BasicAuth.Password)
+	// Секреты не возвращаются в ответе
+	assert.NotContains(t, rec.Body.String(), "secret")
+	assert.NotContains(t, rec.Body.String(), "signed")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []string{"X-Origin-Key"}, resp.Headers)
+	assert.Equal(t, "basic", resp.Auth)
+
+	// Токен заменяет basic_auth
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"bearer_token":"token"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Nil(t, stream.BasicAuth)
+	assert.Equal(t, "token", stream.BearerToken)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream",
+		`{"bearer_token":"token","basic_auth":{"username":"user"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Подмена адреса для проверки отдельного узла CDN; пустой список удаляет ее
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"resolve":["cdn.example.com:443:203.0.113.10"]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Equal(t, []string{"cdn.example.com:443:203.0.113.10"}, stream.Resolve)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"resolve":["cdn.example.com:443"]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"resolve":[]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Empty(t, stream.Resolve)
+
+	// Группа и пользовательские метки; пустые значения удаляют их
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"group":"news","labels":{"tenant":"media"}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Equal(t, "news", stream.Group)
+	assert.Equal(t, map[string]string{"tenant": "media"}, stream.Labels)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "news", resp.Group)
+	assert.Equal(t, map[string]string{"tenant": "media"}, resp.Labels)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"group":"","labels":{}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	stream, _ = sched.Stream("test_stream")
+	assert.Empty(t, stream.Group)
+	assert.Nil(t, stream.Labels)
+
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"timeout":"5m"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/test_stream", `{"name":"renamed"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(mux, http.MethodPatch, "/api/v1/streams/missing", `{"paused":true}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestStreamsAPI_Delete(t *testing.T) {
+	mux, sched := newStreamsTestServer(t, true)
+
+	rec := doRequest(mux, http.MethodDelete, "/api/v1/streams/test_stream", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	_, ok := sched.Stream("test_stream")
+	assert.False(t, ok)
+
+	rec = doRequest(mux, http.MethodDelete, "/api/v1/streams/test_stream", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Оверрайд-эндпоинты продолжают работать через менеджер
+	rec = doRequest(mux, http.MethodPut, "/api/v1/streams/test_stream/override", `{"interval":"10s","ttl":"1h"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
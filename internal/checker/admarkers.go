@@ -0,0 +1,226 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// adMarkers рекламные метки в окне медиаплейлиста
+type adMarkers struct {
+	// Рекламные паузы: EXT-X-CUE-OUT или EXT-X-DATERANGE с SCTE35-OUT
+	breaks int
+	// Время последней метки по EXT-X-PROGRAM-DATE-TIME или START-DATE, нулевое, если
+	// метка есть, но время ее не известно
+	lastCue time.Time
+	// Метка найдена, даже если ее время не известно
+	hasCue    bool
+	malformed []string
+}
+
+// parseAdMarkers разбирает рекламные метки SCTE-35 медиаплейлиста по строкам:
+// EXT-X-DATERANGE с атрибутами SCTE35-*, EXT-X-CUE-OUT и EXT-X-CUE-IN.
+// Если пауза размечена обоими способами, считается большее из двух чисел пауз.
+func parseAdMarkers(body []byte) adMarkers {
+	var (
+		markers    adMarkers
+		cueOuts    int
+		dateOuts   int
+		segment    time.Time // EXT-X-PROGRAM-DATE-TIME следующего сегмента
+		inDuration float64
+	)
+	cue := func(at time.Time) {
+		markers.hasCue = true
+		if at.After(markers.lastCue) {
+			markers.lastCue = at
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			// URI сегмента: следующий сегмент начинается после текущего
+			if !segment.IsZero() {
+				segment = segment.Add(time.Duration(inDuration * float64(time.Second)))
+			}
+			inDuration = 0
+			continue
+		}
+
+		tag, value, _ := strings.Cut(line, ":")
+		switch tag {
+		case "#EXT-X-PROGRAM-DATE-TIME":
+			if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+				segment = t
+			}
+		case "#EXTINF":
+			durationStr, _, _ := strings.Cut(value, ",")
+			inDuration, _ = strconv.ParseFloat(durationStr, 64)
+		case "#EXT-X-CUE-OUT":
+			cueOuts++
+			cue(segment)
+			if err := validateCueOut(value); err != nil {
+				markers.malformed = append(markers.malformed, err.Error())
+			}
+		case "#EXT-X-CUE-IN":
+			cue(segment)
+		case "#EXT-X-DATERANGE":
+			out, start, err := parseDateRange(value)
+			if err != nil {
+				markers.malformed = append(markers.malformed, err.Error())
+				continue
+			}
+			if out {
+				dateOuts++
+			}
+			if !start.IsZero() {
+				cue(start)
+			}
+		}
+	}
+	markers.breaks = max(cueOuts, dateOuts)
+	return markers
+}
+
+// validateCueOut проверяет длительность паузы EXT-X-CUE-OUT: "30", "DURATION=30" или пусто
+func validateCueOut(value string) error {
+	if value == "" {
+		return nil
+	}
+	duration := value
+	if strings.Contains(value, "=") {
+		attrs, err := parseAttributeList(value)
+		if err != nil {
+			return fmt.Errorf("EXT-X-CUE-OUT: %w", err)
+		}
+		var ok bool
+		if duration, ok = attrs["DURATION"]; !ok {
+			return nil
+		}
+	}
+	if d, err := strconv.ParseFloat(duration, 64); err != nil || d < 0 {
+		return fmt.Errorf("EXT-X-CUE-OUT: invalid duration %q", duration)
+	}
+	return nil
+}
+
+// parseDateRange разбирает EXT-X-DATERANGE. Возвращает признак начала паузы (SCTE35-OUT)
+// и START-DATE, если метка содержит SCTE-35; для прочих EXT-X-DATERANGE время нулевое.
+func parseDateRange(value string) (bool, time.Time, error) {
+	attrs, err := parseAttributeList(value)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("EXT-X-DATERANGE: %w", err)
+	}
+	id := attrs["ID"]
+	if id == "" {
+		return false, time.Time{}, fmt.Errorf("EXT-X-DATERANGE: missing ID")
+	}
+	startStr, ok := attrs["START-DATE"]
+	if !ok {
+		return false, time.Time{}, fmt.Errorf("EXT-X-DATERANGE %s: missing START-DATE", id)
+	}
+	start, err := time.Parse(time.RFC3339Nano, startStr)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("EXT-X-DATERANGE %s: invalid START-DATE %q", id, startStr)
+	}
+
+	scte := false
+	for _, name := range []string{"SCTE35-CMD", "SCTE35-OUT", "SCTE35-IN"} {
+		payload, ok := attrs[name]
+		if !ok {
+			continue
+		}
+		scte = true
+		if !validHexSequence(payload) {
+			return false, time.Time{}, fmt.Errorf("EXT-X-DATERANGE %s: invalid %s", id, name)
+		}
+	}
+	if !scte {
+		return false, time.Time{}, nil
+	}
+	_, out := attrs["SCTE35-OUT"]
+	return out, start, nil
+}
+
+// validHexSequence проверяет шестнадцатеричную последовательность вида 0xFC30...
+func validHexSequence(s string) bool {
+	digits, ok := strings.CutPrefix(strings.ToLower(s), "0x")
+	if !ok || digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAttributeList разбирает список атрибутов вида NAME=VALUE,NAME="quoted"
+func parseAttributeList(value string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	rest := value
+	for rest != "" {
+		name, after, found := strings.Cut(rest, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("malformed attribute list")
+		}
+		var attrValue string
+		if strings.HasPrefix(after, `"`) {
+			end := strings.IndexByte(after[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("attribute %s has unterminated quoted string", name)
+			}
+			attrValue, rest = after[1:end+1], after[end+2:]
+			if rest != "" && !strings.HasPrefix(rest, ",") {
+				return nil, fmt.Errorf("malformed attribute list")
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			attrValue, rest, _ = strings.Cut(after, ",")
+		}
+		attrs[strings.TrimSpace(name)] = attrValue
+	}
+	return attrs, nil
+}
+
+// recordAdMarkers публикует рекламные метки окна медиаплейлиста. Метка без известного
+// времени (нет EXT-X-PROGRAM-DATE-TIME) относится ко времени проверки.
+func (c *StreamChecker) recordAdMarkers(
+	stream models.StreamConfig,
+	variant string,
+	markers adMarkers,
+	result *models.CheckResult,
+) {
+	c.metrics.SetAdMarkers(stream.Name, variant, markers.breaks, len(markers.malformed))
+
+	if result.AdMarkers == nil {
+		result.AdMarkers = &models.AdMarkersCheck{}
+	}
+	ads := result.AdMarkers
+	ads.Breaks = max(ads.Breaks, markers.breaks)
+	for _, problem := range markers.malformed {
+		ads.Malformed = append(ads.Malformed, variant+": "+problem)
+	}
+
+	if !markers.hasCue {
+		return
+	}
+	cue := markers.lastCue
+	if cue.IsZero() {
+		cue = result.Timestamp
+	}
+	c.metrics.SetLastAdCue(stream.Name, variant, cue)
+	if cue.After(ads.LastCue) {
+		ads.LastCue = cue
+	}
+}
@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const adMarkersPlaylist = `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-MEDIA-SEQUENCE:10
+#EXT-X-PROGRAM-DATE-TIME:2024-05-01T12:00:00Z
+#EXTINF:6.0,
+seg10.ts
+#EXT-X-DATERANGE:ID="splice-1",START-DATE="2024-05-01T12:00:06Z",PLANNED-DURATION=30,SCTE35-OUT=0xFC002F0000
+#EXT-X-CUE-OUT:30
+#EXTINF:6.0,
+seg11.ts
+#EXT-X-CUE-OUT-CONT:ElapsedTime=6,Duration=30
+#EXTINF:6.0,
+seg12.ts
+#EXT-X-CUE-IN
+#EXTINF:6.0,
+seg13.ts
+#EXT-X-DATERANGE:ID="chapter",START-DATE="2024-05-01T12:10:00Z",CLASS="com.example.chapter"
+#EXT-X-DATERANGE:ID="broken",START-DATE="2024-05-01T12:00:24Z",SCTE35-IN=FC00
+#EXT-X-CUE-OUT:DURATION=abc
+#EXTINF:6.0,
+seg14.ts
+`
+
+func TestParseAdMarkers(t *testing.T) {
+	markers := parseAdMarkers([]byte(adMarkersPlaylist))
+
+	// Пауза splice-1 размечена и DATERANGE, и CUE-OUT; вторая CUE-OUT некорректна, но считается
+	assert.Equal(t, 2, markers.breaks)
+	assert.True(t, markers.hasCue)
+	// Последняя метка - CUE-OUT перед seg14 (12:00:24), DATERANGE без SCTE-35 не учитывается
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 24, 0, time.UTC), markers.lastCue)
+	assert.Equal(t, []string{
+		"EXT-X-DATERANGE broken: invalid SCTE35-IN",
+		`EXT-X-CUE-OUT: invalid duration "abc"`,
+	}, markers.malformed)
+}
+
+func TestParseAdMarkers_NoMarkers(t *testing.T) {
+	assert.Equal(t, adMarkers{}, parseAdMarkers([]byte(markersPlaylist)))
+}
+
+func TestParseAdMarkers_Malformed(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{name: "missing id", tag: `#EXT-X-DATERANGE:START-DATE="2024-05-01T12:00:00Z"`, want: "EXT-X-DATERANGE: missing ID"},
+		{name: "missing start", tag: `#EXT-X-DATERANGE:ID="a",SCTE35-OUT=0xFC`, want: "EXT-X-DATERANGE a: missing START-DATE"},
+		{name: "bad start", tag: `#EXT-X-DATERANGE:ID="a",START-DATE="yesterday"`, want: `EXT-X-DATERANGE a: invalid START-DATE "yesterday"`},
+		{name: "unterminated", tag: `#EXT-X-DATERANGE:ID="a`, want: "EXT-X-DATERANGE: attribute ID has unterminated quoted string"},
+		{name: "negative cue-out", tag: `#EXT-X-CUE-OUT:-5`, want: `EXT-X-CUE-OUT: invalid duration "-5"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markers := parseAdMarkers([]byte("#EXTM3U\n" + tt.tag + "\n"))
+			assert.Equal(t, []string{tt.want}, markers.malformed)
+		})
+	}
+}
+
+func TestStreamChecker_RecordAdMarkers(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream", AdMarkers: true}
+	checkTime := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC)
+	result := &models.CheckResult{Timestamp: checkTime}
+
+	// Метка без EXT-X-PROGRAM-DATE-TIME относится ко времени проверки
+	mockMetrics.On("SetAdMarkers", "test_stream", "720p.m3u8", 1, 0).Return().Once()
+	mockMetrics.On("SetLastAdCue", "test_stream", "720p.m3u8", checkTime).Return().Once()
+	c.recordAdMarkers(stream, "720p.m3u8", parseAdMarkers([]byte("#EXTM3U\n#EXT-X-CUE-OUT\n")), result)
+
+	// Без меток время последней метки не обновляется
+	mockMetrics.On("SetAdMarkers", "test_stream", "480p.m3u8", 0, 0).Return().Once()
+	c.recordAdMarkers(stream, "480p.m3u8", adMarkers{}, result)
+
+	require.NotNil(t, result.AdMarkers)
+	assert.Equal(t, 1, result.AdMarkers.Breaks)
+	assert.Equal(t, checkTime, result.AdMarkers.LastCue)
+	mockMetrics.AssertExpectations(t)
+	mockMetrics.AssertNotCalled(t, "SetLastAdCue", "test_stream", "480p.m3u8", mock.Anything)
+}
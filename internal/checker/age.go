@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// ageTracker запоминает между проверками заголовок Age ответов медиаплейлистов
+type ageTracker struct {
+	mu    sync.Mutex
+	state map[string]time.Duration
+}
+
+func newAgeTracker() *ageTracker {
+	return &ageTracker{state: make(map[string]time.Duration)}
+}
+
+// Observe сравнивает Age с предыдущей проверкой и сообщает, что он вырос:
+// CDN отдает тот же закешированный ответ, что и раньше
+func (a *ageTracker) Observe(stream, playlistURL string, age time.Duration) bool {
+	key := stream + "|" + playlistURL
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prev, ok := a.state[key]
+	a.state[key] = age
+	return ok && age > prev
+}
+
+// parseAge возвращает значение заголовка Age (RFC 9111: целое число секунд)
+func parseAge(headers http.Header) (time.Duration, bool) {
+	value := headers.Get("Age")
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// recordAge публикует Age ответа медиаплейлиста и отмечает результат зависшим, если Age
+// растет между проверками и превышает порог staleness: CDN отдает устаревшую копию
+// live-плейлиста, даже если она разбирается без ошибок
+func (c *StreamChecker) recordAge(
+	stream models.StreamConfig,
+	variant string,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	headers http.Header,
+	result *models.CheckResult,
+) {
+	age, ok := parseAge(headers)
+	if !ok {
+		return
+	}
+	c.metrics.SetPlaylistAge(stream.Name, variant, age)
+	if isVOD(media) || media.TargetDuration <= 0 {
+		return
+	}
+
+	growing := c.ages.Observe(stream.Name, playlistURL, age)
+	limit := time.Duration(media.TargetDuration * staleTargetFactor * float64(time.Second))
+	if !growing || age <= limit {
+		return
+	}
+
+	if !result.Stale {
+		result.Stale = true
+		result.StaleCause = models.StaleCauseCachedPlaylist
+	}
+	if result.Error == nil {
+		result.Error = &models.CheckError{
+			Type: models.ErrPlaylistStale,
+			Message: fmt.Sprintf("playlist %s is served from cache: Age %s keeps growing",
+				playlistURL, age),
+		}
+	}
+}
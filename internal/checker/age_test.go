@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "0", want: 0, wantOK: true},
+		{value: "42", want: 42 * time.Second, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "1.5", wantOK: false},
+	}
+	for _, tt := range tests {
+		headers := http.Header{}
+		if tt.value != "" {
+			headers.Set("Age", tt.value)
+		}
+		age, ok := parseAge(headers)
+		assert.Equal(t, tt.wantOK, ok, tt.value)
+		assert.Equal(t, tt.want, age, tt.value)
+	}
+}
+
+func TestStreamChecker_RecordAge(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	mockMetrics.On("SetPlaylistAge", "test_stream", "720p.m3u8", time.Duration(0)).Return()
+	mockMetrics.On("SetPlaylistAge", "test_stream", "720p.m3u8", 5*time.Second).Return()
+	mockMetrics.On("SetPlaylistAge", "test_stream", "720p.m3u8", 12*time.Second).Return()
+	mockMetrics.On("SetPlaylistAge", "test_stream", "720p.m3u8", 20*time.Second).Return()
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+	const url = "http://a/720p.m3u8"
+	// EXT-X-TARGETDURATION:4, порог 6s
+	media := windowPlaylist(t, 100, "a.ts", "b.ts")
+
+	check := func(age string) *models.CheckResult {
+		result := &models.CheckResult{Success: true}
+		c.recordAge(stream, "720p.m3u8", url, media, http.Header{"Age": {age}}, result)
+		return result
+	}
+
+	// Первая проверка: растущий Age еще не известен
+	assert.False(t, check("12").Stale)
+	// Age сбросился: CDN получил свежий плейлист
+	assert.False(t, check("0").Stale)
+	// Age растет, но не превышает порог
+	assert.False(t, check("5").Stale)
+
+	result := check("12")
+	assert.True(t, result.Stale)
+	assert.Equal(t, models.StaleCauseCachedPlaylist, result.StaleCause)
+	if assert.NotNil(t, result.Error) {
+		assert.Equal(t, models.ErrPlaylistStale, result.Error.Type)
+	}
+
+	// Причина, найденная раньше, не перезаписывается
+	result = &models.CheckResult{Stale: true, StaleCause: models.StaleCauseUnchanged}
+	c.recordAge(stream, "720p.m3u8", url, media, http.Header{"Age": {"20"}}, result)
+	assert.Equal(t, models.StaleCauseUnchanged, result.StaleCause)
+
+	// Без заголовка Age метрика не обновляется
+	c.recordAge(stream, "720p.m3u8", url, media, http.Header{}, &models.CheckResult{})
+	mockMetrics.AssertNumberOfCalls(t, "SetPlaylistAge", 5)
+}
@@ -0,0 +1,58 @@
+package checker
+
+import (
+	"sync"
+	"time"
+)
+
+// budgetTracker учитывает объем загруженных данных по стримам за текущие сутки (UTC)
+type budgetTracker struct {
+	mu    sync.Mutex
+	usage map[string]*budgetUsage
+	now   func() time.Time
+}
+
+type budgetUsage struct {
+	day   string
+	bytes int64
+}
+
+func newBudgetTracker() *budgetTracker {
+	return &budgetTracker{
+		usage: make(map[string]*budgetUsage),
+		now:   time.Now,
+	}
+}
+
+// get возвращает счетчик стрима, сбрасывая его при смене суток
+func (b *budgetTracker) get(name string) *budgetUsage {
+	day := b.now().UTC().Format(time.DateOnly)
+	u, ok := b.usage[name]
+	if !ok || u.day != day {
+		u = &budgetUsage{day: day}
+		b.usage[name] = u
+	}
+	return u
+}
+
+// Add добавляет загруженные байты и возвращает суточную сумму
+func (b *budgetTracker) Add(name string, bytes int64) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u := b.get(name)
+	u.bytes += bytes
+	return u.bytes
+}
+
+// Exceeded сообщает, исчерпан ли суточный лимит стрима
+func (b *budgetTracker) Exceeded(name string, limit int64) bool {
+	if limit <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.get(name).bytes >= limit
+}
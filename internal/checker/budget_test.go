@@ -0,0 +1,29 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetTracker(t *testing.T) {
+	now := time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC)
+	tracker := newBudgetTracker()
+	tracker.now = func() time.Time { return now }
+
+	assert.False(t, tracker.Exceeded("stream", 0), "zero limit means no budget")
+	assert.False(t, tracker.Exceeded("stream", 100))
+
+	assert.Equal(t, int64(60), tracker.Add("stream", 60))
+	assert.False(t, tracker.Exceeded("stream", 100))
+
+	assert.Equal(t, int64(120), tracker.Add("stream", 60))
+	assert.True(t, tracker.Exceeded("stream", 100))
+	assert.False(t, tracker.Exceeded("other", 100), "budgets are tracked per stream")
+
+	// На следующие сутки счетчик сбрасывается
+	now = now.Add(2 * time.Hour)
+	assert.False(t, tracker.Exceeded("stream", 100))
+	assert.Equal(t, int64(10), tracker.Add("stream", 10))
+}
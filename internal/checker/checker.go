@@ -3,30 +3,65 @@ package checker
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/internal/conformance"
 	"github.com/iudanet/hls_exporter/pkg/models"
 	"go.uber.org/zap"
 )
 
 var (
 	_ models.Checker          = (*StreamChecker)(nil)
+	_ models.TagInventory     = (*StreamChecker)(nil)
 	_ models.Validator        = (*HLSValidator)(nil)
 	_ models.SegmentValidator = (*BasicSegmentValidator)(nil)
 )
 
 type StreamChecker struct {
-	client    models.HTTPClient
-	validator models.Validator
-	metrics   models.MetricsCollector
-	workers   int
-	wg        sync.WaitGroup
-	logger    *zap.Logger
-	stopCh    chan struct{}
+	client       models.HTTPClient
+	validator    models.Validator
+	metrics      models.MetricsCollector
+	workers      int
+	maxPerCheck  int
+	sample       int
+	retries      int
+	retryDelay   time.Duration
+	tracing      bool
+	transactions models.TransactionStore
+	maxBodyBytes int
+	wg           sync.WaitGroup
+	logger       *zap.Logger
+	stopCh       chan struct{}
+	jobs         chan func()
+	running      atomic.Bool
+	budget       *budgetTracker
+	staleness    *stalenessTracker
+	sizeTrend    *sizeTrendTracker
+	looping      *loopTracker
+	sequences    *sequenceTracker
+	ages         *ageTracker
+	notFound     *notFoundTracker
+	userAgents   *userAgentRotation
+	tagInventory *tagInventory
+	pids         *pidTracker
+	markers      *discontinuityTracker
+	events       *eventTracker
+	keys         *keyCache
+	tasks        taskTracker
+	onLeak       func(stream string, leaked int64)
+	external     map[string]models.ExternalValidator
+	now          func() time.Time
 }
 
 func NewStreamChecker(
@@ -37,12 +72,27 @@ func NewStreamChecker(
 ) *StreamChecker {
 	logger, _ := zap.NewProduction() // Можно передавать logger как параметр
 	return &StreamChecker{
-		client:    client,
-		validator: validator,
-		metrics:   metrics,
-		workers:   workers,
-		logger:    logger,
-		stopCh:    make(chan struct{}),
+		client:       client,
+		validator:    validator,
+		metrics:      metrics,
+		workers:      workers,
+		logger:       logger,
+		stopCh:       make(chan struct{}),
+		jobs:         make(chan func()),
+		budget:       newBudgetTracker(),
+		staleness:    newStalenessTracker(),
+		sizeTrend:    newSizeTrendTracker(),
+		looping:      newLoopTracker(),
+		sequences:    newSequenceTracker(),
+		ages:         newAgeTracker(),
+		notFound:     newNotFoundTracker(),
+		userAgents:   newUserAgentRotation(),
+		tagInventory: newTagInventory(),
+		pids:         newPIDTracker(),
+		markers:      newDiscontinuityTracker(),
+		events:       newEventTracker(),
+		keys:         newKeyCache(),
+		now:          time.Now,
 	}
 }
 func (c *StreamChecker) StopCh() <-chan struct{} {
@@ -54,6 +104,7 @@ func (c *StreamChecker) Start() error {
 		c.wg.Add(1)
 		go c.worker()
 	}
+	c.running.Store(true)
 	return nil
 }
 
@@ -61,12 +112,19 @@ func (c *StreamChecker) handleError(
 	result *models.CheckResult,
 	err error,
 	errType models.ErrorType,
+) error {
+	return c.handleStatusError(result, err, errType, 0)
+}
+
+// handleStatusError завершает проверку ошибкой запроса с кодом ответа HTTP (0 - без ответа)
+func (c *StreamChecker) handleStatusError(
+	result *models.CheckResult,
+	err error,
+	errType models.ErrorType,
+	statusCode int,
 ) error {
 	result.Success = false
-	result.Error = &models.CheckError{
-		Type:    errType,
-		Message: err.Error(),
-	}
+	result.Error = newCheckError(errType, err, statusCode)
 	return err
 }
 
@@ -76,6 +134,7 @@ func (c *StreamChecker) Stop() error {
 		// Channel is already closed
 		return nil
 	default:
+		c.running.Store(false)
 		close(c.stopCh)
 	}
 	c.wg.Wait()
@@ -85,28 +144,158 @@ func (c *StreamChecker) Check(ctx context.Context, stream models.StreamConfig) (
 	result := c.initResult(stream)
 	start := result.Timestamp
 
-	// Обработка мастер-плейлиста
-	masterPlaylist, masterResp, err := c.checkMasterPlaylist(ctx, stream.URL, result)
+	// Идентификаторы проверки и трассировки, заголовки, авторизация, параметры TLS и подмена
+	// адресов стрима передаются HTTP-клиенту через контекст
+	ctx = models.WithCheckID(ctx, result.CheckID)
+	if c.tracing {
+		result.TraceID = newTraceID()
+		ctx = models.WithTraceID(ctx, result.TraceID)
+	}
+	ctx, saveTransactions := c.withTransactionLog(ctx, result)
+	defer saveTransactions()
+	auth := stream.RequestAuth()
+	// User-Agent плееров чередуются между проверками, результат учитывается по каждому
+	if len(stream.UserAgents) > 0 {
+		result.UserAgent = c.userAgents.Next(stream.Name, stream.UserAgents)
+		auth.UserAgent = resolveUserAgent(result.UserAgent)
+		defer func() { c.metrics.SetUserAgentUp(stream.Name, result.UserAgent, result.Success) }()
+	}
+	if !auth.IsZero() {
+		ctx = models.WithRequestAuth(ctx, auth)
+	}
+	if stream.TLS != nil {
+		ctx = models.WithStreamTLS(ctx, stream.TLS)
+	}
+	if len(stream.Resolve) > 0 {
+		ctx = models.WithResolve(ctx, stream.Resolve)
+	}
+	if stream.RangeBytes > 0 {
+		ctx = models.WithSegmentRange(ctx, stream.RangeBytes)
+	}
+	// Длительности этапов попадают в результат при любом исходе проверки
+	ctx, stages := withStageTimer(ctx)
+	defer func() {
+		result.Stages = stages.snapshot()
+		c.recordStages(stream.Name, result)
+	}()
+
+	// DASH-версия канала загружается параллельно и сравнивается с итоговым
+	// результатом HLS, в том числе неуспешным
+	if stream.DASHURL != "" {
+		manifest := c.fetchDASH(ctx, stream.DASHURL)
+		defer func() { c.recordDASH(stream, <-manifest, result) }()
+	}
+
+	// При исчерпании суточного лимита трафика проверяем сегменты только по заголовкам
+	if c.budget.Exceeded(stream.Name, stream.DailyByteBudget) {
+		stream.ValidateContent = false
+		result.BudgetExceeded = true
+	}
+
+	// Загрузка корневого плейлиста: мастер или сразу медиаплейлист
+	playlist, listType, rootResp, err := c.fetchRootPlaylist(ctx, stream.URL, result)
 	if err != nil {
-		result.Duration = time.Since(start)
-		// Обновляем метрики после установки всех полей
-		c.updateMetrics(stream.Name, result)
-		return result, err
+		return c.abortCheck(stream, result, err)
+	}
+
+	externalStart := time.Now()
+	err = c.validateExternalPlaylist(stream, stream.URL, rootResp.Body)
+	observeStage(ctx, models.StageValidation, externalStart)
+	if err != nil {
+		return c.abortCheck(stream, result, c.handleError(result, err, models.ErrPluginValidate))
 	}
 
+	result.PlaylistResponseTime = rootResp.Duration
+	c.metrics.RecordResponseTime(stream.Name, rootPhase(listType), rootResp.Duration.Seconds(), result.TraceID)
+	result.Conformance = c.checkConformance(stream, stream.URL, rootResp.Body)
+	result.ParseIssues = c.checkParseIssues(stream, stream.URL, rootResp.Body)
+	result.UnknownTags = conformance.UnknownTags(rootResp.Body)
+
+	// Все задачи пула, запущенные проверкой, должны завершиться до возврата из Check
+	ctx, tracker := withTaskTracker(ctx)
+	defer c.assertTasksFinished(stream.Name, tracker)
+
 	// Проверка сегментов
-	segResults := c.checkVariants(ctx, masterPlaylist, stream)
-	result = c.updateResultStatus(result, masterPlaylist, masterResp, segResults)
+	var (
+		segResults    models.SegmentResults
+		variantsCount int
+	)
+	switch listType {
+	case m3u8.MASTER:
+		masterPlaylist := playlist.(*m3u8.MasterPlaylist)
+		variantsCount = len(masterPlaylist.Variants)
+		if err := checkLadder(masterPlaylist, stream); err != nil {
+			return c.abortCheck(stream, result, c.handleError(result, err, models.ErrLadderMismatch))
+		}
+		variants, err := filterVariants(masterPlaylist.Variants, stream.VariantFilter)
+		if err != nil {
+			return c.abortCheck(stream, result, c.handleError(result, err, models.ErrVariantFilter))
+		}
+		segResults = c.checkVariants(ctx, variants, stream, result)
+		renditionsStart := time.Now()
+		result.Renditions = c.checkRenditions(ctx, masterPlaylist, stream, result)
+		observeStage(ctx, models.StageVariantFetch, renditionsStart)
+		c.checkSessionData(ctx, stream, rootResp.Body, result)
+	case m3u8.MEDIA:
+		// Поток без мастер-плейлиста рассматриваем как единственный вариант
+		variantsCount = 1
+		mediaPlaylist := playlist.(*m3u8.MediaPlaylist)
+		c.recordLiveEdge(stream, mediaVariantLabel, mediaPlaylist, result)
+		c.recordPlaylistType(stream, stream.URL, mediaPlaylist, result)
+		c.recordStaleness(stream, stream.URL, mediaPlaylist, result)
+		c.recordAge(stream, mediaVariantLabel, stream.URL, mediaPlaylist, rootResp.Headers, result)
+		c.recordClockSkew(stream, mediaVariantLabel, mediaPlaylist, rootResp.Headers, result)
+		c.recordLooping(stream, stream.URL, mediaPlaylist, result)
+		c.recordMarkers(stream, mediaVariantLabel, stream.URL, mediaPlaylist, countMarkers(rootResp.Body), result)
+		c.recordSequence(stream, mediaVariantLabel, stream.URL, mediaPlaylist, result)
+		c.recordEventGrowth(stream, mediaVariantLabel, stream.URL, mediaPlaylist, result)
+		if stream.AdMarkers {
+			c.recordAdMarkers(stream, mediaVariantLabel, parseAdMarkers(rootResp.Body), result)
+		}
+		segResults = c.checkMediaSegments(ctx, stream.URL, mediaPlaylist, stream, result)
+	}
+	c.recordUnknownTags(stream.Name, result.UnknownTags)
+	result = c.updateResultStatus(result, variantsCount, rootResp, segResults, stream.CheckMode)
+	c.evaluateSLO(stream, result)
 	result.Duration = time.Since(start)
-
-	// Устанавливаем статус до обновления метрик
+	result.QueueWait = tracker.queueWait()
+	c.accountTraffic(stream, result)
+	c.recordTransportErrors(stream, result)
+	c.metrics.SetPlaylistStale(stream.Name, result.Stale)
+	c.metrics.SetPlaylistType(stream.Name, result.StreamStatus.IsLive, result.StreamStatus.TotalDuration)
+	c.metrics.SetContentLooping(stream.Name, slices.Contains(result.Warnings, models.WarningContentLooping))
+
+	// Устанавливаем статус до обновления метрик.
+	// Ошибки плейлистов - первопричина, они приоритетнее ошибок сегментов.
+	if err := parseIssuesError(stream, result.ParseIssues); err != nil {
+		c.handleError(result, err, models.ErrPlaylistParse)
+		c.updateMetrics(stream.Name, result)
+		return result, err
+	}
+	if result.Stale {
+		result.Success = false
+		c.updateMetrics(stream.Name, result)
+		return result, staleError(result)
+	}
+	if stream.ExpectLive && !result.StreamStatus.IsLive {
+		result.Success = false
+		c.updateMetrics(stream.Name, result)
+		return result, fmt.Errorf("unexpected VOD: %s", result.Error.Message)
+	}
 	if segResults.Failed > 0 {
 		result.Success = false
 		errMsg := fmt.Sprintf("%d of %d segments failed validation", segResults.Failed, segResults.Total)
 		result.Error = &models.CheckError{
 			Type:    models.ErrSegmentValidate,
 			Message: errMsg,
+			Cause:   segmentFailureCause(segResults.Details),
+		}
+		// Код ответа и возможность повтора - по первому сегменту, не ответившему 200
+		if segErr := segmentStatusError(segResults.Details); segErr != nil {
+			result.Error.StatusCode = segErr.StatusCode
+			result.Error.Retryable = segErr.Retryable
 		}
+		result.Error.RetryAfter = segmentRetryAfter(segResults.Details)
 		c.updateMetrics(stream.Name, result)
 		return result, fmt.Errorf("segment validation failed: %s", errMsg)
 	}
@@ -117,159 +306,444 @@ func (c *StreamChecker) Check(ctx context.Context, stream models.StreamConfig) (
 	return result, nil
 }
 
+// abortCheck завершает проверку, прерванную ошибкой до проверки сегментов
+func (c *StreamChecker) abortCheck(
+	stream models.StreamConfig,
+	result *models.CheckResult,
+	err error,
+) (*models.CheckResult, error) {
+	result.Duration = time.Since(result.Timestamp)
+	c.accountTraffic(stream, result)
+	// Обновляем метрики после установки всех полей
+	c.updateMetrics(stream.Name, result)
+	return result, err
+}
+
 func (c *StreamChecker) initResult(stream models.StreamConfig) *models.CheckResult {
 	return &models.CheckResult{
+		CheckID:    newCheckID(),
 		Timestamp:  time.Now(),
 		StreamName: stream.Name,
 		Success:    false,
+		// Стрим считается live, пока не найден VOD-медиаплейлист
+		StreamStatus: models.StreamStatus{IsLive: true},
 	}
 }
 
-func (c *StreamChecker) checkMasterPlaylist(ctx context.Context, url string, result *models.CheckResult) (*m3u8.MasterPlaylist, *models.PlaylistResponse, error) {
-	masterResp, err := c.client.GetPlaylist(ctx, url)
+// newCheckID генерирует случайный идентификатор проверки из 16 hex-символов
+func newCheckID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// fetchRootPlaylist загружает плейлист по URL стрима и определяет его тип
+func (c *StreamChecker) fetchRootPlaylist(
+	ctx context.Context,
+	url string,
+	result *models.CheckResult,
+) (m3u8.Playlist, m3u8.ListType, *models.PlaylistResponse, error) {
+	fetchStart := time.Now()
+	var resp *models.PlaylistResponse
+	err := c.retry(ctx, models.ErrPlaylistDownload, func() (int, time.Duration, error) {
+		var err error
+		resp, err = c.client.GetPlaylist(ctx, url)
+		if err != nil && resp != nil {
+			// Тип плейлиста без тела неизвестен, ошибка учитывается как ошибка мастер-плейлиста
+			c.recordResponse(result.StreamName, models.ResponseTimeMaster, resp.StatusCode, resp.RetryAfter)
+			return resp.StatusCode, resp.RetryAfter, err
+		}
+		return 0, 0, err
+	})
+	observeStage(ctx, models.StageMasterFetch, fetchStart)
 	if err != nil {
-		return nil, nil, c.handleError(result, err, models.ErrPlaylistDownload)
+		var code int
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		err = c.handleStatusError(result, err, networkErrorType(err, models.ErrPlaylistDownload), code)
+		if resp != nil {
+			result.Error.RetryAfter = resp.RetryAfter
+		}
+		return nil, 0, nil, err
 	}
 
-	masterPlaylist, err := parseMasterPlaylist(masterResp.Body)
+	result.BytesDownloaded += int64(len(resp.Body))
+
+	playlist, listType, err := parsePlaylist(resp.Body)
+	c.metrics.RecordHTTPResponse(result.StreamName, rootPhase(listType), resp.StatusCode)
 	if err != nil {
-		return nil, nil, c.handleError(result, err, models.ErrPlaylistParse)
+		return nil, 0, nil, c.handleError(result, err, models.ErrPlaylistParse)
 	}
 
-	if err := c.validator.ValidateMaster(masterPlaylist); err != nil {
-		return nil, nil, c.handleError(result, err, models.ErrPlaylistParse)
+	validateStart := time.Now()
+	switch listType {
+	case m3u8.MASTER:
+		err = c.validator.ValidateMaster(playlist.(*m3u8.MasterPlaylist))
+	case m3u8.MEDIA:
+		err = c.validator.ValidateMedia(playlist.(*m3u8.MediaPlaylist))
+	}
+	observeStage(ctx, models.StageValidation, validateStart)
+	if err != nil {
+		c.recordSpecViolation(result.StreamName, err)
+		return nil, 0, nil, c.handleError(result, err, playlistErrorType(err))
 	}
 
-	return masterPlaylist, masterResp, nil
+	return playlist, listType, resp, nil
+}
+
+// rootPhase возвращает тип запроса корневого плейлиста: медиаплейлист стрима без
+// мастер-плейлиста учитывается как плейлист варианта
+func rootPhase(listType m3u8.ListType) string {
+	if listType == m3u8.MEDIA {
+		return models.ResponseTimeVariant
+	}
+	return models.ResponseTimeMaster
 }
 
-func (c *StreamChecker) updateResultStatus(result *models.CheckResult, masterPlaylist *m3u8.MasterPlaylist, masterResp *models.PlaylistResponse, segResults models.SegmentResults) *models.CheckResult {
+// updateResultStatus заполняет состояние стрима. В режимах без загрузки сегментов
+// число сегментов берется из окон медиаплейлистов, иначе - число проверенных сегментов.
+func (c *StreamChecker) updateResultStatus(
+	result *models.CheckResult,
+	variantsCount int,
+	rootResp *models.PlaylistResponse,
+	segResults models.SegmentResults,
+	mode string,
+) *models.CheckResult {
 	var lastModified time.Time
-	if lm := masterResp.Headers.Get("Last-Modified"); lm != "" {
+	if lm := rootResp.Headers.Get("Last-Modified"); lm != "" {
 		if t, err := time.Parse(time.RFC1123, lm); err == nil {
 			lastModified = t
 		}
 	}
 
-	result.Segments = segResults
-	result.StreamStatus = models.StreamStatus{
-		IsLive:        true,
-		VariantsCount: len(masterPlaylist.Variants),
-		SegmentsCount: segResults.Checked,
-		LastModified:  lastModified,
+	segmentsCount := segResults.Checked
+	if models.SkipsSegments(mode) {
+		segmentsCount = segResults.Listed
 	}
 
+	result.Segments = segResults
+	result.StreamStatus.VariantsCount = variantsCount
+	result.StreamStatus.SegmentsCount = segmentsCount
+	result.StreamStatus.LastModified = lastModified
+
 	return result
 }
 
 func (c *StreamChecker) checkVariants(
 	ctx context.Context,
-	master *m3u8.MasterPlaylist,
+	variants []*m3u8.Variant,
 	cfg models.StreamConfig,
+	result *models.CheckResult,
 ) models.SegmentResults {
-	results := models.SegmentResults{}
-	baseURL := cfg.URL
-
-	var wg sync.WaitGroup
-	resultCh := make(chan models.SegmentCheck, len(master.Variants)*10) // Буферизованный канал для результатов
+	// Этап 1: загрузка медиаплейлистов вариантов
+	fetched := make([]variantPlaylist, len(variants))
+	variantURLs := make([]string, len(variants))
+	tasks := make([]func(), 0, len(variants))
+	for i, variant := range variants {
+		variantURLs[i] = resolveURL(cfg.URL, variant.URI)
+		tasks = append(tasks, func() {
+			fetched[i] = c.fetchVariantPlaylist(ctx, cfg, variant, variantURLs[i], result)
+		})
+	}
+	fetchStart := time.Now()
+	c.runTasks(ctx, tasks)
+	observeStage(ctx, models.StageVariantFetch, fetchStart)
+	playlists := make([]*m3u8.MediaPlaylist, len(variants))
+	for i, f := range fetched {
+		playlists[i] = f.playlist
+		result.PlaylistResponseTime = max(result.PlaylistResponseTime, f.responseTime)
+		result.Conformance = append(result.Conformance, f.conformance...)
+		result.ParseIssues = append(result.ParseIssues, f.parseIssues...)
+		result.UnknownTags = mergeTags(result.UnknownTags, f.unknownTags...)
+	}
 
-	for _, variant := range master.Variants {
-		if variant == nil {
-			continue
+	// Этап 2: проверка выбранных сегментов всех вариантов
+	var (
+		segments []*m3u8.MediaSegment
+		owners   []*m3u8.Variant
+		listed   int
+	)
+	for i, playlist := range playlists {
+		if playlist != nil {
+			c.recordMarkers(cfg, variantLabel(variants[i].URI), variantURLs[i], playlist, fetched[i].markers, result)
+			c.recordSequence(cfg, variantLabel(variants[i].URI), variantURLs[i], playlist, result)
+			c.recordEventGrowth(cfg, variantLabel(variants[i].URI), variantURLs[i], playlist, result)
+			if cfg.AdMarkers {
+				c.recordAdMarkers(cfg, variantLabel(variants[i].URI), fetched[i].ads, result)
+			}
+			listed += int(playlist.Count())
+			c.recordLiveEdge(cfg, variantLabel(variants[i].URI), playlist, result)
+			c.recordPlaylistType(cfg, variantURLs[i], playlist, result)
+			c.recordStaleness(cfg, variantURLs[i], playlist, result)
+			c.recordAge(cfg, variantLabel(variants[i].URI), variantURLs[i], playlist, fetched[i].headers, result)
+			c.recordClockSkew(cfg, variantLabel(variants[i].URI), playlist, fetched[i].headers, result)
+			c.recordLooping(cfg, variantURLs[i], playlist, result)
+			selected := c.selectPlaylistSegments(variantURLs[i], playlist, cfg)
+			segments = append(segments, selected...)
+			for range selected {
+				owners = append(owners, variants[i])
+			}
 		}
+	}
 
-		variantURL := resolveURL(baseURL, variant.URI)
-		wg.Add(1)
-		go func(variantURL string) {
-			defer wg.Done()
-			variantResp, err := c.client.GetPlaylist(ctx, variantURL)
-			if err != nil {
-				c.logger.Error("Failed to get variant playlist",
-					zap.String("uri", variant.URI),
-					zap.String("url", variantURL),
-					zap.Error(err))
-				return
-			}
+	segResults := c.checkSegments(ctx, segments, cfg)
+	segResults.Listed = listed
+	c.recordVariantSegments(cfg, owners, segResults)
+	c.recordVariantBitrates(cfg, variants, variantURLs, owners, segments, segResults, result)
+	for i, variant := range variants {
+		_, checks := variantChecks(variant, owners, segments, segResults)
+		c.recordPIDChanges(cfg, variantURLs[i], checks, result)
+		c.recordCodecMismatch(cfg, variant, variantURLs[i], checks, result)
+	}
+	return segResults
+}
 
-			mediaPlaylist, err := parseMediaPlaylist(variantResp.Body)
-			if err != nil {
-				c.logger.Error("Failed to parse media playlist",
-					zap.String("uri", variant.URI),
-					zap.Error(err))
-				return
-			}
+// variantPlaylist результат загрузки медиаплейлиста варианта
+type variantPlaylist struct {
+	// playlist nil, если плейлист не удалось загрузить или он не прошел валидацию
+	playlist    *m3u8.MediaPlaylist
+	conformance []models.ConformanceViolation
+	parseIssues []models.ParseIssue
+	unknownTags []string
+	markers     playlistMarkers
+	ads         adMarkers
+	// Время ответа на запрос плейлиста
+	responseTime time.Duration
+	headers      http.Header
+}
 
-			if err := c.validator.ValidateMedia(mediaPlaylist); err != nil {
-				c.logger.Error("Failed to validate media playlist",
-					zap.String("uri", variant.URI),
-					zap.Error(err))
-				return
-			}
+// fetchVariantPlaylist загружает и валидирует медиаплейлист варианта.
+// Нарушения RFC 8216 и проблемы разбора возвращаются и при ошибке валидации.
+func (c *StreamChecker) fetchVariantPlaylist(
+	ctx context.Context,
+	cfg models.StreamConfig,
+	variant *m3u8.Variant,
+	variantURL string,
+	result *models.CheckResult,
+) variantPlaylist {
+	var fetched variantPlaylist
+	uri := variant.URI
+	variantResp, err := c.client.GetPlaylist(ctx, variantURL)
+	if variantResp != nil {
+		c.recordResponse(cfg.Name, models.ResponseTimeVariant, variantResp.StatusCode, variantResp.RetryAfter)
+	}
+	if err != nil {
+		c.logger.Error("Failed to get variant playlist",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
+			zap.String("uri", uri),
+			zap.String("url", variantURL),
+			zap.Error(err))
+		return fetched
+	}
+	atomic.AddInt64(&result.BytesDownloaded, int64(len(variantResp.Body)))
+	fetched.responseTime = variantResp.Duration
+	c.metrics.RecordResponseTime(cfg.Name, models.ResponseTimeVariant, variantResp.Duration.Seconds(),
+		models.TraceIDFrom(ctx))
+	fetched.headers = variantResp.Headers
+	bandwidth, resolution := variantLabels(variant)
+	c.metrics.RecordVariantResponseTime(cfg.Name, bandwidth, resolution, variantResp.Duration.Seconds(),
+		models.TraceIDFrom(ctx))
+	fetched.conformance = c.checkConformance(cfg, variantURL, variantResp.Body)
+	fetched.parseIssues = c.checkParseIssues(cfg, variantURL, variantResp.Body)
+	fetched.unknownTags = conformance.UnknownTags(variantResp.Body)
+
+	mediaPlaylist, err := parseMediaPlaylist(variantResp.Body)
+	if err != nil {
+		c.logger.Error("Failed to parse media playlist",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
+			zap.String("uri", uri),
+			zap.Error(err))
+		return fetched
+	}
 
-			for _, seg := range mediaPlaylist.Segments {
-				if seg != nil {
-					seg.URI = resolveURL(variantURL, seg.URI)
-				}
-			}
+	validateStart := time.Now()
+	err = c.validator.ValidateMedia(mediaPlaylist)
+	observeStage(ctx, models.StageValidation, validateStart)
+	if err != nil {
+		c.logger.Error("Failed to validate media playlist",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
+			zap.String("uri", uri),
+			zap.Error(err))
+		if errType := playlistErrorType(err); errType != models.ErrPlaylistParse {
+			c.metrics.RecordError(result.StreamName, string(errType))
+		}
+		c.recordSpecViolation(result.StreamName, err)
+		return fetched
+	}
 
-			segments := c.selectSegments(mediaPlaylist, cfg.CheckMode)
-			results.Total += len(segments)
+	externalStart := time.Now()
+	err = c.validateExternalPlaylist(cfg, variantURL, variantResp.Body)
+	observeStage(ctx, models.StageValidation, externalStart)
+	if err != nil {
+		c.logger.Error("External validator rejected media playlist",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
+			zap.String("uri", uri),
+			zap.Error(err))
+		c.metrics.RecordError(result.StreamName, string(models.ErrPluginValidate))
+		return fetched
+	}
 
-			for _, seg := range segments {
-				if seg == nil {
-					continue
-				}
+	fetched.playlist = mediaPlaylist
+	fetched.markers = countMarkers(variantResp.Body)
+	if cfg.AdMarkers {
+		fetched.ads = parseAdMarkers(variantResp.Body)
+	}
+	return fetched
+}
 
-				wg.Add(1)
-				go func(seg *m3u8.MediaSegment) {
-					defer wg.Done()
-					segCheck := c.checkSegment(ctx, seg, cfg)
-					resultCh <- segCheck
-				}(seg)
-			}
-		}(variantURL)
+// checkMediaSegments проверяет выбранные сегменты одного медиаплейлиста
+func (c *StreamChecker) checkMediaSegments(
+	ctx context.Context,
+	playlistURL string,
+	mediaPlaylist *m3u8.MediaPlaylist,
+	cfg models.StreamConfig,
+	result *models.CheckResult,
+) models.SegmentResults {
+	segments := c.selectPlaylistSegments(playlistURL, mediaPlaylist, cfg)
+	segResults := c.checkSegments(ctx, segments, cfg)
+	segResults.Listed = int(mediaPlaylist.Count())
+	c.recordPIDChanges(cfg, playlistURL, segResults.Details, result)
+	// BANDWIDTH без мастер-плейлиста не заявлен, публикуем только битрейт стрима
+	if bitrate, ok := measuredBitrate(segments, segResults.Details); ok {
+		result.Bitrate = bitrate
+		c.recordSizeAnomaly(cfg, playlistURL, "", "", bitrate)
 	}
+	return segResults
+}
 
-	// Закрываем канал после завершения всех горутин
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
+// selectPlaylistSegments приводит URI сегментов к абсолютным и выбирает сегменты для проверки
+func (c *StreamChecker) selectPlaylistSegments(
+	playlistURL string,
+	mediaPlaylist *m3u8.MediaPlaylist,
+	cfg models.StreamConfig,
+) []*m3u8.MediaSegment {
+	for _, seg := range mediaPlaylist.Segments {
+		if seg != nil {
+			seg.URI = resolveURL(playlistURL, seg.URI)
+		}
+	}
+	inheritKeys(playlistURL, mediaPlaylist.Segments)
+
+	return c.selectSegments(mediaPlaylist, cfg.CheckMode, c.segmentSampleSize(cfg))
+}
 
-	// Собираем результаты из канала
-	for segCheck := range resultCh {
+// checkSegments проверяет сегменты в пуле воркеров
+func (c *StreamChecker) checkSegments(
+	ctx context.Context,
+	segments []*m3u8.MediaSegment,
+	cfg models.StreamConfig,
+) models.SegmentResults {
+	checks := make([]models.SegmentCheck, len(segments))
+	tasks := make([]func(), 0, len(segments))
+	for i, seg := range segments {
+		tasks = append(tasks, func() {
+			checks[i] = c.checkSegment(ctx, seg, cfg)
+		})
+	}
+	segmentsStart := time.Now()
+	c.runTasks(ctx, tasks)
+	observeStage(ctx, models.StageSegmentDownload, segmentsStart)
+
+	results := models.SegmentResults{Total: len(segments)}
+	for i, segCheck := range checks {
 		results.Checked++
 		results.Details = append(results.Details, segCheck)
 		if !segCheck.Success {
 			results.Failed++
 		}
+		// Распределения выявляют ошибки настройки энкодера: слишком короткие
+		// сегменты, плавающие длительности
+		if segments[i].Duration > 0 {
+			c.metrics.RecordSegmentDuration(cfg.Name, segments[i].Duration)
+		}
+		if segCheck.Success && segCheck.Size > 0 {
+			c.metrics.RecordSegmentSize(cfg.Name, segCheck.Size)
+		}
+		// Загрузка не быстрее воспроизведения означает остановки у зрителей
+		if segCheck.Success && segCheck.DownloadRatio > 0 {
+			c.metrics.RecordSegmentDownloadRatio(cfg.Name, segCheck.DownloadRatio)
+		}
 	}
 
 	return results
 }
+
 func (c *StreamChecker) checkSegment(ctx context.Context, segment *m3u8.MediaSegment, cfg models.StreamConfig) models.SegmentCheck {
 	check := models.SegmentCheck{
 		URL:     segment.URI,
 		Success: false,
 	}
 
-	resp, err := c.client.GetSegment(ctx, segment.URI, cfg.ValidateContent)
+	// Содержимое сегмента AES-128 анализируется после расшифровки
+	if cfg.ValidateContent && segment.Key != nil && segment.Key.Method == keyMethodAES128 {
+		key, err := c.segmentKey(ctx, segment, cfg)
+		if err != nil {
+			check.Error = newCheckError(models.ErrKeyFetch, err, 0)
+			check.Error.Cause = string(models.ErrKeyFetch)
+			return check
+		}
+		ctx = models.WithSegmentKey(ctx, key)
+	}
+
+	var resp *models.SegmentResponse
+	err := c.retry(ctx, models.ErrSegmentDownload, func() (int, time.Duration, error) {
+		var err error
+		resp, err = c.client.GetSegment(ctx, segment.URI, cfg.ValidateContent)
+		if resp == nil || resp.StatusCode == 0 {
+			return 0, 0, err
+		}
+		c.recordResponse(cfg.Name, models.ResponseTimeSegment, resp.StatusCode, resp.RetryAfter)
+		return resp.StatusCode, resp.RetryAfter, err
+	})
 	if err != nil {
 		c.logger.Debug("Segment download failed",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
 			zap.String("url", segment.URI),
 			zap.Error(err))
-		check.Error = &models.CheckError{
-			Type:    models.ErrSegmentDownload,
-			Message: err.Error(),
+		var code int
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		check.Error = newCheckError(networkErrorType(err, models.ErrSegmentDownload), err, code)
+		if resp != nil {
+			check.Error.RetryAfter = resp.RetryAfter
+		}
+		// Итоговая ошибка проверки сегментов - segment_validate, сетевая причина
+		// передается в причину недоступности стрима
+		if check.Error.Type != models.ErrSegmentDownload {
+			check.Error.Cause = string(check.Error.Type)
+		}
+		if isNotFound(resp) {
+			check.Error.Cause = c.diagnoseNotFound(ctx, segment, cfg)
 		}
 		return check
 	}
+	c.notFound.Forget(cfg.Name, segment.URI)
+	c.metrics.RecordResponseTime(cfg.Name, models.ResponseTimeSegment, resp.Duration.Seconds(),
+		models.TraceIDFrom(ctx))
 
 	// Add logging for successful download
 	c.logger.Debug("Segment downloaded successfully",
+		zap.String("check_id", models.CheckIDFrom(ctx)),
 		zap.String("url", segment.URI),
 		zap.Int64("size", resp.Size))
 
+	check.Size = resp.Size
+
+	// HEAD-запрос не передает тело, трафик учитываем только при загрузке содержимого
+	if cfg.ValidateContent {
+		check.Bytes = resp.Size
+		if cfg.RangeBytes > 0 {
+			check.Bytes = min(resp.Size, cfg.RangeBytes)
+		}
+	}
+
+	// Время загрузки относительно длительности сегмента имеет смысл только для сегмента целиком
+	if cfg.ValidateContent && cfg.RangeBytes == 0 && segment.Duration > 0 {
+		check.DownloadRatio = resp.Duration.Seconds() / segment.Duration
+	}
+
 	// Если валидация контента отключена, считаем сегмент успешным
 	if !cfg.ValidateContent {
 		check.Success = true
@@ -277,6 +751,13 @@ func (c *StreamChecker) checkSegment(ctx context.Context, segment *m3u8.MediaSeg
 		return check
 	}
 
+	check.Programs = resp.MediaInfo.Programs
+	check.CCErrors = resp.MediaInfo.CCErrors
+	check.Packets = resp.MediaInfo.Packets
+	check.NullPackets = resp.MediaInfo.NullPackets
+	check.PCRMaxInterval = resp.MediaInfo.PCRMaxInterval
+	check.PCRJitter = resp.MediaInfo.PCRJitter
+
 	segData := &models.SegmentData{
 		URI:       segment.URI,
 		Duration:  segment.Duration,
@@ -284,9 +765,13 @@ func (c *StreamChecker) checkSegment(ctx context.Context, segment *m3u8.MediaSeg
 		MediaInfo: resp.MediaInfo,
 	}
 
-	if err := c.validator.ValidateSegment(segData, cfg.MediaValidation); err != nil {
+	validateStart := time.Now()
+	err = c.validator.ValidateSegment(segData, cfg.MediaValidation)
+	observeStage(ctx, models.StageValidation, validateStart)
+	if err != nil {
 		if c.logger != nil {
 			c.logger.Debug("Segment validation failed",
+				zap.String("check_id", models.CheckIDFrom(ctx)),
 				zap.String("url", segment.URI),
 				zap.Error(err))
 		}
@@ -297,51 +782,127 @@ func (c *StreamChecker) checkSegment(ctx context.Context, segment *m3u8.MediaSeg
 		return check
 	}
 
+	externalStart := time.Now()
+	err = c.validateExternalSegment(cfg, segData)
+	observeStage(ctx, models.StageValidation, externalStart)
+	if err != nil {
+		c.logger.Debug("External validator rejected segment",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
+			zap.String("url", segment.URI),
+			zap.Error(err))
+		check.Error = &models.CheckError{
+			Type:    models.ErrPluginValidate,
+			Message: err.Error(),
+		}
+		return check
+	}
+
 	check.Success = true
 	check.Duration = resp.Duration
 	return check
 }
 
-func (c *StreamChecker) worker() {
-	defer c.wg.Done()
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.stopCh:
-			return
-		case <-ticker.C:
-			// Здесь можно добавить периодические проверки
-		}
+// accountTraffic учитывает загруженные за проверку байты в суточном бюджете стрима
+func (c *StreamChecker) accountTraffic(stream models.StreamConfig, result *models.CheckResult) {
+	for _, seg := range result.Segments.Details {
+		result.BytesDownloaded += seg.Bytes
+	}
+
+	total := c.budget.Add(stream.Name, result.BytesDownloaded)
+	if stream.DailyByteBudget > 0 {
+		result.BudgetExceeded = total >= stream.DailyByteBudget
+		c.metrics.SetBudgetExceeded(stream.Name, result.BudgetExceeded)
 	}
 }
 
+// downReasonUnknown причина недоступности проверки без описания ошибки
+const downReasonUnknown = "unknown"
+
+// downReason возвращает код основной причины недоступности стрима: причину диагностической
+// проверки, иначе тип ошибки с кодом ответа HTTP, если он есть. Пусто - стрим доступен.
+func downReason(result *models.CheckResult) string {
+	if result.Success {
+		return ""
+	}
+	if result.Error == nil {
+		return downReasonUnknown
+	}
+	if result.Error.Cause != "" {
+		return result.Error.Cause
+	}
+	reason := string(result.Error.Type)
+	if code := result.Error.StatusCode; code != 0 {
+		reason += "_http_" + strconv.Itoa(code)
+	}
+	return reason
+}
+
 func (c *StreamChecker) updateMetrics(stream string, result *models.CheckResult) {
 	c.metrics.SetStreamUp(stream, result.Success)
-	c.metrics.RecordResponseTime(stream, result.Duration.Seconds())
+	c.metrics.SetStreamDownReason(stream, downReason(result))
+	c.metrics.RecordCheckDuration(stream, result.Duration.Seconds(), result.TraceID)
 	c.metrics.SetLastCheckTime(stream, result.Timestamp)
-	c.metrics.SetSegmentsCount(stream, result.Segments.Checked)
+	if result.Success {
+		c.metrics.SetLastSuccessTime(stream, result.Timestamp)
+	}
+	c.metrics.SetSegmentsCount(stream, result.StreamStatus.SegmentsCount)
 	c.metrics.SetActiveChecks(c.workers)
+	c.metrics.SetWorkerPool(int(c.tasks.active()), int(c.tasks.queued.Load()))
+	c.metrics.RecordQueueWait(stream, result.QueueWait.Seconds())
 	c.metrics.RecordSegmentCheck(stream, result.Success)
-	c.metrics.SetStreamBitrate(stream, 0.0) // Add proper bitrate calculation if needed
+	c.metrics.RecordCheckResult(stream, result.Success, result.Timestamp)
+	// Метрика стрима исторически публикуется в байтах в секунду
+	c.metrics.SetStreamBitrate(stream, result.Bitrate/8)
+	c.metrics.AddDownloadedBytes(stream, result.BytesDownloaded)
 
 	if result.Error != nil {
 		c.metrics.RecordError(stream, string(result.Error.Type))
 	}
+
+	for _, r := range result.Renditions {
+		c.metrics.SetRenditionUp(stream, r.Type, r.GroupID, r.Name, r.Success)
+		if r.Error != nil {
+			c.metrics.RecordError(stream, string(r.Error.Type))
+		}
+	}
 }
 
-func parseMasterPlaylist(data []byte) (*m3u8.MasterPlaylist, error) {
+// playlistErrorType определяет тип ошибки валидации плейлиста
+func playlistErrorType(err error) models.ErrorType {
+	var durationErr *models.SegmentDurationError
+	if errors.As(err, &durationErr) {
+		return models.ErrSegmentDuration
+	}
+	var specErr *models.SpecViolationError
+	if errors.As(err, &specErr) {
+		return models.ErrSpecViolation
+	}
+	return models.ErrPlaylistParse
+}
+
+// recordSpecViolation учитывает нарушение спецификации HLS из ошибки валидации плейлиста
+func (c *StreamChecker) recordSpecViolation(stream string, err error) {
+	var specErr *models.SpecViolationError
+	if errors.As(err, &specErr) {
+		c.metrics.RecordSpecViolation(stream, specErr.Rule)
+	}
+}
+
+// parsePlaylist разбирает плейлист и определяет его тип (master/media)
+func parsePlaylist(data []byte) (m3u8.Playlist, m3u8.ListType, error) {
 	playlist, listType, err := m3u8.DecodeFrom(bytes.NewReader(data), false)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	if listType != m3u8.MASTER {
-		return nil, fmt.Errorf("expected master playlist, got %v", listType)
+	if listType != m3u8.MASTER && listType != m3u8.MEDIA {
+		return nil, 0, fmt.Errorf("unknown playlist type: %v", listType)
+	}
+	if listType == m3u8.MEDIA {
+		restoreTargetDuration(playlist.(*m3u8.MediaPlaylist), data)
 	}
 
-	return playlist.(*m3u8.MasterPlaylist), nil
+	return playlist, listType, nil
 }
 
 func parseMediaPlaylist(data []byte) (*m3u8.MediaPlaylist, error) {
@@ -354,7 +915,24 @@ func parseMediaPlaylist(data []byte) (*m3u8.MediaPlaylist, error) {
 		return nil, fmt.Errorf("expected media playlist, got %v", listType)
 	}
 
-	return playlist.(*m3u8.MediaPlaylist), nil
+	media := playlist.(*m3u8.MediaPlaylist)
+	restoreTargetDuration(media, data)
+	return media, nil
+}
+
+// restoreTargetDuration возвращает объявленное в плейлисте значение EXT-X-TARGETDURATION:
+// при разборе m3u8 поднимает его до наибольшей длительности сегмента
+func restoreTargetDuration(media *m3u8.MediaPlaylist, data []byte) {
+	for line := range bytes.Lines(data) {
+		value, ok := bytes.CutPrefix(bytes.TrimSpace(line), []byte("#EXT-X-TARGETDURATION:"))
+		if !ok {
+			continue
+		}
+		if target, err := strconv.ParseFloat(string(value), 64); err == nil && target > 0 {
+			media.TargetDuration = target
+		}
+		return
+	}
 }
 
 func resolveURL(baseURL, relativePath string) string {
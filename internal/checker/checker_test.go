@@ -3,6 +3,7 @@ package checker
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/iudanet/hls_exporter/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock implementations
@@ -37,6 +39,14 @@ func (m *MockHTTPClient) GetSegment(
 	return nil, args.Error(1)
 }
 
+func (m *MockHTTPClient) GetKey(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	args := m.Called(ctx, url)
+	if resp := args.Get(0); resp != nil {
+		return resp.(*models.PlaylistResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockHTTPClient) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -73,8 +83,32 @@ func (m *MockMetricsCollector) SetStreamUp(name string, up bool) {
 	m.Called(name, up)
 }
 
-func (m *MockMetricsCollector) RecordResponseTime(name string, duration float64) {
-	m.Called(name, duration)
+func (m *MockMetricsCollector) RecordResponseTime(name, phase string, duration float64, traceID string) {
+	m.Called(name, phase, duration, traceID)
+}
+
+func (m *MockMetricsCollector) RecordSegmentSize(name string, bytes int64) {
+	m.Called(name, bytes)
+}
+
+func (m *MockMetricsCollector) RecordSegmentDuration(name string, seconds float64) {
+	m.Called(name, seconds)
+}
+
+func (m *MockMetricsCollector) RecordSegmentDownloadRatio(name string, ratio float64) {
+	m.Called(name, ratio)
+}
+
+func (m *MockMetricsCollector) RecordCheckDuration(name string, duration float64, traceID string) {
+	m.Called(name, duration, traceID)
+}
+
+func (m *MockMetricsCollector) RecordHTTPResponse(name, phase string, code int) {
+	m.Called(name, phase, code)
+}
+
+func (m *MockMetricsCollector) RecordRateLimited(name, phase string) {
+	m.Called(name, phase)
 }
 
 func (m *MockMetricsCollector) RecordError(name, errorType string) {
@@ -85,6 +119,10 @@ func (m *MockMetricsCollector) SetLastCheckTime(name string, timestamp time.Time
 	m.Called(name, timestamp)
 }
 
+func (m *MockMetricsCollector) SetLastSuccessTime(name string, timestamp time.Time) {
+	m.Called(name, timestamp)
+}
+
 func (m *MockMetricsCollector) SetSegmentsCount(name string, count int) {
 	m.Called(name, count)
 }
@@ -93,13 +131,222 @@ func (m *MockMetricsCollector) RecordSegmentCheck(name string, success bool) {
 	m.Called(name, success)
 }
 
+func (m *MockMetricsCollector) RecordCheckResult(name string, success bool, timestamp time.Time) {
+	m.Called(name, success, timestamp)
+}
+
 func (m *MockMetricsCollector) SetActiveChecks(count int) {
 	m.Called(count)
 }
+
+func (m *MockMetricsCollector) SetWorkerPool(busy, queued int) {
+	m.Called(busy, queued)
+}
+
+func (m *MockMetricsCollector) RecordQueueWait(name string, seconds float64) {
+	m.Called(name, seconds)
+}
+
+func (m *MockMetricsCollector) SetSchedulingDrift(name string, drift float64) {
+	m.Called(name, drift)
+}
+
 func (m *MockMetricsCollector) SetStreamBitrate(name string, bitrate float64) {
 	m.Called(name, bitrate)
 }
 
+func (m *MockMetricsCollector) SetVariantBitrate(name, bandwidth, resolution string, declared, measured float64) {
+	m.Called(name, bandwidth, resolution, declared, measured)
+}
+
+func (m *MockMetricsCollector) SetVariantSizeAnomaly(name, bandwidth, resolution string, anomaly bool) {
+	m.Called(name, bandwidth, resolution, anomaly)
+}
+
+func (m *MockMetricsCollector) SetContentLooping(name string, looping bool) {
+	m.Called(name, looping)
+}
+
+func (m *MockMetricsCollector) SetStreamSilenced(name string, silenced bool) {
+	m.Called(name, silenced)
+}
+
+func (m *MockMetricsCollector) SetStreamDegraded(name string, degraded bool) {
+	m.Called(name, degraded)
+}
+
+func (m *MockMetricsCollector) RecordSpecViolation(name, rule string) {
+	m.Called(name, rule)
+}
+
+func (m *MockMetricsCollector) RecordStageDuration(name, stage string, duration float64) {
+	m.Called(name, stage, duration)
+}
+
+func (m *MockMetricsCollector) SetPlaylistType(name string, live bool, duration float64) {
+	m.Called(name, live, duration)
+}
+
+func (m *MockMetricsCollector) SetUserAgentUp(name, userAgent string, up bool) {
+	m.Called(name, userAgent, up)
+}
+
+func (m *MockMetricsCollector) SetSLOViolation(name, slo string, violated bool) {
+	m.Called(name, slo, violated)
+}
+
+func (m *MockMetricsCollector) SetDASHUp(name string, up bool) {
+	m.Called(name, up)
+}
+
+func (m *MockMetricsCollector) SetLiveEdgeDivergence(name string, divergence float64) {
+	m.Called(name, divergence)
+}
+
+func (m *MockMetricsCollector) RecordPIDChange(name, kind string) {
+	m.Called(name, kind)
+}
+
+func (m *MockMetricsCollector) SetPlaylistMarkers(name, variant string, discontinuities, gaps int) {
+	m.Called(name, variant, discontinuities, gaps)
+}
+
+func (m *MockMetricsCollector) AddDiscontinuities(name, variant string, count int) {
+	m.Called(name, variant, count)
+}
+
+func (m *MockMetricsCollector) SetMediaSequence(name, variant string, sequence uint64) {
+	m.Called(name, variant, sequence)
+}
+
+func (m *MockMetricsCollector) SetPlaylistAge(name, variant string, age time.Duration) {
+	m.Called(name, variant, age)
+}
+
+func (m *MockMetricsCollector) RecordErrorCause(name, cause string) {
+	m.Called(name, cause)
+}
+
+func (m *MockMetricsCollector) AddHostBudgetWait(host string, wait time.Duration) {
+	m.Called(host, wait)
+}
+
+func (m *MockMetricsCollector) AddRequestLimitWait(wait time.Duration) {
+	m.Called(wait)
+}
+
+func (m *MockMetricsCollector) SetSessionData(name string, entries []models.SessionData) {
+	m.Called(name, entries)
+}
+
+func (m *MockMetricsCollector) RecordEventViolation(name, variant, kind string) {
+	m.Called(name, variant, kind)
+}
+
+func (m *MockMetricsCollector) RecordKeyCacheRequest(name string, hit bool) {
+	m.Called(name, hit)
+}
+
+func (m *MockMetricsCollector) SetStreamDownReason(name, reason string) {
+	m.Called(name, reason)
+}
+
+func (m *MockMetricsCollector) SetStreamInfo(stream models.StreamConfig) {
+	m.Called(stream)
+}
+
+func (m *MockMetricsCollector) Reset(name string) {
+	m.Called(name)
+}
+
+func (m *MockMetricsCollector) SetThresholds(name string, thresholds map[string]float64) {
+	m.Called(name, thresholds)
+}
+
+func (m *MockMetricsCollector) SetAdMarkers(name, variant string, breaks, malformed int) {
+	m.Called(name, variant, breaks, malformed)
+}
+
+func (m *MockMetricsCollector) SetLastAdCue(name, variant string, timestamp time.Time) {
+	m.Called(name, variant, timestamp)
+}
+
+func (m *MockMetricsCollector) RecordCodecMismatch(name, bandwidth, resolution, codec string) {
+	m.Called(name, bandwidth, resolution, codec)
+}
+
+func (m *MockMetricsCollector) AddCCErrors(name string, count int) {
+	m.Called(name, count)
+}
+
+func (m *MockMetricsCollector) SetPCRStats(name string, maxInterval, jitter float64) {
+	m.Called(name, maxInterval, jitter)
+}
+
+func (m *MockMetricsCollector) SetNullPacketRatio(name string, ratio float64) {
+	m.Called(name, ratio)
+}
+
+func (m *MockMetricsCollector) SetLastDeepCheckTime(name string, timestamp time.Time) {
+	m.Called(name, timestamp)
+}
+
+func (m *MockMetricsCollector) SetCheckInterval(name string, interval float64) {
+	m.Called(name, interval)
+}
+
+func (m *MockMetricsCollector) AddChecksSkipped(name string, count int) {
+	m.Called(name, count)
+}
+
+func (m *MockMetricsCollector) AddDownloadedBytes(name string, bytes int64) {
+	m.Called(name, bytes)
+}
+
+func (m *MockMetricsCollector) SetBudgetExceeded(name string, exceeded bool) {
+	m.Called(name, exceeded)
+}
+
+func (m *MockMetricsCollector) SetRenditionUp(name, renditionType, groupID, rendition string, up bool) {
+	m.Called(name, renditionType, groupID, rendition, up)
+}
+
+func (m *MockMetricsCollector) SetLiveEdgeLatency(name, variant string, latency float64) {
+	m.Called(name, variant, latency)
+}
+
+func (m *MockMetricsCollector) SetPackagerClockSkew(name, variant string, skew float64) {
+	m.Called(name, variant, skew)
+}
+
+func (m *MockMetricsCollector) RecordConformanceViolation(name, rule string) {
+	m.Called(name, rule)
+}
+
+func (m *MockMetricsCollector) SetUnknownTag(name, tag string) {
+	m.Called(name, tag)
+}
+
+func (m *MockMetricsCollector) RecordParseIssue(name, kind string) {
+	m.Called(name, kind)
+}
+
+func (m *MockMetricsCollector) SetPlaylistStale(name string, stale bool) {
+	m.Called(name, stale)
+}
+
+func (m *MockMetricsCollector) RecordVariantSegmentCheck(name, bandwidth, resolution string, success bool) {
+	m.Called(name, bandwidth, resolution, success)
+}
+
+func (m *MockMetricsCollector) RecordVariantResponseTime(
+	name, bandwidth, resolution string,
+	duration float64,
+	traceID string,
+) {
+	m.Called(name, bandwidth, resolution, duration, traceID)
+}
+
 func TestStreamChecker_Check_Success(t *testing.T) {
 	// Setup
 	mockClient := new(MockHTTPClient)
@@ -149,12 +396,33 @@ segment1.ts`),
 
 	// Add metrics expectations
 	mockMetrics.On("SetStreamUp", "test_stream", true).Return()
-	mockMetrics.On("RecordResponseTime", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", mock.Anything, mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetLastCheckTime", "test_stream", mock.AnythingOfType("time.Time")).Return()
+	mockMetrics.On("SetLastSuccessTime", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetSegmentsCount", "test_stream", mock.AnythingOfType("int")).Return()
 	mockMetrics.On("SetActiveChecks", mock.AnythingOfType("int")).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("RecordSegmentCheck", "test_stream", true).Return()
-	mockMetrics.On("SetStreamBitrate", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", 102.4).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("SetPlaylistStale", "test_stream", false).Return()
+	mockMetrics.On("SetPlaylistType", "test_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetPlaylistMarkers", "test_stream", mock.Anything, 0, 0).Return()
+	mockMetrics.On("SetMediaSequence", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetContentLooping", "test_stream", false).Return()
+	mockMetrics.On("RecordVariantResponseTime", "test_stream", "1000000", "", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("SetSessionData", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordVariantSegmentCheck", "test_stream", "1000000", "", true).Return()
+	// 1024 байта за 10 секунд EXTINF
+	mockMetrics.On("SetVariantBitrate", "test_stream", "1000000", "", 1000000.0, 819.2).Return()
 
 	// Execute
 	result, err := checker.Check(context.Background(), models.StreamConfig{
@@ -170,6 +438,25 @@ segment1.ts`),
 	assert.True(t, result.Success)
 	assert.Equal(t, 1, result.Segments.Checked)
 	assert.Equal(t, 0, result.Segments.Failed)
+	assert.Equal(t, 819.2, result.Bitrate)
+	require.Len(t, result.Variants, 1)
+	assert.InDelta(t, 0.0008192, result.Variants[0].DeviationRatio, 1e-9)
+	for _, stage := range []string{
+		models.StageMasterFetch, models.StageVariantFetch, models.StageSegmentDownload, models.StageValidation,
+	} {
+		assert.Contains(t, result.Stages, stage)
+	}
+	mockMetrics.AssertCalled(t, "RecordStageDuration", "test_stream", models.StageSegmentDownload, mock.Anything)
+	for _, phase := range []string{
+		models.ResponseTimeMaster, models.ResponseTimeVariant, models.ResponseTimeSegment,
+	} {
+		mockMetrics.AssertCalled(t, "RecordResponseTime", "test_stream", phase, mock.Anything, mock.Anything)
+	}
+	mockMetrics.AssertCalled(t, "RecordResponseTime", "test_stream", models.ResponseTimeSegment, 1.0, mock.Anything)
+	mockMetrics.AssertCalled(t, "RecordCheckDuration", "test_stream", mock.Anything, mock.Anything)
+	mockMetrics.AssertCalled(t, "RecordSegmentDuration", "test_stream", mock.AnythingOfType("float64"))
+	mockMetrics.AssertCalled(t, "RecordHTTPResponse", "test_stream", models.ResponseTimeMaster, 200)
+	mockMetrics.AssertCalled(t, "RecordHTTPResponse", "test_stream", models.ResponseTimeVariant, 200)
 
 	// Verify all expectations were met
 	mockClient.AssertExpectations(t)
@@ -187,17 +474,29 @@ func TestStreamChecker_Check_MasterPlaylistError(t *testing.T) {
 	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
 
 	// Setup only the necessary expectations
-	mockClient.On("GetPlaylist", ctx, "http://test.com/master.m3u8").Return(nil, errors.New("network error"))
+	// Идентификатор проверки передается HTTP-клиенту через контекст
+	var checkID string
+	withCheckID := mock.MatchedBy(func(ctx context.Context) bool {
+		checkID = models.CheckIDFrom(ctx)
+		return checkID != ""
+	})
+	mockClient.On("GetPlaylist", withCheckID, "http://test.com/master.m3u8").Return(nil, errors.New("network error"))
 
 	// Metric expectations that are actually called in updateMetrics
 	mockMetrics.On("SetStreamUp", "test_stream", false).Return()
-	mockMetrics.On("RecordResponseTime", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetLastCheckTime", "test_stream", mock.AnythingOfType("time.Time")).Return()
 	mockMetrics.On("SetSegmentsCount", "test_stream", mock.AnythingOfType("int")).Return()
 	mockMetrics.On("SetActiveChecks", mock.AnythingOfType("int")).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("RecordSegmentCheck", "test_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", false, mock.Anything).Return()
 	mockMetrics.On("SetStreamBitrate", "test_stream", mock.AnythingOfType("float64")).Return()
 	mockMetrics.On("RecordError", "test_stream", string(models.ErrPlaylistDownload)).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", int64(0)).Return()
 
 	// Execute
 	result, err := checker.Check(ctx, models.StreamConfig{
@@ -210,12 +509,309 @@ func TestStreamChecker_Check_MasterPlaylistError(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.False(t, result.Success)
 	assert.Equal(t, models.ErrPlaylistDownload, result.Error.Type)
+	assert.Len(t, result.CheckID, 16)
+	assert.Equal(t, checkID, result.CheckID)
 
 	// Verify expectations
 	mockClient.AssertExpectations(t)
 	mockMetrics.AssertExpectations(t)
 }
 
+func TestStreamChecker_Check_RequestAuth(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, new(MockValidator), mockMetrics, 1)
+
+	// Авторизация и параметры TLS стрима доходят до HTTP-клиента через контекст
+	withToken := mock.MatchedBy(func(ctx context.Context) bool {
+		tlsCfg := models.StreamTLSFrom(ctx)
+		return models.RequestAuthFrom(ctx).BearerToken == "token" && tlsCfg != nil && tlsCfg.CAFile == "/etc/ssl/origin-ca.pem"
+	})
+	mockClient.On("GetPlaylist", withToken, "http://test.com/master.m3u8").Return(nil, errors.New("unexpected status code: 403"))
+
+	mockMetrics.On("SetStreamUp", "test_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "test_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordError", "test_stream", string(models.ErrPlaylistDownload)).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", int64(0)).Return()
+
+	_, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:        "test_stream",
+		URL:         "http://test.com/master.m3u8",
+		BearerToken: "token",
+		TLS:         &models.TLSConfig{CAFile: "/etc/ssl/origin-ca.pem"},
+	})
+
+	assert.Error(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestStreamChecker_Check_MediaPlaylist(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+
+	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+
+	// URL стрима указывает сразу на медиаплейлист (audio-only/single variant)
+	mediaURL := "http://test.com/audio/index.m3u8"
+	mockClient.On("GetPlaylist", mock.Anything, mediaURL).Return(
+		&models.PlaylistResponse{
+			Body: []byte(`#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:10
+#EXTINF:10.0,
+segment1.aac
+#EXTINF:10.0,
+segment2.aac`),
+			StatusCode: 200,
+		}, nil).Once()
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/audio/segment1.aac", false).Return(
+		&models.SegmentResponse{Size: 1024, Duration: time.Second}, nil)
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/audio/segment2.aac", false).Return(
+		&models.SegmentResponse{Size: 1024, Duration: time.Second}, nil)
+
+	mockValidator.On("ValidateMedia", mock.AnythingOfType("*m3u8.MediaPlaylist")).Return(nil)
+
+	mockMetrics.On("SetStreamUp", "audio_stream", true).Return()
+	mockMetrics.On("SetStreamDownReason", "audio_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "audio_stream", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "audio_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "audio_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "audio_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "audio_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "audio_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "audio_stream", mock.Anything).Return()
+	mockMetrics.On("SetLastSuccessTime", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "audio_stream", 2).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "audio_stream", true).Return()
+	mockMetrics.On("RecordCheckResult", "audio_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "audio_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "audio_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("SetPlaylistStale", "audio_stream", false).Return()
+	mockMetrics.On("SetPlaylistType", "audio_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetPlaylistMarkers", "audio_stream", mock.Anything, 0, 0).Return()
+	mockMetrics.On("SetMediaSequence", "audio_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetContentLooping", "audio_stream", false).Return()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:      "audio_stream",
+		URL:       mediaURL,
+		CheckMode: models.CheckModeAll,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, result.StreamStatus.VariantsCount)
+	assert.Equal(t, 2, result.Segments.Checked)
+
+	mockClient.AssertExpectations(t)
+	mockValidator.AssertNotCalled(t, "ValidateMaster", mock.Anything)
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestStreamChecker_Check_SegmentDurationVariation(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockMetrics := new(MockMetricsCollector)
+
+	validator := NewHLSValidator()
+	validator.SetMaxDurationCV(0.3)
+	checker := NewStreamChecker(mockClient, validator, mockMetrics, 1)
+
+	mediaURL := "http://test.com/live/index.m3u8"
+	mockClient.On("GetPlaylist", mock.Anything, mediaURL).Return(
+		&models.PlaylistResponse{
+			Body: []byte(`#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:10
+#EXTINF:2.0,
+segment1.ts
+#EXTINF:2.0,
+segment2.ts
+#EXTINF:9.8,
+segment3.ts
+#EXTINF:0.2,
+segment4.ts`),
+			StatusCode: 200,
+		}, nil).Once()
+
+	mockMetrics.On("SetStreamUp", "gop_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "gop_stream", mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "gop_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordHTTPResponse", "gop_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "gop_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "gop_stream", mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "gop_stream", 0).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "gop_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "gop_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "gop_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "gop_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordError", "gop_stream", string(models.ErrSegmentDuration)).Return()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:      "gop_stream",
+		URL:       mediaURL,
+		CheckMode: models.CheckModeAll,
+	})
+
+	var durationErr *models.SegmentDurationError
+	assert.ErrorAs(t, err, &durationErr)
+	assert.False(t, result.Success)
+	assert.Equal(t, models.ErrSegmentDuration, result.Error.Type)
+
+	mockClient.AssertNotCalled(t, "GetSegment", mock.Anything, mock.Anything, mock.Anything)
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestStreamChecker_Check_SpecViolation(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockMetrics := new(MockMetricsCollector)
+
+	validator := NewHLSValidator()
+	validator.SetTargetDurationCheck(true)
+	checker := NewStreamChecker(mockClient, validator, mockMetrics, 1)
+
+	mediaURL := "http://test.com/live/index.m3u8"
+	mockClient.On("GetPlaylist", mock.Anything, mediaURL).Return(
+		&models.PlaylistResponse{
+			Body: []byte(`#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:4
+#EXTINF:4.0,
+segment1.ts
+#EXTINF:6.0,
+segment2.ts`),
+			StatusCode: 200,
+		}, nil).Once()
+
+	mockMetrics.On("SetStreamUp", "spec_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "spec_stream", mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "spec_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordHTTPResponse", "spec_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "spec_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "spec_stream", mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "spec_stream", 0).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "spec_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "spec_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "spec_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "spec_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordError", "spec_stream", string(models.ErrSpecViolation)).Return()
+	mockMetrics.On("RecordSpecViolation", "spec_stream", models.SpecRuleTargetDuration).Return().Once()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:      "spec_stream",
+		URL:       mediaURL,
+		CheckMode: models.CheckModeAll,
+	})
+
+	var specErr *models.SpecViolationError
+	assert.ErrorAs(t, err, &specErr)
+	assert.False(t, result.Success)
+	assert.Equal(t, models.ErrSpecViolation, result.Error.Type)
+	assert.Contains(t, result.Error.Message, "segment2.ts")
+
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestStreamChecker_Check_BudgetExceeded(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+
+	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+	checker.budget.Add("test_stream", 2048)
+
+	masterURL := "http://test.com/master.m3u8"
+	mockClient.On("GetPlaylist", mock.Anything, masterURL).Return(
+		&models.PlaylistResponse{
+			Body: []byte(`#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=1000000
+stream.m3u8`),
+			StatusCode: 200,
+		}, nil)
+	mockClient.On("GetPlaylist", mock.Anything, "http://test.com/stream.m3u8").Return(
+		&models.PlaylistResponse{
+			Body: []byte(`#EXTM3U
+#EXT-X-TARGETDURATION:10
+#EXTINF:10.0,
+segment1.ts`),
+			StatusCode: 200,
+		}, nil)
+	// Лимит исчерпан: сегмент проверяется без загрузки тела
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/segment1.ts", false).Return(
+		&models.SegmentResponse{Size: 1024, Duration: time.Second}, nil)
+
+	mockValidator.On("ValidateMaster", mock.Anything).Return(nil)
+	mockValidator.On("ValidateMedia", mock.Anything).Return(nil)
+
+	mockMetrics.On("SetStreamUp", "test_stream", true).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetLastSuccessTime", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "test_stream", 1).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "test_stream", true).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("SetPlaylistStale", "test_stream", false).Return()
+	mockMetrics.On("SetPlaylistType", "test_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetPlaylistMarkers", "test_stream", mock.Anything, 0, 0).Return()
+	mockMetrics.On("SetMediaSequence", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetContentLooping", "test_stream", false).Return()
+	mockMetrics.On("RecordVariantResponseTime", "test_stream", "1000000", "", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("SetSessionData", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordVariantSegmentCheck", "test_stream", "1000000", "", true).Return()
+	mockMetrics.On("SetVariantBitrate", "test_stream", "1000000", "", 1000000.0, mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("SetBudgetExceeded", "test_stream", true).Return()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:            "test_stream",
+		URL:             masterURL,
+		CheckMode:       models.CheckModeAll,
+		ValidateContent: true,
+		DailyByteBudget: 1024,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, result.BudgetExceeded)
+	assert.Equal(t, int64(0), result.Segments.Details[0].Bytes, "HEAD check should not count segment body")
+
+	mockClient.AssertExpectations(t)
+	mockMetrics.AssertExpectations(t)
+}
+
 func TestResolveURL(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -250,3 +846,77 @@ func TestResolveURL(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateResultStatus_SegmentsCount(t *testing.T) {
+	c := &StreamChecker{}
+	resp := &models.PlaylistResponse{Headers: http.Header{}}
+	segResults := models.SegmentResults{Checked: 2, Total: 2, Listed: 6}
+
+	result := c.updateResultStatus(&models.CheckResult{}, 1, resp, segResults, models.CheckModeFirstLast)
+	assert.Equal(t, 2, result.StreamStatus.SegmentsCount)
+
+	// Без загрузки сегментов публикуется число сегментов в окнах плейлистов
+	segResults = models.SegmentResults{Listed: 6}
+	result = c.updateResultStatus(&models.CheckResult{}, 1, resp, segResults, models.CheckModePlaylistOnly)
+	assert.Equal(t, 6, result.StreamStatus.SegmentsCount)
+}
+
+func TestDownReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *models.CheckResult
+		want   string
+	}{
+		{name: "up", result: &models.CheckResult{Success: true}},
+		{name: "no error", result: &models.CheckResult{}, want: "unknown"},
+		{
+			name:   "error type",
+			result: &models.CheckResult{Error: &models.CheckError{Type: models.ErrPlaylistStale}},
+			want:   "playlist_stale",
+		},
+		{
+			name:   "http status",
+			result: &models.CheckResult{Error: &models.CheckError{Type: models.ErrPlaylistDownload, StatusCode: 403}},
+			want:   "playlist_download_http_403",
+		},
+		{
+			name: "diagnosed cause",
+			result: &models.CheckResult{Error: &models.CheckError{
+				Type: models.ErrSegmentDownload, StatusCode: 404, Cause: models.CauseCDNNegativeCache,
+			}},
+			want: models.CauseCDNNegativeCache,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, downReason(tt.result))
+		})
+	}
+}
+
+func TestStreamChecker_SegmentDownloadRatio(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+
+	url := "http://test.com/segment1.ts"
+	mockClient.On("GetSegment", mock.Anything, url, true).Return(&models.SegmentResponse{
+		StatusCode: http.StatusOK,
+		Size:       1024,
+		Duration:   3 * time.Second,
+	}, nil)
+	mockValidator.On("ValidateSegment", mock.Anything, mock.Anything).Return(nil)
+	mockMetrics.On("RecordHTTPResponse", "test_stream", models.ResponseTimeSegment, http.StatusOK).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", models.ResponseTimeSegment, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "test_stream", 6.0).Return()
+	mockMetrics.On("RecordSegmentSize", "test_stream", int64(1024)).Return()
+	mockMetrics.On("RecordSegmentDownloadRatio", "test_stream", 0.5).Return()
+
+	results := checker.checkSegments(context.Background(), []*m3u8.MediaSegment{{URI: url, Duration: 6}},
+		models.StreamConfig{Name: "test_stream", ValidateContent: true})
+
+	assert.Equal(t, 0, results.Failed)
+	assert.Equal(t, 0.5, results.Details[0].DownloadRatio)
+	mockMetrics.AssertExpectations(t)
+}
@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// recordClockSkew экспортирует расхождение часов упаковщика и сервера: разницу между
+// концом последнего сегмента по EXT-X-PROGRAM-DATE-TIME и заголовком Date ответа
+// медиаплейлиста. Сравнение с Date, а не с часами экспортера, не зависит от их точности.
+// Небольшое отрицательное значение - задержка публикации сегмента, положительное -
+// часы упаковщика спешат. В результате запоминается наибольшее по модулю расхождение.
+func (c *StreamChecker) recordClockSkew(
+	stream models.StreamConfig,
+	variant string,
+	media *m3u8.MediaPlaylist,
+	headers http.Header,
+	result *models.CheckResult,
+) {
+	end, ok := liveEdgeTime(media)
+	if !ok {
+		return
+	}
+	date, err := http.ParseTime(headers.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	skew := end.Sub(date).Seconds()
+	c.metrics.SetPackagerClockSkew(stream.Name, variant, skew)
+	if math.Abs(skew) > math.Abs(result.PackagerClockSkew) {
+		result.PackagerClockSkew = skew
+	}
+}
@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamChecker_RecordClockSkew(t *testing.T) {
+	const body = `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T12:00:12Z
+#EXTINF:6.0,
+seg1.ts
+#EXTINF:6.0,
+seg2.ts
+`
+	media := decodeMediaPlaylist(t, body)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	mockMetrics := new(MockMetricsCollector)
+	mockMetrics.On("SetPackagerClockSkew", "test_stream", "720p.m3u8", -1.0).Return()
+	mockMetrics.On("SetPackagerClockSkew", "test_stream", "1080p.m3u8", 5.0).Return()
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	result := &models.CheckResult{}
+
+	// Live-край 12:00:24 по часам упаковщика
+	c.recordClockSkew(stream, "720p.m3u8", media, http.Header{"Date": {"Mon, 01 Jan 2024 12:00:25 GMT"}}, result)
+	assert.Equal(t, -1.0, result.PackagerClockSkew)
+	c.recordClockSkew(stream, "1080p.m3u8", media, http.Header{"Date": {"Mon, 01 Jan 2024 12:00:19 GMT"}}, result)
+	assert.Equal(t, 5.0, result.PackagerClockSkew, "largest skew by magnitude should be kept")
+
+	// Без заголовка Date или PROGRAM-DATE-TIME расхождение неизвестно
+	c.recordClockSkew(stream, "720p.m3u8", media, http.Header{}, result)
+	c.recordClockSkew(stream, "720p.m3u8", decodeMediaPlaylist(t, "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6.0,\nseg1.ts\n"),
+		http.Header{"Date": {"Mon, 01 Jan 2024 12:00:25 GMT"}}, result)
+	mockMetrics.AssertNumberOfCalls(t, "SetPackagerClockSkew", 2)
+}
@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"slices"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/internal/mpegts"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// normalizeCodec приводит равнозначные обозначения кодека к одному: hev1 и hvc1 - HEVC
+func normalizeCodec(family string) string {
+	if family == "hev1" {
+		return "hvc1"
+	}
+	return family
+}
+
+// segmentCodecs возвращает семейства кодеков элементарных потоков из PMT успешно проверенных сегментов
+func segmentCodecs(checks []models.SegmentCheck) []string {
+	var codecs []string
+	for _, check := range checks {
+		if !check.Success {
+			continue
+		}
+		for _, program := range check.Programs {
+			for _, stream := range program.Streams {
+				if codec := mpegts.CodecFamily(stream.StreamType); codec != "" && !slices.Contains(codecs, codec) {
+					codecs = append(codecs, codec)
+				}
+			}
+		}
+	}
+	slices.Sort(codecs)
+	return codecs
+}
+
+// recordCodecMismatch сравнивает CODECS варианта с кодеками его TS-сегментов.
+// Учитываются только кодеки сегментов, не объявленные в CODECS: объявленный, но
+// отсутствующий кодек допустим для вариантов с аудио в отдельной rendition.
+// Варианты без CODECS и сегменты без PMT не проверяются.
+func (c *StreamChecker) recordCodecMismatch(
+	stream models.StreamConfig,
+	variant *m3u8.Variant,
+	variantURL string,
+	checks []models.SegmentCheck,
+	result *models.CheckResult,
+) {
+	if variant.Codecs == "" {
+		return
+	}
+	declared := codecFamilies(variant.Codecs)
+	for i := range declared {
+		declared[i] = normalizeCodec(declared[i])
+	}
+
+	var undeclared []string
+	for _, codec := range segmentCodecs(checks) {
+		if !slices.Contains(declared, codec) {
+			undeclared = append(undeclared, codec)
+		}
+	}
+	if len(undeclared) == 0 {
+		return
+	}
+
+	result.CodecMismatches = append(result.CodecMismatches, models.CodecMismatch{
+		URL:        variantURL,
+		Declared:   variant.Codecs,
+		Undeclared: undeclared,
+	})
+	bandwidth, resolution := variantLabels(variant)
+	for _, codec := range undeclared {
+		c.metrics.RecordCodecMismatch(stream.Name, bandwidth, resolution, codec)
+	}
+	c.logger.Warn("Variant segments contain undeclared codecs",
+		zap.String("stream", stream.Name),
+		zap.String("url", variantURL),
+		zap.String("codecs", variant.Codecs),
+		zap.Strings("undeclared", undeclared))
+}
@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentCodecs(t *testing.T) {
+	programs := []models.TSProgram{{Number: 1, Streams: []models.TSStream{tsVideo, tsAudio, {PID: 0x102, StreamType: 0x06}}}}
+	codecs := segmentCodecs([]models.SegmentCheck{
+		{Success: true, Programs: programs},
+		{Success: true, Programs: programs},
+		{Success: false, Programs: []models.TSProgram{{Streams: []models.TSStream{{StreamType: 0x24}}}}},
+	})
+	// Частные данные (0x06) и неуспешные сегменты не учитываются
+	assert.Equal(t, []string{"avc1", "mp4a"}, codecs)
+}
+
+func TestStreamChecker_RecordCodecMismatch(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+	checks := []models.SegmentCheck{{
+		Success:  true,
+		Programs: []models.TSProgram{{Number: 1, Streams: []models.TSStream{tsVideo, tsAudio}}},
+	}}
+
+	variant := func(codecs string) *m3u8.Variant {
+		return &m3u8.Variant{VariantParams: m3u8.VariantParams{Bandwidth: 2000000, Resolution: "1280x720", Codecs: codecs}}
+	}
+
+	// Совпадение, аудио в отдельной rendition и вариант без CODECS не учитываются
+	result := &models.CheckResult{}
+	c.recordCodecMismatch(stream, variant("avc1.64001f,mp4a.40.2"), "v.m3u8", checks, result)
+	c.recordCodecMismatch(stream, variant("avc1.64001f,mp4a.40.2,ec-3"), "v.m3u8", checks, result)
+	c.recordCodecMismatch(stream, variant(""), "v.m3u8", checks, result)
+	assert.Empty(t, result.CodecMismatches)
+
+	// В CODECS объявлен HEVC, а в сегментах H.264
+	mockMetrics.On("RecordCodecMismatch", "test_stream", "2000000", "1280x720", "avc1").Return().Once()
+	c.recordCodecMismatch(stream, variant("hvc1.1.6.L93.B0,mp4a.40.2"), "v.m3u8", checks, result)
+	require.Len(t, result.CodecMismatches, 1)
+	assert.Equal(t, []string{"avc1"}, result.CodecMismatches[0].Undeclared)
+	assert.Equal(t, "hvc1.1.6.L93.B0,mp4a.40.2", result.CodecMismatches[0].Declared)
+	mockMetrics.AssertExpectations(t)
+}
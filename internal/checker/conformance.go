@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"github.com/iudanet/hls_exporter/internal/conformance"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// checkConformance в строгом режиме проверяет плейлист на соответствие RFC 8216
+// правилами стрима и учитывает нарушения в метриках. Нарушения не влияют на успешность проверки.
+func (c *StreamChecker) checkConformance(
+	stream models.StreamConfig,
+	playlistURL string,
+	body []byte,
+) []models.ConformanceViolation {
+	if !stream.Strict {
+		return nil
+	}
+
+	var enable, disable []string
+	if rules := stream.ConformanceRules; rules != nil {
+		enable, disable = rules.Enable, rules.Disable
+	}
+	// Неизвестные правила отклоняются при валидации конфигурации
+	rules, err := conformance.Select(enable, disable)
+	if err != nil {
+		c.logger.Warn("Invalid conformance rules",
+			zap.String("stream", stream.Name),
+			zap.Error(err))
+		return nil
+	}
+
+	violations := conformance.CheckRules(body, rules)
+	for i := range violations {
+		violations[i].URL = playlistURL
+		c.metrics.RecordConformanceViolation(stream.Name, violations[i].Rule)
+		c.logger.Debug("Playlist conformance violation",
+			zap.String("stream", stream.Name),
+			zap.String("url", playlistURL),
+			zap.String("rule", violations[i].Rule),
+			zap.Int("line", violations[i].Line),
+			zap.String("message", violations[i].Message))
+	}
+
+	return violations
+}
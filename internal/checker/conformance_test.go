@@ -0,0 +1,36 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/internal/conformance"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamChecker_CheckConformance(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+
+	body := []byte(`#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXTINF:6,
+seg1.ts
+#EXT-X-MEDIA-SEQUENCE:1
+`)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	// Без строгого режима плейлист не проверяется
+	assert.Nil(t, c.checkConformance(stream, "http://test.com/index.m3u8", body))
+	mockMetrics.AssertNotCalled(t, "RecordConformanceViolation")
+
+	stream.Strict = true
+	mockMetrics.On("RecordConformanceViolation", "test_stream", conformance.RuleTagPlacement).Return().Once()
+
+	violations := c.checkConformance(stream, "http://test.com/index.m3u8", body)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "http://test.com/index.m3u8", violations[0].URL)
+	assert.Equal(t, 5, violations[0].Line)
+	mockMetrics.AssertExpectations(t)
+}
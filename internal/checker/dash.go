@@ -0,0 +1,57 @@
+package checker
+
+import (
+	"context"
+
+	"github.com/iudanet/hls_exporter/internal/dash"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// dashManifest результат загрузки MPD-манифеста
+type dashManifest struct {
+	mpd   *dash.MPD
+	bytes int
+	err   error
+}
+
+// fetchDASH загружает MPD-манифест DASH-версии канала параллельно с проверкой HLS
+func (c *StreamChecker) fetchDASH(ctx context.Context, url string) <-chan dashManifest {
+	ch := make(chan dashManifest, 1)
+	go func() {
+		resp, err := c.client.GetPlaylist(ctx, url)
+		if err != nil {
+			ch <- dashManifest{err: err}
+			return
+		}
+		mpd, err := dash.Parse(resp.Body)
+		ch <- dashManifest{mpd: mpd, bytes: len(resp.Body), err: err}
+	}()
+	return ch
+}
+
+// recordDASH сравнивает доступность и live-край DASH-версии канала с итоговым результатом HLS.
+// Ошибки DASH не влияют на успешность проверки, трафик манифеста учитывается только в метрике.
+func (c *StreamChecker) recordDASH(stream models.StreamConfig, manifest dashManifest, result *models.CheckResult) {
+	check := &models.DASHCheck{URL: stream.DASHURL, Success: manifest.err == nil}
+	result.DASH = check
+	c.metrics.SetDASHUp(stream.Name, check.Success)
+	c.metrics.AddDownloadedBytes(stream.Name, int64(manifest.bytes))
+	if manifest.err != nil {
+		check.Error = &models.CheckError{Type: models.ErrDASHManifest, Message: manifest.err.Error()}
+		c.metrics.RecordError(stream.Name, string(check.Error.Type))
+		return
+	}
+
+	edge, ok := manifest.mpd.LiveEdge()
+	if !ok {
+		return
+	}
+	check.LiveEdgeLatency = c.now().Sub(edge).Seconds()
+
+	// Отставание HLS известно только для плейлистов с EXT-X-PROGRAM-DATE-TIME
+	if result.LiveEdgeLatency == 0 {
+		return
+	}
+	check.LiveEdgeDivergence = check.LiveEdgeLatency - result.LiveEdgeLatency
+	c.metrics.SetLiveEdgeDivergence(stream.Name, check.LiveEdgeDivergence)
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Live-край: 2024-01-01T12:00:00Z + 4s
+const dashTestMPD = `<MPD type="dynamic" availabilityStartTime="2024-01-01T12:00:00Z">
+  <Period start="PT0S">
+    <AdaptationSet>
+      <SegmentTemplate timescale="1">
+        <SegmentTimeline><S t="0" d="2" r="1"/></SegmentTimeline>
+      </SegmentTemplate>
+    </AdaptationSet>
+  </Period>
+</MPD>`
+
+func TestStreamChecker_RecordDASH(t *testing.T) {
+	const dashURL = "http://example.com/live.mpd"
+	stream := models.StreamConfig{Name: "test_stream", DASHURL: dashURL}
+
+	t.Run("divergence", func(t *testing.T) {
+		client := new(MockHTTPClient)
+		mockMetrics := new(MockMetricsCollector)
+		c := NewStreamChecker(client, new(MockValidator), mockMetrics, 1)
+		c.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC) }
+
+		client.On("GetPlaylist", mock.Anything, dashURL).
+			Return(&models.PlaylistResponse{Body: []byte(dashTestMPD), StatusCode: 200}, nil)
+		mockMetrics.On("SetDASHUp", "test_stream", true).Return()
+		mockMetrics.On("AddDownloadedBytes", "test_stream", int64(len(dashTestMPD))).Return()
+		mockMetrics.On("SetLiveEdgeDivergence", "test_stream", 2.5).Return()
+
+		// HLS отстает на 3.5s, DASH - на 6s
+		result := &models.CheckResult{Success: true, LiveEdgeLatency: 3.5}
+		c.recordDASH(stream, <-c.fetchDASH(context.Background(), dashURL), result)
+
+		mockMetrics.AssertExpectations(t)
+		require.NotNil(t, result.DASH)
+		assert.True(t, result.DASH.Success)
+		assert.Equal(t, 6.0, result.DASH.LiveEdgeLatency)
+		assert.Equal(t, 2.5, result.DASH.LiveEdgeDivergence)
+		assert.True(t, result.Success)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		client := new(MockHTTPClient)
+		mockMetrics := new(MockMetricsCollector)
+		c := NewStreamChecker(client, new(MockValidator), mockMetrics, 1)
+
+		client.On("GetPlaylist", mock.Anything, dashURL).Return(nil, errors.New("unexpected status code: 404"))
+		mockMetrics.On("SetDASHUp", "test_stream", false).Return()
+		mockMetrics.On("AddDownloadedBytes", "test_stream", int64(0)).Return()
+		mockMetrics.On("RecordError", "test_stream", string(models.ErrDASHManifest)).Return()
+
+		result := &models.CheckResult{Success: true, LiveEdgeLatency: 3.5}
+		c.recordDASH(stream, <-c.fetchDASH(context.Background(), dashURL), result)
+
+		mockMetrics.AssertExpectations(t)
+		require.NotNil(t, result.DASH)
+		assert.False(t, result.DASH.Success)
+		require.NotNil(t, result.DASH.Error)
+		assert.Contains(t, result.DASH.Error.Message, "404")
+		assert.True(t, result.Success)
+	})
+}
@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// playlistMarkers число тегов EXT-X-DISCONTINUITY и EXT-X-GAP в окне медиаплейлиста
+type playlistMarkers struct {
+	discontinuities int
+	gaps            int
+}
+
+// countMarkers считает теги разрывов и пропусков в теле медиаплейлиста.
+// EXT-X-GAP библиотекой m3u8 не разбирается, поэтому оба тега считаются по строкам.
+func countMarkers(body []byte) playlistMarkers {
+	var markers playlistMarkers
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		switch string(bytes.TrimSpace(scanner.Bytes())) {
+		case "#EXT-X-DISCONTINUITY":
+			markers.discontinuities++
+		case "#EXT-X-GAP":
+			markers.gaps++
+		}
+	}
+	return markers
+}
+
+// discontinuityTracker запоминает между проверками число разрывов медиаплейлистов
+// от начала трансляции: EXT-X-DISCONTINUITY-SEQUENCE плюс разрывы в окне
+type discontinuityTracker struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+func newDiscontinuityTracker() *discontinuityTracker {
+	return &discontinuityTracker{last: make(map[string]uint64)}
+}
+
+// Observe возвращает число новых разрывов с предыдущей проверки плейлиста.
+// При первой проверке и без EXT-X-DISCONTINUITY-SEQUENCE, когда разрывы
+// уходят из окна, число может быть занижено, но не отрицательно.
+func (t *discontinuityTracker) Observe(stream, playlistURL string, total uint64) int {
+	key := stream + "|" + playlistURL
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.last[key]
+	t.last[key] = total
+	if !ok || total <= prev {
+		return 0
+	}
+	return int(total - prev)
+}
+
+// recordMarkers публикует число разрывов и пропусков в окне медиаплейлиста
+// и учитывает новые разрывы, например после перезапуска кодировщика
+func (c *StreamChecker) recordMarkers(
+	stream models.StreamConfig,
+	variant string,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	markers playlistMarkers,
+	result *models.CheckResult,
+) {
+	c.metrics.SetPlaylistMarkers(stream.Name, variant, markers.discontinuities, markers.gaps)
+	result.Discontinuities += markers.discontinuities
+	result.Gaps += markers.gaps
+
+	total := media.DiscontinuitySeq + uint64(markers.discontinuities)
+	if added := c.markers.Observe(stream.Name, playlistURL, total); added > 0 {
+		c.metrics.AddDiscontinuities(stream.Name, variant, added)
+	}
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const markersPlaylist = `#EXTM3U
+#EXT-X-TARGETDURATION:4
+#EXT-X-MEDIA-SEQUENCE:100
+#EXT-X-DISCONTINUITY-SEQUENCE:7
+#EXTINF:4.0,
+seg100.ts
+#EXT-X-DISCONTINUITY
+#EXTINF:4.0,
+seg101.ts
+#EXT-X-GAP
+#EXTINF:4.0,
+seg102.ts
+#EXT-X-DISCONTINUITY
+#EXTINF:4.0,
+seg103.ts
+`
+
+func TestCountMarkers(t *testing.T) {
+	assert.Equal(t, playlistMarkers{discontinuities: 2, gaps: 1}, countMarkers([]byte(markersPlaylist)))
+	assert.Equal(t, playlistMarkers{}, countMarkers([]byte("#EXTM3U\n#EXT-X-DISCONTINUITY-SEQUENCE:3\n")))
+}
+
+func TestDiscontinuityTracker(t *testing.T) {
+	tracker := newDiscontinuityTracker()
+
+	// Первая проверка только запоминает число разрывов
+	assert.Zero(t, tracker.Observe("s", "v.m3u8", 9))
+	assert.Equal(t, 2, tracker.Observe("s", "v.m3u8", 11))
+	assert.Zero(t, tracker.Observe("s", "v.m3u8", 11))
+	// Разрыв ушел из окна без EXT-X-DISCONTINUITY-SEQUENCE
+	assert.Zero(t, tracker.Observe("s", "v.m3u8", 10))
+	assert.Zero(t, tracker.Observe("s", "other.m3u8", 20))
+}
+
+func TestStreamChecker_RecordMarkers(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	media, err := parseMediaPlaylist([]byte(markersPlaylist))
+	require.NoError(t, err)
+	markers := countMarkers([]byte(markersPlaylist))
+
+	mockMetrics.On("SetPlaylistMarkers", "test_stream", "720p.m3u8", 2, 1).Return()
+	result := &models.CheckResult{}
+	c.recordMarkers(stream, "720p.m3u8", "http://test.com/720p.m3u8", media, markers, result)
+	assert.Equal(t, 2, result.Discontinuities)
+	assert.Equal(t, 1, result.Gaps)
+
+	// Перезапуск кодировщика: новый разрыв в следующем окне
+	next := *media
+	next.DiscontinuitySeq = 8
+	mockMetrics.On("SetPlaylistMarkers", "test_stream", "720p.m3u8", 3, 0).Return()
+	mockMetrics.On("AddDiscontinuities", "test_stream", "720p.m3u8", 2).Return().Once()
+	c.recordMarkers(stream, "720p.m3u8", "http://test.com/720p.m3u8", &next, playlistMarkers{discontinuities: 3}, &models.CheckResult{})
+
+	mockMetrics.AssertExpectations(t)
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Нарушения EVENT-плейлиста, в который можно только добавлять сегменты (RFC 8216, 4.3.3.5)
+const (
+	// Сегменты удалены из начала или конца плейлиста
+	eventViolationRemoved = "removed"
+	// Уже опубликованный сегмент заменен или изменил длительность
+	eventViolationModified = "modified"
+)
+
+// eventTracker запоминает между проверками сегменты EVENT-плейлистов, чтобы обнаружить
+// удаление и изменение опубликованных сегментов: они ломают перемотку DVR у зрителей
+type eventTracker struct {
+	mu    sync.Mutex
+	state map[string]eventState
+}
+
+type eventState struct {
+	sequence uint64
+	entries  []eventEntry
+}
+
+type eventEntry struct {
+	uri      string
+	duration float64
+}
+
+func newEventTracker() *eventTracker {
+	return &eventTracker{state: make(map[string]eventState)}
+}
+
+// Observe сравнивает EVENT-плейлист с предыдущей проверкой и возвращает вид нарушения
+// (пусто - плейлист только дополнился) и его описание. Плейлисты других типов забываются.
+func (t *eventTracker) Observe(stream, playlistURL string, media *m3u8.MediaPlaylist) (string, string) {
+	key := stream + "|" + playlistURL
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if media.MediaType != m3u8.EVENT {
+		delete(t.state, key)
+		return "", ""
+	}
+	current := eventState{sequence: media.SeqNo, entries: make([]eventEntry, 0, media.Count())}
+	for _, seg := range media.Segments {
+		if seg != nil {
+			current.entries = append(current.entries, eventEntry{uri: seg.URI, duration: seg.Duration})
+		}
+	}
+	prev, ok := t.state[key]
+	t.state[key] = current
+	if !ok {
+		return "", ""
+	}
+
+	switch {
+	case current.sequence > prev.sequence:
+		return eventViolationRemoved, fmt.Sprintf("media sequence advanced from %d to %d", prev.sequence, current.sequence)
+	case current.sequence < prev.sequence:
+		return eventViolationModified, fmt.Sprintf("media sequence went back from %d to %d", prev.sequence, current.sequence)
+	case len(current.entries) < len(prev.entries):
+		return eventViolationRemoved, fmt.Sprintf("playlist shrank from %d to %d segments", len(prev.entries), len(current.entries))
+	}
+	for i, entry := range prev.entries {
+		if current.entries[i] != entry {
+			return eventViolationModified, fmt.Sprintf("segment %d changed from %s (%gs) to %s (%gs)",
+				prev.sequence+uint64(i), entry.uri, entry.duration, current.entries[i].uri, current.entries[i].duration)
+		}
+	}
+	return "", ""
+}
+
+// recordEventGrowth проверяет, что EVENT-плейлист только дополняется между проверками.
+// Нарушение не влияет на успешность проверки: добавляется предупреждение
+// event_playlist_modified и учитывается hls_event_playlist_violations_total.
+func (c *StreamChecker) recordEventGrowth(
+	stream models.StreamConfig,
+	variant string,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	result *models.CheckResult,
+) {
+	kind, detail := c.events.Observe(stream.Name, playlistURL, media)
+	if kind == "" {
+		return
+	}
+	c.logger.Warn("EVENT playlist is not append-only",
+		zap.String("check_id", result.CheckID),
+		zap.String("stream", stream.Name),
+		zap.String("url", playlistURL),
+		zap.String("violation", kind),
+		zap.String("detail", detail))
+	c.metrics.RecordEventViolation(stream.Name, variant, kind)
+	result.AddWarning(models.WarningEventModified)
+}
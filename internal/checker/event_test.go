@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventPlaylist собирает EVENT-плейлист из сегментов "uri" или "uri:длительность"
+func eventPlaylist(t *testing.T, seq int, segments ...string) *m3u8.MediaPlaylist {
+	t.Helper()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXT-X-PLAYLIST-TYPE:EVENT\n#EXT-X-MEDIA-SEQUENCE:%d\n", seq)
+	for _, seg := range segments {
+		uri, duration, ok := strings.Cut(seg, ":")
+		if !ok {
+			duration = "4.0"
+		}
+		fmt.Fprintf(&b, "#EXTINF:%s,\n%s\n", duration, uri)
+	}
+	return decodeMediaPlaylist(t, b.String())
+}
+
+func TestEventTracker_Observe(t *testing.T) {
+	tests := []struct {
+		name     string
+		seq      int
+		segments []string
+		want     string
+	}{
+		{name: "first check", segments: []string{"a.ts", "b.ts"}},
+		{name: "unchanged", segments: []string{"a.ts", "b.ts"}},
+		{name: "appended", segments: []string{"a.ts", "b.ts", "c.ts"}},
+		{name: "segment replaced", segments: []string{"a.ts", "x.ts", "c.ts"}, want: eventViolationModified},
+		{name: "duration changed", segments: []string{"a.ts", "x.ts", "c.ts:5.0"}, want: eventViolationModified},
+		{name: "last segment removed", segments: []string{"a.ts", "x.ts"}, want: eventViolationRemoved},
+		{name: "first segment removed", seq: 1, segments: []string{"x.ts", "c.ts"}, want: eventViolationRemoved},
+		{name: "sequence went back", segments: []string{"a.ts", "x.ts", "c.ts"}, want: eventViolationModified},
+		{name: "appended after violation", segments: []string{"a.ts", "x.ts", "c.ts", "d.ts"}},
+	}
+
+	tracker := newEventTracker()
+	const url = "http://a/event.m3u8"
+	for _, tt := range tests {
+		kind, detail := tracker.Observe("test_stream", url, eventPlaylist(t, tt.seq, tt.segments...))
+		assert.Equal(t, tt.want, kind, tt.name)
+		assert.Equal(t, tt.want != "", detail != "", tt.name)
+	}
+
+	// Live-плейлисты с движущимся окном не проверяются, а состояние EVENT забывается
+	kind, _ := tracker.Observe("test_stream", url, windowPlaylist(t, 5, "f.ts"))
+	assert.Empty(t, kind)
+	kind, _ = tracker.Observe("test_stream", url, eventPlaylist(t, 0, "a.ts"))
+	assert.Empty(t, kind)
+}
+
+func TestStreamChecker_RecordEventGrowth(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	mockMetrics.On("RecordEventViolation", "test_stream", "720p.m3u8", eventViolationRemoved).Return()
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+	const url = "http://a/720p.m3u8"
+
+	result := &models.CheckResult{Success: true}
+	c.recordEventGrowth(stream, "720p.m3u8", url, eventPlaylist(t, 0, "a.ts", "b.ts"), result)
+	c.recordEventGrowth(stream, "720p.m3u8", url, eventPlaylist(t, 0, "a.ts", "b.ts", "c.ts"), result)
+	assert.Empty(t, result.Warnings)
+
+	// Упаковщик переключился на движущееся окно: DVR-перемотка к началу сломана
+	result = &models.CheckResult{Success: true}
+	c.recordEventGrowth(stream, "720p.m3u8", url, eventPlaylist(t, 1, "b.ts", "c.ts", "d.ts"), result)
+	assert.Equal(t, []string{models.WarningEventModified}, result.Warnings)
+	assert.True(t, result.Success)
+	mockMetrics.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// SetExternalValidators задает внешние валидаторы, подключаемые к стримам по имени
+func (c *StreamChecker) SetExternalValidators(validators map[string]models.ExternalValidator) {
+	c.external = validators
+}
+
+// validateExternal вызывает внешние валидаторы стрима по порядку до первой ошибки
+func (c *StreamChecker) validateExternal(
+	cfg models.StreamConfig,
+	validate func(models.ExternalValidator) error,
+) error {
+	for _, name := range cfg.Validators {
+		v, ok := c.external[name]
+		if !ok {
+			return fmt.Errorf("validator %s is not loaded", name)
+		}
+		if err := validate(v); err != nil {
+			return fmt.Errorf("validator %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateExternalPlaylist проверяет плейлист внешними валидаторами стрима
+func (c *StreamChecker) validateExternalPlaylist(cfg models.StreamConfig, url string, body []byte) error {
+	return c.validateExternal(cfg, func(v models.ExternalValidator) error {
+		return v.ValidatePlaylist(url, body)
+	})
+}
+
+// validateExternalSegment проверяет сегмент внешними валидаторами стрима
+func (c *StreamChecker) validateExternalSegment(cfg models.StreamConfig, segment *models.SegmentData) error {
+	return c.validateExternal(cfg, func(v models.ExternalValidator) error {
+		return v.ValidateSegment(segment)
+	})
+}
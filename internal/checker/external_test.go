@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// funcValidator внешний валидатор для тестов
+type funcValidator struct {
+	playlist func(url string, body []byte) error
+	segment  func(segment *models.SegmentData) error
+}
+
+func (v funcValidator) ValidatePlaylist(url string, body []byte) error { return v.playlist(url, body) }
+func (v funcValidator) ValidateSegment(segment *models.SegmentData) error {
+	return v.segment(segment)
+}
+
+func TestStreamChecker_ValidateExternal(t *testing.T) {
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), new(MockMetricsCollector), 1)
+
+	var calls []string
+	c.SetExternalValidators(map[string]models.ExternalValidator{
+		"ok": funcValidator{
+			playlist: func(string, []byte) error { calls = append(calls, "ok"); return nil },
+			segment:  func(*models.SegmentData) error { return nil },
+		},
+		"scte": funcValidator{
+			playlist: func(string, []byte) error { calls = append(calls, "scte"); return nil },
+			segment: func(s *models.SegmentData) error {
+				if s.Size < 1000 {
+					return errors.New("segment too small")
+				}
+				return nil
+			},
+		},
+	})
+
+	// Стрим без валидаторов не проверяется
+	assert.NoError(t, c.validateExternalPlaylist(models.StreamConfig{}, "u", nil))
+
+	cfg := models.StreamConfig{Validators: []string{"ok", "scte"}}
+	assert.NoError(t, c.validateExternalPlaylist(cfg, "u", nil))
+	assert.Equal(t, []string{"ok", "scte"}, calls)
+
+	err := c.validateExternalSegment(cfg, &models.SegmentData{Size: 10})
+	assert.EqualError(t, err, "validator scte: segment too small")
+
+	err = c.validateExternalPlaylist(models.StreamConfig{Validators: []string{"missing"}}, "u", nil)
+	assert.EqualError(t, err, "validator missing is not loaded")
+}
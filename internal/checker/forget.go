@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"strings"
+	"sync"
+)
+
+// Forget удаляет состояние стрима, накопленное между проверками: при удалении
+// стрима оно больше не нужно, а повторно добавленный стрим начинает с чистого листа
+func (c *StreamChecker) Forget(name string) {
+	forgetStream(&c.budget.mu, c.budget.usage, name)
+	forgetStream(&c.staleness.mu, c.staleness.state, name)
+	forgetStream(&c.sizeTrend.mu, c.sizeTrend.state, name)
+	forgetStream(&c.looping.mu, c.looping.state, name)
+	forgetStream(&c.sequences.mu, c.sequences.state, name)
+	forgetStream(&c.ages.mu, c.ages.state, name)
+	forgetStream(&c.notFound.mu, c.notFound.state, name)
+	forgetStream(&c.userAgents.mu, c.userAgents.next, name)
+	forgetStream(&c.tagInventory.mu, c.tagInventory.tags, name)
+	forgetStream(&c.pids.mu, c.pids.last, name)
+	forgetStream(&c.markers.mu, c.markers.last, name)
+	forgetStream(&c.events.mu, c.events.state, name)
+	forgetStream(&c.keys.mu, c.keys.entries, name)
+}
+
+// forgetStream удаляет из состояния трекера ключи стрима: name или name|url
+func forgetStream[V any](mu *sync.Mutex, state map[string]V, name string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	prefix := name + "|"
+	for key := range state {
+		if key == name || strings.HasPrefix(key, prefix) {
+			delete(state, key)
+		}
+	}
+}
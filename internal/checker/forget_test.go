@@ -0,0 +1,26 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamChecker_Forget(t *testing.T) {
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), new(MockMetricsCollector), 1)
+	for _, name := range []string{"news", "news_hd"} {
+		c.staleness.state[name+"|http://a/index.m3u8"] = &playlistState{}
+		c.keys.entries[name+"|http://keys/1"] = cachedKey{}
+		c.userAgents.next[name] = 1
+		c.budget.usage[name] = &budgetUsage{}
+	}
+
+	c.Forget("news")
+
+	// Состояние других стримов с общим префиксом имени сохраняется
+	assert.Equal(t, map[string]*playlistState{"news_hd|http://a/index.m3u8": {}}, c.staleness.state)
+	assert.Equal(t, map[string]cachedKey{"news_hd|http://keys/1": {}}, c.keys.entries)
+	assert.Equal(t, map[string]int{"news_hd": 1}, c.userAgents.next)
+	assert.Len(t, c.budget.usage, 1)
+	assert.Contains(t, c.budget.usage, "news_hd")
+}
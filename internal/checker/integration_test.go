@@ -0,0 +1,265 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	client "github.com/iudanet/hls_exporter/internal/http"
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/testorigin"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIntegrationChecker собирает чекер из реальных компонентов против синтетического источника
+func newIntegrationChecker(t *testing.T, cfg testorigin.Config) (*StreamChecker, *testorigin.Origin, string) {
+	t.Helper()
+
+	origin := testorigin.New(cfg)
+	srv := httptest.NewServer(origin)
+	t.Cleanup(srv.Close)
+
+	httpClient := client.NewClient(models.HTTPConfig{Timeout: 5 * time.Second, MaxIdleConns: 10})
+	t.Cleanup(func() { _ = httpClient.Close() })
+
+	c := NewStreamChecker(
+		httpClient,
+		NewHLSValidator(),
+		metrics.NewCollector(prometheus.NewRegistry()),
+		4,
+	)
+	require.NoError(t, c.Start())
+	t.Cleanup(func() { _ = c.Stop() })
+
+	return c, origin, srv.URL
+}
+
+func integrationStream(url string) models.StreamConfig {
+	return models.StreamConfig{
+		Name:            "integration",
+		URL:             url,
+		CheckMode:       models.CheckModeAll,
+		Interval:        time.Second,
+		Timeout:         5 * time.Second,
+		ValidateContent: true,
+	}
+}
+
+func TestIntegration_Healthy(t *testing.T) {
+	c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{DiscontinuityEvery: 3})
+
+	result, err := c.Check(context.Background(), integrationStream(baseURL+origin.MasterURL()))
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 2, result.StreamStatus.VariantsCount)
+	assert.Zero(t, result.Segments.Failed)
+	assert.Positive(t, result.Segments.Checked)
+	assert.Positive(t, result.BytesDownloaded)
+
+	// Источник публикует сегменты по мере их готовности: край отстает не более чем на сегмент
+	assert.Positive(t, result.LiveEdgeLatency)
+	assert.LessOrEqual(t, result.LiveEdgeLatency, 2.5)
+}
+
+func TestIntegration_PIDChanges(t *testing.T) {
+	c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+	stream := integrationStream(baseURL + origin.MasterURL())
+
+	result, err := c.Check(context.Background(), stream)
+	require.NoError(t, err)
+	assert.Empty(t, result.PIDChanges)
+	require.NotEmpty(t, result.Segments.Details)
+	assert.NotEmpty(t, result.Segments.Details[0].Programs)
+
+	// Смена PID не ломает проверку, но фиксируется для каждого варианта
+	origin.SetFaults(testorigin.Faults{RemapPIDs: true})
+	result, err = c.Check(context.Background(), stream)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.Len(t, result.PIDChanges, 2)
+	for _, change := range result.PIDChanges {
+		assert.Equal(t, models.PIDChangeElementary, change.Kind)
+	}
+}
+
+func TestIntegration_StrictConformance(t *testing.T) {
+	cfg := testorigin.DefaultConfig()
+	cfg.DiscontinuityEvery = 3
+	cfg.LowLatency = true
+	cfg.Variants = append(cfg.Variants, testorigin.Variant{Name: "enc", Bandwidth: 500000, Encrypted: true})
+	c, origin, baseURL := newIntegrationChecker(t, cfg)
+
+	stream := integrationStream(baseURL + origin.MasterURL())
+	stream.Strict = true
+	result, err := c.Check(context.Background(), stream)
+	require.NoError(t, err)
+	assert.Empty(t, result.Conformance)
+}
+
+func TestIntegration_MediaPlaylist(t *testing.T) {
+	c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{LowLatency: true})
+
+	result, err := c.Check(context.Background(), integrationStream(baseURL+origin.MediaURL("720p")))
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, result.StreamStatus.VariantsCount)
+}
+
+func TestIntegration_PlaylistOnly(t *testing.T) {
+	c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+
+	stream := integrationStream(baseURL + origin.MasterURL())
+	stream.CheckMode = models.CheckModePlaylistOnly
+	result, err := c.Check(context.Background(), stream)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	// Сегменты не загружаются, hls_segments_count - число сегментов в окнах плейлистов
+	assert.Zero(t, result.Segments.Checked)
+	assert.Positive(t, result.Segments.Listed)
+	assert.Equal(t, result.Segments.Listed, result.StreamStatus.SegmentsCount)
+}
+
+func TestIntegration_EncryptedVariant(t *testing.T) {
+	c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{
+		Variants: []testorigin.Variant{{Name: "enc", Bandwidth: 500_000, Encrypted: true}},
+	})
+
+	stream := integrationStream(baseURL + origin.MasterURL())
+	stream.ValidateContent = false
+
+	result, err := c.Check(context.Background(), stream)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+}
+
+func TestIntegration_Faults(t *testing.T) {
+	tests := []struct {
+		name   string
+		faults testorigin.Faults
+	}{
+		{name: "segment status", faults: testorigin.Faults{SegmentStatus: http.StatusNotFound}},
+		{name: "truncated segments", faults: testorigin.Faults{TruncateSegments: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+			origin.SetFaults(tt.faults)
+
+			result, _ := c.Check(context.Background(), integrationStream(baseURL+origin.MasterURL()))
+			require.NotNil(t, result)
+			assert.False(t, result.Success)
+			assert.Positive(t, result.Segments.Failed)
+		})
+	}
+
+	t.Run("stale playlist", func(t *testing.T) {
+		now := time.Now()
+		clock := func() time.Time { return now }
+		c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+		origin.SetClock(clock)
+		c.staleness.now = clock
+		stream := integrationStream(baseURL + origin.MasterURL())
+
+		result, err := c.Check(context.Background(), stream)
+		require.NoError(t, err)
+		assert.False(t, result.Stale)
+
+		origin.SetFaults(testorigin.Faults{StalePlaylist: true})
+		now = now.Add(10 * time.Second)
+		result, err = c.Check(context.Background(), stream)
+		require.Error(t, err)
+		assert.True(t, result.Stale)
+		assert.False(t, result.Success)
+		require.NotNil(t, result.Error)
+		assert.Equal(t, models.ErrPlaylistStale, result.Error.Type)
+
+		origin.SetFaults(testorigin.Faults{})
+		result, err = c.Check(context.Background(), stream)
+		require.NoError(t, err)
+		assert.False(t, result.Stale)
+	})
+
+	t.Run("dropped packets", func(t *testing.T) {
+		c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+		stream := integrationStream(baseURL + origin.MasterURL())
+
+		result, err := c.Check(context.Background(), stream)
+		require.NoError(t, err)
+		assert.Zero(t, result.CCErrors)
+
+		// Потеря пакетов не ломает загрузку, но видна по счетчикам непрерывности
+		origin.SetFaults(testorigin.Faults{DropPackets: true})
+		result, err = c.Check(context.Background(), stream)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, result.Segments.Checked, result.CCErrors)
+	})
+
+	t.Run("PCR limits", func(t *testing.T) {
+		c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+		stream := integrationStream(baseURL + origin.MasterURL())
+
+		result, err := c.Check(context.Background(), stream)
+		require.NoError(t, err)
+		assert.Positive(t, result.PCRMaxInterval)
+
+		// PCR синтетического источника передаются реже, чем допускает лимит
+		stream.MediaValidation = &models.MediaValidation{
+			ContainerType:  []string{"TS"},
+			MaxPCRInterval: result.PCRMaxInterval / 2,
+		}
+		result, err = c.Check(context.Background(), stream)
+		require.Error(t, err)
+		assert.Equal(t, result.Segments.Checked, result.Segments.Failed)
+	})
+
+	t.Run("playlist status", func(t *testing.T) {
+		c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{})
+		origin.SetFaults(testorigin.Faults{PlaylistStatus: http.StatusServiceUnavailable})
+
+		result, _ := c.Check(context.Background(), integrationStream(baseURL+origin.MasterURL()))
+		require.NotNil(t, result)
+		assert.False(t, result.Success)
+		require.NotNil(t, result.Error)
+		assert.Equal(t, models.ErrPlaylistDownload, result.Error.Type)
+	})
+
+	t.Run("connection refused", func(t *testing.T) {
+		c, origin, _ := newIntegrationChecker(t, testorigin.Config{})
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		require.NoError(t, ln.Close())
+
+		result, _ := c.Check(context.Background(), integrationStream("http://"+addr+origin.MasterURL()))
+		require.NotNil(t, result)
+		assert.False(t, result.Success)
+		require.NotNil(t, result.Error)
+		assert.Equal(t, models.ErrConnectionRefused, result.Error.Type)
+	})
+}
+
+func TestIntegration_EncryptedContent(t *testing.T) {
+	c, origin, baseURL := newIntegrationChecker(t, testorigin.Config{
+		Variants: []testorigin.Variant{{Name: "enc", Bandwidth: 500_000, Encrypted: true}},
+	})
+	c.SetKeyCacheTTL(time.Minute)
+
+	result, err := c.Check(context.Background(), integrationStream(baseURL+origin.MasterURL()))
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.NotEmpty(t, result.Segments.Details)
+	// Программы MPEG-TS видны только в расшифрованном содержимом
+	for _, seg := range result.Segments.Details {
+		assert.NotEmpty(t, seg.Programs)
+	}
+	// Ключ варианта загружен один раз на все сегменты
+	assert.Len(t, c.keys.entries, 1)
+}
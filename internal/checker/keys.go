@@ -0,0 +1,173 @@
+package checker
+
+import (
+	"context"
+	"crypto/aes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// keyMethodAES128 метод шифрования сегментов целиком (RFC 8216, 4.3.2.4)
+const keyMethodAES128 = "AES-128"
+
+// keyCache хранит ключи AES-128 стримов по URI, чтобы не запрашивать сервер ключей
+// для каждого сегмента. Одновременные запросы одного ключа объединяются.
+type keyCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	entries  map[string]cachedKey
+	inflight map[string]*keyFetch
+	now      func() time.Time
+}
+
+type cachedKey struct {
+	key     []byte
+	fetched time.Time
+}
+
+type keyFetch struct {
+	done chan struct{}
+	key  []byte
+	err  error
+}
+
+func newKeyCache() *keyCache {
+	return &keyCache{
+		entries:  make(map[string]cachedKey),
+		inflight: make(map[string]*keyFetch),
+		now:      time.Now,
+	}
+}
+
+// SetKeyCacheTTL задает время хранения ключей AES-128 сегментов (0 - без кэша)
+func (c *StreamChecker) SetKeyCacheTTL(ttl time.Duration) {
+	c.keys.mu.Lock()
+	defer c.keys.mu.Unlock()
+	c.keys.ttl = ttl
+}
+
+// Get возвращает ключ стрима по URI и признак того, что сервер ключей не запрашивался
+func (k *keyCache) Get(
+	ctx context.Context,
+	stream, uri string,
+	fetch func(ctx context.Context, uri string) ([]byte, error),
+) ([]byte, bool, error) {
+	id := stream + "|" + uri
+	k.mu.Lock()
+	if k.ttl <= 0 {
+		k.mu.Unlock()
+		key, err := fetch(ctx, uri)
+		return key, false, err
+	}
+	now := k.now()
+	for u, entry := range k.entries {
+		if now.Sub(entry.fetched) >= k.ttl {
+			delete(k.entries, u)
+		}
+	}
+	if entry, ok := k.entries[id]; ok {
+		k.mu.Unlock()
+		return entry.key, true, nil
+	}
+	if f, ok := k.inflight[id]; ok {
+		k.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.key, true, f.err
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+	f := &keyFetch{done: make(chan struct{})}
+	k.inflight[id] = f
+	k.mu.Unlock()
+
+	f.key, f.err = fetch(ctx, uri)
+
+	k.mu.Lock()
+	delete(k.inflight, id)
+	if f.err == nil {
+		k.entries[id] = cachedKey{key: f.key, fetched: k.now()}
+	}
+	k.mu.Unlock()
+	close(f.done)
+	return f.key, false, f.err
+}
+
+// segmentKey возвращает ключ и IV сегмента, зашифрованного METHOD=AES-128
+func (c *StreamChecker) segmentKey(
+	ctx context.Context,
+	segment *m3u8.MediaSegment,
+	cfg models.StreamConfig,
+) (models.SegmentKey, error) {
+	key, hit, err := c.keys.Get(ctx, cfg.Name, segment.Key.URI, func(ctx context.Context, uri string) ([]byte, error) {
+		return c.fetchKey(ctx, cfg.Name, uri)
+	})
+	c.metrics.RecordKeyCacheRequest(cfg.Name, hit)
+	if err != nil {
+		return models.SegmentKey{}, err
+	}
+	iv, err := segmentIV(segment.Key.IV, segment.SeqId)
+	if err != nil {
+		return models.SegmentKey{}, err
+	}
+	return models.SegmentKey{Key: key, IV: iv}, nil
+}
+
+// fetchKey загружает ключ с сервера ключей
+func (c *StreamChecker) fetchKey(ctx context.Context, stream, uri string) ([]byte, error) {
+	resp, err := c.client.GetKey(ctx, uri)
+	if resp != nil {
+		c.recordResponse(stream, models.ResponseTimeKey, resp.StatusCode, resp.RetryAfter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch key %s: %w", uri, err)
+	}
+	c.metrics.RecordResponseTime(stream, models.ResponseTimeKey, resp.Duration.Seconds(), models.TraceIDFrom(ctx))
+	if len(resp.Body) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid key length %d from %s", len(resp.Body), uri)
+	}
+	return resp.Body, nil
+}
+
+// segmentIV возвращает IV из атрибута EXT-X-KEY, а без него - номер сегмента
+// в виде 16-байтного big-endian числа (RFC 8216, 5.2)
+func segmentIV(attr string, seq uint64) ([]byte, error) {
+	if attr == "" {
+		iv := make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint64(iv[aes.BlockSize-8:], seq)
+		return iv, nil
+	}
+	hexIV := strings.TrimPrefix(strings.TrimPrefix(attr, "0x"), "0X")
+	iv, err := hex.DecodeString(hexIV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid IV: %s", attr)
+	}
+	return iv, nil
+}
+
+// inheritKeys распространяет EXT-X-KEY на следующие за тегом сегменты: парсер
+// указывает ключ только у первого из них. URI ключа приводится к абсолютному.
+func inheritKeys(playlistURL string, segments []*m3u8.MediaSegment) {
+	var current *m3u8.Key
+	for _, seg := range segments {
+		if seg == nil {
+			continue
+		}
+		if seg.Key != nil {
+			key := *seg.Key
+			if key.URI != "" {
+				key.URI = resolveURL(playlistURL, key.URI)
+			}
+			current = &key
+		}
+		seg.Key = current
+	}
+}
@@ -0,0 +1,168 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCache_Get(t *testing.T) {
+	cache := newKeyCache()
+	cache.ttl = time.Minute
+	now := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return now }
+
+	var fetches atomic.Int32
+	fetch := func(_ context.Context, uri string) ([]byte, error) {
+		fetches.Add(1)
+		if uri == "http://keys/bad" {
+			return nil, errors.New("unexpected status code: 403")
+		}
+		return []byte(uri), nil
+	}
+
+	key, hit, err := cache.Get(context.Background(), "stream", "http://keys/1", fetch)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, []byte("http://keys/1"), key)
+
+	_, hit, err = cache.Get(context.Background(), "stream", "http://keys/1", fetch)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.EqualValues(t, 1, fetches.Load())
+
+	// Ошибки не кэшируются
+	_, _, err = cache.Get(context.Background(), "stream", "http://keys/bad", fetch)
+	assert.Error(t, err)
+	_, hit, err = cache.Get(context.Background(), "stream", "http://keys/bad", fetch)
+	assert.Error(t, err)
+	assert.False(t, hit)
+
+	// Истекший ключ загружается заново
+	now = now.Add(time.Minute)
+	_, hit, err = cache.Get(context.Background(), "stream", "http://keys/1", fetch)
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.EqualValues(t, 4, fetches.Load())
+}
+
+func TestKeyCache_Disabled(t *testing.T) {
+	cache := newKeyCache()
+	var fetches int
+	fetch := func(context.Context, string) ([]byte, error) {
+		fetches++
+		return []byte("key"), nil
+	}
+
+	for range 3 {
+		_, hit, err := cache.Get(context.Background(), "stream", "http://keys/1", fetch)
+		require.NoError(t, err)
+		assert.False(t, hit)
+	}
+	assert.Equal(t, 3, fetches)
+	assert.Empty(t, cache.entries)
+}
+
+func TestKeyCache_ConcurrentFetch(t *testing.T) {
+	cache := newKeyCache()
+	cache.ttl = time.Minute
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	fetch := func(context.Context, string) ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		return []byte("key"), nil
+	}
+
+	hits := make(chan bool, 4)
+	for range 4 {
+		go func() {
+			_, hit, err := cache.Get(context.Background(), "stream", "http://keys/1", fetch)
+			assert.NoError(t, err)
+			hits <- hit
+		}()
+	}
+	require.Eventually(t, func() bool {
+		cache.mu.Lock()
+		defer cache.mu.Unlock()
+		return len(cache.inflight) == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	misses := 0
+	for range 4 {
+		if !<-hits {
+			misses++
+		}
+	}
+	assert.Equal(t, 1, misses)
+	assert.EqualValues(t, 1, fetches.Load())
+}
+
+func TestSegmentIV(t *testing.T) {
+	iv, err := segmentIV("", 258)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2}, iv)
+
+	iv, err = segmentIV("0x000102030405060708090A0B0C0D0E0F", 258)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, iv)
+
+	_, err = segmentIV("0x0102", 0)
+	assert.Error(t, err)
+}
+
+func TestInheritKeys(t *testing.T) {
+	segments := []*m3u8.MediaSegment{
+		{URI: "seg0.ts"},
+		{URI: "seg1.ts", Key: &m3u8.Key{Method: keyMethodAES128, URI: "key1.bin"}},
+		{URI: "seg2.ts"},
+		{URI: "seg3.ts", Key: &m3u8.Key{Method: "NONE"}},
+		{URI: "seg4.ts"},
+		nil,
+	}
+	inheritKeys("http://test.com/live/stream.m3u8", segments)
+
+	assert.Nil(t, segments[0].Key)
+	require.NotNil(t, segments[2].Key)
+	assert.Equal(t, "http://test.com/live/key1.bin", segments[1].Key.URI)
+	assert.Equal(t, "http://test.com/live/key1.bin", segments[2].Key.URI)
+	assert.Equal(t, "NONE", segments[4].Key.Method)
+}
+
+func TestStreamChecker_KeyFetchError(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, NewHLSValidator(), mockMetrics, 1)
+
+	mockClient.On("GetKey", mock.Anything, "http://test.com/key.bin").Return(
+		&models.PlaylistResponse{Body: []byte("short"), StatusCode: 200}, nil)
+	mockMetrics.On("RecordKeyCacheRequest", "test_stream", false).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", models.ResponseTimeKey, 200).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", models.ResponseTimeKey, mock.Anything, mock.Anything).Return()
+
+	segment := &m3u8.MediaSegment{
+		URI: "http://test.com/seg1.ts",
+		Key: &m3u8.Key{Method: keyMethodAES128, URI: "http://test.com/key.bin"},
+	}
+	check := checker.checkSegment(context.Background(), segment, models.StreamConfig{
+		Name:            "test_stream",
+		ValidateContent: true,
+	})
+
+	assert.False(t, check.Success)
+	require.NotNil(t, check.Error)
+	assert.Equal(t, models.ErrKeyFetch, check.Error.Type)
+	assert.Contains(t, check.Error.Message, "invalid key length 5")
+	mockClient.AssertNotCalled(t, "GetSegment", mock.Anything, mock.Anything, mock.Anything)
+	mockMetrics.AssertExpectations(t)
+}
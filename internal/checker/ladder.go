@@ -0,0 +1,85 @@
+package checker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// checkLadder сравнивает варианты мастер-плейлиста с ожидаемой лестницей стрима.
+// Наборы разрешений и кодеков должны совпадать с ожидаемыми без учета порядка и повторов.
+func checkLadder(master *m3u8.MasterPlaylist, stream models.StreamConfig) error {
+	var (
+		count       int
+		resolutions []string
+		codecs      []string
+	)
+	for _, variant := range master.Variants {
+		if variant == nil {
+			continue
+		}
+		count++
+		if variant.Resolution != "" {
+			resolutions = append(resolutions, variant.Resolution)
+		}
+		codecs = append(codecs, codecFamilies(variant.Codecs)...)
+	}
+
+	var problems []string
+	if stream.ExpectVariants > 0 && count != stream.ExpectVariants {
+		problems = append(problems, fmt.Sprintf("expected %d variants, got %d", stream.ExpectVariants, count))
+	}
+	if len(stream.ExpectResolutions) > 0 {
+		problems = append(problems, compareSet("resolutions", stream.ExpectResolutions, resolutions)...)
+	}
+	if len(stream.ExpectCodecs) > 0 {
+		expected := make([]string, 0, len(stream.ExpectCodecs))
+		for _, codec := range stream.ExpectCodecs {
+			expected = append(expected, strings.ToLower(codec))
+		}
+		problems = append(problems, compareSet("codecs", expected, codecs)...)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("ladder mismatch: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// codecFamilies возвращает семейства кодеков атрибута CODECS: "avc1.64001f,mp4a.40.2" -> avc1, mp4a
+func codecFamilies(codecs string) []string {
+	var families []string
+	for codec := range strings.SplitSeq(codecs, ",") {
+		family, _, _ := strings.Cut(strings.TrimSpace(codec), ".")
+		if family != "" {
+			families = append(families, strings.ToLower(family))
+		}
+	}
+	return families
+}
+
+// compareSet описывает отсутствующие и лишние значения набора
+func compareSet(name string, expected, actual []string) []string {
+	var problems []string
+	if missing := setDiff(expected, actual); len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing %s: %s", name, strings.Join(missing, ", ")))
+	}
+	if unexpected := setDiff(actual, expected); len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("unexpected %s: %s", name, strings.Join(unexpected, ", ")))
+	}
+	return problems
+}
+
+// setDiff возвращает отсортированные уникальные значения a, которых нет в b
+func setDiff(a, b []string) []string {
+	var diff []string
+	for _, v := range a {
+		if !slices.Contains(b, v) && !slices.Contains(diff, v) {
+			diff = append(diff, v)
+		}
+	}
+	slices.Sort(diff)
+	return diff
+}
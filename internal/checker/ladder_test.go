@@ -0,0 +1,114 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const ladderMaster = `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=5000000,RESOLUTION=1920x1080,CODECS="avc1.640028,mp4a.40.2"
+1080p.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2000000,RESOLUTION=1280x720,CODECS="avc1.64001f,mp4a.40.2"
+720p.m3u8
+`
+
+func TestCheckLadder(t *testing.T) {
+	playlist, _, err := parsePlaylist([]byte(ladderMaster))
+	require.NoError(t, err)
+	master := playlist.(*m3u8.MasterPlaylist)
+
+	tests := []struct {
+		name   string
+		stream models.StreamConfig
+		errMsg string
+	}{
+		{name: "no expectations"},
+		{
+			name: "match",
+			stream: models.StreamConfig{
+				ExpectVariants:    2,
+				ExpectResolutions: []string{"1280x720", "1920x1080"},
+				ExpectCodecs:      []string{"mp4a", "AVC1"},
+			},
+		},
+		{
+			name:   "variants count",
+			stream: models.StreamConfig{ExpectVariants: 3},
+			errMsg: "expected 3 variants, got 2",
+		},
+		{
+			name:   "missing resolution",
+			stream: models.StreamConfig{ExpectResolutions: []string{"1920x1080", "1280x720", "640x360"}},
+			errMsg: "missing resolutions: 640x360",
+		},
+		{
+			name:   "unexpected codec",
+			stream: models.StreamConfig{ExpectCodecs: []string{"avc1"}},
+			errMsg: "unexpected codecs: mp4a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLadder(master, tt.stream)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestCodecFamilies(t *testing.T) {
+	assert.Equal(t, []string{"avc1", "mp4a"}, codecFamilies("avc1.64001f, mp4a.40.2"))
+	assert.Equal(t, []string{"hvc1"}, codecFamilies("HVC1.1.6.L93.B0"))
+	assert.Empty(t, codecFamilies(""))
+}
+
+func TestStreamChecker_Check_LadderMismatch(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+
+	mockClient.On("GetPlaylist", mock.Anything, "http://test.com/master.m3u8").Return(
+		&models.PlaylistResponse{Body: []byte(ladderMaster), StatusCode: 200}, nil)
+	mockValidator.On("ValidateMaster", mock.AnythingOfType("*m3u8.MasterPlaylist")).Return(nil)
+
+	mockMetrics.On("SetStreamUp", "test_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", mock.Anything, mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "test_stream", mock.AnythingOfType("time.Time")).Return()
+	mockMetrics.On("SetSegmentsCount", "test_stream", mock.AnythingOfType("int")).Return()
+	mockMetrics.On("SetActiveChecks", mock.AnythingOfType("int")).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "test_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordError", "test_stream", string(models.ErrLadderMismatch)).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", mock.AnythingOfType("int64")).Return()
+
+	result, err := c.Check(context.Background(), models.StreamConfig{
+		Name:           "test_stream",
+		URL:            "http://test.com/master.m3u8",
+		CheckMode:      models.CheckModeAll,
+		ExpectVariants: 5,
+	})
+
+	assert.Error(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, models.ErrLadderMismatch, result.Error.Type)
+	// Медиаплейлисты вариантов не загружаются
+	mockClient.AssertNumberOfCalls(t, "GetPlaylist", 1)
+	mockMetrics.AssertExpectations(t)
+}
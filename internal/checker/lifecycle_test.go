@@ -16,7 +16,10 @@ func TestStreamChecker_Lifecycle(t *testing.T) {
 	// Добавляем ожидания для новых методов
 	mockClient.On("SetTimeout", mock.Anything).Return()
 	mockMetrics.On("RecordSegmentCheck", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckResult", mock.Anything, mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetStreamBitrate", mock.Anything, mock.Anything).Return()
 	mockClient.On("Close").Return(nil)
 	tests := []struct {
@@ -77,7 +80,10 @@ func TestStreamChecker_MultipleStartStop(t *testing.T) {
 	// Добавляем ожидания для новых методов
 	mockClient.On("SetTimeout", mock.Anything).Return()
 	mockMetrics.On("RecordSegmentCheck", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckResult", mock.Anything, mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
 	mockMetrics.On("SetStreamBitrate", mock.Anything, mock.Anything).Return()
 
 	mockClient.On("Close").Return(nil)
@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// mediaVariantLabel метка варианта для потока без мастер-плейлиста
+const mediaVariantLabel = "media"
+
+// variantLabel возвращает значение метки variant: URI варианта без query и фрагмента,
+// чтобы токены и идентификаторы сессий в URI не порождали новые серии метрик
+func variantLabel(uri string) string {
+	if i := strings.IndexAny(uri, "?#"); i >= 0 {
+		return uri[:i]
+	}
+	return uri
+}
+
+// liveEdgeLatency вычисляет отставание конца последнего сегмента от now.
+// Для VOD и плейлистов без PROGRAM-DATE-TIME возвращает false.
+func liveEdgeLatency(media *m3u8.MediaPlaylist, now time.Time) (float64, bool) {
+	end, ok := liveEdgeTime(media)
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(end).Seconds(), true
+}
+
+// liveEdgeTime возвращает время конца последнего сегмента по часам упаковщика:
+// от последнего EXT-X-PROGRAM-DATE-TIME с учетом длительностей следующих за ним сегментов.
+// Для VOD и плейлистов без PROGRAM-DATE-TIME возвращает false.
+func liveEdgeTime(media *m3u8.MediaPlaylist) (time.Time, bool) {
+	if media == nil || isVOD(media) {
+		return time.Time{}, false
+	}
+
+	count := int(media.Count())
+	for i := count - 1; i >= 0; i-- {
+		seg := media.Segments[i]
+		if seg == nil || seg.ProgramDateTime.IsZero() {
+			continue
+		}
+
+		end := seg.ProgramDateTime
+		for j := i; j < count; j++ {
+			if media.Segments[j] != nil {
+				end = end.Add(time.Duration(media.Segments[j].Duration * float64(time.Second)))
+			}
+		}
+		return end, true
+	}
+
+	return time.Time{}, false
+}
+
+// recordLiveEdge экспортирует отставание live-края варианта и запоминает наибольшее в результате
+func (c *StreamChecker) recordLiveEdge(
+	stream models.StreamConfig,
+	variant string,
+	media *m3u8.MediaPlaylist,
+	result *models.CheckResult,
+) {
+	latency, ok := liveEdgeLatency(media, c.now())
+	if !ok {
+		return
+	}
+
+	c.metrics.SetLiveEdgeLatency(stream.Name, variant, latency)
+	if latency > result.LiveEdgeLatency {
+		result.LiveEdgeLatency = latency
+	}
+}
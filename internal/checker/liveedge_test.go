@@ -0,0 +1,118 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeMediaPlaylist(t *testing.T, body string) *m3u8.MediaPlaylist {
+	t.Helper()
+
+	p, listType, err := m3u8.DecodeFrom(strings.NewReader(body), false)
+	require.NoError(t, err)
+	require.Equal(t, m3u8.MEDIA, listType)
+	return p.(*m3u8.MediaPlaylist)
+}
+
+func TestLiveEdgeLatency(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "pdt on every segment",
+			body: `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T12:00:12Z
+#EXTINF:6.0,
+seg1.ts
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T12:00:18Z
+#EXTINF:6.0,
+seg2.ts
+`,
+			want: 6,
+		},
+		{
+			name: "pdt extrapolated from earlier segment",
+			body: `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T12:00:06Z
+#EXTINF:6.0,
+seg1.ts
+#EXTINF:6.0,
+seg2.ts
+#EXTINF:4.0,
+seg3.ts
+`,
+			want: 8,
+		},
+		{
+			name: "no pdt",
+			body: `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXTINF:6.0,
+seg1.ts
+`,
+			wantErr: true,
+		},
+		{
+			name: "vod",
+			body: `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T12:00:06Z
+#EXTINF:6.0,
+seg1.ts
+#EXT-X-ENDLIST
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latency, ok := liveEdgeLatency(decodeMediaPlaylist(t, tt.body), now)
+			assert.Equal(t, !tt.wantErr, ok)
+			assert.InDelta(t, tt.want, latency, 0.001)
+		})
+	}
+}
+
+func TestVariantLabel(t *testing.T) {
+	assert.Equal(t, "720p/index.m3u8", variantLabel("720p/index.m3u8"))
+	// Токены и идентификаторы сессий не попадают в метку
+	assert.Equal(t, "720p/index.m3u8", variantLabel("720p/index.m3u8?token=abc&session=1"))
+	assert.Equal(t, "http://cdn/720p.m3u8", variantLabel("http://cdn/720p.m3u8#t=10"))
+}
+
+func TestStreamChecker_RecordLiveEdge(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	c.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC) }
+
+	media := decodeMediaPlaylist(t, `#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-PROGRAM-DATE-TIME:2024-01-01T12:00:20Z
+#EXTINF:6.0,
+seg1.ts
+`)
+	mockMetrics.On("SetLiveEdgeLatency", "test_stream", "720p.m3u8", mock.MatchedBy(func(v float64) bool {
+		return v > 3.99 && v < 4.01
+	})).Return()
+
+	result := &models.CheckResult{}
+	c.recordLiveEdge(models.StreamConfig{Name: "test_stream"}, "720p.m3u8", media, result)
+
+	mockMetrics.AssertExpectations(t)
+	assert.InDelta(t, 4, result.LiveEdgeLatency, 0.01)
+}
@@ -0,0 +1,109 @@
+package checker
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+const (
+	// loopHistory число последних окон плейлиста, среди которых ищутся повторы.
+	// Цикл заставки длиннее loopHistory проверок не обнаруживается.
+	loopHistory = 128
+	// loopRepeatChecks проверок подряд с повтором окна до срабатывания
+	loopRepeatChecks = 3
+)
+
+// loopTracker запоминает между проверками хэши окон медиаплейлистов, чтобы
+// обнаружить источник, циклически повторяющий один и тот же набор сегментов
+type loopTracker struct {
+	mu    sync.Mutex
+	state map[string]*loopState
+}
+
+type loopState struct {
+	// windows хэши и media sequence последних окон, не больше loopHistory
+	windows []playlistWindow
+	// repeats проверки подряд, окно которых уже встречалось с другим media sequence
+	repeats int
+}
+
+type playlistWindow struct {
+	hash     uint64
+	sequence uint64
+}
+
+func newLoopTracker() *loopTracker {
+	return &loopTracker{state: make(map[string]*loopState)}
+}
+
+// Observe сравнивает окно плейлиста с предыдущими и сообщает, что плейлист продвигается,
+// но повторяет уже встречавшиеся окна loopRepeatChecks проверок подряд
+func (l *loopTracker) Observe(stream, playlistURL string, media *m3u8.MediaPlaylist) bool {
+	current := playlistWindow{hash: windowHash(media), sequence: media.SeqNo}
+	key := stream + "|" + playlistURL
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st, ok := l.state[key]
+	if !ok {
+		st = &loopState{}
+		l.state[key] = st
+	}
+
+	// Непродвинувшийся плейлист - признак зависания, а не цикла
+	if n := len(st.windows); n > 0 && st.windows[n-1] == current {
+		return st.repeats >= loopRepeatChecks
+	}
+
+	repeated := false
+	for _, w := range st.windows {
+		if w.hash == current.hash && w.sequence != current.sequence {
+			repeated = true
+			break
+		}
+	}
+	if repeated {
+		st.repeats++
+	} else {
+		st.repeats = 0
+	}
+
+	st.windows = append(st.windows, current)
+	if extra := len(st.windows) - loopHistory; extra > 0 {
+		st.windows = append(st.windows[:0], st.windows[extra:]...)
+	}
+	return st.repeats >= loopRepeatChecks
+}
+
+// windowHash хэширует URI сегментов окна плейлиста
+func windowHash(media *m3u8.MediaPlaylist) uint64 {
+	h := fnv.New64a()
+	for _, seg := range media.Segments {
+		if seg == nil {
+			continue
+		}
+		_, _ = h.Write([]byte(seg.URI))
+		_, _ = h.Write([]byte{'\n'})
+	}
+	return h.Sum64()
+}
+
+// recordLooping добавляет предупреждение content_looping, если live-плейлист
+// циклически повторяет одни и те же сегменты. VOD-плейлисты не проверяются.
+func (c *StreamChecker) recordLooping(
+	stream models.StreamConfig,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	result *models.CheckResult,
+) {
+	if media == nil || isVOD(media) || media.Count() == 0 {
+		return
+	}
+	if c.looping.Observe(stream.Name, playlistURL, media) {
+		result.AddWarning(models.WarningContentLooping)
+	}
+}
@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// windowPlaylist возвращает live-плейлист с media sequence seq и сегментами uris
+func windowPlaylist(t *testing.T, seq int, uris ...string) *m3u8.MediaPlaylist {
+	t.Helper()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:4\n#EXT-X-MEDIA-SEQUENCE:%d\n", seq)
+	for _, uri := range uris {
+		fmt.Fprintf(&b, "#EXTINF:4.0,\n%s\n", uri)
+	}
+	return decodeMediaPlaylist(t, b.String())
+}
+
+func TestLoopTracker_Observe(t *testing.T) {
+	tracker := newLoopTracker()
+	const url = "http://a/index.m3u8"
+
+	// Обычный live: окна не повторяются
+	for seq := range 10 {
+		media := windowPlaylist(t, seq, fmt.Sprintf("seg%d.ts", seq), fmt.Sprintf("seg%d.ts", seq+1))
+		assert.False(t, tracker.Observe("live", url, media))
+	}
+
+	// Заставка из трех сегментов по кругу
+	slate := []string{"slate0.ts", "slate1.ts", "slate2.ts"}
+	window := func(seq int) *m3u8.MediaPlaylist {
+		return windowPlaylist(t, seq, slate[seq%3], slate[(seq+1)%3])
+	}
+	for seq := range 3 {
+		assert.False(t, tracker.Observe("slate", url, window(seq)), "first cycle")
+	}
+	assert.False(t, tracker.Observe("slate", url, window(3)))
+	assert.False(t, tracker.Observe("slate", url, window(4)))
+	assert.True(t, tracker.Observe("slate", url, window(5)))
+
+	// Повторное наблюдение того же окна сохраняет состояние
+	assert.True(t, tracker.Observe("slate", url, window(5)))
+	assert.False(t, tracker.Observe("slate", "http://b/index.m3u8", window(5)), "variants are tracked separately")
+
+	// Новые сегменты сбрасывают повторы
+	assert.False(t, tracker.Observe("slate", url, windowPlaylist(t, 6, "live0.ts", "live1.ts")))
+}
+
+func TestStreamChecker_RecordLooping(t *testing.T) {
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), new(MockMetricsCollector), 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	var result *models.CheckResult
+	for seq := range 6 {
+		result = &models.CheckResult{Success: true}
+		c.recordLooping(stream, "http://a/index.m3u8", windowPlaylist(t, seq, "slate.ts"), result)
+	}
+	assert.Equal(t, []string{models.WarningContentLooping}, result.Warnings)
+	assert.True(t, result.Success)
+
+	// VOD-плейлист не проверяется
+	vod := windowPlaylist(t, 0, "a.ts")
+	vod.Closed = true
+	result = &models.CheckResult{}
+	for range 5 {
+		c.recordLooping(stream, "http://a/vod.m3u8", vod, result)
+	}
+	assert.Empty(t, result.Warnings)
+}
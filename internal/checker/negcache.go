@@ -0,0 +1,128 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// negativeCacheFactor сегмент проверяется на negative caching CDN, если отвечает 404
+	// дольше negativeCacheFactor своих длительностей
+	negativeCacheFactor = 2
+	// negativeCacheTTL сегменты, не встречавшиеся дольше, забываются: они ушли из окна плейлиста
+	negativeCacheTTL = 10 * time.Minute
+	// cacheBustParam параметр запроса, обходящий кеш CDN при диагностике
+	cacheBustParam = "hls_exporter_nocache"
+)
+
+// notFoundTracker запоминает между проверками, с какого момента сегменты отвечают 404
+type notFoundTracker struct {
+	mu    sync.Mutex
+	state map[string]notFoundState
+	now   func() time.Time
+}
+
+type notFoundState struct {
+	first time.Time
+	last  time.Time
+}
+
+func newNotFoundTracker() *notFoundTracker {
+	return &notFoundTracker{
+		state: make(map[string]notFoundState),
+		now:   time.Now,
+	}
+}
+
+// Observe отмечает ответ 404 сегмента и возвращает, сколько времени сегмент уже отвечает 404
+func (t *notFoundTracker) Observe(stream, segmentURL string) time.Duration {
+	key := stream + "|" + segmentURL
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k, st := range t.state {
+		if now.Sub(st.last) > negativeCacheTTL {
+			delete(t.state, k)
+		}
+	}
+	st, ok := t.state[key]
+	if !ok {
+		st.first = now
+	}
+	st.last = now
+	t.state[key] = st
+	return now.Sub(st.first)
+}
+
+// Forget удаляет сегмент, ответивший без ошибки
+func (t *notFoundTracker) Forget(stream, segmentURL string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.state, stream+"|"+segmentURL)
+}
+
+// cacheBustURL добавляет к URL сегмента случайный параметр запроса
+func cacheBustURL(segmentURL string) (string, error) {
+	u, err := url.Parse(segmentURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set(cacheBustParam, newCheckID())
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// diagnoseNotFound выясняет причину ответа 404 сегмента. Если сегмент отвечает 404 давно,
+// он повторно запрашивается с параметром в обход кеша CDN: успешный ответ означает, что CDN
+// закешировал 404, полученный до появления сегмента на origin (negative caching).
+// Возвращает причину или пустую строку.
+func (c *StreamChecker) diagnoseNotFound(
+	ctx context.Context,
+	segment *m3u8.MediaSegment,
+	cfg models.StreamConfig,
+) string {
+	notFoundFor := c.notFound.Observe(cfg.Name, segment.URI)
+	if notFoundFor < time.Duration(segment.Duration*negativeCacheFactor*float64(time.Second)) {
+		return ""
+	}
+
+	bustURL, err := cacheBustURL(segment.URI)
+	if err != nil {
+		return ""
+	}
+	if _, err := c.client.GetSegment(ctx, bustURL, false); err != nil {
+		return ""
+	}
+
+	c.logger.Warn("Segment 404 is cached by CDN",
+		zap.String("check_id", models.CheckIDFrom(ctx)),
+		zap.String("url", segment.URI),
+		zap.Duration("not_found_for", notFoundFor))
+	c.metrics.RecordErrorCause(cfg.Name, models.CauseCDNNegativeCache)
+	return models.CauseCDNNegativeCache
+}
+
+// segmentFailureCause возвращает первую диагностированную причину ошибок сегментов
+func segmentFailureCause(details []models.SegmentCheck) string {
+	for _, seg := range details {
+		if seg.Error != nil && seg.Error.Cause != "" {
+			return seg.Error.Cause
+		}
+	}
+	return ""
+}
+
+// isNotFound сообщает, что сегмент ответил 404
+func isNotFound(resp *models.SegmentResponse) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
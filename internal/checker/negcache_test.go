@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotFoundTracker_Observe(t *testing.T) {
+	tracker := newNotFoundTracker()
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+
+	assert.Zero(t, tracker.Observe("test_stream", "http://a/seg1.ts"))
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, 10*time.Second, tracker.Observe("test_stream", "http://a/seg1.ts"))
+	assert.Zero(t, tracker.Observe("other_stream", "http://a/seg1.ts"), "streams are tracked separately")
+
+	tracker.Forget("test_stream", "http://a/seg1.ts")
+	assert.Zero(t, tracker.Observe("test_stream", "http://a/seg1.ts"))
+
+	// Сегмент, ушедший из окна, забывается
+	now = now.Add(negativeCacheTTL + time.Second)
+	assert.Zero(t, tracker.Observe("other_stream", "http://a/seg1.ts"))
+}
+
+func TestCacheBustURL(t *testing.T) {
+	got, err := cacheBustURL("http://cdn.example.com/live/seg1.ts?token=abc")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "http://cdn.example.com/live/seg1.ts?"), got)
+	assert.Contains(t, got, "token=abc")
+	assert.Contains(t, got, cacheBustParam+"=")
+}
+
+func TestStreamChecker_NegativeCache(t *testing.T) {
+	const segURL = "http://cdn.example.com/live/seg1.ts"
+	notFound := &models.SegmentResponse{StatusCode: http.StatusNotFound}
+	isBust := func(url string) bool { return strings.Contains(url, cacheBustParam) }
+
+	tests := []struct {
+		name      string
+		bustErr   error
+		wantCause string
+	}{
+		{name: "cached by CDN", wantCause: models.CauseCDNNegativeCache},
+		{name: "missing on origin", bustErr: errors.New("unexpected status code: 404")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			mockClient.On("GetSegment", mock.Anything, segURL, false).
+				Return(notFound, errors.New("unexpected status code: 404"))
+			mockClient.On("GetSegment", mock.Anything, mock.MatchedBy(isBust), false).
+				Return(&models.SegmentResponse{StatusCode: http.StatusOK}, tt.bustErr)
+			mockMetrics := new(MockMetricsCollector)
+			mockMetrics.On("RecordErrorCause", "test_stream", models.CauseCDNNegativeCache).Return()
+			mockMetrics.On("RecordHTTPResponse", "test_stream", models.ResponseTimeSegment, http.StatusNotFound).Return()
+
+			c := NewStreamChecker(mockClient, new(MockValidator), mockMetrics, 1)
+			now := time.Unix(1_700_000_000, 0)
+			c.notFound.now = func() time.Time { return now }
+			stream := models.StreamConfig{Name: "test_stream"}
+			segment := &m3u8.MediaSegment{URI: segURL, Duration: 4}
+
+			// Первый 404: сегмент мог еще не появиться на origin
+			check := c.checkSegment(context.Background(), segment, stream)
+			assert.False(t, check.Success)
+			assert.Equal(t, http.StatusNotFound, check.Error.StatusCode)
+			assert.Empty(t, check.Error.Cause)
+			mockClient.AssertNotCalled(t, "GetSegment", mock.Anything, mock.MatchedBy(isBust), false)
+
+			// 404 дольше двух длительностей сегмента: запрос в обход кеша
+			now = now.Add(10 * time.Second)
+			check = c.checkSegment(context.Background(), segment, stream)
+			assert.False(t, check.Success)
+			assert.Equal(t, tt.wantCause, check.Error.Cause)
+			assert.Equal(t, tt.wantCause, segmentFailureCause([]models.SegmentCheck{{}, check}))
+		})
+	}
+}
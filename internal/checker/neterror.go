@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// networkErrorType определяет тип сетевой ошибки запроса: DNS, отказ или сброс соединения,
+// TLS-рукопожатие, таймаут. Прочие ошибки (коды ответа HTTP, чтение тела) получают fallback.
+func networkErrorType(err error, fallback models.ErrorType) models.ErrorType {
+	var (
+		dnsErr     *net.DNSError
+		certErr    *tls.CertificateVerificationError
+		recordErr  tls.RecordHeaderError
+		alertErr   tls.AlertError
+		unknownCA  x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+		netErr     net.Error
+	)
+	switch {
+	case errors.As(err, &dnsErr):
+		return models.ErrDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return models.ErrConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return models.ErrConnectionReset
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &alertErr),
+		errors.As(err, &unknownCA), errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return models.ErrTLSHandshake
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return models.ErrTimeout
+	}
+	return fallback
+}
+
+// newCheckError создает ошибку запроса с кодом ответа HTTP (0 - ответа нет)
+// и признаком временной ошибки
+func newCheckError(errType models.ErrorType, err error, statusCode int) *models.CheckError {
+	return &models.CheckError{
+		Type:       errType,
+		Message:    err.Error(),
+		StatusCode: statusCode,
+		Retryable:  retryable(errType, statusCode),
+	}
+}
+
+// retryable сообщает, что ошибка временная и запрос имеет смысл повторить:
+// таймаут, отказ или сброс соединения, ответы 408, 429 и 5xx
+func retryable(errType models.ErrorType, statusCode int) bool {
+	switch errType {
+	case models.ErrTimeout, models.ErrConnectionRefused, models.ErrConnectionReset:
+		return true
+	}
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
+// segmentRetryAfter возвращает наибольшую паузу Retry-After среди сегментов
+func segmentRetryAfter(details []models.SegmentCheck) time.Duration {
+	var longest time.Duration
+	for _, seg := range details {
+		if seg.Error != nil {
+			longest = max(longest, seg.Error.RetryAfter)
+		}
+	}
+	return longest
+}
+
+// segmentStatusError возвращает ошибку первого сегмента, ответившего кодом не 200
+func segmentStatusError(details []models.SegmentCheck) *models.CheckError {
+	for _, seg := range details {
+		if seg.Error != nil && seg.Error.StatusCode != 0 {
+			return seg.Error
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dialError ошибка запроса в том виде, в каком ее возвращает net/http
+func dialError(err error) error {
+	return fmt.Errorf("do request: %w", &url.Error{
+		Op:  "Get",
+		URL: "http://origin.test/master.m3u8",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Err: err},
+	})
+}
+
+func TestNetworkErrorType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want models.ErrorType
+	}{
+		{
+			name: "dns",
+			err:  dialError(&net.DNSError{Err: "no such host", Name: "origin.test", IsNotFound: true}),
+			want: models.ErrDNS,
+		},
+		{
+			name: "connection refused",
+			err:  dialError(os.NewSyscallError("connect", syscall.ECONNREFUSED)),
+			want: models.ErrConnectionRefused,
+		},
+		{
+			name: "connection reset",
+			err:  dialError(os.NewSyscallError("read", syscall.ECONNRESET)),
+			want: models.ErrConnectionReset,
+		},
+		{
+			name: "deadline",
+			err:  dialError(context.DeadlineExceeded),
+			want: models.ErrTimeout,
+		},
+		{
+			name: "i/o timeout",
+			err:  dialError(os.ErrDeadlineExceeded),
+			want: models.ErrTimeout,
+		},
+		{
+			name: "http status",
+			err:  errors.New("unexpected status code: 503"),
+			want: models.ErrPlaylistDownload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, networkErrorType(tt.err, models.ErrPlaylistDownload))
+		})
+	}
+}
+
+func TestNetworkErrorType_Real(t *testing.T) {
+	t.Run("connection refused", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		require.NoError(t, ln.Close())
+
+		_, err = http.Get("http://" + addr + "/master.m3u8")
+		require.Error(t, err)
+		assert.Equal(t, models.ErrConnectionRefused, networkErrorType(err, models.ErrPlaylistDownload))
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		_, err := http.Get(srv.URL)
+		require.Error(t, err)
+		assert.Equal(t, models.ErrTLSHandshake, networkErrorType(err, models.ErrPlaylistDownload))
+	})
+}
+
+func TestStreamChecker_SegmentNetworkError(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	checker := NewStreamChecker(mockClient, NewHLSValidator(), new(MockMetricsCollector), 1)
+
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/seg1.ts", false).Return(
+		nil, dialError(os.NewSyscallError("read", syscall.ECONNRESET)))
+
+	check := checker.checkSegment(context.Background(), &m3u8.MediaSegment{URI: "http://test.com/seg1.ts"},
+		models.StreamConfig{Name: "test_stream"})
+
+	assert.False(t, check.Success)
+	require.NotNil(t, check.Error)
+	assert.Equal(t, models.ErrConnectionReset, check.Error.Type)
+	// Причина попадает в hls_stream_down_reason через итоговую ошибку segment_validate
+	assert.Equal(t, string(models.ErrConnectionReset), check.Error.Cause)
+}
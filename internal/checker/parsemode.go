@@ -0,0 +1,45 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/iudanet/hls_exporter/internal/conformance"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// checkParseIssues в режимах warn и strict находит строки плейлиста,
+// которые m3u8-парсер молча пропускает, и учитывает их в метриках
+func (c *StreamChecker) checkParseIssues(
+	stream models.StreamConfig,
+	playlistURL string,
+	body []byte,
+) []models.ParseIssue {
+	if stream.ParseMode != models.ParseModeWarn && stream.ParseMode != models.ParseModeStrict {
+		return nil
+	}
+
+	issues := conformance.Scan(body)
+	for i := range issues {
+		issues[i].URL = playlistURL
+		c.metrics.RecordParseIssue(stream.Name, string(issues[i].Kind))
+		c.logger.Warn("Playlist parse issue",
+			zap.String("stream", stream.Name),
+			zap.String("url", playlistURL),
+			zap.String("kind", string(issues[i].Kind)),
+			zap.Int("line", issues[i].Line),
+			zap.String("message", issues[i].Message))
+	}
+
+	return issues
+}
+
+// parseIssuesError в строгом режиме разбора возвращает ошибку при найденных проблемах
+func parseIssuesError(stream models.StreamConfig, issues []models.ParseIssue) error {
+	if stream.ParseMode != models.ParseModeStrict || len(issues) == 0 {
+		return nil
+	}
+	first := issues[0]
+	return fmt.Errorf("%d playlist parse issues, first at %s line %d: %s",
+		len(issues), first.URL, first.Line, first.Message)
+}
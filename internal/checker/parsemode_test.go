@@ -0,0 +1,97 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const vendorTagPlaylist = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-CUE-OUT:30
+#EXTINF:6.0,
+segment1.ts
+`
+
+func TestStreamChecker_CheckParseIssues(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	body := []byte(vendorTagPlaylist)
+
+	// Нестрогий разбор, как и режим по умолчанию, проблем не ищет
+	for _, mode := range []string{"", models.ParseModeLenient} {
+		stream := models.StreamConfig{Name: "test_stream", ParseMode: mode}
+		assert.Nil(t, c.checkParseIssues(stream, "http://test.com/index.m3u8", body))
+	}
+
+	mockMetrics.On("RecordParseIssue", "test_stream", string(models.ParseIssueUnknownTag)).Return().Once()
+	stream := models.StreamConfig{Name: "test_stream", ParseMode: models.ParseModeWarn}
+	issues := c.checkParseIssues(stream, "http://test.com/index.m3u8", body)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "http://test.com/index.m3u8", issues[0].URL)
+	assert.NoError(t, parseIssuesError(stream, issues), "warn mode must not fail the check")
+
+	stream.ParseMode = models.ParseModeStrict
+	assert.ErrorContains(t, parseIssuesError(stream, issues), "unknown tag EXT-X-CUE-OUT")
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestStreamChecker_Check_StrictParseMode(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+
+	mediaURL := "http://test.com/live/index.m3u8"
+	mockClient.On("GetPlaylist", mock.Anything, mediaURL).Return(
+		&models.PlaylistResponse{Body: []byte(vendorTagPlaylist), StatusCode: 200}, nil)
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/live/segment1.ts", false).Return(
+		&models.SegmentResponse{Size: 1024}, nil)
+	mockValidator.On("ValidateMedia", mock.Anything).Return(nil)
+
+	mockMetrics.On("RecordParseIssue", "strict_stream", string(models.ParseIssueUnknownTag)).Return()
+	mockMetrics.On("SetUnknownTag", "strict_stream", "EXT-X-CUE-OUT").Return().Once()
+	mockMetrics.On("SetStreamUp", "strict_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "strict_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "strict_stream", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "strict_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "strict_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "strict_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "strict_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "strict_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "strict_stream", mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "strict_stream", 1).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "strict_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "strict_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "strict_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "strict_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("SetPlaylistStale", "strict_stream", false).Return()
+	mockMetrics.On("SetPlaylistType", "strict_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetPlaylistMarkers", "strict_stream", mock.Anything, 0, 0).Return()
+	mockMetrics.On("SetMediaSequence", "strict_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetContentLooping", "strict_stream", false).Return()
+	mockMetrics.On("RecordError", "strict_stream", string(models.ErrPlaylistParse)).Return()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:      "strict_stream",
+		URL:       mediaURL,
+		CheckMode: models.CheckModeAll,
+		ParseMode: models.ParseModeStrict,
+	})
+
+	require.Error(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, models.ErrPlaylistParse, result.Error.Type)
+	require.Len(t, result.ParseIssues, 1)
+	assert.Equal(t, "EXT-X-CUE-OUT", result.ParseIssues[0].Tag)
+	assert.Equal(t, []string{"EXT-X-CUE-OUT"}, result.UnknownTags)
+	mockMetrics.AssertExpectations(t)
+}
@@ -0,0 +1,101 @@
+package checker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// pidTracker запоминает между проверками программы MPEG-TS последнего сегмента
+// каждого медиаплейлиста
+type pidTracker struct {
+	mu   sync.Mutex
+	last map[string][]models.TSProgram
+}
+
+func newPIDTracker() *pidTracker {
+	return &pidTracker{last: make(map[string][]models.TSProgram)}
+}
+
+// Observe сохраняет программы сегмента и возвращает программы предыдущего
+// сегмента того же плейлиста. ok=false, если плейлист встретился впервые.
+func (t *pidTracker) Observe(stream, playlistURL string, programs []models.TSProgram) ([]models.TSProgram, bool) {
+	key := stream + "|" + playlistURL
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev, ok := t.last[key]
+	t.last[key] = programs
+	return prev, ok
+}
+
+// comparePrograms определяет вид изменения структуры потока
+func comparePrograms(prev, cur []models.TSProgram) (models.PIDChangeKind, bool) {
+	if !slices.EqualFunc(prev, cur, func(a, b models.TSProgram) bool { return a.Number == b.Number }) {
+		return models.PIDChangeProgram, true
+	}
+	for i := range prev {
+		if prev[i].PMTPID != cur[i].PMTPID || !slices.Equal(prev[i].Streams, cur[i].Streams) {
+			return models.PIDChangeElementary, true
+		}
+	}
+	return "", false
+}
+
+// formatPrograms выводит программы в виде "1:pmt=0x1000[0x100/0x1b,0x101/0x0f]"
+func formatPrograms(programs []models.TSProgram) string {
+	parts := make([]string, 0, len(programs))
+	for _, p := range programs {
+		streams := make([]string, 0, len(p.Streams))
+		for _, s := range p.Streams {
+			streams = append(streams, fmt.Sprintf("%#x/0x%02x", s.PID, s.StreamType))
+		}
+		parts = append(parts, fmt.Sprintf("%d:pmt=%#x[%s]", p.Number, p.PMTPID, strings.Join(streams, ",")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// recordPIDChanges сравнивает программы MPEG-TS проверенных сегментов плейлиста
+// с предыдущими сегментами, в том числе из прошлых проверок. Смена программ
+// не делает стрим недоступным, но ломает часть приставок, поэтому только учитывается.
+func (c *StreamChecker) recordPIDChanges(
+	stream models.StreamConfig,
+	playlistURL string,
+	checks []models.SegmentCheck,
+	result *models.CheckResult,
+) {
+	for _, check := range checks {
+		if !check.Success || len(check.Programs) == 0 {
+			continue
+		}
+
+		prev, ok := c.pids.Observe(stream.Name, playlistURL, check.Programs)
+		if !ok {
+			continue
+		}
+		kind, changed := comparePrograms(prev, check.Programs)
+		if !changed {
+			continue
+		}
+
+		change := models.PIDChange{
+			Kind:     kind,
+			URL:      check.URL,
+			Previous: formatPrograms(prev),
+			Current:  formatPrograms(check.Programs),
+		}
+		result.PIDChanges = append(result.PIDChanges, change)
+		c.metrics.RecordPIDChange(stream.Name, string(kind))
+		c.logger.Warn("MPEG-TS programs changed",
+			zap.String("stream", stream.Name),
+			zap.String("url", change.URL),
+			zap.String("kind", string(kind)),
+			zap.String("previous", change.Previous),
+			zap.String("current", change.Current))
+	}
+}
@@ -0,0 +1,99 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	tsVideo = models.TSStream{PID: 0x100, StreamType: 0x1B}
+	tsAudio = models.TSStream{PID: 0x101, StreamType: 0x0F}
+)
+
+func TestComparePrograms(t *testing.T) {
+	base := []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{tsVideo, tsAudio}}}
+
+	tests := []struct {
+		name    string
+		cur     []models.TSProgram
+		want    models.PIDChangeKind
+		changed bool
+	}{
+		{name: "same", cur: []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{tsVideo, tsAudio}}}},
+		{
+			name:    "program number",
+			cur:     []models.TSProgram{{Number: 2, PMTPID: 0x1000, Streams: []models.TSStream{tsVideo, tsAudio}}},
+			want:    models.PIDChangeProgram,
+			changed: true,
+		},
+		{
+			name:    "elementary PID",
+			cur:     []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{{PID: 0x200, StreamType: 0x1B}, tsAudio}}},
+			want:    models.PIDChangeElementary,
+			changed: true,
+		},
+		{
+			name:    "PMT PID",
+			cur:     []models.TSProgram{{Number: 1, PMTPID: 0x1001, Streams: []models.TSStream{tsVideo, tsAudio}}},
+			want:    models.PIDChangeElementary,
+			changed: true,
+		},
+		{
+			name:    "stream dropped",
+			cur:     []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{tsVideo}}},
+			want:    models.PIDChangeElementary,
+			changed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, changed := comparePrograms(base, tt.cur)
+			assert.Equal(t, tt.changed, changed)
+			assert.Equal(t, tt.want, kind)
+		})
+	}
+}
+
+func TestFormatPrograms(t *testing.T) {
+	assert.Equal(t, "1:pmt=0x1000[0x100/0x1b,0x101/0x0f]", formatPrograms([]models.TSProgram{
+		{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{tsVideo, tsAudio}},
+	}))
+}
+
+func TestStreamChecker_RecordPIDChanges(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	before := []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{tsVideo, tsAudio}}}
+	after := []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{{PID: 0x200, StreamType: 0x1B}, tsAudio}}}
+
+	// Первая проверка запоминает раскладку, изменений нет
+	result := &models.CheckResult{}
+	c.recordPIDChanges(stream, "http://test.com/low.m3u8", []models.SegmentCheck{
+		{URL: "seg1.ts", Success: true, Programs: before},
+		{URL: "seg2.ts", Success: false},
+	}, result)
+	assert.Empty(t, result.PIDChanges)
+
+	// Смена PID между проверками учитывается один раз, другой плейлист не затрагивается
+	mockMetrics.On("RecordPIDChange", "test_stream", string(models.PIDChangeElementary)).Return().Once()
+	result = &models.CheckResult{}
+	c.recordPIDChanges(stream, "http://test.com/low.m3u8", []models.SegmentCheck{
+		{URL: "seg3.ts", Success: true, Programs: after},
+		{URL: "seg4.ts", Success: true, Programs: after},
+	}, result)
+	c.recordPIDChanges(stream, "http://test.com/high.m3u8", []models.SegmentCheck{
+		{URL: "hi1.ts", Success: true, Programs: after},
+	}, result)
+
+	require.Len(t, result.PIDChanges, 1)
+	assert.Equal(t, "seg3.ts", result.PIDChanges[0].URL)
+	assert.Equal(t, "1:pmt=0x1000[0x100/0x1b,0x101/0x0f]", result.PIDChanges[0].Previous)
+	assert.Equal(t, "1:pmt=0x1000[0x200/0x1b,0x101/0x0f]", result.PIDChanges[0].Current)
+	mockMetrics.AssertExpectations(t)
+}
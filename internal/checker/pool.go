@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// worker выполняет задачи проверок из общей очереди до остановки чекера
+func (c *StreamChecker) worker() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case job := <-c.jobs:
+			job()
+		}
+	}
+}
+
+// SetMaxConcurrencyPerCheck ограничивает число одновременно выполняемых задач одной проверки.
+// Значение 0 означает ограничение размером пула воркеров.
+func (c *StreamChecker) SetMaxConcurrencyPerCheck(n int) {
+	c.maxPerCheck = n
+}
+
+func (c *StreamChecker) concurrencyPerCheck() int {
+	n := c.maxPerCheck
+	if n <= 0 || (c.workers > 0 && n > c.workers) {
+		n = c.workers
+	}
+	if n <= 0 {
+		n = 1
+	}
+	return n
+}
+
+// runTasks выполняет задачи проверки в пуле воркеров и ожидает их завершения.
+// Задачи не должны сами вызывать runTasks, иначе пул может исчерпаться.
+func (c *StreamChecker) runTasks(ctx context.Context, tasks []func()) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrencyPerCheck())
+	tracker := taskTrackerFrom(ctx)
+
+	for _, task := range tasks {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		// Ожидание считается с момента, когда задача могла бы выполняться по лимиту проверки
+		queuedAt := time.Now()
+		c.tasks.queued.Add(1)
+		job := func() {
+			waited := time.Since(queuedAt)
+			c.tasks.queued.Add(-1)
+			c.tasks.started.Add(1)
+			if tracker != nil {
+				tracker.waited.Add(int64(waited))
+				tracker.started.Add(1)
+			}
+			defer func() {
+				if tracker != nil {
+					tracker.finished.Add(1)
+				}
+				c.tasks.finished.Add(1)
+				<-sem
+				wg.Done()
+			}()
+			task()
+		}
+
+		// Если пул не запущен или остановлен, выполняем задачу в текущей горутине
+		if !c.submit(job) {
+			job()
+		}
+	}
+
+	wg.Wait()
+}
+
+// submit передает задачу в очередь пула. Возвращает false, если пул не принимает задачи.
+func (c *StreamChecker) submit(job func()) bool {
+	if !c.running.Load() {
+		return false
+	}
+
+	select {
+	case c.jobs <- job:
+		return true
+	case <-c.stopCh:
+		return false
+	}
+}
@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPoolChecker(workers int) *StreamChecker {
+	mockClient := new(MockHTTPClient)
+	mockClient.On("SetTimeout", mock.Anything).Return()
+	return NewStreamChecker(mockClient, new(MockValidator), new(MockMetricsCollector), workers)
+}
+
+// measureConcurrency выполняет задачи и возвращает максимальное число одновременно работавших
+func measureConcurrency(c *StreamChecker, count int) (int32, int32) {
+	var running, peak, done int32
+	var mu sync.Mutex
+
+	tasks := make([]func(), 0, count)
+	for i := 0; i < count; i++ {
+		tasks = append(tasks, func() {
+			n := atomic.AddInt32(&running, 1)
+			mu.Lock()
+			if n > peak {
+				peak = n
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+		})
+	}
+	c.runTasks(context.Background(), tasks)
+
+	return peak, done
+}
+
+func TestRunTasks_BoundedByPool(t *testing.T) {
+	c := newPoolChecker(4)
+	assert.NoError(t, c.Start())
+	defer func() { assert.NoError(t, c.Stop()) }()
+
+	peak, done := measureConcurrency(c, 20)
+	assert.Equal(t, int32(20), done)
+	assert.LessOrEqual(t, peak, int32(4))
+	assert.Greater(t, peak, int32(1), "tasks should run in parallel on the pool")
+}
+
+func TestRunTasks_MaxConcurrencyPerCheck(t *testing.T) {
+	c := newPoolChecker(8)
+	c.SetMaxConcurrencyPerCheck(2)
+	assert.NoError(t, c.Start())
+	defer func() { assert.NoError(t, c.Stop()) }()
+
+	peak, done := measureConcurrency(c, 10)
+	assert.Equal(t, int32(10), done)
+	assert.LessOrEqual(t, peak, int32(2))
+}
+
+func TestRunTasks_WithoutPool(t *testing.T) {
+	// Без запущенного пула задачи выполняются в вызывающей горутине
+	c := newPoolChecker(4)
+	peak, done := measureConcurrency(c, 5)
+	assert.Equal(t, int32(5), done)
+	assert.Equal(t, int32(1), peak)
+
+	// После остановки пула задачи по-прежнему выполняются
+	assert.NoError(t, c.Start())
+	assert.NoError(t, c.Stop())
+	_, done = measureConcurrency(c, 3)
+	assert.Equal(t, int32(3), done)
+}
+
+func TestRunTasks_QueueWait(t *testing.T) {
+	// Одного воркера одновременно ждут остальные задачи двух проверок
+	c := newPoolChecker(1)
+	assert.NoError(t, c.Start())
+	defer func() { assert.NoError(t, c.Stop()) }()
+
+	release := make(chan struct{})
+	blocked := make(chan struct{})
+	go c.runTasks(context.Background(), []func(){func() {
+		close(blocked)
+		<-release
+	}})
+	<-blocked
+
+	ctx, tracker := withTaskTracker(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.runTasks(ctx, []func(){func() {}})
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return c.tasks.queued.Load() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(1), c.ActiveTasks())
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	<-done
+
+	assert.Zero(t, c.tasks.queued.Load())
+	assert.GreaterOrEqual(t, tracker.queueWait(), 20*time.Millisecond)
+}
@@ -0,0 +1,21 @@
+package checker
+
+import (
+	"net/http"
+	"time"
+)
+
+// rateLimited сообщает, что источник ограничил частоту запросов: ответ 429
+// или 503 с заголовком Retry-After
+func rateLimited(statusCode int, retryAfter time.Duration) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		(statusCode == http.StatusServiceUnavailable && retryAfter > 0)
+}
+
+// recordResponse учитывает ответ HTTP на запрос проверки и ограничение частоты запросов
+func (c *StreamChecker) recordResponse(stream, phase string, statusCode int, retryAfter time.Duration) {
+	c.metrics.RecordHTTPResponse(stream, phase, statusCode)
+	if rateLimited(statusCode, retryAfter) {
+		c.metrics.RecordRateLimited(stream, phase)
+	}
+}
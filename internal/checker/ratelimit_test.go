@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimited(t *testing.T) {
+	assert.True(t, rateLimited(http.StatusTooManyRequests, 0))
+	assert.True(t, rateLimited(http.StatusServiceUnavailable, time.Second))
+	assert.False(t, rateLimited(http.StatusServiceUnavailable, 0))
+	assert.False(t, rateLimited(http.StatusForbidden, time.Second))
+}
+
+func TestStreamChecker_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter time.Duration
+		timeout    time.Duration
+		calls      int
+		success    bool
+	}{
+		{name: "retried after pause", retryAfter: 20 * time.Millisecond, timeout: time.Second, calls: 2, success: true},
+		{name: "pause exceeds timeout", retryAfter: time.Minute, timeout: time.Second, calls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			mockMetrics := new(MockMetricsCollector)
+			checker := NewStreamChecker(mockClient, NewHLSValidator(), mockMetrics, 1)
+			// retry_delay больше таймаута: повтор возможен только по Retry-After
+			checker.SetRetry(1, time.Hour)
+
+			url := "http://test.com/seg1.ts"
+			mockClient.On("GetSegment", mock.Anything, url, false).Return(
+				&models.SegmentResponse{StatusCode: http.StatusTooManyRequests, RetryAfter: tt.retryAfter},
+				errors.New("unexpected status code: 429")).Once()
+			mockClient.On("GetSegment", mock.Anything, url, false).Return(
+				&models.SegmentResponse{StatusCode: http.StatusOK}, nil).Once()
+			mockMetrics.On("RecordHTTPResponse", "test_stream", models.ResponseTimeSegment, mock.Anything).Return()
+			mockMetrics.On("RecordRateLimited", "test_stream", models.ResponseTimeSegment).Return().Once()
+			mockMetrics.On("RecordResponseTime", "test_stream", models.ResponseTimeSegment, mock.Anything, mock.Anything).Return().Maybe()
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			start := time.Now()
+			check := checker.checkSegment(ctx, &m3u8.MediaSegment{URI: url}, models.StreamConfig{Name: "test_stream"})
+
+			assert.Equal(t, tt.success, check.Success)
+			mockClient.AssertNumberOfCalls(t, "GetSegment", tt.calls)
+			mockMetrics.AssertExpectations(t)
+			if tt.success {
+				assert.GreaterOrEqual(t, time.Since(start), tt.retryAfter)
+			} else {
+				require.NotNil(t, check.Error)
+				assert.Equal(t, tt.retryAfter, check.Error.RetryAfter)
+			}
+		})
+	}
+}
+
+func TestSegmentRetryAfter(t *testing.T) {
+	details := []models.SegmentCheck{
+		{Success: true},
+		{Error: &models.CheckError{RetryAfter: 5 * time.Second}},
+		{Error: &models.CheckError{RetryAfter: 30 * time.Second}},
+		{Error: &models.CheckError{}},
+	}
+	assert.Equal(t, 30*time.Second, segmentRetryAfter(details))
+	assert.Zero(t, segmentRetryAfter(details[:1]))
+}
@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// collectRenditions возвращает уникальные рендишены EXT-X-MEDIA всех вариантов.
+// Рендишены без URI (например, CLOSED-CAPTIONS внутри видео) загружать нечего, они пропускаются.
+func collectRenditions(master *m3u8.MasterPlaylist) []*m3u8.Alternative {
+	var renditions []*m3u8.Alternative
+	seen := make(map[string]bool)
+
+	for _, variant := range master.Variants {
+		if variant == nil {
+			continue
+		}
+		for _, alt := range variant.Alternatives {
+			if alt == nil || alt.URI == "" {
+				continue
+			}
+			key := alt.Type + "|" + alt.GroupId + "|" + alt.URI
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			renditions = append(renditions, alt)
+		}
+	}
+
+	return renditions
+}
+
+// checkRenditions загружает и валидирует плейлисты альтернативных рендишенов
+func (c *StreamChecker) checkRenditions(
+	ctx context.Context,
+	master *m3u8.MasterPlaylist,
+	cfg models.StreamConfig,
+	result *models.CheckResult,
+) []models.RenditionCheck {
+	renditions := collectRenditions(master)
+	if len(renditions) == 0 {
+		return nil
+	}
+
+	checks := make([]models.RenditionCheck, len(renditions))
+	tasks := make([]func(), 0, len(renditions))
+	for i, alt := range renditions {
+		tasks = append(tasks, func() {
+			checks[i] = c.checkRendition(ctx, alt, cfg, result)
+		})
+	}
+	c.runTasks(ctx, tasks)
+
+	return checks
+}
+
+func (c *StreamChecker) checkRendition(
+	ctx context.Context,
+	alt *m3u8.Alternative,
+	cfg models.StreamConfig,
+	result *models.CheckResult,
+) models.RenditionCheck {
+	check := models.RenditionCheck{
+		Type:     alt.Type,
+		GroupID:  alt.GroupId,
+		Name:     alt.Name,
+		Language: alt.Language,
+		URL:      resolveURL(cfg.URL, alt.URI),
+	}
+
+	fail := func(err error, statusCode int, msg string) models.RenditionCheck {
+		c.logger.Error(msg,
+			zap.String("type", alt.Type),
+			zap.String("group_id", alt.GroupId),
+			zap.String("url", check.URL),
+			zap.Error(err))
+		check.Error = newCheckError(models.ErrRendition, err, statusCode)
+		return check
+	}
+
+	resp, err := c.client.GetPlaylist(ctx, check.URL)
+	if resp != nil {
+		c.recordResponse(cfg.Name, models.ResponseTimeVariant, resp.StatusCode, resp.RetryAfter)
+	}
+	if err != nil {
+		var code int
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		return fail(err, code, "Failed to get rendition playlist")
+	}
+	atomic.AddInt64(&result.BytesDownloaded, int64(len(resp.Body)))
+
+	mediaPlaylist, err := parseMediaPlaylist(resp.Body)
+	if err != nil {
+		return fail(err, 0, "Failed to parse rendition playlist")
+	}
+
+	if err := c.validator.ValidateMedia(mediaPlaylist); err != nil {
+		return fail(err, 0, "Failed to validate rendition playlist")
+	}
+
+	check.Success = true
+	return check
+}
@@ -0,0 +1,125 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const renditionMaster = `#EXTM3U
+#EXT-X-VERSION:4
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="English",LANGUAGE="en",URI="audio_en.m3u8"
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="Deutsch",LANGUAGE="de",URI="audio_de.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",NAME="English",LANGUAGE="en",URI="subs_en.m3u8"
+#EXT-X-MEDIA:TYPE=CLOSED-CAPTIONS,GROUP-ID="cc",NAME="CC1",INSTREAM-ID="CC1"
+#EXT-X-STREAM-INF:BANDWIDTH=1000000,AUDIO="aud",SUBTITLES="subs",CLOSED-CAPTIONS="cc"
+low.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=2000000,AUDIO="aud",SUBTITLES="subs",CLOSED-CAPTIONS="cc"
+high.m3u8`
+
+const renditionMedia = `#EXTM3U
+#EXT-X-TARGETDURATION:10
+#EXTINF:10.0,
+segment1.ts`
+
+func TestCollectRenditions(t *testing.T) {
+	playlist, _, err := parsePlaylist([]byte(renditionMaster))
+	require.NoError(t, err)
+
+	master, ok := playlist.(*m3u8.MasterPlaylist)
+	require.True(t, ok)
+
+	renditions := collectRenditions(master)
+	// Рендишены разделяются вариантами, но проверяются один раз; CC без URI пропускается
+	require.Len(t, renditions, 3)
+
+	types := make(map[string]int)
+	for _, r := range renditions {
+		types[r.Type]++
+	}
+	assert.Equal(t, 2, types["AUDIO"])
+	assert.Equal(t, 1, types["SUBTITLES"])
+}
+
+func TestStreamChecker_Check_Renditions(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+
+	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+
+	mediaResp := &models.PlaylistResponse{Body: []byte(renditionMedia), StatusCode: 200}
+	mockClient.On("GetPlaylist", mock.Anything, "http://test.com/master.m3u8").Return(
+		&models.PlaylistResponse{Body: []byte(renditionMaster), StatusCode: 200}, nil)
+	for _, uri := range []string{"low.m3u8", "high.m3u8", "audio_en.m3u8", "subs_en.m3u8"} {
+		mockClient.On("GetPlaylist", mock.Anything, "http://test.com/"+uri).Return(mediaResp, nil)
+	}
+	mockClient.On("GetPlaylist", mock.Anything, "http://test.com/audio_de.m3u8").Return(
+		nil, errors.New("unexpected status code: 404"))
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/segment1.ts", false).Return(
+		&models.SegmentResponse{Size: 1024, Duration: time.Second}, nil)
+
+	mockValidator.On("ValidateMaster", mock.Anything).Return(nil)
+	mockValidator.On("ValidateMedia", mock.Anything).Return(nil)
+
+	mockMetrics.On("SetStreamUp", "test_stream", true).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetLastSuccessTime", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "test_stream", true).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetPlaylistStale", "test_stream", false).Return()
+	mockMetrics.On("SetPlaylistType", "test_stream", true, mock.Anything).Return()
+	mockMetrics.On("SetPlaylistMarkers", "test_stream", mock.Anything, 0, 0).Return()
+	mockMetrics.On("SetMediaSequence", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetContentLooping", "test_stream", false).Return()
+	mockMetrics.On("RecordVariantResponseTime", "test_stream", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetSessionData", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordVariantSegmentCheck", "test_stream", mock.Anything, mock.Anything, true).Return()
+	mockMetrics.On("SetVariantBitrate", "test_stream", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetRenditionUp", "test_stream", "AUDIO", "aud", "English", true).Return()
+	mockMetrics.On("SetRenditionUp", "test_stream", "AUDIO", "aud", "Deutsch", false).Return()
+	mockMetrics.On("SetRenditionUp", "test_stream", "SUBTITLES", "subs", "English", true).Return()
+	mockMetrics.On("RecordError", "test_stream", string(models.ErrRendition)).Return()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:      "test_stream",
+		URL:       "http://test.com/master.m3u8",
+		CheckMode: models.CheckModeAll,
+	})
+
+	assert.NoError(t, err)
+	require.Len(t, result.Renditions, 3)
+
+	failed := 0
+	for _, r := range result.Renditions {
+		if !r.Success {
+			failed++
+			assert.Equal(t, "Deutsch", r.Name)
+			assert.Equal(t, models.ErrRendition, r.Error.Type)
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	mockClient.AssertExpectations(t)
+	mockMetrics.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"context"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// SetRetry задает число повторов загрузки плейлиста и сегментов при временной ошибке
+// (checks.retry_attempts, 0 - без повторов) и паузу между попытками (checks.retry_delay)
+func (c *StreamChecker) SetRetry(attempts int, delay time.Duration) {
+	c.retries = attempts
+	c.retryDelay = delay
+}
+
+// retry выполняет запрос do и повторяет его, пока ошибка временная (CheckError.Retryable)
+// и не исчерпаны повторы. do возвращает код ответа HTTP (0 - ответа нет), паузу
+// из Retry-After и ошибку, fallback - тип ошибки, если она не сетевая.
+// Пауза Retry-After заменяет retry_delay; если она не укладывается в таймаут проверки,
+// запрос не повторяется, и повтор откладывается до следующей проверки.
+func (c *StreamChecker) retry(ctx context.Context, fallback models.ErrorType, do func() (int, time.Duration, error)) error {
+	code, retryAfter, err := do()
+	for attempt := 1; err != nil && attempt <= c.retries; attempt++ {
+		if !retryable(networkErrorType(err, fallback), code) {
+			return err
+		}
+		delay := c.retryDelay
+		if retryAfter > 0 {
+			delay = retryAfter
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+				return err
+			}
+		}
+		c.logger.Debug("Retrying request",
+			zap.String("check_id", models.CheckIDFrom(ctx)),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+		if !sleepContext(ctx, delay) {
+			return err
+		}
+		code, retryAfter, err = do()
+	}
+	return err
+}
+
+// sleepContext ждет d и возвращает false, если контекст завершился раньше
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
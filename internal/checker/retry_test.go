@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamChecker_RetrySegment(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		retries  int
+		calls    int
+		success  bool
+		retrying bool
+	}{
+		{name: "server error retried", status: http.StatusServiceUnavailable, retries: 2, calls: 2, success: true},
+		{name: "retries disabled", status: http.StatusServiceUnavailable, retries: 0, calls: 1, retrying: true},
+		{name: "client error not retried", status: http.StatusForbidden, retries: 2, calls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockHTTPClient)
+			mockMetrics := new(MockMetricsCollector)
+			checker := NewStreamChecker(mockClient, NewHLSValidator(), mockMetrics, 1)
+			checker.SetRetry(tt.retries, time.Millisecond)
+
+			url := "http://test.com/seg1.ts"
+			mockClient.On("GetSegment", mock.Anything, url, false).Return(
+				&models.SegmentResponse{StatusCode: tt.status}, errors.New("unexpected status code")).Once()
+			mockClient.On("GetSegment", mock.Anything, url, false).Return(
+				&models.SegmentResponse{StatusCode: http.StatusOK}, nil).Once()
+			mockMetrics.On("RecordHTTPResponse", "test_stream", models.ResponseTimeSegment, mock.Anything).Return()
+			mockMetrics.On("RecordResponseTime", "test_stream", models.ResponseTimeSegment, mock.Anything, mock.Anything).Return()
+
+			check := checker.checkSegment(context.Background(), &m3u8.MediaSegment{URI: url},
+				models.StreamConfig{Name: "test_stream"})
+
+			assert.Equal(t, tt.success, check.Success)
+			mockClient.AssertNumberOfCalls(t, "GetSegment", tt.calls)
+			if !tt.success {
+				require.NotNil(t, check.Error)
+				assert.Equal(t, tt.status, check.Error.StatusCode)
+				assert.Equal(t, tt.retrying, check.Error.Retryable)
+			}
+		})
+	}
+}
+
+// errTimeout таймаут запроса в том виде, в каком его возвращает net/http
+var errTimeout = dialError(context.DeadlineExceeded)
+
+func TestStreamChecker_RetryStopsOnCancel(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	checker := NewStreamChecker(mockClient, NewHLSValidator(), new(MockMetricsCollector), 1)
+	checker.SetRetry(3, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockClient.On("GetPlaylist", mock.Anything, "http://test.com/master.m3u8").Return(nil, errTimeout).
+		Run(func(mock.Arguments) { cancel() })
+
+	result := &models.CheckResult{StreamName: "test_stream"}
+	_, _, _, err := checker.fetchRootPlaylist(ctx, "http://test.com/master.m3u8", result)
+
+	require.Error(t, err)
+	mockClient.AssertNumberOfCalls(t, "GetPlaylist", 1)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrTimeout, result.Error.Type)
+	assert.True(t, result.Error.Retryable)
+}
+
+func TestStreamChecker_PlaylistStatusCode(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, NewHLSValidator(), mockMetrics, 1)
+
+	mockClient.On("GetPlaylist", mock.Anything, "http://test.com/master.m3u8").Return(
+		&models.PlaylistResponse{StatusCode: http.StatusForbidden}, errors.New("unexpected status code: 403"))
+	mockMetrics.On("RecordHTTPResponse", "test_stream", models.ResponseTimeMaster, http.StatusForbidden).Return()
+
+	result := &models.CheckResult{StreamName: "test_stream"}
+	_, _, _, err := checker.fetchRootPlaylist(context.Background(), "http://test.com/master.m3u8", result)
+
+	require.Error(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrPlaylistDownload, result.Error.Type)
+	assert.Equal(t, http.StatusForbidden, result.Error.StatusCode)
+	assert.False(t, result.Error.Retryable)
+	assert.Equal(t, "playlist_download_http_403", downReason(result))
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, retryable(models.ErrTimeout, 0))
+	assert.True(t, retryable(models.ErrConnectionReset, 0))
+	assert.True(t, retryable(models.ErrPlaylistDownload, http.StatusBadGateway))
+	assert.True(t, retryable(models.ErrSegmentDownload, http.StatusTooManyRequests))
+	assert.False(t, retryable(models.ErrSegmentDownload, http.StatusNotFound))
+	assert.False(t, retryable(models.ErrTLSHandshake, 0))
+	assert.False(t, retryable(models.ErrPlaylistParse, 0))
+}
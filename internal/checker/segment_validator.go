@@ -73,5 +73,30 @@ func (v *BasicSegmentValidator) ValidateMedia(
 		}
 	}
 
+	return validatePCR(segment.MediaInfo, validation)
+}
+
+// validatePCR проверяет тайминг PCR. Сегменты без PCR (fMP4) не проверяются.
+func validatePCR(info models.MediaInfo, validation *models.MediaValidation) error {
+	if info.PCRCount == 0 {
+		return nil
+	}
+
+	if validation.MaxPCRInterval > 0 && info.PCRMaxInterval > validation.MaxPCRInterval {
+		return &models.ValidationError{
+			Type: models.ErrPCRInterval,
+			Message: fmt.Sprintf("PCR interval %s exceeds maximum %s",
+				info.PCRMaxInterval, validation.MaxPCRInterval),
+		}
+	}
+
+	if validation.MaxPCRJitter > 0 && info.PCRJitter > validation.MaxPCRJitter {
+		return &models.ValidationError{
+			Type: models.ErrPCRJitter,
+			Message: fmt.Sprintf("PCR jitter %s exceeds maximum %s",
+				info.PCRJitter, validation.MaxPCRJitter),
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package checker
 
 import (
 	"testing"
+	"time"
 
 	"github.com/iudanet/hls_exporter/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -80,7 +81,56 @@ func TestBasicSegmentValidator_ValidateMedia(t *testing.T) {
 			},
 			wantErr: false,
 		},
-		// Добавьте больше тест-кейсов
+		{
+			name: "PCR within limits",
+			segment: &models.SegmentData{
+				MediaInfo: models.MediaInfo{
+					Container:      "TS",
+					PCRCount:       10,
+					PCRMaxInterval: 40 * time.Millisecond,
+					PCRJitter:      200 * time.Microsecond,
+				},
+			},
+			validation: &models.MediaValidation{
+				ContainerType:  []string{"TS"},
+				MaxPCRInterval: 100 * time.Millisecond,
+				MaxPCRJitter:   500 * time.Microsecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "PCR interval exceeded",
+			segment: &models.SegmentData{
+				MediaInfo: models.MediaInfo{
+					Container:      "TS",
+					PCRCount:       10,
+					PCRMaxInterval: 150 * time.Millisecond,
+				},
+			},
+			validation: &models.MediaValidation{ContainerType: []string{"TS"}, MaxPCRInterval: 100 * time.Millisecond},
+			wantErr:    true,
+		},
+		{
+			name: "PCR jitter exceeded",
+			segment: &models.SegmentData{
+				MediaInfo: models.MediaInfo{
+					Container:      "TS",
+					PCRCount:       10,
+					PCRMaxInterval: 40 * time.Millisecond,
+					PCRJitter:      time.Millisecond,
+				},
+			},
+			validation: &models.MediaValidation{ContainerType: []string{"TS"}, MaxPCRJitter: 500 * time.Microsecond},
+			wantErr:    true,
+		},
+		{
+			name: "no PCR skips limits",
+			segment: &models.SegmentData{
+				MediaInfo: models.MediaInfo{Container: "fMP4"},
+			},
+			validation: &models.MediaValidation{ContainerType: []string{"fMP4"}, MaxPCRInterval: 100 * time.Millisecond},
+			wantErr:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,69 @@
+package checker
+
+import (
+	"sync"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// windowShrinkFactor окно считается сократившимся, если в нем стало больше чем
+// в windowShrinkFactor раз меньше сегментов, чем при предыдущей проверке
+const windowShrinkFactor = 2
+
+// sequenceTracker запоминает между проверками media sequence и размер окна медиаплейлистов,
+// чтобы обнаружить перезапуск упаковщика
+type sequenceTracker struct {
+	mu    sync.Mutex
+	state map[string]sequenceState
+}
+
+type sequenceState struct {
+	sequence uint64
+	segments int
+}
+
+func newSequenceTracker() *sequenceTracker {
+	return &sequenceTracker{state: make(map[string]sequenceState)}
+}
+
+// Observe сравнивает плейлист с предыдущей проверкой и сообщает, что media sequence
+// уменьшился и что окно сократилось больше чем в windowShrinkFactor раз
+func (s *sequenceTracker) Observe(stream, playlistURL string, media *m3u8.MediaPlaylist) (reset, shrunk bool) {
+	current := sequenceState{sequence: media.SeqNo, segments: int(media.Count())}
+	key := stream + "|" + playlistURL
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.state[key]
+	s.state[key] = current
+	if !ok {
+		return false, false
+	}
+	return current.sequence < prev.sequence, current.segments*windowShrinkFactor < prev.segments
+}
+
+// recordSequence публикует media sequence медиаплейлиста и добавляет предупреждения
+// media_sequence_reset и window_shrink, если live-плейлист откатился назад или его
+// окно резко сократилось. VOD-плейлисты не меняются и не отслеживаются.
+func (c *StreamChecker) recordSequence(
+	stream models.StreamConfig,
+	variant string,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	result *models.CheckResult,
+) {
+	c.metrics.SetMediaSequence(stream.Name, variant, media.SeqNo)
+	if isVOD(media) {
+		return
+	}
+
+	reset, shrunk := c.sequences.Observe(stream.Name, playlistURL, media)
+	if reset {
+		result.AddWarning(models.WarningSequenceReset)
+	}
+	if shrunk {
+		result.AddWarning(models.WarningWindowShrink)
+	}
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequenceTracker_Observe(t *testing.T) {
+	tracker := newSequenceTracker()
+	const url = "http://a/index.m3u8"
+	window := []string{"a.ts", "b.ts", "c.ts", "d.ts", "e.ts", "f.ts"}
+
+	tests := []struct {
+		name       string
+		seq        int
+		uris       []string
+		wantReset  bool
+		wantShrunk bool
+	}{
+		{name: "first check", seq: 100, uris: window},
+		{name: "advanced", seq: 101, uris: window},
+		{name: "unchanged", seq: 101, uris: window},
+		{name: "window shrunk by half", seq: 102, uris: window[:3]},
+		{name: "window shrunk more than half", seq: 103, uris: window[:1], wantShrunk: true},
+		{name: "window grows", seq: 104, uris: window},
+		{name: "packager restart", seq: 0, uris: window[:2], wantReset: true, wantShrunk: true},
+		{name: "after restart", seq: 1, uris: window[:3]},
+	}
+	for _, tt := range tests {
+		reset, shrunk := tracker.Observe("test_stream", url, windowPlaylist(t, tt.seq, tt.uris...))
+		assert.Equal(t, tt.wantReset, reset, tt.name)
+		assert.Equal(t, tt.wantShrunk, shrunk, tt.name)
+	}
+
+	reset, _ := tracker.Observe("test_stream", "http://b/index.m3u8", windowPlaylist(t, 0, "a.ts"))
+	assert.False(t, reset, "variants are tracked separately")
+}
+
+func TestStreamChecker_RecordSequence(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	mockMetrics.On("SetMediaSequence", "test_stream", "720p.m3u8", uint64(500)).Return()
+	mockMetrics.On("SetMediaSequence", "test_stream", "720p.m3u8", uint64(3)).Return()
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+	const url = "http://a/720p.m3u8"
+
+	result := &models.CheckResult{Success: true}
+	c.recordSequence(stream, "720p.m3u8", url, windowPlaylist(t, 500, "a.ts", "b.ts", "c.ts"), result)
+	assert.Empty(t, result.Warnings)
+
+	result = &models.CheckResult{Success: true}
+	c.recordSequence(stream, "720p.m3u8", url, windowPlaylist(t, 3, "a.ts"), result)
+	assert.Equal(t, []string{models.WarningSequenceReset, models.WarningWindowShrink}, result.Warnings)
+	assert.True(t, result.Success)
+
+	// VOD-плейлист не отслеживается
+	vod := windowPlaylist(t, 500, "a.ts", "b.ts", "c.ts")
+	vod.Closed = true
+	c.recordSequence(stream, "720p.m3u8", "http://a/vod.m3u8", vod, result)
+	vod = windowPlaylist(t, 3, "a.ts")
+	vod.Closed = true
+	result = &models.CheckResult{}
+	c.recordSequence(stream, "720p.m3u8", "http://a/vod.m3u8", vod, result)
+	assert.Empty(t, result.Warnings)
+	mockMetrics.AssertExpectations(t)
+}
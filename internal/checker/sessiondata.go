@@ -0,0 +1,103 @@
+package checker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+const sessionDataTag = "#EXT-X-SESSION-DATA:"
+
+// parseSessionData возвращает записи EXT-X-SESSION-DATA мастер-плейлиста.
+// Записи с некорректным списком атрибутов или без DATA-ID пропускаются: их находит строгий режим.
+func parseSessionData(body []byte) []models.SessionData {
+	var entries []models.SessionData
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), sessionDataTag)
+		if !ok {
+			continue
+		}
+		attrs, err := parseAttributeList(value)
+		if err != nil || attrs["DATA-ID"] == "" {
+			continue
+		}
+		entries = append(entries, models.SessionData{
+			DataID:   attrs["DATA-ID"],
+			Value:    attrs["VALUE"],
+			URI:      attrs["URI"],
+			Language: attrs["LANGUAGE"],
+		})
+	}
+	return entries
+}
+
+// checkSessionData разбирает EXT-X-SESSION-DATA мастер-плейлиста и публикует записи.
+// С validate_session_data документы URI загружаются и проверяются на JSON (RFC 8216, 4.3.4.4);
+// ошибки не влияют на успешность проверки и отмечаются предупреждением.
+func (c *StreamChecker) checkSessionData(
+	ctx context.Context,
+	cfg models.StreamConfig,
+	body []byte,
+	result *models.CheckResult,
+) {
+	entries := parseSessionData(body)
+	if cfg.ValidateSessionData {
+		var tasks []func()
+		for i := range entries {
+			if entries[i].URI == "" {
+				continue
+			}
+			tasks = append(tasks, func() {
+				entries[i].Error = c.fetchSessionData(ctx, cfg, entries[i], result)
+			})
+		}
+		c.runTasks(ctx, tasks)
+	}
+
+	for _, entry := range entries {
+		if entry.Error != nil {
+			result.AddWarning(models.WarningSessionData)
+		}
+	}
+	result.SessionData = entries
+	c.metrics.SetSessionData(cfg.Name, entries)
+}
+
+// fetchSessionData загружает документ URI записи и проверяет, что он является JSON
+func (c *StreamChecker) fetchSessionData(
+	ctx context.Context,
+	cfg models.StreamConfig,
+	entry models.SessionData,
+	result *models.CheckResult,
+) *models.CheckError {
+	url := resolveURL(cfg.URL, entry.URI)
+	resp, err := c.client.GetPlaylist(ctx, url)
+	if err == nil {
+		atomic.AddInt64(&result.BytesDownloaded, int64(len(resp.Body)))
+		if !json.Valid(resp.Body) {
+			err = fmt.Errorf("session data %s is not valid JSON", entry.DataID)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	c.logger.Warn("Invalid session data document",
+		zap.String("check_id", models.CheckIDFrom(ctx)),
+		zap.String("data_id", entry.DataID),
+		zap.String("url", url),
+		zap.Error(err))
+	var code int
+	if resp != nil {
+		code = resp.StatusCode
+	}
+	return newCheckError(models.ErrSessionData, err, code)
+}
@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const sessionDataMaster = `#EXTM3U
+#EXT-X-SESSION-DATA:DATA-ID="com.example.title",VALUE="Evening News",LANGUAGE="en"
+#EXT-X-SESSION-DATA:DATA-ID="com.example.meta",URI="meta/info.json"
+#EXT-X-SESSION-DATA:DATA-ID="com.example.broken",URI="broken.json"
+#EXT-X-SESSION-DATA:VALUE="no data id"
+#EXT-X-SESSION-DATA:DATA-ID="com.example.unterminated,VALUE="x"
+#EXT-X-STREAM-INF:BANDWIDTH=1000000
+720p.m3u8
+`
+
+func TestParseSessionData(t *testing.T) {
+	assert.Equal(t, []models.SessionData{
+		{DataID: "com.example.title", Value: "Evening News", Language: "en"},
+		{DataID: "com.example.meta", URI: "meta/info.json"},
+		{DataID: "com.example.broken", URI: "broken.json"},
+	}, parseSessionData([]byte(sessionDataMaster)))
+	assert.Empty(t, parseSessionData([]byte("#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1\n1.m3u8\n")))
+}
+
+func TestStreamChecker_CheckSessionData(t *testing.T) {
+	stream := models.StreamConfig{Name: "test_stream", URL: "http://example.com/live/master.m3u8"}
+
+	t.Run("without validation", func(t *testing.T) {
+		mockMetrics := new(MockMetricsCollector)
+		mockMetrics.On("SetSessionData", "test_stream", mock.Anything).Return()
+		mockClient := new(MockHTTPClient)
+		c := NewStreamChecker(mockClient, new(MockValidator), mockMetrics, 1)
+
+		result := &models.CheckResult{}
+		c.checkSessionData(context.Background(), stream, []byte(sessionDataMaster), result)
+		assert.Len(t, result.SessionData, 3)
+		assert.Empty(t, result.Warnings)
+		mockClient.AssertNotCalled(t, "GetPlaylist", mock.Anything, mock.Anything)
+	})
+
+	t.Run("validate documents", func(t *testing.T) {
+		mockMetrics := new(MockMetricsCollector)
+		mockMetrics.On("SetSessionData", "test_stream", mock.Anything).Return()
+		mockClient := new(MockHTTPClient)
+		mockClient.On("GetPlaylist", mock.Anything, "http://example.com/live/meta/info.json").
+			Return(&models.PlaylistResponse{StatusCode: http.StatusOK, Body: []byte(`{"title":"Evening News"}`)}, nil)
+		mockClient.On("GetPlaylist", mock.Anything, "http://example.com/live/broken.json").
+			Return(&models.PlaylistResponse{StatusCode: http.StatusOK, Body: []byte(`<html>`)}, nil)
+		c := NewStreamChecker(mockClient, new(MockValidator), mockMetrics, 1)
+
+		validating := stream
+		validating.ValidateSessionData = true
+		result := &models.CheckResult{}
+		c.checkSessionData(context.Background(), validating, []byte(sessionDataMaster), result)
+
+		require.Len(t, result.SessionData, 3)
+		assert.Nil(t, result.SessionData[1].Error)
+		if assert.NotNil(t, result.SessionData[2].Error) {
+			assert.Equal(t, models.ErrSessionData, result.SessionData[2].Error.Type)
+		}
+		assert.Equal(t, []string{models.WarningSessionData}, result.Warnings)
+		assert.EqualValues(t, 30, result.BytesDownloaded)
+		mockMetrics.AssertCalled(t, "SetSessionData", "test_stream", result.SessionData)
+	})
+
+	t.Run("document unavailable", func(t *testing.T) {
+		mockMetrics := new(MockMetricsCollector)
+		mockMetrics.On("SetSessionData", "test_stream", mock.Anything).Return()
+		mockClient := new(MockHTTPClient)
+		mockClient.On("GetPlaylist", mock.Anything, mock.Anything).
+			Return(&models.PlaylistResponse{StatusCode: http.StatusNotFound}, errors.New("unexpected status code: 404"))
+		c := NewStreamChecker(mockClient, new(MockValidator), mockMetrics, 1)
+
+		validating := stream
+		validating.ValidateSessionData = true
+		result := &models.CheckResult{}
+		c.checkSessionData(context.Background(), validating, []byte(sessionDataMaster), result)
+		require.NotNil(t, result.SessionData[1].Error)
+		assert.Equal(t, http.StatusNotFound, result.SessionData[1].Error.StatusCode)
+	})
+}
@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"sync"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// sizeTrendTracker хранит между проверками базовую линию битрейта вариантов,
+// измеренного по размерам сегментов
+type sizeTrendTracker struct {
+	mu    sync.Mutex
+	state map[string]*sizeTrend
+}
+
+type sizeTrend struct {
+	// samples битрейты последних проверок без падения, не больше Window
+	samples []float64
+	// low проверки подряд с битрейтом ниже порога
+	low int
+}
+
+func newSizeTrendTracker() *sizeTrendTracker {
+	return &sizeTrendTracker{state: make(map[string]*sizeTrend)}
+}
+
+// Observe учитывает битрейт варианта и сообщает, что он ниже базовой линии
+// cfg.Checks проверок подряд. Пока битрейт упавший, базовая линия не обновляется,
+// чтобы заставка не стала новой нормой. До накопления cfg.Window проверок
+// падение не определяется.
+func (s *sizeTrendTracker) Observe(stream, playlistURL string, bitrate float64, cfg models.SizeAnomalyConfig) bool {
+	key := stream + "|" + playlistURL
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.state[key]
+	if !ok {
+		st = &sizeTrend{}
+		s.state[key] = st
+	}
+
+	if len(st.samples) >= cfg.Window && bitrate < mean(st.samples)*cfg.Threshold {
+		st.low++
+		return st.low >= cfg.Checks
+	}
+
+	st.low = 0
+	st.samples = append(st.samples, bitrate)
+	if extra := len(st.samples) - cfg.Window; extra > 0 {
+		st.samples = append(st.samples[:0], st.samples[extra:]...)
+	}
+	return false
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// recordSizeAnomaly сравнивает измеренный битрейт варианта с базовой линией
+// и экспортирует признак устойчивого падения
+func (c *StreamChecker) recordSizeAnomaly(
+	stream models.StreamConfig,
+	playlistURL, bandwidth, resolution string,
+	bitrate float64,
+) bool {
+	if stream.SizeAnomaly == nil {
+		return false
+	}
+
+	anomaly := c.sizeTrend.Observe(stream.Name, playlistURL, bitrate, stream.SizeAnomaly.WithDefaults())
+	c.metrics.SetVariantSizeAnomaly(stream.Name, bandwidth, resolution, anomaly)
+	return anomaly
+}
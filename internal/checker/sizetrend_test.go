@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSizeTrendTracker_Observe(t *testing.T) {
+	tracker := newSizeTrendTracker()
+	cfg := models.SizeAnomalyConfig{Window: 3, Threshold: 0.5, Checks: 2}
+	const url = "http://a/index.m3u8"
+
+	// Падение не определяется, пока не накоплена базовая линия
+	assert.False(t, tracker.Observe("stream", url, 1000, cfg))
+	assert.False(t, tracker.Observe("stream", url, 100, cfg))
+	assert.False(t, tracker.Observe("stream", url, 1000, cfg))
+
+	// Базовая линия 700: одиночное падение не срабатывает, устойчивое срабатывает
+	assert.False(t, tracker.Observe("stream", url, 300, cfg))
+	assert.True(t, tracker.Observe("stream", url, 300, cfg))
+	assert.True(t, tracker.Observe("stream", url, 300, cfg), "baseline is frozen during the drop")
+	assert.False(t, tracker.Observe("other", url, 300, cfg), "streams are tracked separately")
+
+	// Восстановление сбрасывает счетчик, окно сдвигается: (1000+1000+1000)/3
+	assert.False(t, tracker.Observe("stream", url, 1000, cfg))
+	assert.False(t, tracker.Observe("stream", url, 1000, cfg))
+	assert.False(t, tracker.Observe("stream", url, 400, cfg))
+	assert.True(t, tracker.Observe("stream", url, 400, cfg))
+}
+
+func TestSizeAnomalyConfig_WithDefaults(t *testing.T) {
+	assert.Equal(t, models.SizeAnomalyConfig{
+		Window:    models.DefaultSizeAnomalyWindow,
+		Threshold: models.DefaultSizeAnomalyThreshold,
+		Checks:    models.DefaultSizeAnomalyChecks,
+	}, models.SizeAnomalyConfig{}.WithDefaults())
+
+	cfg := models.SizeAnomalyConfig{Window: 5, Threshold: 0.3, Checks: 1}
+	assert.Equal(t, cfg, cfg.WithDefaults())
+}
+
+func TestStreamChecker_RecordSizeAnomaly(t *testing.T) {
+	metrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), metrics, 1)
+
+	// Без size_anomaly метрика не публикуется
+	assert.False(t, c.recordSizeAnomaly(models.StreamConfig{Name: "test_stream"}, "http://a/v.m3u8", "1000000", "", 100))
+
+	stream := models.StreamConfig{
+		Name:        "test_stream",
+		SizeAnomaly: &models.SizeAnomalyConfig{Window: 2, Checks: 1},
+	}
+	metrics.On("SetVariantSizeAnomaly", "test_stream", "1000000", "", false).Times(2).Return()
+	metrics.On("SetVariantSizeAnomaly", "test_stream", "1000000", "", true).Once().Return()
+
+	assert.False(t, c.recordSizeAnomaly(stream, "http://a/v.m3u8", "1000000", "", 1000))
+	assert.False(t, c.recordSizeAnomaly(stream, "http://a/v.m3u8", "1000000", "", 1000))
+	assert.True(t, c.recordSizeAnomaly(stream, "http://a/v.m3u8", "1000000", "", 200))
+	metrics.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package checker
+
+import (
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// evaluateSLO сравнивает результат проверки с целями SLO стрима.
+// Нарушения не меняют успешность проверки, а публикуются отдельной метрикой.
+func (c *StreamChecker) evaluateSLO(stream models.StreamConfig, result *models.CheckResult) {
+	if stream.SLO == nil {
+		return
+	}
+	slo := stream.SLO
+
+	if slo.MaxPlaylistLatency > 0 {
+		violated := result.PlaylistResponseTime > slo.MaxPlaylistLatency
+		c.setSLO(stream.Name, models.SLOPlaylistLatency, violated, result)
+	}
+
+	if slo.MaxSegmentDownloadRatio > 0 {
+		violated := false
+		for _, check := range result.Segments.Details {
+			if check.DownloadRatio > slo.MaxSegmentDownloadRatio {
+				violated = true
+				break
+			}
+		}
+		c.setSLO(stream.Name, models.SLOSegmentDownload, violated, result)
+	}
+}
+
+// setSLO публикует состояние цели SLO и запоминает нарушение в результате
+func (c *StreamChecker) setSLO(name, slo string, violated bool, result *models.CheckResult) {
+	c.metrics.SetSLOViolation(name, slo, violated)
+	if violated {
+		result.SLOViolations = append(result.SLOViolations, slo)
+	}
+}
@@ -0,0 +1,50 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamChecker_EvaluateSLO(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+
+	stream := models.StreamConfig{
+		Name: "test_stream",
+		SLO: &models.SLOConfig{
+			MaxPlaylistLatency:      500 * time.Millisecond,
+			MaxSegmentDownloadRatio: 0.5,
+		},
+	}
+	result := &models.CheckResult{
+		Success:              true,
+		PlaylistResponseTime: 200 * time.Millisecond,
+		Segments: models.SegmentResults{Details: []models.SegmentCheck{
+			{URL: "seg1.ts", Success: true, DownloadRatio: 0.2},
+			{URL: "seg2.ts", Success: true, DownloadRatio: 0.8},
+		}},
+	}
+
+	mockMetrics.On("SetSLOViolation", "test_stream", models.SLOPlaylistLatency, false).Return().Once()
+	mockMetrics.On("SetSLOViolation", "test_stream", models.SLOSegmentDownload, true).Return().Once()
+
+	c.evaluateSLO(stream, result)
+
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{models.SLOSegmentDownload}, result.SLOViolations)
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestStreamChecker_EvaluateSLO_NotConfigured(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+
+	result := &models.CheckResult{PlaylistResponseTime: time.Minute}
+	c.evaluateSLO(models.StreamConfig{Name: "test_stream"}, result)
+
+	assert.Empty(t, result.SLOViolations)
+	mockMetrics.AssertNotCalled(t, "SetSLOViolation", "test_stream", models.SLOPlaylistLatency, true)
+}
@@ -0,0 +1,57 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// stageTimer накапливает длительности этапов проверки, в том числе из задач пула
+type stageTimer struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+type stageTimerKey struct{}
+
+// withStageTimer привязывает к контексту проверки учет длительностей этапов
+func withStageTimer(ctx context.Context) (context.Context, *stageTimer) {
+	timer := &stageTimer{durations: make(map[string]time.Duration)}
+	return context.WithValue(ctx, stageTimerKey{}, timer), timer
+}
+
+// observeStage добавляет к этапу время, прошедшее с start. Без учета в контексте ничего не делает.
+func observeStage(ctx context.Context, stage string, start time.Time) {
+	timer, _ := ctx.Value(stageTimerKey{}).(*stageTimer)
+	if timer == nil {
+		return
+	}
+	elapsed := time.Since(start)
+
+	timer.mu.Lock()
+	defer timer.mu.Unlock()
+	timer.durations[stage] += elapsed
+}
+
+// snapshot возвращает накопленные длительности этапов или nil, если этапов не было
+func (t *stageTimer) snapshot() map[string]time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.durations) == 0 {
+		return nil
+	}
+	stages := make(map[string]time.Duration, len(t.durations))
+	for stage, d := range t.durations {
+		stages[stage] = d
+	}
+	return stages
+}
+
+// recordStages публикует длительности этапов проверки
+func (c *StreamChecker) recordStages(stream string, result *models.CheckResult) {
+	for stage, d := range result.Stages {
+		c.metrics.RecordStageDuration(stream, stage, d.Seconds())
+	}
+}
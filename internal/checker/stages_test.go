@@ -0,0 +1,28 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStageTimer(t *testing.T) {
+	ctx, timer := withStageTimer(context.Background())
+	assert.Nil(t, timer.snapshot())
+
+	start := time.Now().Add(-time.Second)
+	observeStage(ctx, "fetch", start)
+	observeStage(ctx, "fetch", start)
+
+	stages := timer.snapshot()
+	assert.GreaterOrEqual(t, stages["fetch"], 2*time.Second)
+
+	// Снимок не меняется при последующих наблюдениях
+	observeStage(ctx, "fetch", start)
+	assert.Greater(t, timer.snapshot()["fetch"], stages["fetch"])
+
+	// Без учета в контексте наблюдение игнорируется
+	observeStage(context.Background(), "fetch", start)
+}
@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// staleTargetFactor плейлист считается зависшим, если не обновлялся дольше
+// полутора целевых длительностей сегмента
+const staleTargetFactor = 1.5
+
+// stalenessTracker запоминает между проверками последнюю позицию медиаплейлистов
+type stalenessTracker struct {
+	mu    sync.Mutex
+	state map[string]*playlistState
+	now   func() time.Time
+}
+
+type playlistState struct {
+	sequence uint64
+	lastURI  string
+	advanced time.Time
+}
+
+func newStalenessTracker() *stalenessTracker {
+	return &stalenessTracker{
+		state: make(map[string]*playlistState),
+		now:   time.Now,
+	}
+}
+
+// Observe сравнивает плейлист с предыдущей проверкой и возвращает время,
+// в течение которого он не продвигался
+func (s *stalenessTracker) Observe(stream, playlistURL string, media *m3u8.MediaPlaylist) time.Duration {
+	sequence, lastURI := playlistPosition(media)
+	key := stream + "|" + playlistURL
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.state[key]
+	if !ok || st.sequence != sequence || st.lastURI != lastURI {
+		s.state[key] = &playlistState{sequence: sequence, lastURI: lastURI, advanced: now}
+		return 0
+	}
+	return now.Sub(st.advanced)
+}
+
+// playlistPosition возвращает media sequence и URI последнего сегмента
+func playlistPosition(media *m3u8.MediaPlaylist) (uint64, string) {
+	var lastURI string
+	for i := int(media.Count()) - 1; i >= 0; i-- {
+		if seg := media.Segments[i]; seg != nil {
+			lastURI = seg.URI
+			break
+		}
+	}
+	return media.SeqNo, lastURI
+}
+
+// recordStaleness отмечает результат зависшим, если live-плейлист перестал обновляться.
+// VOD-плейлисты не меняются и не проверяются.
+func (c *StreamChecker) recordStaleness(
+	stream models.StreamConfig,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	result *models.CheckResult,
+) {
+	if media == nil || isVOD(media) || media.TargetDuration <= 0 {
+		return
+	}
+
+	unchanged := c.staleness.Observe(stream.Name, playlistURL, media)
+	limit := time.Duration(media.TargetDuration * staleTargetFactor * float64(time.Second))
+	if unchanged <= limit {
+		return
+	}
+
+	result.Stale = true
+	result.StaleCause = models.StaleCauseUnchanged
+	if result.Error == nil {
+		result.Error = &models.CheckError{
+			Type: models.ErrPlaylistStale,
+			Message: fmt.Sprintf("playlist %s has not advanced for %s (media sequence %d)",
+				playlistURL, unchanged.Truncate(time.Millisecond), media.SeqNo),
+		}
+	}
+}
+
+// staleError возвращает ошибку проверки по вердикту зависания. Ошибка другого
+// типа, найденная раньше, остается в результате, но не выдается за причину зависания.
+func staleError(result *models.CheckResult) error {
+	if result.Error != nil && result.Error.Type == models.ErrPlaylistStale {
+		return fmt.Errorf("playlist stale: %s", result.Error.Message)
+	}
+	switch result.StaleCause {
+	case models.StaleCauseCachedPlaylist:
+		return fmt.Errorf("playlist stale: media playlist is served from cache")
+	default:
+		return fmt.Errorf("playlist stale: media playlist has not advanced")
+	}
+}
@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const staleTestPlaylist = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:4
+#EXTINF:4.0,
+seg0.ts
+`
+
+func TestStalenessTracker_Observe(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newStalenessTracker()
+	tracker.now = func() time.Time { return now }
+
+	first := decodeMediaPlaylist(t, staleTestPlaylist)
+	assert.Zero(t, tracker.Observe("stream", "http://a/index.m3u8", first))
+
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, 5*time.Second, tracker.Observe("stream", "http://a/index.m3u8", first))
+	assert.Zero(t, tracker.Observe("stream", "http://b/index.m3u8", first), "variants are tracked separately")
+	assert.Zero(t, tracker.Observe("other", "http://a/index.m3u8", first), "streams are tracked separately")
+
+	// Новый сегмент сбрасывает время зависания
+	now = now.Add(5 * time.Second)
+	advanced := decodeMediaPlaylist(t, `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:4
+#EXTINF:4.0,
+seg0.ts
+#EXTINF:4.0,
+seg1.ts
+`)
+	assert.Zero(t, tracker.Observe("stream", "http://a/index.m3u8", advanced))
+}
+
+func TestStreamChecker_RecordStaleness(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), new(MockMetricsCollector), 1)
+	c.staleness.now = func() time.Time { return now }
+	stream := models.StreamConfig{Name: "test_stream"}
+	media := decodeMediaPlaylist(t, staleTestPlaylist)
+
+	result := &models.CheckResult{}
+	c.recordStaleness(stream, "http://a/index.m3u8", media, result)
+	assert.False(t, result.Stale)
+
+	// Обновление в пределах 1.5 целевой длительности допустимо
+	now = now.Add(6 * time.Second)
+	result = &models.CheckResult{}
+	c.recordStaleness(stream, "http://a/index.m3u8", media, result)
+	assert.False(t, result.Stale)
+
+	now = now.Add(time.Second)
+	result = &models.CheckResult{}
+	c.recordStaleness(stream, "http://a/index.m3u8", media, result)
+	assert.True(t, result.Stale)
+	assert.Equal(t, models.StaleCauseUnchanged, result.StaleCause)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrPlaylistStale, result.Error.Type)
+	assert.Contains(t, result.Error.Message, "has not advanced for 7s")
+
+	// VOD-плейлист не меняется по определению
+	media.Closed = true
+	result = &models.CheckResult{}
+	c.recordStaleness(stream, "http://a/index.m3u8", media, result)
+	assert.False(t, result.Stale)
+}
+
+func TestStaleError(t *testing.T) {
+	result := &models.CheckResult{
+		Stale:      true,
+		StaleCause: models.StaleCauseUnchanged,
+		Error:      &models.CheckError{Type: models.ErrPlaylistStale, Message: "playlist http://a/index.m3u8 has not advanced for 7s"},
+	}
+	assert.EqualError(t, staleError(result), "playlist stale: playlist http://a/index.m3u8 has not advanced for 7s")
+
+	// Более ранняя ошибка другого типа сохраняется, но не описывает зависание
+	result = &models.CheckResult{
+		Stale:      true,
+		StaleCause: models.StaleCauseCachedPlaylist,
+		Error:      &models.CheckError{Type: models.ErrRendition, Message: "audio rendition: 404"},
+	}
+	assert.EqualError(t, staleError(result), "playlist stale: media playlist is served from cache")
+	assert.Equal(t, models.ErrRendition, result.Error.Type)
+}
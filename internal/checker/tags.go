@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// maxUnknownTags ограничивает число учитываемых тегов стрима, чтобы
+// ошибочный упаковщик не раздул кардинальность метрик
+const maxUnknownTags = 64
+
+// tagInventory накапливает нестандартные теги стримов между проверками
+type tagInventory struct {
+	mu   sync.Mutex
+	tags map[string]map[string]*models.UnknownTag
+	now  func() time.Time
+}
+
+func newTagInventory() *tagInventory {
+	return &tagInventory{
+		tags: make(map[string]map[string]*models.UnknownTag),
+		now:  time.Now,
+	}
+}
+
+// Observe учитывает теги проверки и возвращает впервые встреченные
+func (t *tagInventory) Observe(stream string, tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen, ok := t.tags[stream]
+	if !ok {
+		seen = make(map[string]*models.UnknownTag)
+		t.tags[stream] = seen
+	}
+
+	var added []string
+	for _, tag := range tags {
+		if u, ok := seen[tag]; ok {
+			u.LastSeen = now
+			u.Checks++
+			continue
+		}
+		if len(seen) >= maxUnknownTags {
+			continue
+		}
+		seen[tag] = &models.UnknownTag{Tag: tag, FirstSeen: now, LastSeen: now, Checks: 1}
+		added = append(added, tag)
+	}
+	return added
+}
+
+// List возвращает теги стрима, отсортированные по имени
+func (t *tagInventory) List(stream string) []models.UnknownTag {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tags := make([]models.UnknownTag, 0, len(t.tags[stream]))
+	for _, u := range t.tags[stream] {
+		tags = append(tags, *u)
+	}
+	slices.SortFunc(tags, func(a, b models.UnknownTag) int {
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return tags
+}
+
+// UnknownTags возвращает нестандартные теги, встреченные в плейлистах стрима
+func (c *StreamChecker) UnknownTags(stream string) []models.UnknownTag {
+	return c.tagInventory.List(stream)
+}
+
+// recordUnknownTags учитывает теги проверки и публикует info-метрику для новых
+func (c *StreamChecker) recordUnknownTags(stream string, tags []string) {
+	for _, tag := range c.tagInventory.Observe(stream, tags) {
+		c.metrics.SetUnknownTag(stream, tag)
+	}
+}
+
+// mergeTags объединяет списки тегов без повторов
+func mergeTags(tags []string, more ...string) []string {
+	tags = append(tags, more...)
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
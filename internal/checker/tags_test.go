@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagInventory(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	inventory := newTagInventory()
+	inventory.now = func() time.Time { return now }
+
+	assert.Nil(t, inventory.Observe("stream", nil))
+	assert.Equal(t, []string{"EXT-X-CUE-IN", "EXT-X-CUE-OUT"},
+		inventory.Observe("stream", []string{"EXT-X-CUE-IN", "EXT-X-CUE-OUT"}))
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, []string{"X-VENDOR"}, inventory.Observe("stream", []string{"EXT-X-CUE-OUT", "X-VENDOR"}))
+
+	tags := inventory.List("stream")
+	require.Len(t, tags, 3)
+	assert.Equal(t, "EXT-X-CUE-IN", tags[0].Tag)
+	assert.Equal(t, int64(1), tags[0].Checks)
+	assert.Equal(t, "EXT-X-CUE-OUT", tags[1].Tag)
+	assert.Equal(t, int64(2), tags[1].Checks)
+	assert.Equal(t, now.Add(-time.Minute), tags[1].FirstSeen)
+	assert.Equal(t, now, tags[1].LastSeen)
+
+	assert.Empty(t, inventory.List("other"))
+}
+
+func TestTagInventory_Limit(t *testing.T) {
+	inventory := newTagInventory()
+
+	tags := make([]string, 0, maxUnknownTags+10)
+	for i := range maxUnknownTags + 10 {
+		tags = append(tags, fmt.Sprintf("X-TAG-%03d", i))
+	}
+
+	assert.Len(t, inventory.Observe("stream", tags), maxUnknownTags)
+	assert.Len(t, inventory.List("stream"), maxUnknownTags)
+}
+
+func TestMergeTags(t *testing.T) {
+	assert.Equal(t, []string{"A", "B", "C"}, mergeTags([]string{"B", "C"}, "A", "B"))
+	assert.Nil(t, mergeTags(nil))
+}
@@ -0,0 +1,64 @@
+package checker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// taskTracker учитывает запущенные, завершенные и ожидающие воркера задачи пула
+type taskTracker struct {
+	started  atomic.Int64
+	finished atomic.Int64
+	queued   atomic.Int64
+	// Суммарное ожидание свободного воркера, наносекунды
+	waited atomic.Int64
+}
+
+func (t *taskTracker) active() int64 {
+	return t.started.Load() - t.finished.Load()
+}
+
+func (t *taskTracker) queueWait() time.Duration {
+	return time.Duration(t.waited.Load())
+}
+
+type taskTrackerKey struct{}
+
+// withTaskTracker привязывает к контексту проверки учет ее задач
+func withTaskTracker(ctx context.Context) (context.Context, *taskTracker) {
+	tracker := &taskTracker{}
+	return context.WithValue(ctx, taskTrackerKey{}, tracker), tracker
+}
+
+func taskTrackerFrom(ctx context.Context) *taskTracker {
+	tracker, _ := ctx.Value(taskTrackerKey{}).(*taskTracker)
+	return tracker
+}
+
+// SetLeakHandler задает обработчик задач, не завершившихся к концу проверки
+func (c *StreamChecker) SetLeakHandler(fn func(stream string, leaked int64)) {
+	c.onLeak = fn
+}
+
+// ActiveTasks возвращает число выполняющихся задач всех проверок
+func (c *StreamChecker) ActiveTasks() int64 {
+	return c.tasks.active()
+}
+
+// assertTasksFinished проверяет, что все задачи проверки вернулись до ее завершения
+func (c *StreamChecker) assertTasksFinished(stream string, tracker *taskTracker) {
+	leaked := tracker.active()
+	if leaked == 0 {
+		return
+	}
+
+	c.logger.Error("Check finished with running tasks",
+		zap.String("stream", stream),
+		zap.Int64("leaked", leaked))
+	if c.onLeak != nil {
+		c.onLeak(stream, leaked)
+	}
+}
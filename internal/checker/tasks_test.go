@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskTracker_AssertTasksFinished(t *testing.T) {
+	c := newPoolChecker(2)
+	assert.NoError(t, c.Start())
+	defer func() { assert.NoError(t, c.Stop()) }()
+
+	var leaks []int64
+	c.SetLeakHandler(func(stream string, leaked int64) {
+		assert.Equal(t, "test_stream", stream)
+		leaks = append(leaks, leaked)
+	})
+
+	ctx, tracker := withTaskTracker(context.Background())
+	c.runTasks(ctx, []func(){
+		func() { time.Sleep(5 * time.Millisecond) },
+		func() {},
+	})
+	assert.Equal(t, int64(2), tracker.finished.Load())
+	assert.Zero(t, c.ActiveTasks())
+
+	c.assertTasksFinished("test_stream", tracker)
+	assert.Empty(t, leaks)
+
+	// Незавершенная задача считается утечкой
+	tracker.started.Add(1)
+	c.assertTasksFinished("test_stream", tracker)
+	assert.Equal(t, []int64{1}, leaks)
+}
+
+func TestTaskTracker_ActiveTasks(t *testing.T) {
+	c := newPoolChecker(2)
+	assert.NoError(t, c.Start())
+	defer func() { assert.NoError(t, c.Stop()) }()
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		c.runTasks(context.Background(), []func(){func() { <-release }})
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return c.ActiveTasks() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	<-done
+	assert.Zero(t, c.ActiveTasks())
+}
@@ -0,0 +1,19 @@
+package checker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// SetTracing включает трассировку: каждой проверке назначается trace_id, который
+// передается HTTP-клиенту через контекст и прикрепляется exemplar к гистограммам
+func (c *StreamChecker) SetTracing(enabled bool) {
+	c.tracing = enabled
+}
+
+// newTraceID генерирует идентификатор трассировки W3C Trace Context из 32 hex-символов
+func newTraceID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
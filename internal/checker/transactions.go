@@ -0,0 +1,41 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// SetTransactionLog включает журнал HTTP-транзакций: транзакции неуспешных проверок
+// сохраняются в store. maxBodyBytes 0 - models.DefaultTransactionBodyBytes.
+func (c *StreamChecker) SetTransactionLog(store models.TransactionStore, maxBodyBytes int) {
+	c.transactions = store
+	c.maxBodyBytes = cmp.Or(maxBodyBytes, models.DefaultTransactionBodyBytes)
+}
+
+// withTransactionLog привязывает к контексту проверки журнал транзакций и возвращает
+// функцию, сохраняющую журнал неуспешной проверки. Без журнала функция ничего не делает.
+func (c *StreamChecker) withTransactionLog(
+	ctx context.Context,
+	result *models.CheckResult,
+) (context.Context, func()) {
+	if c.transactions == nil {
+		return ctx, func() {}
+	}
+
+	log := models.NewTransactionLog(c.maxBodyBytes)
+	return models.WithTransactionLog(ctx, log), func() {
+		if result.Success {
+			return
+		}
+		c.transactions.Save(&models.TransactionBundle{
+			Stream:       result.StreamName,
+			CheckID:      result.CheckID,
+			TraceID:      result.TraceID,
+			Timestamp:    result.Timestamp,
+			Error:        result.Error,
+			Transactions: log.Transactions(),
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamChecker_TransactionLog(t *testing.T) {
+	transactions := store.NewTransactionStore()
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), new(MockMetricsCollector), 1)
+
+	// Без журнала контекст не меняется
+	ctx, save := c.withTransactionLog(context.Background(), &models.CheckResult{})
+	assert.Nil(t, models.TransactionLogFrom(ctx))
+	save()
+
+	c.SetTransactionLog(transactions, 0)
+	assert.Equal(t, models.DefaultTransactionBodyBytes, c.maxBodyBytes)
+
+	// Успешная проверка не сохраняется
+	ok := &models.CheckResult{StreamName: "test_stream", Success: true}
+	ctx, save = c.withTransactionLog(context.Background(), ok)
+	models.TransactionLogFrom(ctx).Add(models.HTTPTransaction{URL: "http://a/index.m3u8"})
+	save()
+	_, found := transactions.Get("test_stream")
+	assert.False(t, found)
+
+	failed := &models.CheckResult{StreamName: "test_stream", CheckID: "0123456789abcdef"}
+	ctx, save = c.withTransactionLog(context.Background(), failed)
+	models.TransactionLogFrom(ctx).Add(models.HTTPTransaction{URL: "http://a/index.m3u8", StatusCode: 503})
+	failed.Error = &models.CheckError{Type: models.ErrPlaylistDownload}
+	save()
+
+	bundle, found := transactions.Get("test_stream")
+	require.True(t, found)
+	assert.Equal(t, "0123456789abcdef", bundle.CheckID)
+	assert.Equal(t, models.ErrPlaylistDownload, bundle.Error.Type)
+	require.Len(t, bundle.Transactions, 1)
+	assert.Equal(t, 503, bundle.Transactions[0].StatusCode)
+}
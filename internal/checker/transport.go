@@ -0,0 +1,30 @@
+package checker
+
+import (
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// recordTransportErrors сводит ошибки и тайминг транспортного потока проверенных сегментов.
+// Ошибки счетчиков непрерывности не делают сегмент недоступным и только учитываются в метриках.
+func (c *StreamChecker) recordTransportErrors(stream models.StreamConfig, result *models.CheckResult) {
+	var packets, nullPackets int
+	for _, seg := range result.Segments.Details {
+		result.CCErrors += seg.CCErrors
+		packets += seg.Packets
+		nullPackets += seg.NullPackets
+		result.PCRMaxInterval = max(result.PCRMaxInterval, seg.PCRMaxInterval)
+		result.PCRJitter = max(result.PCRJitter, seg.PCRJitter)
+	}
+
+	if result.CCErrors > 0 {
+		c.metrics.AddCCErrors(stream.Name, result.CCErrors)
+	}
+	// Интервал есть только при двух и более PCR: без него тайминг не измерялся
+	if result.PCRMaxInterval > 0 {
+		c.metrics.SetPCRStats(stream.Name, result.PCRMaxInterval.Seconds(), result.PCRJitter.Seconds())
+	}
+	if packets > 0 {
+		result.NullPacketRatio = float64(nullPackets) / float64(packets)
+		c.metrics.SetNullPacketRatio(stream.Name, result.NullPacketRatio)
+	}
+}
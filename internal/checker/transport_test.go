@@ -0,0 +1,46 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamChecker_RecordTransportErrors(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	mockMetrics.On("AddCCErrors", "test_stream", 3).Return().Once()
+
+	result := &models.CheckResult{Segments: models.SegmentResults{Details: []models.SegmentCheck{
+		{CCErrors: 1},
+		{CCErrors: 2},
+		{},
+	}}}
+	c.recordTransportErrors(stream, result)
+	assert.Equal(t, 3, result.CCErrors)
+
+	// Без ошибок метрика не трогается, без TS-пакетов доля заполнения не считается
+	c.recordTransportErrors(stream, &models.CheckResult{})
+
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestStreamChecker_RecordNullPacketRatio(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+	stream := models.StreamConfig{Name: "test_stream"}
+
+	mockMetrics.On("SetNullPacketRatio", "test_stream", 0.25).Return().Once()
+
+	result := &models.CheckResult{Segments: models.SegmentResults{Details: []models.SegmentCheck{
+		{Packets: 100, NullPackets: 40},
+		{Packets: 300, NullPackets: 60},
+	}}}
+	c.recordTransportErrors(stream, result)
+	assert.Equal(t, 0.25, result.NullPacketRatio)
+
+	mockMetrics.AssertExpectations(t)
+}
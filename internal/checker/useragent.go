@@ -0,0 +1,42 @@
+package checker
+
+import (
+	"strings"
+	"sync"
+)
+
+// userAgentPresets User-Agent распространенных плееров, которые можно указать в user_agents по имени
+var userAgentPresets = map[string]string{
+	"applecoremedia": "AppleCoreMedia/1.0.0.21E236 (iPhone; U; CPU OS 17_4 like Mac OS X; en_us)",
+	"exoplayer":      "ExoPlayerLib/2.19.1 (Linux; Android 14) ExoPlayerLib/2.19.1",
+	"hlsjs": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
+		"(KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+}
+
+// resolveUserAgent возвращает строку User-Agent по имени из userAgentPresets или саму строку
+func resolveUserAgent(agent string) string {
+	if preset, ok := userAgentPresets[strings.ToLower(agent)]; ok {
+		return preset
+	}
+	return agent
+}
+
+// userAgentRotation запоминает между проверками, какой User-Agent стрима использовать следующим
+type userAgentRotation struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func newUserAgentRotation() *userAgentRotation {
+	return &userAgentRotation{next: make(map[string]int)}
+}
+
+// Next возвращает User-Agent для очередной проверки стрима по кругу
+func (r *userAgentRotation) Next(stream string, agents []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.next[stream] % len(agents)
+	r.next[stream] = i + 1
+	return agents[i]
+}
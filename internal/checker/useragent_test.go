@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserAgentRotation_Next(t *testing.T) {
+	r := newUserAgentRotation()
+	agents := []string{"applecoremedia", "exoplayer", "hlsjs"}
+
+	var got []string
+	for range 4 {
+		got = append(got, r.Next("a", agents))
+	}
+	assert.Equal(t, []string{"applecoremedia", "exoplayer", "hlsjs", "applecoremedia"}, got)
+	assert.Equal(t, "applecoremedia", r.Next("b", agents), "streams rotate separately")
+
+	// Сокращение списка после перезагрузки конфигурации
+	assert.Equal(t, "exoplayer", r.Next("a", agents[1:2]))
+}
+
+func TestResolveUserAgent(t *testing.T) {
+	assert.Contains(t, resolveUserAgent("AppleCoreMedia"), "AppleCoreMedia/")
+	assert.Contains(t, resolveUserAgent("exoplayer"), "ExoPlayerLib/")
+	assert.Equal(t, "VLC/3.0.20 LibVLC/3.0.20", resolveUserAgent("VLC/3.0.20 LibVLC/3.0.20"))
+}
+
+func TestStreamChecker_Check_UserAgents(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, new(MockValidator), mockMetrics, 1)
+
+	// Только запросы с User-Agent hls.js получают ошибку
+	withAgent := func(agent string) any {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			return models.RequestAuthFrom(ctx).UserAgent == resolveUserAgent(agent)
+		})
+	}
+	const url = "http://test.com/master.m3u8"
+	mockClient.On("GetPlaylist", withAgent("exoplayer"), url).Return(nil, errors.New("unexpected status code: 503"))
+	mockClient.On("GetPlaylist", withAgent("Custom/1.0"), url).Return(nil, errors.New("unexpected status code: 503"))
+
+	mockMetrics.On("SetStreamUp", "test_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "test_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordError", "test_stream", string(models.ErrPlaylistDownload)).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", int64(0)).Return()
+	mockMetrics.On("SetUserAgentUp", "test_stream", "exoplayer", false).Return()
+	mockMetrics.On("SetUserAgentUp", "test_stream", "Custom/1.0", false).Return()
+
+	stream := models.StreamConfig{
+		Name:       "test_stream",
+		URL:        url,
+		UserAgents: []string{"exoplayer", "Custom/1.0"},
+	}
+	for _, want := range []string{"exoplayer", "Custom/1.0", "exoplayer"} {
+		result, err := checker.Check(context.Background(), stream)
+		assert.Error(t, err)
+		assert.Equal(t, want, result.UserAgent)
+	}
+
+	mockClient.AssertExpectations(t)
+	mockMetrics.AssertExpectations(t)
+}
@@ -1,8 +1,10 @@
 package checker
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
 	"crypto/rand"
@@ -25,13 +27,36 @@ func getRandomIndex(limit int64) (int, error) {
 
 type HLSValidator struct {
 	segmentValidator models.SegmentValidator // встраиваем интерфейс
+	maxDurationCV    float64
+	// Проверки длительностей сегментов по EXT-X-TARGETDURATION
+	targetDuration    bool
+	durationTolerance float64
 }
 
+// minDurationSamples минимальное число сегментов для оценки разброса длительностей
+const minDurationSamples = 3
+
 func NewHLSValidator() *HLSValidator {
 	return &HLSValidator{
 		segmentValidator: NewSegmentValidator(), // создаем конкретную реализацию
 	}
 }
+
+// SetMaxDurationCV задает порог коэффициента вариации длительностей сегментов (0 - без проверки)
+func (v *HLSValidator) SetMaxDurationCV(cv float64) {
+	v.maxDurationCV = cv
+}
+
+// SetTargetDurationCheck включает проверку EXTINF <= EXT-X-TARGETDURATION по RFC 8216
+func (v *HLSValidator) SetTargetDurationCheck(enabled bool) {
+	v.targetDuration = enabled
+}
+
+// SetDurationTolerance задает допустимое отклонение EXTINF от EXT-X-TARGETDURATION, доля (0 - без проверки)
+func (v *HLSValidator) SetDurationTolerance(tolerance float64) {
+	v.durationTolerance = tolerance
+}
+
 func (v *HLSValidator) ValidateSegment(
 	segment *models.SegmentData,
 	validation *models.MediaValidation,
@@ -89,10 +114,132 @@ func (v *HLSValidator) ValidateMedia(playlist *m3u8.MediaPlaylist) error {
 		prevSeq = seg.SeqId
 	}
 
+	if err := v.validateTargetDuration(playlist); err != nil {
+		return err
+	}
+	return v.validateDurations(playlist)
+}
+
+// validateTargetDuration сравнивает длительности сегментов с EXT-X-TARGETDURATION.
+// Допуск не применяется к сегменту перед разрывом и к последнему сегменту VOD-плейлиста:
+// они бывают короче остальных.
+func (v *HLSValidator) validateTargetDuration(playlist *m3u8.MediaPlaylist) error {
+	if !v.targetDuration && v.durationTolerance <= 0 {
+		return nil
+	}
+	target := playlist.TargetDuration
+	if target <= 0 {
+		return nil
+	}
+
+	segments := playlist.Segments[:playlist.Count()]
+	for i, seg := range segments {
+		if seg == nil {
+			continue
+		}
+		if v.targetDuration && math.Round(seg.Duration) > target {
+			return &models.SpecViolationError{
+				Rule:     models.SpecRuleTargetDuration,
+				URI:      seg.URI,
+				Duration: seg.Duration,
+				Target:   target,
+			}
+		}
+
+		if v.durationTolerance <= 0 {
+			continue
+		}
+		last := i == len(segments)-1
+		beforeDiscontinuity := !last && segments[i+1] != nil && segments[i+1].Discontinuity
+		if (last && playlist.Closed) || beforeDiscontinuity {
+			continue
+		}
+		if math.Abs(seg.Duration-target) > target*v.durationTolerance {
+			return &models.SpecViolationError{
+				Rule:      models.SpecRuleDurationTolerance,
+				URI:       seg.URI,
+				Duration:  seg.Duration,
+				Target:    target,
+				Tolerance: v.durationTolerance,
+			}
+		}
+	}
 	return nil
 }
 
-func (c *StreamChecker) selectSegments(playlist *m3u8.MediaPlaylist, mode string) []*m3u8.MediaSegment {
+// validateDurations сравнивает коэффициент вариации длительностей сегментов с порогом
+func (v *HLSValidator) validateDurations(playlist *m3u8.MediaPlaylist) error {
+	if v.maxDurationCV <= 0 {
+		return nil
+	}
+
+	cv, minDur, maxDur, ok := durationVariation(playlist)
+	if !ok || cv <= v.maxDurationCV {
+		return nil
+	}
+
+	return &models.SegmentDurationError{
+		CV:        cv,
+		Threshold: v.maxDurationCV,
+		Min:       minDur,
+		Max:       maxDur,
+	}
+}
+
+// durationVariation считает коэффициент вариации (stddev/mean) длительностей сегментов.
+// Последний сегмент VOD-плейлиста может быть короче остальных и не учитывается.
+func durationVariation(playlist *m3u8.MediaPlaylist) (cv, minDur, maxDur float64, ok bool) {
+	durations := make([]float64, 0, playlist.Count())
+	for _, seg := range playlist.Segments {
+		if seg != nil {
+			durations = append(durations, seg.Duration)
+		}
+	}
+	if playlist.Closed && len(durations) > 0 {
+		durations = durations[:len(durations)-1]
+	}
+	if len(durations) < minDurationSamples {
+		return 0, 0, 0, false
+	}
+
+	minDur, maxDur = durations[0], durations[0]
+	var sum float64
+	for _, d := range durations {
+		sum += d
+		minDur = math.Min(minDur, d)
+		maxDur = math.Max(maxDur, d)
+	}
+	mean := sum / float64(len(durations))
+	if mean <= 0 {
+		return 0, 0, 0, false
+	}
+
+	var variance float64
+	for _, d := range durations {
+		variance += (d - mean) * (d - mean)
+	}
+	variance /= float64(len(durations))
+
+	return math.Sqrt(variance) / mean, minDur, maxDur, true
+}
+
+// defaultSegmentSample число сегментов для режимов random и newest_n по умолчанию
+const defaultSegmentSample = 3
+
+// SetSegmentSample задает число сегментов для режимов random и newest_n,
+// если у стрима не задан собственный segment_sample. Значение 0 - defaultSegmentSample.
+func (c *StreamChecker) SetSegmentSample(n int) {
+	c.sample = n
+}
+
+// segmentSampleSize возвращает число сегментов для режимов random и newest_n
+func (c *StreamChecker) segmentSampleSize(cfg models.StreamConfig) int {
+	return cmp.Or(max(cfg.SegmentSample, 0), max(c.sample, 0), defaultSegmentSample)
+}
+
+// selectSegments выбирает сегменты плейлиста для проверки. sample - число сегментов
+// для режимов random и newest_n.
+func (c *StreamChecker) selectSegments(playlist *m3u8.MediaPlaylist, mode string, sample int) []*m3u8.MediaSegment {
 	var segments []*m3u8.MediaSegment
 
 	switch mode {
@@ -117,9 +264,12 @@ func (c *StreamChecker) selectSegments(playlist *m3u8.MediaPlaylist, mode string
 			}
 		}
 
+	case models.CheckModePlaylistOnly:
+		// Сегменты не проверяются
+
 	case models.CheckModeRandom:
 		if playlist.Count() > 0 {
-			count := minInt(3, safeCount(playlist.Count()))
+			count := minInt(sample, safeCount(playlist.Count()))
 			seen := make(map[int]bool)
 
 			playlistCount := safeInt64(playlist.Count())
@@ -134,6 +284,16 @@ func (c *StreamChecker) selectSegments(playlist *m3u8.MediaPlaylist, mode string
 				}
 			}
 		}
+
+	case models.CheckModeNewestN:
+		// Последние сегменты у live-края в порядке плейлиста
+		live := playlist.Segments[:playlist.Count()]
+		start := max(len(live)-sample, 0)
+		for _, seg := range live[start:] {
+			if seg != nil {
+				segments = append(segments, seg)
+			}
+		}
 	}
 
 	return segments
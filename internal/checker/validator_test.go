@@ -7,6 +7,7 @@ import (
 	"github.com/grafov/m3u8"
 	"github.com/iudanet/hls_exporter/pkg/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHLSValidator_ValidateSegment(t *testing.T) {
@@ -104,6 +105,111 @@ func TestHLSValidator_ValidateSegment(t *testing.T) {
 	}
 }
 
+func TestHLSValidator_ValidateMedia_DurationVariation(t *testing.T) {
+	playlist := func(durations []float64, closed bool) *m3u8.MediaPlaylist {
+		p, err := m3u8.NewMediaPlaylist(uint(len(durations)), uint(len(durations)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, d := range durations {
+			if err := p.Append(fmt.Sprintf("seg%d.ts", i), d, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		p.Closed = closed
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		durations []float64
+		closed    bool
+		wantErr   bool
+	}{
+		{name: "stable durations", threshold: 0.3, durations: []float64{2, 2, 2, 2}},
+		{name: "small jitter", threshold: 0.3, durations: []float64{2, 2.1, 1.9, 2}},
+		{name: "misconfigured gop", threshold: 0.3, durations: []float64{2, 2, 9.8, 0.2}, wantErr: true},
+		{name: "check disabled", threshold: 0, durations: []float64{2, 2, 9.8, 0.2}},
+		{name: "too few segments", threshold: 0.3, durations: []float64{2, 9.8}},
+		{name: "short last vod segment", threshold: 0.3, durations: []float64{6, 6, 6, 0.5}, closed: true},
+		{name: "short last live segment", threshold: 0.3, durations: []float64{6, 6, 6, 0.5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewHLSValidator()
+			v.SetMaxDurationCV(tt.threshold)
+
+			err := v.ValidateMedia(playlist(tt.durations, tt.closed))
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			var durationErr *models.SegmentDurationError
+			assert.ErrorAs(t, err, &durationErr)
+			assert.Greater(t, durationErr.CV, tt.threshold)
+			assert.Equal(t, tt.threshold, durationErr.Threshold)
+		})
+	}
+}
+
+func TestHLSValidator_ValidateMedia_TargetDuration(t *testing.T) {
+	playlist := func(target float64, durations []float64, closed bool, discontinuity int) *m3u8.MediaPlaylist {
+		p, err := m3u8.NewMediaPlaylist(uint(len(durations)), uint(len(durations)))
+		require.NoError(t, err)
+		for i, d := range durations {
+			require.NoError(t, p.Append(fmt.Sprintf("seg%d.ts", i), d, ""))
+			// discontinuity - номер сегмента с EXT-X-DISCONTINUITY (0 - без разрыва)
+			if discontinuity > 0 && i == discontinuity {
+				require.NoError(t, p.SetDiscontinuity())
+			}
+		}
+		p.TargetDuration = target
+		p.Closed = closed
+		return p
+	}
+
+	tests := []struct {
+		name          string
+		spec          bool
+		tolerance     float64
+		durations     []float64
+		closed        bool
+		discontinuity int
+		rule          string
+	}{
+		{name: "checks disabled", durations: []float64{6, 9, 6}},
+		{name: "within target", spec: true, durations: []float64{6, 6.4, 5.8}},
+		{name: "exceeds target", spec: true, durations: []float64{6, 6.6, 6}, rule: models.SpecRuleTargetDuration},
+		{name: "within tolerance", tolerance: 0.1, durations: []float64{6, 5.5, 6}},
+		{name: "short segment", tolerance: 0.1, durations: []float64{6, 3, 6}, rule: models.SpecRuleDurationTolerance},
+		{name: "short last vod segment", tolerance: 0.1, durations: []float64{6, 6, 1}, closed: true},
+		{name: "short segment before discontinuity", tolerance: 0.1, durations: []float64{6, 3, 6}, discontinuity: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewHLSValidator()
+			v.SetTargetDurationCheck(tt.spec)
+			v.SetDurationTolerance(tt.tolerance)
+
+			err := v.ValidateMedia(playlist(6, tt.durations, tt.closed, tt.discontinuity))
+			if tt.rule == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var specErr *models.SpecViolationError
+			require.ErrorAs(t, err, &specErr)
+			assert.Equal(t, tt.rule, specErr.Rule)
+			assert.Equal(t, "seg1.ts", specErr.URI)
+			assert.Equal(t, models.ErrSpecViolation, playlistErrorType(err))
+		})
+	}
+}
+
 func TestGetRandomIndex(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -183,6 +289,12 @@ func TestSelectSegments(t *testing.T) {
 			mode:          models.CheckModeRandom,
 			expectedCount: 3, // default random sample size
 		},
+		{
+			name:          "playlist only",
+			playlist:      createPlaylist(5),
+			mode:          models.CheckModePlaylistOnly,
+			expectedCount: 0,
+		},
 		{
 			name:          "empty playlist",
 			playlist:      createPlaylist(0),
@@ -193,7 +305,7 @@ func TestSelectSegments(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			segments := checker.selectSegments(tt.playlist, tt.mode)
+			segments := checker.selectSegments(tt.playlist, tt.mode, 3)
 			assert.Len(t, segments, tt.expectedCount)
 
 			if tt.checkFirst {
@@ -206,6 +318,34 @@ func TestSelectSegments(t *testing.T) {
 	}
 }
 
+func TestSelectSegments_Sample(t *testing.T) {
+	checker := &StreamChecker{}
+
+	segments := checker.selectSegments(createPlaylist(10), models.CheckModeRandom, 5)
+	assert.Len(t, segments, 5)
+
+	// Выборка больше плейлиста ограничена числом сегментов
+	segments = checker.selectSegments(createPlaylist(2), models.CheckModeRandom, 5)
+	assert.Len(t, segments, 2)
+
+	segments = checker.selectSegments(createPlaylist(10), models.CheckModeNewestN, 3)
+	require.Len(t, segments, 3)
+	assert.Equal(t, "segment_7.ts", segments[0].URI)
+	assert.Equal(t, "segment_9.ts", segments[2].URI)
+
+	segments = checker.selectSegments(createPlaylist(2), models.CheckModeNewestN, 3)
+	assert.Len(t, segments, 2)
+}
+
+func TestSegmentSampleSize(t *testing.T) {
+	checker := &StreamChecker{}
+	assert.Equal(t, defaultSegmentSample, checker.segmentSampleSize(models.StreamConfig{}))
+
+	checker.SetSegmentSample(5)
+	assert.Equal(t, 5, checker.segmentSampleSize(models.StreamConfig{}))
+	assert.Equal(t, 2, checker.segmentSampleSize(models.StreamConfig{SegmentSample: 2}))
+}
+
 func TestSafeConversions(t *testing.T) {
 	tests := []struct {
 		name     string
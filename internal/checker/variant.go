@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// variantLabels возвращает значения меток variant_bandwidth и resolution варианта
+func variantLabels(variant *m3u8.Variant) (string, string) {
+	return strconv.FormatUint(uint64(variant.Bandwidth), 10), variant.Resolution
+}
+
+// filterVariants возвращает варианты мастер-плейлиста, прошедшие фильтр стрима.
+// Если фильтру не соответствует ни один вариант, возвращается ошибка.
+func filterVariants(variants []*m3u8.Variant, filter *models.VariantFilter) ([]*m3u8.Variant, error) {
+	variants = slices.DeleteFunc(slices.Clone(variants), func(v *m3u8.Variant) bool { return v == nil })
+	if filter == nil {
+		return variants, nil
+	}
+
+	uri, err := regexp.Compile(filter.URIRegex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uri_regex: %w", err)
+	}
+	variants = slices.DeleteFunc(variants, func(v *m3u8.Variant) bool {
+		return v.Bandwidth < filter.MinBandwidth ||
+			(filter.MaxBandwidth > 0 && v.Bandwidth > filter.MaxBandwidth) ||
+			!uri.MatchString(v.URI)
+	})
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("no variants match variant_filter")
+	}
+
+	byBandwidth := func(a, b *m3u8.Variant) int { return cmp.Compare(a.Bandwidth, b.Bandwidth) }
+	switch filter.Select {
+	case models.VariantSelectLowest:
+		return []*m3u8.Variant{slices.MinFunc(variants, byBandwidth)}, nil
+	case models.VariantSelectHighest:
+		return []*m3u8.Variant{slices.MaxFunc(variants, byBandwidth)}, nil
+	}
+	return variants, nil
+}
+
+// recordVariantSegments учитывает результаты сегментов по вариантам.
+// owners[i] - вариант, которому принадлежит i-й проверенный сегмент.
+func (c *StreamChecker) recordVariantSegments(
+	cfg models.StreamConfig,
+	owners []*m3u8.Variant,
+	results models.SegmentResults,
+) {
+	for i, check := range results.Details {
+		if i >= len(owners) {
+			return
+		}
+		bandwidth, resolution := variantLabels(owners[i])
+		c.metrics.RecordVariantSegmentCheck(cfg.Name, bandwidth, resolution, check.Success)
+	}
+}
+
+// measuredBitrate вычисляет битрейт по размерам успешно проверенных сегментов
+// и их длительностям из EXTINF. checks[i] - результат проверки segments[i].
+// Сегменты без известного размера не учитываются.
+func measuredBitrate(segments []*m3u8.MediaSegment, checks []models.SegmentCheck) (float64, bool) {
+	var (
+		bytes    int64
+		duration float64
+	)
+	for i, check := range checks {
+		if i >= len(segments) || !check.Success || check.Size <= 0 {
+			continue
+		}
+		bytes += check.Size
+		duration += segments[i].Duration
+	}
+	if duration <= 0 {
+		return 0, false
+	}
+	return float64(bytes) * 8 / duration, true
+}
+
+// recordVariantBitrates сравнивает измеренный битрейт вариантов с заявленным BANDWIDTH.
+// owners[i] - вариант, которому принадлежит segments[i].
+func (c *StreamChecker) recordVariantBitrates(
+	cfg models.StreamConfig,
+	variants []*m3u8.Variant,
+	variantURLs []string,
+	owners []*m3u8.Variant,
+	segments []*m3u8.MediaSegment,
+	results models.SegmentResults,
+	result *models.CheckResult,
+) {
+	for i, variant := range variants {
+		measured, ok := measuredBitrate(variantChecks(variant, owners, segments, results))
+		if !ok {
+			continue
+		}
+
+		declared := float64(variant.Bandwidth)
+		bandwidth, resolution := variantLabels(variant)
+		c.metrics.SetVariantBitrate(cfg.Name, bandwidth, resolution, declared, measured)
+
+		vb := models.VariantBitrate{
+			URL:             variantURLs[i],
+			Bandwidth:       variant.Bandwidth,
+			Resolution:      variant.Resolution,
+			MeasuredBitrate: measured,
+		}
+		if declared > 0 {
+			vb.DeviationRatio = measured / declared
+		}
+		vb.SizeAnomaly = c.recordSizeAnomaly(cfg, variantURLs[i], bandwidth, resolution, measured)
+		result.Variants = append(result.Variants, vb)
+		result.Bitrate = max(result.Bitrate, measured)
+	}
+}
+
+// variantChecks отбирает сегменты варианта и результаты их проверки в порядке плейлиста
+func variantChecks(
+	variant *m3u8.Variant,
+	owners []*m3u8.Variant,
+	segments []*m3u8.MediaSegment,
+	results models.SegmentResults,
+) ([]*m3u8.MediaSegment, []models.SegmentCheck) {
+	var (
+		variantSegments []*m3u8.MediaSegment
+		checks          []models.SegmentCheck
+	)
+	for i, owner := range owners {
+		if owner == variant && i < len(segments) && i < len(results.Details) {
+			variantSegments = append(variantSegments, segments[i])
+			checks = append(checks, results.Details[i])
+		}
+	}
+	return variantSegments, checks
+}
@@ -0,0 +1,179 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantLabels(t *testing.T) {
+	bandwidth, resolution := variantLabels(&m3u8.Variant{
+		VariantParams: m3u8.VariantParams{Bandwidth: 2000000, Resolution: "1280x720"},
+	})
+	assert.Equal(t, "2000000", bandwidth)
+	assert.Equal(t, "1280x720", resolution)
+}
+
+func TestStreamChecker_RecordVariantSegments(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+
+	high := &m3u8.Variant{VariantParams: m3u8.VariantParams{Bandwidth: 2000000, Resolution: "1280x720"}}
+	low := &m3u8.Variant{VariantParams: m3u8.VariantParams{Bandwidth: 800000, Resolution: "640x360"}}
+
+	mockMetrics.On("RecordVariantSegmentCheck", "test_stream", "2000000", "1280x720", true).Return().Twice()
+	mockMetrics.On("RecordVariantSegmentCheck", "test_stream", "800000", "640x360", false).Return().Once()
+
+	c.recordVariantSegments(models.StreamConfig{Name: "test_stream"},
+		[]*m3u8.Variant{high, high, low},
+		models.SegmentResults{Details: []models.SegmentCheck{
+			{Success: true},
+			{Success: true},
+			{Success: false},
+		}})
+
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestMeasuredBitrate(t *testing.T) {
+	segments := []*m3u8.MediaSegment{{Duration: 4}, {Duration: 6}, {Duration: 5}, {Duration: 5}}
+
+	tests := []struct {
+		name   string
+		checks []models.SegmentCheck
+		want   float64
+		ok     bool
+	}{
+		{
+			name:   "all segments",
+			checks: []models.SegmentCheck{{Success: true, Size: 500000}, {Success: true, Size: 750000}},
+			want:   1000000,
+			ok:     true,
+		},
+		{
+			name: "failed and unknown size skipped",
+			checks: []models.SegmentCheck{
+				{Success: true, Size: 500000},
+				{Success: false, Size: 750000},
+				{Success: true, Size: 0},
+				{Success: true, Size: 625000},
+			},
+			want: 1000000,
+			ok:   true,
+		},
+		{
+			name:   "no measurable segments",
+			checks: []models.SegmentCheck{{Success: false}},
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := measuredBitrate(segments, tt.checks)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.want, got, 1e-6)
+		})
+	}
+}
+
+func TestStreamChecker_RecordVariantBitrates(t *testing.T) {
+	mockMetrics := new(MockMetricsCollector)
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), mockMetrics, 1)
+
+	high := &m3u8.Variant{VariantParams: m3u8.VariantParams{Bandwidth: 2000000, Resolution: "1280x720"}}
+	low := &m3u8.Variant{VariantParams: m3u8.VariantParams{Bandwidth: 800000, Resolution: "640x360"}}
+	// Вариант без проверенных сегментов не публикуется
+	idle := &m3u8.Variant{VariantParams: m3u8.VariantParams{Bandwidth: 400000}}
+
+	// high заявлен на 2 Мбит/с, фактически 3 Мбит/с; low соответствует заявленному
+	mockMetrics.On("SetVariantBitrate", "test_stream", "2000000", "1280x720", 2000000.0, 3000000.0).Return().Once()
+	mockMetrics.On("SetVariantBitrate", "test_stream", "800000", "640x360", 800000.0, 800000.0).Return().Once()
+
+	result := &models.CheckResult{}
+	c.recordVariantBitrates(models.StreamConfig{Name: "test_stream"},
+		[]*m3u8.Variant{high, low, idle},
+		[]string{"http://test.com/high.m3u8", "http://test.com/low.m3u8", "http://test.com/idle.m3u8"},
+		[]*m3u8.Variant{high, high, low},
+		[]*m3u8.MediaSegment{{Duration: 2}, {Duration: 2}, {Duration: 5}},
+		models.SegmentResults{Details: []models.SegmentCheck{
+			{Success: true, Size: 750000},
+			{Success: true, Size: 750000},
+			{Success: true, Size: 500000},
+		}},
+		result)
+
+	require.Len(t, result.Variants, 2)
+	assert.Equal(t, "http://test.com/high.m3u8", result.Variants[0].URL)
+	assert.InDelta(t, 1.5, result.Variants[0].DeviationRatio, 1e-9)
+	assert.InDelta(t, 1.0, result.Variants[1].DeviationRatio, 1e-9)
+	assert.Equal(t, 3000000.0, result.Bitrate)
+
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestFilterVariants(t *testing.T) {
+	variant := func(uri string, bandwidth uint32) *m3u8.Variant {
+		return &m3u8.Variant{URI: uri, VariantParams: m3u8.VariantParams{Bandwidth: bandwidth}}
+	}
+	variants := []*m3u8.Variant{
+		variant("360p.m3u8", 800000),
+		nil,
+		variant("720p.m3u8", 2000000),
+		variant("1080p.m3u8", 5000000),
+	}
+
+	tests := []struct {
+		name    string
+		filter  *models.VariantFilter
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filter", want: []string{"360p.m3u8", "720p.m3u8", "1080p.m3u8"}},
+		{
+			name:   "bandwidth range",
+			filter: &models.VariantFilter{MinBandwidth: 1000000, MaxBandwidth: 5000000},
+			want:   []string{"720p.m3u8", "1080p.m3u8"},
+		},
+		{
+			name:   "uri regex",
+			filter: &models.VariantFilter{URIRegex: `^(360|1080)p`},
+			want:   []string{"360p.m3u8", "1080p.m3u8"},
+		},
+		{
+			name:   "lowest",
+			filter: &models.VariantFilter{Select: models.VariantSelectLowest},
+			want:   []string{"360p.m3u8"},
+		},
+		{
+			name:   "highest after range",
+			filter: &models.VariantFilter{MaxBandwidth: 3000000, Select: models.VariantSelectHighest},
+			want:   []string{"720p.m3u8"},
+		},
+		{
+			name:    "nothing matches",
+			filter:  &models.VariantFilter{MinBandwidth: 10000000},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterVariants(variants, tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			uris := make([]string, 0, len(got))
+			for _, v := range got {
+				uris = append(uris, v.URI)
+			}
+			assert.Equal(t, tt.want, uris)
+		})
+	}
+	// Исходный список не меняется
+	assert.Len(t, variants, 4)
+}
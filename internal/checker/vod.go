@@ -0,0 +1,48 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/grafov/m3u8"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// isVOD сообщает, что медиаплейлист больше не будет обновляться:
+// он завершен EXT-X-ENDLIST или имеет EXT-X-PLAYLIST-TYPE:VOD
+func isVOD(media *m3u8.MediaPlaylist) bool {
+	return media.Closed || media.MediaType == m3u8.VOD
+}
+
+// playlistDuration возвращает сумму EXTINF сегментов медиаплейлиста в секундах
+func playlistDuration(media *m3u8.MediaPlaylist) float64 {
+	var total float64
+	for _, seg := range media.Segments {
+		if seg != nil {
+			total += seg.Duration
+		}
+	}
+	return total
+}
+
+// recordPlaylistType учитывает тип и длительность медиаплейлиста. Стрим с expect_live
+// получает ошибку unexpected_vod, если медиаплейлист завершен или объявлен VOD.
+func (c *StreamChecker) recordPlaylistType(
+	stream models.StreamConfig,
+	playlistURL string,
+	media *m3u8.MediaPlaylist,
+	result *models.CheckResult,
+) {
+	status := &result.StreamStatus
+	status.TotalDuration = max(status.TotalDuration, playlistDuration(media))
+	if !isVOD(media) {
+		return
+	}
+
+	status.IsLive = false
+	if stream.ExpectLive && result.Error == nil {
+		result.Error = &models.CheckError{
+			Type:    models.ErrUnexpectedVOD,
+			Message: fmt.Sprintf("playlist %s is VOD (EXT-X-ENDLIST or PLAYLIST-TYPE:VOD), expected live", playlistURL),
+		}
+	}
+}
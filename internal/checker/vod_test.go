@@ -0,0 +1,129 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsVOD(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			name: "live",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:4\n#EXTINF:4.0,\na.ts\n",
+		},
+		{
+			name: "event",
+			body: "#EXTM3U\n#EXT-X-PLAYLIST-TYPE:EVENT\n#EXT-X-TARGETDURATION:4\n#EXTINF:4.0,\na.ts\n",
+		},
+		{
+			name: "endlist",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:4\n#EXTINF:4.0,\na.ts\n#EXT-X-ENDLIST\n",
+			want: true,
+		},
+		{
+			name: "playlist type vod",
+			body: "#EXTM3U\n#EXT-X-PLAYLIST-TYPE:VOD\n#EXT-X-TARGETDURATION:4\n#EXTINF:4.0,\na.ts\n",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isVOD(decodeMediaPlaylist(t, tt.body)))
+		})
+	}
+}
+
+func TestStreamChecker_RecordPlaylistType(t *testing.T) {
+	c := NewStreamChecker(new(MockHTTPClient), new(MockValidator), new(MockMetricsCollector), 1)
+	live := windowPlaylist(t, 10, "a.ts", "b.ts", "c.ts")
+	vod := decodeMediaPlaylist(t, "#EXTM3U\n#EXT-X-TARGETDURATION:10\n"+
+		"#EXTINF:10.0,\na.ts\n#EXTINF:10.0,\nb.ts\n#EXTINF:3.5,\nc.ts\n#EXT-X-ENDLIST\n")
+
+	result := c.initResult(models.StreamConfig{Name: "test_stream"})
+	c.recordPlaylistType(models.StreamConfig{}, "http://a/live.m3u8", live, result)
+	assert.True(t, result.StreamStatus.IsLive)
+	assert.Equal(t, 12.0, result.StreamStatus.TotalDuration)
+
+	c.recordPlaylistType(models.StreamConfig{}, "http://a/vod.m3u8", vod, result)
+	assert.False(t, result.StreamStatus.IsLive)
+	assert.Equal(t, 23.5, result.StreamStatus.TotalDuration)
+	assert.Nil(t, result.Error, "VOD is allowed without expect_live")
+
+	result = c.initResult(models.StreamConfig{Name: "test_stream"})
+	c.recordPlaylistType(models.StreamConfig{ExpectLive: true}, "http://a/vod.m3u8", vod, result)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrUnexpectedVOD, result.Error.Type)
+}
+
+func TestStreamChecker_Check_ExpectLive(t *testing.T) {
+	mockClient := new(MockHTTPClient)
+	mockValidator := new(MockValidator)
+	mockMetrics := new(MockMetricsCollector)
+	checker := NewStreamChecker(mockClient, mockValidator, mockMetrics, 1)
+
+	// Live-канал внезапно опубликовал EXT-X-ENDLIST
+	mediaURL := "http://test.com/live/index.m3u8"
+	mockClient.On("GetPlaylist", mock.Anything, mediaURL).Return(
+		&models.PlaylistResponse{
+			Body: []byte(`#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:10
+#EXT-X-MEDIA-SEQUENCE:500
+#EXTINF:10.0,
+segment500.ts
+#EXT-X-ENDLIST`),
+			StatusCode: 200,
+		}, nil)
+	mockClient.On("GetSegment", mock.Anything, "http://test.com/live/segment500.ts", false).Return(
+		&models.SegmentResponse{Size: 1024, Duration: time.Second}, nil)
+	mockValidator.On("ValidateMedia", mock.AnythingOfType("*m3u8.MediaPlaylist")).Return(nil)
+
+	mockMetrics.On("SetStreamUp", "test_stream", false).Return()
+	mockMetrics.On("SetStreamDownReason", "test_stream", mock.Anything).Return()
+	mockMetrics.On("RecordResponseTime", "test_stream", mock.Anything, mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordCheckDuration", "test_stream", mock.AnythingOfType("float64"), mock.Anything).Return()
+	mockMetrics.On("RecordSegmentDuration", "test_stream", mock.AnythingOfType("float64")).Return()
+	mockMetrics.On("RecordSegmentSize", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordHTTPResponse", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordStageDuration", "test_stream", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("SetLastCheckTime", "test_stream", mock.Anything).Return()
+	mockMetrics.On("SetSegmentsCount", "test_stream", 1).Return()
+	mockMetrics.On("SetActiveChecks", mock.Anything).Return()
+	mockMetrics.On("SetWorkerPool", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordQueueWait", mock.Anything, mock.Anything).Return()
+	mockMetrics.On("RecordSegmentCheck", "test_stream", false).Return()
+	mockMetrics.On("RecordCheckResult", "test_stream", false, mock.Anything).Return()
+	mockMetrics.On("SetStreamBitrate", "test_stream", mock.Anything).Return()
+	mockMetrics.On("AddDownloadedBytes", "test_stream", mock.AnythingOfType("int64")).Return()
+	mockMetrics.On("RecordError", "test_stream", string(models.ErrUnexpectedVOD)).Return()
+	mockMetrics.On("SetPlaylistStale", "test_stream", false).Return()
+	mockMetrics.On("SetPlaylistType", "test_stream", false, 10.0).Return()
+	mockMetrics.On("SetPlaylistMarkers", "test_stream", mock.Anything, 0, 0).Return()
+	mockMetrics.On("SetMediaSequence", "test_stream", mediaVariantLabel, uint64(500)).Return()
+	mockMetrics.On("SetContentLooping", "test_stream", false).Return()
+
+	result, err := checker.Check(context.Background(), models.StreamConfig{
+		Name:       "test_stream",
+		URL:        mediaURL,
+		CheckMode:  models.CheckModeAll,
+		ExpectLive: true,
+	})
+
+	assert.ErrorContains(t, err, "unexpected VOD")
+	assert.False(t, result.Success)
+	assert.False(t, result.StreamStatus.IsLive)
+	assert.Equal(t, 10.0, result.StreamStatus.TotalDuration)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrUnexpectedVOD, result.Error.Type)
+	mockMetrics.AssertExpectations(t)
+}
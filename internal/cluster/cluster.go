@@ -0,0 +1,120 @@
+// Package cluster распределяет стримы между экземплярами экспортера.
+// Все экземпляры используют одинаковый список стримов и вычисляют
+// принадлежность детерминированно, без обмена состоянием.
+package cluster
+
+import (
+	"cmp"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// virtualNodes число точек узла на кольце для равномерного распределения
+const virtualNodes = 128
+
+var (
+	_ models.ShardFilter = (*Static)(nil)
+	_ models.ShardFilter = (*Ring)(nil)
+)
+
+// New создает фильтр стримов по конфигурации кластера.
+// Возвращает nil, если режим кластера выключен.
+func New(cfg models.ClusterConfig) (models.ShardFilter, error) {
+	switch {
+	case cfg.ShardCount > 0 && len(cfg.Peers) > 0:
+		return nil, fmt.Errorf("shard_count and peers are mutually exclusive")
+	case cfg.ShardCount > 0:
+		return NewStatic(cfg.ShardCount, cfg.ShardIndex)
+	case len(cfg.Peers) > 0:
+		return NewRing(cfg.Peers, cfg.Self)
+	}
+	return nil, nil
+}
+
+// Static статическое шардирование по остатку от деления хэша имени стрима
+type Static struct {
+	count, index int
+}
+
+func NewStatic(count, index int) (*Static, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("shard_count must be greater than 0")
+	}
+	if index < 0 || index >= count {
+		return nil, fmt.Errorf("shard_index must be in range [0, %d]", count-1)
+	}
+	return &Static{count: count, index: index}, nil
+}
+
+func (s *Static) Owns(stream string) bool {
+	return int(hash(stream)%uint32(s.count)) == s.index
+}
+
+// Ring консистентное хэш-кольцо: при добавлении или удалении узла
+// меняют владельца только стримы соседних с ним участков
+type Ring struct {
+	self   string
+	points []point
+}
+
+type point struct {
+	hash uint32
+	peer string
+}
+
+func NewRing(peers []string, self string) (*Ring, error) {
+	seen := make(map[string]bool, len(peers))
+	for _, peer := range peers {
+		if peer == "" {
+			return nil, fmt.Errorf("peer name cannot be empty")
+		}
+		if seen[peer] {
+			return nil, fmt.Errorf("duplicate peer %q", peer)
+		}
+		seen[peer] = true
+	}
+	if !seen[self] {
+		return nil, fmt.Errorf("self %q is not in peers", self)
+	}
+
+	r := &Ring{self: self, points: make([]point, 0, len(peers)*virtualNodes)}
+	for _, peer := range peers {
+		for i := range virtualNodes {
+			r.points = append(r.points, point{hash: hash(peer + "#" + strconv.Itoa(i)), peer: peer})
+		}
+	}
+	// При совпадении хэшей порядок задается именем узла, чтобы кольцо не зависело от порядка peers
+	slices.SortFunc(r.points, func(a, b point) int {
+		if a.hash != b.hash {
+			return cmp.Compare(a.hash, b.hash)
+		}
+		return cmp.Compare(a.peer, b.peer)
+	})
+	return r, nil
+}
+
+// Owner возвращает узел, которому принадлежит стрим
+func (r *Ring) Owner(stream string) string {
+	h := hash(stream)
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint32) int {
+		return cmp.Compare(p.hash, h)
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].peer
+}
+
+func (r *Ring) Owns(stream string) bool {
+	return r.Owner(stream) == r.self
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
@@ -0,0 +1,135 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("stream_%d", i)
+	}
+	return names
+}
+
+// owners возвращает число экземпляров, которым принадлежит каждый стрим
+func owners(filters []models.ShardFilter, names []string) map[string]int {
+	counts := make(map[string]int, len(names))
+	for _, name := range names {
+		for _, f := range filters {
+			if f.Owns(name) {
+				counts[name]++
+			}
+		}
+	}
+	return counts
+}
+
+func TestStatic(t *testing.T) {
+	names := streamNames(1000)
+
+	filters := make([]models.ShardFilter, 3)
+	for i := range filters {
+		s, err := NewStatic(3, i)
+		require.NoError(t, err)
+		filters[i] = s
+	}
+
+	for name, count := range owners(filters, names) {
+		assert.Equal(t, 1, count, name)
+	}
+	for i, f := range filters {
+		owned := 0
+		for _, name := range names {
+			if f.Owns(name) {
+				owned++
+			}
+		}
+		assert.InDelta(t, 333, owned, 60, "shard %d", i)
+	}
+
+	_, err := NewStatic(0, 0)
+	assert.Error(t, err)
+	_, err = NewStatic(3, 3)
+	assert.Error(t, err)
+	_, err = NewStatic(3, -1)
+	assert.Error(t, err)
+}
+
+func TestRing(t *testing.T) {
+	names := streamNames(1000)
+	peers := []string{"exporter-0", "exporter-1", "exporter-2"}
+
+	filters := make([]models.ShardFilter, len(peers))
+	for i, peer := range peers {
+		r, err := NewRing(peers, peer)
+		require.NoError(t, err)
+		filters[i] = r
+	}
+	for name, count := range owners(filters, names) {
+		assert.Equal(t, 1, count, name)
+	}
+
+	// Порядок узлов в конфигурации не влияет на распределение
+	a, err := NewRing(peers, "exporter-0")
+	require.NoError(t, err)
+	b, err := NewRing([]string{"exporter-2", "exporter-0", "exporter-1"}, "exporter-0")
+	require.NoError(t, err)
+	for _, name := range names {
+		assert.Equal(t, a.Owner(name), b.Owner(name))
+	}
+
+	// Новый узел забирает стримы только себе, остальные не переезжают
+	grown, err := NewRing(append(peers, "exporter-3"), "exporter-3")
+	require.NoError(t, err)
+	moved := 0
+	for _, name := range names {
+		before, after := a.Owner(name), grown.Owner(name)
+		if before != after {
+			assert.Equal(t, "exporter-3", after, name)
+			moved++
+		}
+	}
+	assert.InDelta(t, 250, moved, 100)
+}
+
+func TestRing_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		peers []string
+		self  string
+	}{
+		{name: "self not in peers", peers: []string{"a", "b"}, self: "c"},
+		{name: "empty self", peers: []string{"a"}},
+		{name: "duplicate peer", peers: []string{"a", "a"}, self: "a"},
+		{name: "empty peer", peers: []string{"a", ""}, self: "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRing(tt.peers, tt.self)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	f, err := New(models.ClusterConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	f, err = New(models.ClusterConfig{ShardCount: 2, ShardIndex: 1})
+	require.NoError(t, err)
+	assert.IsType(t, &Static{}, f)
+
+	f, err = New(models.ClusterConfig{Peers: []string{"a", "b"}, Self: "b"})
+	require.NoError(t, err)
+	assert.IsType(t, &Ring{}, f)
+
+	_, err = New(models.ClusterConfig{ShardCount: 2, Peers: []string{"a"}, Self: "a"})
+	assert.ErrorContains(t, err, "mutually exclusive")
+}
@@ -1,8 +1,20 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"slices"
+
+	"github.com/iudanet/hls_exporter/internal/cluster"
+	"github.com/iudanet/hls_exporter/internal/conformance"
+	"github.com/iudanet/hls_exporter/internal/cron"
+	"github.com/iudanet/hls_exporter/internal/labels"
+	"github.com/iudanet/hls_exporter/internal/silence"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/iudanet/hls_exporter/pkg/models"
 
@@ -50,6 +62,16 @@ func (cm *Manager) LoadConfig(path string) (*models.Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	if err := applyProfiles(&config); err != nil {
+		return nil, fmt.Errorf("config validation error: %w", err)
+	}
+	if err := applyDevices(&config); err != nil {
+		return nil, fmt.Errorf("config validation error: %w", err)
+	}
+	if err := applyWebConfig(&config.Server); err != nil {
+		return nil, fmt.Errorf("config validation error: %w", err)
+	}
+
 	validator := NewValidator()
 	if err := validator.Validate(&config); err != nil {
 		return nil, fmt.Errorf("config validation error: %w", err)
@@ -65,27 +87,261 @@ func NewValidator() models.ConfigValidator {
 	return &Validator{}
 }
 
+// Validate проверяет конфигурацию целиком и возвращает все найденные проблемы,
+// объединенные errors.Join
 func (cv *Validator) Validate(cfg *models.Config) error {
+	var errs []error
+
 	if cfg.Server.Port <= 0 || cfg.Server.Port > 65535 {
-		return fmt.Errorf("invalid server port: %d", cfg.Server.Port)
+		errs = append(errs, fmt.Errorf("invalid server port: %d", cfg.Server.Port))
+	}
+
+	if cfg.Server.MetricsCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("metrics_cache_ttl cannot be negative"))
+	}
+	if addr := cfg.Server.GRPCAddress; addr != "" {
+		if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+			errs = append(errs, fmt.Errorf("invalid grpc_address: %s", addr))
+		}
+	}
+
+	// Селекторы токенов и silences могут ссылаться на пользовательские метки стримов
+	known := labels.Configured(cfg.Streams)
+	if err := validateAPITokens(cfg.Server.APITokens, known); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateServerAuth(cfg.Server); err != nil {
+		errs = append(errs, err)
 	}
 
 	if cfg.Checks.Workers <= 0 {
-		return fmt.Errorf("workers must be greater than 0")
+		errs = append(errs, fmt.Errorf("workers must be greater than 0"))
+	}
+
+	if cfg.Checks.MaxConcurrencyPerCheck < 0 {
+		errs = append(errs, fmt.Errorf("max_concurrency_per_check cannot be negative"))
+	}
+
+	if cfg.Checks.SegmentDurationMaxCV < 0 {
+		errs = append(errs, fmt.Errorf("segment_duration_max_cv cannot be negative"))
+	}
+	if cfg.Checks.SegmentDurationTolerance < 0 {
+		errs = append(errs, fmt.Errorf("segment_duration_tolerance cannot be negative"))
+	}
+
+	if cfg.Checks.ScrapeConcurrency < 0 {
+		errs = append(errs, fmt.Errorf("scrape_concurrency cannot be negative"))
+	}
+
+	if cfg.Checks.Jitter < 0 || cfg.Checks.Jitter > 0.5 {
+		errs = append(errs, fmt.Errorf("jitter must be in range [0, 0.5]"))
+	}
+
+	switch cfg.Server.ReadinessMode {
+	case "", models.ReadinessChecked, models.ReadinessAnyUp:
+	default:
+		errs = append(errs, fmt.Errorf("invalid readiness_mode: %s", cfg.Server.ReadinessMode))
+	}
+
+	switch cfg.Checks.OverrunPolicy {
+	case "", models.OverrunQueue, models.OverrunSkip:
+	default:
+		errs = append(errs, fmt.Errorf("invalid overrun_policy: %s", cfg.Checks.OverrunPolicy))
+	}
+
+	if cfg.Checks.TransactionLog.MaxBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("transaction_log: max_body_bytes cannot be negative"))
+	}
+
+	if err := validateSoak(cfg.Soak); err != nil {
+		errs = append(errs, err)
+	}
+
+	if cfg.Checks.HostBudget.RequestsPerSecond < 0 || cfg.Checks.HostBudget.Burst < 0 {
+		errs = append(errs, fmt.Errorf("host_budget: values cannot be negative"))
+	}
+	if cfg.Checks.MaxRequestsPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("max_requests_per_second cannot be negative"))
+	}
+	if cfg.Checks.KeyCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("key_cache_ttl cannot be negative"))
+	}
+	if cfg.Checks.MaxFastRetryStreams < 0 {
+		errs = append(errs, fmt.Errorf("max_fast_retry_streams cannot be negative"))
+	}
+
+	if cfg.History.Enabled && cfg.History.Path == "" {
+		errs = append(errs, fmt.Errorf("history: path is required"))
+	}
+	if cfg.History.Retention < 0 {
+		errs = append(errs, fmt.Errorf("history: retention cannot be negative"))
+	}
+
+	if cfg.EventLog.Enabled && cfg.EventLog.Path == "" {
+		errs = append(errs, fmt.Errorf("event_log: path is required"))
+	}
+	if cfg.EventLog.MaxSize < 0 || cfg.EventLog.RotateInterval < 0 || cfg.EventLog.MaxBackups < 0 {
+		errs = append(errs, fmt.Errorf("event_log: rotation settings cannot be negative"))
+	}
+
+	if err := validateCluster(cfg.Cluster); err != nil {
+		errs = append(errs, fmt.Errorf("cluster: %w", err))
+	}
+
+	if cfg.HTTPClient.MaxSegmentBytes < 0 {
+		errs = append(errs, fmt.Errorf("max_segment_bytes cannot be negative"))
+	}
+
+	if err := validateTLS(cfg.HTTPClient.TLSConfig); err != nil {
+		errs = append(errs, fmt.Errorf("http_client: %w", err))
+	}
+
+	if cfg.Checks.SegmentSample < 0 {
+		errs = append(errs, fmt.Errorf("segment_sample cannot be negative"))
 	}
 
 	if cfg.Checks.RetryAttempts < 0 {
-		return fmt.Errorf("retry_attempts cannot be negative")
+		errs = append(errs, fmt.Errorf("retry_attempts cannot be negative"))
+	}
+	if cfg.Checks.RetryDelay < 0 {
+		errs = append(errs, fmt.Errorf("retry_delay cannot be negative"))
+	}
+	for i, bound := range cfg.Checks.DurationBuckets {
+		if bound <= 0 || (i > 0 && bound <= cfg.Checks.DurationBuckets[i-1]) {
+			errs = append(errs, fmt.Errorf("duration_buckets must be positive and strictly increasing"))
+			break
+		}
+	}
+	for _, window := range cfg.Checks.SLAWindows {
+		if window < time.Minute {
+			errs = append(errs, fmt.Errorf("sla_windows must be at least 1m: %s", window))
+		}
 	}
 
 	if len(cfg.Streams) == 0 {
-		return fmt.Errorf("no streams configured")
+		errs = append(errs, fmt.Errorf("no streams configured"))
+	}
+
+	plugins := make(map[string]bool, len(cfg.Plugins))
+	for i, p := range cfg.Plugins {
+		switch {
+		case p.Name == "":
+			errs = append(errs, fmt.Errorf("plugins[%d]: name is required", i))
+		case plugins[p.Name]:
+			errs = append(errs, fmt.Errorf("plugins[%d]: duplicate name: %s", i, p.Name))
+		}
+		if p.Path == "" {
+			errs = append(errs, fmt.Errorf("plugins[%d]: path is required", i))
+		}
+		plugins[p.Name] = true
 	}
 
 	for i, stream := range cfg.Streams {
 		if err := cv.ValidateStream(&stream, i); err != nil {
-			return err
+			errs = append(errs, err)
+		}
+		for _, name := range stream.Validators {
+			if !plugins[name] {
+				errs = append(errs, fmt.Errorf("stream[%d]: unknown validator: %s", i, name))
+			}
+		}
+	}
+
+	if err := ValidateLabelNames(cfg.Streams); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, rule := range cfg.FaultInjection {
+		if err := validateFaultRule(rule, i); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, rule := range cfg.Silences {
+		if _, err := silence.Compile(rule, known); err != nil {
+			errs = append(errs, fmt.Errorf("silences[%d]: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateSoak проверяет настройки режима контроля утечек
+func validateSoak(soak models.SoakConfig) error {
+	if !soak.Enabled {
+		return nil
+	}
+
+	if soak.Interval <= 0 {
+		return fmt.Errorf("soak: interval must be greater than 0")
+	}
+
+	if soak.MaxGoroutines < 0 || soak.MaxHeapBytes < 0 || soak.MaxOpenFDs < 0 {
+		return fmt.Errorf("soak: thresholds cannot be negative")
+	}
+
+	return nil
+}
+
+// validateCluster проверяет настройки распределения стримов между экземплярами
+func validateCluster(c models.ClusterConfig) error {
+	if c.ShardCount < 0 {
+		return fmt.Errorf("shard_count cannot be negative")
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	_, err := cluster.New(c)
+	return err
+}
+
+// validateAPITokens проверяет токены доступа к API: имена и значения должны быть уникальны
+func validateAPITokens(tokens []models.APIToken, known func(label string) bool) error {
+	var errs []error
+	names := make(map[string]bool, len(tokens))
+	values := make(map[string]bool, len(tokens))
+	for i, tok := range tokens {
+		switch {
+		case tok.Name == "":
+			errs = append(errs, fmt.Errorf("api_tokens[%d]: name is required", i))
+		case names[tok.Name]:
+			errs = append(errs, fmt.Errorf("api_tokens[%d]: duplicate name: %s", i, tok.Name))
 		}
+		names[tok.Name] = true
+
+		switch {
+		case tok.Token == "":
+			errs = append(errs, fmt.Errorf("api_tokens[%d]: token is required", i))
+		case values[tok.Token]:
+			errs = append(errs, fmt.Errorf("api_tokens[%d]: duplicate token", i))
+		}
+		values[tok.Token] = true
+
+		if _, err := labels.ParseSelector(tok.Selector, known); err != nil {
+			errs = append(errs, fmt.Errorf("api_tokens[%d]: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateFaultRule проверяет правило внедрения сбоев
+func validateFaultRule(rule models.FaultRule, index int) error {
+	switch rule.Stage {
+	case models.FaultStagePlaylist, models.FaultStageSegment, models.FaultStageKey:
+	default:
+		return fmt.Errorf("fault_injection[%d]: invalid stage: %s", index, rule.Stage)
+	}
+
+	if rule.Delay < 0 {
+		return fmt.Errorf("fault_injection[%d]: delay cannot be negative", index)
+	}
+
+	if rule.StatusCode != 0 && (rule.StatusCode < 100 || rule.StatusCode > 599) {
+		return fmt.Errorf("fault_injection[%d]: invalid status_code: %d", index, rule.StatusCode)
+	}
+
+	if rule.Probability < 0 || rule.Probability > 1 {
+		return fmt.Errorf("fault_injection[%d]: probability must be in range [0, 1]", index)
 	}
 
 	return nil
@@ -96,79 +352,390 @@ func (cm *Manager) setDefaults() {
 	cm.viper.SetDefault("server.port", 9090)
 	cm.viper.SetDefault("server.metrics_path", "/metrics")
 	cm.viper.SetDefault("server.health_path", "/health")
+	cm.viper.SetDefault("server.readiness_mode", models.ReadinessChecked)
+	cm.viper.SetDefault("server.admin_api", false)
+	cm.viper.SetDefault("server.metrics_compression", true)
+	cm.viper.SetDefault("server.metrics_cache_ttl", "0s")
+
+	cm.viper.SetDefault("soak.enabled", false)
+	cm.viper.SetDefault("soak.interval", "15s")
+
+	cm.viper.SetDefault("history.enabled", false)
+	cm.viper.SetDefault("history.path", "hls_history.db")
+	cm.viper.SetDefault("history.retention", "168h")
+
+	cm.viper.SetDefault("event_log.enabled", false)
+	cm.viper.SetDefault("event_log.path", "")
+	cm.viper.SetDefault("event_log.max_size", 100*1024*1024)
+	cm.viper.SetDefault("event_log.rotate_interval", "24h")
+	cm.viper.SetDefault("event_log.max_backups", 7)
+
+	// Ключи кластера известны viper, чтобы задавать их через HLS_CLUSTER_* для каждого экземпляра
+	cm.viper.SetDefault("cluster.shard_count", 0)
+	cm.viper.SetDefault("cluster.shard_index", 0)
+	cm.viper.SetDefault("cluster.self", "")
 
 	cm.viper.SetDefault("checks.workers", 5)
 	cm.viper.SetDefault("checks.retry_attempts", 3)
 	cm.viper.SetDefault("checks.retry_delay", "1s")
 	cm.viper.SetDefault("checks.segment_sample", 3)
+	cm.viper.SetDefault("checks.max_concurrency_per_check", 0)
+	cm.viper.SetDefault("checks.segment_duration_max_cv", 0)
+	cm.viper.SetDefault("checks.target_duration_check", false)
+	cm.viper.SetDefault("checks.segment_duration_tolerance", 0)
+	cm.viper.SetDefault("checks.collect_on_scrape", false)
+	cm.viper.SetDefault("checks.scrape_concurrency", 0)
+	cm.viper.SetDefault("checks.stagger_start", false)
+	cm.viper.SetDefault("checks.jitter", 0)
+	cm.viper.SetDefault("checks.overrun_policy", models.OverrunQueue)
+	cm.viper.SetDefault("checks.max_fast_retry_streams", 0)
+	cm.viper.SetDefault("checks.key_cache_ttl", "5m")
 
 	cm.viper.SetDefault("http_client.timeout", "5s")
 	cm.viper.SetDefault("http_client.keep_alive", true)
 	cm.viper.SetDefault("http_client.max_idle_conns", 10)
 	cm.viper.SetDefault("http_client.tls_verify", true)
 	cm.viper.SetDefault("http_client.user_agent", "hls_exporter/1.0")
+	cm.viper.SetDefault("http_client.request_id_header", "X-Request-ID")
+	cm.viper.SetDefault("http_client.max_segment_bytes", 0)
+}
+
+// minRangeBytes наименьшая выборка сегмента: несколько TS-пакетов с PAT и PMT
+const minRangeBytes = 1024
+
+// resolutionPattern формат разрешения варианта в expect_resolutions
+var resolutionPattern = regexp.MustCompile(`^[0-9]+x[0-9]+$`)
+
+// Ограничения пользовательских меток стрима: каждая метка - отдельное измерение
+// всех серий стрима, поэтому их число и длина значений ограничены
+const (
+	maxStreamLabels     = 10
+	maxStreamLabelValue = 128
+)
+
+// labelNamePattern допустимое имя метки Prometheus
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedLabels метки метрик экспортера и Prometheus; встроенные метки стрима
+// (labels.Known) также недоступны
+var reservedLabels = map[string]bool{
+	"name": true, "le": true, "quantile": true, "job": true, "instance": true, "silenced": true,
+}
+
+// ValidateLabelNames проверяет число различных пользовательских меток набора
+// стримов: метки разных стримов в сумме расширяют все метрики экспортера
+func ValidateLabelNames(streams []models.StreamConfig) error {
+	names := make(map[string]bool)
+	for _, stream := range streams {
+		for name := range stream.Labels {
+			names[name] = true
+		}
+	}
+	if len(names) > maxStreamLabels {
+		return fmt.Errorf("streams use too many distinct labels: %d (max %d)", len(names), maxStreamLabels)
+	}
+	return nil
 }
 
-// validateStream проверяет конфигурацию отдельного стрима
+// ValidateStream проверяет конфигурацию отдельного стрима и возвращает все найденные проблемы
 func (cv *Validator) ValidateStream(stream *models.StreamConfig, index int) error {
+	var errs []error
+	addf := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("stream[%d]: "+format, append([]any{index}, args...)...))
+	}
 
 	if stream.Name == "" {
-		return fmt.Errorf("stream[%d]: name cannot be empty", index)
+		addf("name cannot be empty")
 	}
 
 	if stream.URL == "" {
-		return fmt.Errorf("stream[%d]: url cannot be empty", index)
+		addf("url cannot be empty")
 	}
 
 	// Проверка CheckMode
 	validModes := map[string]bool{
-		models.CheckModeAll:       true,
-		models.CheckModeFirstLast: true,
-		models.CheckModeRandom:    true,
+		models.CheckModeAll:          true,
+		models.CheckModeFirstLast:    true,
+		models.CheckModeRandom:       true,
+		models.CheckModeNewestN:      true,
+		models.CheckModePlaylistOnly: true,
 	}
 	if !validModes[stream.CheckMode] {
-		return fmt.Errorf("stream[%d]: invalid check_mode: %s", index, stream.CheckMode)
+		addf("invalid check_mode: %s", stream.CheckMode)
+	}
+
+	if stream.DeepCheck != nil {
+		if err := validateDeepCheck(*stream.DeepCheck, validModes); err != nil {
+			addf("deep_check: %w", err)
+		}
 	}
 
 	// Проверка интервалов
 	if stream.Interval <= 0 {
-		return fmt.Errorf("stream[%d]: interval must be greater than 0", index)
+		addf("interval must be greater than 0")
 	}
 
 	if stream.Timeout <= 0 {
-		return fmt.Errorf("stream[%d]: timeout must be greater than 0", index)
+		addf("timeout must be greater than 0")
+	} else if stream.Interval > 0 && stream.Timeout >= stream.Interval {
+		addf("timeout must be less than interval")
+	}
+
+	if stream.Backoff != nil {
+		if err := validateBackoff(*stream.Backoff, stream.Timeout); err != nil {
+			addf("backoff: %w", err)
+		}
+	}
+
+	if stream.SizeAnomaly != nil {
+		if err := validateSizeAnomaly(*stream.SizeAnomaly); err != nil {
+			addf("size_anomaly: %w", err)
+		}
+	}
+
+	if stream.VariantFilter != nil {
+		if err := validateVariantFilter(*stream.VariantFilter); err != nil {
+			addf("variant_filter: %w", err)
+		}
+	}
+
+	if stream.Hook != nil {
+		if len(stream.Hook.Command) == 0 || stream.Hook.Command[0] == "" {
+			addf("hook: command is required")
+		}
+		if stream.Hook.Timeout < 0 {
+			addf("hook: timeout cannot be negative")
+		}
+	}
+
+	if stream.SLO != nil {
+		if stream.SLO.MaxPlaylistLatency < 0 {
+			addf("slo: max_playlist_latency cannot be negative")
+		}
+		if stream.SLO.MaxSegmentDownloadRatio < 0 {
+			addf("slo: max_segment_download_ratio cannot be negative")
+		}
+	}
+
+	for _, agent := range stream.UserAgents {
+		if strings.TrimSpace(agent) == "" {
+			addf("user_agents: empty value")
+		}
+	}
+	if len(stream.UserAgents) > 0 {
+		for name := range stream.Headers {
+			if strings.EqualFold(name, "User-Agent") {
+				addf("user_agents conflicts with User-Agent in headers")
+			}
+		}
+	}
+
+	if stream.ExpectVariants < 0 {
+		addf("expect_variants cannot be negative")
+	}
+	for _, res := range stream.ExpectResolutions {
+		if !resolutionPattern.MatchString(res) {
+			addf("invalid expect_resolutions value: %q (expected WxH)", res)
+		}
+	}
+	for _, codec := range stream.ExpectCodecs {
+		if codec == "" || strings.ContainsAny(codec, "., ") {
+			addf("invalid expect_codecs value: %q (expected codec family, e.g. avc1)", codec)
+		}
+	}
+
+	if stream.DailyByteBudget < 0 {
+		addf("daily_byte_budget cannot be negative")
+	}
+
+	if stream.SegmentSample < 0 {
+		addf("segment_sample cannot be negative")
+	}
+
+	if stream.RangeBytes < 0 {
+		addf("range_bytes cannot be negative")
+	} else if stream.RangeBytes > 0 && stream.RangeBytes < minRangeBytes {
+		addf("range_bytes must be at least %d", minRangeBytes)
+	}
+
+	switch stream.ParseMode {
+	case "", models.ParseModeLenient, models.ParseModeWarn, models.ParseModeStrict:
+	default:
+		addf("invalid parse_mode: %s", stream.ParseMode)
+	}
+
+	if rules := stream.ConformanceRules; rules != nil {
+		if _, err := conformance.Select(rules.Enable, rules.Disable); err != nil {
+			addf("conformance_rules: %w", err)
+		}
+	}
+
+	if err := validateRequestAuth(stream.RequestAuth(), index); err != nil {
+		errs = append(errs, err)
+	}
+
+	if stream.TLS != nil {
+		if err := validateTLS(*stream.TLS); err != nil {
+			addf("tls: %w", err)
+		}
 	}
 
-	if stream.Timeout >= stream.Interval {
-		return fmt.Errorf("stream[%d]: timeout must be less than interval", index)
+	if _, err := models.ParseResolve(stream.Resolve); err != nil {
+		addf("resolve: %w", err)
+	}
+
+	if len(stream.Labels) > maxStreamLabels {
+		addf("labels: too many labels: %d (max %d)", len(stream.Labels), maxStreamLabels)
+	}
+	for _, name := range slices.Sorted(maps.Keys(stream.Labels)) {
+		switch {
+		case !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__"):
+			addf("labels: invalid label name: %q", name)
+		case reservedLabels[name] || labels.Known(name):
+			addf("labels: reserved label name: %s", name)
+		case stream.Labels[name] == "":
+			addf("labels: empty value for %s", name)
+		case len(stream.Labels[name]) > maxStreamLabelValue:
+			addf("labels: value of %s is longer than %d", name, maxStreamLabelValue)
+		}
 	}
 
 	// Проверка MediaValidation если включена валидация контента
 	if stream.ValidateContent && stream.MediaValidation != nil {
 		if err := cv.ValidateMediaValidation(stream.MediaValidation, index); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateRequestAuth проверяет заголовки и авторизацию запросов стрима
+func validateRequestAuth(auth models.RequestAuth, index int) error {
+	var errs []error
+
+	for name := range auth.Headers {
+		if name == "" || strings.ContainsAny(name, ": \t\r\n") {
+			errs = append(errs, fmt.Errorf("stream[%d]: invalid header name: %q", index, name))
+		}
+	}
+
+	if auth.BasicAuth != nil && auth.BasicAuth.Username == "" {
+		errs = append(errs, fmt.Errorf("stream[%d]: basic_auth: username cannot be empty", index))
+	}
+
+	if auth.BasicAuth != nil && auth.BearerToken != "" {
+		errs = append(errs, fmt.Errorf("stream[%d]: basic_auth and bearer_token are mutually exclusive", index))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateBackoff проверяет политику интервала проверок недоступного стрима
+func validateBackoff(b models.BackoffConfig, timeout time.Duration) error {
+	switch b.Policy {
+	case models.BackoffExponential:
+		if b.Multiplier != 0 && b.Multiplier <= 1 {
+			return fmt.Errorf("multiplier must be greater than 1")
+		}
+		if b.MaxInterval <= 0 {
+			return fmt.Errorf("max_interval must be greater than 0")
+		}
+	case models.BackoffFastRetry:
+		if b.RetryInterval <= timeout {
+			return fmt.Errorf("retry_interval must be greater than timeout")
+		}
+	default:
+		return fmt.Errorf("invalid policy: %s", b.Policy)
+	}
+	return nil
+}
+
+// validateSizeAnomaly проверяет параметры обнаружения падения размеров сегментов
+func validateSizeAnomaly(c models.SizeAnomalyConfig) error {
+	if c.Window < 0 || c.Window == 1 {
+		return fmt.Errorf("window must be at least 2")
+	}
+	if c.Threshold < 0 || c.Threshold >= 1 {
+		return fmt.Errorf("threshold must be in range (0, 1)")
+	}
+	if c.Checks < 0 {
+		return fmt.Errorf("checks cannot be negative")
+	}
+	return nil
+}
+
+// validateVariantFilter проверяет фильтр вариантов мастер-плейлиста
+func validateVariantFilter(f models.VariantFilter) error {
+	if f.MaxBandwidth > 0 && f.MaxBandwidth < f.MinBandwidth {
+		return fmt.Errorf("max_bandwidth must be greater than or equal to min_bandwidth")
+	}
+	if _, err := regexp.Compile(f.URIRegex); err != nil {
+		return fmt.Errorf("invalid uri_regex: %w", err)
+	}
+	switch f.Select {
+	case "", models.VariantSelectAll, models.VariantSelectLowest, models.VariantSelectHighest:
+	default:
+		return fmt.Errorf("invalid select: %s", f.Select)
+	}
+	return nil
+}
+
+// validateDeepCheck проверяет расписание углубленной проверки
+func validateDeepCheck(deep models.DeepCheckConfig, validModes map[string]bool) error {
+	if (deep.Interval > 0) == (deep.Cron != "") {
+		return fmt.Errorf("exactly one of interval or cron must be set")
+	}
+	if deep.Interval < 0 {
+		return fmt.Errorf("interval cannot be negative")
+	}
+	if deep.Cron != "" {
+		if _, err := cron.Parse(deep.Cron); err != nil {
 			return err
 		}
 	}
+	if deep.CheckMode != "" && (!validModes[deep.CheckMode] || models.SkipsSegments(deep.CheckMode)) {
+		return fmt.Errorf("invalid check_mode: %s", deep.CheckMode)
+	}
+	return nil
+}
 
+// validateTLS проверяет параметры TLS. Файлы читаются HTTP-клиентом при создании соединений.
+func validateTLS(t models.TLSConfig) error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if _, err := t.ParseMinTLSVersion(); err != nil {
+		return err
+	}
 	return nil
 }
 
-// validateMediaValidation проверяет настройки валидации медиа
+// ValidateMediaValidation проверяет настройки валидации медиа
 func (cv *Validator) ValidateMediaValidation(mv *models.MediaValidation, streamIndex int) error {
+	var errs []error
+	addf := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("stream[%d]: media_validation: "+format, append([]any{streamIndex}, args...)...))
+	}
+
 	if len(mv.ContainerType) == 0 {
-		return fmt.Errorf("stream[%d]: media_validation: container_type cannot be empty", streamIndex)
+		addf("container_type cannot be empty")
 	}
 
 	validContainers := map[string]bool{"TS": true, "fMP4": true}
 	for _, ct := range mv.ContainerType {
 		if !validContainers[ct] {
-			return fmt.Errorf("stream[%d]: media_validation: invalid container_type: %s", streamIndex, ct)
+			addf("invalid container_type: %s", ct)
 		}
 	}
 
 	if mv.MinSegmentSize < 0 {
-		return fmt.Errorf("stream[%d]: media_validation: min_segment_size cannot be negative", streamIndex)
+		addf("min_segment_size cannot be negative")
 	}
 
-	return nil
+	if mv.MaxPCRInterval < 0 || mv.MaxPCRJitter < 0 {
+		addf("PCR limits cannot be negative")
+	}
+
+	return errors.Join(errs...)
 }
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -22,6 +24,7 @@ checks:
   retry_attempts: 3
   retry_delay: "1s"
   segment_sample: 3
+  sla_windows: ["5m", "1h"]
 
 http_client:
   timeout: "5s"
@@ -29,6 +32,8 @@ http_client:
   max_idle_conns: 10
   tls_verify: true
   user_agent: "hls_exporter/1.0"
+  ca_file: "/etc/ssl/origin-ca.pem"
+  min_tls_version: "1.2"
 
 streams:
   - name: "stream_1"
@@ -37,6 +42,12 @@ streams:
     interval: "30s"
     timeout: "10s"
     validate_content: false
+    tls:
+      cert_file: "/etc/ssl/client.pem"
+      key_file: "/etc/ssl/client-key.pem"
+    deep_check:
+      cron: "0 */6 * * *"
+      check_mode: "first_last"
 
   - name: "stream_2"
     url: "http://example.com/stream2.m3u8"
@@ -68,13 +79,23 @@ streams:
 		assert.Equal(t, 9090, cfg.Server.Port)
 		assert.Equal(t, "/metrics", cfg.Server.MetricsPath)
 		assert.Equal(t, 5, cfg.Checks.Workers)
+		assert.Equal(t, []time.Duration{5 * time.Minute, time.Hour}, cfg.Checks.SLAWindows)
 		assert.Equal(t, 2, len(cfg.Streams))
+		assert.Equal(t, "/etc/ssl/origin-ca.pem", cfg.HTTPClient.CAFile)
+		assert.Equal(t, "1.2", cfg.HTTPClient.MinTLSVersion)
+		assert.Equal(t, "X-Request-ID", cfg.HTTPClient.RequestIDHeader)
 
 		stream1 := cfg.Streams[0]
 		assert.Equal(t, "stream_1", stream1.Name)
 		assert.Equal(t, "first_last", stream1.CheckMode)
 		assert.Equal(t, 30*time.Second, stream1.Interval)
 		assert.False(t, stream1.ValidateContent)
+		require.NotNil(t, stream1.TLS)
+		assert.Equal(t, "/etc/ssl/client.pem", stream1.TLS.CertFile)
+		assert.Equal(t, "/etc/ssl/client-key.pem", stream1.TLS.KeyFile)
+		require.NotNil(t, stream1.DeepCheck)
+		assert.Equal(t, "0 */6 * * *", stream1.DeepCheck.Cron)
+		assert.Equal(t, models.CheckModeFirstLast, stream1.DeepCheck.CheckMode)
 
 		stream2 := cfg.Streams[1]
 		assert.Equal(t, "stream_2", stream2.Name)
@@ -103,47 +124,755 @@ streams:
     timeout: "10s"`,
 			expectError: "invalid server port",
 		},
+		{
+			name: "invalid grpc address",
+			configFile: `
+server:
+  port: 9090
+  grpc_address: "9091"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "invalid grpc_address: 9091",
+		},
 		{
 			name: "empty stream name",
 			configFile: `
 server:
   port: 9090
 streams:
-  - name: ""
+  - name: ""
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "name cannot be empty",
+		},
+		{
+			name: "invalid check mode",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "invalid"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "invalid check_mode",
+		},
+		{
+			name: "timeout greater than interval",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "10s"
+    timeout: "30s"`,
+			expectError: "timeout must be less than interval",
+		},
+		{
+			name: "invalid media validation",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    validate_content: true
+    media_validation:
+      container_type: ["invalid"]
+      min_segment_size: 1024
+      check_audio: true
+      check_video: true`,
+			expectError: "invalid container_type",
+		},
+		{
+			name: "invalid fault injection stage",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+fault_injection:
+  - stage: "manifest"
+    status_code: 503`,
+			expectError: "invalid stage",
+		},
+		{
+			name: "invalid fault injection probability",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+fault_injection:
+  - stage: "key"
+    error: "connection reset"
+    probability: 1.5`,
+			expectError: "probability must be in range",
+		},
+		{
+			name: "invalid silence matcher",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    labels:
+      tenant: "news"
+silences:
+  - name: "maintenance"
+    matchers: ['region="eu"']`,
+			expectError: "silences[0]: invalid matcher",
+		},
+		{
+			name: "invalid variant filter",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    variant_filter:
+      min_bandwidth: 2000000
+      max_bandwidth: 1000000`,
+			expectError: "variant_filter: max_bandwidth must be greater than or equal to min_bandwidth",
+		},
+		{
+			name: "invalid expected resolution",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    expect_resolutions: ["1080p"]`,
+			expectError: "invalid expect_resolutions value",
+		},
+		{
+			name: "unknown validator",
+			configFile: `
+server:
+  port: 9090
+plugins:
+  - name: "scte"
+    path: "/opt/hls_exporter/scte.so"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    validators: ["scte", "drm"]`,
+			expectError: "stream[0]: unknown validator: drm",
+		},
+		{
+			name: "empty hook command",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    hook:
+      timeout: "5s"`,
+			expectError: "hook: command is required",
+		},
+		{
+			name: "negative slo target",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    slo:
+      max_segment_download_ratio: -0.5`,
+			expectError: "slo: max_segment_download_ratio cannot be negative",
+		},
+		{
+			name: "user agents with user-agent header",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    headers:
+      user-agent: "custom"
+    user_agents: ["applecoremedia", "exoplayer"]`,
+			expectError: "user_agents conflicts with User-Agent in headers",
+		},
+		{
+			name: "duplicate api token",
+			configFile: `
+server:
+  port: 9090
+  api_tokens:
+    - name: "news"
+      token: "secret"
+      selector: ['stream=~"news_.*"']
+    - name: "sports"
+      token: "secret"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "api_tokens[1]: duplicate token",
+		},
+		{
+			name: "invalid parse mode",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    parse_mode: "pedantic"`,
+			expectError: "invalid parse_mode",
+		},
+		{
+			name: "unknown conformance rule",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    strict: true
+    conformance_rules:
+      enable: ["independent_segments"]
+      disable: ["no_such_rule"]`,
+			expectError: "conformance_rules: unknown rule: no_such_rule",
+		},
+		{
+			name: "basic auth without username",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    basic_auth:
+      password: "secret"`,
+			expectError: "basic_auth: username cannot be empty",
+		},
+		{
+			name: "basic auth with bearer token",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    bearer_token: "token"
+    basic_auth:
+      username: "user"`,
+			expectError: "mutually exclusive",
+		},
+		{
+			name: "negative segment duration threshold",
+			configFile: `
+server:
+  port: 9090
+checks:
+  segment_duration_max_cv: -0.5
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "segment_duration_max_cv cannot be negative",
+		},
+		{
+			name: "invalid readiness mode",
+			configFile: `
+server:
+  port: 9090
+  readiness_mode: "all_up"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "invalid readiness_mode: all_up",
+		},
+		{
+			name: "negative max fast retry streams",
+			configFile: `
+server:
+  port: 9090
+checks:
+  max_fast_retry_streams: -1
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "max_fast_retry_streams cannot be negative",
+		},
+		{
+			name: "negative key cache ttl",
+			configFile: `
+server:
+  port: 9090
+checks:
+  key_cache_ttl: -1m
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "key_cache_ttl cannot be negative",
+		},
+		{
+			name: "negative retry delay",
+			configFile: `
+server:
+  port: 9090
+checks:
+  retry_delay: -1s
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "retry_delay cannot be negative",
+		},
+		{
+			name: "invalid stream label name",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    labels:
+      __region: eu`,
+			expectError: "labels: invalid label name",
+		},
+		{
+			name: "reserved stream label name",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    labels:
+      group: news`,
+			expectError: "labels: reserved label name: group",
+		},
+		{
+			name: "too many distinct stream labels",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "first"
+    url: "http://example.com/1"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    labels: {l1: a, l2: a, l3: a, l4: a, l5: a, l6: a}
+  - name: "second"
+    url: "http://example.com/2"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    labels: {l7: a, l8: a, l9: a, l10: a, l11: a}`,
+			expectError: "streams use too many distinct labels: 11",
+		},
+		{
+			name: "unsorted duration buckets",
+			configFile: `
+server:
+  port: 9090
+checks:
+  duration_buckets: [1, 5, 2]
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "duration_buckets must be positive and strictly increasing",
+		},
+		{
+			name: "too short sla window",
+			configFile: `
+server:
+  port: 9090
+checks:
+  sla_windows: ["30s"]
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "sla_windows must be at least 1m: 30s",
+		},
+		{
+			name: "negative segment duration tolerance",
+			configFile: `
+server:
+  port: 9090
+checks:
+  segment_duration_tolerance: -0.1
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "segment_duration_tolerance cannot be negative",
+		},
+		{
+			name: "negative soak threshold",
+			configFile: `
+server:
+  port: 9090
+soak:
+  enabled: true
+  max_goroutines: -1
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "soak: thresholds cannot be negative",
+		},
+		{
+			name: "range bytes too small",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    range_bytes: 100`,
+			expectError: "stream[0]: range_bytes must be at least 1024",
+		},
+		{
+			name: "invalid size anomaly threshold",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    size_anomaly:
+      threshold: 1.5`,
+			expectError: "stream[0]: size_anomaly: threshold must be in range (0, 1)",
+		},
+		{
+			name: "unknown backoff policy",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    backoff:
+      policy: "linear"`,
+			expectError: "stream[0]: backoff: invalid policy: linear",
+		},
+		{
+			name: "fast retry shorter than timeout",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    backoff:
+      policy: "fast_retry"
+      retry_interval: "5s"`,
+			expectError: "stream[0]: backoff: retry_interval must be greater than timeout",
+		},
+		{
+			name: "exponential backoff without limit",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    backoff:
+      policy: "exponential"
+      multiplier: 3`,
+			expectError: "stream[0]: backoff: max_interval must be greater than 0",
+		},
+		{
+			name: "negative max segment bytes",
+			configFile: `
+server:
+  port: 9090
+http_client:
+  max_segment_bytes: -1
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "max_segment_bytes cannot be negative",
+		},
+		{
+			name: "invalid overrun policy",
+			configFile: `
+server:
+  port: 9090
+checks:
+  overrun_policy: "drop"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "invalid overrun_policy: drop",
+		},
+		{
+			name: "negative transaction log body size",
+			configFile: `
+server:
+  port: 9090
+checks:
+  transaction_log:
+    enabled: true
+    max_body_bytes: -1
+streams:
+  - name: "test"
     url: "http://example.com"
     check_mode: "all"
     interval: "30s"
     timeout: "10s"`,
-			expectError: "name cannot be empty",
+			expectError: "transaction_log: max_body_bytes cannot be negative",
 		},
 		{
-			name: "invalid check mode",
+			name: "negative host budget",
 			configFile: `
 server:
   port: 9090
+checks:
+  host_budget:
+    requests_per_second: -5
 streams:
   - name: "test"
     url: "http://example.com"
-    check_mode: "invalid"
+    check_mode: "all"
     interval: "30s"
     timeout: "10s"`,
-			expectError: "invalid check_mode",
+			expectError: "host_budget: values cannot be negative",
 		},
 		{
-			name: "timeout greater than interval",
+			name: "negative max requests per second",
 			configFile: `
 server:
   port: 9090
+checks:
+  max_requests_per_second: -1
 streams:
   - name: "test"
     url: "http://example.com"
     check_mode: "all"
-    interval: "10s"
-    timeout: "30s"`,
-			expectError: "timeout must be less than interval",
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "max_requests_per_second cannot be negative",
 		},
 		{
-			name: "invalid media validation",
+			name: "event log without path",
+			configFile: `
+server:
+  port: 9090
+event_log:
+  enabled: true
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "event_log: path is required",
+		},
+		{
+			name: "negative history retention",
+			configFile: `
+server:
+  port: 9090
+history:
+  enabled: true
+  retention: "-1h"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "history: retention cannot be negative",
+		},
+		{
+			name: "jitter out of range",
+			configFile: `
+server:
+  port: 9090
+checks:
+  jitter: 0.8
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "jitter must be in range [0, 0.5]",
+		},
+		{
+			name: "negative scrape concurrency",
+			configFile: `
+server:
+  port: 9090
+checks:
+  collect_on_scrape: true
+  scrape_concurrency: -1
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "scrape_concurrency cannot be negative",
+		},
+		{
+			name: "shard index out of range",
+			configFile: `
+server:
+  port: 9090
+cluster:
+  shard_count: 3
+  shard_index: 3
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "cluster: shard_index must be in range [0, 2]",
+		},
+		{
+			name: "cluster self not in peers",
+			configFile: `
+server:
+  port: 9090
+cluster:
+  peers: ["exporter-0", "exporter-1"]
+  self: "exporter-2"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: `cluster: self "exporter-2" is not in peers`,
+		},
+		{
+			name: "static shards and peers together",
+			configFile: `
+server:
+  port: 9090
+cluster:
+  shard_count: 2
+  peers: ["exporter-0"]
+  self: "exporter-0"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "cluster: shard_count and peers are mutually exclusive",
+		},
+		{
+			name: "negative PCR limit",
 			configFile: `
 server:
   port: 9090
@@ -155,11 +884,83 @@ streams:
     timeout: "10s"
     validate_content: true
     media_validation:
-      container_type: ["invalid"]
-      min_segment_size: 1024
-      check_audio: true
-      check_video: true`,
-			expectError: "invalid container_type",
+      container_type: ["TS"]
+      max_pcr_jitter: "-1ms"`,
+			expectError: "PCR limits cannot be negative",
+		},
+		{
+			name: "invalid min TLS version",
+			configFile: `
+server:
+  port: 9090
+http_client:
+  min_tls_version: "1.4"
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`,
+			expectError: "http_client: invalid min_tls_version",
+		},
+		{
+			name: "client certificate without key",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "https://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    tls:
+      cert_file: "/etc/ssl/client.pem"`,
+			expectError: "stream[0]: tls: cert_file and key_file must be set together",
+		},
+		{
+			name: "invalid resolve address",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "https://cdn.example.com/master.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    resolve: ["cdn.example.com:443:edge-1"]`,
+			expectError: `stream[0]: resolve: invalid resolve entry "cdn.example.com:443:edge-1"`,
+		},
+		{
+			name: "deep check without schedule",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "playlist_only"
+    interval: "30s"
+    timeout: "10s"
+    deep_check:
+      check_mode: "all"`,
+			expectError: "stream[0]: deep_check: exactly one of interval or cron must be set",
+		},
+		{
+			name: "deep check invalid cron",
+			configFile: `
+server:
+  port: 9090
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "playlist_only"
+    interval: "30s"
+    timeout: "10s"
+    deep_check:
+      cron: "*/15 * * *"`,
+			expectError: "stream[0]: deep_check: invalid cron expression",
 		},
 	}
 
@@ -211,6 +1012,223 @@ streams:
 	assert.Equal(t, 8080, cfg.Server.Port)
 }
 
+func TestEnvironmentOverrides_Cluster(t *testing.T) {
+	configContent := `
+cluster:
+  shard_count: 4
+streams:
+  - name: "test"
+    url: "http://example.com"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"`
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configContent), 0o600))
+
+	// Номер шарда задается для каждого экземпляра через окружение
+	t.Setenv("HLS_CLUSTER_SHARD_INDEX", "2")
+
+	cfg, err := NewConfigManager().LoadConfig(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.ClusterConfig{ShardCount: 4, ShardIndex: 2}, cfg.Cluster)
+}
+
+func TestProfiles(t *testing.T) {
+	configContent := `
+server:
+  port: 9090
+profiles:
+  SportsHD:
+    check_mode: "first_last"
+    interval: "30s"
+    timeout: "10s"
+    validate_content: true
+    media_validation:
+      container_type: ["TS"]
+      min_segment_size: 1024
+    headers:
+      X-Origin-Key: "profile-key"
+      X-Client: "exporter"
+    bearer_token: "profile-token"
+    tls:
+      ca_file: "/etc/ssl/sports-ca.pem"
+    deep_check:
+      interval: "15m"
+streams:
+  - name: "sport_1"
+    url: "http://example.com/sport1.m3u8"
+    profile: "SportsHD"
+  - name: "sport_2"
+    url: "http://example.com/sport2.m3u8"
+    profile: "SportsHD"
+    check_mode: "all"
+    interval: "1m"
+    headers:
+      X-Origin-Key: "stream-key"
+    basic_auth:
+      username: "user"
+      password: "pass"`
+
+	tmpfile, err := os.CreateTemp("", "config-*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	_, err = tmpfile.Write([]byte(configContent))
+	require.NoError(t, err)
+	tmpfile.Close()
+
+	cfg, err := NewConfigManager().LoadConfig(tmpfile.Name())
+	require.NoError(t, err)
+	require.Len(t, cfg.Streams, 2)
+
+	sport1 := cfg.Streams[0]
+	assert.Equal(t, models.CheckModeFirstLast, sport1.CheckMode)
+	assert.Equal(t, 30*time.Second, sport1.Interval)
+	assert.Equal(t, 10*time.Second, sport1.Timeout)
+	assert.True(t, sport1.ValidateContent)
+	require.NotNil(t, sport1.MediaValidation)
+	assert.Equal(t, int64(1024), sport1.MediaValidation.MinSegmentSize)
+	assert.Equal(t, "profile-token", sport1.BearerToken)
+	// Viper приводит ключи map к нижнему регистру, регистр заголовков не важен
+	assert.Equal(t, "profile-key", sport1.Headers["x-origin-key"])
+	require.NotNil(t, sport1.TLS)
+	assert.Equal(t, "/etc/ssl/sports-ca.pem", sport1.TLS.CAFile)
+	require.NotNil(t, sport1.DeepCheck)
+	assert.Equal(t, 15*time.Minute, sport1.DeepCheck.Interval)
+
+	// Явно заданные поля стрима переопределяют профиль
+	sport2 := cfg.Streams[1]
+	assert.Equal(t, models.CheckModeAll, sport2.CheckMode)
+	assert.Equal(t, time.Minute, sport2.Interval)
+	assert.Equal(t, 10*time.Second, sport2.Timeout)
+	assert.Equal(t, map[string]string{"x-origin-key": "stream-key", "x-client": "exporter"}, sport2.Headers)
+	require.NotNil(t, sport2.BasicAuth)
+	assert.Equal(t, "user", sport2.BasicAuth.Username)
+	assert.Empty(t, sport2.BearerToken)
+
+	t.Run("unknown profile", func(t *testing.T) {
+		cfg := &models.Config{
+			Streams: []models.StreamConfig{{Name: "test", Profile: "missing"}},
+		}
+		err := applyProfiles(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown profile")
+	})
+}
+
+func TestDevices(t *testing.T) {
+	configContent := `
+server:
+  port: 9090
+devices:
+  SmartTV:
+    user_agent: "Mozilla/5.0 (SMART-TV; Linux; Tizen 7.0)"
+    headers:
+      Accept: "application/vnd.apple.mpegurl"
+      Origin: "https://tv.example.com"
+      Referer: "https://tv.example.com/player"
+profiles:
+  tv:
+    device: "smarttv"
+streams:
+  - name: "tv_1"
+    url: "http://example.com/tv1.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    profile: "tv"
+  - name: "tv_2"
+    url: "http://example.com/tv2.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    device: "SmartTV"
+    user_agents: ["exoplayer", "hlsjs"]
+    headers:
+      referer: "https://partner.example.com"`
+
+	tmpfile, err := os.CreateTemp("", "config-*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	_, err = tmpfile.Write([]byte(configContent))
+	require.NoError(t, err)
+	tmpfile.Close()
+
+	cfg, err := NewConfigManager().LoadConfig(tmpfile.Name())
+	require.NoError(t, err)
+	require.Len(t, cfg.Streams, 2)
+
+	// Устройство из профиля
+	assert.Equal(t, map[string]string{
+		"User-Agent": "Mozilla/5.0 (SMART-TV; Linux; Tizen 7.0)",
+		"Accept":     "application/vnd.apple.mpegurl",
+		"Origin":     "https://tv.example.com",
+		"Referer":    "https://tv.example.com/player",
+	}, cfg.Streams[0].Headers)
+
+	// Заголовки стрима имеют приоритет, а user_agents заменяет User-Agent устройства
+	assert.Equal(t, map[string]string{
+		"Accept":  "application/vnd.apple.mpegurl",
+		"Origin":  "https://tv.example.com",
+		"Referer": "https://partner.example.com",
+	}, cfg.Streams[1].Headers)
+
+	t.Run("unknown device", func(t *testing.T) {
+		cfg := &models.Config{
+			Streams: []models.StreamConfig{{Name: "test", Device: "missing"}},
+		}
+		err := applyDevices(cfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown device")
+	})
+}
+
+func TestCustomLabelSelectors(t *testing.T) {
+	configContent := `
+server:
+  port: 9090
+  api_tokens:
+    - name: "media"
+      token: "secret"
+      selector: ['tenant="media"']
+streams:
+  - name: "news"
+    url: "http://example.com/news.m3u8"
+    check_mode: "all"
+    interval: "30s"
+    timeout: "10s"
+    labels:
+      tenant: "media"
+silences:
+  - name: "maintenance"
+    matchers: ['tenant="media"', 'error_type="segment_download"']`
+
+	tmpfile, err := os.CreateTemp("", "config-*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+
+	_, err = tmpfile.Write([]byte(configContent))
+	require.NoError(t, err)
+	tmpfile.Close()
+
+	// Пользовательские метки стримов доступны в селекторах токенов и silences
+	cfg, err := NewConfigManager().LoadConfig(tmpfile.Name())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tenant": "media"}, cfg.Streams[0].Labels)
+}
+
+func TestValidateLabelNames(t *testing.T) {
+	streams := make([]models.StreamConfig, 0, maxStreamLabels+1)
+	for i := range maxStreamLabels + 1 {
+		streams = append(streams, models.StreamConfig{Labels: map[string]string{fmt.Sprintf("label_%d", i): "x"}})
+	}
+	assert.NoError(t, ValidateLabelNames(streams[:maxStreamLabels]))
+	assert.ErrorContains(t, ValidateLabelNames(streams), "too many distinct labels")
+}
+
 // Добавим тесты для валидатора отдельно
 func TestConfigValidator(t *testing.T) {
 	validator := NewValidator()
@@ -237,4 +1255,20 @@ func TestConfigValidator(t *testing.T) {
 		err := validator.ValidateMediaValidation(mv, 0)
 		assert.NoError(t, err)
 	})
+	t.Run("all problems reported", func(t *testing.T) {
+		cfg := &models.Config{
+			Server: models.ServerConfig{Port: 9090},
+			Checks: models.CheckConfig{Workers: 0},
+			Streams: []models.StreamConfig{
+				{URL: "http://example.com", CheckMode: "sometimes", Interval: 10 * time.Second, Timeout: 10 * time.Second},
+				{Name: "b", URL: "http://example.com", CheckMode: models.CheckModeAll, Interval: 30 * time.Second, Timeout: 10 * time.Second},
+			},
+		}
+		err := validator.Validate(cfg)
+		require.Error(t, err)
+		assert.Equal(t, `workers must be greater than 0
+stream[0]: name cannot be empty
+stream[0]: invalid check_mode: sometimes
+stream[0]: timeout must be less than interval`, err.Error())
+	})
 }
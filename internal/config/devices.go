@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// applyDevices добавляет заголовки именованных устройств к стримам, которые на них
+// ссылаются напрямую или через профиль
+func applyDevices(cfg *models.Config) error {
+	var errs []error
+	for i := range cfg.Streams {
+		stream := &cfg.Streams[i]
+		if stream.Device == "" {
+			continue
+		}
+
+		// Viper приводит ключи map к нижнему регистру
+		device, ok := cfg.Devices[strings.ToLower(stream.Device)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("stream[%d]: unknown device: %s", i, stream.Device))
+			continue
+		}
+		ApplyDevice(stream, device)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyDevice дополняет заголовки стрима заголовками устройства. Заголовки стрима
+// имеют приоритет, а User-Agent устройства не применяется к стримам с user_agents.
+func ApplyDevice(stream *models.StreamConfig, device models.DeviceConfig) {
+	headers := make(map[string]string, len(device.Headers)+len(stream.Headers)+1)
+	if device.UserAgent != "" {
+		headers["User-Agent"] = device.UserAgent
+	}
+	// Имена приводятся к каноническому виду, чтобы заголовки стрима заменяли
+	// заголовки устройства независимо от регистра
+	for name, value := range device.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	if len(stream.UserAgents) > 0 {
+		delete(headers, "User-Agent")
+	}
+	for name, value := range stream.Headers {
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	stream.Headers = headers
+}
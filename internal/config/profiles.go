@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// applyProfiles подставляет параметры именованных профилей в стримы,
+// которые на них ссылаются. Явно заданные в стриме значения имеют приоритет.
+func applyProfiles(cfg *models.Config) error {
+	var errs []error
+	for i := range cfg.Streams {
+		stream := &cfg.Streams[i]
+		if stream.Profile == "" {
+			continue
+		}
+
+		// Viper приводит ключи map к нижнему регистру
+		profile, ok := cfg.Profiles[strings.ToLower(stream.Profile)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("stream[%d]: unknown profile: %s", i, stream.Profile))
+			continue
+		}
+		ApplyProfile(stream, profile)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyProfile заполняет незаданные поля стрима значениями профиля
+func ApplyProfile(stream *models.StreamConfig, profile models.ProfileConfig) {
+	if stream.CheckMode == "" {
+		stream.CheckMode = profile.CheckMode
+	}
+	if stream.Interval == 0 {
+		stream.Interval = profile.Interval
+	}
+	if stream.Timeout == 0 {
+		stream.Timeout = profile.Timeout
+	}
+	if !stream.ValidateContent {
+		stream.ValidateContent = profile.ValidateContent
+	}
+	if stream.MediaValidation == nil && profile.MediaValidation != nil {
+		mv := *profile.MediaValidation
+		stream.MediaValidation = &mv
+	}
+	if stream.DailyByteBudget == 0 {
+		stream.DailyByteBudget = profile.DailyByteBudget
+	}
+	if stream.RangeBytes == 0 {
+		stream.RangeBytes = profile.RangeBytes
+	}
+	if stream.SegmentSample == 0 {
+		stream.SegmentSample = profile.SegmentSample
+	}
+	if !stream.Strict {
+		stream.Strict = profile.Strict
+	}
+	if !stream.AdMarkers {
+		stream.AdMarkers = profile.AdMarkers
+	}
+	if !stream.ExpectLive {
+		stream.ExpectLive = profile.ExpectLive
+	}
+	if stream.Device == "" {
+		stream.Device = profile.Device
+	}
+	if !stream.ValidateSessionData {
+		stream.ValidateSessionData = profile.ValidateSessionData
+	}
+	if stream.ParseMode == "" {
+		stream.ParseMode = profile.ParseMode
+	}
+	if len(profile.Headers) > 0 {
+		headers := make(map[string]string, len(profile.Headers)+len(stream.Headers))
+		for name, value := range profile.Headers {
+			headers[name] = value
+		}
+		for name, value := range stream.Headers {
+			headers[name] = value
+		}
+		stream.Headers = headers
+	}
+	// Способ авторизации стрима целиком заменяет способ профиля
+	if stream.BasicAuth == nil && stream.BearerToken == "" {
+		if profile.BasicAuth != nil {
+			ba := *profile.BasicAuth
+			stream.BasicAuth = &ba
+		}
+		stream.BearerToken = profile.BearerToken
+	}
+	if stream.TLS == nil && profile.TLS != nil {
+		tlsCfg := *profile.TLS
+		stream.TLS = &tlsCfg
+	}
+	if stream.Backoff == nil && profile.Backoff != nil {
+		backoff := *profile.Backoff
+		stream.Backoff = &backoff
+	}
+	if stream.SizeAnomaly == nil && profile.SizeAnomaly != nil {
+		sizeAnomaly := *profile.SizeAnomaly
+		stream.SizeAnomaly = &sizeAnomaly
+	}
+	if stream.ConformanceRules == nil && profile.ConformanceRules != nil {
+		rules := *profile.ConformanceRules
+		stream.ConformanceRules = &rules
+	}
+	if stream.DeepCheck == nil && profile.DeepCheck != nil {
+		deep := *profile.DeepCheck
+		stream.DeepCheck = &deep
+	}
+	if stream.VariantFilter == nil && profile.VariantFilter != nil {
+		filter := *profile.VariantFilter
+		stream.VariantFilter = &filter
+	}
+	if stream.Hook == nil && profile.Hook != nil {
+		hook := *profile.Hook
+		stream.Hook = &hook
+	}
+	if stream.SLO == nil && profile.SLO != nil {
+		slo := *profile.SLO
+		stream.SLO = &slo
+	}
+	if len(stream.UserAgents) == 0 {
+		stream.UserAgents = profile.UserAgents
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+)
+
+// webConfig файл web config prometheus/exporter-toolkit. Поддерживается подмножество
+// формата: сертификат и ключ TLS и пользователи Basic-авторизации. Остальные параметры
+// отклоняются, чтобы не создавать ложного впечатления о защите.
+type webConfig struct {
+	TLSServerConfig struct {
+		CertFile string `yaml:"cert_file"`
+		KeyFile  string `yaml:"key_file"`
+	} `yaml:"tls_server_config"`
+	BasicAuthUsers map[string]string `yaml:"basic_auth_users"`
+}
+
+// applyWebConfig заполняет параметры TLS и Basic-авторизации сервера из web_config_file
+func applyWebConfig(server *models.ServerConfig) error {
+	if server.WebConfigFile == "" {
+		return nil
+	}
+	if server.TLSCertFile != "" || server.TLSKeyFile != "" || len(server.BasicAuthUsers) > 0 {
+		return errors.New("server: web_config_file cannot be combined with tls_cert_file, tls_key_file or basic_auth_users")
+	}
+
+	data, err := os.ReadFile(server.WebConfigFile)
+	if err != nil {
+		return fmt.Errorf("server: failed to read web_config_file: %w", err)
+	}
+	var web webConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	// Пустой файл допустим и означает сервер без TLS и авторизации
+	if err := decoder.Decode(&web); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("server: invalid web_config_file: %w", err)
+	}
+
+	server.TLSCertFile = web.TLSServerConfig.CertFile
+	server.TLSKeyFile = web.TLSServerConfig.KeyFile
+	server.BasicAuthUsers = web.BasicAuthUsers
+	return nil
+}
+
+// validateServerAuth проверяет параметры TLS и Basic-авторизации сервера
+func validateServerAuth(server models.ServerConfig) error {
+	var errs []error
+	if (server.TLSCertFile == "") != (server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("server: tls_cert_file and tls_key_file must be set together"))
+	}
+	for user, hash := range server.BasicAuthUsers {
+		if user == "" {
+			errs = append(errs, errors.New("server: basic_auth_users: user name is required"))
+			continue
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			errs = append(errs, fmt.Errorf("server: basic_auth_users: %s: password must be a bcrypt hash", user))
+		}
+	}
+	return errors.Join(errs...)
+}
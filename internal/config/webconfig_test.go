@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBcryptHash хеш bcrypt пароля "secret"
+const testBcryptHash = "$2a$04$ivk2um3g3owgVk4kJmeUIOwBrLpDkY1cSbJTeES7XT1l46mwdoJzG"
+
+func TestApplyWebConfig(t *testing.T) {
+	dir := t.TempDir()
+	webConfigFile := filepath.Join(dir, "web.yml")
+	require.NoError(t, os.WriteFile(webConfigFile, []byte(`
+tls_server_config:
+  cert_file: /etc/hls_exporter/tls.crt
+  key_file: /etc/hls_exporter/tls.key
+basic_auth_users:
+  Prometheus: "`+testBcryptHash+`"
+`), 0o600))
+
+	server := models.ServerConfig{WebConfigFile: webConfigFile}
+	require.NoError(t, applyWebConfig(&server))
+	assert.Equal(t, "/etc/hls_exporter/tls.crt", server.TLSCertFile)
+	assert.Equal(t, "/etc/hls_exporter/tls.key", server.TLSKeyFile)
+	// Имена пользователей из файла сохраняют регистр
+	assert.Equal(t, map[string]string{"Prometheus": testBcryptHash}, server.BasicAuthUsers)
+	assert.NoError(t, validateServerAuth(server))
+
+	t.Run("combined with inline settings", func(t *testing.T) {
+		server := models.ServerConfig{WebConfigFile: webConfigFile, TLSCertFile: "tls.crt"}
+		assert.ErrorContains(t, applyWebConfig(&server), "cannot be combined")
+	})
+
+	t.Run("unsupported option", func(t *testing.T) {
+		path := filepath.Join(dir, "mtls.yml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+tls_server_config:
+  client_ca_file: ca.crt
+`), 0o600))
+		server := models.ServerConfig{WebConfigFile: path}
+		assert.ErrorContains(t, applyWebConfig(&server), "invalid web_config_file")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		server := models.ServerConfig{WebConfigFile: filepath.Join(dir, "missing.yml")}
+		assert.ErrorContains(t, applyWebConfig(&server), "failed to read web_config_file")
+	})
+}
+
+func TestValidateServerAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		server      models.ServerConfig
+		expectError string
+	}{
+		{name: "disabled"},
+		{
+			name:   "tls and basic auth",
+			server: models.ServerConfig{TLSCertFile: "tls.crt", TLSKeyFile: "tls.key", BasicAuthUsers: map[string]string{"prometheus": testBcryptHash}},
+		},
+		{
+			name:        "cert without key",
+			server:      models.ServerConfig{TLSCertFile: "tls.crt"},
+			expectError: "tls_cert_file and tls_key_file must be set together",
+		},
+		{
+			name:        "plain text password",
+			server:      models.ServerConfig{BasicAuthUsers: map[string]string{"prometheus": "secret"}},
+			expectError: "prometheus: password must be a bcrypt hash",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateServerAuth(tt.server)
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectError)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Package conformance проверяет плейлисты на соответствие RFC 8216 в строгом
+// режиме: порядок и размещение тегов, теги, требующие версии протокола, и
+// синтаксис списков атрибутов. Каждая проверка - отдельное правило (Rule),
+// правила включаются и выключаются для стрима по идентификатору.
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Правила соответствия, значения метки rule
+const (
+	RuleHeader            = "extm3u_header"
+	RuleVersion           = "version_tag"
+	RuleVersionGated      = "version_gated_tag"
+	RuleTargetDuration    = "target_duration"
+	RuleTagPlacement      = "tag_placement"
+	RuleMixedTags         = "mixed_playlist_tags"
+	RuleURIPlacement      = "uri_placement"
+	RuleAttributeSyntax   = "attribute_syntax"
+	RuleRequiredAttribute = "required_attribute"
+	RuleURIScheme         = "uri_scheme"
+	// Рекомендации Apple HLS Authoring Specification, по умолчанию выключены
+	RuleIndependentSegments = "independent_segments"
+	RuleRequiredTags        = "required_tags"
+)
+
+// playlistTags теги медиаплейлиста, допустимые только до первого сегмента
+var playlistTags = map[string]bool{
+	"#EXT-X-TARGETDURATION":         true,
+	"#EXT-X-MEDIA-SEQUENCE":         true,
+	"#EXT-X-DISCONTINUITY-SEQUENCE": true,
+	"#EXT-X-PLAYLIST-TYPE":          true,
+	"#EXT-X-I-FRAMES-ONLY":          true,
+	"#EXT-X-PART-INF":               true,
+	"#EXT-X-SERVER-CONTROL":         true,
+}
+
+// segmentTags теги, относящиеся к сегментам медиаплейлиста
+var segmentTags = map[string]bool{
+	"#EXTINF":                  true,
+	"#EXT-X-BYTERANGE":         true,
+	"#EXT-X-DISCONTINUITY":     true,
+	"#EXT-X-KEY":               true,
+	"#EXT-X-MAP":               true,
+	"#EXT-X-PROGRAM-DATE-TIME": true,
+	"#EXT-X-DATERANGE":         true,
+	"#EXT-X-GAP":               true,
+	"#EXT-X-PART":              true,
+	"#EXT-X-PRELOAD-HINT":      true,
+	"#EXT-X-RENDITION-REPORT":  true,
+	"#EXT-X-SKIP":              true,
+	"#EXT-X-ENDLIST":           true,
+}
+
+// masterTags теги мастер-плейлиста
+var masterTags = map[string]bool{
+	"#EXT-X-STREAM-INF":         true,
+	"#EXT-X-I-FRAME-STREAM-INF": true,
+	"#EXT-X-MEDIA":              true,
+	"#EXT-X-SESSION-DATA":       true,
+	"#EXT-X-SESSION-KEY":        true,
+}
+
+// requiredAttributes обязательные атрибуты тегов со списком атрибутов;
+// nil - список атрибутов проверяется только на синтаксис
+var requiredAttributes = map[string][]string{
+	"#EXT-X-STREAM-INF":         {"BANDWIDTH"},
+	"#EXT-X-I-FRAME-STREAM-INF": {"BANDWIDTH", "URI"},
+	"#EXT-X-MEDIA":              {"TYPE", "GROUP-ID", "NAME"},
+	"#EXT-X-KEY":                {"METHOD"},
+	"#EXT-X-SESSION-KEY":        {"METHOD"},
+	"#EXT-X-MAP":                {"URI"},
+	"#EXT-X-SESSION-DATA":       {"DATA-ID"},
+	"#EXT-X-DATERANGE":          {"ID", "START-DATE"},
+	"#EXT-X-PART":               {"URI", "DURATION"},
+	"#EXT-X-PART-INF":           {"PART-TARGET"},
+	"#EXT-X-PRELOAD-HINT":       {"TYPE", "URI"},
+	"#EXT-X-RENDITION-REPORT":   {"URI"},
+	"#EXT-X-SKIP":               {"SKIPPED-SEGMENTS"},
+	"#EXT-X-START":              {"TIME-OFFSET"},
+	"#EXT-X-SERVER-CONTROL":     nil,
+	"#EXT-X-DEFINE":             nil,
+	"#EXT-X-CONTENT-STEERING":   {"SERVER-URI"},
+}
+
+// Line непустая строка плейлиста с номером
+type Line struct {
+	Num  int
+	Text string
+}
+
+// splitLines возвращает непустые строки плейлиста с номерами
+func splitLines(body []byte) []Line {
+	var lines []Line
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text != "" {
+			lines = append(lines, Line{Num: n, Text: text})
+		}
+	}
+	return lines
+}
+
+// parseAttributes разбирает список атрибутов вида NAME=VALUE,NAME="quoted".
+// problems содержит описания синтаксических ошибок; при ok == false список
+// разобрать не удалось, а повторы атрибутов не мешают разбору.
+func parseAttributes(tag, value string) (attrs map[string]string, problems []string, ok bool) {
+	attrs = make(map[string]string)
+	if value == "" {
+		return attrs, nil, true
+	}
+
+	rest := value
+	for rest != "" {
+		name, after, found := strings.Cut(rest, "=")
+		if !found || !validAttributeName(name) {
+			return nil, append(problems, fmt.Sprintf("%s has malformed attribute list", tag[1:])), false
+		}
+
+		var attrValue string
+		if strings.HasPrefix(after, `"`) {
+			end := strings.IndexByte(after[1:], '"')
+			if end < 0 {
+				return nil, append(problems,
+					fmt.Sprintf("%s attribute %s has unterminated quoted string", tag[1:], name)), false
+			}
+			attrValue, rest = after[1:end+1], after[end+2:]
+			if rest != "" && !strings.HasPrefix(rest, ",") {
+				return nil, append(problems, fmt.Sprintf("%s has malformed attribute list", tag[1:])), false
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			attrValue, rest, _ = strings.Cut(after, ",")
+			if attrValue == "" || strings.ContainsAny(attrValue, "\" \t") {
+				return nil, append(problems, fmt.Sprintf("%s attribute %s has invalid value", tag[1:], name)), false
+			}
+		}
+		if rest == "" && strings.HasSuffix(value, ",") {
+			return nil, append(problems, fmt.Sprintf("%s has trailing comma in attribute list", tag[1:])), false
+		}
+
+		if _, dup := attrs[name]; dup {
+			problems = append(problems, fmt.Sprintf("%s has duplicate attribute %s", tag[1:], name))
+		}
+		attrs[name] = attrValue
+	}
+
+	return attrs, problems, true
+}
+
+// validAttributeName имя атрибута состоит из [A-Z0-9-]
+func validAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// splitTag делит строку тега на имя и значение после двоеточия
+func splitTag(text string) (string, string) {
+	tag, value, _ := strings.Cut(text, ":")
+	return tag, value
+}
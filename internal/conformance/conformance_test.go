@@ -0,0 +1,248 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rules(body string) []string {
+	var result []string
+	for _, v := range Check([]byte(body)) {
+		result = append(result, v.Rule)
+	}
+	return result
+}
+
+func TestCheck_Conformant(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "media playlist",
+			body: `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-MEDIA-SEQUENCE:10
+# комментарий
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin",IV=0x0A
+#EXTINF:5.960,
+seg10.ts
+#EXT-X-DISCONTINUITY
+#EXTINF:6.000,title
+seg11.ts
+#EXT-X-ENDLIST
+`,
+		},
+		{
+			name: "master playlist",
+			body: `#EXTM3U
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="English",LANGUAGE="en",URI="audio/en.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=2000000,RESOLUTION=1280x720,CODECS="avc1.4d401f,mp4a.40.2",AUDIO="aud"
+720p/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360
+360p/index.m3u8
+`,
+		},
+		{
+			name: "fmp4 with map",
+			body: `#EXTM3U
+#EXT-X-VERSION:6
+#EXT-X-TARGETDURATION:4
+#EXT-X-MAP:URI="init.mp4"
+#EXTINF:4.0,
+seg1.m4s
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, Check([]byte(tt.body)))
+		})
+	}
+}
+
+func TestCheck_Violations(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "missing header",
+			body: "#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg.ts\n",
+			want: []string{RuleHeader},
+		},
+		{
+			name: "duplicate version",
+			body: "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg.ts\n",
+			want: []string{RuleVersion},
+		},
+		{
+			name: "float duration without version 3",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:5.5,\nseg.ts\n",
+			want: []string{RuleVersionGated},
+		},
+		{
+			name: "byterange requires version 4",
+			body: "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:6.0,\n#EXT-X-BYTERANGE:1000@0\nseg.ts\n",
+			want: []string{RuleVersionGated},
+		},
+		{
+			name: "map requires version 6",
+			body: "#EXTM3U\n#EXT-X-VERSION:5\n#EXT-X-TARGETDURATION:6\n#EXT-X-MAP:URI=\"init.mp4\"\n#EXTINF:6.0,\nseg.m4s\n",
+			want: []string{RuleVersionGated},
+		},
+		{
+			name: "key iv requires version 2",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXT-X-KEY:METHOD=AES-128,URI=\"k\",IV=0x01\n#EXTINF:6,\nseg.ts\n",
+			want: []string{RuleVersionGated},
+		},
+		{
+			name: "missing target duration",
+			body: "#EXTM3U\n#EXTINF:6,\nseg.ts\n",
+			want: []string{RuleTargetDuration},
+		},
+		{
+			name: "segment exceeds target duration",
+			body: "#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:6\n#EXTINF:7.2,\nseg.ts\n",
+			want: []string{RuleTargetDuration},
+		},
+		{
+			name: "media sequence after first segment",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg1.ts\n#EXT-X-MEDIA-SEQUENCE:1\n#EXTINF:6,\nseg2.ts\n",
+			want: []string{RuleTagPlacement},
+		},
+		{
+			name: "mixed master and media tags",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXT-X-STREAM-INF:BANDWIDTH=1000\nv.m3u8\n",
+			want: []string{RuleMixedTags},
+		},
+		{
+			name: "extinf without uri",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\n#EXTINF:6,\nseg.ts\n",
+			want: []string{RuleURIPlacement},
+		},
+		{
+			name: "uri without extinf",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\nseg.ts\n",
+			want: []string{RuleURIPlacement},
+		},
+		{
+			name: "unquoted value with space",
+			body: "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1000,RESOLUTION=1280 x720\nv.m3u8\n",
+			want: []string{RuleAttributeSyntax},
+		},
+		{
+			name: "lowercase attribute name",
+			body: "#EXTM3U\n#EXT-X-STREAM-INF:bandwidth=1000\nv.m3u8\n",
+			want: []string{RuleAttributeSyntax},
+		},
+		{
+			name: "unterminated quoted string",
+			body: "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1000,CODECS=\"avc1\nv.m3u8\n",
+			want: []string{RuleAttributeSyntax},
+		},
+		{
+			name: "duplicate attribute",
+			body: "#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1000,BANDWIDTH=2000\nv.m3u8\n",
+			want: []string{RuleAttributeSyntax},
+		},
+		{
+			name: "missing bandwidth",
+			body: "#EXTM3U\n#EXT-X-STREAM-INF:RESOLUTION=1280x720\nv.m3u8\n",
+			want: []string{RuleRequiredAttribute},
+		},
+		{
+			name: "key without uri",
+			body: "#EXTM3U\n#EXT-X-TARGETDURATION:6\n#EXT-X-KEY:METHOD=AES-128\n#EXTINF:6,\nseg.ts\n",
+			want: []string{RuleRequiredAttribute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rules(tt.body))
+		})
+	}
+}
+
+func TestCheck_LineNumbers(t *testing.T) {
+	violations := Check([]byte("#EXTM3U\n\n#EXT-X-TARGETDURATION:6\n#EXTINF:6,\nseg1.ts\n#EXT-X-MEDIA-SEQUENCE:1\n"))
+
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, RuleTagPlacement, violations[0].Rule)
+		assert.Equal(t, 6, violations[0].Line)
+	}
+}
+
+func TestCheck_URIScheme(t *testing.T) {
+	body := `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-KEY:METHOD=SAMPLE-AES,URI="skd://key-id"
+#EXTINF:6.0,
+https://cdn.example.com/seg1.ts
+#EXTINF:6.0,
+file:///var/media/seg2.ts
+`
+	violations := Check([]byte(body))
+	if assert.Len(t, violations, 1) {
+		assert.Equal(t, RuleURIScheme, violations[0].Rule)
+		assert.Equal(t, 8, violations[0].Line)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	ids := func(rules []Rule) []string {
+		var result []string
+		for _, rule := range rules {
+			result = append(result, rule.ID)
+		}
+		return result
+	}
+
+	defaults, err := Select(nil, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, ids(defaults), RuleVersionGated)
+	assert.NotContains(t, ids(defaults), RuleIndependentSegments)
+	assert.NotContains(t, ids(defaults), RuleRequiredTags)
+
+	selected, err := Select([]string{RuleIndependentSegments}, []string{RuleURIScheme})
+	assert.NoError(t, err)
+	assert.Contains(t, ids(selected), RuleIndependentSegments)
+	assert.NotContains(t, ids(selected), RuleURIScheme)
+	assert.Len(t, selected, len(defaults))
+
+	_, err = Select([]string{"no_such_rule"}, nil)
+	assert.ErrorContains(t, err, "unknown rule: no_such_rule")
+}
+
+func TestCheckRules_Optional(t *testing.T) {
+	master := `#EXTM3U
+#EXT-X-STREAM-INF:BANDWIDTH=800000
+360p/index.m3u8
+`
+	selected, err := Select([]string{RuleIndependentSegments, RuleRequiredTags}, nil)
+	assert.NoError(t, err)
+
+	var got []string
+	for _, v := range CheckRules([]byte(master), selected) {
+		got = append(got, v.Rule)
+	}
+	assert.ElementsMatch(t, []string{RuleIndependentSegments, RuleRequiredTags}, got)
+
+	// Без #EXTM3U при выключенном extm3u_header нарушений нет
+	selected, err = Select(nil, []string{RuleHeader})
+	assert.NoError(t, err)
+	assert.Empty(t, CheckRules([]byte("seg1.ts\n"), selected))
+}
+
+func TestRegister(t *testing.T) {
+	assert.Panics(t, func() {
+		Register(Rule{ID: RuleVersion, Check: func(*Playlist, Reporter) {}})
+	})
+	assert.Panics(t, func() { Register(Rule{ID: "no_check"}) })
+}
@@ -0,0 +1,456 @@
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Reporter добавляет нарушение правила в строке плейлиста
+type Reporter func(line int, format string, args ...any)
+
+// Rule правило соответствия плейлиста. ID служит меткой rule метрик и именем
+// правила в conformance_rules стрима.
+type Rule struct {
+	ID string
+	// Optional правило выполняется, только если включено в conformance_rules.enable
+	Optional bool
+	Check    func(p *Playlist, report Reporter)
+}
+
+// Playlist плейлист, подготовленный для правил
+type Playlist struct {
+	// Непустые строки после #EXTM3U
+	Lines []Line
+	// Версия протокола из первого EXT-X-VERSION, 1 без тега или при некорректном значении
+	Version int
+	// Номера строк первых тегов мастер- и медиаплейлиста, 0 - таких тегов нет
+	FirstMaster int
+	FirstMedia  int
+}
+
+func newPlaylist(lines []Line) *Playlist {
+	p := &Playlist{Lines: lines[1:], Version: 1}
+	versionSeen := false
+	for _, l := range p.Lines {
+		tag, value := splitTag(l.Text)
+		switch {
+		case tag == "#EXT-X-VERSION" && !versionSeen:
+			versionSeen = true
+			if v, err := strconv.Atoi(value); err == nil && v >= 1 {
+				p.Version = v
+			}
+		case masterTags[tag]:
+			if p.FirstMaster == 0 {
+				p.FirstMaster = l.Num
+			}
+		case segmentTags[tag] || playlistTags[tag]:
+			if p.FirstMedia == 0 {
+				p.FirstMedia = l.Num
+			}
+		}
+	}
+	return p
+}
+
+// IsMaster сообщает, что плейлист содержит только теги мастер-плейлиста
+func (p *Playlist) IsMaster() bool {
+	return p.FirstMaster > 0 && p.FirstMedia == 0
+}
+
+// IsMedia сообщает, что плейлист содержит только теги медиаплейлиста
+func (p *Playlist) IsMedia() bool {
+	return p.FirstMedia > 0 && p.FirstMaster == 0
+}
+
+// registry зарегистрированные правила в порядке регистрации
+var registry = []Rule{
+	// Проверяется в CheckRules до разбора: без #EXTM3U остальные правила не выполняются
+	{ID: RuleHeader, Check: func(*Playlist, Reporter) {}},
+	{ID: RuleVersion, Check: checkVersionTag},
+	{ID: RuleVersionGated, Check: checkVersionGated},
+	{ID: RuleTargetDuration, Check: checkTargetDuration},
+	{ID: RuleTagPlacement, Check: checkTagPlacement},
+	{ID: RuleMixedTags, Check: checkMixedTags},
+	{ID: RuleURIPlacement, Check: checkURIPlacement},
+	{ID: RuleAttributeSyntax, Check: checkAttributeSyntax},
+	{ID: RuleRequiredAttribute, Check: checkRequiredAttributes},
+	{ID: RuleURIScheme, Check: checkURIScheme},
+	{ID: RuleIndependentSegments, Optional: true, Check: checkIndependentSegments},
+	{ID: RuleRequiredTags, Optional: true, Check: checkRequiredTags},
+}
+
+// Register добавляет правило. Вызывается при инициализации, до проверок;
+// пустой или повторяющийся идентификатор - ошибка программы.
+func Register(rule Rule) {
+	if rule.ID == "" || rule.Check == nil {
+		panic("conformance: rule must have ID and Check")
+	}
+	if slices.ContainsFunc(registry, func(r Rule) bool { return r.ID == rule.ID }) {
+		panic("conformance: duplicate rule " + rule.ID)
+	}
+	registry = append(registry, rule)
+}
+
+// Rules возвращает зарегистрированные правила
+func Rules() []Rule {
+	return slices.Clone(registry)
+}
+
+// Select возвращает правила по умолчанию (все, кроме Optional), дополненные
+// enable и без disable. Неизвестные идентификаторы - ошибка.
+func Select(enable, disable []string) ([]Rule, error) {
+	var errs []error
+	for _, id := range slices.Concat(enable, disable) {
+		if !slices.ContainsFunc(registry, func(r Rule) bool { return r.ID == id }) {
+			errs = append(errs, fmt.Errorf("unknown rule: %s", id))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	var rules []Rule
+	for _, rule := range registry {
+		enabled := !rule.Optional || slices.Contains(enable, rule.ID)
+		if enabled && !slices.Contains(disable, rule.ID) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// Check возвращает нарушения RFC 8216 в плейлисте по правилам по умолчанию;
+// пустой результат означает соответствие
+func Check(body []byte) []models.ConformanceViolation {
+	rules, _ := Select(nil, nil)
+	return CheckRules(body, rules)
+}
+
+// CheckRules возвращает нарушения плейлиста по правилам rules в порядке строк
+func CheckRules(body []byte, rules []Rule) []models.ConformanceViolation {
+	lines := splitLines(body)
+	if len(lines) == 0 || lines[0].Text != "#EXTM3U" {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.ID == RuleHeader }) {
+			return nil
+		}
+		return []models.ConformanceViolation{{
+			Rule:    RuleHeader,
+			Line:    1,
+			Message: "playlist must start with #EXTM3U",
+		}}
+	}
+
+	p := newPlaylist(lines)
+	var violations []models.ConformanceViolation
+	for _, rule := range rules {
+		rule.Check(p, func(line int, format string, args ...any) {
+			violations = append(violations, models.ConformanceViolation{
+				Rule:    rule.ID,
+				Line:    line,
+				Message: fmt.Sprintf(format, args...),
+			})
+		})
+	}
+	slices.SortStableFunc(violations, func(a, b models.ConformanceViolation) int {
+		return a.Line - b.Line
+	})
+	return violations
+}
+
+// checkVersionTag EXT-X-VERSION допускается не более одного раза и содержит номер версии
+func checkVersionTag(p *Playlist, report Reporter) {
+	seen := false
+	for _, l := range p.Lines {
+		tag, value := splitTag(l.Text)
+		if tag != "#EXT-X-VERSION" {
+			continue
+		}
+		if seen {
+			report(l.Num, "EXT-X-VERSION must appear at most once")
+			continue
+		}
+		seen = true
+
+		if v, err := strconv.Atoi(value); err != nil || v < 1 {
+			report(l.Num, "invalid EXT-X-VERSION value %q", value)
+		}
+	}
+}
+
+// checkVersionGated теги и атрибуты, появившиеся в более поздних версиях протокола,
+// чем объявлена в плейлисте
+func checkVersionGated(p *Playlist, report Reporter) {
+	requireVersion := func(tag string, lineNum, version int) {
+		if p.Version < version {
+			report(lineNum, "%s requires version %d, playlist declares %d", tag[1:], version, p.Version)
+		}
+	}
+
+	var (
+		iFramesOnly bool
+		maps        []int
+	)
+	for _, l := range p.Lines {
+		tag, value := splitTag(l.Text)
+		switch tag {
+		case "#EXTINF":
+			duration, _, _ := strings.Cut(value, ",")
+			if strings.Contains(duration, ".") && p.Version < 3 {
+				report(l.Num, "floating-point EXTINF duration requires version 3, playlist declares %d", p.Version)
+			}
+		case "#EXT-X-BYTERANGE":
+			requireVersion(tag, l.Num, 4)
+		case "#EXT-X-I-FRAMES-ONLY":
+			iFramesOnly = true
+			requireVersion(tag, l.Num, 4)
+		case "#EXT-X-MAP":
+			maps = append(maps, l.Num)
+		case "#EXT-X-KEY":
+			attrs, _, ok := parseAttributes(tag, value)
+			if !ok {
+				continue
+			}
+			if _, ok := attrs["IV"]; ok {
+				requireVersion("#EXT-X-KEY IV attribute", l.Num, 2)
+			}
+			_, hasFormat := attrs["KEYFORMAT"]
+			_, hasVersions := attrs["KEYFORMATVERSIONS"]
+			if hasFormat || hasVersions {
+				requireVersion("#EXT-X-KEY KEYFORMAT attribute", l.Num, 5)
+			}
+		}
+	}
+
+	if !p.IsMedia() {
+		return
+	}
+	// EXT-X-MAP без EXT-X-I-FRAMES-ONLY появился в версии 6
+	mapVersion := 6
+	if iFramesOnly {
+		mapVersion = 5
+	}
+	for _, n := range maps {
+		requireVersion("#EXT-X-MAP", n, mapVersion)
+	}
+}
+
+// checkTargetDuration медиаплейлист содержит ровно один EXT-X-TARGETDURATION,
+// и округленный EXTINF его не превышает
+func checkTargetDuration(p *Playlist, report Reporter) {
+	var (
+		targetDuration = -1
+		targetCount    int
+		extinfs        []Line
+	)
+	for _, l := range p.Lines {
+		tag, value := splitTag(l.Text)
+		switch tag {
+		case "#EXT-X-TARGETDURATION":
+			targetCount++
+			d, err := strconv.Atoi(value)
+			if err != nil || d < 0 {
+				report(l.Num, "invalid EXT-X-TARGETDURATION value %q", value)
+				continue
+			}
+			if targetCount == 1 {
+				targetDuration = d
+			}
+		case "#EXTINF":
+			extinfs = append(extinfs, l)
+		}
+	}
+
+	if !p.IsMedia() {
+		return
+	}
+	switch {
+	case targetCount == 0:
+		report(1, "media playlist must contain EXT-X-TARGETDURATION")
+	case targetCount > 1:
+		report(1, "EXT-X-TARGETDURATION must appear exactly once")
+	}
+	if targetDuration < 0 {
+		return
+	}
+	for _, l := range extinfs {
+		_, value := splitTag(l.Text)
+		durationStr, _, _ := strings.Cut(value, ",")
+		duration, err := strconv.ParseFloat(durationStr, 64)
+		if err != nil {
+			continue
+		}
+		if int(math.Round(duration)) > targetDuration {
+			report(l.Num, "EXTINF duration %s exceeds target duration %d", durationStr, targetDuration)
+		}
+	}
+}
+
+// checkTagPlacement теги медиаплейлиста, допустимые только до первого сегмента
+func checkTagPlacement(p *Playlist, report Reporter) {
+	media, segmentStarted := false, false
+	for _, l := range p.Lines {
+		if !strings.HasPrefix(l.Text, "#") {
+			segmentStarted = segmentStarted || media
+			continue
+		}
+		tag, _ := splitTag(l.Text)
+		if segmentTags[tag] || playlistTags[tag] {
+			media = true
+		}
+		if playlistTags[tag] && segmentStarted {
+			report(l.Num, "%s must appear before the first media segment", tag[1:])
+		}
+	}
+}
+
+// checkMixedTags плейлист не смешивает теги мастер- и медиаплейлиста
+func checkMixedTags(p *Playlist, report Reporter) {
+	if p.FirstMaster > 0 && p.FirstMedia > 0 {
+		report(max(p.FirstMaster, p.FirstMedia), "playlist mixes master and media playlist tags")
+	}
+}
+
+// checkURIPlacement URI следует за EXTINF или EXT-X-STREAM-INF, а они - за URI
+func checkURIPlacement(p *Playlist, report Reporter) {
+	var (
+		pendingURI  string
+		pendingLine int
+	)
+	for _, l := range p.Lines {
+		if !strings.HasPrefix(l.Text, "#") {
+			if pendingURI == "" {
+				report(l.Num, "URI line is not preceded by EXTINF or EXT-X-STREAM-INF")
+			}
+			pendingURI = ""
+			continue
+		}
+		tag, _ := splitTag(l.Text)
+		if tag == "#EXTINF" || tag == "#EXT-X-STREAM-INF" {
+			if pendingURI != "" {
+				report(pendingLine, "%s is not followed by a URI", pendingURI[1:])
+			}
+			pendingURI, pendingLine = tag, l.Num
+		}
+	}
+	if pendingURI != "" {
+		report(pendingLine, "%s is not followed by a URI", pendingURI[1:])
+	}
+}
+
+// attributeTags перебирает теги со списком атрибутов
+func attributeTags(p *Playlist, fn func(l Line, tag, value string)) {
+	for _, l := range p.Lines {
+		if !strings.HasPrefix(l.Text, "#EXT") {
+			continue
+		}
+		tag, value := splitTag(l.Text)
+		if _, ok := requiredAttributes[tag]; ok {
+			fn(l, tag, value)
+		}
+	}
+}
+
+// checkAttributeSyntax синтаксис списков атрибутов
+func checkAttributeSyntax(p *Playlist, report Reporter) {
+	attributeTags(p, func(l Line, tag, value string) {
+		_, problems, _ := parseAttributes(tag, value)
+		for _, problem := range problems {
+			report(l.Num, "%s", problem)
+		}
+	})
+}
+
+// checkRequiredAttributes обязательные атрибуты тегов
+func checkRequiredAttributes(p *Playlist, report Reporter) {
+	attributeTags(p, func(l Line, tag, value string) {
+		attrs, _, ok := parseAttributes(tag, value)
+		if !ok {
+			return
+		}
+		for _, name := range requiredAttributes[tag] {
+			if _, ok := attrs[name]; !ok {
+				report(l.Num, "%s is missing required attribute %s", tag[1:], name)
+			}
+		}
+		if tag != "#EXT-X-KEY" {
+			return
+		}
+		if method := attrs["METHOD"]; method != "" && method != "NONE" {
+			if _, ok := attrs["URI"]; !ok {
+				report(l.Num, "EXT-X-KEY with METHOD=%s is missing required attribute URI", method)
+			}
+		}
+	})
+}
+
+// checkURIScheme URI сегментов, плейлистов и атрибутов URI относительные или http(s).
+// Ключи EXT-X-KEY и EXT-X-SESSION-KEY не проверяются: DRM использует свои схемы (skd://, data:).
+func checkURIScheme(p *Playlist, report Reporter) {
+	check := func(lineNum int, uri string) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			report(lineNum, "invalid URI %q", uri)
+			return
+		}
+		if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+			report(lineNum, "URI %q has unsupported scheme %s", uri, u.Scheme)
+		}
+	}
+
+	for _, l := range p.Lines {
+		if !strings.HasPrefix(l.Text, "#") {
+			check(l.Num, l.Text)
+		}
+	}
+	attributeTags(p, func(l Line, tag, value string) {
+		if tag == "#EXT-X-KEY" || tag == "#EXT-X-SESSION-KEY" {
+			return
+		}
+		attrs, _, ok := parseAttributes(tag, value)
+		if !ok {
+			return
+		}
+		if uri, ok := attrs["URI"]; ok {
+			check(l.Num, uri)
+		}
+	})
+}
+
+// checkIndependentSegments мастер-плейлист объявляет EXT-X-INDEPENDENT-SEGMENTS
+func checkIndependentSegments(p *Playlist, report Reporter) {
+	if !p.IsMaster() {
+		return
+	}
+	for _, l := range p.Lines {
+		if l.Text == "#EXT-X-INDEPENDENT-SEGMENTS" {
+			return
+		}
+	}
+	report(1, "master playlist should contain EXT-X-INDEPENDENT-SEGMENTS")
+}
+
+// checkRequiredTags плейлист объявляет EXT-X-VERSION, а мастер-плейлист содержит варианты
+func checkRequiredTags(p *Playlist, report Reporter) {
+	hasVersion, hasVariants := false, false
+	for _, l := range p.Lines {
+		switch tag, _ := splitTag(l.Text); tag {
+		case "#EXT-X-VERSION":
+			hasVersion = true
+		case "#EXT-X-STREAM-INF":
+			hasVariants = true
+		}
+	}
+	if !hasVersion {
+		report(1, "playlist should contain EXT-X-VERSION")
+	}
+	if p.IsMaster() && !hasVariants {
+		report(1, "master playlist should contain at least one EXT-X-STREAM-INF")
+	}
+}
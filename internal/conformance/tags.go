@@ -0,0 +1,90 @@
+package conformance
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// knownTags теги RFC 8216 и его редакции с Low-Latency HLS; остальные,
+// включая вендорские (#EXT-X-CUE-OUT и т.п.), считаются неизвестными
+var knownTags = map[string]bool{
+	"#EXTM3U":                       true,
+	"#EXT-X-VERSION":                true,
+	"#EXT-X-INDEPENDENT-SEGMENTS":   true,
+	"#EXT-X-START":                  true,
+	"#EXT-X-DEFINE":                 true,
+	"#EXT-X-CONTENT-STEERING":       true,
+	"#EXT-X-PART":                   true,
+	"#EXT-X-PRELOAD-HINT":           true,
+	"#EXT-X-RENDITION-REPORT":       true,
+	"#EXT-X-SKIP":                   true,
+	"#EXT-X-TARGETDURATION":         true,
+	"#EXT-X-MEDIA-SEQUENCE":         true,
+	"#EXT-X-DISCONTINUITY-SEQUENCE": true,
+	"#EXT-X-PLAYLIST-TYPE":          true,
+	"#EXT-X-I-FRAMES-ONLY":          true,
+	"#EXT-X-PART-INF":               true,
+	"#EXT-X-SERVER-CONTROL":         true,
+	"#EXT-X-ENDLIST":                true,
+}
+
+func init() {
+	for tag := range segmentTags {
+		knownTags[tag] = true
+	}
+	for tag := range masterTags {
+		knownTags[tag] = true
+	}
+}
+
+// Scan находит строки, которые m3u8-парсер молча пропускает: неизвестные
+// теги и теги с некорректным списком атрибутов
+func Scan(body []byte) []models.ParseIssue {
+	var issues []models.ParseIssue
+	for _, l := range splitLines(body) {
+		if !strings.HasPrefix(l.Text, "#EXT") {
+			continue
+		}
+
+		tag, value := splitTag(l.Text)
+		if !knownTags[tag] {
+			issues = append(issues, models.ParseIssue{
+				Kind:    models.ParseIssueUnknownTag,
+				Tag:     tag[1:],
+				Line:    l.Num,
+				Message: "unknown tag " + tag[1:],
+			})
+			continue
+		}
+
+		if _, ok := requiredAttributes[tag]; !ok {
+			continue
+		}
+		if _, problems, ok := parseAttributes(tag, value); !ok {
+			issues = append(issues, models.ParseIssue{
+				Kind:    models.ParseIssueMalformedAttributes,
+				Tag:     tag[1:],
+				Line:    l.Num,
+				Message: problems[0],
+			})
+		}
+	}
+	return issues
+}
+
+// UnknownTags возвращает отсортированный список нестандартных тегов плейлиста без повторов
+func UnknownTags(body []byte) []string {
+	var tags []string
+	for _, l := range splitLines(body) {
+		if !strings.HasPrefix(l.Text, "#EXT") {
+			continue
+		}
+		if tag, _ := splitTag(l.Text); !knownTags[tag] {
+			tags = append(tags, tag[1:])
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
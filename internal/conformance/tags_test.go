@@ -0,0 +1,60 @@
+package conformance
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	issues := Scan([]byte(`#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-TARGETDURATION:6
+#EXT-X-CUE-OUT:30
+#EXT-X-KEY:METHOD=AES-128,URI="key.bin
+# обычный комментарий
+#EXTINF:6.0,
+seg1.ts
+#EXT-X-PART:DURATION=1.0,URI="part1.ts"
+#EXT-X-VENDOR-MARKER
+`))
+
+	require.Len(t, issues, 3)
+	assert.Equal(t, models.ParseIssue{
+		Kind:    models.ParseIssueUnknownTag,
+		Tag:     "EXT-X-CUE-OUT",
+		Line:    4,
+		Message: "unknown tag EXT-X-CUE-OUT",
+	}, issues[0])
+	assert.Equal(t, models.ParseIssueMalformedAttributes, issues[1].Kind)
+	assert.Equal(t, "EXT-X-KEY", issues[1].Tag)
+	assert.Equal(t, 5, issues[1].Line)
+	assert.Equal(t, models.ParseIssueUnknownTag, issues[2].Kind)
+	assert.Equal(t, "EXT-X-VENDOR-MARKER", issues[2].Tag)
+}
+
+func TestScan_Clean(t *testing.T) {
+	assert.Empty(t, Scan([]byte(`#EXTM3U
+#EXT-X-INDEPENDENT-SEGMENTS
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",NAME="English",URI="audio.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=1000000,AUDIO="aud"
+video.m3u8
+`)))
+}
+
+func TestUnknownTags(t *testing.T) {
+	tags := UnknownTags([]byte(`#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-CUE-OUT:30
+#EXTINF:6.0,
+seg1.ts
+#EXT-X-CUE-IN
+#EXT-X-CUE-OUT:15
+#EXTINF:6.0,
+seg2.ts
+`))
+	assert.Equal(t, []string{"EXT-X-CUE-IN", "EXT-X-CUE-OUT"}, tags)
+	assert.Empty(t, UnknownTags([]byte("#EXTM3U\n#EXT-X-TARGETDURATION:6\n")))
+}
@@ -0,0 +1,160 @@
+// Package cron разбирает стандартные выражения cron из пяти полей
+// (минута, час, день месяца, месяц, день недели) и вычисляет время срабатывания.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field допустимый диапазон поля выражения
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = [5]field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 и 7 - воскресенье
+}
+
+// maxSearch ограничивает поиск срабатывания для выражений вроде "0 0 31 2 *"
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Schedule разобранное выражение cron. Значения полей хранятся битовыми масками.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// Если ограничены оба поля дня, достаточно совпадения любого (как в Vixie cron)
+	domStar, dowStar bool
+}
+
+// Parse разбирает выражение из пяти полей. Поддерживаются "*", списки,
+// диапазоны и шаги: "*/15 9-18 * * 1-5".
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields", expr)
+	}
+
+	var masks [5]uint64
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		masks[i] = mask
+	}
+
+	// Воскресенье может быть задано как 7
+	dow := masks[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+
+	return &Schedule{
+		minute:  masks[0],
+		hour:    masks[1],
+		dom:     masks[2],
+		month:   masks[3],
+		dow:     dow &^ (1 << 7),
+		domStar: strings.HasPrefix(parts[2], "*"),
+		dowStar: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+func parseField(value string, f field) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseValue(from, f); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseValue(to, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/15" означает "5-max/15"
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, rangePart)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s: value %q out of range [%d, %d]", f.name, value, f.min, f.max)
+	}
+	return n, nil
+}
+
+// Next возвращает ближайшее время срабатывания строго после t
+// (с точностью до минуты, в часовом поясе t). Нулевое время, если срабатываний нет.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hour, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func has(mask uint64, v int) bool {
+	return mask&(1<<uint(v)) != 0
+}
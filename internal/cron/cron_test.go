@@ -0,0 +1,96 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	// Среда, 15 января 2025
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			want: time.Date(2025, time.January, 15, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name: "step",
+			expr: "*/15 * * * *",
+			want: time.Date(2025, time.January, 15, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name: "hour wrap",
+			expr: "5 * * * *",
+			want: time.Date(2025, time.January, 15, 11, 5, 0, 0, time.UTC),
+		},
+		{
+			name: "list and range",
+			expr: "0 3,22 * * *",
+			want: time.Date(2025, time.January, 15, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays only",
+			expr: "0 9 * * 6-7",
+			want: time.Date(2025, time.January, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 0",
+			expr: "30 6 * * 0",
+			want: time.Date(2025, time.January, 19, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 1 * 5",
+			want: time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "month and year wrap",
+			expr: "0 0 1 1 *",
+			want: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never",
+			expr: "0 0 31 2 *",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(from))
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
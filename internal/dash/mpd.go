@@ -0,0 +1,198 @@
+// Package dash разбирает MPD-манифесты MPEG-DASH в объеме, необходимом для
+// сравнения live-края с HLS-версией того же канала
+package dash
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypeDynamic тип live-манифеста
+const TypeDynamic = "dynamic"
+
+// MPD корневой элемент манифеста
+type MPD struct {
+	Type                  string    `xml:"type,attr"`
+	AvailabilityStartTime time.Time `xml:"availabilityStartTime,attr"`
+	PublishTime           time.Time `xml:"publishTime,attr"`
+	Periods               []Period  `xml:"Period"`
+}
+
+type Period struct {
+	ID             string          `xml:"id,attr"`
+	Start          string          `xml:"start,attr"`
+	AdaptationSets []AdaptationSet `xml:"AdaptationSet"`
+}
+
+type AdaptationSet struct {
+	ContentType     string           `xml:"contentType,attr"`
+	MimeType        string           `xml:"mimeType,attr"`
+	SegmentTemplate *SegmentTemplate `xml:"SegmentTemplate"`
+	Representations []Representation `xml:"Representation"`
+}
+
+type Representation struct {
+	ID              string           `xml:"id,attr"`
+	Bandwidth       uint64           `xml:"bandwidth,attr"`
+	SegmentTemplate *SegmentTemplate `xml:"SegmentTemplate"`
+}
+
+type SegmentTemplate struct {
+	Timescale              uint64           `xml:"timescale,attr"`
+	PresentationTimeOffset uint64           `xml:"presentationTimeOffset,attr"`
+	Timeline               *SegmentTimeline `xml:"SegmentTimeline"`
+}
+
+type SegmentTimeline struct {
+	Segments []TimelineSegment `xml:"S"`
+}
+
+// TimelineSegment элемент S: сегмент длительностью D, начинающийся в T
+// (или сразу после предыдущего) и повторенный еще R раз
+type TimelineSegment struct {
+	T *uint64 `xml:"t,attr"`
+	D uint64  `xml:"d,attr"`
+	R int     `xml:"r,attr"`
+}
+
+// Parse разбирает MPD-манифест
+func Parse(data []byte) (*MPD, error) {
+	var mpd MPD
+	if err := xml.Unmarshal(data, &mpd); err != nil {
+		return nil, fmt.Errorf("parse mpd: %w", err)
+	}
+	if len(mpd.Periods) == 0 {
+		return nil, fmt.Errorf("mpd has no periods")
+	}
+	return &mpd, nil
+}
+
+// LiveEdge возвращает время конца последнего доступного сегмента по SegmentTimeline.
+// Для нескольких адаптаций берется наиболее отстающая, как и для вариантов HLS.
+// Для статических манифестов и манифестов без SegmentTimeline возвращает false.
+func (m *MPD) LiveEdge() (time.Time, bool) {
+	if m.Type != TypeDynamic || m.AvailabilityStartTime.IsZero() {
+		return time.Time{}, false
+	}
+
+	// Сегменты доступны только в последнем периоде
+	period := m.Periods[len(m.Periods)-1]
+	start, err := ParseDuration(period.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var (
+		edge  time.Time
+		found bool
+	)
+	for _, set := range period.AdaptationSets {
+		for _, tmpl := range set.templates() {
+			end, ok := tmpl.end()
+			if !ok {
+				continue
+			}
+			t := m.AvailabilityStartTime.Add(start + end)
+			if !found || t.Before(edge) {
+				edge, found = t, true
+			}
+		}
+	}
+	return edge, found
+}
+
+// templates возвращает шаблоны сегментов адаптации: собственный и шаблоны представлений
+func (a AdaptationSet) templates() []*SegmentTemplate {
+	var templates []*SegmentTemplate
+	if a.SegmentTemplate != nil {
+		templates = append(templates, a.SegmentTemplate)
+	}
+	for _, r := range a.Representations {
+		if r.SegmentTemplate != nil {
+			templates = append(templates, r.SegmentTemplate)
+		}
+	}
+	return templates
+}
+
+// end возвращает время конца последнего сегмента шкалы относительно начала периода.
+// Отрицательный R (повтор до следующего элемента) считается одним сегментом.
+func (s *SegmentTemplate) end() (time.Duration, bool) {
+	if s.Timeline == nil || len(s.Timeline.Segments) == 0 {
+		return 0, false
+	}
+
+	timescale := s.Timescale
+	if timescale == 0 {
+		timescale = 1
+	}
+
+	var t uint64
+	for _, seg := range s.Timeline.Segments {
+		if seg.T != nil {
+			t = *seg.T
+		}
+		t += seg.D * uint64(max(seg.R, 0)+1)
+	}
+	if t < s.PresentationTimeOffset {
+		return 0, false
+	}
+
+	ticks := t - s.PresentationTimeOffset
+	return time.Duration(ticks/timescale)*time.Second +
+		time.Duration(ticks%timescale)*time.Second/time.Duration(timescale), true
+}
+
+// ParseDuration разбирает длительность xs:duration (например, PT1H2M3.5S).
+// Пустая строка - нулевая длительность. Годы и месяцы не поддерживаются.
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	rest, ok := strings.CutPrefix(s, "P")
+	if !ok {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+
+	var (
+		total  time.Duration
+		inTime bool
+	)
+	for rest != "" {
+		if rest[0] == 'T' {
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+
+		i := strings.IndexAny(rest, "DHMS")
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		value, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+
+		var unit time.Duration
+		switch {
+		case rest[i] == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case rest[i] == 'H' && inTime:
+			unit = time.Hour
+		case rest[i] == 'M' && inTime:
+			unit = time.Minute
+		case rest[i] == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration: %s", s)
+		}
+		total += time.Duration(value * float64(unit))
+		rest = rest[i+1:]
+	}
+	return total, nil
+}
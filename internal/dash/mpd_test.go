@@ -0,0 +1,110 @@
+package dash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const liveMPD = `<?xml version="1.0" encoding="UTF-8"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" type="dynamic"
+     availabilityStartTime="2025-01-01T00:00:00Z" publishTime="2025-01-01T01:00:00Z">
+  <Period id="p0" start="PT0S">
+    <AdaptationSet contentType="video" mimeType="video/mp4">
+      <SegmentTemplate timescale="90000" media="v_$Time$.m4s">
+        <SegmentTimeline>
+          <S t="324000000" d="360000" r="2"/>
+          <S d="180000"/>
+        </SegmentTimeline>
+      </SegmentTemplate>
+      <Representation id="v1" bandwidth="2000000"/>
+    </AdaptationSet>
+    <AdaptationSet contentType="audio" mimeType="audio/mp4">
+      <Representation id="a1" bandwidth="128000">
+        <SegmentTemplate timescale="48000" presentationTimeOffset="48000">
+          <SegmentTimeline>
+            <S t="172848000" d="192000"/>
+          </SegmentTimeline>
+        </SegmentTemplate>
+      </Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`
+
+func TestMPD_LiveEdge(t *testing.T) {
+	mpd, err := Parse([]byte(liveMPD))
+	require.NoError(t, err)
+	assert.Equal(t, TypeDynamic, mpd.Type)
+	require.Len(t, mpd.Periods, 1)
+	require.Len(t, mpd.Periods[0].AdaptationSets, 2)
+
+	// Видео: 3600s + 3*4s + 2s = 3614s, аудио: 3601s + 4s - 1s (offset) = 3604s
+	edge, ok := mpd.LiveEdge()
+	require.True(t, ok)
+	ast := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, ast.Add(3604*time.Second), edge)
+}
+
+func TestMPD_LiveEdge_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		mpd  string
+	}{
+		{
+			name: "static",
+			mpd: `<MPD type="static"><Period><AdaptationSet><SegmentTemplate timescale="1">
+<SegmentTimeline><S t="0" d="4"/></SegmentTimeline></SegmentTemplate></AdaptationSet></Period></MPD>`,
+		},
+		{
+			name: "number template",
+			mpd: `<MPD type="dynamic" availabilityStartTime="2025-01-01T00:00:00Z"><Period>
+<AdaptationSet><SegmentTemplate timescale="1" duration="4"/></AdaptationSet></Period></MPD>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mpd, err := Parse([]byte(tt.mpd))
+			require.NoError(t, err)
+			_, ok := mpd.LiveEdge()
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte("#EXTM3U"))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`<MPD type="dynamic"></MPD>`))
+	assert.ErrorContains(t, err, "no periods")
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "PT0S", want: 0},
+		{in: "PT1H2M3.5S", want: time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{in: "P1DT1S", want: 24*time.Hour + time.Second},
+		{in: "1H", wantErr: true},
+		{in: "PT1D", wantErr: true},
+		{in: "P1H", wantErr: true},
+		{in: "PTxS", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package eventlog пишет результаты проверок в файл JSON Lines с ротацией
+// по размеру и времени для загрузки в SIEM
+package eventlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.ResultRecorder = (*Writer)(nil)
+
+// backupTimeFormat время ротации в имени ротированного файла
+const backupTimeFormat = "20060102T150405.000Z"
+
+// Writer журнал результатов проверок: одна строка JSON на проверку
+type Writer struct {
+	cfg models.EventLogConfig
+	now func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+	// opened время начала текущего файла для ротации по времени
+	opened time.Time
+}
+
+// Open открывает журнал для дозаписи. Существующий файл продолжается, а для ротации
+// по времени считается начатым в момент последнего изменения.
+func Open(cfg models.EventLogConfig) (*Writer, error) {
+	w := &Writer{cfg: cfg, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.opened = w.now()
+	if w.size > 0 {
+		w.opened = info.ModTime()
+	}
+	return nil
+}
+
+// Record записывает результат проверки строкой JSON
+func (w *Writer) Record(result *models.CheckResult) error {
+	if result == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode check result: %w", err)
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return errors.New("event log is closed")
+	}
+	if w.rotationDue(len(line)) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.Write(line)
+	w.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return nil
+}
+
+// rotationDue сообщает, что строку нужно писать в новый файл: текущий превысит
+// max_size или начат в прошлом периоде rotate_interval
+func (w *Writer) rotationDue(n int) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.cfg.MaxSize > 0 && w.size+int64(n) > w.cfg.MaxSize {
+		return true
+	}
+	if interval := w.cfg.RotateInterval; interval > 0 {
+		return !w.now().Truncate(interval).Equal(w.opened.Truncate(interval))
+	}
+	return false
+}
+
+// rotate переименовывает текущий файл, добавляя к имени время ротации,
+// открывает новый и удаляет ротированные файлы сверх max_backups
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate event log: %w", err)
+	}
+	w.file = nil
+
+	if err := os.Rename(w.cfg.Path, w.backupName(w.now())); err != nil {
+		return fmt.Errorf("failed to rotate event log: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	return w.removeOldBackups()
+}
+
+// backupName возвращает имя ротированного файла: checks.jsonl -> checks-<время>.jsonl
+func (w *Writer) backupName(t time.Time) string {
+	ext := filepath.Ext(w.cfg.Path)
+	base := strings.TrimSuffix(w.cfg.Path, ext)
+	return base + "-" + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// backups возвращает ротированные файлы журнала от старых к новым
+func (w *Writer) backups() ([]string, error) {
+	ext := filepath.Ext(w.cfg.Path)
+	pattern := strings.TrimSuffix(w.cfg.Path, ext) + "-*" + ext
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	// Время в имени упорядочено лексикографически
+	slices.Sort(matches)
+	return matches, nil
+}
+
+func (w *Writer) removeOldBackups() error {
+	if w.cfg.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := w.backups()
+	if err != nil {
+		return fmt.Errorf("failed to list event log backups: %w", err)
+	}
+	var errs []error
+	for len(backups) > w.cfg.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			errs = append(errs, err)
+		}
+		backups = backups[1:]
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to remove event log backups: %w", err)
+	}
+	return nil
+}
+
+// Close закрывает журнал
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
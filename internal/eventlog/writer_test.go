@@ -0,0 +1,109 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readLines возвращает результаты из файла журнала
+func readLines(t *testing.T, path string) []models.CheckResult {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var results []models.CheckResult
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result models.CheckResult
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		results = append(results, result)
+	}
+	require.NoError(t, scanner.Err())
+	return results
+}
+
+func TestWriter_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checks.jsonl")
+	w, err := Open(models.EventLogConfig{Path: path})
+	require.NoError(t, err)
+
+	require.NoError(t, w.Record(nil))
+	require.NoError(t, w.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "a1", Success: true}))
+	require.NoError(t, w.Record(&models.CheckResult{
+		StreamName: "stream_a",
+		CheckID:    "a2",
+		Error:      &models.CheckError{Type: models.ErrPlaylistDownload, Message: "unexpected status code: 503"},
+	}))
+	require.NoError(t, w.Close())
+
+	// Повторное открытие продолжает файл
+	w, err = Open(models.EventLogConfig{Path: path})
+	require.NoError(t, err)
+	require.NoError(t, w.Record(&models.CheckResult{StreamName: "stream_b", CheckID: "b1"}))
+	require.NoError(t, w.Close())
+	assert.Error(t, w.Record(&models.CheckResult{StreamName: "stream_b"}))
+
+	results := readLines(t, path)
+	require.Len(t, results, 3)
+	assert.Equal(t, "a1", results[0].CheckID)
+	require.NotNil(t, results[1].Error)
+	assert.Equal(t, models.ErrPlaylistDownload, results[1].Error.Type)
+	assert.Equal(t, "stream_b", results[2].StreamName)
+}
+
+func TestWriter_RotateSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checks.jsonl")
+	w, err := Open(models.EventLogConfig{Path: path, MaxSize: 1, MaxBackups: 2})
+	require.NoError(t, err)
+	defer w.Close()
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	// Каждая строка больше max_size: файл ротируется перед каждой записью, кроме первой
+	for i := range 4 {
+		now = now.Add(time.Second)
+		require.NoError(t, w.Record(&models.CheckResult{StreamName: "stream_a", CheckID: string(rune('a' + i))}))
+	}
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "checks-20261015T100003.000Z.jsonl"),
+		filepath.Join(dir, "checks-20261015T100004.000Z.jsonl"),
+	}, backups, "oldest backups should be removed")
+	assert.Equal(t, "c", readLines(t, backups[1])[0].CheckID)
+	assert.Equal(t, "d", readLines(t, path)[0].CheckID)
+}
+
+func TestWriter_RotateInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checks.jsonl")
+	w, err := Open(models.EventLogConfig{Path: path, RotateInterval: 24 * time.Hour})
+	require.NoError(t, err)
+	defer w.Close()
+	now := time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	w.opened = now
+
+	require.NoError(t, w.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "a1"}))
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, w.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "a2"}))
+	assert.Len(t, readLines(t, path), 2)
+
+	// Наступили новые сутки UTC
+	now = now.Add(time.Hour)
+	require.NoError(t, w.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "a3"}))
+	assert.Len(t, readLines(t, path), 1)
+	assert.FileExists(t, filepath.Join(dir, "checks-20261016T003000.000Z.jsonl"))
+}
@@ -0,0 +1,80 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.HTTPClient = (*Client)(nil)
+
+// Client HTTP-клиент, внедряющий сбои перед обращением к источнику
+type Client struct {
+	models.HTTPClient
+	injector models.FaultInjector
+}
+
+// Wrap оборачивает клиент внедрением сбоев
+func Wrap(client models.HTTPClient, injector models.FaultInjector) *Client {
+	return &Client{
+		HTTPClient: client,
+		injector:   injector,
+	}
+}
+
+func (c *Client) GetPlaylist(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	return c.get(ctx, models.FaultStagePlaylist, url, c.HTTPClient.GetPlaylist)
+}
+
+func (c *Client) GetKey(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	return c.get(ctx, models.FaultStageKey, url, c.HTTPClient.GetKey)
+}
+
+// get внедряет сбой этапа stage перед загрузкой плейлиста или ключа
+func (c *Client) get(
+	ctx context.Context,
+	stage models.FaultStage,
+	url string,
+	fetch func(ctx context.Context, url string) (*models.PlaylistResponse, error),
+) (*models.PlaylistResponse, error) {
+	start := time.Now()
+	if err := c.injector.Inject(ctx, stage, url); err != nil {
+		var fault *models.InjectedFault
+		if errors.As(err, &fault) && fault.StatusCode != 0 {
+			return &models.PlaylistResponse{
+				StatusCode: fault.StatusCode,
+				Duration:   time.Since(start),
+			}, fmt.Errorf("unexpected status code: %d", fault.StatusCode)
+		}
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	resp, err := fetch(ctx, url)
+	if resp != nil {
+		resp.Duration = time.Since(start)
+	}
+	return resp, err
+}
+
+func (c *Client) GetSegment(ctx context.Context, url string, validate bool) (*models.SegmentResponse, error) {
+	start := time.Now()
+	if err := c.injector.Inject(ctx, models.FaultStageSegment, url); err != nil {
+		var fault *models.InjectedFault
+		if errors.As(err, &fault) && fault.StatusCode != 0 {
+			return &models.SegmentResponse{
+				StatusCode: fault.StatusCode,
+				Duration:   time.Since(start),
+			}, fmt.Errorf("unexpected status code: %d", fault.StatusCode)
+		}
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.GetSegment(ctx, url, validate)
+	if resp != nil {
+		resp.Duration = time.Since(start)
+	}
+	return resp, err
+}
@@ -0,0 +1,138 @@
+package faults
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/checker"
+	client "github.com/iudanet/hls_exporter/internal/http"
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/testorigin"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFaultyChecker(t *testing.T, rules ...models.FaultRule) (*checker.StreamChecker, string) {
+	t.Helper()
+	return newFaultyCheckerWithOrigin(t, testorigin.Config{}, rules...)
+}
+
+// newFaultyCheckerWithOrigin как newFaultyChecker, но с заданной конфигурацией источника
+func newFaultyCheckerWithOrigin(
+	t *testing.T,
+	cfg testorigin.Config,
+	rules ...models.FaultRule,
+) (*checker.StreamChecker, string) {
+	t.Helper()
+
+	origin := testorigin.New(cfg)
+	srv := httptest.NewServer(origin)
+	t.Cleanup(srv.Close)
+
+	httpClient := client.NewClient(models.HTTPConfig{Timeout: 5 * time.Second, MaxIdleConns: 10})
+	t.Cleanup(func() { _ = httpClient.Close() })
+
+	c := checker.NewStreamChecker(
+		Wrap(httpClient, NewInjector(rules...)),
+		checker.NewHLSValidator(),
+		metrics.NewCollector(prometheus.NewRegistry()),
+		2,
+	)
+	require.NoError(t, c.Start())
+	t.Cleanup(func() { _ = c.Stop() })
+
+	return c, srv.URL + origin.MasterURL()
+}
+
+func faultStream(url string) models.StreamConfig {
+	return models.StreamConfig{
+		Name:            "chaos",
+		URL:             url,
+		CheckMode:       models.CheckModeFirstLast,
+		Interval:        time.Minute,
+		Timeout:         5 * time.Second,
+		ValidateContent: true,
+	}
+}
+
+func TestClient_NoRules(t *testing.T) {
+	c, url := newFaultyChecker(t)
+
+	result, err := c.Check(context.Background(), faultStream(url))
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+}
+
+func TestClient_PlaylistStatus(t *testing.T) {
+	c, url := newFaultyChecker(t, models.FaultRule{
+		Stage:       models.FaultStagePlaylist,
+		URLContains: "master",
+		StatusCode:  http.StatusServiceUnavailable,
+	})
+
+	result, err := c.Check(context.Background(), faultStream(url))
+	require.Error(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, models.ErrPlaylistDownload, result.Error.Type)
+}
+
+func TestClient_SegmentError(t *testing.T) {
+	c, url := newFaultyChecker(t, models.FaultRule{
+		Stage:       models.FaultStageSegment,
+		URLContains: "360p",
+		Error:       "connection reset",
+	})
+
+	result, _ := c.Check(context.Background(), faultStream(url))
+	require.NotNil(t, result)
+	assert.False(t, result.Success)
+	assert.Equal(t, 2, result.Segments.Failed, "only the 360p segments must fail")
+}
+
+func TestClient_DelayCountedInDuration(t *testing.T) {
+	injected := models.FaultRule{Stage: models.FaultStagePlaylist, Delay: 30 * time.Millisecond}
+	httpClient := client.NewClient(models.HTTPConfig{Timeout: time.Second})
+	defer httpClient.Close()
+
+	srv := httptest.NewServer(testorigin.New(testorigin.Config{}))
+	defer srv.Close()
+
+	resp, err := Wrap(httpClient, NewInjector(injected)).GetPlaylist(context.Background(), srv.URL+"/master.m3u8")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, resp.Duration, 30*time.Millisecond)
+}
+
+func TestClient_KeyError(t *testing.T) {
+	encrypted := testorigin.Config{
+		Variants: []testorigin.Variant{{Name: "enc", Bandwidth: 500_000, Encrypted: true}},
+	}
+
+	// Сбой этапа key проваливает расшифровку сегментов, плейлисты загружаются
+	c, url := newFaultyCheckerWithOrigin(t, encrypted, models.FaultRule{
+		Stage:      models.FaultStageKey,
+		StatusCode: http.StatusForbidden,
+	})
+	result, err := c.Check(context.Background(), faultStream(url))
+	require.Error(t, err)
+	require.NotEmpty(t, result.Segments.Details)
+	for _, seg := range result.Segments.Details {
+		assert.False(t, seg.Success)
+		require.NotNil(t, seg.Error)
+		assert.Equal(t, models.ErrKeyFetch, seg.Error.Type)
+	}
+
+	// Сбой этапа playlist не затрагивает загрузку ключей
+	c, url = newFaultyCheckerWithOrigin(t, encrypted, models.FaultRule{
+		Stage:       models.FaultStagePlaylist,
+		URLContains: "key.bin",
+		StatusCode:  http.StatusForbidden,
+	})
+	result, err = c.Check(context.Background(), faultStream(url))
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+}
@@ -0,0 +1,6 @@
+//go:build !debug
+
+package faults
+
+// Enabled внедрение сбоев недоступно в обычной сборке
+const Enabled = false
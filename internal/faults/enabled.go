@@ -0,0 +1,6 @@
+//go:build debug
+
+package faults
+
+// Enabled внедрение сбоев доступно в debug-сборке (go build -tags debug)
+const Enabled = true
@@ -0,0 +1,83 @@
+// Package faults внедряет задержки и ошибки на этапах загрузки плейлистов,
+// сегментов и ключей для хаос-тестирования чекера.
+package faults
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.FaultInjector = (*Injector)(nil)
+
+// Injector применяет правила внедрения сбоев
+type Injector struct {
+	mu     sync.RWMutex
+	rules  []models.FaultRule
+	random func() float64
+}
+
+func NewInjector(rules ...models.FaultRule) *Injector {
+	return &Injector{
+		rules:  append([]models.FaultRule(nil), rules...),
+		random: rand.Float64, // #nosec G404 -- вероятность срабатывания, не криптография
+	}
+}
+
+// SetRules заменяет набор правил
+func (i *Injector) SetRules(rules []models.FaultRule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = append([]models.FaultRule(nil), rules...)
+}
+
+// Inject применяет первое подходящее правило: выдерживает задержку и
+// возвращает внедренную ошибку. Правило только с задержкой ошибку не возвращает.
+func (i *Injector) Inject(ctx context.Context, stage models.FaultStage, url string) error {
+	rule, ok := i.match(stage, url)
+	if !ok {
+		return nil
+	}
+
+	if rule.Delay > 0 {
+		timer := time.NewTimer(rule.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if rule.StatusCode == 0 && rule.Error == "" {
+		return nil
+	}
+	return &models.InjectedFault{
+		Stage:      stage,
+		StatusCode: rule.StatusCode,
+		Message:    rule.Error,
+	}
+}
+
+func (i *Injector) match(stage models.FaultStage, url string) (models.FaultRule, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, rule := range i.rules {
+		if rule.Stage != stage {
+			continue
+		}
+		if rule.URLContains != "" && !strings.Contains(url, rule.URLContains) {
+			continue
+		}
+		if rule.Probability > 0 && i.random() >= rule.Probability {
+			continue
+		}
+		return rule, true
+	}
+	return models.FaultRule{}, false
+}
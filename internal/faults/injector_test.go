@@ -0,0 +1,60 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjector_Inject(t *testing.T) {
+	injector := NewInjector(
+		models.FaultRule{Stage: models.FaultStagePlaylist, URLContains: "broken", StatusCode: 503},
+		models.FaultRule{Stage: models.FaultStageSegment, Error: "connection reset"},
+		models.FaultRule{Stage: models.FaultStagePlaylist, URLContains: "slow", Delay: 20 * time.Millisecond},
+	)
+	ctx := context.Background()
+
+	assert.NoError(t, injector.Inject(ctx, models.FaultStagePlaylist, "http://example.com/ok.m3u8"))
+
+	var fault *models.InjectedFault
+	err := injector.Inject(ctx, models.FaultStagePlaylist, "http://example.com/broken.m3u8")
+	require.True(t, errors.As(err, &fault))
+	assert.Equal(t, 503, fault.StatusCode)
+
+	err = injector.Inject(ctx, models.FaultStageSegment, "http://example.com/seg.ts")
+	require.True(t, errors.As(err, &fault))
+	assert.Equal(t, "injected segment fault: connection reset", err.Error())
+
+	// Правило только с задержкой замедляет загрузку без ошибки
+	start := time.Now()
+	assert.NoError(t, injector.Inject(ctx, models.FaultStagePlaylist, "http://example.com/slow.m3u8"))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	injector.SetRules(nil)
+	assert.NoError(t, injector.Inject(ctx, models.FaultStageSegment, "http://example.com/seg.ts"))
+}
+
+func TestInjector_DelayHonorsContext(t *testing.T) {
+	injector := NewInjector(models.FaultRule{Stage: models.FaultStagePlaylist, Delay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := injector.Inject(ctx, models.FaultStagePlaylist, "http://example.com/index.m3u8")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestInjector_Probability(t *testing.T) {
+	injector := NewInjector(models.FaultRule{Stage: models.FaultStageSegment, StatusCode: 500, Probability: 0.5})
+
+	injector.random = func() float64 { return 0.7 }
+	assert.NoError(t, injector.Inject(context.Background(), models.FaultStageSegment, "seg.ts"))
+
+	injector.random = func() float64 { return 0.2 }
+	assert.Error(t, injector.Inject(context.Background(), models.FaultStageSegment, "seg.ts"))
+}
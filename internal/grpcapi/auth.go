@@ -0,0 +1,122 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/internal/labels"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiToken токен доступа с разобранным селектором видимых стримов
+type apiToken struct {
+	name     string
+	token    []byte
+	selector labels.Selector
+	admin    bool
+}
+
+type tokenKey struct{}
+
+// newTokens разбирает токены из конфигурации так же, как HTTP API
+func newTokens(cfgs []models.APIToken, logger *zap.Logger) []apiToken {
+	tokens := make([]apiToken, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		selector, err := labels.ParseSelector(cfg.Selector, nil)
+		if err != nil {
+			logger.Error("Invalid API token selector", zap.String("token", cfg.Name), zap.Error(err))
+			continue
+		}
+		tokens = append(tokens, apiToken{
+			name:     cfg.Name,
+			token:    []byte(cfg.Token),
+			selector: selector,
+			admin:    cfg.Admin,
+		})
+	}
+	return tokens
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+}
+
+// authStream передает обработчику контекст с токеном вызова
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate проверяет метаданные authorization вызова. При настроенных токенах
+// требуется токен (Bearer), иначе при настроенных пользователях - Basic-авторизация.
+func (s *Server) authenticate(ctx context.Context) (context.Context, error) {
+	if !s.auth && s.basic == nil {
+		return ctx, nil
+	}
+	var value string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			value = values[0]
+		}
+	}
+
+	if s.auth {
+		bearer, ok := strings.CutPrefix(value, "Bearer ")
+		if ok && bearer != "" {
+			for i := range s.tokens {
+				if subtle.ConstantTimeCompare([]byte(bearer), s.tokens[i].token) == 1 {
+					return context.WithValue(ctx, tokenKey{}, &s.tokens[i]), nil
+				}
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing API token")
+	}
+
+	user, password, ok := parseBasicAuth(value)
+	if !ok || !s.basic.Verify(user, password) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing credentials")
+	}
+	return ctx, nil
+}
+
+// parseBasicAuth разбирает значение "Basic base64(user:password)"
+func parseBasicAuth(value string) (string, string, bool) {
+	encoded, ok := strings.CutPrefix(value, "Basic ")
+	if !ok {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
+
+// visible сообщает, что стрим входит в область видимости токена вызова
+func (s *Server) visible(ctx context.Context, stream models.StreamConfig) bool {
+	tok, ok := ctx.Value(tokenKey{}).(*apiToken)
+	return !ok || tok.selector.Matches(labels.StreamLabels(stream))
+}
@@ -0,0 +1,133 @@
+package grpcapi
+
+import (
+	"time"
+
+	pb "github.com/iudanet/hls_exporter/pkg/api/hlsexporterv1"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Нулевые длительности и моменты времени не передаются, как omitempty в JSON API
+
+func duration(d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	return durationpb.New(d)
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func checkResult(r *models.CheckResult) *pb.CheckResult {
+	out := &pb.CheckResult{
+		CheckId:    r.CheckID,
+		Success:    r.Success,
+		StreamName: r.StreamName,
+		StreamStatus: &pb.StreamStatus{
+			IsLive:        r.StreamStatus.IsLive,
+			VariantsCount: int32(r.StreamStatus.VariantsCount),
+			SegmentsCount: int32(r.StreamStatus.SegmentsCount),
+			TotalDuration: r.StreamStatus.TotalDuration,
+			LastModified:  timestamp(r.StreamStatus.LastModified),
+		},
+		Segments: &pb.SegmentResults{
+			Checked: int32(r.Segments.Checked),
+			Failed:  int32(r.Segments.Failed),
+			Total:   int32(r.Segments.Total),
+			Listed:  int32(r.Segments.Listed),
+		},
+		Duration:               duration(r.Duration),
+		Timestamp:              timestamp(r.Timestamp),
+		Error:                  checkError(r.Error),
+		BytesDownloaded:        r.BytesDownloaded,
+		BudgetExceeded:         r.BudgetExceeded,
+		LiveEdgeLatencySeconds: r.LiveEdgeLatency,
+		Stale:                  r.Stale,
+		UnknownTags:            r.UnknownTags,
+		BitrateBps:             r.Bitrate,
+		CcErrors:               int32(r.CCErrors),
+		PcrMaxInterval:         duration(r.PCRMaxInterval),
+		PcrJitter:              duration(r.PCRJitter),
+		NullPacketRatio:        r.NullPacketRatio,
+		DeepCheck:              r.DeepCheck,
+		Warnings:               r.Warnings,
+	}
+	for _, seg := range r.Segments.Details {
+		out.Segments.Details = append(out.Segments.Details, &pb.SegmentCheck{
+			Url:         seg.URL,
+			Success:     seg.Success,
+			Duration:    duration(seg.Duration),
+			Bytes:       seg.Bytes,
+			Size:        seg.Size,
+			CcErrors:    int32(seg.CCErrors),
+			TsPackets:   int32(seg.Packets),
+			NullPackets: int32(seg.NullPackets),
+		})
+	}
+	for _, rendition := range r.Renditions {
+		out.Renditions = append(out.Renditions, &pb.RenditionCheck{
+			Type:     rendition.Type,
+			GroupId:  rendition.GroupID,
+			Name:     rendition.Name,
+			Language: rendition.Language,
+			Url:      rendition.URL,
+			Success:  rendition.Success,
+			Error:    checkError(rendition.Error),
+		})
+	}
+	for _, variant := range r.Variants {
+		out.Variants = append(out.Variants, &pb.VariantBitrate{
+			Url:                variant.URL,
+			Bandwidth:          variant.Bandwidth,
+			Resolution:         variant.Resolution,
+			MeasuredBitrateBps: variant.MeasuredBitrate,
+			DeviationRatio:     variant.DeviationRatio,
+			SizeAnomaly:        variant.SizeAnomaly,
+		})
+	}
+	if r.DASH != nil {
+		out.Dash = &pb.DASHCheck{
+			Url:                       r.DASH.URL,
+			Success:                   r.DASH.Success,
+			Error:                     checkError(r.DASH.Error),
+			LiveEdgeLatencySeconds:    r.DASH.LiveEdgeLatency,
+			LiveEdgeDivergenceSeconds: r.DASH.LiveEdgeDivergence,
+		}
+	}
+	return out
+}
+
+func checkError(e *models.CheckError) *pb.CheckError {
+	if e == nil {
+		return nil
+	}
+	return &pb.CheckError{
+		Type:       string(e.Type),
+		Message:    e.Message,
+		StatusCode: int32(e.StatusCode),
+		Retryable:  e.Retryable,
+		RetryAfter: duration(e.RetryAfter),
+	}
+}
+
+func historyRecord(r *models.HistoryRecord) *pb.HistoryRecord {
+	return &pb.HistoryRecord{
+		Stream:          r.Stream,
+		CheckId:         r.CheckID,
+		Timestamp:       timestamp(r.Timestamp),
+		Duration:        duration(r.Duration),
+		Success:         r.Success,
+		ErrorType:       string(r.ErrorType),
+		ErrorMessage:    r.ErrorMessage,
+		StatusCode:      int32(r.StatusCode),
+		SegmentsChecked: int32(r.SegmentsChecked),
+		SegmentsFailed:  int32(r.SegmentsFailed),
+	}
+}
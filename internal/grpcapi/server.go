@@ -0,0 +1,239 @@
+// Package grpcapi реализует gRPC-сервис StatusService (api/proto/hlsexporter/v1/status.proto)
+// поверх планировщика, хранилища результатов и истории проверок
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/webauth"
+	pb "github.com/iudanet/hls_exporter/pkg/api/hlsexporterv1"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// checkNowGrace запас ожидания CheckNow сверх таймаутов проверок
+const checkNowGrace = 10 * time.Second
+
+// Dependencies зависимости gRPC-сервиса
+type Dependencies struct {
+	Manager models.StreamManager
+	Results models.ResultStore
+	// Feed источник новых результатов для CheckNow и WatchResults
+	Feed models.ResultFeed
+	// History история проверок (опционально)
+	History models.HistoryStore
+	// Tokens токены доступа с областью видимости стримов (пусто - без токенов)
+	Tokens []models.APIToken
+	// BasicAuth проверка Basic-авторизации, если токены не заданы (опционально)
+	BasicAuth *webauth.BasicAuth
+	Logger    *zap.Logger
+	// Admin разрешает CheckNow
+	Admin bool
+}
+
+// Server реализация StatusService
+type Server struct {
+	pb.UnimplementedStatusServiceServer
+
+	manager models.StreamManager
+	results models.ResultStore
+	feed    models.ResultFeed
+	history models.HistoryStore
+	basic   *webauth.BasicAuth
+	logger  *zap.Logger
+	admin   bool
+	// auth требует токен для всех вызовов
+	auth   bool
+	tokens []apiToken
+}
+
+func NewServer(deps Dependencies) *Server {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return &Server{
+		manager: deps.Manager,
+		results: deps.Results,
+		feed:    deps.Feed,
+		history: deps.History,
+		basic:   deps.BasicAuth,
+		logger:  logger,
+		admin:   deps.Admin,
+		auth:    len(deps.Tokens) > 0,
+		tokens:  newTokens(deps.Tokens, logger),
+	}
+}
+
+// GRPCServer создает gRPC-сервер с авторизацией вызовов и зарегистрированным сервисом
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamAuth),
+	)
+	gs := grpc.NewServer(opts...)
+	pb.RegisterStatusServiceServer(gs, s)
+	return gs
+}
+
+// ListStreams возвращает видимые стримы с последними результатами проверок
+func (s *Server) ListStreams(ctx context.Context, _ *pb.ListStreamsRequest) (*pb.ListStreamsResponse, error) {
+	resp := &pb.ListStreamsResponse{}
+	for _, stream := range s.manager.Streams() {
+		if s.visible(ctx, stream) {
+			resp.Streams = append(resp.Streams, s.streamState(stream))
+		}
+	}
+	return resp, nil
+}
+
+// GetStream возвращает параметры и последний результат стрима
+func (s *Server) GetStream(ctx context.Context, req *pb.GetStreamRequest) (*pb.StreamState, error) {
+	stream, err := s.stream(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return s.streamState(stream), nil
+}
+
+// GetHistory возвращает историю проверок стрима, новые записи первыми
+func (s *Server) GetHistory(ctx context.Context, req *pb.GetHistoryRequest) (*pb.GetHistoryResponse, error) {
+	if s.history == nil {
+		return nil, status.Error(codes.Unimplemented, "check history is disabled")
+	}
+	if _, err := s.stream(ctx, req.GetName()); err != nil {
+		return nil, err
+	}
+
+	q := models.HistoryQuery{
+		Stream:     req.GetName(),
+		Limit:      int(req.GetLimit()),
+		FailedOnly: req.GetFailedOnly(),
+	}
+	if req.Since != nil {
+		q.Since = req.GetSince().AsTime()
+	}
+	if req.Until != nil {
+		q.Until = req.GetUntil().AsTime()
+	}
+
+	records, err := s.history.Query(ctx, q)
+	if err != nil {
+		s.logger.Error("Failed to query check history", zap.String("stream", q.Stream), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to query check history")
+	}
+	resp := &pb.GetHistoryResponse{Records: make([]*pb.HistoryRecord, 0, len(records))}
+	for i := range records {
+		resp.Records = append(resp.Records, historyRecord(&records[i]))
+	}
+	return resp, nil
+}
+
+// CheckNow запускает внеочередную проверку и ожидает ее результат. Если стрим
+// проверяется в момент вызова, результатом будет следующая за текущей проверка.
+func (s *Server) CheckNow(ctx context.Context, req *pb.CheckNowRequest) (*pb.CheckResult, error) {
+	if !s.admin {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if tok, ok := ctx.Value(tokenKey{}).(*apiToken); ok && !tok.admin {
+		return nil, status.Error(codes.PermissionDenied, "API token is not allowed to change state")
+	}
+	stream, err := s.stream(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	// Подписка до запуска, чтобы не пропустить быстро завершившуюся проверку
+	results, cancel := s.feed.Subscribe()
+	defer cancel()
+	requested := time.Now()
+	if err := s.manager.TriggerCheck(stream.Name); err != nil {
+		switch {
+		case errors.Is(err, models.ErrStreamNotFound):
+			return nil, status.Error(codes.NotFound, "stream not found")
+		case errors.Is(err, models.ErrStreamInactive):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Текущая проверка и запущенная после нее укладываются в два таймаута
+	timer := time.NewTimer(2*stream.Timeout + checkNowGrace)
+	defer timer.Stop()
+	for {
+		select {
+		case result := <-results:
+			// Проверка, начатая до запроса, не является ответом на него
+			if result.StreamName == stream.Name && !result.Timestamp.Before(requested) {
+				return checkResult(result), nil
+			}
+		case <-timer.C:
+			return nil, status.Error(codes.DeadlineExceeded, "check did not finish in time")
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// WatchResults отправляет результаты проверок видимых стримов по мере их сохранения
+func (s *Server) WatchResults(req *pb.WatchResultsRequest, srv grpc.ServerStreamingServer[pb.CheckResult]) error {
+	ctx := srv.Context()
+	names := make(map[string]bool, len(req.GetNames()))
+	for _, name := range req.GetNames() {
+		names[name] = true
+	}
+
+	results, cancel := s.feed.Subscribe()
+	defer cancel()
+	// Заголовки после подписки: дождавшись их, клиент не пропустит следующие результаты
+	if err := srv.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case result := <-results:
+			if len(names) > 0 && !names[result.StreamName] {
+				continue
+			}
+			if stream, ok := s.manager.Stream(result.StreamName); !ok || !s.visible(ctx, stream) {
+				continue
+			}
+			if err := srv.Send(checkResult(result)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// stream возвращает стрим, видимый вызывающему. Стримы вне области видимости
+// неотличимы от несуществующих.
+func (s *Server) stream(ctx context.Context, name string) (models.StreamConfig, error) {
+	stream, ok := s.manager.Stream(name)
+	if !ok || !s.visible(ctx, stream) {
+		return models.StreamConfig{}, status.Error(codes.NotFound, "stream not found")
+	}
+	return stream, nil
+}
+
+func (s *Server) streamState(stream models.StreamConfig) *pb.StreamState {
+	state := &pb.StreamState{
+		Name:     stream.Name,
+		Url:      stream.URL,
+		Interval: duration(stream.Interval),
+		Paused:   s.manager.Paused(stream.Name),
+		Owned:    s.manager.Owns(stream.Name),
+	}
+	if result, ok := s.results.Get(stream.Name); ok {
+		state.LastResult = checkResult(result)
+	}
+	return state
+}
@@ -0,0 +1,294 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/history"
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/internal/scheduler"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/internal/webauth"
+	pb "github.com/iudanet/hls_exporter/pkg/api/hlsexporterv1"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testHash хеш bcrypt пароля "secret" с минимальной стоимостью
+const testHash = "$2a$04$ivk2um3g3owgVk4kJmeUIOwBrLpDkY1cSbJTeES7XT1l46mwdoJzG"
+
+// stubChecker успешно проверяет любой стрим и считает проверки
+type stubChecker struct {
+	checks atomic.Int32
+}
+
+func (c *stubChecker) Check(_ context.Context, stream models.StreamConfig) (*models.CheckResult, error) {
+	c.checks.Add(1)
+	return &models.CheckResult{
+		StreamName: stream.Name,
+		Success:    true,
+		Timestamp:  time.Now(),
+		Segments:   models.SegmentResults{Checked: 2, Total: 2, Listed: 5},
+	}, nil
+}
+func (c *stubChecker) Start() error  { return nil }
+func (c *stubChecker) Stop() error   { return nil }
+func (c *stubChecker) Forget(string) {}
+
+type testEnv struct {
+	sched   *scheduler.Scheduler
+	results *store.ResultStore
+	checker *stubChecker
+}
+
+// newTestEnv запускает планировщик со стримами news_hd и sports_hd. Плановые
+// проверки редкие, поэтому после первой проверки стримы проверяются по запросу.
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := &testEnv{results: store.NewResultStore(), checker: &stubChecker{}}
+	env.sched = scheduler.New(scheduler.Dependencies{
+		Checker:   env.checker,
+		Metrics:   metrics.NewCollector(prometheus.NewRegistry()),
+		Results:   env.results,
+		Overrides: override.NewStore(),
+	})
+	for _, name := range []string{"news_hd", "sports_hd"} {
+		require.NoError(t, env.sched.Add(models.StreamConfig{
+			Name:      name,
+			URL:       "http://example.com/" + name + ".m3u8",
+			CheckMode: models.CheckModeFirstLast,
+			Interval:  time.Hour,
+			Timeout:   time.Second,
+		}))
+	}
+	env.sched.Start(context.Background())
+	t.Cleanup(env.sched.Stop)
+
+	require.Eventually(t, func() bool {
+		return len(env.results.List()) == 2
+	}, time.Second, 10*time.Millisecond, "first checks should complete")
+	return env
+}
+
+func (env *testEnv) deps() Dependencies {
+	return Dependencies{Manager: env.sched, Results: env.results, Feed: env.results}
+}
+
+// newTestClient запускает сервис в памяти через bufconn
+func newTestClient(t *testing.T, deps Dependencies) pb.StatusServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	gs := NewServer(deps).GRPCServer()
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewStatusServiceClient(conn)
+}
+
+func withAuth(value string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", value)
+}
+
+func TestStreams(t *testing.T) {
+	env := newTestEnv(t)
+	require.NoError(t, env.sched.SetPaused("sports_hd", true))
+	client := newTestClient(t, env.deps())
+	ctx := context.Background()
+
+	resp, err := client.ListStreams(ctx, &pb.ListStreamsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Streams, 2)
+
+	state, err := client.GetStream(ctx, &pb.GetStreamRequest{Name: "sports_hd"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://example.com/sports_hd.m3u8", state.Url)
+	assert.Equal(t, time.Hour, state.Interval.AsDuration())
+	assert.True(t, state.Paused)
+	assert.True(t, state.Owned)
+	require.NotNil(t, state.LastResult)
+	assert.True(t, state.LastResult.Success)
+	assert.Equal(t, int32(2), state.LastResult.Segments.Checked)
+	assert.Equal(t, int32(5), state.LastResult.Segments.Listed)
+
+	_, err = client.GetStream(ctx, &pb.GetStreamRequest{Name: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGetHistory(t *testing.T) {
+	env := newTestEnv(t)
+	client := newTestClient(t, env.deps())
+	_, err := client.GetHistory(context.Background(), &pb.GetHistoryRequest{Name: "news_hd"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err), "history is disabled")
+
+	checks, err := history.Open(filepath.Join(t.TempDir(), "history.db"), 0)
+	require.NoError(t, err)
+	defer checks.Close()
+	start := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		result := &models.CheckResult{
+			StreamName: "news_hd",
+			Success:    i != 1,
+			Timestamp:  start.Add(time.Duration(i) * time.Minute),
+		}
+		if !result.Success {
+			result.Error = &models.CheckError{Type: models.ErrSegmentDownload, Message: "not found", StatusCode: 404}
+		}
+		require.NoError(t, checks.Record(result))
+	}
+	deps := env.deps()
+	deps.History = checks
+	client = newTestClient(t, deps)
+
+	query := func(req *pb.GetHistoryRequest) []*pb.HistoryRecord {
+		t.Helper()
+		req.Name = "news_hd"
+		resp, err := client.GetHistory(context.Background(), req)
+		require.NoError(t, err)
+		return resp.Records
+	}
+
+	records := query(&pb.GetHistoryRequest{})
+	require.Len(t, records, 3)
+	assert.Equal(t, start.Add(2*time.Minute), records[0].Timestamp.AsTime(), "newest records first")
+	assert.Len(t, query(&pb.GetHistoryRequest{Limit: 2}), 2)
+	assert.Len(t, query(&pb.GetHistoryRequest{Since: timestamppb.New(start.Add(time.Minute))}), 2)
+	assert.Len(t, query(&pb.GetHistoryRequest{Until: timestamppb.New(start.Add(time.Minute))}), 2)
+	failed := query(&pb.GetHistoryRequest{FailedOnly: true})
+	require.Len(t, failed, 1)
+	assert.Equal(t, string(models.ErrSegmentDownload), failed[0].ErrorType)
+	assert.Equal(t, int32(404), failed[0].StatusCode)
+
+	_, err = client.GetHistory(context.Background(), &pb.GetHistoryRequest{Name: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCheckNow(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	_, err := newTestClient(t, env.deps()).CheckNow(ctx, &pb.CheckNowRequest{Name: "news_hd"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "admin API is disabled")
+
+	deps := env.deps()
+	deps.Admin = true
+	client := newTestClient(t, deps)
+
+	checks := env.checker.checks.Load()
+	requested := time.Now()
+	result, err := client.CheckNow(ctx, &pb.CheckNowRequest{Name: "news_hd"})
+	require.NoError(t, err)
+	assert.Equal(t, "news_hd", result.StreamName)
+	assert.True(t, result.Success)
+	assert.False(t, result.Timestamp.AsTime().Before(requested), "result of the triggered check")
+	assert.Equal(t, checks+1, env.checker.checks.Load())
+
+	_, err = client.CheckNow(ctx, &pb.CheckNowRequest{Name: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	require.NoError(t, env.sched.SetPaused("news_hd", true))
+	_, err = client.CheckNow(ctx, &pb.CheckNowRequest{Name: "news_hd"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "paused stream is not checked")
+}
+
+func TestWatchResults(t *testing.T) {
+	env := newTestEnv(t)
+	client := newTestClient(t, env.deps())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watch, err := client.WatchResults(ctx, &pb.WatchResultsRequest{Names: []string{"sports_hd"}})
+	require.NoError(t, err)
+	// Заголовки приходят после подписки на результаты
+	_, err = watch.Header()
+	require.NoError(t, err)
+
+	require.NoError(t, env.sched.TriggerCheck("news_hd"))
+	require.NoError(t, env.sched.TriggerCheck("sports_hd"))
+	result, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "sports_hd", result.StreamName, "results of other streams are filtered")
+}
+
+func TestAuth_Tokens(t *testing.T) {
+	env := newTestEnv(t)
+	deps := env.deps()
+	deps.Admin = true
+	deps.Tokens = []models.APIToken{
+		{Name: "news", Token: "news-token", Selector: []string{`stream=~"news_.*"`}},
+		{Name: "ops", Token: "ops-token", Admin: true},
+	}
+	client := newTestClient(t, deps)
+
+	_, err := client.ListStreams(context.Background(), &pb.ListStreamsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "missing token")
+	_, err = client.ListStreams(withAuth("Bearer wrong"), &pb.ListStreamsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "unknown token")
+
+	news := withAuth("Bearer news-token")
+	resp, err := client.ListStreams(news, &pb.ListStreamsRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Streams, 1)
+	assert.Equal(t, "news_hd", resp.Streams[0].Name)
+	_, err = client.GetStream(news, &pb.GetStreamRequest{Name: "sports_hd"})
+	assert.Equal(t, codes.NotFound, status.Code(err), "streams out of scope look missing")
+	_, err = client.CheckNow(news, &pb.CheckNowRequest{Name: "news_hd"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "token without admin")
+
+	_, err = client.CheckNow(withAuth("Bearer ops-token"), &pb.CheckNowRequest{Name: "sports_hd"})
+	assert.NoError(t, err)
+
+	// Область видимости применяется и к потоку результатов
+	watch, err := client.WatchResults(news, &pb.WatchResultsRequest{})
+	require.NoError(t, err)
+	_, err = watch.Header()
+	require.NoError(t, err)
+	require.NoError(t, env.sched.TriggerCheck("sports_hd"))
+	require.NoError(t, env.sched.TriggerCheck("news_hd"))
+	result, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "news_hd", result.StreamName)
+}
+
+func TestAuth_Basic(t *testing.T) {
+	env := newTestEnv(t)
+	deps := env.deps()
+	deps.BasicAuth = webauth.New(map[string]string{"prometheus": testHash}, "")
+	client := newTestClient(t, deps)
+
+	basic := func(credentials string) context.Context {
+		return withAuth("Basic " + base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+
+	_, err := client.ListStreams(context.Background(), &pb.ListStreamsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.ListStreams(basic("prometheus:wrong"), &pb.ListStreamsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	resp, err := client.ListStreams(basic("prometheus:secret"), &pb.ListStreamsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Streams, 2)
+}
@@ -0,0 +1,223 @@
+// Package history хранит результаты всех проверок во встроенной базе SQLite
+// для разбора инцидентов с точностью выше разрешения метрик
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	_ "modernc.org/sqlite" // драйвер SQLite без cgo
+)
+
+var _ models.HistoryStore = (*Store)(nil)
+
+// pruneInterval периодичность удаления записей старше срока хранения
+const pruneInterval = time.Minute
+
+const schema = `
+CREATE TABLE IF NOT EXISTS checks (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	stream           TEXT    NOT NULL,
+	check_id         TEXT    NOT NULL DEFAULT '',
+	timestamp        INTEGER NOT NULL,
+	duration         INTEGER NOT NULL,
+	success          INTEGER NOT NULL,
+	error_type       TEXT    NOT NULL DEFAULT '',
+	error_message    TEXT    NOT NULL DEFAULT '',
+	status_code      INTEGER NOT NULL DEFAULT 0,
+	segments_checked INTEGER NOT NULL DEFAULT 0,
+	segments_failed  INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS checks_stream_timestamp ON checks (stream, timestamp);
+CREATE INDEX IF NOT EXISTS checks_timestamp ON checks (timestamp);
+`
+
+// Store история проверок в файле SQLite
+type Store struct {
+	db        *sql.DB
+	retention time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// Open открывает или создает базу истории. Записи старше retention удаляются
+// (0 - хранятся без ограничения).
+func Open(path string, retention time.Duration) (*Store, error) {
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+	// SQLite допускает одного писателя, общее соединение исключает SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %w", err)
+	}
+	return &Store{
+		db:        db,
+		retention: retention,
+		now:       time.Now,
+	}, nil
+}
+
+// Close закрывает базу
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record сохраняет результат проверки и периодически удаляет устаревшие записи
+func (s *Store) Record(result *models.CheckResult) error {
+	if result == nil {
+		return nil
+	}
+
+	var (
+		errType    models.ErrorType
+		message    string
+		statusCode int
+	)
+	if result.Error != nil {
+		errType = result.Error.Type
+		message = result.Error.Message
+		statusCode = result.Error.StatusCode
+	}
+	timestamp := result.Timestamp
+	if timestamp.IsZero() {
+		timestamp = s.now()
+	}
+
+	_, err := s.db.Exec(`INSERT INTO checks
+		(stream, check_id, timestamp, duration, success, error_type, error_message,
+		 status_code, segments_checked, segments_failed)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		result.StreamName, result.CheckID, timestamp.UnixNano(), int64(result.Duration),
+		result.Success, string(errType), message, statusCode,
+		result.Segments.Checked, result.Segments.Failed)
+	if err != nil {
+		return fmt.Errorf("failed to record check result: %w", err)
+	}
+
+	if s.pruneDue() {
+		if _, err := s.Prune(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneDue сообщает, что пора удалить устаревшие записи
+func (s *Store) pruneDue() bool {
+	if s.retention <= 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return false
+	}
+	s.lastPrune = now
+	return true
+}
+
+// Prune удаляет записи старше срока хранения и возвращает их число
+func (s *Store) Prune() (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	res, err := s.db.Exec(`DELETE FROM checks WHERE timestamp < ?`,
+		s.now().Add(-s.retention).UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune check history: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Query возвращает записи истории стрима, новые первыми
+func (s *Store) Query(ctx context.Context, q models.HistoryQuery) ([]models.HistoryRecord, error) {
+	where := []string{"stream = ?"}
+	args := []any{q.Stream}
+	if !q.Since.IsZero() {
+		where = append(where, "timestamp >= ?")
+		args = append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "timestamp <= ?")
+		args = append(args, q.Until.UnixNano())
+	}
+	if q.FailedOnly {
+		where = append(where, "success = 0")
+	}
+	query := `SELECT stream, check_id, timestamp, duration, success, error_type, error_message,
+		status_code, segments_checked, segments_failed
+		FROM checks WHERE ` + strings.Join(where, " AND ") + ` ORDER BY timestamp DESC, id DESC`
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check history: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.HistoryRecord{}
+	for rows.Next() {
+		var (
+			rec       models.HistoryRecord
+			timestamp int64
+			duration  int64
+			errType   string
+		)
+		if err := rows.Scan(&rec.Stream, &rec.CheckID, &timestamp, &duration, &rec.Success,
+			&errType, &rec.ErrorMessage, &rec.StatusCode, &rec.SegmentsChecked, &rec.SegmentsFailed); err != nil {
+			return nil, fmt.Errorf("failed to read check history: %w", err)
+		}
+		rec.Timestamp = time.Unix(0, timestamp)
+		rec.Duration = time.Duration(duration)
+		rec.ErrorType = models.ErrorType(errType)
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read check history: %w", err)
+	}
+	return records, nil
+}
+
+// LastSuccess возвращает время последней успешной проверки каждого стрима
+func (s *Store) LastSuccess(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT stream, MAX(timestamp) FROM checks WHERE success = 1 GROUP BY stream`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check history: %w", err)
+	}
+	defer rows.Close()
+
+	last := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			stream    string
+			timestamp int64
+		)
+		if err := rows.Scan(&stream, &timestamp); err != nil {
+			return nil, fmt.Errorf("failed to read check history: %w", err)
+		}
+		last[stream] = time.Unix(0, timestamp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read check history: %w", err)
+	}
+	return last, nil
+}
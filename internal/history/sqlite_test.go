@@ -0,0 +1,150 @@
+package history
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestStore(t *testing.T, retention time.Duration) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"), retention)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestStore_RecordQuery(t *testing.T) {
+	s := openTestStore(t, 0)
+	start := time.Unix(1_700_000_000, 0)
+
+	require.NoError(t, s.Record(nil))
+	require.NoError(t, s.Record(&models.CheckResult{
+		StreamName: "stream_a",
+		CheckID:    "a1",
+		Success:    true,
+		Timestamp:  start,
+		Duration:   1500 * time.Millisecond,
+		Segments:   models.SegmentResults{Checked: 3},
+	}))
+	require.NoError(t, s.Record(&models.CheckResult{
+		StreamName: "stream_a",
+		CheckID:    "a2",
+		Timestamp:  start.Add(time.Minute),
+		Duration:   time.Second,
+		Segments:   models.SegmentResults{Checked: 3, Failed: 2},
+		Error: &models.CheckError{
+			Type:       models.ErrSegmentDownload,
+			Message:    "unexpected status code: 404",
+			StatusCode: http.StatusNotFound,
+		},
+	}))
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_b", Success: true, Timestamp: start}))
+
+	records, err := s.Query(context.Background(), models.HistoryQuery{Stream: "stream_a"})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, models.HistoryRecord{
+		Stream:          "stream_a",
+		CheckID:         "a2",
+		Timestamp:       start.Add(time.Minute),
+		Duration:        time.Second,
+		ErrorType:       models.ErrSegmentDownload,
+		ErrorMessage:    "unexpected status code: 404",
+		StatusCode:      http.StatusNotFound,
+		SegmentsChecked: 3,
+		SegmentsFailed:  2,
+	}, records[0], "newest record should come first")
+	assert.Equal(t, "a1", records[1].CheckID)
+	assert.True(t, records[1].Success)
+	assert.Equal(t, 1500*time.Millisecond, records[1].Duration)
+
+	tests := []struct {
+		name  string
+		query models.HistoryQuery
+		want  []string
+	}{
+		{name: "since", query: models.HistoryQuery{Since: start.Add(time.Second)}, want: []string{"a2"}},
+		{name: "until", query: models.HistoryQuery{Until: start}, want: []string{"a1"}},
+		{name: "limit", query: models.HistoryQuery{Limit: 1}, want: []string{"a2"}},
+		{name: "failed only", query: models.HistoryQuery{FailedOnly: true}, want: []string{"a2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Stream = "stream_a"
+			records, err := s.Query(context.Background(), tt.query)
+			require.NoError(t, err)
+			var ids []string
+			for _, rec := range records {
+				ids = append(ids, rec.CheckID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+
+	records, err = s.Query(context.Background(), models.HistoryQuery{Stream: "missing"})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestStore_Retention(t *testing.T) {
+	s := openTestStore(t, time.Hour)
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "old", Timestamp: now.Add(-2 * time.Hour)}))
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "new", Timestamp: now}))
+
+	// Первая запись удаляется при очистке, выполненной вместе с ней
+	records, err := s.Query(context.Background(), models.HistoryQuery{Stream: "stream_a"})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "new", records[0].CheckID)
+
+	// Следующая очистка не раньше чем через pruneInterval
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "stale", Timestamp: now.Add(-2 * time.Hour)}))
+	records, err = s.Query(context.Background(), models.HistoryQuery{Stream: "stream_a"})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	removed, err := s.Prune()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, removed)
+}
+
+func TestOpen_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	s, err := Open(path, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", CheckID: "a1", Timestamp: time.Now()}))
+	require.NoError(t, s.Close())
+
+	s, err = Open(path, 0)
+	require.NoError(t, err)
+	defer s.Close()
+	records, err := s.Query(context.Background(), models.HistoryQuery{Stream: "stream_a"})
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func TestStore_LastSuccess(t *testing.T) {
+	s := openTestStore(t, 0)
+	start := time.Unix(1_700_000_000, 0)
+
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", Success: true, Timestamp: start}))
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", Success: true, Timestamp: start.Add(time.Minute)}))
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_a", Timestamp: start.Add(2 * time.Minute)}))
+	require.NoError(t, s.Record(&models.CheckResult{StreamName: "stream_b", Timestamp: start}))
+
+	last, err := s.LastSuccess(context.Background())
+	require.NoError(t, err)
+	// Стрим без успешных проверок отсутствует
+	assert.Equal(t, map[string]time.Time{"stream_a": start.Add(time.Minute)}, last)
+}
@@ -0,0 +1,83 @@
+// Package hook передает результаты проверок внешним командам стримов
+package hook
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.ResultHook = (*Runner)(nil)
+
+const (
+	// maxReason наибольшая длина причины деградации из вывода команды
+	maxReason = 256
+	// waitDelay ожидание закрытия вывода после завершения или остановки команды
+	waitDelay = time.Second
+)
+
+// Runner запускает команды hook стримов
+type Runner struct{}
+
+// New создает Runner
+func New() *Runner {
+	return &Runner{}
+}
+
+// Run передает JSON результата на stdin команды hook стрима. Ненулевой код выхода,
+// в том числе по истечении timeout, отмечает результат деградировавшим; причиной
+// становится первая строка stderr команды. Стримы без hook не затрагиваются.
+func (r *Runner) Run(ctx context.Context, stream models.StreamConfig, result *models.CheckResult) error {
+	if stream.Hook == nil || result == nil {
+		return nil
+	}
+
+	input, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal result: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cmp.Or(stream.Hook.Timeout, models.DefaultHookTimeout))
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, stream.Hook.Command[0], stream.Hook.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+	// Дочерние процессы команды могут удерживать stderr после ее завершения
+	cmd.WaitDelay = waitDelay
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	result.Degraded = true
+	result.DegradedReason = reason(stderr.String(), exitErr)
+	if ctx.Err() != nil {
+		result.DegradedReason = "hook timed out"
+	}
+	return nil
+}
+
+// reason возвращает первую непустую строку вывода команды или код выхода
+func reason(output string, exitErr *exec.ExitError) string {
+	for line := range strings.Lines(output) {
+		if line = strings.TrimSpace(line); line != "" {
+			if len(line) > maxReason {
+				line = line[:maxReason]
+			}
+			return line
+		}
+	}
+	return exitErr.Error()
+}
@@ -0,0 +1,73 @@
+package hook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runHook(t *testing.T, hook *models.HookConfig) (*models.CheckResult, error) {
+	t.Helper()
+	result := &models.CheckResult{StreamName: "test_stream", Success: true}
+	err := New().Run(context.Background(), models.StreamConfig{Name: "test_stream", Hook: hook}, result)
+	return result, err
+}
+
+func TestRunner_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		command    []string
+		degraded   bool
+		wantReason string
+	}{
+		{
+			name: "success reads result",
+			// Команда получает JSON результата на stdin
+			command: []string{"sh", "-c", `grep -q '"stream_name":"test_stream"'`},
+		},
+		{
+			name:       "non-zero exit",
+			command:    []string{"sh", "-c", "echo; echo 'no SCTE-35 markers' >&2; exit 2"},
+			degraded:   true,
+			wantReason: "no SCTE-35 markers",
+		},
+		{
+			name:       "no output",
+			command:    []string{"false"},
+			degraded:   true,
+			wantReason: "exit status 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := runHook(t, &models.HookConfig{Command: tt.command})
+			require.NoError(t, err)
+			assert.Equal(t, tt.degraded, result.Degraded)
+			assert.Equal(t, tt.wantReason, result.DegradedReason)
+			// Успешность проверки не меняется
+			assert.True(t, result.Success)
+		})
+	}
+}
+
+func TestRunner_Run_Timeout(t *testing.T) {
+	result, err := runHook(t, &models.HookConfig{Command: []string{"sh", "-c", "sleep 5"}, Timeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+	assert.True(t, result.Degraded)
+	assert.Equal(t, "hook timed out", result.DegradedReason)
+}
+
+func TestRunner_Run_NotFound(t *testing.T) {
+	result, err := runHook(t, &models.HookConfig{Command: []string{"/nonexistent/hook"}})
+	assert.Error(t, err)
+	assert.False(t, result.Degraded)
+
+	// Стрим без hook не затрагивается
+	result, err = runHook(t, nil)
+	assert.NoError(t, err)
+	assert.False(t, result.Degraded)
+}
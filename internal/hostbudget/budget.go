@@ -0,0 +1,110 @@
+// Package hostbudget ограничивает суммарную частоту запросов к одному хосту
+// источника или CDN, общему для нескольких стримов, и всех запросов проверок
+package hostbudget
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Budget ограничивает частоту запросов к каждому хосту алгоритмом token bucket
+type Budget struct {
+	rate  float64
+	burst float64
+	// onWait получает время ожидания запроса к хосту (опционально)
+	onWait func(host string, wait time.Duration)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New создает ограничение по cfg. Burst по умолчанию - число запросов в секунду,
+// но не меньше одного.
+func New(cfg models.HostBudgetConfig, onWait func(host string, wait time.Duration)) *Budget {
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = max(int(math.Ceil(cfg.RequestsPerSecond)), 1)
+	}
+	return &Budget{
+		rate:    cfg.RequestsPerSecond,
+		burst:   float64(burst),
+		onWait:  onWait,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Host возвращает хост URL в нижнем регистре, по которому считается ограничение
+func Host(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// Wait ожидает возможности выполнить запрос к хосту. При отмене контекста
+// зарезервированный запрос возвращается в бюджет.
+func (b *Budget) Wait(ctx context.Context, host string) error {
+	wait := b.reserve(host)
+	if wait <= 0 {
+		return nil
+	}
+	if b.onWait != nil {
+		b.onWait(host, wait)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel(host)
+		return ctx.Err()
+	}
+}
+
+// reserve забирает из бюджета хоста один запрос и возвращает, сколько ждать до его выполнения
+func (b *Budget) reserve(host string) time.Duration {
+	now := b.now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk, ok := b.buckets[host]
+	if !ok {
+		bk = &bucket{tokens: b.burst, last: now}
+		b.buckets[host] = bk
+	}
+	if elapsed := now.Sub(bk.last); elapsed > 0 {
+		bk.tokens = min(bk.tokens+elapsed.Seconds()*b.rate, b.burst)
+		bk.last = now
+	}
+	bk.tokens--
+	if bk.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-bk.tokens / b.rate * float64(time.Second))
+}
+
+// cancel возвращает неиспользованный запрос в бюджет хоста
+func (b *Budget) cancel(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if bk, ok := b.buckets[host]; ok {
+		bk.tokens = min(bk.tokens+1, b.burst)
+	}
+}
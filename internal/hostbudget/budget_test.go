@@ -0,0 +1,123 @@
+package hostbudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHost(t *testing.T) {
+	assert.Equal(t, "cdn.example.com", Host("http://CDN.example.com:8080/live/index.m3u8"))
+	assert.Equal(t, "", Host("://bad"))
+}
+
+func TestBudget_Reserve(t *testing.T) {
+	b := New(models.HostBudgetConfig{RequestsPerSecond: 2}, nil)
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+
+	// Всплеск по умолчанию равен частоте
+	assert.Zero(t, b.reserve("a"))
+	assert.Zero(t, b.reserve("a"))
+	assert.Equal(t, 500*time.Millisecond, b.reserve("a"))
+	assert.Equal(t, time.Second, b.reserve("a"))
+	// Хосты ограничиваются независимо
+	assert.Zero(t, b.reserve("b"))
+
+	b.cancel("a")
+	assert.Equal(t, time.Second, b.reserve("a"))
+
+	// Бюджет восстанавливается со временем, но не выше всплеска
+	now = now.Add(time.Minute)
+	assert.Zero(t, b.reserve("a"))
+	assert.Zero(t, b.reserve("a"))
+	assert.Equal(t, 500*time.Millisecond, b.reserve("a"))
+}
+
+func TestBudget_Wait(t *testing.T) {
+	var waited []string
+	b := New(models.HostBudgetConfig{RequestsPerSecond: 100, Burst: 1}, func(host string, _ time.Duration) {
+		waited = append(waited, host)
+	})
+
+	require.NoError(t, b.Wait(context.Background(), "a"))
+	require.NoError(t, b.Wait(context.Background(), "a"))
+	assert.Equal(t, []string{"a"}, waited)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b = New(models.HostBudgetConfig{RequestsPerSecond: 0.001, Burst: 1}, nil)
+	require.NoError(t, b.Wait(ctx, "a"))
+	assert.ErrorIs(t, b.Wait(ctx, "a"), context.Canceled)
+}
+
+type mockHTTPClient struct {
+	mock.Mock
+}
+
+func (m *mockHTTPClient) GetPlaylist(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	args := m.Called(ctx, url)
+	return args.Get(0).(*models.PlaylistResponse), args.Error(1)
+}
+
+func (m *mockHTTPClient) GetSegment(ctx context.Context, url string, validate bool) (*models.SegmentResponse, error) {
+	args := m.Called(ctx, url, validate)
+	return args.Get(0).(*models.SegmentResponse), args.Error(1)
+}
+
+func (m *mockHTTPClient) GetKey(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	args := m.Called(ctx, url)
+	return args.Get(0).(*models.PlaylistResponse), args.Error(1)
+}
+
+func (m *mockHTTPClient) SetTimeout(time.Duration) {}
+func (m *mockHTTPClient) Close() error             { return nil }
+
+func TestClient(t *testing.T) {
+	inner := new(mockHTTPClient)
+	inner.On("GetPlaylist", mock.Anything, "http://cdn/live.m3u8").Return(&models.PlaylistResponse{}, nil)
+	inner.On("GetSegment", mock.Anything, "http://cdn/seg1.ts", true).Return(&models.SegmentResponse{}, nil)
+	c := Wrap(inner, New(models.HostBudgetConfig{RequestsPerSecond: 0.001, Burst: 1}, nil))
+
+	_, err := c.GetPlaylist(context.Background(), "http://cdn/live.m3u8")
+	require.NoError(t, err)
+
+	// Бюджет хоста исчерпан: запрос не выполняется до истечения таймаута
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetSegment(ctx, "http://cdn/seg1.ts", true)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	inner.AssertNotCalled(t, "GetSegment", mock.Anything, mock.Anything, mock.Anything)
+
+	// Ключи сегментов учитываются в том же бюджете
+	_, err = c.GetKey(ctx, "http://cdn/key.bin")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	inner.AssertNotCalled(t, "GetKey", mock.Anything, mock.Anything)
+}
+
+func TestClient_Global(t *testing.T) {
+	inner := new(mockHTTPClient)
+	inner.On("GetPlaylist", mock.Anything, "http://a/live.m3u8").Return(&models.PlaylistResponse{}, nil)
+	c := WrapGlobal(inner, New(models.HostBudgetConfig{RequestsPerSecond: 0.001, Burst: 1}, nil))
+
+	_, err := c.GetPlaylist(context.Background(), "http://a/live.m3u8")
+	require.NoError(t, err)
+
+	// Бюджет общий для всех хостов
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = c.GetSegment(ctx, "http://b/seg1.ts", true)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "request limit")
+	inner.AssertNotCalled(t, "GetSegment", mock.Anything, mock.Anything, mock.Anything)
+
+	// Ключи сегментов учитываются в том же ограничении
+	_, err = c.GetKey(ctx, "http://c/key.bin")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	inner.AssertNotCalled(t, "GetKey", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,65 @@
+package hostbudget
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.HTTPClient = (*Client)(nil)
+
+// globalKey ключ единственного бюджета общего ограничения частоты запросов
+const globalKey = "*"
+
+// Client HTTP-клиент, соблюдающий ограничение частоты запросов к хостам.
+// Ожидание не входит во время ответа, но входит в таймаут проверки.
+type Client struct {
+	models.HTTPClient
+	budget *Budget
+	// key возвращает ключ бюджета запроса по URL
+	key func(url string) string
+	// name используется в ошибке ожидания
+	name string
+}
+
+// Wrap оборачивает клиент ограничением частоты запросов к каждому хосту
+func Wrap(client models.HTTPClient, budget *Budget) *Client {
+	return &Client{
+		HTTPClient: client,
+		budget:     budget,
+		key:        Host,
+		name:       "host budget",
+	}
+}
+
+// WrapGlobal оборачивает клиент общим ограничением частоты всех запросов
+func WrapGlobal(client models.HTTPClient, budget *Budget) *Client {
+	return &Client{
+		HTTPClient: client,
+		budget:     budget,
+		key:        func(string) string { return globalKey },
+		name:       "request limit",
+	}
+}
+
+func (c *Client) GetPlaylist(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	if err := c.budget.Wait(ctx, c.key(url)); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	return c.HTTPClient.GetPlaylist(ctx, url)
+}
+
+func (c *Client) GetSegment(ctx context.Context, url string, validate bool) (*models.SegmentResponse, error) {
+	if err := c.budget.Wait(ctx, c.key(url)); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	return c.HTTPClient.GetSegment(ctx, url, validate)
+}
+
+func (c *Client) GetKey(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	if err := c.budget.Wait(ctx, c.key(url)); err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+	return c.HTTPClient.GetKey(ctx, url)
+}
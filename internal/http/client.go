@@ -2,40 +2,173 @@ package http
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/iudanet/hls_exporter/internal/mpegts"
 	"github.com/iudanet/hls_exporter/pkg/models"
 )
 
+// maxKeyBytes наибольший размер ответа сервера ключей: ключ AES-128 занимает 16 байт
+const maxKeyBytes = 1024
+
+// copyBufferSize размер буфера чтения тела сегмента
+const copyBufferSize = 32 * 1024
+
+// copyBuffers буферы чтения тел сегментов, общие для параллельных загрузок
+var copyBuffers = sync.Pool{
+	New: func() any {
+		buf := make([]byte, copyBufferSize)
+		return &buf
+	},
+}
+
 type Client struct {
 	httpClient *http.Client
 	userAgent  string
+	// requestIDHeader заголовок с идентификатором проверки
+	requestIDHeader string
+	// maxSegmentBytes наибольший размер проверяемого сегмента (0 - без ограничения)
+	maxSegmentBytes int64
+
+	maxIdleConns int
+	// tls параметры TLS по умолчанию, tlsErr - ошибка их загрузки
+	tls    models.TLSConfig
+	tlsErr error
+
+	mu sync.Mutex
+	// streamClients клиенты для стримов с переопределенными параметрами TLS
+	// или подменой адресов: у каждого свой пул соединений
+	streamClients map[transportKey]*http.Client
+}
+
+// transportKey параметры соединений стрима, определяющие отдельный транспорт
+type transportKey struct {
+	tls     models.TLSConfig
+	resolve string
 }
 
 var _ models.HTTPClient = (*Client)(nil)
 
+// NewClient создает HTTP-клиент. Ошибка загрузки файлов TLS возвращается
+// при каждом запросе, чтобы не прерывать запуск экспортера.
 func NewClient(config models.HTTPConfig) models.HTTPClient {
+	tlsCfg := config.TLSConfig
+	if !config.TLSVerify {
+		tlsCfg.InsecureSkipVerify = true
+	}
+
+	c := &Client{
+		userAgent:       config.UserAgent,
+		requestIDHeader: config.RequestIDHeader,
+		maxSegmentBytes: config.MaxSegmentBytes,
+		maxIdleConns:    config.MaxIdleConns,
+		tls:             tlsCfg,
+		streamClients:   make(map[transportKey]*http.Client),
+	}
+
+	tlsClientConfig, err := buildTLSConfig(tlsCfg)
+	if err != nil {
+		c.tlsErr = fmt.Errorf("tls config: %w", err)
+	}
+	c.httpClient = c.newHTTPClient(tlsClientConfig, nil, config.Timeout)
+	return c
+}
+
+// newHTTPClient создает клиент с отдельным транспортом. resolve подменяет
+// адреса соединений "host:port", не меняя Host запросов и SNI.
+func (c *Client) newHTTPClient(tlsClientConfig *tls.Config, resolve map[string]string, timeout time.Duration) *http.Client {
 	transport := &http.Transport{
-		MaxIdleConns:    config.MaxIdleConns,
+		MaxIdleConns:    c.maxIdleConns,
 		IdleConnTimeout: 90 * time.Second,
-		TLSClientConfig: nil, // TODO: add TLS config if needed
+		TLSClientConfig: tlsClientConfig,
+		// Со своим TLSClientConfig HTTP/2 включается только явно
+		ForceAttemptHTTP2: true,
 	}
 
-	client := &http.Client{
+	if len(resolve) > 0 {
+		var dialer net.Dialer
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if target, ok := resolve[strings.ToLower(addr)]; ok {
+				addr = target
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+
+	return &http.Client{
 		Transport: transport,
-		Timeout:   config.Timeout,
+		Timeout:   timeout,
 	}
+}
 
-	return &Client{
-		httpClient: client,
-		userAgent:  config.UserAgent,
+// clientFor возвращает клиент с параметрами TLS и подменой адресов стрима,
+// привязанными к контексту
+func (c *Client) clientFor(ctx context.Context) (*http.Client, error) {
+	override := models.StreamTLSFrom(ctx)
+	entries := models.ResolveFrom(ctx)
+	if override == nil && len(entries) == 0 {
+		return c.httpClient, c.tlsErr
 	}
+
+	key := transportKey{
+		tls:     c.tls.Merge(override),
+		resolve: strings.Join(entries, ","),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.streamClients[key]; ok {
+		return client, nil
+	}
+	tlsClientConfig, err := buildTLSConfig(key.tls)
+	if err != nil {
+		return nil, fmt.Errorf("tls config: %w", err)
+	}
+	resolve, err := models.ParseResolve(entries)
+	if err != nil {
+		return nil, fmt.Errorf("resolve: %w", err)
+	}
+	client := c.newHTTPClient(tlsClientConfig, resolve, c.httpClient.Timeout)
+	c.streamClients[key] = client
+	return client, nil
+}
+
+// do выполняет запрос клиентом, соответствующим параметрам соединений стрима, и записывает
+// транзакцию в журнал, если он привязан к контексту
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	client, err := c.clientFor(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if log := models.TransactionLogFrom(req.Context()); log != nil {
+		return doRecorded(client, req, log)
+	}
+	return client.Do(req)
 }
 
 func (c *Client) GetPlaylist(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	return c.get(ctx, url, 0)
+}
+
+// GetKey загружает ключ AES-128 сегментов. Ответ длиннее maxKeyBytes не дочитывается
+// и считается ошибкой.
+func (c *Client) GetKey(ctx context.Context, url string) (*models.PlaylistResponse, error) {
+	return c.get(ctx, url, maxKeyBytes)
+}
+
+// get загружает тело ответа целиком; limit > 0 ограничивает его размер
+func (c *Client) get(ctx context.Context, url string, limit int64) (*models.PlaylistResponse, error) {
 	start := time.Now()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -43,11 +176,9 @@ func (c *Client) GetPlaylist(ctx context.Context, url string) (*models.PlaylistR
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	if c.userAgent != "" {
-		req.Header.Set("User-Agent", c.userAgent)
-	}
+	c.prepareRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
 	}
@@ -58,10 +189,22 @@ func (c *Client) GetPlaylist(ctx context.Context, url string) (*models.PlaylistR
 			StatusCode: resp.StatusCode,
 			Duration:   time.Since(start),
 			Headers:    resp.Header,
+			RetryAfter: retryAfter(resp, time.Now()),
 		}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var body []byte
+	if limit > 0 {
+		if resp.ContentLength > limit {
+			return nil, fmt.Errorf("response size %d exceeds %d bytes", resp.ContentLength, limit)
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+		if err == nil && int64(len(body)) > limit {
+			return nil, fmt.Errorf("response exceeds %d bytes", limit)
+		}
+	} else {
+		body, err = io.ReadAll(resp.Body)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("read body: %w", err)
 	}
@@ -82,25 +225,30 @@ func (c *Client) GetSegment(ctx context.Context, url string, validate bool) (*mo
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	if c.userAgent != "" {
-		req.Header.Set("User-Agent", c.userAgent)
-	}
+	c.prepareRequest(req)
 
-	// Если не нужна валидация, проверяем только заголовки
-	if !validate {
+	// Если не нужна валидация, проверяем только заголовки,
+	// в режиме выборки загружаем только начало сегмента
+	rangeBytes := models.SegmentRangeFrom(ctx)
+	switch {
+	case !validate:
 		req.Method = http.MethodHead
+	case rangeBytes > 0:
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", rangeBytes-1))
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	partial := resp.StatusCode == http.StatusPartialContent && rangeBytes > 0
+	if resp.StatusCode != http.StatusOK && !partial {
 		return &models.SegmentResponse{
 			StatusCode: resp.StatusCode,
 			Duration:   time.Since(start),
+			RetryAfter: retryAfter(resp, time.Now()),
 		}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
@@ -109,8 +257,10 @@ func (c *Client) GetSegment(ctx context.Context, url string, validate bool) (*mo
 		Duration:   time.Since(start),
 	}
 
-	// Получаем размер сегмента
-	if contentLength := resp.Header.Get("Content-Length"); contentLength != "" {
+	// Получаем размер сегмента: для части сегмента - полный размер из Content-Range
+	if partial {
+		segmentResponse.Size = contentRangeSize(resp.Header.Get("Content-Range"))
+	} else if contentLength := resp.Header.Get("Content-Length"); contentLength != "" {
 		size, err := parseInt64(contentLength)
 		if err == nil {
 			segmentResponse.Size = size
@@ -119,35 +269,163 @@ func (c *Client) GetSegment(ctx context.Context, url string, validate bool) (*mo
 
 	// Если нужна валидация, читаем и анализируем тело
 	if validate {
-		mediaInfo, err := c.analyzeSegment(resp.Body)
+		body, limit := io.Reader(resp.Body), c.maxSegmentBytes
+		// Выборка ограничена сама по себе, а источник может проигнорировать Range
+		// и передать сегмент целиком
+		if rangeBytes > 0 {
+			body, limit = io.LimitReader(body, rangeBytes), 0
+		} else if limit > 0 && resp.ContentLength > limit {
+			return nil, fmt.Errorf("segment size %d exceeds max_segment_bytes %d", resp.ContentLength, limit)
+		}
+		if key, ok := models.SegmentKeyFrom(ctx); ok {
+			if body, err = newDecryptReader(body, key, rangeBytes == 0); err != nil {
+				return nil, fmt.Errorf("decrypt segment: %w", err)
+			}
+		}
+		mediaInfo, read, err := c.analyzeSegment(body, limit)
 		if err != nil {
 			return nil, fmt.Errorf("analyze segment: %w", err)
 		}
 		segmentResponse.MediaInfo = mediaInfo
+		// Без Content-Length (chunked) размер известен только после чтения
+		if segmentResponse.Size == 0 {
+			segmentResponse.Size = read
+		}
 	}
 
 	return segmentResponse, nil
 }
 
+// prepareRequest добавляет к запросу User-Agent, а также идентификаторы проверки и трассировки,
+// заголовки и авторизацию стрима, привязанные к контексту запроса
+func (c *Client) prepareRequest(req *http.Request) {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if id := models.CheckIDFrom(req.Context()); id != "" && c.requestIDHeader != "" {
+		req.Header.Set(c.requestIDHeader, id)
+	}
+	if traceID := models.TraceIDFrom(req.Context()); traceID != "" {
+		req.Header.Set("traceparent", traceParent(traceID))
+	}
+
+	auth := models.RequestAuthFrom(req.Context())
+	if auth.UserAgent != "" {
+		req.Header.Set("User-Agent", auth.UserAgent)
+	}
+	for name, value := range auth.Headers {
+		req.Header.Set(name, value)
+	}
+	if auth.BasicAuth != nil {
+		req.SetBasicAuth(auth.BasicAuth.Username, auth.BasicAuth.Password)
+	}
+	if auth.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+auth.BearerToken)
+	}
+}
+
+// traceParent формирует заголовок traceparent (W3C Trace Context) с новым
+// идентификатором родительского span для каждого запроса
+func traceParent(traceID string) string {
+	var span [8]byte
+	_, _ = rand.Read(span[:])
+	return "00-" + traceID + "-" + hex.EncodeToString(span[:]) + "-01"
+}
+
 func (c *Client) SetTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.httpClient.Timeout = timeout
+	for _, client := range c.streamClients {
+		client.Timeout = timeout
+	}
 }
 
 func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.httpClient.CloseIdleConnections()
+	for _, client := range c.streamClients {
+		client.CloseIdleConnections()
+	}
 	return nil
 }
 
-// analyzeSegment анализирует медиа-контейнер сегмента
-func (c *Client) analyzeSegment(_ io.Reader) (models.MediaInfo, error) {
+// analyzeSegment анализирует медиа-контейнер сегмента и возвращает число прочитанных байт.
+// Тело не буферизуется целиком: оно читается буфером из пула и сразу передается анализатору.
+// Тело длиннее limit байт (если limit > 0) считается ошибкой.
+func (c *Client) analyzeSegment(body io.Reader, limit int64) (models.MediaInfo, int64, error) {
+	if limit > 0 {
+		body = io.LimitReader(body, limit+1)
+	}
+
+	buf := copyBuffers.Get().(*[]byte)
+	defer copyBuffers.Put(buf)
+
+	// Дочитываем тело, чтобы обнаружить оборванную передачу,
+	// попутно анализируя транспортный поток MPEG-TS
+	scanner := mpegts.NewScanner()
+	read, err := io.CopyBuffer(scanner, body, *buf)
+	if err != nil {
+		return models.MediaInfo{}, read, fmt.Errorf("read body: %w", err)
+	}
+	if limit > 0 && read > limit {
+		return models.MediaInfo{}, read, fmt.Errorf("segment exceeds max_segment_bytes %d", limit)
+	}
+
+	pcr := scanner.PCRStats()
+	packets, nullPackets := scanner.Packets()
+
 	// TODO: Implement actual media container analysis
 	// This is a placeholder that should be replaced with actual media container parsing
 	return models.MediaInfo{
-		Container:  "TS",
-		HasVideo:   true,
-		HasAudio:   true,
-		IsComplete: true,
-	}, nil
+		Container:      "TS",
+		HasVideo:       true,
+		HasAudio:       true,
+		IsComplete:     true,
+		Programs:       scanner.Programs(),
+		CCErrors:       scanner.CCErrors(),
+		Packets:        packets,
+		NullPackets:    nullPackets,
+		PCRCount:       pcr.Count,
+		PCRMaxInterval: pcr.MaxInterval,
+		PCRJitter:      pcr.Jitter,
+	}, read, nil
+}
+
+// retryAfter возвращает паузу из заголовка Retry-After ответа 429 или 503:
+// число секунд или дату HTTP (RFC 9110, 10.2.3). 0 - заголовка нет или он некорректен.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	value := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+// contentRangeSize возвращает полный размер ресурса из заголовка
+// Content-Range "bytes 0-1023/146515" (0, если размер неизвестен)
+func contentRangeSize(header string) int64 {
+	_, total, ok := strings.Cut(header, "/")
+	if !ok || total == "*" {
+		return 0
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return size
 }
 
 func parseInt64(s string) (int64, error) {
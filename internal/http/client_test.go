@@ -2,8 +2,10 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,9 +85,16 @@ func TestClient_GetSegment(t *testing.T) {
 			name:       "full validation",
 			validate:   true,
 			statusCode: http.StatusOK,
-			size:       "2048",
+			size:       "17",
 			wantErr:    false,
 		},
+		{
+			name:       "truncated body",
+			validate:   true,
+			statusCode: http.StatusOK,
+			size:       "2048",
+			wantErr:    true,
+		},
 		{
 			name:       "error response",
 			validate:   false,
@@ -167,3 +176,374 @@ func TestClient_Context(t *testing.T) {
 		t.Error("GetPlaylist() should fail with context deadline exceeded")
 	}
 }
+
+func TestClient_RequestAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		auth          models.RequestAuth
+		wantHeader    string
+		wantAuth      string
+		wantUserAgent string
+	}{
+		{
+			name:          "no auth",
+			wantUserAgent: "test-agent",
+		},
+		{
+			name: "headers and bearer token",
+			auth: models.RequestAuth{
+				Headers:     map[string]string{"x-origin-key": "signed", "User-Agent": "custom-agent"},
+				BearerToken: "token",
+			},
+			wantHeader:    "signed",
+			wantAuth:      "Bearer token",
+			wantUserAgent: "custom-agent",
+		},
+		{
+			name:          "check user agent",
+			auth:          models.RequestAuth{UserAgent: "AppleCoreMedia/1.0"},
+			wantUserAgent: "AppleCoreMedia/1.0",
+		},
+		{
+			name:          "basic auth",
+			auth:          models.RequestAuth{BasicAuth: &models.BasicAuth{Username: "user", Password: "pass"}},
+			wantAuth:      "Basic dXNlcjpwYXNz",
+			wantUserAgent: "test-agent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewClient(models.HTTPConfig{
+				Timeout:   5 * time.Second,
+				UserAgent: "test-agent",
+			})
+
+			ctx := models.WithRequestAuth(context.Background(), tt.auth)
+			if _, err := client.GetPlaylist(ctx, server.URL); err != nil {
+				t.Fatalf("GetPlaylist() error = %v", err)
+			}
+			if _, err := client.GetSegment(ctx, server.URL, false); err != nil {
+				t.Fatalf("GetSegment() error = %v", err)
+			}
+
+			// Заголовки добавляются и к плейлистам, и к сегментам
+			if len(requests) != 2 {
+				t.Fatalf("got %d requests, want 2", len(requests))
+			}
+			for _, r := range requests {
+				if got := r.Header.Get("X-Origin-Key"); got != tt.wantHeader {
+					t.Errorf("%s X-Origin-Key = %q, want %q", r.Method, got, tt.wantHeader)
+				}
+				if got := r.Header.Get("Authorization"); got != tt.wantAuth {
+					t.Errorf("%s Authorization = %q, want %q", r.Method, got, tt.wantAuth)
+				}
+				if got := r.Header.Get("User-Agent"); got != tt.wantUserAgent {
+					t.Errorf("%s User-Agent = %q, want %q", r.Method, got, tt.wantUserAgent)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RequestIDHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		checkID string
+		want    string
+	}{
+		{name: "header configured", header: "X-Request-ID", checkID: "0123456789abcdef", want: "0123456789abcdef"},
+		{name: "header disabled", checkID: "0123456789abcdef"},
+		{name: "no check id", header: "X-Request-ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = append(got, r.Header.Get("X-Request-ID"))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second, RequestIDHeader: tt.header})
+			ctx := context.Background()
+			if tt.checkID != "" {
+				ctx = models.WithCheckID(ctx, tt.checkID)
+			}
+			if _, err := client.GetPlaylist(ctx, server.URL); err != nil {
+				t.Fatalf("GetPlaylist() error = %v", err)
+			}
+			if _, err := client.GetSegment(ctx, server.URL, false); err != nil {
+				t.Fatalf("GetSegment() error = %v", err)
+			}
+
+			for i, id := range got {
+				if id != tt.want {
+					t.Errorf("request %d X-Request-ID = %q, want %q", i, id, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_TraceParent(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("traceparent"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second})
+	if _, err := client.GetPlaylist(context.Background(), server.URL); err != nil {
+		t.Fatalf("GetPlaylist() error = %v", err)
+	}
+	ctx := models.WithTraceID(context.Background(), traceID)
+	for range 2 {
+		if _, err := client.GetPlaylist(ctx, server.URL); err != nil {
+			t.Fatalf("GetPlaylist() error = %v", err)
+		}
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("got %d requests, want 3", len(got))
+	}
+	if got[0] != "" {
+		t.Errorf("traceparent without trace id = %q, want empty", got[0])
+	}
+	for _, header := range got[1:] {
+		parts := strings.Split(header, "-")
+		if len(parts) != 4 || parts[0] != "00" || parts[1] != traceID || len(parts[2]) != 16 || parts[3] != "01" {
+			t.Errorf("traceparent = %q, want 00-%s-<span>-01", header, traceID)
+		}
+	}
+	if got[1] == got[2] {
+		t.Errorf("requests share parent span id: %q", got[1])
+	}
+}
+
+func TestClient_MaxSegmentBytes(t *testing.T) {
+	body := make([]byte, 188*100)
+	for i := 0; i < len(body); i += 188 {
+		body[i] = 0x47
+	}
+
+	tests := []struct {
+		name        string
+		limit       int64
+		chunked     bool
+		wantErr     bool
+		wantSize    int64
+		wantPackets int
+	}{
+		{name: "no limit", limit: 0, wantSize: int64(len(body)), wantPackets: 100},
+		{name: "within limit", limit: int64(len(body)), wantSize: int64(len(body)), wantPackets: 100},
+		{name: "content length over limit", limit: 1000, wantErr: true},
+		{name: "chunked over limit", limit: 1000, chunked: true, wantErr: true},
+		{name: "chunked size from body", limit: 0, chunked: true, wantSize: int64(len(body)), wantPackets: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.chunked {
+					// Без Content-Length ответ передается по частям
+					w.(http.Flusher).Flush()
+				}
+				if _, err := w.Write(body); err != nil {
+					t.Errorf("Failed to write response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second, MaxSegmentBytes: tt.limit})
+			resp, err := client.GetSegment(context.Background(), server.URL, true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSegment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if resp.Size != tt.wantSize {
+				t.Errorf("GetSegment() size = %d, want %d", resp.Size, tt.wantSize)
+			}
+			if resp.MediaInfo.Packets != tt.wantPackets {
+				t.Errorf("GetSegment() packets = %d, want %d", resp.MediaInfo.Packets, tt.wantPackets)
+			}
+		})
+	}
+}
+
+func TestClient_GetKey(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	large := []byte(strings.Repeat("k", maxKeyBytes+1))
+
+	tests := []struct {
+		name    string
+		body    []byte
+		chunked bool
+		wantErr bool
+	}{
+		{name: "aes-128 key", body: key},
+		{name: "content length over limit", body: large, wantErr: true},
+		{name: "chunked over limit", body: large, chunked: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.chunked {
+					// Без Content-Length размер проверяется при чтении
+					w.(http.Flusher).Flush()
+				}
+				if _, err := w.Write(tt.body); err != nil {
+					t.Errorf("Failed to write response: %v", err)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second})
+			resp, err := client.GetKey(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(resp.Body) != string(tt.body) {
+				t.Errorf("GetKey() body = %q, want %q", resp.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestClient_SegmentRange(t *testing.T) {
+	body := make([]byte, 188*100)
+	for i := 0; i < len(body); i += 188 {
+		body[i] = 0x47
+	}
+
+	tests := []struct {
+		name        string
+		honorRange  bool
+		wantSize    int64
+		wantPackets int
+	}{
+		{name: "partial content", honorRange: true, wantSize: int64(len(body)), wantPackets: 10},
+		// Источник без поддержки Range: читаем только начало полного ответа
+		{name: "range ignored", honorRange: false, wantSize: int64(len(body)), wantPackets: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRange = r.Header.Get("Range")
+				if !tt.honorRange {
+					w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+					_, _ = w.Write(body)
+					return
+				}
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-1879/%d", len(body)))
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write(body[:1880])
+			}))
+			defer server.Close()
+
+			client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second, MaxSegmentBytes: 1000})
+			ctx := models.WithSegmentRange(context.Background(), 1880)
+			resp, err := client.GetSegment(ctx, server.URL, true)
+			if err != nil {
+				t.Fatalf("GetSegment() error = %v", err)
+			}
+			if gotRange != "bytes=0-1879" {
+				t.Errorf("Range = %q, want %q", gotRange, "bytes=0-1879")
+			}
+			if resp.Size != tt.wantSize {
+				t.Errorf("GetSegment() size = %d, want %d", resp.Size, tt.wantSize)
+			}
+			if resp.MediaInfo.Packets != tt.wantPackets {
+				t.Errorf("GetSegment() packets = %d, want %d", resp.MediaInfo.Packets, tt.wantPackets)
+			}
+		})
+	}
+
+	// HEAD-проверка не использует Range
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second})
+	if _, err := client.GetSegment(models.WithSegmentRange(context.Background(), 1880), server.URL, false); err != nil {
+		t.Errorf("GetSegment() HEAD error = %v", err)
+	}
+}
+
+func TestContentRangeSize(t *testing.T) {
+	tests := map[string]int64{
+		"bytes 0-1023/146515": 146515,
+		"bytes 0-1023/*":      0,
+		"":                    0,
+		"bytes 0-1023/abc":    0,
+	}
+	for header, want := range tests {
+		if got := contentRangeSize(header); got != want {
+			t.Errorf("contentRangeSize(%q) = %d, want %d", header, got, want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status int
+		header string
+		want   time.Duration
+	}{
+		{name: "seconds", status: http.StatusTooManyRequests, header: "120", want: 2 * time.Minute},
+		{name: "http date", status: http.StatusServiceUnavailable, header: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second},
+		{name: "date in the past", status: http.StatusServiceUnavailable, header: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		{name: "invalid", status: http.StatusTooManyRequests, header: "soon", want: 0},
+		{name: "missing", status: http.StatusTooManyRequests, want: 0},
+		{name: "other status", status: http.StatusForbidden, header: "120", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			if got := retryAfter(resp, now); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second})
+
+	playlist, err := client.GetPlaylist(context.Background(), server.URL)
+	if err == nil || playlist == nil || playlist.RetryAfter != 7*time.Second {
+		t.Errorf("GetPlaylist() = %+v, %v, want RetryAfter 7s", playlist, err)
+	}
+	segment, err := client.GetSegment(context.Background(), server.URL, true)
+	if err == nil || segment == nil || segment.RetryAfter != 7*time.Second {
+		t.Errorf("GetSegment() = %+v, %v, want RetryAfter 7s", segment, err)
+	}
+}
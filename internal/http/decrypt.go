@@ -0,0 +1,98 @@
+package http
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// cbcReader расшифровывает AES-128-CBC на лету: последний блок придерживается
+// до конца потока, чтобы снять с него дополнение PKCS7
+type cbcReader struct {
+	src  io.Reader
+	mode cipher.BlockMode
+	// whole поток содержит сегмент целиком: проверяются выравнивание и дополнение,
+	// иначе (выборка Range) неполный хвост отбрасывается
+	whole bool
+
+	buf  []byte
+	in   []byte
+	out  []byte
+	held []byte
+	done bool
+}
+
+// newDecryptReader оборачивает тело зашифрованного сегмента
+func newDecryptReader(src io.Reader, key models.SegmentKey, whole bool) (io.Reader, error) {
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	if len(key.IV) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid IV length: %d", len(key.IV))
+	}
+	return &cbcReader{
+		src:   src,
+		mode:  cipher.NewCBCDecrypter(block, key.IV),
+		whole: whole,
+		buf:   make([]byte, 32*1024),
+	}, nil
+}
+
+func (r *cbcReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, err := r.src.Read(r.buf)
+		r.in = append(r.in, r.buf[:n]...)
+		if full := len(r.in) - len(r.in)%aes.BlockSize; full > 0 {
+			plain := make([]byte, len(r.held)+full)
+			copy(plain, r.held)
+			r.mode.CryptBlocks(plain[len(r.held):], r.in[:full])
+			r.in = append(r.in[:0], r.in[full:]...)
+			split := len(plain) - aes.BlockSize
+			r.out, r.held = plain[:split], plain[split:]
+		}
+		if errors.Is(err, io.EOF) {
+			r.done = true
+			if err := r.finish(); err != nil {
+				return 0, err
+			}
+		} else if err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// finish отдает придержанный блок, для сегмента целиком - без дополнения PKCS7
+func (r *cbcReader) finish() error {
+	if !r.whole {
+		r.out = append(r.out, r.held...)
+		return nil
+	}
+	if len(r.in) != 0 {
+		return fmt.Errorf("encrypted segment is not a multiple of block size")
+	}
+	if len(r.held) == 0 {
+		return nil
+	}
+	pad := int(r.held[len(r.held)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return fmt.Errorf("invalid PKCS7 padding")
+	}
+	for _, b := range r.held[len(r.held)-pad:] {
+		if int(b) != pad {
+			return fmt.Errorf("invalid PKCS7 padding")
+		}
+	}
+	r.out = append(r.out, r.held[:len(r.held)-pad]...)
+	return nil
+}
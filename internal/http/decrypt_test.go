@@ -0,0 +1,74 @@
+package http
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptCBC шифрует данные AES-128-CBC с дополнением PKCS7
+func encryptCBC(t *testing.T, key models.SegmentKey, plain []byte) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key.Key)
+	require.NoError(t, err)
+	pad := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(bytes.Clone(plain), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	out := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, key.IV).CryptBlocks(out, padded)
+	return out
+}
+
+func TestDecryptReader(t *testing.T) {
+	key := models.SegmentKey{Key: bytes.Repeat([]byte{7}, 16), IV: bytes.Repeat([]byte{1}, 16)}
+
+	for _, size := range []int{0, 1, 15, 16, 188 * 100} {
+		plain := bytes.Repeat([]byte{0x47, 0x10}, size)[:size]
+		encrypted := encryptCBC(t, key, plain)
+
+		// Чтение мелкими порциями проверяет перенос неполных блоков между вызовами
+		r, err := newDecryptReader(iotest.OneByteReader(bytes.NewReader(encrypted)), key, true)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, plain, got, "size %d", size)
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		encrypted := encryptCBC(t, key, []byte("segment"))
+		wrong := models.SegmentKey{Key: bytes.Repeat([]byte{8}, 16), IV: key.IV}
+		r, err := newDecryptReader(bytes.NewReader(encrypted), wrong, true)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.ErrorContains(t, err, "invalid PKCS7 padding")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		encrypted := encryptCBC(t, key, bytes.Repeat([]byte{0x47}, 100))
+		r, err := newDecryptReader(bytes.NewReader(encrypted[:40]), key, true)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.ErrorContains(t, err, "not a multiple of block size")
+	})
+
+	t.Run("range sample", func(t *testing.T) {
+		plain := bytes.Repeat([]byte{0x47}, 100)
+		encrypted := encryptCBC(t, key, plain)
+		r, err := newDecryptReader(bytes.NewReader(encrypted[:40]), key, false)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, plain[:32], got)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := newDecryptReader(bytes.NewReader(nil), models.SegmentKey{Key: []byte("short")}, true)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// buildTLSConfig собирает tls.Config из параметров конфигурации.
+// Для параметров по умолчанию возвращает nil: используются настройки Go.
+func buildTLSConfig(cfg models.TLSConfig) (*tls.Config, error) {
+	if cfg == (models.TLSConfig{}) {
+		return nil, nil
+	}
+
+	minVersion, err := cfg.ParseMinTLSVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         minVersion,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, // #nosec G402 -- включается явно в конфигурации
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca_file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA удостоверяющий центр для выпуска клиентских сертификатов в тестах
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issueClientCert выпускает клиентский сертификат и сохраняет его с ключом в PEM-файлы
+func (ca *testCA) issueClientCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "hls_exporter"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = writePEM(t, dir, "client.pem", "CERTIFICATE", der)
+	keyFile = writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("#EXTM3U"))
+	})
+}
+
+func TestClient_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(okHandler())
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	tests := []struct {
+		name    string
+		config  models.HTTPConfig
+		wantErr bool
+	}{
+		{
+			name:    "unknown authority",
+			config:  models.HTTPConfig{TLSVerify: true},
+			wantErr: true,
+		},
+		{
+			name:   "custom CA",
+			config: models.HTTPConfig{TLSVerify: true, TLSConfig: models.TLSConfig{CAFile: caFile}},
+		},
+		{
+			name:   "insecure skip verify",
+			config: models.HTTPConfig{TLSVerify: true, TLSConfig: models.TLSConfig{InsecureSkipVerify: true}},
+		},
+		{
+			name:   "tls_verify disabled",
+			config: models.HTTPConfig{TLSVerify: false},
+		},
+		{
+			name:    "missing CA file",
+			config:  models.HTTPConfig{TLSVerify: true, TLSConfig: models.TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Timeout = 5 * time.Second
+			client := NewClient(tt.config)
+			defer client.Close()
+
+			_, err := client.GetPlaylist(context.Background(), server.URL)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(okHandler())
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	certFile, keyFile := ca.issueClientCert(t, dir)
+
+	// Параметры по умолчанию доверяют серверу, но без клиентского сертификата
+	client := NewClient(models.HTTPConfig{
+		Timeout:   5 * time.Second,
+		TLSVerify: true,
+		TLSConfig: models.TLSConfig{CAFile: caFile},
+	})
+	defer client.Close()
+
+	_, err := client.GetPlaylist(context.Background(), server.URL)
+	assert.Error(t, err)
+
+	// Сертификат стрима дополняет параметры по умолчанию
+	ctx := models.WithStreamTLS(context.Background(), &models.TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	_, err = client.GetPlaylist(ctx, server.URL)
+	assert.NoError(t, err)
+
+	// Ошибка загрузки файлов стрима не затрагивает остальные стримы
+	ctx = models.WithStreamTLS(context.Background(), &models.TLSConfig{CertFile: caFile, KeyFile: caFile})
+	_, err = client.GetPlaylist(ctx, server.URL)
+	assert.ErrorContains(t, err, "tls config")
+}
+
+func TestClient_MinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(okHandler())
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second})
+	defer client.Close()
+
+	_, err := client.GetPlaylist(context.Background(), server.URL)
+	assert.NoError(t, err)
+
+	ctx := models.WithStreamTLS(context.Background(), &models.TLSConfig{MinTLSVersion: "1.3"})
+	_, err = client.GetPlaylist(ctx, server.URL)
+	assert.Error(t, err)
+}
+
+func TestClient_Resolve(t *testing.T) {
+	var hosts []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.Host)
+		_, _ = w.Write([]byte("#EXTM3U"))
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	caFile := writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+
+	client := NewClient(models.HTTPConfig{
+		Timeout:   5 * time.Second,
+		TLSVerify: true,
+		TLSConfig: models.TLSConfig{CAFile: caFile},
+	})
+	defer client.Close()
+
+	// Сертификат тестового сервера выдан на example.com: проверка проходит,
+	// только если SNI и Host сохраняются при подмене адреса
+	url := "https://example.com:" + port + "/master.m3u8"
+	ctx := models.WithResolve(context.Background(), []string{"example.com:" + port + ":127.0.0.1"})
+	_, err = client.GetPlaylist(ctx, url)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com:" + port}, hosts)
+
+	ctx = models.WithResolve(context.Background(), []string{"example.com:" + port + ":edge"})
+	_, err = client.GetPlaylist(ctx, url)
+	assert.ErrorContains(t, err, "resolve")
+}
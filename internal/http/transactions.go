@@ -0,0 +1,94 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// redactedHeaders заголовки с учетными данными, не попадающие в журнал транзакций
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactHeaders копирует заголовки, заменяя учетные данные
+func redactHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	clone := h.Clone()
+	for _, name := range redactedHeaders {
+		if _, ok := clone[name]; ok {
+			clone[name] = []string{"REDACTED"}
+		}
+	}
+	return clone
+}
+
+// doRecorded выполняет запрос и записывает транзакцию в журнал. Транзакция с ответом
+// добавляется при закрытии тела, чтобы учесть время его чтения.
+func doRecorded(client *http.Client, req *http.Request, log *models.TransactionLog) (*http.Response, error) {
+	tx := models.HTTPTransaction{
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		Start:          time.Now(),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+
+	resp, err := client.Do(req)
+	tx.FirstByte = time.Since(tx.Start)
+	if err != nil {
+		tx.Duration = tx.FirstByte
+		tx.Error = err.Error()
+		log.Add(tx)
+		return nil, err
+	}
+
+	tx.StatusCode = resp.StatusCode
+	tx.ResponseHeaders = redactHeaders(resp.Header)
+	resp.Body = &recordedBody{ReadCloser: resp.Body, tx: tx, log: log}
+	return resp, nil
+}
+
+// recordedBody сохраняет начало тела ответа и добавляет транзакцию в журнал при закрытии
+type recordedBody struct {
+	io.ReadCloser
+	tx   models.HTTPTransaction
+	log  *models.TransactionLog
+	buf  bytes.Buffer
+	once sync.Once
+}
+
+func (b *recordedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.tx.BodyBytes += int64(n)
+	if rest := b.log.MaxBodyBytes() - b.buf.Len(); rest > 0 {
+		b.buf.Write(p[:min(n, rest)])
+	}
+	return n, err
+}
+
+func (b *recordedBody) Close() error {
+	// Клиент не читает тело ответа с ошибкой, а для поставщика CDN оно часто важнее всего
+	if b.tx.StatusCode >= http.StatusBadRequest && b.tx.BodyBytes == 0 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(b, int64(b.log.MaxBodyBytes())))
+	}
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.tx.Duration = time.Since(b.tx.Start)
+		body := b.buf.Bytes()
+		b.tx.BodyTruncated = b.tx.BodyBytes > int64(len(body))
+		// Обрезка могла разделить последний символ UTF-8
+		for i := 0; b.tx.BodyTruncated && i < utf8.UTFMax-1 && len(body) > 0 && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+		if utf8.Valid(body) {
+			b.tx.Body = string(body)
+		}
+		b.log.Add(b.tx)
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+func TestClient_TransactionLog(t *testing.T) {
+	playlist := "#EXTM3U\n#EXT-X-TARGETDURATION:6\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".ts") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/down.m3u8") {
+			http.Error(w, "origin down", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("X-Cache", "HIT")
+		_, _ = w.Write([]byte(playlist))
+	}))
+	defer server.Close()
+
+	client := NewClient(models.HTTPConfig{Timeout: 5 * time.Second})
+	log := models.NewTransactionLog(10)
+	ctx := models.WithTransactionLog(context.Background(), log)
+	ctx = models.WithRequestAuth(ctx, models.RequestAuth{BearerToken: "secret"})
+
+	if _, err := client.GetPlaylist(ctx, server.URL+"/index.m3u8"); err != nil {
+		t.Fatalf("GetPlaylist() error = %v", err)
+	}
+	if _, err := client.GetSegment(ctx, server.URL+"/seg1.ts", false); err == nil {
+		t.Fatal("GetSegment() expected error for 404")
+	}
+	if _, err := client.GetPlaylist(ctx, server.URL+"/down.m3u8"); err == nil {
+		t.Fatal("GetPlaylist() expected error for 502")
+	}
+	// Запрос без журнала в контексте не записывается
+	if _, err := client.GetPlaylist(context.Background(), server.URL+"/index.m3u8"); err != nil {
+		t.Fatalf("GetPlaylist() error = %v", err)
+	}
+
+	transactions := log.Transactions()
+	if len(transactions) != 3 {
+		t.Fatalf("got %d transactions, want 3", len(transactions))
+	}
+
+	pl := transactions[0]
+	if pl.Method != http.MethodGet || pl.StatusCode != http.StatusOK || pl.ResponseHeaders.Get("X-Cache") != "HIT" {
+		t.Errorf("playlist transaction = %+v", pl)
+	}
+	if pl.Body != playlist[:10] || !pl.BodyTruncated || pl.BodyBytes != int64(len(playlist)) {
+		t.Errorf("playlist body = %q (truncated %v, %d bytes), want first 10 bytes of %d",
+			pl.Body, pl.BodyTruncated, pl.BodyBytes, len(playlist))
+	}
+	if got := pl.RequestHeaders.Get("Authorization"); got != "REDACTED" {
+		t.Errorf("Authorization header = %q, want REDACTED", got)
+	}
+	if pl.Duration < pl.FirstByte {
+		t.Errorf("duration %v is less than time to first byte %v", pl.Duration, pl.FirstByte)
+	}
+
+	seg := transactions[1]
+	if seg.Method != http.MethodHead || seg.StatusCode != http.StatusNotFound {
+		t.Errorf("segment transaction = %+v", seg)
+	}
+
+	// Тело ответа с ошибкой сохраняется, хотя клиент его не читает
+	if down := transactions[2]; down.StatusCode != http.StatusBadGateway || down.Body != "origin dow" {
+		t.Errorf("error transaction = %+v", down)
+	}
+}
+
+func TestClient_TransactionLogError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := NewClient(models.HTTPConfig{Timeout: time.Second})
+	log := models.NewTransactionLog(10)
+	ctx := models.WithTransactionLog(context.Background(), log)
+	if _, err := client.GetPlaylist(ctx, server.URL); err == nil {
+		t.Fatal("GetPlaylist() expected connection error")
+	}
+
+	transactions := log.Transactions()
+	if len(transactions) != 1 || transactions[0].Error == "" || transactions[0].StatusCode != 0 {
+		t.Errorf("transactions = %+v, want one failed request", transactions)
+	}
+}
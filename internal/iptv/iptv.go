@@ -0,0 +1,203 @@
+// Package iptv разбирает списки каналов IPTV в формате M3U (#EXTM3U с #EXTINF
+// на каждый канал) и преобразует их в конфигурации стримов.
+package iptv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Channel канал списка IPTV
+type Channel struct {
+	// Название канала после запятой в #EXTINF (или tvg-name)
+	Name string
+	URL  string
+	// Группа из атрибута group-title или тега #EXTGRP
+	Group string
+	// Атрибуты #EXTINF: tvg-id, tvg-logo и другие
+	Attrs map[string]string
+}
+
+// Parse читает список каналов. Строки без предшествующего #EXTINF тоже считаются каналами,
+// их название берется из URL.
+func Parse(r io.Reader) ([]Channel, error) {
+	var (
+		channels []Channel
+		pending  *Channel
+		lineNum  int
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			ch, err := parseExtinf(strings.TrimPrefix(line, "#EXTINF:"))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			pending = &ch
+		case strings.HasPrefix(line, "#EXTGRP:"):
+			if pending != nil && pending.Group == "" {
+				pending.Group = strings.TrimSpace(strings.TrimPrefix(line, "#EXTGRP:"))
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			ch := Channel{}
+			if pending != nil {
+				ch = *pending
+			}
+			ch.URL = line
+			if ch.Name == "" {
+				ch.Name = nameFromURL(line)
+			}
+			channels = append(channels, ch)
+			pending = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// parseExtinf разбирает значение #EXTINF: `-1 tvg-id="id" group-title="News",Channel Name`.
+// Название отделяется первой запятой вне кавычек.
+func parseExtinf(value string) (Channel, error) {
+	inQuotes := false
+	split := -1
+	for i, r := range value {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ',' && !inQuotes {
+			split = i
+			break
+		}
+	}
+	if split < 0 {
+		return Channel{}, fmt.Errorf("EXTINF without channel name: %q", value)
+	}
+
+	attrs, err := parseAttrs(value[:split])
+	if err != nil {
+		return Channel{}, err
+	}
+	ch := Channel{
+		Name:  strings.TrimSpace(value[split+1:]),
+		Group: attrs["group-title"],
+		Attrs: attrs,
+	}
+	if ch.Name == "" {
+		ch.Name = attrs["tvg-name"]
+	}
+	return ch, nil
+}
+
+// parseAttrs разбирает атрибуты вида key="value" после длительности
+func parseAttrs(s string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	// Длительность (-1 или 0) перед атрибутами
+	_, rest, _ := strings.Cut(strings.TrimSpace(s), " ")
+	for {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return attrs, nil
+		}
+		key, after, found := strings.Cut(rest, "=")
+		if !found {
+			return nil, fmt.Errorf("malformed EXTINF attribute: %q", rest)
+		}
+		key = strings.TrimSpace(key)
+		if !strings.HasPrefix(after, `"`) {
+			// Значение без кавычек до пробела
+			value, next, _ := strings.Cut(after, " ")
+			attrs[key] = value
+			rest = next
+			continue
+		}
+		end := strings.IndexByte(after[1:], '"')
+		if end < 0 {
+			return nil, fmt.Errorf("EXTINF attribute %s has unterminated quoted string", key)
+		}
+		attrs[key] = after[1 : end+1]
+		rest = after[end+2:]
+	}
+}
+
+// nameFromURL строит название канала по последним элементам пути URL
+func nameFromURL(rawURL string) string {
+	path, _, _ := strings.Cut(rawURL, "?")
+	path = strings.TrimSuffix(path, "/")
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if ext := strings.LastIndexByte(name, '.'); ext > 0 {
+		name = name[:ext]
+		// index.m3u8, playlist.m3u8: название по каталогу
+		if (name == "index" || name == "playlist" || name == "master") && len(parts) > 1 {
+			name = parts[len(parts)-2]
+		}
+	}
+	return name
+}
+
+// StreamName преобразует название канала в имя стрима: строчные буквы и цифры,
+// остальные символы заменяются подчеркиванием
+func StreamName(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// Streams создает конфигурации стримов по каналам на основе шаблона. Имена стримов
+// уникальны: к повторяющимся добавляется номер, а уже занятые имена из taken пропускаются.
+func Streams(channels []Channel, template models.StreamConfig, taken []string) []models.StreamConfig {
+	used := make(map[string]bool, len(taken)+len(channels))
+	for _, name := range taken {
+		used[name] = true
+	}
+
+	streams := make([]models.StreamConfig, 0, len(channels))
+	for i, ch := range channels {
+		base := StreamName(ch.Name)
+		if base == "" {
+			base = "channel_" + strconv.Itoa(i+1)
+		}
+		name := base
+		for n := 2; used[name]; n++ {
+			name = base + "_" + strconv.Itoa(n)
+		}
+		used[name] = true
+
+		stream := template
+		stream.Name = name
+		stream.URL = ch.URL
+		stream.Group = ch.Group
+		streams = append(streams, stream)
+	}
+	return streams
+}
@@ -0,0 +1,78 @@
+package iptv
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelList = "\ufeff#EXTM3U x-tvg-url=\"http://epg.example.com/guide.xml\"\n" + `
+#EXTINF:-1 tvg-id="news.1" tvg-logo="http://example.com/logo.png" group-title="News, Russia",Первый канал HD
+http://cdn.example.com/first/index.m3u8
+#EXTINF:0 tvg-name="Sports Extra" group-title=Sports,
+#EXTVLCOPT:http-user-agent=Player
+http://cdn.example.com/sports/playlist.m3u8?token=abc
+#EXTINF:-1,Music
+#EXTGRP:Entertainment
+http://cdn.example.com/music.m3u8
+http://cdn.example.com/bare/master.m3u8
+`
+
+func TestParse(t *testing.T) {
+	channels, err := Parse(strings.NewReader(channelList))
+	require.NoError(t, err)
+	require.Len(t, channels, 4)
+
+	assert.Equal(t, "Первый канал HD", channels[0].Name)
+	assert.Equal(t, "http://cdn.example.com/first/index.m3u8", channels[0].URL)
+	assert.Equal(t, "News, Russia", channels[0].Group)
+	assert.Equal(t, "news.1", channels[0].Attrs["tvg-id"])
+
+	// Пустое название берется из tvg-name, значение без кавычек до пробела
+	assert.Equal(t, "Sports Extra", channels[1].Name)
+	assert.Equal(t, "Sports", channels[1].Group)
+
+	assert.Equal(t, "Entertainment", channels[2].Group)
+
+	// URL без #EXTINF: название по каталогу
+	assert.Equal(t, "bare", channels[3].Name)
+	assert.Empty(t, channels[3].Group)
+}
+
+func TestParse_Malformed(t *testing.T) {
+	_, err := Parse(strings.NewReader("#EXTM3U\n#EXTINF:-1 tvg-id=\"x\n"))
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = Parse(strings.NewReader("#EXTM3U\n#EXTINF:-1 no name\n"))
+	assert.ErrorContains(t, err, "without channel name")
+}
+
+func TestStreamName(t *testing.T) {
+	assert.Equal(t, "первый_канал_hd", StreamName("Первый канал HD"))
+	assert.Equal(t, "cnn_international", StreamName("  CNN (International) "))
+	assert.Empty(t, StreamName("!!!"))
+}
+
+func TestStreams(t *testing.T) {
+	channels := []Channel{
+		{Name: "News", URL: "http://a/news.m3u8", Group: "News"},
+		{Name: "news", URL: "http://b/news.m3u8"},
+		{Name: "???", URL: "http://c/x.m3u8"},
+		{Name: "Sport", URL: "http://d/sport.m3u8"},
+	}
+	template := models.StreamConfig{CheckMode: models.CheckModeFirstLast, Interval: time.Minute}
+
+	streams := Streams(channels, template, []string{"sport"})
+	require.Len(t, streams, 4)
+	assert.Equal(t, "news", streams[0].Name)
+	assert.Equal(t, "news_2", streams[1].Name)
+	assert.Equal(t, "channel_3", streams[2].Name)
+	assert.Equal(t, "sport_2", streams[3].Name)
+	assert.Equal(t, "http://b/news.m3u8", streams[1].URL)
+	assert.Equal(t, time.Minute, streams[1].Interval)
+	assert.Equal(t, "News", streams[0].Group)
+}
@@ -0,0 +1,148 @@
+// Package labels разбирает выражения сопоставления меток стримов в стиле
+// Alertmanager (stream=~"news_.*") для правил silences и областей видимости API.
+package labels
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Метки, доступные у каждого стрима
+const (
+	Stream    = "stream"
+	URL       = "url"
+	Profile   = "profile"
+	Group     = "group"
+	CheckMode = "check_mode"
+)
+
+// StreamLabels возвращает встроенные и пользовательские (labels) метки стрима
+func StreamLabels(stream models.StreamConfig) map[string]string {
+	values := make(map[string]string, len(stream.Labels)+5)
+	maps.Copy(values, stream.Labels)
+	values[Stream] = stream.Name
+	values[URL] = stream.URL
+	values[Profile] = stream.Profile
+	values[Group] = stream.Group
+	values[CheckMode] = stream.CheckMode
+	return values
+}
+
+// Known сообщает, что метка есть у стримов
+func Known(label string) bool {
+	switch label {
+	case Stream, URL, Profile, Group, CheckMode:
+		return true
+	}
+	return false
+}
+
+// Configured возвращает проверку имен меток селекторов: встроенные метки и
+// пользовательские метки стримов конфигурации
+func Configured(streams []models.StreamConfig) func(label string) bool {
+	custom := make(map[string]bool)
+	for _, stream := range streams {
+		for name := range stream.Labels {
+			custom[name] = true
+		}
+	}
+	return func(label string) bool {
+		return Known(label) || custom[label]
+	}
+}
+
+// Matcher выражение сравнения метки со значением
+type Matcher struct {
+	Label string
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+// ParseMatcher разбирает выражение вида label="value". Операторы: =, !=, =~, !~.
+// Регулярные выражения сопоставляются со значением целиком, как в Alertmanager.
+func ParseMatcher(expr string) (Matcher, error) {
+	i := strings.IndexAny(expr, "=!")
+	if i <= 0 {
+		return Matcher{}, fmt.Errorf("invalid matcher %q", expr)
+	}
+	m := Matcher{Label: strings.TrimSpace(expr[:i])}
+
+	rest := expr[i:]
+	for _, op := range []string{"=~", "!~", "!=", "="} {
+		if strings.HasPrefix(rest, op) {
+			m.Op = op
+			break
+		}
+	}
+	if m.Op == "" {
+		return Matcher{}, fmt.Errorf("invalid matcher %q", expr)
+	}
+
+	value := strings.TrimSpace(rest[len(m.Op):])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return Matcher{}, fmt.Errorf("invalid matcher %q: %w", expr, err)
+		}
+		value = unquoted
+	}
+	m.Value = value
+
+	if m.Op == "=~" || m.Op == "!~" {
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return Matcher{}, fmt.Errorf("invalid matcher %q: %w", expr, err)
+		}
+		m.re = re
+	}
+	return m, nil
+}
+
+// Matches сравнивает значение метки
+func (m Matcher) Matches(value string) bool {
+	switch m.Op {
+	case "=":
+		return value == m.Value
+	case "!=":
+		return value != m.Value
+	case "=~":
+		return m.re.MatchString(value)
+	default:
+		return !m.re.MatchString(value)
+	}
+}
+
+// Selector набор выражений, которые должны совпасть все. Пустой селектор совпадает с любыми метками.
+type Selector []Matcher
+
+// ParseSelector разбирает выражения селектора. known проверяет имена меток (nil - любые).
+func ParseSelector(exprs []string, known func(label string) bool) (Selector, error) {
+	selector := make(Selector, 0, len(exprs))
+	for _, expr := range exprs {
+		m, err := ParseMatcher(expr)
+		if err != nil {
+			return nil, err
+		}
+		if known != nil && !known(m.Label) {
+			return nil, fmt.Errorf("invalid matcher %q: unknown label %s", expr, m.Label)
+		}
+		selector = append(selector, m)
+	}
+	return selector, nil
+}
+
+// Matches сравнивает селектор с метками
+func (s Selector) Matches(values map[string]string) bool {
+	for _, m := range s {
+		if !m.Matches(values[m.Label]) {
+			return false
+		}
+	}
+	return true
+}
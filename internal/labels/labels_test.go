@@ -0,0 +1,77 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMatcher(t *testing.T) {
+	tests := []struct {
+		expr    string
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{expr: `stream="news"`, value: "news", want: true},
+		{expr: `stream = news`, value: "sport", want: false},
+		{expr: `stream!="news"`, value: "sport", want: true},
+		{expr: `stream=~"news_.*"`, value: "news_hd", want: true},
+		{expr: `stream=~"news"`, value: "news_hd", want: false},
+		{expr: `error_type!~"segment_.*"`, value: "playlist_download", want: true},
+		{expr: `stream`, wantErr: true},
+		{expr: `stream=~"("`, wantErr: true},
+		{expr: `stream="news`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			m, err := ParseMatcher(tt.expr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, m.Matches(tt.value))
+		})
+	}
+}
+
+func TestSelector(t *testing.T) {
+	_, err := ParseSelector([]string{`tenant="a"`}, Known)
+	assert.ErrorContains(t, err, "unknown label tenant")
+
+	selector, err := ParseSelector([]string{`stream=~"news_.*"`, `check_mode!="all"`}, Known)
+	require.NoError(t, err)
+	assert.True(t, selector.Matches(map[string]string{Stream: "news_hd", CheckMode: "random"}))
+	assert.False(t, selector.Matches(map[string]string{Stream: "news_hd", CheckMode: "all"}))
+
+	// Пустой селектор совпадает с любыми метками
+	assert.True(t, Selector{}.Matches(nil))
+}
+
+func TestStreamLabels(t *testing.T) {
+	selector, err := ParseSelector([]string{`group="news"`}, Known)
+	require.NoError(t, err)
+	assert.True(t, selector.Matches(StreamLabels(models.StreamConfig{Name: "first", Group: "news"})))
+	assert.False(t, selector.Matches(StreamLabels(models.StreamConfig{Name: "sport"})))
+}
+
+func TestStreamLabels_Custom(t *testing.T) {
+	stream := models.StreamConfig{Name: "news", Labels: map[string]string{"tenant": "media", Stream: "other"}}
+	values := StreamLabels(stream)
+	assert.Equal(t, "media", values["tenant"])
+	// Встроенные метки имеют приоритет
+	assert.Equal(t, "news", values[Stream])
+
+	known := Configured([]models.StreamConfig{stream})
+	assert.True(t, known("tenant"))
+	assert.True(t, known(Group))
+	assert.False(t, known("region"))
+
+	selector, err := ParseSelector([]string{`tenant="media"`}, known)
+	require.NoError(t, err)
+	assert.True(t, selector.Matches(values))
+	assert.False(t, selector.Matches(StreamLabels(models.StreamConfig{Name: "sport"})))
+}
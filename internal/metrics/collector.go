@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/iudanet/hls_exporter/pkg/models"
@@ -13,34 +14,230 @@ const (
 	namespace = "hls"
 
 	// Метрики
-	MetricStreamUp        = namespace + "_stream_up"
-	MetricResponseTime    = namespace + "_response_time_seconds"
-	MetricErrorsTotal     = namespace + "_errors_total"
-	MetricLastCheck       = namespace + "_last_check_timestamp"
-	MetricSegmentsChecked = namespace + "_segments_checked_total"
+	MetricStreamUp             = namespace + "_stream_up"
+	MetricDownReason           = namespace + "_stream_down_reason"
+	MetricStreamInfo           = namespace + "_stream_info"
+	MetricResponseTime         = namespace + "_response_time_seconds"
+	MetricStageDuration        = namespace + "_check_stage_duration_seconds"
+	MetricCheckDuration        = namespace + "_check_duration_seconds"
+	MetricSegmentSize          = namespace + "_segment_size_bytes"
+	MetricSegmentDuration      = namespace + "_segment_duration_seconds"
+	MetricSegmentDownloadRatio = namespace + "_segment_download_ratio"
+	MetricWorkerBusy           = namespace + "_worker_busy"
+	MetricQueueDepth           = namespace + "_queue_depth"
+	MetricQueueWait            = namespace + "_check_queue_wait_seconds"
+	MetricErrorsTotal          = namespace + "_errors_total"
+	MetricLastCheck            = namespace + "_last_check_timestamp"
+	MetricLastSuccess          = namespace + "_last_successful_check_timestamp"
+	MetricChecksTotal          = namespace + "_checks_total"
+	MetricSuccessRatio         = namespace + "_stream_success_ratio"
+	MetricSegmentsChecked      = namespace + "_segments_checked_total"
+	MetricDownloadedBytes      = namespace + "_downloaded_bytes_total"
+	MetricBudgetExceeded       = namespace + "_budget_exceeded"
+	MetricRenditionUp          = namespace + "_rendition_up"
+	MetricSchedulingDrift      = namespace + "_scheduling_drift_seconds"
+	MetricLiveEdgeLatency      = namespace + "_live_edge_latency_seconds"
+	MetricLiveEdgeEWMA         = namespace + "_live_edge_latency_seconds_ewma"
+	MetricClockSkew            = namespace + "_packager_clock_skew_seconds"
+	MetricBitrateEWMA          = namespace + "_stream_bitrate_bytes_ewma"
+	MetricConformance          = namespace + "_conformance_violations_total"
+	MetricSpecViolations       = namespace + "_spec_violations_total"
+	MetricEventViolations      = namespace + "_event_playlist_violations_total"
+	MetricPlaylistStale        = namespace + "_playlist_stale"
+	MetricStreamLive           = namespace + "_stream_live"
+	MetricTotalDuration        = namespace + "_playlist_duration_seconds"
+	MetricParseIssues          = namespace + "_parse_issues_total"
+	MetricUnknownTag           = namespace + "_unknown_tag_info"
+	MetricPIDChanges           = namespace + "_ts_pid_changes_total"
+	MetricCodecMismatch        = namespace + "_codec_mismatch_total"
+	MetricDiscontinuities      = namespace + "_playlist_discontinuities"
+	MetricGaps                 = namespace + "_playlist_gaps"
+	MetricDiscontEvents        = namespace + "_discontinuities_total"
+	MetricMediaSequence        = namespace + "_media_sequence"
+	MetricPlaylistAge          = namespace + "_playlist_age_seconds"
+	MetricErrorCauses          = namespace + "_error_causes_total"
+	MetricHostBudgetWait       = namespace + "_host_budget_wait_seconds_total"
+	MetricRequestLimitWait     = namespace + "_request_limit_wait_seconds_total"
+	MetricKeyCache             = namespace + "_key_cache_requests_total"
+	MetricHTTPResponses        = namespace + "_http_responses_total"
+	MetricRateLimited          = namespace + "_rate_limited_total"
+	MetricSessionData          = namespace + "_session_data_info"
+	MetricAdBreaks             = namespace + "_ad_breaks"
+	MetricAdLastCue            = namespace + "_ad_last_cue_timestamp_seconds"
+	MetricAdMalformed          = namespace + "_ad_markers_malformed"
+	MetricCCErrors             = namespace + "_ts_cc_errors_total"
+	MetricPCRInterval          = namespace + "_ts_pcr_interval_max_seconds"
+	MetricPCRJitter            = namespace + "_ts_pcr_jitter_seconds"
+	MetricNullPacketRatio      = namespace + "_ts_null_packet_ratio"
+	MetricLastDeepCheck        = namespace + "_last_deep_check_timestamp"
+	MetricCheckInterval        = namespace + "_check_interval_seconds"
+	MetricChecksSkipped        = namespace + "_checks_skipped_total"
+	MetricContentLooping       = namespace + "_content_looping"
+	MetricDASHUp               = namespace + "_dash_up"
+	MetricEdgeDivergence       = namespace + "_dash_live_edge_divergence_seconds"
+	MetricStreamSilenced       = namespace + "_stream_silenced"
+	MetricStreamDegraded       = namespace + "_stream_degraded"
+	MetricSLOViolation         = namespace + "_slo_violation"
+	MetricUserAgentUp          = namespace + "_user_agent_up"
+	MetricThreshold            = namespace + "_stream_threshold"
+
+	MetricVariantSegmentsChecked = namespace + "_variant_segments_checked_total"
+	MetricVariantResponseTime    = namespace + "_variant_response_time_seconds"
+	MetricVariantDeclaredBitrate = namespace + "_variant_declared_bitrate_bps"
+	MetricVariantMeasuredBitrate = namespace + "_variant_measured_bitrate_bps"
+	MetricVariantBitrateEWMA     = namespace + "_variant_measured_bitrate_bps_ewma"
+	MetricVariantBitrateRatio    = namespace + "_variant_bitrate_deviation_ratio"
+	MetricVariantSizeAnomaly     = namespace + "_variant_size_anomaly"
 )
 
 // Collector реализует интерфейс MetricsCollector
 type Collector struct {
-	streamUp        *prometheus.GaugeVec
-	responseTime    *prometheus.HistogramVec
-	errorsTotal     *prometheus.CounterVec
-	lastCheck       *prometheus.GaugeVec
-	segmentsChecked *prometheus.CounterVec
-	streamBitrate   *prometheus.GaugeVec // Добавляем
-	segmentsCount   *prometheus.GaugeVec // Добавляем
-	activeChecks    prometheus.Gauge     // Добавляем
+	streamUp             *prometheus.GaugeVec
+	downReason           *prometheus.GaugeVec
+	streamInfo           *prometheus.GaugeVec
+	responseTime         *prometheus.HistogramVec
+	checkDuration        *prometheus.HistogramVec
+	stageDuration        *prometheus.HistogramVec
+	errorsTotal          *prometheus.CounterVec
+	lastCheck            *prometheus.GaugeVec
+	lastSuccess          *prometheus.GaugeVec
+	checksTotal          *prometheus.CounterVec
+	successRatio         *prometheus.GaugeVec
+	segmentsChecked      *prometheus.CounterVec
+	streamBitrate        *prometheus.GaugeVec // Добавляем
+	segmentsCount        *prometheus.GaugeVec // Добавляем
+	activeChecks         prometheus.Gauge     // Добавляем
+	workerBusy           prometheus.Gauge
+	queueDepth           prometheus.Gauge
+	queueWait            *prometheus.HistogramVec
+	downloadedBytes      *prometheus.CounterVec
+	budgetExceeded       *prometheus.GaugeVec
+	renditionUp          *prometheus.GaugeVec
+	schedulingDrift      *prometheus.GaugeVec
+	liveEdgeLatency      *prometheus.GaugeVec
+	liveEdgeEWMA         *prometheus.GaugeVec
+	clockSkew            *prometheus.GaugeVec
+	bitrateEWMA          *prometheus.GaugeVec
+	conformance          *prometheus.CounterVec
+	specViolations       *prometheus.CounterVec
+	eventViolations      *prometheus.CounterVec
+	segmentSize          *prometheus.HistogramVec
+	segmentDuration      *prometheus.HistogramVec
+	segmentDownloadRatio *prometheus.HistogramVec
+	playlistStale        *prometheus.GaugeVec
+	streamLive           *prometheus.GaugeVec
+	totalDuration        *prometheus.GaugeVec
+	parseIssues          *prometheus.CounterVec
+	unknownTag           *prometheus.GaugeVec
+	pidChanges           *prometheus.CounterVec
+	codecMismatch        *prometheus.CounterVec
+	discontinuities      *prometheus.GaugeVec
+	gaps                 *prometheus.GaugeVec
+	discontEvents        *prometheus.CounterVec
+	mediaSequence        *prometheus.GaugeVec
+	playlistAge          *prometheus.GaugeVec
+	errorCauses          *prometheus.CounterVec
+	hostBudgetWait       *prometheus.CounterVec
+	requestLimitWait     prometheus.Counter
+	keyCache             *prometheus.CounterVec
+	httpResponses        *prometheus.CounterVec
+	rateLimited          *prometheus.CounterVec
+	sessionData          *prometheus.GaugeVec
+	adBreaks             *prometheus.GaugeVec
+	adLastCue            *prometheus.GaugeVec
+	adMalformed          *prometheus.GaugeVec
+	ccErrors             *prometheus.CounterVec
+	pcrInterval          *prometheus.GaugeVec
+	pcrJitter            *prometheus.GaugeVec
+	nullPacketRatio      *prometheus.GaugeVec
+	lastDeepCheck        *prometheus.GaugeVec
+	checkInterval        *prometheus.GaugeVec
+	checksSkipped        *prometheus.CounterVec
+	contentLooping       *prometheus.GaugeVec
+	dashUp               *prometheus.GaugeVec
+	edgeDivergence       *prometheus.GaugeVec
+	streamSilenced       *prometheus.GaugeVec
+	streamDegraded       *prometheus.GaugeVec
+	sloViolation         *prometheus.GaugeVec
+	userAgentUp          *prometheus.GaugeVec
+	threshold            *prometheus.GaugeVec
+
+	variantSegmentsChecked *prometheus.CounterVec
+	variantResponseTime    *prometheus.HistogramVec
+	variantDeclaredBitrate *prometheus.GaugeVec
+	variantMeasuredBitrate *prometheus.GaugeVec
+	variantBitrateEWMA     *prometheus.GaugeVec
+	variantBitrateRatio    *prometheus.GaugeVec
+	variantSizeAnomaly     *prometheus.GaugeVec
+
+	// Состояние сглаженных метрик *_ewma
+	ewma *ewma
+	// Пользовательские метки стримов, см. WithStreamLabels
+	labels *streamLabels
+	// Публиковать метку silenced, см. Options.SilencedLabel
+	silencedLabel bool
+	// Окна долей успешных проверок hls_stream_success_ratio
+	sla *slaTracker
+	// Все векторы метрик для удаления серий стрима в Reset
+	vecs []seriesDeleter
+}
+
+// seriesDeleter вектор метрик, из которого можно удалить серии по части меток
+type seriesDeleter interface {
+	DeletePartialMatch(labels prometheus.Labels) int
+}
+
+// trackingRegisterer запоминает регистрируемые векторы метрик
+type trackingRegisterer struct {
+	prometheus.Registerer
+	vecs []seriesDeleter
+}
+
+func (r *trackingRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if vec, ok := c.(seriesDeleter); ok {
+			r.vecs = append(r.vecs, vec)
+		}
+	}
+	r.Registerer.MustRegister(cs...)
 }
 
 var _ models.MetricsCollector = (*Collector)(nil)
 
-// NewCollector создает и регистрирует все метрики
+// DefaultCheckDurationBuckets границы гистограммы длительности проверки по умолчанию
+var DefaultCheckDurationBuckets = []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60}
+
+// Options параметры метрик из конфигурации
+type Options struct {
+	// Границы hls_check_duration_seconds (пусто - DefaultCheckDurationBuckets)
+	CheckDurationBuckets []float64
+	// Окна hls_stream_success_ratio (пусто - DefaultSLAWindows)
+	SLAWindows []time.Duration
+	// SilencedLabel добавляет к сериям стримов метку silenced="true|false"
+	SilencedLabel bool
+}
+
+// NewCollector создает и регистрирует все метрики с параметрами по умолчанию
 func NewCollector(reg prometheus.Registerer) models.MetricsCollector {
+	return NewCollectorWithOptions(reg, Options{})
+}
+
+// NewCollectorWithOptions создает и регистрирует все метрики
+func NewCollectorWithOptions(reg prometheus.Registerer, opts Options) *Collector {
 	if reg == nil {
 		reg = prometheus.DefaultRegisterer
 	}
+	checkBuckets := opts.CheckDurationBuckets
+	if len(checkBuckets) == 0 {
+		checkBuckets = DefaultCheckDurationBuckets
+	}
+	slaWindows := opts.SLAWindows
+	if len(slaWindows) == 0 {
+		slaWindows = DefaultSLAWindows
+	}
 
-	factory := promauto.With(reg)
+	tracking := &trackingRegisterer{Registerer: reg}
+	factory := promauto.With(tracking)
 
 	c := &Collector{
 		streamUp: factory.NewGaugeVec(
@@ -51,6 +248,22 @@ func NewCollector(reg prometheus.Registerer) models.MetricsCollector {
 			[]string{"name"},
 		),
 
+		downReason: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricDownReason,
+				Help: "Dominant failure cause of the unavailable stream (1 - current reason)",
+			},
+			[]string{"name", "reason"},
+		),
+
+		streamInfo: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricStreamInfo,
+				Help: "Configured stream metadata, always 1",
+			},
+			[]string{"name", "url", "check_mode", "group"},
+		),
+
 		responseTime: factory.NewHistogramVec( // Заменили promauto на factory
 			prometheus.HistogramOpts{
 				Name:    MetricResponseTime,
@@ -60,6 +273,40 @@ func NewCollector(reg prometheus.Registerer) models.MetricsCollector {
 			[]string{"name", "type"},
 		),
 
+		checkDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    MetricCheckDuration,
+				Help:    "End-to-end check duration in seconds including download, parsing and validation",
+				Buckets: checkBuckets,
+			},
+			[]string{"name"},
+		),
+
+		httpResponses: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricHTTPResponses,
+				Help: "HTTP responses to check requests by request phase and status code",
+			},
+			[]string{"name", "phase", "code"},
+		),
+
+		rateLimited: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricRateLimited,
+				Help: "Check requests rejected by rate limiting (429, or 503 with Retry-After) by request phase",
+			},
+			[]string{"name", "phase"},
+		),
+
+		stageDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    MetricStageDuration,
+				Help:    "Duration of a check stage in seconds",
+				Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"name", "stage"},
+		),
+
 		errorsTotal: factory.NewCounterVec( // Заменили promauto на factory
 			prometheus.CounterOpts{
 				Name: MetricErrorsTotal,
@@ -76,6 +323,30 @@ func NewCollector(reg prometheus.Registerer) models.MetricsCollector {
 			[]string{"name"},
 		),
 
+		lastSuccess: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricLastSuccess,
+				Help: "Timestamp of last successful check",
+			},
+			[]string{"name"},
+		),
+
+		checksTotal: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricChecksTotal,
+				Help: "Number of completed checks by result (success or failure)",
+			},
+			[]string{"name", "result"},
+		),
+
+		successRatio: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricSuccessRatio,
+				Help: "Share of successful checks over a rolling window",
+			},
+			[]string{"name", "window"},
+		),
+
 		segmentsChecked: factory.NewCounterVec( // Заменили promauto на factory
 			prometheus.CounterOpts{
 				Name: MetricSegmentsChecked,
@@ -106,7 +377,489 @@ func NewCollector(reg prometheus.Registerer) models.MetricsCollector {
 				Help: "Number of active checks",
 			},
 		),
+
+		workerBusy: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: MetricWorkerBusy,
+				Help: "Number of workers executing check tasks",
+			},
+		),
+
+		queueDepth: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: MetricQueueDepth,
+				Help: "Number of check tasks waiting for a free worker",
+			},
+		),
+
+		queueWait: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    MetricQueueWait,
+				Help:    "Total time tasks of a check waited for a free worker in seconds",
+				Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"name"},
+		),
+
+		downloadedBytes: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricDownloadedBytes,
+				Help: "Total bytes downloaded by checks",
+			},
+			[]string{"name"},
+		),
+
+		budgetExceeded: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricBudgetExceeded,
+				Help: "Shows if the daily download budget of the stream is exceeded",
+			},
+			[]string{"name"},
+		),
+
+		renditionUp: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricRenditionUp,
+				Help: "Shows if the alternative rendition (EXT-X-MEDIA) playlist is available",
+			},
+			[]string{"name", "type", "group_id", "rendition"},
+		),
+
+		schedulingDrift: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricSchedulingDrift,
+				Help: "Delay between the scheduled and the actual start of the last check",
+			},
+			[]string{"name"},
+		),
+
+		liveEdgeLatency: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricLiveEdgeLatency,
+				Help: "Wall clock minus the end of the last segment by EXT-X-PROGRAM-DATE-TIME",
+			},
+			[]string{"name", "variant"},
+		),
+
+		liveEdgeEWMA: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricLiveEdgeEWMA,
+				Help: "Exponentially weighted moving average of the live edge latency across checks",
+			},
+			[]string{"name", "variant"},
+		),
+
+		clockSkew: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricClockSkew,
+				Help: "End of the last segment by EXT-X-PROGRAM-DATE-TIME minus the Date header of the playlist response",
+			},
+			[]string{"name", "variant"},
+		),
+
+		bitrateEWMA: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricBitrateEWMA,
+				Help: "Exponentially weighted moving average of the stream bitrate in bytes per second across checks",
+			},
+			[]string{"name"},
+		),
+
+		conformance: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricConformance,
+				Help: "RFC 8216 conformance violations found in strict mode",
+			},
+			[]string{"name", "rule"},
+		),
+
+		specViolations: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricSpecViolations,
+				Help: "Media playlists failed by segment duration rules against EXT-X-TARGETDURATION",
+			},
+			[]string{"name", "rule"},
+		),
+
+		eventViolations: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricEventViolations,
+				Help: "EVENT playlist refreshes that removed or modified published segments",
+			},
+			[]string{"name", "variant", "kind"},
+		),
+
+		segmentSize: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: MetricSegmentSize,
+				Help: "Size of successfully downloaded segments in bytes",
+				// 32 КиБ - 32 МиБ
+				Buckets: prometheus.ExponentialBuckets(32*1024, 2, 11),
+			},
+			[]string{"name"},
+		),
+
+		segmentDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    MetricSegmentDuration,
+				Help:    "Duration of checked segments from EXTINF in seconds",
+				Buckets: []float64{0.5, 1, 2, 3, 4, 5, 6, 8, 10, 12, 15, 20},
+			},
+			[]string{"name"},
+		),
+
+		segmentDownloadRatio: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: MetricSegmentDownloadRatio,
+				Help: "Segment download time divided by its duration; values near 1 mean players will stall",
+				// Подробнее у 1: отношение, близкое к 1, предвещает буферизацию у зрителей
+				Buckets: []float64{0.05, 0.1, 0.25, 0.5, 0.75, 0.9, 1, 1.5, 2, 5},
+			},
+			[]string{"name"},
+		),
+
+		playlistStale: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricPlaylistStale,
+				Help: "Whether the live playlist stopped advancing between checks (1 = stale)",
+			},
+			[]string{"name"},
+		),
+
+		streamLive: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricStreamLive,
+				Help: "Whether media playlists are live, without EXT-X-ENDLIST or PLAYLIST-TYPE:VOD (1 = live)",
+			},
+			[]string{"name"},
+		),
+
+		totalDuration: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricTotalDuration,
+				Help: "Largest sum of EXTINF durations among media playlists: VOD length or live window",
+			},
+			[]string{"name"},
+		),
+
+		parseIssues: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricParseIssues,
+				Help: "Playlist lines skipped by the lenient parser (unknown tags, malformed attribute lists)",
+			},
+			[]string{"name", "kind"},
+		),
+
+		unknownTag: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricUnknownTag,
+				Help: "Non-standard playlist tag seen in the stream playlists",
+			},
+			[]string{"name", "tag"},
+		),
+
+		pidChanges: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricPIDChanges,
+				Help: "Changes of MPEG-TS program numbers or elementary stream PIDs between segments",
+			},
+			[]string{"name", "kind"},
+		),
+
+		codecMismatch: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricCodecMismatch,
+				Help: "Checks where variant segments contain a codec not declared in the CODECS attribute",
+			},
+			[]string{"name", "variant_bandwidth", "resolution", "codec"},
+		),
+
+		discontinuities: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricDiscontinuities,
+				Help: "EXT-X-DISCONTINUITY tags in the media playlist window",
+			},
+			[]string{"name", "variant"},
+		),
+
+		gaps: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricGaps,
+				Help: "EXT-X-GAP tags in the media playlist window",
+			},
+			[]string{"name", "variant"},
+		),
+
+		discontEvents: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricDiscontEvents,
+				Help: "Discontinuities added to the media playlist since the previous check",
+			},
+			[]string{"name", "variant"},
+		),
+
+		mediaSequence: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricMediaSequence,
+				Help: "EXT-X-MEDIA-SEQUENCE of the media playlist",
+			},
+			[]string{"name", "variant"},
+		),
+
+		playlistAge: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricPlaylistAge,
+				Help: "Age header of the media playlist response",
+			},
+			[]string{"name", "variant"},
+		),
+
+		errorCauses: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricErrorCauses,
+				Help: "Error causes found by diagnostic sub-checks",
+			},
+			[]string{"name", "cause"},
+		),
+
+		hostBudgetWait: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricHostBudgetWait,
+				Help: "Time requests waited for the per-host request budget",
+			},
+			[]string{"host"},
+		),
+
+		requestLimitWait: factory.NewCounter(
+			prometheus.CounterOpts{
+				Name: MetricRequestLimitWait,
+				Help: "Time requests waited for the global request rate limit",
+			},
+		),
+
+		keyCache: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricKeyCache,
+				Help: "Requests for AES-128 segment keys by cache result (hit or miss)",
+			},
+			[]string{"name", "result"},
+		),
+
+		sessionData: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricSessionData,
+				Help: "EXT-X-SESSION-DATA entry of the master playlist (0 if its URI document is invalid)",
+			},
+			[]string{"name", "data_id", "language", "value", "uri"},
+		),
+
+		adBreaks: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricAdBreaks,
+				Help: "Ad breaks (EXT-X-CUE-OUT or SCTE35-OUT EXT-X-DATERANGE) in the media playlist window",
+			},
+			[]string{"name", "variant"},
+		),
+
+		adLastCue: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricAdLastCue,
+				Help: "Timestamp of the latest ad cue seen in the media playlist",
+			},
+			[]string{"name", "variant"},
+		),
+
+		adMalformed: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricAdMalformed,
+				Help: "Malformed ad marker tags in the media playlist window",
+			},
+			[]string{"name", "variant"},
+		),
+
+		ccErrors: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricCCErrors,
+				Help: "MPEG-TS continuity counter errors in checked segments",
+			},
+			[]string{"name"},
+		),
+
+		pcrInterval: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricPCRInterval,
+				Help: "Longest interval between MPEG-TS PCR values in checked segments",
+			},
+			[]string{"name"},
+		),
+
+		pcrJitter: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricPCRJitter,
+				Help: "Largest MPEG-TS PCR deviation from the constant bitrate timeline in checked segments",
+			},
+			[]string{"name"},
+		),
+
+		nullPacketRatio: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricNullPacketRatio,
+				Help: "Share of MPEG-TS null (stuffing) packets in checked segments",
+			},
+			[]string{"name"},
+		),
+
+		lastDeepCheck: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricLastDeepCheck,
+				Help: "Timestamp of the last scheduled deep check",
+			},
+			[]string{"name"},
+		),
+
+		checkInterval: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricCheckInterval,
+				Help: "Current interval until the next check, including failure backoff",
+			},
+			[]string{"name"},
+		),
+
+		checksSkipped: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricChecksSkipped,
+				Help: "Scheduled checks skipped because the previous check overran the interval",
+			},
+			[]string{"name"},
+		),
+
+		contentLooping: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricContentLooping,
+				Help: "Live playlist keeps cycling through the same segments (1 - looping)",
+			},
+			[]string{"name"},
+		),
+
+		dashUp: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricDASHUp,
+				Help: "DASH manifest of the same channel is available (1 - up, 0 - down)",
+			},
+			[]string{"name"},
+		),
+
+		edgeDivergence: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricEdgeDivergence,
+				Help: "DASH live edge latency minus HLS live edge latency in seconds",
+			},
+			[]string{"name"},
+		),
+
+		streamSilenced: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricStreamSilenced,
+				Help: "Stream results are silenced by a silence rule (1 - silenced)",
+			},
+			[]string{"name"},
+		),
+
+		streamDegraded: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricStreamDegraded,
+				Help: "Stream hook command exited with a non-zero code after the last check (1 - degraded)",
+			},
+			[]string{"name"},
+		),
+
+		sloViolation: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricSLOViolation,
+				Help: "Stream SLO target was violated by the last check (1 - violated)",
+			},
+			[]string{"name", "slo"},
+		),
+
+		userAgentUp: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricUserAgentUp,
+				Help: "Result of the last check made with the User-Agent from user_agents (1 - up)",
+			},
+			[]string{"name", "user_agent"},
+		),
+
+		threshold: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricThreshold,
+				Help: "Configured stream threshold, for dashboards and alert rules",
+			},
+			[]string{"name", "threshold"},
+		),
+
+		variantSegmentsChecked: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: MetricVariantSegmentsChecked,
+				Help: "Segments checked per variant of the master playlist",
+			},
+			[]string{"name", "variant_bandwidth", "resolution", "status"},
+		),
+
+		variantResponseTime: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    MetricVariantResponseTime,
+				Help:    "Variant media playlist response time in seconds",
+				Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+			},
+			[]string{"name", "variant_bandwidth", "resolution"},
+		),
+
+		variantDeclaredBitrate: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricVariantDeclaredBitrate,
+				Help: "Variant bitrate declared by BANDWIDTH attribute in bits per second",
+			},
+			[]string{"name", "variant_bandwidth", "resolution"},
+		),
+
+		variantMeasuredBitrate: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricVariantMeasuredBitrate,
+				Help: "Variant bitrate measured from checked segment sizes and durations in bits per second",
+			},
+			[]string{"name", "variant_bandwidth", "resolution"},
+		),
+
+		variantBitrateEWMA: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricVariantBitrateEWMA,
+				Help: "Exponentially weighted moving average of the measured variant bitrate in bits per second across checks",
+			},
+			[]string{"name", "variant_bandwidth", "resolution"},
+		),
+
+		variantBitrateRatio: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricVariantBitrateRatio,
+				Help: "Ratio of measured to declared variant bitrate",
+			},
+			[]string{"name", "variant_bandwidth", "resolution"},
+		),
+
+		variantSizeAnomaly: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: MetricVariantSizeAnomaly,
+				Help: "Variant segment sizes are persistently below their rolling baseline (1 - anomaly)",
+			},
+			[]string{"name", "variant_bandwidth", "resolution"},
+		),
+
+		ewma:          newEWMA(),
+		labels:        newStreamLabels(),
+		silencedLabel: opts.SilencedLabel,
+		sla:           newSLATracker(slaWindows),
 	}
+	c.vecs = tracking.vecs
 
 	return c
 }
@@ -120,9 +873,38 @@ func (c *Collector) SetStreamUp(name string, up bool) {
 	c.streamUp.WithLabelValues(name).Set(value)
 }
 
-// RecordResponseTime записывает время ответа
-func (c *Collector) RecordResponseTime(name string, duration float64) {
-	c.responseTime.WithLabelValues(name, "total").Observe(duration)
+// SetStreamDownReason публикует причину недоступности стрима вместо прежней.
+// Пустая причина (стрим доступен) удаляет серию.
+func (c *Collector) SetStreamDownReason(name, reason string) {
+	c.downReason.DeletePartialMatch(prometheus.Labels{"name": name})
+	if reason != "" {
+		c.downReason.WithLabelValues(name, reason).Set(1)
+	}
+}
+
+// RecordResponseTime записывает время ответа запроса проверки типа phase (models.ResponseTime*)
+func (c *Collector) RecordResponseTime(name, phase string, duration float64, traceID string) {
+	observeWithTrace(c.responseTime.WithLabelValues(name, phase), duration, traceID)
+}
+
+// RecordCheckDuration записывает длительность проверки целиком
+func (c *Collector) RecordCheckDuration(name string, duration float64, traceID string) {
+	observeWithTrace(c.checkDuration.WithLabelValues(name), duration, traceID)
+}
+
+// RecordHTTPResponse учитывает ответ HTTP на запрос проверки типа phase
+func (c *Collector) RecordHTTPResponse(name, phase string, code int) {
+	c.httpResponses.WithLabelValues(name, phase, strconv.Itoa(code)).Inc()
+}
+
+// RecordRateLimited учитывает запрос проверки, отклоненный ограничением частоты запросов
+func (c *Collector) RecordRateLimited(name, phase string) {
+	c.rateLimited.WithLabelValues(name, phase).Inc()
+}
+
+// RecordStageDuration записывает длительность этапа проверки
+func (c *Collector) RecordStageDuration(name, stage string, duration float64) {
+	c.stageDuration.WithLabelValues(name, stage).Observe(duration)
 }
 
 // RecordError увеличивает счетчик ошибок
@@ -135,6 +917,24 @@ func (c *Collector) SetLastCheckTime(name string, timestamp time.Time) {
 	c.lastCheck.WithLabelValues(name).Set(float64(timestamp.Unix()))
 }
 
+// RecordCheckResult учитывает результат проверки в hls_checks_total
+// и скользящих долях успешных проверок
+func (c *Collector) RecordCheckResult(name string, success bool, timestamp time.Time) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	c.checksTotal.WithLabelValues(name, result).Inc()
+	c.sla.Record(name, success, timestamp, func(window time.Duration, ratio float64) {
+		c.successRatio.WithLabelValues(name, windowLabel(window)).Set(ratio)
+	})
+}
+
+// SetLastSuccessTime устанавливает время последней успешной проверки
+func (c *Collector) SetLastSuccessTime(name string, timestamp time.Time) {
+	c.lastSuccess.WithLabelValues(name).Set(float64(timestamp.Unix()))
+}
+
 // RecordSegmentCheck записывает результат проверки сегмента
 func (c *Collector) RecordSegmentCheck(name string, success bool) {
 	status := "success"
@@ -144,11 +944,24 @@ func (c *Collector) RecordSegmentCheck(name string, success bool) {
 	c.segmentsChecked.WithLabelValues(name, status).Inc()
 }
 
-// Reset сбрасывает все метрики для указанного потока
+// SetStreamInfo публикует метаданные стрима вместо опубликованных ранее
+// и запоминает его пользовательские метки
+func (c *Collector) SetStreamInfo(stream models.StreamConfig) {
+	c.labels.Set(stream.Name, stream.Labels)
+	c.streamInfo.DeletePartialMatch(prometheus.Labels{"name": stream.Name})
+	c.streamInfo.WithLabelValues(stream.Name, stream.URL, stream.CheckMode, stream.Group).Set(1)
+}
+
+// Reset удаляет все серии удаленного стрима, включая счетчики и гистограммы,
+// а также его сглаженные значения и пользовательские метки: иначе дашборды
+// бесконечно показывают последние значения
 func (c *Collector) Reset(name string) {
-	c.streamUp.DeleteLabelValues(name)
-	// Для гистограмм и счетчиков сброс не требуется,
-	// так как они автоматически очищаются Prometheus
+	for _, vec := range c.vecs {
+		vec.DeletePartialMatch(prometheus.Labels{"name": name})
+	}
+	c.ewma.Delete(name)
+	c.labels.Delete(name)
+	c.sla.Delete(name)
 }
 
 // Close освобождает ресурсы (необязательно, так как promauto сам управляет регистрацией)
@@ -167,6 +980,7 @@ func (c *Collector) GetErrorsTotal(name, errorType string) float64 {
 
 func (c *Collector) SetStreamBitrate(name string, bitrate float64) {
 	c.streamBitrate.WithLabelValues(name).Set(bitrate)
+	c.bitrateEWMA.WithLabelValues(name).Set(c.ewma.Update(bitrate, MetricBitrateEWMA, name))
 }
 
 func (c *Collector) SetSegmentsCount(name string, count int) {
@@ -177,6 +991,342 @@ func (c *Collector) SetActiveChecks(count int) {
 	c.activeChecks.Set(float64(count))
 }
 
+// SetWorkerPool публикует число занятых воркеров и задач в очереди пула
+func (c *Collector) SetWorkerPool(busy, queued int) {
+	c.workerBusy.Set(float64(busy))
+	c.queueDepth.Set(float64(queued))
+}
+
+// RecordQueueWait учитывает ожидание свободного воркера задачами проверки
+func (c *Collector) RecordQueueWait(name string, seconds float64) {
+	c.queueWait.WithLabelValues(name).Observe(seconds)
+}
+
+// AddDownloadedBytes увеличивает счетчик загруженных байт
+func (c *Collector) AddDownloadedBytes(name string, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	c.downloadedBytes.WithLabelValues(name).Add(float64(bytes))
+}
+
+// SetBudgetExceeded отмечает исчерпание суточного лимита трафика
+func (c *Collector) SetBudgetExceeded(name string, exceeded bool) {
+	value := 0.0
+	if exceeded {
+		value = 1.0
+	}
+	c.budgetExceeded.WithLabelValues(name).Set(value)
+}
+
+// SetRenditionUp устанавливает доступность альтернативного рендишена
+func (c *Collector) SetRenditionUp(name, renditionType, groupID, rendition string, up bool) {
+	value := 0.0
+	if up {
+		value = 1.0
+	}
+	c.renditionUp.WithLabelValues(name, renditionType, groupID, rendition).Set(value)
+}
+
+// SetSchedulingDrift устанавливает отставание старта проверки от расписания
+func (c *Collector) SetSchedulingDrift(name string, drift float64) {
+	c.schedulingDrift.WithLabelValues(name).Set(drift)
+}
+
+// SetLiveEdgeLatency устанавливает отставание последнего сегмента варианта от текущего времени
+func (c *Collector) SetLiveEdgeLatency(name, variant string, latency float64) {
+	c.liveEdgeLatency.WithLabelValues(name, variant).Set(latency)
+	c.liveEdgeEWMA.WithLabelValues(name, variant).Set(c.ewma.Update(latency, MetricLiveEdgeEWMA, name, variant))
+}
+
+// SetPackagerClockSkew устанавливает расхождение часов упаковщика и сервера для варианта
+func (c *Collector) SetPackagerClockSkew(name, variant string, skew float64) {
+	c.clockSkew.WithLabelValues(name, variant).Set(skew)
+}
+
+// RecordSpecViolation учитывает медиаплейлист, не прошедший правило длительностей сегментов
+func (c *Collector) RecordSpecViolation(name, rule string) {
+	c.specViolations.WithLabelValues(name, rule).Inc()
+}
+
+// RecordEventViolation учитывает обновление EVENT-плейлиста, удалившее (removed)
+// или изменившее (modified) опубликованные сегменты
+func (c *Collector) RecordEventViolation(name, variant, kind string) {
+	c.eventViolations.WithLabelValues(name, variant, kind).Inc()
+}
+
+// RecordSegmentSize записывает размер загруженного сегмента
+func (c *Collector) RecordSegmentSize(name string, bytes int64) {
+	c.segmentSize.WithLabelValues(name).Observe(float64(bytes))
+}
+
+// RecordSegmentDuration записывает длительность сегмента из EXTINF
+func (c *Collector) RecordSegmentDuration(name string, seconds float64) {
+	c.segmentDuration.WithLabelValues(name).Observe(seconds)
+}
+
+// RecordSegmentDownloadRatio записывает отношение времени загрузки сегмента к его длительности
+func (c *Collector) RecordSegmentDownloadRatio(name string, ratio float64) {
+	c.segmentDownloadRatio.WithLabelValues(name).Observe(ratio)
+}
+
+// RecordConformanceViolation учитывает нарушение RFC 8216
+func (c *Collector) RecordConformanceViolation(name, rule string) {
+	c.conformance.WithLabelValues(name, rule).Inc()
+}
+
+// RecordVariantSegmentCheck учитывает проверку сегмента варианта
+func (c *Collector) RecordVariantSegmentCheck(name, bandwidth, resolution string, success bool) {
+	status := "success"
+	if !success {
+		status = "failed"
+	}
+	c.variantSegmentsChecked.WithLabelValues(name, bandwidth, resolution, status).Inc()
+}
+
+// RecordVariantResponseTime записывает время загрузки медиаплейлиста варианта
+func (c *Collector) RecordVariantResponseTime(name, bandwidth, resolution string, duration float64, traceID string) {
+	observeWithTrace(c.variantResponseTime.WithLabelValues(name, bandwidth, resolution), duration, traceID)
+}
+
+// observeWithTrace записывает значение гистограммы с exemplar trace_id, если трассировка включена
+func observeWithTrace(observer prometheus.Observer, value float64, traceID string) {
+	if eo, ok := observer.(prometheus.ExemplarObserver); ok && traceID != "" {
+		eo.ObserveWithExemplar(value, prometheus.Labels{"trace_id": traceID})
+		return
+	}
+	observer.Observe(value)
+}
+
+// SetVariantBitrate устанавливает заявленный и измеренный битрейт варианта.
+// Отношение публикуется только при заданном BANDWIDTH.
+func (c *Collector) SetVariantBitrate(name, bandwidth, resolution string, declared, measured float64) {
+	c.variantDeclaredBitrate.WithLabelValues(name, bandwidth, resolution).Set(declared)
+	c.variantMeasuredBitrate.WithLabelValues(name, bandwidth, resolution).Set(measured)
+	smoothed := c.ewma.Update(measured, MetricVariantBitrateEWMA, name, bandwidth, resolution)
+	c.variantBitrateEWMA.WithLabelValues(name, bandwidth, resolution).Set(smoothed)
+	if declared > 0 {
+		c.variantBitrateRatio.WithLabelValues(name, bandwidth, resolution).Set(measured / declared)
+	}
+}
+
+// SetContentLooping устанавливает признак циклического повтора сегментов в плейлистах стрима
+func (c *Collector) SetContentLooping(name string, looping bool) {
+	value := 0.0
+	if looping {
+		value = 1.0
+	}
+	c.contentLooping.WithLabelValues(name).Set(value)
+}
+
+// SetDASHUp устанавливает доступность DASH-версии канала
+func (c *Collector) SetDASHUp(name string, up bool) {
+	value := 0.0
+	if up {
+		value = 1.0
+	}
+	c.dashUp.WithLabelValues(name).Set(value)
+}
+
+// SetLiveEdgeDivergence устанавливает разницу отставаний live-края DASH и HLS в секундах
+func (c *Collector) SetLiveEdgeDivergence(name string, divergence float64) {
+	c.edgeDivergence.WithLabelValues(name).Set(divergence)
+}
+
+// SetStreamSilenced устанавливает признак действующего правила silences для стрима
+func (c *Collector) SetStreamSilenced(name string, silenced bool) {
+	value := 0.0
+	if silenced {
+		value = 1.0
+	}
+	c.streamSilenced.WithLabelValues(name).Set(value)
+	if c.silencedLabel {
+		c.labels.SetSilenced(name, silenced)
+	}
+}
+
+// SetStreamDegraded устанавливает признак деградации стрима по коду выхода команды hook
+func (c *Collector) SetStreamDegraded(name string, degraded bool) {
+	value := 0.0
+	if degraded {
+		value = 1.0
+	}
+	c.streamDegraded.WithLabelValues(name).Set(value)
+}
+
+// SetSLOViolation устанавливает признак нарушения цели SLO стрима
+func (c *Collector) SetSLOViolation(name, slo string, violated bool) {
+	value := 0.0
+	if violated {
+		value = 1.0
+	}
+	c.sloViolation.WithLabelValues(name, slo).Set(value)
+}
+
+// SetUserAgentUp устанавливает результат последней проверки стрима с User-Agent из user_agents
+func (c *Collector) SetUserAgentUp(name, userAgent string, up bool) {
+	value := 0.0
+	if up {
+		value = 1.0
+	}
+	c.userAgentUp.WithLabelValues(name, userAgent).Set(value)
+}
+
+// SetThresholds публикует заданные в конфигурации пороги стрима вместо опубликованных ранее
+func (c *Collector) SetThresholds(name string, thresholds map[string]float64) {
+	c.threshold.DeletePartialMatch(prometheus.Labels{"name": name})
+	for threshold, value := range thresholds {
+		c.threshold.WithLabelValues(name, threshold).Set(value)
+	}
+}
+
+// SetVariantSizeAnomaly устанавливает признак устойчивого падения размеров сегментов варианта
+func (c *Collector) SetVariantSizeAnomaly(name, bandwidth, resolution string, anomaly bool) {
+	value := 0.0
+	if anomaly {
+		value = 1.0
+	}
+	c.variantSizeAnomaly.WithLabelValues(name, bandwidth, resolution).Set(value)
+}
+
+// RecordPIDChange учитывает смену программ или PID элементарных потоков MPEG-TS
+func (c *Collector) RecordPIDChange(name, kind string) {
+	c.pidChanges.WithLabelValues(name, kind).Inc()
+}
+
+// RecordCodecMismatch учитывает кодек сегментов варианта, не объявленный в CODECS
+func (c *Collector) RecordCodecMismatch(name, bandwidth, resolution, codec string) {
+	c.codecMismatch.WithLabelValues(name, bandwidth, resolution, codec).Inc()
+}
+
+// SetPlaylistMarkers устанавливает число разрывов и пропусков в окне медиаплейлиста
+func (c *Collector) SetPlaylistMarkers(name, variant string, discontinuities, gaps int) {
+	c.discontinuities.WithLabelValues(name, variant).Set(float64(discontinuities))
+	c.gaps.WithLabelValues(name, variant).Set(float64(gaps))
+}
+
+// SetAdMarkers устанавливает число рекламных пауз и некорректных рекламных меток в окне медиаплейлиста
+func (c *Collector) SetAdMarkers(name, variant string, breaks, malformed int) {
+	c.adBreaks.WithLabelValues(name, variant).Set(float64(breaks))
+	c.adMalformed.WithLabelValues(name, variant).Set(float64(malformed))
+}
+
+// SetLastAdCue устанавливает время последней рекламной метки медиаплейлиста
+func (c *Collector) SetLastAdCue(name, variant string, timestamp time.Time) {
+	c.adLastCue.WithLabelValues(name, variant).Set(float64(timestamp.Unix()))
+}
+
+// AddDiscontinuities учитывает новые разрывы медиаплейлиста
+func (c *Collector) AddDiscontinuities(name, variant string, count int) {
+	c.discontEvents.WithLabelValues(name, variant).Add(float64(count))
+}
+
+// SetMediaSequence устанавливает EXT-X-MEDIA-SEQUENCE медиаплейлиста
+func (c *Collector) SetMediaSequence(name, variant string, sequence uint64) {
+	c.mediaSequence.WithLabelValues(name, variant).Set(float64(sequence))
+}
+
+// SetPlaylistAge устанавливает заголовок Age ответа медиаплейлиста
+func (c *Collector) SetPlaylistAge(name, variant string, age time.Duration) {
+	c.playlistAge.WithLabelValues(name, variant).Set(age.Seconds())
+}
+
+// AddHostBudgetWait учитывает ожидание запроса к хосту из-за ограничения частоты
+func (c *Collector) AddHostBudgetWait(host string, wait time.Duration) {
+	c.hostBudgetWait.WithLabelValues(host).Add(wait.Seconds())
+}
+
+// AddRequestLimitWait учитывает ожидание запроса из-за общего ограничения частоты
+func (c *Collector) AddRequestLimitWait(wait time.Duration) {
+	c.requestLimitWait.Add(wait.Seconds())
+}
+
+// RecordKeyCacheRequest учитывает запрос ключа AES-128 сегмента: из кэша или с сервера ключей
+func (c *Collector) RecordKeyCacheRequest(name string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.keyCache.WithLabelValues(name, result).Inc()
+}
+
+// SetSessionData публикует записи EXT-X-SESSION-DATA стрима вместо опубликованных ранее
+func (c *Collector) SetSessionData(name string, entries []models.SessionData) {
+	c.sessionData.DeletePartialMatch(prometheus.Labels{"name": name})
+	for _, entry := range entries {
+		value := 1.0
+		if entry.Error != nil {
+			value = 0
+		}
+		c.sessionData.WithLabelValues(name, entry.DataID, entry.Language, entry.Value, entry.URI).Set(value)
+	}
+}
+
+// RecordErrorCause учитывает причину ошибки, установленную диагностической проверкой
+func (c *Collector) RecordErrorCause(name, cause string) {
+	c.errorCauses.WithLabelValues(name, cause).Inc()
+}
+
+// AddCCErrors учитывает нарушения счетчиков непрерывности TS-пакетов
+func (c *Collector) AddCCErrors(name string, count int) {
+	c.ccErrors.WithLabelValues(name).Add(float64(count))
+}
+
+// SetPCRStats устанавливает наибольшие интервал между PCR и джиттер PCR за проверку
+func (c *Collector) SetPCRStats(name string, maxInterval, jitter float64) {
+	c.pcrInterval.WithLabelValues(name).Set(maxInterval)
+	c.pcrJitter.WithLabelValues(name).Set(jitter)
+}
+
+// SetNullPacketRatio устанавливает долю null-пакетов в TS-сегментах за проверку
+func (c *Collector) SetNullPacketRatio(name string, ratio float64) {
+	c.nullPacketRatio.WithLabelValues(name).Set(ratio)
+}
+
+// SetLastDeepCheckTime устанавливает время последней углубленной проверки
+func (c *Collector) SetLastDeepCheckTime(name string, timestamp time.Time) {
+	c.lastDeepCheck.WithLabelValues(name).Set(float64(timestamp.Unix()))
+}
+
+// SetCheckInterval устанавливает действующий интервал проверок стрима в секундах
+func (c *Collector) SetCheckInterval(name string, interval float64) {
+	c.checkInterval.WithLabelValues(name).Set(interval)
+}
+
+// AddChecksSkipped учитывает запуски проверок, пропущенные из-за долгой проверки
+func (c *Collector) AddChecksSkipped(name string, count int) {
+	c.checksSkipped.WithLabelValues(name).Add(float64(count))
+}
+
+// RecordParseIssue учитывает проблему разбора плейлиста
+func (c *Collector) RecordParseIssue(name, kind string) {
+	c.parseIssues.WithLabelValues(name, kind).Inc()
+}
+
+// SetUnknownTag отмечает нестандартный тег стрима
+func (c *Collector) SetUnknownTag(name, tag string) {
+	c.unknownTag.WithLabelValues(name, tag).Set(1)
+}
+
+// SetPlaylistStale устанавливает признак зависшего live-плейлиста
+func (c *Collector) SetPlaylistStale(name string, stale bool) {
+	value := 0.0
+	if stale {
+		value = 1.0
+	}
+	c.playlistStale.WithLabelValues(name).Set(value)
+}
+
+// SetPlaylistType устанавливает тип стрима (live или VOD) и длительность медиаплейлистов
+func (c *Collector) SetPlaylistType(name string, live bool, duration float64) {
+	value := 0.0
+	if live {
+		value = 1.0
+	}
+	c.streamLive.WithLabelValues(name).Set(value)
+	c.totalDuration.WithLabelValues(name).Set(duration)
+}
+
 // Получение значения Gauge метрики
 func getGaugeValue(gauge prometheus.Gauge) float64 {
 	var metric dto.Metric
@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCollector(t *testing.T) {
@@ -29,13 +30,67 @@ func TestCollector(t *testing.T) {
 		test func(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector)
 	}{
 		{"SetStreamUp", testSetStreamUp},
+		{"SetStreamDownReason", testSetStreamDownReason},
 		{"RecordError", testRecordError},
 		{"SetLastCheckTime", testSetLastCheckTime},
+		{"SetLastSuccessTime", testSetLastSuccessTime},
 		{"RecordSegmentCheck", testRecordSegmentCheck},
+		{"RecordCheckResult", testRecordCheckResult},
 		{"RecordResponseTime", testRecordResponseTime},
 		{"SetActiveChecks", testSetActiveChecks},
+		{"SetWorkerPool", testSetWorkerPool},
 		{"SetSegmentsCount", testSetSegmentsCount},
 		{"SetStreamBitrate", testSetStreamBitrate},
+		{"DownloadBudget", testDownloadBudget},
+		{"SetRenditionUp", testSetRenditionUp},
+		{"SetSchedulingDrift", testSetSchedulingDrift},
+		{"SetLiveEdgeLatency", testSetLiveEdgeLatency},
+		{"SetPackagerClockSkew", testSetPackagerClockSkew},
+		{"RecordConformanceViolation", testRecordConformanceViolation},
+		{"RecordSpecViolation", testRecordSpecViolation},
+		{"SetPlaylistStale", testSetPlaylistStale},
+		{"SetPlaylistType", testSetPlaylistType},
+		{"RecordParseIssue", testRecordParseIssue},
+		{"SetUnknownTag", testSetUnknownTag},
+		{"RecordVariantSegmentCheck", testRecordVariantSegmentCheck},
+		{"RecordVariantResponseTime", testRecordVariantResponseTime},
+		{"RecordStageDuration", testRecordStageDuration},
+		{"SetVariantBitrate", testSetVariantBitrate},
+		{"RecordPIDChange", testRecordPIDChange},
+		{"RecordCodecMismatch", testRecordCodecMismatch},
+		{"SetPlaylistMarkers", testSetPlaylistMarkers},
+		{"SetMediaSequence", testSetMediaSequence},
+		{"SetPlaylistAge", testSetPlaylistAge},
+		{"RecordErrorCause", testRecordErrorCause},
+		{"AddHostBudgetWait", testAddHostBudgetWait},
+		{"AddRequestLimitWait", testAddRequestLimitWait},
+		{"SetSessionData", testSetSessionData},
+		{"RecordKeyCacheRequest", testRecordKeyCacheRequest},
+		{"RecordEventViolation", testRecordEventViolation},
+		{"RecordHTTPResponse", testRecordHTTPResponse},
+		{"RecordRateLimited", testRecordRateLimited},
+		{"RecordCheckDuration", testRecordCheckDuration},
+		{"RecordSegmentDistribution", testRecordSegmentDistribution},
+		{"SetStreamInfo", testSetStreamInfo},
+		{"AddDiscontinuities", testAddDiscontinuities},
+		{"SetAdMarkers", testSetAdMarkers},
+		{"SetLastAdCue", testSetLastAdCue},
+		{"AddCCErrors", testAddCCErrors},
+		{"SetPCRStats", testSetPCRStats},
+		{"SetNullPacketRatio", testSetNullPacketRatio},
+		{"SetLastDeepCheckTime", testSetLastDeepCheckTime},
+		{"SetCheckInterval", testSetCheckInterval},
+		{"AddChecksSkipped", testAddChecksSkipped},
+		{"SetVariantSizeAnomaly", testSetVariantSizeAnomaly},
+		{"SetContentLooping", testSetContentLooping},
+		{"SetStreamSilenced", testSetStreamSilenced},
+		{"SetStreamDegraded", testSetStreamDegraded},
+		{"SetSLOViolation", testSetSLOViolation},
+		{"SetUserAgentUp", testSetUserAgentUp},
+		{"SetThresholds", testSetThresholds},
+		{"SetDASHUp", testSetDASHUp},
+		{"SetLiveEdgeDivergence", testSetLiveEdgeDivergence},
+		{"Reset", testReset},
 	}
 
 	for _, tt := range tests {
@@ -123,24 +178,36 @@ func testRecordSegmentCheck(t *testing.T, reg *prometheus.Registry, collector mo
 
 // Тест для RecordResponseTime
 func testRecordResponseTime(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
-	collector.RecordResponseTime("test_stream", 0.5)
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	collector.RecordResponseTime("test_stream", models.ResponseTimeMaster, 0.5, traceID)
+	collector.RecordResponseTime("test_stream", models.ResponseTimeSegment, 0.2, "")
 
 	metrics, err := reg.Gather()
 	assert.NoError(t, err)
 
-	found := false
+	found, series := false, 0
 	for _, m := range metrics {
 		if *m.Name == MetricResponseTime {
+			series += len(m.Metric)
 			for _, metric := range m.Metric {
-				if hasLabelValue(metric, "name", "test_stream") {
+				if hasLabelValue(metric, "name", "test_stream") && hasLabelValue(metric, "type", models.ResponseTimeMaster) {
 					found = true
 					assert.Equal(t, uint64(1), *metric.Histogram.SampleCount)
 					assert.Equal(t, 0.5, *metric.Histogram.SampleSum)
+
+					var exemplarTrace string
+					for _, bucket := range metric.Histogram.Bucket {
+						if ex := bucket.GetExemplar(); ex != nil {
+							exemplarTrace = ex.GetLabel()[0].GetValue()
+						}
+					}
+					assert.Equal(t, traceID, exemplarTrace)
 				}
 			}
 		}
 	}
 	assert.True(t, found, "ResponseTime metric should be found")
+	assert.Equal(t, 2, series, "phases are recorded as separate series")
 }
 
 // Тест для SetActiveChecks
@@ -200,6 +267,396 @@ func testSetStreamBitrate(t *testing.T, reg *prometheus.Registry, collector mode
 		}
 	}
 	assert.True(t, found, "StreamBitrate metric should be found")
+
+	c := collector.(*Collector)
+	c.SetStreamBitrate("test_stream", 500000)
+	assert.InDelta(t, 1300000.0, getGaugeValue(c.bitrateEWMA.WithLabelValues("test_stream")), 1e-6)
+}
+
+// Тест для AddDownloadedBytes и SetBudgetExceeded
+func testDownloadBudget(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.AddDownloadedBytes("test_stream", 1024)
+	c.AddDownloadedBytes("test_stream", 0)
+	c.AddDownloadedBytes("test_stream", 512)
+	assert.Equal(t, float64(1536), getCounterValue(c.downloadedBytes.WithLabelValues("test_stream")))
+
+	c.SetBudgetExceeded("test_stream", true)
+	assert.Equal(t, float64(1), getGaugeValue(c.budgetExceeded.WithLabelValues("test_stream")))
+	c.SetBudgetExceeded("test_stream", false)
+	assert.Equal(t, float64(0), getGaugeValue(c.budgetExceeded.WithLabelValues("test_stream")))
+}
+
+// Тест для SetRenditionUp
+func testSetRenditionUp(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetRenditionUp("test_stream", "AUDIO", "aud", "English", true)
+	c.SetRenditionUp("test_stream", "AUDIO", "aud", "Deutsch", false)
+
+	assert.Equal(t, float64(1), getGaugeValue(c.renditionUp.WithLabelValues("test_stream", "AUDIO", "aud", "English")))
+	assert.Equal(t, float64(0), getGaugeValue(c.renditionUp.WithLabelValues("test_stream", "AUDIO", "aud", "Deutsch")))
+}
+
+// Тест для SetSchedulingDrift
+func testSetSchedulingDrift(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetSchedulingDrift("test_stream", 0.25)
+	assert.Equal(t, 0.25, getGaugeValue(c.schedulingDrift.WithLabelValues("test_stream")))
+}
+
+// Тест для SetLiveEdgeLatency
+func testSetLiveEdgeLatency(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetLiveEdgeLatency("test_stream", "720p/index.m3u8", 6.5)
+	assert.Equal(t, 6.5, getGaugeValue(c.liveEdgeLatency.WithLabelValues("test_stream", "720p/index.m3u8")))
+	assert.Equal(t, 6.5, getGaugeValue(c.liveEdgeEWMA.WithLabelValues("test_stream", "720p/index.m3u8")))
+
+	// Мгновенное значение заменяется, сглаженное смещается к нему на долю ewmaAlpha
+	c.SetLiveEdgeLatency("test_stream", "720p/index.m3u8", 16.5)
+	assert.Equal(t, 16.5, getGaugeValue(c.liveEdgeLatency.WithLabelValues("test_stream", "720p/index.m3u8")))
+	assert.InDelta(t, 8.5, getGaugeValue(c.liveEdgeEWMA.WithLabelValues("test_stream", "720p/index.m3u8")), 1e-9)
+}
+
+// Тест для SetPackagerClockSkew
+func testSetPackagerClockSkew(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetPackagerClockSkew("test_stream", "720p/index.m3u8", -3.5)
+	assert.Equal(t, -3.5, getGaugeValue(c.clockSkew.WithLabelValues("test_stream", "720p/index.m3u8")))
+}
+
+// Тест для RecordSpecViolation
+func testRecordSpecViolation(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordSpecViolation("test_stream", models.SpecRuleTargetDuration)
+	assert.Equal(t, 1.0, getCounterValue(c.specViolations.WithLabelValues("test_stream", models.SpecRuleTargetDuration)))
+}
+
+// Тест для RecordConformanceViolation
+func testRecordConformanceViolation(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordConformanceViolation("test_stream", "tag_placement")
+	c.RecordConformanceViolation("test_stream", "tag_placement")
+	assert.Equal(t, 2.0, getCounterValue(c.conformance.WithLabelValues("test_stream", "tag_placement")))
+}
+
+// Тест для RecordParseIssue
+func testRecordParseIssue(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordParseIssue("test_stream", "unknown_tag")
+	assert.Equal(t, 1.0, getCounterValue(c.parseIssues.WithLabelValues("test_stream", "unknown_tag")))
+}
+
+// Тест для SetUnknownTag
+func testSetUnknownTag(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetUnknownTag("test_stream", "EXT-X-CUE-OUT")
+	assert.Equal(t, 1.0, getGaugeValue(c.unknownTag.WithLabelValues("test_stream", "EXT-X-CUE-OUT")))
+}
+
+// Тест для SetPlaylistStale
+func testSetPlaylistStale(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetPlaylistStale("test_stream", true)
+	assert.Equal(t, 1.0, getGaugeValue(c.playlistStale.WithLabelValues("test_stream")))
+	c.SetPlaylistStale("test_stream", false)
+	assert.Equal(t, 0.0, getGaugeValue(c.playlistStale.WithLabelValues("test_stream")))
+}
+
+// Тест для SetPlaylistType
+func testSetPlaylistType(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetPlaylistType("test_stream", false, 5400.5)
+	assert.Equal(t, 0.0, getGaugeValue(c.streamLive.WithLabelValues("test_stream")))
+	assert.Equal(t, 5400.5, getGaugeValue(c.totalDuration.WithLabelValues("test_stream")))
+}
+
+// Тест для RecordVariantSegmentCheck
+func testRecordVariantSegmentCheck(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordVariantSegmentCheck("test_stream", "2000000", "1280x720", true)
+	c.RecordVariantSegmentCheck("test_stream", "2000000", "1280x720", false)
+	c.RecordVariantSegmentCheck("test_stream", "800000", "640x360", false)
+
+	assert.Equal(t, 1.0, getCounterValue(c.variantSegmentsChecked.WithLabelValues("test_stream", "2000000", "1280x720", "success")))
+	assert.Equal(t, 1.0, getCounterValue(c.variantSegmentsChecked.WithLabelValues("test_stream", "2000000", "1280x720", "failed")))
+	assert.Equal(t, 1.0, getCounterValue(c.variantSegmentsChecked.WithLabelValues("test_stream", "800000", "640x360", "failed")))
+}
+
+// Тест для RecordVariantResponseTime
+func testRecordVariantResponseTime(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	collector.RecordVariantResponseTime("test_stream", "2000000", "1280x720", 0.3, "")
+
+	metrics, err := reg.Gather()
+	assert.NoError(t, err)
+
+	found := false
+	for _, m := range metrics {
+		if *m.Name == MetricVariantResponseTime {
+			for _, metric := range m.Metric {
+				if hasLabelValue(metric, "variant_bandwidth", "2000000") && hasLabelValue(metric, "resolution", "1280x720") {
+					found = true
+					assert.Equal(t, uint64(1), *metric.Histogram.SampleCount)
+				}
+			}
+		}
+	}
+	assert.True(t, found, "VariantResponseTime metric should be found")
+}
+
+// Тест для RecordStageDuration
+func testRecordStageDuration(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	collector.RecordStageDuration("test_stream", models.StageSegmentDownload, 1.2)
+	collector.RecordStageDuration("test_stream", models.StageSegmentDownload, 0.8)
+
+	metrics, err := reg.Gather()
+	assert.NoError(t, err)
+
+	found := false
+	for _, m := range metrics {
+		if *m.Name == MetricStageDuration {
+			for _, metric := range m.Metric {
+				if hasLabelValue(metric, "stage", models.StageSegmentDownload) {
+					found = true
+					assert.Equal(t, uint64(2), *metric.Histogram.SampleCount)
+					assert.InDelta(t, 2.0, *metric.Histogram.SampleSum, 1e-9)
+				}
+			}
+		}
+	}
+	assert.True(t, found, "StageDuration metric should be found")
+}
+
+// Тест для SetVariantBitrate
+func testSetVariantBitrate(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetVariantBitrate("test_stream", "2000000", "1280x720", 2000000, 2500000)
+
+	assert.Equal(t, 2000000.0, getGaugeValue(c.variantDeclaredBitrate.WithLabelValues("test_stream", "2000000", "1280x720")))
+	assert.Equal(t, 2500000.0, getGaugeValue(c.variantMeasuredBitrate.WithLabelValues("test_stream", "2000000", "1280x720")))
+	assert.Equal(t, 1.25, getGaugeValue(c.variantBitrateRatio.WithLabelValues("test_stream", "2000000", "1280x720")))
+	assert.Equal(t, 2500000.0, getGaugeValue(c.variantBitrateEWMA.WithLabelValues("test_stream", "2000000", "1280x720")))
+
+	c.SetVariantBitrate("test_stream", "2000000", "1280x720", 2000000, 1500000)
+	assert.InDelta(t, 2300000.0, getGaugeValue(c.variantBitrateEWMA.WithLabelValues("test_stream", "2000000", "1280x720")), 1e-6)
+}
+
+// Тест для RecordPIDChange
+func testRecordPIDChange(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordPIDChange("test_stream", "elementary_pid")
+	assert.Equal(t, 1.0, getCounterValue(c.pidChanges.WithLabelValues("test_stream", "elementary_pid")))
+}
+
+// Тест для RecordCodecMismatch
+func testRecordCodecMismatch(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordCodecMismatch("test_stream", "2000000", "1280x720", "hvc1")
+	assert.Equal(t, 1.0, getCounterValue(c.codecMismatch.WithLabelValues("test_stream", "2000000", "1280x720", "hvc1")))
+}
+
+// Тест для SetPlaylistMarkers
+func testSetPlaylistMarkers(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetPlaylistMarkers("test_stream", "720p.m3u8", 2, 1)
+	assert.Equal(t, 2.0, getGaugeValue(c.discontinuities.WithLabelValues("test_stream", "720p.m3u8")))
+	assert.Equal(t, 1.0, getGaugeValue(c.gaps.WithLabelValues("test_stream", "720p.m3u8")))
+}
+
+// Тест для SetMediaSequence
+func testSetMediaSequence(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetMediaSequence("test_stream", "720p.m3u8", 1042)
+	assert.Equal(t, 1042.0, getGaugeValue(c.mediaSequence.WithLabelValues("test_stream", "720p.m3u8")))
+}
+
+// Тест для SetPlaylistAge
+func testSetPlaylistAge(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetPlaylistAge("test_stream", "720p.m3u8", 42*time.Second)
+	assert.Equal(t, 42.0, getGaugeValue(c.playlistAge.WithLabelValues("test_stream", "720p.m3u8")))
+}
+
+// Тест для RecordErrorCause
+func testRecordErrorCause(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordErrorCause("test_stream", models.CauseCDNNegativeCache)
+	c.RecordErrorCause("test_stream", models.CauseCDNNegativeCache)
+	assert.Equal(t, 2.0, getCounterValue(c.errorCauses.WithLabelValues("test_stream", models.CauseCDNNegativeCache)))
+}
+
+// Тест для AddRequestLimitWait
+func testAddRequestLimitWait(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.AddRequestLimitWait(500 * time.Millisecond)
+	c.AddRequestLimitWait(250 * time.Millisecond)
+	assert.Equal(t, 0.75, getCounterValue(c.requestLimitWait))
+}
+
+// Тест для AddHostBudgetWait
+func testAddHostBudgetWait(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.AddHostBudgetWait("cdn.example.com", 500*time.Millisecond)
+	c.AddHostBudgetWait("cdn.example.com", 250*time.Millisecond)
+	assert.Equal(t, 0.75, getCounterValue(c.hostBudgetWait.WithLabelValues("cdn.example.com")))
+}
+
+// Тест для SetSessionData
+func testSetSessionData(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetSessionData("test_stream", []models.SessionData{
+		{DataID: "com.example.title", Value: "News", Language: "en"},
+		{DataID: "com.example.meta", URI: "meta.json", Error: &models.CheckError{Type: models.ErrSessionData}},
+	})
+	assert.Equal(t, 1.0, getGaugeValue(c.sessionData.WithLabelValues("test_stream", "com.example.title", "en", "News", "")))
+	assert.Equal(t, 0.0, getGaugeValue(c.sessionData.WithLabelValues("test_stream", "com.example.meta", "", "", "meta.json")))
+
+	// Новый набор записей заменяет прежний
+	c.SetSessionData("test_stream", []models.SessionData{{DataID: "com.example.title", Value: "Sports", Language: "en"}})
+	metrics, err := reg.Gather()
+	assert.NoError(t, err)
+	series := 0
+	for _, m := range metrics {
+		if *m.Name == MetricSessionData {
+			series = len(m.Metric)
+		}
+	}
+	assert.Equal(t, 1, series)
+}
+
+// Тест для AddDiscontinuities
+func testAddDiscontinuities(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.AddDiscontinuities("test_stream", "720p.m3u8", 3)
+	assert.Equal(t, 3.0, getCounterValue(c.discontEvents.WithLabelValues("test_stream", "720p.m3u8")))
+}
+
+// Тест для SetAdMarkers
+func testSetAdMarkers(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetAdMarkers("test_stream", "720p.m3u8", 2, 1)
+	assert.Equal(t, 2.0, getGaugeValue(c.adBreaks.WithLabelValues("test_stream", "720p.m3u8")))
+	assert.Equal(t, 1.0, getGaugeValue(c.adMalformed.WithLabelValues("test_stream", "720p.m3u8")))
+}
+
+// Тест для SetLastAdCue
+func testSetLastAdCue(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	cue := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c.SetLastAdCue("test_stream", "720p.m3u8", cue)
+	assert.Equal(t, float64(cue.Unix()), getGaugeValue(c.adLastCue.WithLabelValues("test_stream", "720p.m3u8")))
+}
+
+// Тест для AddCCErrors
+func testAddCCErrors(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.AddCCErrors("test_stream", 3)
+	c.AddCCErrors("test_stream", 2)
+	assert.Equal(t, 5.0, getCounterValue(c.ccErrors.WithLabelValues("test_stream")))
+}
+
+// Тест для SetPCRStats
+func testSetPCRStats(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetPCRStats("test_stream", 0.04, 0.0005)
+	assert.Equal(t, 0.04, getGaugeValue(c.pcrInterval.WithLabelValues("test_stream")))
+	assert.Equal(t, 0.0005, getGaugeValue(c.pcrJitter.WithLabelValues("test_stream")))
+}
+
+// Тест для SetNullPacketRatio
+func testSetNullPacketRatio(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetNullPacketRatio("test_stream", 0.25)
+	assert.Equal(t, 0.25, getGaugeValue(c.nullPacketRatio.WithLabelValues("test_stream")))
+}
+
+// Тест для SetLastDeepCheckTime
+func testSetLastDeepCheckTime(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	now := time.Unix(1700000000, 0)
+	c.SetLastDeepCheckTime("test_stream", now)
+	assert.Equal(t, float64(now.Unix()), getGaugeValue(c.lastDeepCheck.WithLabelValues("test_stream")))
+}
+
+// Тест для SetCheckInterval
+func testSetCheckInterval(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetCheckInterval("test_stream", 120)
+	assert.Equal(t, float64(120), getGaugeValue(c.checkInterval.WithLabelValues("test_stream")))
+}
+
+// Тест для AddChecksSkipped
+func testAddChecksSkipped(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.AddChecksSkipped("test_stream", 1)
+	c.AddChecksSkipped("test_stream", 2)
+	assert.Equal(t, 3.0, getCounterValue(c.checksSkipped.WithLabelValues("test_stream")))
+}
+
+// Тест для SetVariantSizeAnomaly
+func testSetVariantSizeAnomaly(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetVariantSizeAnomaly("test_stream", "2000000", "1280x720", true)
+	assert.Equal(t, 1.0, getGaugeValue(c.variantSizeAnomaly.WithLabelValues("test_stream", "2000000", "1280x720")))
+
+	c.SetVariantSizeAnomaly("test_stream", "2000000", "1280x720", false)
+	assert.Equal(t, 0.0, getGaugeValue(c.variantSizeAnomaly.WithLabelValues("test_stream", "2000000", "1280x720")))
+}
+
+// Тест для SetContentLooping
+func testSetContentLooping(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetContentLooping("test_stream", true)
+	assert.Equal(t, 1.0, getGaugeValue(c.contentLooping.WithLabelValues("test_stream")))
+}
+
+// Тест для SetStreamSilenced
+func testSetStreamSilenced(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetStreamSilenced("test_stream", true)
+	assert.Equal(t, 1.0, getGaugeValue(c.streamSilenced.WithLabelValues("test_stream")))
+
+	c.SetStreamSilenced("test_stream", false)
+	assert.Equal(t, 0.0, getGaugeValue(c.streamSilenced.WithLabelValues("test_stream")))
+}
+
+// Тест для SetStreamDegraded
+func testSetStreamDegraded(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetStreamDegraded("test_stream", true)
+	assert.Equal(t, 1.0, getGaugeValue(c.streamDegraded.WithLabelValues("test_stream")))
+}
+
+// Тест для SetSLOViolation
+func testSetSLOViolation(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetSLOViolation("test_stream", models.SLOPlaylistLatency, true)
+	assert.Equal(t, 1.0, getGaugeValue(c.sloViolation.WithLabelValues("test_stream", models.SLOPlaylistLatency)))
+
+	c.SetSLOViolation("test_stream", models.SLOPlaylistLatency, false)
+	assert.Equal(t, 0.0, getGaugeValue(c.sloViolation.WithLabelValues("test_stream", models.SLOPlaylistLatency)))
+}
+
+// Тест для SetUserAgentUp
+func testSetUserAgentUp(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetUserAgentUp("test_stream", "exoplayer", true)
+	c.SetUserAgentUp("test_stream", "hlsjs", false)
+	assert.Equal(t, 1.0, getGaugeValue(c.userAgentUp.WithLabelValues("test_stream", "exoplayer")))
+	assert.Equal(t, 0.0, getGaugeValue(c.userAgentUp.WithLabelValues("test_stream", "hlsjs")))
+}
+
+// Тест для SetDASHUp
+func testSetDASHUp(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetDASHUp("test_stream", true)
+	assert.Equal(t, 1.0, getGaugeValue(c.dashUp.WithLabelValues("test_stream")))
+}
+
+// Тест для SetLiveEdgeDivergence
+func testSetLiveEdgeDivergence(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetLiveEdgeDivergence("test_stream", -2.5)
+	assert.Equal(t, -2.5, getGaugeValue(c.edgeDivergence.WithLabelValues("test_stream")))
 }
 
 // Вспомогательная функция для проверки значения метки
@@ -224,3 +681,264 @@ func TestNewCollectorWithNilRegistry(t *testing.T) {
 	collector := NewCollector(nil)
 	assert.NotNil(t, collector)
 }
+
+// Тест для SetThresholds
+func testSetThresholds(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetThresholds("test_stream", map[string]float64{"timeout_seconds": 10, "expect_variants": 3})
+	assert.Equal(t, 10.0, getGaugeValue(c.threshold.WithLabelValues("test_stream", "timeout_seconds")))
+	assert.Equal(t, 3.0, getGaugeValue(c.threshold.WithLabelValues("test_stream", "expect_variants")))
+
+	// Новый набор порогов заменяет прежний
+	c.SetThresholds("test_stream", map[string]float64{"timeout_seconds": 5})
+	metrics, err := reg.Gather()
+	assert.NoError(t, err)
+	series := 0
+	for _, m := range metrics {
+		if *m.Name == MetricThreshold {
+			series = len(m.Metric)
+		}
+	}
+	assert.Equal(t, 1, series)
+	assert.Equal(t, 5.0, getGaugeValue(c.threshold.WithLabelValues("test_stream", "timeout_seconds")))
+}
+
+// Тест для SetStreamDownReason
+func testSetStreamDownReason(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.SetStreamDownReason("test_stream", "playlist_download")
+	assert.Equal(t, 1.0, getGaugeValue(c.downReason.WithLabelValues("test_stream", "playlist_download")))
+
+	// Новая причина заменяет прежнюю, а доступный стрим не имеет причины
+	series := func() int {
+		metrics, err := reg.Gather()
+		assert.NoError(t, err)
+		for _, m := range metrics {
+			if *m.Name == MetricDownReason {
+				return len(m.Metric)
+			}
+		}
+		return 0
+	}
+	c.SetStreamDownReason("test_stream", "segment_download")
+	assert.Equal(t, 1, series())
+	c.SetStreamDownReason("test_stream", "")
+	assert.Equal(t, 0, series())
+}
+
+// Тест для RecordKeyCacheRequest
+func testRecordKeyCacheRequest(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordKeyCacheRequest("test_stream", false)
+	c.RecordKeyCacheRequest("test_stream", true)
+	c.RecordKeyCacheRequest("test_stream", true)
+	assert.Equal(t, 1.0, getCounterValue(c.keyCache.WithLabelValues("test_stream", "miss")))
+	assert.Equal(t, 2.0, getCounterValue(c.keyCache.WithLabelValues("test_stream", "hit")))
+}
+
+// Тест для RecordEventViolation
+func testRecordEventViolation(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordEventViolation("test_stream", "720p.m3u8", "removed")
+	c.RecordEventViolation("test_stream", "720p.m3u8", "removed")
+	c.RecordEventViolation("test_stream", "480p.m3u8", "modified")
+	assert.Equal(t, 2.0, getCounterValue(c.eventViolations.WithLabelValues("test_stream", "720p.m3u8", "removed")))
+	assert.Equal(t, 1.0, getCounterValue(c.eventViolations.WithLabelValues("test_stream", "480p.m3u8", "modified")))
+}
+
+// Тест для RecordHTTPResponse
+func testRecordHTTPResponse(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordHTTPResponse("test_stream", models.ResponseTimeSegment, 404)
+	c.RecordHTTPResponse("test_stream", models.ResponseTimeSegment, 404)
+	c.RecordHTTPResponse("test_stream", models.ResponseTimeMaster, 403)
+	assert.Equal(t, 2.0, getCounterValue(c.httpResponses.WithLabelValues("test_stream", "segment", "404")))
+	assert.Equal(t, 1.0, getCounterValue(c.httpResponses.WithLabelValues("test_stream", "master_playlist", "403")))
+}
+
+// Тест для RecordRateLimited
+func testRecordRateLimited(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	c := collector.(*Collector)
+	c.RecordRateLimited("test_stream", models.ResponseTimeSegment)
+	c.RecordRateLimited("test_stream", models.ResponseTimeSegment)
+	assert.Equal(t, 2.0, getCounterValue(c.rateLimited.WithLabelValues("test_stream", "segment")))
+}
+
+// Тест для RecordCheckDuration
+func testRecordCheckDuration(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	collector.RecordCheckDuration("test_stream", 3, "")
+
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, m := range metrics {
+		if m.GetName() != MetricCheckDuration {
+			continue
+		}
+		found = true
+		hist := m.GetMetric()[0].GetHistogram()
+		assert.Equal(t, uint64(1), hist.GetSampleCount())
+		assert.Len(t, hist.GetBucket(), len(DefaultCheckDurationBuckets))
+	}
+	assert.True(t, found, "CheckDuration metric should be found")
+}
+
+func TestCollector_CheckDurationBuckets(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := NewCollectorWithOptions(reg, Options{CheckDurationBuckets: []float64{1, 5}})
+	collector.RecordCheckDuration("test_stream", 3, "")
+
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	for _, m := range metrics {
+		if m.GetName() == MetricCheckDuration {
+			buckets := m.GetMetric()[0].GetHistogram().GetBucket()
+			require.Len(t, buckets, 2)
+			assert.Equal(t, uint64(0), buckets[0].GetCumulativeCount())
+			assert.Equal(t, uint64(1), buckets[1].GetCumulativeCount())
+			return
+		}
+	}
+	t.Fatal("CheckDuration metric should be found")
+}
+
+// Тест для RecordSegmentSize, RecordSegmentDuration и RecordSegmentDownloadRatio
+func testRecordSegmentDistribution(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	collector.RecordSegmentSize("test_stream", 512*1024)
+	collector.RecordSegmentSize("test_stream", 2*1024*1024)
+	collector.RecordSegmentDuration("test_stream", 0.8)
+	collector.RecordSegmentDownloadRatio("test_stream", 0.25)
+	collector.RecordSegmentDownloadRatio("test_stream", 0.5)
+
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	counts := make(map[string]uint64)
+	sums := make(map[string]float64)
+	for _, m := range metrics {
+		for _, metric := range m.GetMetric() {
+			if hasLabelValue(metric, "name", "test_stream") && metric.GetHistogram() != nil {
+				counts[m.GetName()] = metric.GetHistogram().GetSampleCount()
+				sums[m.GetName()] = metric.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	assert.Equal(t, uint64(2), counts[MetricSegmentSize])
+	assert.Equal(t, float64(512*1024+2*1024*1024), sums[MetricSegmentSize])
+	assert.Equal(t, uint64(1), counts[MetricSegmentDuration])
+	assert.Equal(t, 0.8, sums[MetricSegmentDuration])
+	assert.Equal(t, uint64(2), counts[MetricSegmentDownloadRatio])
+	assert.Equal(t, 0.75, sums[MetricSegmentDownloadRatio])
+}
+
+// Тест для SetStreamInfo и удаления стрима
+func testSetStreamInfo(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	stream := models.StreamConfig{Name: "test_stream", URL: "http://a/master.m3u8", CheckMode: "all", Group: "news"}
+	collector.SetStreamInfo(stream)
+	stream.CheckMode = "first_last"
+	collector.SetStreamInfo(stream)
+
+	series := func() []*dto.Metric {
+		metrics, err := reg.Gather()
+		require.NoError(t, err)
+		for _, m := range metrics {
+			if m.GetName() == MetricStreamInfo {
+				return m.GetMetric()
+			}
+		}
+		return nil
+	}
+	// Прежние метки заменяются, а не накапливаются
+	require.Len(t, series(), 1)
+	assert.True(t, hasLabelValue(series()[0], "check_mode", "first_last"))
+	assert.True(t, hasLabelValue(series()[0], "group", "news"))
+	assert.Equal(t, 1.0, series()[0].GetGauge().GetValue())
+
+	collector.Reset("test_stream")
+	assert.Empty(t, series())
+}
+
+// Тест для Reset: удаляются все серии стрима, включая счетчики и гистограммы
+func testReset(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	for _, name := range []string{"removed", "kept"} {
+		collector.SetStreamUp(name, true)
+		collector.SetStreamInfo(models.StreamConfig{Name: name, URL: "http://a/" + name + ".m3u8", CheckMode: "all"})
+		collector.RecordError(name, "timeout")
+		collector.RecordResponseTime(name, models.ResponseTimeSegment, 0.1, "")
+		collector.RecordHTTPResponse(name, models.ResponseTimeSegment, 200)
+		collector.SetStreamBitrate(name, 1000)
+	}
+
+	collector.Reset("removed")
+
+	streams := func() map[string]int {
+		families, err := reg.Gather()
+		require.NoError(t, err)
+		series := make(map[string]int)
+		for _, family := range families {
+			for _, m := range family.GetMetric() {
+				for _, pair := range m.GetLabel() {
+					if pair.GetName() == "name" {
+						series[pair.GetValue()]++
+					}
+				}
+			}
+		}
+		return series
+	}
+	assert.Zero(t, streams()["removed"])
+	assert.NotZero(t, streams()["kept"])
+
+	// Сглаженное значение нового стрима с тем же именем начинается заново
+	collector.SetStreamBitrate("removed", 2000)
+	c := collector.(*Collector)
+	assert.Equal(t, 2000.0, getGaugeValue(c.bitrateEWMA.WithLabelValues("removed")))
+}
+
+// Тест для SetWorkerPool и RecordQueueWait
+func testSetWorkerPool(t *testing.T, reg *prometheus.Registry, collector models.MetricsCollector) {
+	collector.SetWorkerPool(3, 7)
+	collector.RecordQueueWait("test_stream", 0.3)
+
+	c := collector.(*Collector)
+	assert.Equal(t, 3.0, getGaugeValue(c.workerBusy))
+	assert.Equal(t, 7.0, getGaugeValue(c.queueDepth))
+
+	metrics, err := reg.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, m := range metrics {
+		if m.GetName() == MetricQueueWait {
+			found = true
+			require.Len(t, m.GetMetric(), 1)
+			assert.True(t, hasLabelValue(m.GetMetric()[0], "name", "test_stream"))
+			assert.Equal(t, uint64(1), m.GetMetric()[0].GetHistogram().GetSampleCount())
+			assert.InDelta(t, 0.3, m.GetMetric()[0].GetHistogram().GetSampleSum(), 1e-9)
+		}
+	}
+	assert.True(t, found, "queue wait histogram not found")
+}
+
+// Тест для SetLastSuccessTime
+func testSetLastSuccessTime(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	now := time.Now()
+	collector.SetLastSuccessTime("test_stream", now)
+
+	c := collector.(*Collector)
+	assert.Equal(t, float64(now.Unix()), getGaugeValue(c.lastSuccess.WithLabelValues("test_stream")))
+	// Время последней проверки обновляется отдельно
+	assert.Zero(t, getGaugeValue(c.lastCheck.WithLabelValues("test_stream")))
+}
+
+// Тест для RecordCheckResult
+func testRecordCheckResult(t *testing.T, _ *prometheus.Registry, collector models.MetricsCollector) {
+	now := time.Now()
+	collector.RecordCheckResult("test_stream", true, now)
+	collector.RecordCheckResult("test_stream", false, now)
+
+	c := collector.(*Collector)
+	assert.Equal(t, 1.0, getCounterValue(c.checksTotal.WithLabelValues("test_stream", "success")))
+	assert.Equal(t, 1.0, getCounterValue(c.checksTotal.WithLabelValues("test_stream", "failure")))
+	for _, window := range []string{"5m", "1h", "24h"} {
+		assert.Equal(t, 0.5, getGaugeValue(c.successRatio.WithLabelValues("test_stream", window)), window)
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+)
+
+// ewmaAlpha вес нового значения в сглаженных метриках: влияние значения
+// уменьшается вдвое примерно за три проверки
+const ewmaAlpha = 0.2
+
+// ewma хранит экспоненциально сглаженные значения серий между проверками
+type ewma struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func newEWMA() *ewma {
+	return &ewma{values: make(map[string]float64)}
+}
+
+// Update учитывает новое значение серии и возвращает сглаженное.
+// Первое значение серии принимается как есть.
+func (e *ewma) Update(value float64, series ...string) float64 {
+	key := strings.Join(series, "\xff")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	prev, ok := e.values[key]
+	if ok {
+		value = prev + ewmaAlpha*(value-prev)
+	}
+	e.values[key] = value
+	return value
+}
+
+// Delete забывает сглаженные значения всех серий стрима
+func (e *ewma) Delete(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key := range e.values {
+		// Ключ: имя метрики, имя стрима, остальные метки
+		if series := strings.Split(key, "\xff"); len(series) > 1 && series[1] == name {
+			delete(e.values, key)
+		}
+	}
+}
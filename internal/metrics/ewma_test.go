@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEWMA_Update(t *testing.T) {
+	e := newEWMA()
+
+	assert.Equal(t, 10.0, e.Update(10, "latency", "s", "v"))
+	assert.InDelta(t, 12.0, e.Update(20, "latency", "s", "v"), 1e-9)
+	assert.InDelta(t, 9.6, e.Update(0, "latency", "s", "v"), 1e-9)
+
+	// Серии с разными метками сглаживаются независимо
+	assert.Equal(t, 100.0, e.Update(100, "latency", "s", "other"))
+	assert.Equal(t, 5.0, e.Update(5, "bitrate", "s", "v"))
+}
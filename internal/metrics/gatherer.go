@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// CachingGatherer кэширует результат Gather на короткое время.
+// При тысячах серий сбор метрик дорогой, а несколько Prometheus (HA-пара,
+// федерация) скрейпят экспортер почти одновременно - повторные запросы
+// в пределах ttl обслуживаются из кэша.
+type CachingGatherer struct {
+	gatherer prometheus.Gatherer
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	families   []*dto.MetricFamily
+	err        error
+	gatheredAt time.Time
+}
+
+var _ prometheus.Gatherer = (*CachingGatherer)(nil)
+
+// NewCachingGatherer оборачивает gatherer кэшем; при ttl <= 0 кэширование отключено
+func NewCachingGatherer(gatherer prometheus.Gatherer, ttl time.Duration) prometheus.Gatherer {
+	if ttl <= 0 {
+		return gatherer
+	}
+	return &CachingGatherer{
+		gatherer: gatherer,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// Gather возвращает закэшированные метрики или собирает их заново.
+// Одновременные запросы при устаревшем кэше выполняют только один сбор.
+func (g *CachingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.gatheredAt.IsZero() && g.now().Sub(g.gatheredAt) < g.ttl {
+		return g.families, g.err
+	}
+
+	g.families, g.err = g.gatherer.Gather()
+	g.gatheredAt = g.now()
+	return g.families, g.err
+}
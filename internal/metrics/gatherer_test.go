@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingGatherer struct {
+	calls int
+}
+
+func (g *countingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	g.calls++
+	return []*dto.MetricFamily{}, nil
+}
+
+func TestCachingGatherer(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		inner := &countingGatherer{}
+		assert.Same(t, prometheus.Gatherer(inner), NewCachingGatherer(inner, 0))
+	})
+
+	t.Run("cached within ttl", func(t *testing.T) {
+		inner := &countingGatherer{}
+		now := time.Now()
+		g := NewCachingGatherer(inner, time.Second).(*CachingGatherer)
+		g.now = func() time.Time { return now }
+
+		_, err := g.Gather()
+		require.NoError(t, err)
+		_, err = g.Gather()
+		require.NoError(t, err)
+		assert.Equal(t, 1, inner.calls)
+
+		now = now.Add(time.Second)
+		_, err = g.Gather()
+		require.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+}
+
+// newLargeRegistry создает регистр с числом серий, характерным для тысяч стримов
+func newLargeRegistry(streams int) *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	c := NewCollector(reg)
+	for i := 0; i < streams; i++ {
+		name := fmt.Sprintf("stream_%d", i)
+		c.SetStreamUp(name, true)
+		c.RecordCheckDuration(name, 0.5, "")
+		c.SetLastCheckTime(name, time.Now())
+		c.RecordSegmentCheck(name, true)
+		c.SetSegmentsCount(name, 5)
+		c.SetStreamBitrate(name, 1e6)
+		c.AddDownloadedBytes(name, 1024)
+	}
+	return reg
+}
+
+func BenchmarkGather(b *testing.B) {
+	reg := newLargeRegistry(2000)
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := reg.Gather(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		g := NewCachingGatherer(reg, time.Minute)
+		for i := 0; i < b.N; i++ {
+			if _, err := g.Gather(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// streamLabelName метка имени стрима, по которой серии сопоставляются со стримом
+const streamLabelName = "name"
+
+// silencedLabelName метка подавленных правилом silences стримов (Options.SilencedLabel)
+const silencedLabelName = "silenced"
+
+// streamLabels хранит пользовательские метки стримов из labels конфигурации
+// и метку silenced
+type streamLabels struct {
+	mu       sync.RWMutex
+	labels   map[string][]*dto.LabelPair
+	silenced map[string]*dto.LabelPair
+}
+
+func newStreamLabels() *streamLabels {
+	return &streamLabels{
+		labels:   make(map[string][]*dto.LabelPair),
+		silenced: make(map[string]*dto.LabelPair),
+	}
+}
+
+// Set заменяет метки стрима; пустой набор удаляет их
+func (s *streamLabels) Set(name string, labels map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(labels) == 0 {
+		delete(s.labels, name)
+		return
+	}
+	pairs := make([]*dto.LabelPair, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		value := labels[key]
+		pairs = append(pairs, &dto.LabelPair{Name: &key, Value: &value})
+	}
+	s.labels[name] = pairs
+}
+
+// SetSilenced задает метку silenced стрима
+func (s *streamLabels) SetSilenced(name string, silenced bool) {
+	key, value := silencedLabelName, strconv.FormatBool(silenced)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.silenced[name] = &dto.LabelPair{Name: &key, Value: &value}
+}
+
+// Delete удаляет метки стрима
+func (s *streamLabels) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.labels, name)
+	delete(s.silenced, name)
+}
+
+// labelingGatherer добавляет пользовательские метки стрима ко всем сериям с его меткой name
+type labelingGatherer struct {
+	gatherer prometheus.Gatherer
+	labels   *streamLabels
+}
+
+// WithStreamLabels оборачивает gatherer так, что серии стрима получают его метки из labels.
+// Собственные метки серии имеют приоритет над одноименными пользовательскими.
+func (c *Collector) WithStreamLabels(gatherer prometheus.Gatherer) prometheus.Gatherer {
+	return &labelingGatherer{gatherer: gatherer, labels: c.labels}
+}
+
+// Gather дополняет метки серий. Серии изменяются на месте: Registry.Gather
+// каждый раз строит их заново.
+func (g *labelingGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+
+	g.labels.mu.RLock()
+	defer g.labels.mu.RUnlock()
+	if len(g.labels.labels) == 0 && len(g.labels.silenced) == 0 {
+		return families, err
+	}
+
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			name := streamName(metric)
+			extra := g.labels.labels[name]
+			if silenced := g.labels.silenced[name]; silenced != nil {
+				extra = append(slices.Clip(extra), silenced)
+			}
+			if len(extra) == 0 {
+				continue
+			}
+			for _, pair := range extra {
+				if !hasLabel(metric, pair.GetName()) {
+					metric.Label = append(metric.Label, pair)
+				}
+			}
+			sort.Slice(metric.Label, func(i, j int) bool {
+				return metric.Label[i].GetName() < metric.Label[j].GetName()
+			})
+		}
+	}
+	return families, err
+}
+
+// streamName возвращает значение метки name серии
+func streamName(metric *dto.Metric) string {
+	for _, pair := range metric.Label {
+		if pair.GetName() == streamLabelName {
+			return pair.GetValue()
+		}
+	}
+	return ""
+}
+
+func hasLabel(metric *dto.Metric, name string) bool {
+	for _, pair := range metric.Label {
+		if pair.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_WithStreamLabels(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := NewCollectorWithOptions(reg, Options{})
+	gatherer := collector.WithStreamLabels(reg)
+
+	collector.SetStreamInfo(models.StreamConfig{
+		Name:      "news",
+		URL:       "http://a/news.m3u8",
+		CheckMode: "all",
+		Labels:    map[string]string{"region": "eu", "cdn": "edge1", "url": "ignored"},
+	})
+	collector.SetStreamInfo(models.StreamConfig{Name: "sport", URL: "http://a/sport.m3u8", CheckMode: "all"})
+	collector.SetStreamUp("news", true)
+	collector.SetStreamUp("sport", true)
+
+	series := func(metric, stream string) *dto.Metric {
+		families, err := gatherer.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() != metric {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				if hasLabelValue(m, "name", stream) {
+					return m
+				}
+			}
+		}
+		return nil
+	}
+
+	up := series(MetricStreamUp, "news")
+	require.NotNil(t, up)
+	assert.True(t, hasLabelValue(up, "region", "eu"))
+	assert.True(t, hasLabelValue(up, "cdn", "edge1"))
+	// Метки остаются отсортированными по имени
+	names := make([]string, 0, len(up.GetLabel()))
+	for _, pair := range up.GetLabel() {
+		names = append(names, pair.GetName())
+	}
+	assert.IsIncreasing(t, names)
+
+	// Собственная метка серии не перезаписывается
+	info := series(MetricStreamInfo, "news")
+	require.NotNil(t, info)
+	assert.True(t, hasLabelValue(info, "url", "http://a/news.m3u8"))
+
+	// Стрим без меток не меняется
+	assert.Len(t, series(MetricStreamUp, "sport").GetLabel(), 1)
+
+	// Метки удаленного стрима забываются
+	collector.Reset("news")
+	collector.SetStreamUp("news", true)
+	assert.Len(t, series(MetricStreamUp, "news").GetLabel(), 1)
+}
+
+func TestCollector_SilencedLabel(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := NewCollectorWithOptions(reg, Options{SilencedLabel: true})
+	gatherer := collector.WithStreamLabels(reg)
+
+	collector.SetStreamInfo(models.StreamConfig{Name: "news", Labels: map[string]string{"region": "eu"}})
+	collector.SetStreamUp("news", false)
+	collector.SetStreamUp("sport", false)
+
+	up := func(stream string) *dto.Metric {
+		families, err := gatherer.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() != MetricStreamUp {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				if hasLabelValue(m, "name", stream) {
+					return m
+				}
+			}
+		}
+		return nil
+	}
+
+	// Метка появляется после первой оценки правил silences
+	assert.False(t, hasLabel(up("news"), silencedLabelName))
+
+	collector.SetStreamSilenced("news", true)
+	collector.SetStreamSilenced("sport", false)
+	assert.True(t, hasLabelValue(up("news"), silencedLabelName, "true"))
+	assert.True(t, hasLabelValue(up("news"), "region", "eu"))
+	assert.True(t, hasLabelValue(up("sport"), silencedLabelName, "false"))
+
+	// Без опции метка не публикуется
+	reg = prometheus.NewRegistry()
+	collector = NewCollectorWithOptions(reg, Options{})
+	gatherer = collector.WithStreamLabels(reg)
+	collector.SetStreamUp("news", false)
+	collector.SetStreamSilenced("news", true)
+	assert.False(t, hasLabel(up("news"), silencedLabelName))
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// slaBuckets число интервалов, на которые делится окно доли успешных проверок
+const slaBuckets = 60
+
+// DefaultSLAWindows окна hls_stream_success_ratio по умолчанию
+var DefaultSLAWindows = []time.Duration{5 * time.Minute, time.Hour, 24 * time.Hour}
+
+// slaBucket число проверок за один интервал окна
+type slaBucket struct {
+	// Номер интервала от начала эпохи; устаревший интервал обнуляется при записи
+	index   int64
+	success int
+	total   int
+}
+
+// slaWindow скользящее окно из slaBuckets интервалов
+type slaWindow struct {
+	step    time.Duration
+	buckets [slaBuckets]slaBucket
+}
+
+func (w *slaWindow) record(t time.Time, success bool) {
+	index := t.UnixNano() / int64(w.step)
+	b := &w.buckets[index%slaBuckets]
+	if b.index != index {
+		*b = slaBucket{index: index}
+	}
+	b.total++
+	if success {
+		b.success++
+	}
+}
+
+// ratio возвращает долю успешных проверок в окне, заканчивающемся в now,
+// и false, если проверок в окне не было
+func (w *slaWindow) ratio(now time.Time) (float64, bool) {
+	current := now.UnixNano() / int64(w.step)
+	var success, total int
+	for _, b := range w.buckets {
+		if b.index > current-slaBuckets && b.index <= current {
+			success += b.success
+			total += b.total
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(success) / float64(total), true
+}
+
+// slaTracker хранит окна долей успешных проверок стримов между проверками
+type slaTracker struct {
+	mu      sync.Mutex
+	windows []time.Duration
+	streams map[string][]*slaWindow
+}
+
+func newSLATracker(windows []time.Duration) *slaTracker {
+	return &slaTracker{windows: windows, streams: make(map[string][]*slaWindow)}
+}
+
+// Record учитывает проверку и вызывает set с долей успешных проверок каждого окна
+func (s *slaTracker) Record(name string, success bool, t time.Time, set func(window time.Duration, ratio float64)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	windows, ok := s.streams[name]
+	if !ok {
+		windows = make([]*slaWindow, len(s.windows))
+		for i, size := range s.windows {
+			windows[i] = &slaWindow{step: max(size/slaBuckets, 1)}
+		}
+		s.streams[name] = windows
+	}
+	for i, w := range windows {
+		w.record(t, success)
+		if ratio, ok := w.ratio(t); ok {
+			set(s.windows[i], ratio)
+		}
+	}
+}
+
+// Delete забывает проверки стрима
+func (s *slaTracker) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.streams, name)
+}
+
+// windowLabel форматирует окно для метки window без нулевых единиц: 5m, 1h, 1h30m
+func windowLabel(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
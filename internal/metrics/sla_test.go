@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLATracker(t *testing.T) {
+	tracker := newSLATracker([]time.Duration{5 * time.Minute, time.Hour})
+	start := time.Unix(1_700_000_000, 0)
+
+	var ratios map[time.Duration]float64
+	record := func(success bool, at time.Time) {
+		ratios = make(map[time.Duration]float64)
+		tracker.Record("stream", success, at, func(window time.Duration, ratio float64) {
+			ratios[window] = ratio
+		})
+	}
+
+	record(true, start)
+	record(true, start.Add(time.Minute))
+	record(false, start.Add(2*time.Minute))
+	record(true, start.Add(3*time.Minute))
+	assert.Equal(t, map[time.Duration]float64{5 * time.Minute: 0.75, time.Hour: 0.75}, ratios)
+
+	// Через 10 минут ранние проверки выходят из 5-минутного окна, но остаются в часовом
+	record(false, start.Add(10*time.Minute))
+	assert.Equal(t, 0.0, ratios[5*time.Minute])
+	assert.Equal(t, 0.6, ratios[time.Hour])
+
+	// После удаления стрима учет начинается заново
+	tracker.Delete("stream")
+	record(true, start.Add(11*time.Minute))
+	assert.Equal(t, 1.0, ratios[time.Hour])
+}
+
+func TestWindowLabel(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		5 * time.Minute:  "5m",
+		time.Hour:        "1h",
+		24 * time.Hour:   "24h",
+		90 * time.Minute: "1h30m",
+		90 * time.Second: "1m30s",
+	} {
+		assert.Equal(t, want, windowLabel(d))
+	}
+}
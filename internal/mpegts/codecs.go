@@ -0,0 +1,32 @@
+package mpegts
+
+// Типы элементарных потоков PMT (ISO/IEC 13818-1, ATSC A/52)
+const (
+	StreamTypeMPEG1Audio = 0x03
+	StreamTypeMPEG2Audio = 0x04
+	StreamTypeAACADTS    = 0x0F
+	StreamTypeAACLATM    = 0x11
+	StreamTypeH264       = 0x1B
+	StreamTypeH265       = 0x24
+	StreamTypeAC3        = 0x81
+	StreamTypeEAC3       = 0x87
+)
+
+// CodecFamily возвращает семейство кодека атрибута CODECS для типа элементарного потока.
+// Для неизвестных типов и частных данных (например, 0x06) возвращает пустую строку.
+// MP3 и MPEG-аудио в HLS объявляются как mp4a.40.34 и mp4a.6B.
+func CodecFamily(streamType uint8) string {
+	switch streamType {
+	case StreamTypeH264:
+		return "avc1"
+	case StreamTypeH265:
+		return "hvc1"
+	case StreamTypeAACADTS, StreamTypeAACLATM, StreamTypeMPEG1Audio, StreamTypeMPEG2Audio:
+		return "mp4a"
+	case StreamTypeAC3:
+		return "ac-3"
+	case StreamTypeEAC3:
+		return "ec-3"
+	}
+	return ""
+}
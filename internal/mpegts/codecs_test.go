@@ -0,0 +1,16 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecFamily(t *testing.T) {
+	assert.Equal(t, "avc1", CodecFamily(StreamTypeH264))
+	assert.Equal(t, "hvc1", CodecFamily(StreamTypeH265))
+	assert.Equal(t, "mp4a", CodecFamily(StreamTypeAACADTS))
+	assert.Equal(t, "mp4a", CodecFamily(StreamTypeMPEG1Audio))
+	assert.Equal(t, "ec-3", CodecFamily(StreamTypeEAC3))
+	assert.Empty(t, CodecFamily(0x06))
+}
@@ -0,0 +1,42 @@
+package mpegts
+
+// ccState последний счетчик непрерывности PID
+type ccState struct {
+	cc        byte
+	duplicate bool
+}
+
+// checkContinuity проверяет continuity_counter пакета по ISO/IEC 13818-1:
+// счетчик растет по модулю 16 только в пакетах с полезной нагрузкой,
+// допускается один повтор пакета, discontinuity_indicator сбрасывает проверку.
+func (s *Scanner) checkContinuity(pid uint16, pkt []byte) {
+	cc := pkt[3] & 0x0F
+	control := pkt[3] >> 4 & 0x03
+	hasPayload := control&0x01 != 0
+	discontinuity := control&0x02 != 0 && pkt[4] > 0 && pkt[5]&0x80 != 0
+
+	st, ok := s.continuity[pid]
+	if !ok || discontinuity {
+		s.continuity[pid] = &ccState{cc: cc}
+		return
+	}
+
+	switch {
+	case !hasPayload:
+		// Без полезной нагрузки счетчик не меняется
+		if cc != st.cc {
+			s.ccErrors++
+		}
+	case cc == st.cc:
+		if st.duplicate {
+			s.ccErrors++
+		}
+		st.duplicate = true
+		return
+	case cc != (st.cc+1)&0x0F:
+		s.ccErrors++
+	}
+
+	st.cc = cc
+	st.duplicate = false
+}
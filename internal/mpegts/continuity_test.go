@@ -0,0 +1,104 @@
+package mpegts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ccPacket пакет PID с заданным счетчиком непрерывности.
+// adaptation добавляет поле адаптации, payload - полезную нагрузку.
+func ccPacket(pid uint16, cc byte, payload, adaptation, discontinuity bool) []byte {
+	control := byte(0)
+	if payload {
+		control |= 0x10
+	}
+	if adaptation {
+		control |= 0x20
+	}
+	pkt := []byte{syncByte, byte(pid >> 8), byte(pid), control | cc&0x0F}
+	if adaptation {
+		flags := byte(0)
+		if discontinuity {
+			flags = 0x80
+		}
+		pkt = append(pkt, 1, flags)
+	}
+	return pad(pkt)
+}
+
+func TestScanner_CCErrors(t *testing.T) {
+	data := func(pkts ...[]byte) []byte { return bytes.Join(pkts, nil) }
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{
+			name: "continuous with wrap",
+			data: data(
+				ccPacket(0x100, 14, true, false, false),
+				ccPacket(0x100, 15, true, false, false),
+				ccPacket(0x100, 0, true, false, false),
+				ccPacket(0x101, 7, true, false, false),
+				ccPacket(0x100, 1, true, false, false),
+			),
+		},
+		{
+			name: "lost packet",
+			data: data(
+				ccPacket(0x100, 1, true, false, false),
+				ccPacket(0x100, 3, true, false, false),
+				ccPacket(0x100, 4, true, false, false),
+			),
+			want: 1,
+		},
+		{
+			name: "single duplicate allowed, second counted",
+			data: data(
+				ccPacket(0x100, 1, true, false, false),
+				ccPacket(0x100, 1, true, false, false),
+				ccPacket(0x100, 1, true, false, false),
+			),
+			want: 1,
+		},
+		{
+			name: "adaptation only keeps counter",
+			data: data(
+				ccPacket(0x100, 5, true, false, false),
+				ccPacket(0x100, 5, false, true, false),
+				ccPacket(0x100, 6, false, true, false),
+				ccPacket(0x100, 6, true, false, false),
+			),
+			// Пакет без нагрузки со сменой счетчика - ошибка, следующий пакет продолжает от него
+			want: 1,
+		},
+		{
+			name: "discontinuity indicator resets",
+			data: data(
+				ccPacket(0x100, 5, true, false, false),
+				ccPacket(0x100, 11, true, true, true),
+				ccPacket(0x100, 12, true, false, false),
+			),
+		},
+		{
+			name: "null packets ignored",
+			data: data(
+				ccPacket(nullPID, 0, true, false, false),
+				ccPacket(nullPID, 0, true, false, false),
+				ccPacket(nullPID, 0, true, false, false),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner()
+			_, err := s.Write(tt.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.CCErrors())
+		})
+	}
+}
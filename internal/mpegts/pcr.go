@@ -0,0 +1,112 @@
+package mpegts
+
+import (
+	"time"
+)
+
+const (
+	// pcrClock частота системных часов PCR, Гц
+	pcrClock = 27_000_000
+	// pcrWrap период переполнения PCR: 33 бита base * 300 + extension
+	pcrWrap = (1 << 33) * 300
+	// maxPCRSamples ограничивает память на PID для очень длинных сегментов
+	maxPCRSamples = 8192
+)
+
+// PCRStats интервалы и джиттер PCR сегмента
+type PCRStats struct {
+	// Count число PCR во всех PID
+	Count int
+	// MaxInterval наибольший интервал между соседними PCR одного PID
+	MaxInterval time.Duration
+	// Jitter наибольшее отклонение PCR от значения, ожидаемого по позиции
+	// пакета в потоке при постоянном битрейте (аналог PCR_AC из TR 101 290)
+	Jitter time.Duration
+}
+
+// pcrSample значение PCR и номер пакета, в котором оно передано
+type pcrSample struct {
+	packet int
+	pcr    uint64
+}
+
+// recordPCR сохраняет PCR из поля адаптации пакета.
+// discontinuity_indicator начинает новую последовательность PCR.
+func (s *Scanner) recordPCR(pid uint16, pkt []byte) {
+	control := pkt[3] >> 4 & 0x03
+	if control&0x02 == 0 || pkt[4] < 7 || pkt[5]&0x10 == 0 {
+		return
+	}
+
+	base := uint64(pkt[6])<<25 | uint64(pkt[7])<<17 | uint64(pkt[8])<<9 | uint64(pkt[9])<<1 | uint64(pkt[10])>>7
+	ext := uint64(pkt[10]&0x01)<<8 | uint64(pkt[11])
+	sample := pcrSample{packet: s.packets - 1, pcr: base*300 + ext}
+
+	runs := s.pcr[pid]
+	if len(runs) == 0 || pkt[5]&0x80 != 0 {
+		runs = append(runs, nil)
+	}
+	last := len(runs) - 1
+	if len(runs[last]) < maxPCRSamples {
+		runs[last] = append(runs[last], sample)
+	}
+	s.pcr[pid] = runs
+}
+
+// PCRStats возвращает интервалы и джиттер PCR разобранных пакетов
+func (s *Scanner) PCRStats() PCRStats {
+	var (
+		stats            PCRStats
+		interval, jitter uint64
+	)
+	for _, runs := range s.pcr {
+		for _, run := range runs {
+			stats.Count += len(run)
+			interval = max(interval, maxPCRInterval(run))
+			jitter = max(jitter, pcrJitter(run))
+		}
+	}
+	stats.MaxInterval = ticksToDuration(interval)
+	stats.Jitter = ticksToDuration(jitter)
+	return stats
+}
+
+// pcrDiff разность PCR с учетом переполнения
+func pcrDiff(from, to uint64) uint64 {
+	return (to + pcrWrap - from) % pcrWrap
+}
+
+func maxPCRInterval(run []pcrSample) uint64 {
+	var longest uint64
+	for i := 1; i < len(run); i++ {
+		longest = max(longest, pcrDiff(run[i-1].pcr, run[i].pcr))
+	}
+	return longest
+}
+
+// pcrJitter сравнивает PCR с линейной интерполяцией между первым и последним
+// значением последовательности по номеру пакета
+func pcrJitter(run []pcrSample) uint64 {
+	if len(run) < 3 {
+		return 0
+	}
+	first, last := run[0], run[len(run)-1]
+	packets := last.packet - first.packet
+	if packets <= 0 {
+		return 0
+	}
+	rate := float64(pcrDiff(first.pcr, last.pcr)) / float64(packets)
+
+	var worst float64
+	for _, sample := range run[1 : len(run)-1] {
+		expected := rate * float64(sample.packet-first.packet)
+		deviation := float64(pcrDiff(first.pcr, sample.pcr)) - expected
+		worst = max(worst, deviation, -deviation)
+	}
+	return uint64(worst)
+}
+
+// ticksToDuration переводит тики 27 МГц в длительность (27 тиков в микросекунде)
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks * 1000 / (pcrClock / 1_000_000))
+}
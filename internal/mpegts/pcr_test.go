@@ -0,0 +1,100 @@
+package mpegts
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pcrPacket пакет PID с PCR в поле адаптации (в тиках 27 МГц)
+func pcrPacket(pid uint16, pcr uint64, discontinuity bool) []byte {
+	base, ext := pcr/300, pcr%300
+	flags := byte(0x10)
+	if discontinuity {
+		flags |= 0x80
+	}
+	return pad([]byte{
+		syncByte, byte(pid >> 8), byte(pid), 0x30,
+		7, flags,
+		byte(base >> 25), byte(base >> 17), byte(base >> 9), byte(base >> 1),
+		byte(base<<7) | 0x7E | byte(ext>>8), byte(ext),
+	})
+}
+
+// ms переводит миллисекунды в тики PCR
+func ms(v float64) uint64 {
+	return uint64(v * pcrClock / 1000)
+}
+
+func TestScanner_PCRStats(t *testing.T) {
+	data := func(pkts ...[]byte) []byte { return bytes.Join(pkts, nil) }
+
+	tests := []struct {
+		name string
+		data []byte
+		want PCRStats
+	}{
+		{
+			name: "constant rate",
+			data: data(
+				pcrPacket(0x100, ms(0), false), pesPacket(0x100),
+				pcrPacket(0x100, ms(40), false), pesPacket(0x100),
+				pcrPacket(0x100, ms(80), false),
+			),
+			want: PCRStats{Count: 3, MaxInterval: 40 * time.Millisecond},
+		},
+		{
+			name: "jitter",
+			data: data(
+				pcrPacket(0x100, ms(0), false), pesPacket(0x100),
+				pcrPacket(0x100, ms(41), false), pesPacket(0x100),
+				pcrPacket(0x100, ms(80), false),
+			),
+			want: PCRStats{Count: 3, MaxInterval: 41 * time.Millisecond, Jitter: time.Millisecond},
+		},
+		{
+			name: "wrap",
+			data: data(
+				pcrPacket(0x100, pcrWrap-ms(10), false),
+				pcrPacket(0x100, ms(30), false),
+			),
+			want: PCRStats{Count: 2, MaxInterval: 40 * time.Millisecond},
+		},
+		{
+			name: "discontinuity starts new run",
+			data: data(
+				pcrPacket(0x100, ms(1000), false),
+				pcrPacket(0x100, ms(1020), false),
+				pcrPacket(0x100, ms(0), true),
+				pcrPacket(0x100, ms(20), false),
+			),
+			want: PCRStats{Count: 4, MaxInterval: 20 * time.Millisecond},
+		},
+		{
+			name: "PIDs measured separately",
+			data: data(
+				pcrPacket(0x100, ms(0), false),
+				pcrPacket(0x200, ms(500), false),
+				pcrPacket(0x100, ms(30), false),
+				pcrPacket(0x200, ms(560), false),
+			),
+			want: PCRStats{Count: 4, MaxInterval: 60 * time.Millisecond},
+		},
+		{
+			name: "no PCR",
+			data: data(pesPacket(0x100), ccPacket(0x100, 1, true, true, false)),
+			want: PCRStats{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner()
+			_, err := s.Write(tt.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.PCRStats())
+		})
+	}
+}
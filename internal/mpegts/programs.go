@@ -0,0 +1,123 @@
+package mpegts
+
+import (
+	"sort"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Programs возвращает программы, отсортированные по номеру.
+// nil, если данные не MPEG-TS или PAT не найдена.
+func (s *Scanner) Programs() []models.TSProgram {
+	if s.notTS || !s.patSeen {
+		return nil
+	}
+
+	programs := make([]models.TSProgram, 0, len(s.pmtPIDs))
+	for pmtPID, number := range s.pmtPIDs {
+		if p, ok := s.programs[number]; ok {
+			programs = append(programs, *p)
+			continue
+		}
+		// PMT не встретилась в сегменте, известен только PID таблицы
+		programs = append(programs, models.TSProgram{Number: number, PMTPID: pmtPID})
+	}
+	sort.Slice(programs, func(i, j int) bool { return programs[i].Number < programs[j].Number })
+	return programs
+}
+
+// parsePSI разбирает PAT и PMT, пока не получены все объявленные таблицы.
+// Поддерживаются секции, умещающиеся в один пакет.
+func (s *Scanner) parsePSI(pid uint16, pkt []byte) {
+	if s.patSeen && len(s.programs) == len(s.pmtPIDs) {
+		return
+	}
+	if pkt[1]&0x40 == 0 {
+		// Секции начинаются только в пакетах с payload_unit_start_indicator
+		return
+	}
+
+	if pid != patPID {
+		if _, ok := s.pmtPIDs[pid]; !ok {
+			return
+		}
+	}
+
+	section := sectionPayload(pkt)
+	if section == nil {
+		return
+	}
+
+	switch {
+	case pid == patPID && section[0] == tableIDPAT:
+		s.parsePAT(section)
+	case pid != patPID && section[0] == tableIDPMT:
+		s.parsePMT(pid, section)
+	}
+}
+
+// sectionPayload возвращает PSI-секцию пакета без заголовков и CRC,
+// если она целиком умещается в пакете и является текущей
+func sectionPayload(pkt []byte) []byte {
+	control := pkt[3] >> 4 & 0x03
+	if control&0x01 == 0 {
+		return nil // нет полезной нагрузки
+	}
+
+	offset := 4
+	if control&0x02 != 0 {
+		offset += 1 + int(pkt[4])
+	}
+	if offset >= packetSize {
+		return nil
+	}
+
+	offset += 1 + int(pkt[offset]) // pointer_field
+	if offset+3 > packetSize {
+		return nil
+	}
+
+	section := pkt[offset:]
+	length := int(section[1]&0x0F)<<8 | int(section[2])
+	end := 3 + length
+	if end > len(section) || length < 5+crcSize {
+		return nil
+	}
+	if section[5]&0x01 == 0 {
+		return nil // current_next_indicator: таблица еще не действует
+	}
+	return section[:end-crcSize]
+}
+
+func (s *Scanner) parsePAT(section []byte) {
+	s.patSeen = true
+	for i := 8; i+4 <= len(section); i += 4 {
+		number := uint16(section[i])<<8 | uint16(section[i+1])
+		pid := uint16(section[i+2]&0x1F)<<8 | uint16(section[i+3])
+		if number == 0 {
+			continue // network PID
+		}
+		s.pmtPIDs[pid] = number
+	}
+}
+
+func (s *Scanner) parsePMT(pmtPID uint16, section []byte) {
+	if len(section) < 12 {
+		return
+	}
+
+	number := uint16(section[3])<<8 | uint16(section[4])
+	program := &models.TSProgram{Number: number, PMTPID: pmtPID}
+
+	infoLength := int(section[10]&0x0F)<<8 | int(section[11])
+	for i := 12 + infoLength; i+5 <= len(section); {
+		program.Streams = append(program.Streams, models.TSStream{
+			StreamType: section[i],
+			PID:        uint16(section[i+1]&0x1F)<<8 | uint16(section[i+2]),
+		})
+		i += 5 + (int(section[i+3]&0x0F)<<8 | int(section[i+4]))
+	}
+
+	sort.Slice(program.Streams, func(i, j int) bool { return program.Streams[i].PID < program.Streams[j].PID })
+	s.programs[number] = program
+}
@@ -0,0 +1,116 @@
+package mpegts
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// psiPacket упаковывает PSI-секцию (без CRC, он не проверяется) в TS-пакет
+func psiPacket(pid uint16, tableID byte, body []byte) []byte {
+	length := len(body) + crcSize
+	pkt := []byte{
+		syncByte, 0x40 | byte(pid>>8), byte(pid), 0x10,
+		0x00, // pointer_field
+		tableID, 0xB0 | byte(length>>8), byte(length),
+	}
+	pkt = append(pkt, body...)
+	pkt = append(pkt, 0, 0, 0, 0)
+	return pad(pkt)
+}
+
+func pesPacket(pid uint16) []byte {
+	return pad([]byte{syncByte, byte(pid >> 8), byte(pid), 0x10})
+}
+
+func pad(pkt []byte) []byte {
+	for len(pkt) < packetSize {
+		pkt = append(pkt, 0xFF)
+	}
+	return pkt
+}
+
+func patPacket(programs ...[2]uint16) []byte {
+	body := []byte{0x00, 0x01, 0xC1, 0x00, 0x00}
+	for _, p := range programs {
+		body = append(body, byte(p[0]>>8), byte(p[0]), 0xE0|byte(p[1]>>8), byte(p[1]))
+	}
+	return psiPacket(patPID, tableIDPAT, body)
+}
+
+func pmtPacket(pmtPID, number uint16, streams ...models.TSStream) []byte {
+	body := []byte{byte(number >> 8), byte(number), 0xC1, 0x00, 0x00, 0xE1, 0x00, 0xF0, 0x00}
+	for _, s := range streams {
+		body = append(body, s.StreamType, 0xE0|byte(s.PID>>8), byte(s.PID), 0xF0, 0x00)
+	}
+	return psiPacket(pmtPID, tableIDPMT, body)
+}
+
+func scan(t *testing.T, data []byte, chunk int) []models.TSProgram {
+	t.Helper()
+	s := NewScanner()
+	// Пишем порциями, не кратными размеру пакета
+	n, err := io.CopyBuffer(s, struct{ io.Reader }{bytes.NewReader(data)}, make([]byte, chunk))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	return s.Programs()
+}
+
+func TestScanner_Programs(t *testing.T) {
+	video := models.TSStream{PID: 0x100, StreamType: 0x1B}
+	audio := models.TSStream{PID: 0x101, StreamType: 0x0F}
+
+	tests := []struct {
+		name string
+		data [][]byte
+		want []models.TSProgram
+	}{
+		{
+			name: "single program",
+			data: [][]byte{
+				patPacket([2]uint16{1, 0x1000}),
+				pmtPacket(0x1000, 1, audio, video),
+				pesPacket(0x100),
+			},
+			want: []models.TSProgram{{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{video, audio}}},
+		},
+		{
+			name: "network PID skipped, programs sorted",
+			data: [][]byte{
+				patPacket([2]uint16{0, 0x0010}, [2]uint16{2, 0x1100}, [2]uint16{1, 0x1000}),
+				pmtPacket(0x1100, 2, audio),
+				pmtPacket(0x1000, 1, video),
+			},
+			want: []models.TSProgram{
+				{Number: 1, PMTPID: 0x1000, Streams: []models.TSStream{video}},
+				{Number: 2, PMTPID: 0x1100, Streams: []models.TSStream{audio}},
+			},
+		},
+		{
+			name: "PMT missing",
+			data: [][]byte{patPacket([2]uint16{1, 0x1000}), pesPacket(0x100)},
+			want: []models.TSProgram{{Number: 1, PMTPID: 0x1000}},
+		},
+		{
+			name: "no PAT",
+			data: [][]byte{pesPacket(0x100)},
+			want: nil,
+		},
+		{
+			name: "not TS",
+			data: [][]byte{pad([]byte("\x00\x00\x00\x18ftypmp42"))},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := bytes.Join(tt.data, nil)
+			assert.Equal(t, tt.want, scan(t, data, 100))
+			assert.Equal(t, tt.want, scan(t, data, 4096))
+		})
+	}
+}
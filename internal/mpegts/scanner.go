@@ -0,0 +1,121 @@
+// Package mpegts анализирует транспортный поток MPEG-TS для мониторинга
+package mpegts
+
+import (
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+const (
+	packetSize = 188
+	syncByte   = 0x47
+
+	patPID  uint16 = 0x0000
+	nullPID uint16 = 0x1FFF
+
+	tableIDPAT = 0x00
+	tableIDPMT = 0x02
+
+	// crcSize длина CRC_32 в конце PSI-секции
+	crcSize = 4
+)
+
+// Scanner разбирает поток MPEG-TS: собирает программы из PAT и PMT,
+// считает ошибки счетчиков непрерывности и null-пакеты, измеряет PCR. Реализует io.Writer, чтобы
+// анализировать тело сегмента по мере загрузки без буферизации целиком.
+type Scanner struct {
+	pending []byte
+	packets int
+	// nullPackets пакеты заполнения (PID 0x1FFF)
+	nullPackets int
+	notTS       bool
+	// desync поток потерял синхронизацию, дальнейшие пакеты не разбираются
+	desync bool
+
+	patSeen  bool
+	pmtPIDs  map[uint16]uint16 // PMT PID -> program_number
+	programs map[uint16]*models.TSProgram
+
+	continuity map[uint16]*ccState
+	ccErrors   int
+
+	pcr map[uint16][][]pcrSample
+}
+
+func NewScanner() *Scanner {
+	return &Scanner{
+		pmtPIDs:    make(map[uint16]uint16),
+		programs:   make(map[uint16]*models.TSProgram),
+		continuity: make(map[uint16]*ccState),
+		pcr:        make(map[uint16][][]pcrSample),
+	}
+}
+
+// Write разбирает очередную порцию данных. Ошибок не возвращает: данные,
+// не похожие на MPEG-TS, просто игнорируются.
+func (s *Scanner) Write(p []byte) (int, error) {
+	n := len(p)
+	if s.stopped() {
+		return n, nil
+	}
+
+	if len(s.pending) > 0 {
+		need := packetSize - len(s.pending)
+		if len(p) < need {
+			s.pending = append(s.pending, p...)
+			return n, nil
+		}
+		s.pending = append(s.pending, p[:need]...)
+		p = p[need:]
+		s.packet(s.pending)
+		s.pending = s.pending[:0]
+	}
+
+	for len(p) >= packetSize && !s.stopped() {
+		s.packet(p[:packetSize])
+		p = p[packetSize:]
+	}
+
+	if len(p) > 0 && !s.stopped() {
+		s.pending = append(s.pending, p...)
+	}
+	return n, nil
+}
+
+// Packets возвращает число разобранных пакетов и null-пакетов среди них
+func (s *Scanner) Packets() (total, null int) {
+	if s.notTS {
+		return 0, 0
+	}
+	return s.packets, s.nullPackets
+}
+
+// CCErrors возвращает число нарушений счетчиков непрерывности
+func (s *Scanner) CCErrors() int {
+	return s.ccErrors
+}
+
+func (s *Scanner) stopped() bool {
+	return s.notTS || s.desync
+}
+
+func (s *Scanner) packet(pkt []byte) {
+	if pkt[0] != syncByte {
+		if s.packets == 0 {
+			s.notTS = true
+		} else {
+			s.desync = true
+		}
+		return
+	}
+	s.packets++
+
+	pid := uint16(pkt[1]&0x1F)<<8 | uint16(pkt[2])
+	if pid == nullPID {
+		s.nullPackets++
+		return
+	}
+
+	s.checkContinuity(pid, pkt)
+	s.recordPCR(pid, pkt)
+	s.parsePSI(pid, pkt)
+}
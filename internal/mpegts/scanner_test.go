@@ -0,0 +1,49 @@
+package mpegts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanner_Packets(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		wantTotal int
+		wantNull  int
+	}{
+		{
+			name: "stuffing counted",
+			data: bytes.Join([][]byte{
+				pesPacket(0x100),
+				pesPacket(nullPID),
+				pesPacket(0x101),
+				pesPacket(nullPID),
+			}, nil),
+			wantTotal: 4,
+			wantNull:  2,
+		},
+		{
+			name:      "no stuffing",
+			data:      bytes.Join([][]byte{pesPacket(0x100), pesPacket(0x101)}, nil),
+			wantTotal: 2,
+		},
+		{
+			name: "not TS",
+			data: pad([]byte("\x00\x00\x00\x18ftypmp42")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScanner()
+			_, err := s.Write(tt.data)
+			assert.NoError(t, err)
+			total, null := s.Packets()
+			assert.Equal(t, tt.wantTotal, total)
+			assert.Equal(t, tt.wantNull, null)
+		})
+	}
+}
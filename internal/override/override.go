@@ -0,0 +1,105 @@
+package override
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Override временное переопределение параметров проверки стрима
+type Override struct {
+	Interval        time.Duration
+	CheckMode       string
+	ValidateContent *bool
+	ExpiresAt       time.Time
+}
+
+// Store хранит активные переопределения и уведомляет о их изменении
+type Store struct {
+	mu        sync.Mutex
+	overrides map[string]Override
+	changed   chan struct{}
+	now       func() time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		overrides: make(map[string]Override),
+		changed:   make(chan struct{}),
+		now:       time.Now,
+	}
+}
+
+// Set устанавливает переопределение для стрима, заменяя предыдущее
+func (s *Store) Set(name string, o Override) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.overrides[name] = o
+	s.notify()
+}
+
+// Delete снимает переопределение стрима
+func (s *Store) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.overrides[name]
+	if ok {
+		delete(s.overrides, name)
+		s.notify()
+	}
+	return ok
+}
+
+// Get возвращает действующее переопределение; истекшие удаляются
+func (s *Store) Get(name string) (Override, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.overrides[name]
+	if !ok {
+		return Override{}, false
+	}
+	if !s.now().Before(o.ExpiresAt) {
+		delete(s.overrides, name)
+		return Override{}, false
+	}
+	return o, true
+}
+
+// Apply возвращает конфигурацию стрима с учетом действующего переопределения
+func (s *Store) Apply(stream models.StreamConfig) models.StreamConfig {
+	o, ok := s.Get(stream.Name)
+	if !ok {
+		return stream
+	}
+
+	if o.Interval > 0 {
+		stream.Interval = o.Interval
+		// Таймаут не может превышать интервал проверки
+		if stream.Timeout >= o.Interval {
+			stream.Timeout = o.Interval
+		}
+	}
+	if o.CheckMode != "" {
+		stream.CheckMode = o.CheckMode
+	}
+	if o.ValidateContent != nil {
+		stream.ValidateContent = *o.ValidateContent
+	}
+	return stream
+}
+
+// Changed возвращает канал, закрываемый при следующем изменении переопределений
+func (s *Store) Changed() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+func (s *Store) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
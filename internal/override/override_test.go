@@ -0,0 +1,56 @@
+package override
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	now := time.Now()
+	store := NewStore()
+	store.now = func() time.Time { return now }
+
+	stream := models.StreamConfig{
+		Name:      "test",
+		CheckMode: models.CheckModeFirstLast,
+		Interval:  time.Minute,
+		Timeout:   15 * time.Second,
+	}
+
+	assert.Equal(t, stream, store.Apply(stream), "no override should keep config")
+
+	changed := store.Changed()
+	validate := true
+	store.Set("test", Override{
+		Interval:        10 * time.Second,
+		CheckMode:       models.CheckModeAll,
+		ValidateContent: &validate,
+		ExpiresAt:       now.Add(time.Hour),
+	})
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("Set should notify subscribers")
+	}
+
+	effective := store.Apply(stream)
+	assert.Equal(t, 10*time.Second, effective.Interval)
+	assert.Equal(t, 10*time.Second, effective.Timeout, "timeout is capped by overridden interval")
+	assert.Equal(t, models.CheckModeAll, effective.CheckMode)
+	assert.True(t, effective.ValidateContent)
+
+	// После истечения TTL действуют исходные значения
+	now = now.Add(time.Hour)
+	assert.Equal(t, stream, store.Apply(stream))
+	_, ok := store.Get("test")
+	assert.False(t, ok)
+
+	store.Set("test", Override{CheckMode: models.CheckModeAll, ExpiresAt: now.Add(time.Minute)})
+	assert.True(t, store.Delete("test"))
+	assert.False(t, store.Delete("test"))
+	assert.Equal(t, stream, store.Apply(stream))
+}
@@ -0,0 +1,83 @@
+// Package plugins загружает внешние валидаторы из Go plugin (.so) и модулей WASM (.wasm).
+// Go plugin собирается командой go build -buildmode=plugin той же версией Go и с теми же
+// версиями зависимостей, что и экспортер, и экспортирует переменную Validator. Модуль WASM
+// выполняется во встроенной среде wazero и реализует экспорты, описанные в wasm.go.
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"plugin"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Symbol имя экспортируемой плагином переменной
+const Symbol = "Validator"
+
+// symbols поиск символов загруженного плагина
+type symbols interface {
+	Lookup(name string) (plugin.Symbol, error)
+}
+
+// Load загружает внешние валидаторы из конфигурации. Файлы с расширением .wasm
+// загружаются как модули WASM, остальные - как Go plugin.
+func Load(cfgs []models.PluginConfig) (map[string]models.ExternalValidator, error) {
+	validators := make(map[string]models.ExternalValidator, len(cfgs))
+	for i, cfg := range cfgs {
+		v, err := load(cfg.Path)
+		if err != nil {
+			Close(validators)
+			return nil, fmt.Errorf("plugins[%d]: %s: %w", i, cfg.Path, err)
+		}
+		validators[cfg.Name] = v
+	}
+	return validators, nil
+}
+
+// Close освобождает ресурсы валидаторов (среды выполнения модулей WASM)
+func Close(validators map[string]models.ExternalValidator) {
+	for _, v := range validators {
+		if c, ok := v.(io.Closer); ok {
+			_ = c.Close()
+		}
+	}
+}
+
+func load(path string) (models.ExternalValidator, error) {
+	if filepath.Ext(path) == ".wasm" {
+		code, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return loadWASM(context.Background(), code)
+	}
+
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return lookup(p)
+}
+
+// lookup находит валидатор плагина. Lookup возвращает указатель на переменную,
+// поэтому поддерживаются и переменная типа интерфейса, и переменная конкретного типа.
+func lookup(p symbols) (models.ExternalValidator, error) {
+	sym, err := p.Lookup(Symbol)
+	if err != nil {
+		return nil, err
+	}
+	switch v := sym.(type) {
+	case *models.ExternalValidator:
+		if *v == nil {
+			return nil, fmt.Errorf("symbol %s is nil", Symbol)
+		}
+		return *v, nil
+	case models.ExternalValidator:
+		return v, nil
+	}
+	return nil, fmt.Errorf("symbol %s (%T) does not implement ExternalValidator", Symbol, sym)
+}
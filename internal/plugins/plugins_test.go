@@ -0,0 +1,60 @@
+package plugins
+
+import (
+	"errors"
+	"plugin"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeValidator struct{}
+
+func (*fakeValidator) ValidatePlaylist(string, []byte) error     { return nil }
+func (*fakeValidator) ValidateSegment(*models.SegmentData) error { return nil }
+
+type fakePlugin map[string]plugin.Symbol
+
+func (p fakePlugin) Lookup(name string) (plugin.Symbol, error) {
+	sym, ok := p[name]
+	if !ok {
+		return nil, errors.New("symbol not found")
+	}
+	return sym, nil
+}
+
+func TestLookup(t *testing.T) {
+	var iface models.ExternalValidator = &fakeValidator{}
+	var nilIface models.ExternalValidator
+	concrete := fakeValidator{}
+
+	tests := []struct {
+		name    string
+		plugin  fakePlugin
+		wantErr string
+	}{
+		{name: "interface variable", plugin: fakePlugin{Symbol: &iface}},
+		{name: "concrete variable", plugin: fakePlugin{Symbol: &concrete}},
+		{name: "missing symbol", plugin: fakePlugin{}, wantErr: "symbol not found"},
+		{name: "nil interface", plugin: fakePlugin{Symbol: &nilIface}, wantErr: "is nil"},
+		{name: "wrong type", plugin: fakePlugin{Symbol: new(int)}, wantErr: "does not implement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := lookup(tt.plugin)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, v)
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	_, err := Load([]models.PluginConfig{{Name: "x", Path: "/nonexistent/validator.so"}})
+	assert.ErrorContains(t, err, "plugins[0]")
+}
@@ -0,0 +1,188 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Экспорты модуля WASM
+const (
+	wasmAlloc            = "hls_alloc"
+	wasmValidatePlaylist = "hls_validate_playlist"
+	wasmValidateSegment  = "hls_validate_segment"
+)
+
+// wasmCallTimeout ограничивает время одного вызова модуля. Прерванный экземпляр
+// модуля закрывается и создается заново при следующем вызове.
+const wasmCallTimeout = 5 * time.Second
+
+// wasmPlaylist входной документ hls_validate_playlist
+type wasmPlaylist struct {
+	URL  string `json:"url"`
+	Body string `json:"body"`
+}
+
+// wasmValidator внешний валидатор из модуля WASM. Экземпляр модуля не поддерживает
+// параллельные вызовы, поэтому вызовы выполняются по одному.
+type wasmValidator struct {
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+
+	mu  sync.Mutex
+	mod api.Module
+}
+
+// loadWASM компилирует модуль и проверяет его экспорты
+func loadWASM(ctx context.Context, code []byte) (*wasmValidator, error) {
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+	// Модули TinyGo и Rust (wasm32-wasip1) импортируют WASI; файловая система и сеть им недоступны
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	v := &wasmValidator{runtime: runtime}
+	compiled, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	v.compiled = compiled
+
+	if err = checkExports(compiled); err == nil {
+		_, err = v.instance(ctx)
+	}
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	return v, nil
+}
+
+// checkExports проверяет наличие и сигнатуры экспортов модуля
+func checkExports(compiled wazero.CompiledModule) error {
+	i32, i64 := api.ValueTypeI32, api.ValueTypeI64
+	exports := compiled.ExportedFunctions()
+	if len(compiled.ExportedMemories()) == 0 {
+		return errors.New("module does not export memory")
+	}
+	if err := checkSignature(exports, wasmAlloc, []api.ValueType{i32}, []api.ValueType{i32}, true); err != nil {
+		return err
+	}
+	for _, name := range []string{wasmValidatePlaylist, wasmValidateSegment} {
+		if err := checkSignature(exports, name, []api.ValueType{i32, i32}, []api.ValueType{i64}, false); err != nil {
+			return err
+		}
+	}
+	if exports[wasmValidatePlaylist] == nil && exports[wasmValidateSegment] == nil {
+		return fmt.Errorf("module exports neither %s nor %s", wasmValidatePlaylist, wasmValidateSegment)
+	}
+	return nil
+}
+
+func checkSignature(
+	exports map[string]api.FunctionDefinition,
+	name string,
+	params, results []api.ValueType,
+	required bool,
+) error {
+	def, ok := exports[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("module does not export %s", name)
+		}
+		return nil
+	}
+	if !slices.Equal(def.ParamTypes(), params) || !slices.Equal(def.ResultTypes(), results) {
+		return fmt.Errorf("%s has unexpected signature", name)
+	}
+	return nil
+}
+
+// ValidatePlaylist передает модулю URL и текст плейлиста
+func (v *wasmValidator) ValidatePlaylist(url string, body []byte) error {
+	input, err := json.Marshal(wasmPlaylist{URL: url, Body: string(body)})
+	if err != nil {
+		return err
+	}
+	return v.call(wasmValidatePlaylist, input)
+}
+
+// ValidateSegment передает модулю JSON-представление models.SegmentData
+func (v *wasmValidator) ValidateSegment(segment *models.SegmentData) error {
+	input, err := json.Marshal(segment)
+	if err != nil {
+		return err
+	}
+	return v.call(wasmValidateSegment, input)
+}
+
+// Close освобождает среду выполнения модуля
+func (v *wasmValidator) Close() error {
+	return v.runtime.Close(context.Background())
+}
+
+// instance возвращает экземпляр модуля, создавая его заново после прерванного вызова
+func (v *wasmValidator) instance(ctx context.Context) (api.Module, error) {
+	if v.mod != nil && !v.mod.IsClosed() {
+		return v.mod, nil
+	}
+	// Анонимный экземпляр: имя модуля не занимается в среде выполнения
+	mod, err := v.runtime.InstantiateModule(ctx, v.compiled,
+		wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return nil, err
+	}
+	v.mod = mod
+	return mod, nil
+}
+
+// call копирует input в память модуля через hls_alloc и вызывает функцию проверки.
+// Функция возвращает 0 при успехе или (указатель << 32 | длина) текста ошибки в памяти модуля.
+// Модуль без функции проверки этот этап не проверяет.
+func (v *wasmValidator) call(name string, input []byte) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), wasmCallTimeout)
+	defer cancel()
+
+	mod, err := v.instance(ctx)
+	if err != nil {
+		return err
+	}
+	fn := mod.ExportedFunction(name)
+	if fn == nil {
+		return nil
+	}
+
+	res, err := mod.ExportedFunction(wasmAlloc).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", wasmAlloc, err)
+	}
+	ptr := api.DecodeU32(res[0])
+	if !mod.Memory().Write(ptr, input) {
+		return fmt.Errorf("%s returned out of range pointer %d", wasmAlloc, ptr)
+	}
+
+	res, err = fn.Call(ctx, uint64(ptr), uint64(len(input)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if res[0] == 0 {
+		return nil
+	}
+	msgPtr, msgLen := uint32(res[0]>>32), uint32(res[0])
+	msg, ok := mod.Memory().Read(msgPtr, msgLen)
+	if !ok {
+		return fmt.Errorf("%s returned out of range error message", name)
+	}
+	return errors.New(string(msg))
+}
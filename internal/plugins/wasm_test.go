@@ -0,0 +1,99 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModule собран вручную из следующего WAT:
+//
+//	(module
+//	  (memory (export "memory") 1)
+//	  (global $next (mut i32) (i32.const 1024))
+//	  (func (export "hls_alloc") (param $n i32) (result i32)
+//	    global.get $next
+//	    (global.set $next (i32.add (global.get $next) (local.get $n))))
+//	  (func (export "hls_validate_playlist") (param $p i32) (param $n i32) (result i64)
+//	    (if (i32.ne (i32.load8_u (local.get $p)) (i32.const 123))         ;; не '{'
+//	      (then (return (i64.const 0x0000001000000009))))                  ;; "bad input"
+//	    (if (i32.gt_u (local.get $n) (i32.const 200))
+//	      (then (return (i64.const 0x0000002000000012))))                  ;; "playlist too large"
+//	    (i64.const 0))
+//	  (func (export "hls_validate_segment") (param i32 i32) (result i64)
+//	    (i64.const 0x0000004000000010))                                    ;; "segment rejected"
+//	  (data (i32.const 16) "bad input")
+//	  (data (i32.const 32) "playlist too large")
+//	  (data (i32.const 64) "segment rejected"))
+var testModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x0c, 0x02, 0x60,
+	0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7e, 0x03, 0x04,
+	0x03, 0x00, 0x01, 0x01, 0x05, 0x03, 0x01, 0x00, 0x01, 0x06, 0x07, 0x01,
+	0x7f, 0x01, 0x41, 0x80, 0x08, 0x0b, 0x07, 0x45, 0x04, 0x06, 0x6d, 0x65,
+	0x6d, 0x6f, 0x72, 0x79, 0x02, 0x00, 0x09, 0x68, 0x6c, 0x73, 0x5f, 0x61,
+	0x6c, 0x6c, 0x6f, 0x63, 0x00, 0x00, 0x15, 0x68, 0x6c, 0x73, 0x5f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x6c, 0x61, 0x79,
+	0x6c, 0x69, 0x73, 0x74, 0x00, 0x01, 0x14, 0x68, 0x6c, 0x73, 0x5f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x73, 0x65, 0x67, 0x6d,
+	0x65, 0x6e, 0x74, 0x00, 0x02, 0x0a, 0x41, 0x03, 0x0b, 0x00, 0x23, 0x00,
+	0x23, 0x00, 0x20, 0x00, 0x6a, 0x24, 0x00, 0x0b, 0x29, 0x00, 0x20, 0x00,
+	0x2d, 0x00, 0x00, 0x41, 0xfb, 0x00, 0x47, 0x04, 0x40, 0x42, 0x89, 0x80,
+	0x80, 0x80, 0x80, 0x02, 0x0f, 0x0b, 0x20, 0x01, 0x41, 0xc8, 0x01, 0x4b,
+	0x04, 0x40, 0x42, 0x92, 0x80, 0x80, 0x80, 0x80, 0x04, 0x0f, 0x0b, 0x42,
+	0x00, 0x0b, 0x09, 0x00, 0x42, 0x90, 0x80, 0x80, 0x80, 0x80, 0x08, 0x0b,
+	0x0b, 0x3c, 0x03, 0x00, 0x41, 0x10, 0x0b, 0x09, 0x62, 0x61, 0x64, 0x20,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x00, 0x41, 0x20, 0x0b, 0x12, 0x70, 0x6c,
+	0x61, 0x79, 0x6c, 0x69, 0x73, 0x74, 0x20, 0x74, 0x6f, 0x6f, 0x20, 0x6c,
+	0x61, 0x72, 0x67, 0x65, 0x00, 0x41, 0xc0, 0x00, 0x0b, 0x10, 0x73, 0x65,
+	0x67, 0x6d, 0x65, 0x6e, 0x74, 0x20, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74,
+	0x65, 0x64,
+}
+
+func TestWASMValidator(t *testing.T) {
+	v, err := loadWASM(context.Background(), testModule)
+	require.NoError(t, err)
+	defer v.Close()
+
+	assert.NoError(t, v.ValidatePlaylist("http://example.com/index.m3u8", []byte("#EXTM3U\n")))
+	// Повторные вызовы не исчерпывают память модуля
+	assert.NoError(t, v.ValidatePlaylist("http://example.com/index.m3u8", []byte("#EXTM3U\n")))
+	assert.EqualError(t, v.ValidatePlaylist("http://example.com/index.m3u8",
+		[]byte(strings.Repeat("#EXT-X-DISCONTINUITY\n", 20))), "playlist too large")
+	assert.EqualError(t, v.ValidateSegment(&models.SegmentData{URI: "seg1.ts"}), "segment rejected")
+}
+
+func TestLoadWASM_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    []byte
+		wantErr string
+	}{
+		{name: "not a module", code: []byte("not wasm"), wantErr: "invalid magic number"},
+		{name: "no exports", code: []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}, wantErr: "does not export memory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadWASM(context.Background(), tt.code)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoad_WASM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validator.wasm")
+	require.NoError(t, os.WriteFile(path, testModule, 0o600))
+
+	validators, err := Load([]models.PluginConfig{{Name: "strict", Path: path}})
+	require.NoError(t, err)
+	defer Close(validators)
+
+	require.Contains(t, validators, "strict")
+	assert.NoError(t, validators["strict"].ValidatePlaylist("index.m3u8", []byte("#EXTM3U\n")))
+	assert.EqualError(t, validators["strict"].ValidateSegment(&models.SegmentData{}), "segment rejected")
+}
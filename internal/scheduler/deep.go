@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/cron"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+// deepSchedule расписание углубленных проверок стрима
+type deepSchedule struct {
+	interval time.Duration
+	cron     *cron.Schedule
+	next     time.Time
+}
+
+// newDeepSchedule создает расписание по настройкам deep_check стрима.
+// По интервалу первая проверка углубленная, по cron - в ближайшее время срабатывания.
+// Возвращает nil, если углубленные проверки не настроены.
+func (s *Scheduler) newDeepSchedule(cfg models.StreamConfig, now time.Time) *deepSchedule {
+	if cfg.DeepCheck == nil {
+		return nil
+	}
+
+	if cfg.DeepCheck.Cron == "" {
+		if cfg.DeepCheck.Interval <= 0 {
+			return nil
+		}
+		return &deepSchedule{interval: cfg.DeepCheck.Interval, next: now}
+	}
+
+	schedule, err := cron.Parse(cfg.DeepCheck.Cron)
+	if err != nil {
+		// Конфигурация проверяется при загрузке, сюда попадать не должны
+		s.logger.Error("Invalid deep check schedule",
+			zap.String("stream", cfg.Name),
+			zap.Error(err))
+		return nil
+	}
+	d := &deepSchedule{cron: schedule}
+	d.advance(now)
+	return d
+}
+
+// due сообщает, что подошел срок углубленной проверки
+func (d *deepSchedule) due(now time.Time) bool {
+	return d != nil && !d.next.IsZero() && !now.Before(d.next)
+}
+
+// before возвращает время углубленной проверки, если она наступает раньше next
+func (d *deepSchedule) before(next time.Time) time.Time {
+	if d != nil && !d.next.IsZero() && d.next.Before(next) {
+		return d.next
+	}
+	return next
+}
+
+// advance планирует следующую углубленную проверку после начатой в started.
+// Для cron без будущих срабатываний next остается нулевым.
+func (d *deepSchedule) advance(started time.Time) {
+	if d.cron != nil {
+		d.next = d.cron.Next(started)
+		return
+	}
+	d.next = started.Add(d.interval)
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepSchedule(t *testing.T) {
+	s := New(Dependencies{})
+	now := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.Local)
+	stream := models.StreamConfig{Name: "test_stream", Interval: time.Minute}
+
+	assert.Nil(t, s.newDeepSchedule(stream, now))
+
+	t.Run("interval", func(t *testing.T) {
+		stream.DeepCheck = &models.DeepCheckConfig{Interval: 15 * time.Minute}
+		d := s.newDeepSchedule(stream, now)
+		require.NotNil(t, d)
+
+		assert.True(t, d.due(now))
+		d.advance(now)
+		assert.False(t, d.due(now.Add(14*time.Minute)))
+		assert.True(t, d.due(now.Add(15*time.Minute)))
+		// Обычная проверка раньше углубленной не сдвигается
+		assert.Equal(t, now.Add(time.Minute), d.before(now.Add(time.Minute)))
+	})
+
+	t.Run("cron", func(t *testing.T) {
+		stream.DeepCheck = &models.DeepCheckConfig{Cron: "0 * * * *"}
+		d := s.newDeepSchedule(stream, now)
+		require.NotNil(t, d)
+
+		hour := time.Date(2025, time.January, 15, 11, 0, 0, 0, time.Local)
+		assert.False(t, d.due(now))
+		assert.True(t, d.due(hour))
+		// Срабатывание cron сокращает ожидание обычной проверки
+		assert.Equal(t, hour, d.before(now.Add(time.Hour)))
+	})
+
+	t.Run("cron never fires", func(t *testing.T) {
+		stream.DeepCheck = &models.DeepCheckConfig{Cron: "0 0 31 2 *"}
+		d := s.newDeepSchedule(stream, now)
+		require.NotNil(t, d)
+		assert.False(t, d.due(now.Add(24*time.Hour)))
+		assert.Equal(t, now.Add(time.Minute), d.before(now.Add(time.Minute)))
+	})
+
+	t.Run("invalid cron", func(t *testing.T) {
+		stream.DeepCheck = &models.DeepCheckConfig{Cron: "every hour"}
+		assert.Nil(t, s.newDeepSchedule(stream, now))
+	})
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// fastRetryAllowed сообщает, может ли недоступный стрим с политикой fast_retry
+// перепроверяться чаще обычного интервала. Стримы занимают места в пределах
+// max_fast_retry_streams в порядке обнаружения недоступности, остальные
+// проверяются с обычным интервалом, пока место не освободится.
+func (s *Scheduler) fastRetryAllowed(name string) bool {
+	if s.maxFastRetry <= 0 {
+		return true
+	}
+
+	s.fastRetryMu.Lock()
+	defer s.fastRetryMu.Unlock()
+
+	if _, ok := s.fastRetry[name]; ok {
+		return true
+	}
+	if len(s.fastRetry) >= s.maxFastRetry {
+		return false
+	}
+	s.fastRetry[name] = struct{}{}
+	return true
+}
+
+// releaseFastRetry освобождает место стрима среди частых перепроверок
+func (s *Scheduler) releaseFastRetry(name string) {
+	if s.maxFastRetry <= 0 {
+		return
+	}
+
+	s.fastRetryMu.Lock()
+	defer s.fastRetryMu.Unlock()
+	delete(s.fastRetry, name)
+}
+
+// backoffInterval возвращает интервал после failures неудачных проверок подряд
+// с учетом ограничения числа стримов с частыми перепроверками
+func (s *Scheduler) backoffInterval(cfg models.StreamConfig, failures int) time.Duration {
+	if failures <= 0 {
+		s.releaseFastRetry(cfg.Name)
+		return cfg.Interval
+	}
+	if cfg.Backoff != nil && cfg.Backoff.Policy == models.BackoffFastRetry && !s.fastRetryAllowed(cfg.Name) {
+		return cfg.Interval
+	}
+	return cfg.Backoff.Interval(cfg.Interval, failures)
+}
@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// maxRetryAfter наибольшая пауза Retry-After, на которую откладывается проверка:
+// ошибочный заголовок источника не должен надолго остановить проверки стрима
+const maxRetryAfter = time.Hour
+
+// retryAfter возвращает паузу, которую источник потребовал ответом 429 или 503
+// с Retry-After (0 - не требовал)
+func retryAfter(result *models.CheckResult) time.Duration {
+	if result == nil || result.Error == nil {
+		return 0
+	}
+	return min(result.Error.RetryAfter, maxRetryAfter)
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfter(t *testing.T) {
+	assert.Zero(t, retryAfter(nil))
+	assert.Zero(t, retryAfter(&models.CheckResult{Success: true}))
+	assert.Equal(t, 30*time.Second, retryAfter(&models.CheckResult{
+		Error: &models.CheckError{RetryAfter: 30 * time.Second},
+	}))
+	// Слишком долгая пауза ограничивается
+	assert.Equal(t, maxRetryAfter, retryAfter(&models.CheckResult{
+		Error: &models.CheckError{RetryAfter: 48 * time.Hour},
+	}))
+}
+
+func TestWaitNextCheck_RetryAfter(t *testing.T) {
+	s := New(Dependencies{})
+	cfg := models.StreamConfig{Name: "test_stream", Interval: 10 * time.Millisecond, Timeout: time.Second}
+
+	// Следующая проверка откладывается до конца паузы Retry-After
+	started := time.Now()
+	notBefore := started.Add(80 * time.Millisecond)
+	next, ok := s.waitNextCheck(context.Background(), cfg, started, nil, 0, notBefore, nil)
+	require.True(t, ok)
+	assert.Equal(t, notBefore, next)
+	assert.False(t, time.Now().Before(notBefore))
+
+	// Прошедшая пауза не меняет расписание
+	next, ok = s.waitNextCheck(context.Background(), cfg, started, nil, 0, started.Add(-time.Second), nil)
+	require.True(t, ok)
+	assert.Equal(t, started.Add(cfg.Interval), next)
+}
+
+func TestScrapeDue_RetryAfter(t *testing.T) {
+	s := New(Dependencies{})
+	cfg := models.StreamConfig{Name: "test_stream", Interval: time.Second, Timeout: time.Second}
+	now := time.Now()
+
+	// Устаревший результат не проверяется заново до конца паузы Retry-After
+	state := &scrapeState{checked: now.Add(-time.Minute), notBefore: now.Add(time.Minute)}
+	assert.False(t, s.scrapeDue(cfg, state, now))
+	assert.True(t, s.scrapeDue(cfg, state, now.Add(2*time.Minute)))
+}
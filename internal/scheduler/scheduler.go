@@ -0,0 +1,650 @@
+// Package scheduler запускает периодические проверки стримов и позволяет
+// менять набор стримов во время работы без перезапуска процесса.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"go.uber.org/zap"
+)
+
+var _ models.StreamManager = (*Scheduler)(nil)
+
+// Dependencies зависимости планировщика
+type Dependencies struct {
+	Checker   models.Checker
+	Metrics   models.MetricsCollector
+	Results   models.ResultStore
+	Overrides *override.Store
+	Logger    *zap.Logger
+	// Shard ограничивает проверки стримами текущего экземпляра кластера (nil - все стримы)
+	Shard models.ShardFilter
+	// CollectOnScrape отключает циклы проверок: проверки запускает Collect при сборе метрик
+	CollectOnScrape bool
+	// ScrapeConcurrency ограничивает число одновременных проверок Collect (минимум 1)
+	ScrapeConcurrency int
+	// StaggerStart распределяет первые проверки стримов при запуске по их интервалам
+	StaggerStart bool
+	// Jitter случайно отклоняет каждый интервал на долю до ±Jitter
+	Jitter float64
+	// OverrunPolicy поведение при проверке дольше интервала (по умолчанию models.OverrunQueue)
+	OverrunPolicy string
+	// Silences подавляет оповещения о результатах на время плановых работ (опционально)
+	Silences models.Silencer
+	// Hook передает результаты проверок внешним командам стримов (опционально)
+	Hook models.ResultHook
+	// Incidents отслеживает текущие инциденты стримов (опционально)
+	Incidents models.IncidentStore
+	// History сохраняет результаты всех проверок (опционально)
+	History models.HistoryStore
+	// EventLog пишет результаты всех проверок в журнал для SIEM (опционально)
+	EventLog models.ResultRecorder
+	// HostSpacing распределяет проверки стримов с общим хостом по их интервалу
+	HostSpacing bool
+	// MaxFastRetryStreams ограничивает число недоступных стримов с частыми
+	// перепроверками backoff fast_retry (0 - без ограничения)
+	MaxFastRetryStreams int
+}
+
+// Scheduler управляет циклами проверок стримов
+type Scheduler struct {
+	checker   models.Checker
+	metrics   models.MetricsCollector
+	results   models.ResultStore
+	overrides *override.Store
+	logger    *zap.Logger
+	shard     models.ShardFilter
+	silences  models.Silencer
+	hook      models.ResultHook
+	incidents models.IncidentStore
+	history   models.HistoryStore
+	eventLog  models.ResultRecorder
+
+	// Режим проверок при сборе метрик
+	collectOnScrape bool
+	scrapeSem       chan struct{}
+	scrapeWG        sync.WaitGroup
+
+	staggerStart bool
+	jitter       float64
+	// random источник случайных чисел в [0, 1) для jitter
+	random func() float64
+	// skipOverrun отбрасывает запуски, пропущенные за время долгой проверки
+	skipOverrun bool
+	// hostSpacing распределяет проверки стримов с общим хостом, hostSlots - ближайшее
+	// свободное время проверки по хостам (под mu)
+	hostSpacing bool
+	hostSlots   map[string]time.Time
+	// maxFastRetry ограничение числа стримов с частыми перепроверками,
+	// fastRetry - стримы, занявшие места (под fastRetryMu)
+	maxFastRetry int
+	fastRetryMu  sync.Mutex
+	fastRetry    map[string]struct{}
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	streams map[string]*task
+	// order сохраняет порядок добавления стримов
+	order []string
+}
+
+// task состояние стрима в планировщике
+type task struct {
+	cfg    models.StreamConfig
+	paused bool
+	cancel context.CancelFunc
+	done   chan struct{}
+	// trigger запрос внеочередной проверки, повторные запросы объединяются
+	trigger chan struct{}
+	// next время следующей плановой проверки (нулевое - не запланирована)
+	next time.Time
+
+	// Состояние стрима в режиме проверок при сборе метрик
+	scrape *scrapeState
+	// scrapes проверки стрима, запущенные Collect
+	scrapes sync.WaitGroup
+}
+
+func New(deps Dependencies) *Scheduler {
+	logger := deps.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	overrides := deps.Overrides
+	if overrides == nil {
+		overrides = override.NewStore()
+	}
+
+	return &Scheduler{
+		checker:   deps.Checker,
+		metrics:   deps.Metrics,
+		results:   deps.Results,
+		overrides: overrides,
+		logger:    logger,
+		shard:     deps.Shard,
+		silences:  deps.Silences,
+		hook:      deps.Hook,
+		incidents: deps.Incidents,
+		history:   deps.History,
+		eventLog:  deps.EventLog,
+		streams:   make(map[string]*task),
+
+		collectOnScrape: deps.CollectOnScrape,
+		scrapeSem:       make(chan struct{}, max(deps.ScrapeConcurrency, 1)),
+
+		staggerStart: deps.StaggerStart,
+		jitter:       deps.Jitter,
+		random:       rand.Float64,
+		skipOverrun:  deps.OverrunPolicy == models.OverrunSkip,
+		hostSpacing:  deps.HostSpacing,
+		hostSlots:    make(map[string]time.Time),
+		maxFastRetry: deps.MaxFastRetryStreams,
+		fastRetry:    make(map[string]struct{}),
+	}
+}
+
+// Start запускает циклы проверок всех активных стримов
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for i, name := range s.order {
+		t := s.streams[name]
+		s.startAfterLocked(t, s.startDelay(t.cfg, i, len(s.order)))
+	}
+}
+
+// Stop останавливает все циклы и ожидает их завершения
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	done := make([]chan struct{}, 0, len(s.streams))
+	for _, t := range s.streams {
+		if t.done != nil {
+			done = append(done, t.done)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, ch := range done {
+		<-ch
+	}
+	s.scrapeWG.Wait()
+}
+
+// Streams возвращает стримы в порядке добавления
+func (s *Scheduler) Streams() []models.StreamConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	streams := make([]models.StreamConfig, 0, len(s.order))
+	for _, name := range s.order {
+		streams = append(streams, s.streams[name].cfg)
+	}
+	return streams
+}
+
+func (s *Scheduler) Stream(name string) (models.StreamConfig, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.streams[name]
+	if !ok {
+		return models.StreamConfig{}, false
+	}
+	return t.cfg, true
+}
+
+// Add добавляет стрим и запускает его проверки, если планировщик запущен
+func (s *Scheduler) Add(stream models.StreamConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.streams[stream.Name]; ok {
+		return fmt.Errorf("%w: %s", models.ErrStreamExists, stream.Name)
+	}
+
+	t := &task{cfg: stream, trigger: make(chan struct{}, 1)}
+	s.streams[stream.Name] = t
+	s.order = append(s.order, stream.Name)
+	s.metrics.SetStreamInfo(stream)
+	s.startLocked(t)
+	return nil
+}
+
+// Update заменяет конфигурацию стрима и перезапускает его цикл проверок
+func (s *Scheduler) Update(stream models.StreamConfig) error {
+	for {
+		s.mu.Lock()
+		t, ok := s.streams[stream.Name]
+		if !ok {
+			s.mu.Unlock()
+			return fmt.Errorf("%w: %s", models.ErrStreamNotFound, stream.Name)
+		}
+		// Новая конфигурация применяется только к остановленному циклу
+		if t.cancel == nil {
+			t.cfg = stream
+			s.metrics.SetStreamInfo(stream)
+			s.startLocked(t)
+			s.mu.Unlock()
+			return nil
+		}
+		done := s.stopLocked(t)
+		s.mu.Unlock()
+
+		<-done
+	}
+}
+
+// NextCheck возвращает время следующей плановой проверки стрима. Для остановленных
+// стримов и в режиме проверок при сборе метрик проверка не запланирована.
+func (s *Scheduler) NextCheck(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.streams[name]
+	if !ok || t.next.IsZero() {
+		return time.Time{}, false
+	}
+	return t.next, true
+}
+
+// setNextCheck запоминает время следующей проверки стрима, если его цикл не остановлен
+func (s *Scheduler) setNextCheck(ctx context.Context, name string, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Контекст отменяется под mu, поэтому остановленный цикл не перезапишет время нового
+	if t, ok := s.streams[name]; ok && ctx.Err() == nil {
+		t.next = next
+	}
+}
+
+// Remove останавливает проверки стрима и удаляет его вместе с результатами и переопределениями
+func (s *Scheduler) Remove(name string) error {
+	s.mu.Lock()
+	t, ok := s.streams[name]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", models.ErrStreamNotFound, name)
+	}
+	done := s.stopLocked(t)
+	delete(s.streams, name)
+	for i, n := range s.order {
+		if n == name {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	// В режиме collect_on_scrape done закрывается сразу: проверка, запущенная
+	// Collect, не должна записать результаты после их удаления
+	t.scrapes.Wait()
+	s.checker.Forget(name)
+	s.overrides.Delete(name)
+	s.metrics.Reset(name)
+	if s.results != nil {
+		s.results.Delete(name)
+	}
+	if s.incidents != nil {
+		s.incidents.Delete(name)
+	}
+	s.releaseFastRetry(name)
+	return nil
+}
+
+// SetPaused приостанавливает или возобновляет проверки стрима
+func (s *Scheduler) SetPaused(name string, paused bool) error {
+	s.mu.Lock()
+	t, ok := s.streams[name]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", models.ErrStreamNotFound, name)
+	}
+	if t.paused == paused {
+		s.mu.Unlock()
+		return nil
+	}
+	t.paused = paused
+
+	if !paused {
+		s.startLocked(t)
+		s.mu.Unlock()
+		return nil
+	}
+	done := s.stopLocked(t)
+	s.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	return nil
+}
+
+func (s *Scheduler) Paused(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.streams[name]
+	return ok && t.paused
+}
+
+// TriggerCheck запускает внеочередную проверку стрима. Если стрим проверяется
+// в момент вызова, проверка начнется сразу после текущей.
+func (s *Scheduler) TriggerCheck(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.streams[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", models.ErrStreamNotFound, name)
+	}
+	if t.cancel == nil || s.collectOnScrape {
+		return fmt.Errorf("%w: %s", models.ErrStreamInactive, name)
+	}
+
+	select {
+	case t.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Owns сообщает, проверяет ли стрим текущий экземпляр
+func (s *Scheduler) Owns(name string) bool {
+	return s.shard == nil || s.shard.Owns(name)
+}
+
+// startLocked запускает цикл проверок стрима, если планировщик запущен и стрим активен.
+// Стримы других экземпляров кластера остаются в наборе, но не проверяются.
+func (s *Scheduler) startLocked(t *task) {
+	s.startAfterLocked(t, 0)
+}
+
+// startAfterLocked запускает цикл проверок стрима с первой проверкой через delay
+func (s *Scheduler) startAfterLocked(t *task, delay time.Duration) {
+	if s.ctx == nil || s.ctx.Err() != nil || t.paused || t.cancel != nil || !s.Owns(t.cfg.Name) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	t.cancel = cancel
+	t.done = done
+
+	// Без цикла проверок: их запускает Collect
+	if s.collectOnScrape {
+		t.scrape = &scrapeState{ctx: ctx, deep: s.newDeepSchedule(t.cfg, time.Now())}
+		close(done)
+		return
+	}
+
+	cfg := t.cfg
+	trigger := t.trigger
+	t.next = time.Now().Add(delay)
+	go func() {
+		defer close(done)
+		s.run(ctx, cfg, delay, trigger)
+	}()
+}
+
+// stopLocked отменяет цикл проверок стрима и возвращает канал его завершения
+func (s *Scheduler) stopLocked(t *task) <-chan struct{} {
+	if t.cancel == nil {
+		return nil
+	}
+	t.cancel()
+	done := t.done
+	t.cancel = nil
+	t.done = nil
+	t.next = time.Time{}
+	return done
+}
+
+// run выполняет периодические проверки стрима до отмены контекста.
+// Первая проверка выполняется через delay.
+func (s *Scheduler) run(ctx context.Context, cfg models.StreamConfig, delay time.Duration, trigger <-chan struct{}) {
+	scheduled := time.Now().Add(delay)
+	if !sleep(ctx, delay) {
+		return
+	}
+	deep := s.newDeepSchedule(cfg, scheduled)
+	defer s.releaseFastRetry(cfg.Name)
+	// failures неудачные проверки подряд для backoff
+	failures := 0
+	for {
+		if !s.waitHostSlot(ctx, cfg) {
+			return
+		}
+		started, success, delay := s.check(ctx, cfg, deep, scheduled)
+		if ctx.Err() != nil {
+			return
+		}
+		if success {
+			failures = 0
+		} else {
+			failures++
+		}
+		s.metrics.SetCheckInterval(cfg.Name, s.interval(cfg, failures).Seconds())
+
+		// Источник ограничил частоту запросов: следующая проверка не раньше Retry-After
+		var notBefore time.Time
+		if delay > 0 {
+			notBefore = time.Now().Add(delay)
+		}
+		next, ok := s.waitNextCheck(ctx, cfg, started, deep, failures, notBefore, trigger)
+		if !ok {
+			return
+		}
+		scheduled = next
+	}
+}
+
+// check выполняет одну проверку стрима, запланированную на scheduled,
+// сохраняет результат и возвращает время ее начала, успешность и паузу
+// до следующей проверки, потребованную источником в Retry-After
+func (s *Scheduler) check(
+	ctx context.Context,
+	cfg models.StreamConfig,
+	deep *deepSchedule,
+	scheduled time.Time,
+) (time.Time, bool, time.Duration) {
+	// Действующая конфигурация с учетом временных переопределений
+	effective := s.overrides.Apply(cfg)
+	started := time.Now()
+
+	// Углубленная проверка заменяет обычную, если подошел ее срок
+	isDeep := deep.due(started)
+	if isDeep {
+		effective = cfg.DeepCheck.Apply(effective)
+		deep.advance(started)
+		s.metrics.SetLastDeepCheckTime(cfg.Name, started)
+	}
+
+	// Отставание фактического старта от запланированного
+	s.metrics.SetSchedulingDrift(cfg.Name, started.Sub(scheduled).Seconds())
+	s.metrics.SetThresholds(cfg.Name, thresholds(effective))
+
+	checkCtx, cancel := context.WithTimeout(ctx, effective.Timeout)
+	result, err := s.checker.Check(checkCtx, effective)
+	cancel()
+	var checkID string
+	if result != nil {
+		result.DeepCheck = isDeep
+		checkID = result.CheckID
+	}
+
+	// Результат прерванной удалением или паузой проверки не сохраняем
+	if ctx.Err() != nil {
+		return started, false, 0
+	}
+	silence := s.silence(cfg, result)
+	// Подавленный результат не передается внешней команде, чтобы она не оповещала о нем
+	if silence == "" {
+		s.runHook(ctx, cfg, result)
+	}
+	if s.results != nil {
+		s.results.Save(result)
+	}
+	if s.incidents != nil {
+		s.incidents.Observe(result)
+	}
+	if s.history != nil {
+		if err := s.history.Record(result); err != nil {
+			s.logger.Error("Failed to record check history",
+				zap.String("stream", cfg.Name),
+				zap.String("check_id", checkID),
+				zap.Error(err))
+		}
+	}
+	if s.eventLog != nil {
+		if err := s.eventLog.Record(result); err != nil {
+			s.logger.Error("Failed to write check result to event log",
+				zap.String("stream", cfg.Name),
+				zap.String("check_id", checkID),
+				zap.Error(err))
+		}
+	}
+
+	switch {
+	case err != nil && silence != "":
+		s.logger.Info("Stream check failed (silenced)",
+			zap.String("stream", cfg.Name),
+			zap.String("check_id", checkID),
+			zap.String("silence", silence),
+			zap.Error(err))
+	case err != nil:
+		s.logger.Error("Stream check failed",
+			zap.String("stream", cfg.Name),
+			zap.String("check_id", checkID),
+			zap.Error(err))
+	default:
+		s.logger.Debug("Stream check completed",
+			zap.String("stream", cfg.Name),
+			zap.String("check_id", checkID),
+			zap.Bool("deep", isDeep),
+			zap.Bool("success", result.Success))
+	}
+	delay := retryAfter(result)
+	if delay > 0 {
+		s.logger.Warn("Origin is rate limiting checks, postponing next check",
+			zap.String("stream", cfg.Name),
+			zap.String("check_id", checkID),
+			zap.Duration("retry_after", delay))
+	}
+	return started, err == nil && result != nil && result.Success, delay
+}
+
+// silence отмечает результат действующим правилом silences и возвращает его имя
+func (s *Scheduler) silence(cfg models.StreamConfig, result *models.CheckResult) string {
+	if s.silences == nil {
+		return ""
+	}
+
+	name, silenced := s.silences.Silence(cfg, result)
+	s.metrics.SetStreamSilenced(cfg.Name, silenced)
+	if !silenced {
+		return ""
+	}
+	if result != nil {
+		result.Silence = name
+	}
+	return name
+}
+
+// runHook передает результат внешней команде стрима и обновляет признак деградации
+func (s *Scheduler) runHook(ctx context.Context, cfg models.StreamConfig, result *models.CheckResult) {
+	if s.hook == nil || cfg.Hook == nil || result == nil {
+		return
+	}
+
+	if err := s.hook.Run(ctx, cfg, result); err != nil {
+		s.logger.Error("Failed to run stream hook",
+			zap.String("stream", cfg.Name),
+			zap.String("check_id", result.CheckID),
+			zap.Error(err))
+	}
+	s.metrics.SetStreamDegraded(cfg.Name, result.Degraded)
+	if result.Degraded {
+		s.logger.Warn("Stream marked degraded by hook",
+			zap.String("stream", cfg.Name),
+			zap.String("check_id", result.CheckID),
+			zap.String("reason", result.DegradedReason))
+	}
+}
+
+// interval возвращает интервал до следующей проверки с учетом временных
+// переопределений и backoff после failures неудачных проверок подряд
+func (s *Scheduler) interval(cfg models.StreamConfig, failures int) time.Duration {
+	return s.backoffInterval(s.overrides.Apply(cfg), failures)
+}
+
+// waitNextCheck ожидает время следующей проверки и возвращает запланированное время.
+// Интервал учитывает backoff после failures неудачных проверок подряд и пересчитывается
+// при изменении переопределений, углубленная проверка может наступить раньше обычной.
+// Если проверка длилась дольше интервала, по умолчанию следующая начинается сразу,
+// а с политикой skip - в ближайший запуск по сетке от started. Плановая проверка
+// не начинается раньше notBefore (Retry-After источника).
+// Запрос trigger запускает проверку немедленно. Возвращает false при остановке.
+func (s *Scheduler) waitNextCheck(
+	ctx context.Context,
+	cfg models.StreamConfig,
+	started time.Time,
+	deep *deepSchedule,
+	failures int,
+	notBefore time.Time,
+	trigger <-chan struct{},
+) (time.Time, bool) {
+	finished := time.Now()
+	// Отклонение выбирается один раз и сохраняется при пересчете интервала
+	jitter := s.jitterFactor()
+	for first := true; ; first = false {
+		changed := s.overrides.Changed()
+		interval := scale(s.interval(cfg, failures), jitter)
+		next := started.Add(interval)
+		// Запуски, наступившие до завершения проверки, отбрасываются
+		if s.skipOverrun && next.Before(finished) {
+			missed := (finished.Sub(next) + interval - 1) / interval
+			next = next.Add(missed * interval)
+			if first {
+				s.metrics.AddChecksSkipped(cfg.Name, int(missed))
+			}
+		}
+		next = deep.before(next)
+		if next.Before(notBefore) {
+			next = notBefore
+		}
+		s.setNextCheck(ctx, cfg.Name, next)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-timer.C:
+			return next, true
+		case <-changed:
+			timer.Stop()
+		case <-trigger:
+			timer.Stop()
+			return time.Now(), true
+		case <-ctx.Done():
+			timer.Stop()
+			return time.Time{}, false
+		}
+	}
+}
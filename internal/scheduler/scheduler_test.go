@@ -0,0 +1,693 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/override"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChecker считает проверки по стримам
+type fakeChecker struct {
+	mu    sync.Mutex
+	calls map[string]int
+	urls  map[string]string
+	// modes режимы выбора сегментов проверок по порядку
+	modes map[string][]string
+	// down стримы, проверки которых завершаются неудачей
+	down map[string]bool
+	// forgotten стримы, состояние которых удалено
+	forgotten []string
+}
+
+func newFakeChecker() *fakeChecker {
+	return &fakeChecker{
+		calls: make(map[string]int),
+		urls:  make(map[string]string),
+		modes: make(map[string][]string),
+		down:  make(map[string]bool),
+	}
+}
+
+func (f *fakeChecker) Check(_ context.Context, stream models.StreamConfig) (*models.CheckResult, error) {
+	f.mu.Lock()
+	f.calls[stream.Name]++
+	f.urls[stream.Name] = stream.URL
+	f.modes[stream.Name] = append(f.modes[stream.Name], stream.CheckMode)
+	down := f.down[stream.Name]
+	f.mu.Unlock()
+
+	if down {
+		return &models.CheckResult{StreamName: stream.Name}, errors.New("stream is down")
+	}
+	return &models.CheckResult{StreamName: stream.Name, Success: true}, nil
+}
+
+func (f *fakeChecker) setDown(name string, down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down[name] = down
+}
+
+func (f *fakeChecker) Start() error { return nil }
+func (f *fakeChecker) Stop() error  { return nil }
+
+func (f *fakeChecker) Forget(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.forgotten = append(f.forgotten, name)
+}
+
+func (f *fakeChecker) count(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[name]
+}
+
+func (f *fakeChecker) checkModes(name string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.modes[name])
+}
+
+func (f *fakeChecker) url(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.urls[name]
+}
+
+// waitCheck ожидает очередную проверку стрима после вызова
+func (f *fakeChecker) waitCheck(t *testing.T, name string, after int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return f.count(name) > after
+	}, 2*time.Second, 5*time.Millisecond, "stream %s was not checked", name)
+}
+
+func newTestScheduler(t *testing.T) (*Scheduler, *fakeChecker, *store.ResultStore) {
+	t.Helper()
+
+	checker := newFakeChecker()
+	results := store.NewResultStore()
+	s := New(Dependencies{
+		Checker: checker,
+		Metrics: metrics.NewCollector(prometheus.NewRegistry()),
+		Results: results,
+	})
+	return s, checker, results
+}
+
+func testStream(name string) models.StreamConfig {
+	return models.StreamConfig{
+		Name:      name,
+		URL:       "http://example.com/" + name + ".m3u8",
+		CheckMode: models.CheckModeAll,
+		Interval:  time.Hour,
+		Timeout:   time.Second,
+	}
+}
+
+func TestScheduler_AddBeforeStart(t *testing.T) {
+	s, checker, results := newTestScheduler(t)
+
+	require.NoError(t, s.Add(testStream("a")))
+	require.NoError(t, s.Add(testStream("b")))
+	assert.ErrorIs(t, s.Add(testStream("a")), models.ErrStreamExists)
+	assert.Zero(t, checker.count("a"))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	checker.waitCheck(t, "a", 0)
+	checker.waitCheck(t, "b", 0)
+
+	streams := s.Streams()
+	require.Len(t, streams, 2)
+	assert.Equal(t, "a", streams[0].Name)
+	assert.Equal(t, "b", streams[1].Name)
+
+	require.Eventually(t, func() bool {
+		_, ok := results.Get("a")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestScheduler_AddRemoveAtRuntime(t *testing.T) {
+	s, checker, results := newTestScheduler(t)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.NoError(t, s.Add(testStream("live")))
+	checker.waitCheck(t, "live", 0)
+	require.Eventually(t, func() bool {
+		_, ok := results.Get("live")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, s.Remove("live"))
+	_, ok := s.Stream("live")
+	assert.False(t, ok)
+	_, ok = results.Get("live")
+	assert.False(t, ok)
+	// Состояние проверок удаленного стрима не накапливается
+	checker.mu.Lock()
+	assert.Equal(t, []string{"live"}, checker.forgotten)
+	checker.mu.Unlock()
+
+	assert.ErrorIs(t, s.Remove("live"), models.ErrStreamNotFound)
+}
+
+func TestScheduler_Pause(t *testing.T) {
+	s, checker, _ := newTestScheduler(t)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	stream := testStream("paused")
+	stream.Interval = 20 * time.Millisecond
+	stream.Timeout = 10 * time.Millisecond
+	require.NoError(t, s.Add(stream))
+	checker.waitCheck(t, "paused", 0)
+
+	_, scheduled := s.NextCheck("paused")
+	assert.True(t, scheduled)
+
+	require.NoError(t, s.SetPaused("paused", true))
+	assert.True(t, s.Paused("paused"))
+	_, scheduled = s.NextCheck("paused")
+	assert.False(t, scheduled, "paused stream has no next check")
+	count := checker.count("paused")
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, count, checker.count("paused"), "paused stream must not be checked")
+
+	require.NoError(t, s.SetPaused("paused", false))
+	assert.False(t, s.Paused("paused"))
+	require.Eventually(t, func() bool {
+		return checker.count("paused") > count
+	}, time.Second, 10*time.Millisecond)
+
+	assert.ErrorIs(t, s.SetPaused("missing", true), models.ErrStreamNotFound)
+}
+
+func TestScheduler_Update(t *testing.T) {
+	s, checker, _ := newTestScheduler(t)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.NoError(t, s.Add(testStream("upd")))
+	checker.waitCheck(t, "upd", 0)
+
+	updated := testStream("upd")
+	updated.URL = "http://example.com/other.m3u8"
+	require.NoError(t, s.Update(updated))
+
+	// Цикл перезапускается и сразу проверяет новую конфигурацию
+	checker.waitCheck(t, "upd", 1)
+	assert.Equal(t, updated.URL, checker.url("upd"))
+
+	err := s.Update(testStream("missing"))
+	assert.True(t, errors.Is(err, models.ErrStreamNotFound))
+}
+
+func TestScheduler_Stop(t *testing.T) {
+	s, checker, _ := newTestScheduler(t)
+	require.NoError(t, s.Add(testStream("a")))
+	s.Start(context.Background())
+	checker.waitCheck(t, "a", 0)
+
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop should wait for check loops and return")
+	}
+}
+
+func TestWaitNextCheck_Override(t *testing.T) {
+	overrides := override.NewStore()
+	s := New(Dependencies{Overrides: overrides})
+	cfg := models.StreamConfig{Name: "test_stream", Interval: time.Hour, Timeout: time.Second}
+
+	done := make(chan bool)
+	start := time.Now()
+	go func() {
+		_, ok := s.waitNextCheck(context.Background(), cfg, start, nil, 0, time.Time{}, nil)
+		done <- ok
+	}()
+
+	// Переопределение интервала пересчитывает время ожидания текущего цикла
+	time.Sleep(20 * time.Millisecond)
+	overrides.Set("test_stream", override.Override{
+		Interval:  50 * time.Millisecond,
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	select {
+	case ok := <-done:
+		assert.True(t, ok)
+		assert.Less(t, time.Since(start), time.Second)
+	case <-time.After(2 * time.Second):
+		t.Fatal("override should shorten the wait")
+	}
+}
+
+func TestScheduler_DeepCheck(t *testing.T) {
+	s, checker, results := newTestScheduler(t)
+
+	// Частая проверка только плейлистов и углубленная проверка каждые 100ms
+	stream := testStream("a")
+	stream.CheckMode = models.CheckModePlaylistOnly
+	stream.Interval = 20 * time.Millisecond
+	stream.DeepCheck = &models.DeepCheckConfig{Interval: 100 * time.Millisecond}
+	require.NoError(t, s.Add(stream))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	// Первая проверка углубленная, затем обычные до срока следующей углубленной
+	require.Eventually(t, func() bool {
+		return len(checker.checkModes("a")) >= 8
+	}, 2*time.Second, 5*time.Millisecond)
+
+	modes := checker.checkModes("a")
+	assert.Equal(t, models.CheckModeAll, modes[0])
+	assert.Equal(t, models.CheckModePlaylistOnly, modes[1])
+	assert.GreaterOrEqual(t, slices.Index(modes[1:], models.CheckModeAll), 2)
+
+	// Результат углубленной проверки помечается
+	deep := testStream("b")
+	deep.DeepCheck = &models.DeepCheckConfig{Interval: time.Hour}
+	require.NoError(t, s.Add(deep))
+	require.Eventually(t, func() bool {
+		result, ok := results.Get("b")
+		return ok && result.DeepCheck
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+// shardFunc фильтр стримов экземпляра кластера для тестов
+type shardFunc func(string) bool
+
+func (f shardFunc) Owns(stream string) bool { return f(stream) }
+
+func TestScheduler_Shard(t *testing.T) {
+	checker := newFakeChecker()
+	s := New(Dependencies{
+		Checker: checker,
+		Metrics: metrics.NewCollector(prometheus.NewRegistry()),
+		Shard:   shardFunc(func(name string) bool { return name == "mine" }),
+	})
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.NoError(t, s.Add(testStream("mine")))
+	require.NoError(t, s.Add(testStream("other")))
+	checker.waitCheck(t, "mine", 0)
+
+	// Чужой стрим остается в наборе, но не проверяется
+	assert.Len(t, s.Streams(), 2)
+	assert.True(t, s.Owns("mine"))
+	assert.False(t, s.Owns("other"))
+
+	require.NoError(t, s.SetPaused("other", true))
+	require.NoError(t, s.SetPaused("other", false))
+	require.NoError(t, s.Update(testStream("other")))
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, checker.count("other"))
+}
+
+type silenceFunc func(models.StreamConfig, *models.CheckResult) (string, bool)
+
+func (f silenceFunc) Silence(stream models.StreamConfig, result *models.CheckResult) (string, bool) {
+	return f(stream, result)
+}
+
+func TestScheduler_Silences(t *testing.T) {
+	checker := newFakeChecker()
+	results := store.NewResultStore()
+	var (
+		mu       sync.Mutex
+		notified = make(map[string]int)
+	)
+	s := New(Dependencies{
+		Checker: checker,
+		Metrics: metrics.NewCollector(prometheus.NewRegistry()),
+		Results: results,
+		Silences: silenceFunc(func(stream models.StreamConfig, _ *models.CheckResult) (string, bool) {
+			return "maintenance", stream.Name == "silenced"
+		}),
+		Hook: hookFunc(func(stream models.StreamConfig, _ *models.CheckResult) error {
+			mu.Lock()
+			defer mu.Unlock()
+			notified[stream.Name]++
+			return nil
+		}),
+	})
+	checker.setDown("silenced", true)
+	checker.setDown("loud", true)
+	for _, name := range []string{"silenced", "loud"} {
+		stream := testStream(name)
+		stream.Hook = &models.HookConfig{Command: []string{"notify.sh"}}
+		require.NoError(t, s.Add(stream))
+	}
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.Eventually(t, func() bool {
+		_, ok1 := results.Get("silenced")
+		_, ok2 := results.Get("loud")
+		return ok1 && ok2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	result, _ := results.Get("silenced")
+	assert.Equal(t, "maintenance", result.Silence)
+	result, _ = results.Get("loud")
+	assert.Empty(t, result.Silence)
+
+	// Сбой подавленного стрима не передается hook, который рассылает оповещения
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Zero(t, notified["silenced"])
+	assert.NotZero(t, notified["loud"])
+}
+
+type hookFunc func(models.StreamConfig, *models.CheckResult) error
+
+func (f hookFunc) Run(_ context.Context, stream models.StreamConfig, result *models.CheckResult) error {
+	return f(stream, result)
+}
+
+func TestScheduler_Hook(t *testing.T) {
+	checker := newFakeChecker()
+	results := store.NewResultStore()
+	s := New(Dependencies{
+		Checker: checker,
+		Metrics: metrics.NewCollector(prometheus.NewRegistry()),
+		Results: results,
+		Hook: hookFunc(func(_ models.StreamConfig, result *models.CheckResult) error {
+			result.Degraded = true
+			result.DegradedReason = "custom check failed"
+			return nil
+		}),
+	})
+	hooked := testStream("hooked")
+	hooked.Hook = &models.HookConfig{Command: []string{"check.sh"}}
+	require.NoError(t, s.Add(hooked))
+	require.NoError(t, s.Add(testStream("plain")))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.Eventually(t, func() bool {
+		_, ok1 := results.Get("hooked")
+		_, ok2 := results.Get("plain")
+		return ok1 && ok2
+	}, 2*time.Second, 5*time.Millisecond)
+
+	// Результат сохраняется с отметкой hook, стримы без hook не затрагиваются
+	result, _ := results.Get("hooked")
+	assert.True(t, result.Degraded)
+	assert.Equal(t, "custom check failed", result.DegradedReason)
+	result, _ = results.Get("plain")
+	assert.False(t, result.Degraded)
+}
+
+func TestScheduler_Incidents(t *testing.T) {
+	checker := newFakeChecker()
+	incidents := store.NewIncidentStore()
+	s := New(Dependencies{
+		Checker:   checker,
+		Metrics:   metrics.NewCollector(prometheus.NewRegistry()),
+		Incidents: incidents,
+	})
+	checker.setDown("broken", true)
+	require.NoError(t, s.Add(testStream("broken")))
+	require.NoError(t, s.Add(testStream("healthy")))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	checker.waitCheck(t, "healthy", 0)
+	require.Eventually(t, func() bool {
+		_, ok := incidents.Get("broken")
+		return ok
+	}, 2*time.Second, 5*time.Millisecond)
+	_, ok := incidents.Get("healthy")
+	assert.False(t, ok)
+
+	// Удаление стрима закрывает его инцидент
+	require.NoError(t, s.Remove("broken"))
+	_, ok = incidents.Get("broken")
+	assert.False(t, ok)
+}
+
+func TestScheduler_StreamInfo(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	s := New(Dependencies{
+		Checker: newFakeChecker(),
+		Metrics: metrics.NewCollector(reg),
+	})
+
+	stream := testStream("news")
+	stream.Group = "news"
+	require.NoError(t, s.Add(stream))
+	assert.Equal(t, []string{"news|" + stream.URL + "|all|news"}, streamInfo(t, reg))
+
+	// Метаданные заменяются при изменении стрима и удаляются вместе с ним
+	stream.CheckMode = models.CheckModeFirstLast
+	require.NoError(t, s.Update(stream))
+	assert.Equal(t, []string{"news|" + stream.URL + "|first_last|news"}, streamInfo(t, reg))
+
+	require.NoError(t, s.Remove("news"))
+	assert.Empty(t, streamInfo(t, reg))
+}
+
+func TestScheduler_Backoff(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	checker := newFakeChecker()
+	s := New(Dependencies{
+		Checker: checker,
+		Metrics: metrics.NewCollector(reg),
+	})
+
+	// Недоступный стрим перепроверяется чаще обычного интервала
+	fast := testStream("fast")
+	fast.Timeout = 10 * time.Millisecond
+	fast.Backoff = &models.BackoffConfig{Policy: models.BackoffFastRetry, RetryInterval: 20 * time.Millisecond}
+	checker.setDown("fast", true)
+	require.NoError(t, s.Add(fast))
+
+	// Интервал недоступного стрима растет до max_interval
+	slow := testStream("slow")
+	slow.Interval = 20 * time.Millisecond
+	slow.Timeout = 10 * time.Millisecond
+	slow.Backoff = &models.BackoffConfig{Policy: models.BackoffExponential, MaxInterval: time.Hour}
+	checker.setDown("slow", true)
+	require.NoError(t, s.Add(slow))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	checker.waitCheck(t, "fast", 3)
+	checker.waitCheck(t, "slow", 1)
+	require.Eventually(t, func() bool {
+		return checkInterval(t, reg, "slow") >= 0.08
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0.02, checkInterval(t, reg, "fast"))
+
+	// После восстановления действует обычный интервал
+	checker.setDown("fast", false)
+	require.Eventually(t, func() bool {
+		return checkInterval(t, reg, "fast") == time.Hour.Seconds()
+	}, time.Second, 10*time.Millisecond)
+	count := checker.count("fast")
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, count, checker.count("fast"))
+}
+
+func TestScheduler_MaxFastRetryStreams(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	checker := newFakeChecker()
+	s := New(Dependencies{
+		Checker:             checker,
+		Metrics:             metrics.NewCollector(reg),
+		MaxFastRetryStreams: 1,
+	})
+
+	backoff := &models.BackoffConfig{Policy: models.BackoffFastRetry, RetryInterval: 20 * time.Millisecond}
+	first := testStream("first")
+	first.Timeout = 10 * time.Millisecond
+	first.Backoff = backoff
+	checker.setDown("first", true)
+	require.NoError(t, s.Add(first))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	checker.waitCheck(t, "first", 2)
+	assert.Equal(t, 0.02, checkInterval(t, reg, "first"))
+
+	// Место частых перепроверок занято: второй стрим проверяется с обычным интервалом
+	second := testStream("second")
+	second.Timeout = 10 * time.Millisecond
+	second.Backoff = backoff
+	checker.setDown("second", true)
+	require.NoError(t, s.Add(second))
+	checker.waitCheck(t, "second", 0)
+	require.Eventually(t, func() bool {
+		return checkInterval(t, reg, "second") == time.Hour.Seconds()
+	}, time.Second, 10*time.Millisecond)
+
+	// После восстановления первого место переходит ко второму на следующей проверке
+	checker.setDown("first", false)
+	require.Eventually(t, func() bool {
+		return checkInterval(t, reg, "first") == time.Hour.Seconds()
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, s.TriggerCheck("second"))
+	checker.waitCheck(t, "second", 3)
+	assert.Equal(t, 0.02, checkInterval(t, reg, "second"))
+}
+
+// checkInterval возвращает значение hls_check_interval_seconds стрима
+func checkInterval(t *testing.T, reg *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != metrics.MetricCheckInterval {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if m.GetLabel()[0].GetValue() == name {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+// streamInfo возвращает метки серий hls_stream_info в виде name|url|check_mode|group
+func streamInfo(t *testing.T, reg *prometheus.Registry) []string {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var series []string
+	for _, family := range families {
+		if family.GetName() != metrics.MetricStreamInfo {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			values := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				values[label.GetName()] = label.GetValue()
+			}
+			series = append(series, strings.Join([]string{
+				values["name"], values["url"], values["check_mode"], values["group"],
+			}, "|"))
+		}
+	}
+	return series
+}
+
+// checksSkipped возвращает значение hls_checks_skipped_total стрима
+func checksSkipped(t *testing.T, reg *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != metrics.MetricChecksSkipped {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if m.GetLabel()[0].GetValue() == name {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestWaitNextCheck_Overrun(t *testing.T) {
+	cfg := models.StreamConfig{Name: "slow", Interval: 100 * time.Millisecond, Timeout: time.Second}
+
+	tests := []struct {
+		name    string
+		policy  string
+		want    time.Duration
+		skipped float64
+	}{
+		// Следующая проверка сразу после долгой
+		{name: "queue", policy: models.OverrunQueue, want: 100 * time.Millisecond},
+		// Запуски на 100ms и 200ms пропущены, следующий по сетке на 300ms
+		{name: "skip", policy: models.OverrunSkip, want: 300 * time.Millisecond, skipped: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			s := New(Dependencies{
+				Metrics:       metrics.NewCollector(reg),
+				OverrunPolicy: tt.policy,
+			})
+
+			// Проверка началась 250ms назад и только что завершилась
+			started := time.Now().Add(-250 * time.Millisecond)
+			next, ok := s.waitNextCheck(context.Background(), cfg, started, nil, 0, time.Time{}, nil)
+			require.True(t, ok)
+			assert.Equal(t, started.Add(tt.want), next)
+			assert.Equal(t, tt.skipped, checksSkipped(t, reg, "slow"))
+		})
+	}
+}
+
+func TestScheduler_OverrunSkip(t *testing.T) {
+	checker := &slowChecker{fakeChecker: newFakeChecker(), delay: 60 * time.Millisecond}
+	s := New(Dependencies{
+		Checker:       checker,
+		Metrics:       metrics.NewCollector(prometheus.NewRegistry()),
+		Results:       store.NewResultStore(),
+		OverrunPolicy: models.OverrunSkip,
+	})
+
+	stream := testStream("slow")
+	stream.Interval = 40 * time.Millisecond
+	stream.Timeout = time.Second
+	require.NoError(t, s.Add(stream))
+
+	s.Start(context.Background())
+	time.Sleep(250 * time.Millisecond)
+	s.Stop()
+
+	// Проверки не пересекаются и идут через 80ms: 0, 80, 160, 240
+	assert.Equal(t, 1, checker.maxRunning())
+	assert.LessOrEqual(t, checker.count("slow"), 4)
+}
+
+func TestScheduler_TriggerCheck(t *testing.T) {
+	s, checker, _ := newTestScheduler(t)
+	require.NoError(t, s.Add(testStream("stream")))
+
+	// До запуска планировщика проверки не запланированы
+	assert.ErrorIs(t, s.TriggerCheck("stream"), models.ErrStreamInactive)
+	assert.ErrorIs(t, s.TriggerCheck("missing"), models.ErrStreamNotFound)
+
+	s.Start(context.Background())
+	defer s.Stop()
+	checker.waitCheck(t, "stream", 0)
+
+	// Внеочередная проверка не ждет часового интервала
+	require.NoError(t, s.TriggerCheck("stream"))
+	checker.waitCheck(t, "stream", 1)
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// scrapeState состояние стрима в режиме проверок при сборе метрик.
+// Создается заново при каждом запуске стрима, ctx отменяется при остановке.
+type scrapeState struct {
+	ctx  context.Context
+	deep *deepSchedule
+	// checked время начала последней завершенной проверки
+	checked time.Time
+	// failures неудачные проверки подряд для backoff
+	failures int
+	// notBefore время, до которого источник просил не повторять запросы (Retry-After)
+	notBefore time.Time
+	// inflight закрывается по завершении текущей проверки (nil - проверка не идет)
+	inflight chan struct{}
+}
+
+// Collect запускает проверки стримов, результаты которых старше их интервала,
+// и ожидает их завершения или отмены ctx. Проверка, уже запущенная другим
+// вызовом, не дублируется. Проверки, не успевшие завершиться до отмены ctx,
+// продолжаются и обновят метрики к следующему вызову.
+func (s *Scheduler) Collect(ctx context.Context) {
+	now := time.Now()
+
+	s.mu.Lock()
+	var wait []chan struct{}
+	for _, name := range s.order {
+		t := s.streams[name]
+		state := t.scrape
+		if t.cancel == nil || state == nil {
+			continue
+		}
+		if state.inflight == nil {
+			if !s.scrapeDue(t.cfg, state, now) {
+				continue
+			}
+			state.inflight = make(chan struct{})
+			s.scrapeWG.Add(1)
+			t.scrapes.Add(1)
+			go func(cfg models.StreamConfig, inflight chan struct{}) {
+				defer t.scrapes.Done()
+				s.scrapeCheck(cfg, state, inflight, now)
+			}(t.cfg, state.inflight)
+		}
+		wait = append(wait, state.inflight)
+	}
+	s.mu.Unlock()
+
+	for _, ch := range wait {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scrapeDue сообщает, что результат стрима устарел (с учетом backoff) или подошла углубленная проверка
+func (s *Scheduler) scrapeDue(cfg models.StreamConfig, state *scrapeState, now time.Time) bool {
+	if now.Before(state.notBefore) {
+		return false
+	}
+	if state.checked.IsZero() || state.deep.due(now) {
+		return true
+	}
+	return now.Sub(state.checked) >= s.interval(cfg, state.failures)
+}
+
+// scrapeCheck выполняет проверку стрима для Collect с ограничением параллельности
+func (s *Scheduler) scrapeCheck(cfg models.StreamConfig, state *scrapeState, done chan struct{}, requested time.Time) {
+	defer s.scrapeWG.Done()
+	defer close(done)
+
+	var (
+		started time.Time
+		success bool
+		delay   time.Duration
+	)
+	select {
+	case s.scrapeSem <- struct{}{}:
+		// Отставание от requested - время ожидания свободного слота
+		started, success, delay = s.check(state.ctx, cfg, state.deep, requested)
+		<-s.scrapeSem
+	case <-state.ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state.inflight = nil
+	if state.ctx.Err() != nil {
+		return
+	}
+	state.checked = started
+	state.notBefore = time.Time{}
+	if delay > 0 {
+		state.notBefore = time.Now().Add(delay)
+	}
+	if success {
+		state.failures = 0
+	} else {
+		state.failures++
+	}
+	s.metrics.SetCheckInterval(cfg.Name, s.interval(cfg, state.failures).Seconds())
+}
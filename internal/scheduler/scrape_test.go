@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowChecker задерживает проверки и считает наибольшее число одновременных
+type slowChecker struct {
+	*fakeChecker
+	delay time.Duration
+
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (c *slowChecker) Check(ctx context.Context, stream models.StreamConfig) (*models.CheckResult, error) {
+	c.mu.Lock()
+	c.running++
+	c.peak = max(c.peak, c.running)
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.running--
+		c.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+	}
+	return c.fakeChecker.Check(ctx, stream)
+}
+
+func (c *slowChecker) maxRunning() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.peak
+}
+
+func newScrapeScheduler(checker models.Checker, concurrency int) (*Scheduler, *store.ResultStore) {
+	results := store.NewResultStore()
+	return New(Dependencies{
+		Checker:           checker,
+		Metrics:           metrics.NewCollector(prometheus.NewRegistry()),
+		Results:           results,
+		CollectOnScrape:   true,
+		ScrapeConcurrency: concurrency,
+	}), results
+}
+
+func TestScheduler_CollectOnScrape(t *testing.T) {
+	checker := newFakeChecker()
+	s, results := newScrapeScheduler(checker, 2)
+
+	stream := testStream("lazy")
+	stream.Interval = 200 * time.Millisecond
+	stream.Timeout = 100 * time.Millisecond
+	require.NoError(t, s.Add(stream))
+
+	// До запуска планировщика проверять нечего
+	s.Collect(context.Background())
+	assert.Zero(t, checker.count("lazy"))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	// Без сбора метрик проверки не выполняются
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, checker.count("lazy"))
+
+	s.Collect(context.Background())
+	assert.Equal(t, 1, checker.count("lazy"))
+	_, ok := results.Get("lazy")
+	assert.True(t, ok)
+
+	// Свежий результат используется повторно
+	s.Collect(context.Background())
+	assert.Equal(t, 1, checker.count("lazy"))
+
+	time.Sleep(stream.Interval)
+	s.Collect(context.Background())
+	assert.Equal(t, 2, checker.count("lazy"))
+
+	// Приостановленный стрим не проверяется
+	require.NoError(t, s.SetPaused("lazy", true))
+	time.Sleep(stream.Interval)
+	s.Collect(context.Background())
+	assert.Equal(t, 2, checker.count("lazy"))
+
+	// После возобновления результат считается устаревшим
+	require.NoError(t, s.SetPaused("lazy", false))
+	s.Collect(context.Background())
+	assert.Equal(t, 3, checker.count("lazy"))
+}
+
+func TestScheduler_CollectOnScrape_Concurrency(t *testing.T) {
+	checker := &slowChecker{fakeChecker: newFakeChecker(), delay: 30 * time.Millisecond}
+	s, _ := newScrapeScheduler(checker, 2)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	for i := range 6 {
+		require.NoError(t, s.Add(testStream(fmt.Sprintf("s%d", i))))
+	}
+
+	// Одновременные сборы не дублируют проверки
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Collect(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	for i := range 6 {
+		assert.Equal(t, 1, checker.count(fmt.Sprintf("s%d", i)))
+	}
+	assert.Equal(t, 2, checker.maxRunning())
+}
+
+func TestScheduler_CollectOnScrape_Timeout(t *testing.T) {
+	checker := &slowChecker{fakeChecker: newFakeChecker(), delay: 200 * time.Millisecond}
+	s, results := newScrapeScheduler(checker, 1)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.NoError(t, s.Add(testStream("slow")))
+
+	// Сбор не ждет дольше своего контекста, проверка продолжается в фоне
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	s.Collect(ctx)
+	assert.Less(t, time.Since(started), 150*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		_, ok := results.Get("slow")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	s.Collect(context.Background())
+	assert.Equal(t, 1, checker.count("slow"))
+}
+
+// blockingChecker завершает проверку только после release, не реагируя на отмену
+type blockingChecker struct {
+	*fakeChecker
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingChecker) Check(ctx context.Context, stream models.StreamConfig) (*models.CheckResult, error) {
+	c.started <- struct{}{}
+	<-c.release
+	return c.fakeChecker.Check(ctx, stream)
+}
+
+func TestScheduler_CollectOnScrape_Remove(t *testing.T) {
+	checker := &blockingChecker{
+		fakeChecker: newFakeChecker(),
+		started:     make(chan struct{}, 1),
+		release:     make(chan struct{}),
+	}
+	s, results := newScrapeScheduler(checker, 1)
+	s.Start(context.Background())
+	defer s.Stop()
+
+	require.NoError(t, s.Add(testStream("removed")))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Collect(ctx)
+	<-checker.started
+
+	// Remove ожидает проверку, запущенную Collect
+	removed := make(chan struct{})
+	go func() {
+		defer close(removed)
+		assert.NoError(t, s.Remove("removed"))
+	}()
+	select {
+	case <-removed:
+		close(checker.release)
+		t.Fatal("Remove returned before the scrape check finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(checker.release)
+	<-removed
+	_, ok := results.Get("removed")
+	assert.False(t, ok)
+}
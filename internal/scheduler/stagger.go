@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/hostbudget"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// startDelay возвращает задержку первой проверки стрима с номером index из count
+// при запуске планировщика. Первые проверки равномерно распределяются по интервалу
+// стрима, чтобы не обращаться к источникам одновременно.
+func (s *Scheduler) startDelay(cfg models.StreamConfig, index, count int) time.Duration {
+	if !s.staggerStart || count <= 1 {
+		return 0
+	}
+	return cfg.Interval * time.Duration(index) / time.Duration(count)
+}
+
+// waitHostSlot ожидает очереди проверки среди стримов с общим хостом источника:
+// их проверки начинаются не чаще интервала стрима, деленного на число таких стримов.
+// Возвращает false при остановке.
+func (s *Scheduler) waitHostSlot(ctx context.Context, cfg models.StreamConfig) bool {
+	if !s.hostSpacing {
+		return true
+	}
+	return sleep(ctx, s.reserveHostSlot(cfg, time.Now()))
+}
+
+// reserveHostSlot занимает ближайшее свободное время проверки хоста стрима
+// и возвращает задержку до него
+func (s *Scheduler) reserveHostSlot(cfg models.StreamConfig, now time.Time) time.Duration {
+	host := hostbudget.Host(cfg.URL)
+	interval := s.overrides.Apply(cfg).Interval
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sharing := 0
+	for _, t := range s.streams {
+		if t.cancel != nil && hostbudget.Host(t.cfg.URL) == host {
+			sharing++
+		}
+	}
+	if sharing <= 1 {
+		return 0
+	}
+
+	slot := s.hostSlots[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	s.hostSlots[host] = slot.Add(interval / time.Duration(sharing))
+	return slot.Sub(now)
+}
+
+// jitterFactor возвращает множитель интервала в [1-jitter, 1+jitter)
+func (s *Scheduler) jitterFactor() float64 {
+	if s.jitter <= 0 {
+		return 1
+	}
+	return 1 + s.jitter*(2*s.random()-1)
+}
+
+func scale(d time.Duration, factor float64) time.Duration {
+	if factor == 1 {
+		return d
+	}
+	return time.Duration(float64(d) * factor)
+}
+
+// sleep ожидает d и возвращает false при отмене контекста
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
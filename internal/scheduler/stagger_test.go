@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/metrics"
+	"github.com/iudanet/hls_exporter/internal/store"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartDelay(t *testing.T) {
+	s, _, _ := newTestScheduler(t)
+	cfg := testStream("a")
+	cfg.Interval = 40 * time.Second
+
+	// Без распределения все стримы стартуют сразу
+	assert.Zero(t, s.startDelay(cfg, 3, 4))
+
+	s.staggerStart = true
+	assert.Zero(t, s.startDelay(cfg, 0, 1))
+	for i, want := range []time.Duration{0, 10 * time.Second, 20 * time.Second, 30 * time.Second} {
+		assert.Equal(t, want, s.startDelay(cfg, i, 4))
+	}
+}
+
+func TestReserveHostSlot(t *testing.T) {
+	s, _, _ := newTestScheduler(t)
+	s.hostSpacing = true
+	cfg := testStream("a")
+	cfg.Interval = 30 * time.Second
+	other := testStream("other")
+	other.URL = "http://other.example.com/live.m3u8"
+	for _, stream := range []models.StreamConfig{cfg, testStream("b"), testStream("c"), other} {
+		s.streams[stream.Name] = &task{cfg: stream, cancel: func() {}}
+	}
+	now := time.Unix(1_700_000_000, 0)
+
+	// Три стрима example.com: проверки через 10s
+	assert.Zero(t, s.reserveHostSlot(cfg, now))
+	assert.Equal(t, 10*time.Second, s.reserveHostSlot(cfg, now))
+	assert.Equal(t, 15*time.Second, s.reserveHostSlot(cfg, now.Add(5*time.Second)))
+	// После простоя очередь не накапливается
+	assert.Zero(t, s.reserveHostSlot(cfg, now.Add(time.Minute)))
+
+	// Единственный стрим хоста не ждет
+	assert.Zero(t, s.reserveHostSlot(other, now))
+	assert.Zero(t, s.reserveHostSlot(other, now))
+}
+
+func TestJitterFactor(t *testing.T) {
+	s, _, _ := newTestScheduler(t)
+	assert.Equal(t, 1.0, s.jitterFactor())
+
+	s.jitter = 0.2
+	s.random = func() float64 { return 0 }
+	assert.InDelta(t, 0.8, s.jitterFactor(), 1e-9)
+	s.random = func() float64 { return 0.5 }
+	assert.InDelta(t, 1.0, s.jitterFactor(), 1e-9)
+	s.random = func() float64 { return 0.999 }
+	assert.InDelta(t, 1.2, s.jitterFactor(), 1e-3)
+
+	assert.Equal(t, 12*time.Second, scale(10*time.Second, 1.2))
+	assert.Equal(t, 10*time.Second, scale(10*time.Second, 1))
+}
+
+func TestScheduler_StaggerStart(t *testing.T) {
+	checker := newFakeChecker()
+	s := New(Dependencies{
+		Checker:      checker,
+		Metrics:      metrics.NewCollector(prometheus.NewRegistry()),
+		Results:      store.NewResultStore(),
+		StaggerStart: true,
+	})
+
+	first, second := testStream("first"), testStream("second")
+	first.Interval, second.Interval = 400*time.Millisecond, 400*time.Millisecond
+	first.Timeout, second.Timeout = 100*time.Millisecond, 100*time.Millisecond
+	require.NoError(t, s.Add(first))
+	require.NoError(t, s.Add(second))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	// Первый стрим проверяется сразу, второй через половину интервала
+	require.Eventually(t, func() bool { return checker.count("first") == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, checker.count("second"))
+
+	require.Eventually(t, func() bool { return checker.count("second") == 1 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+// Имена порогов в метрике hls_stream_threshold
+const (
+	ThresholdTimeout         = "timeout_seconds"
+	ThresholdPlaylistLatency = "max_playlist_latency_seconds"
+	ThresholdSegmentDownload = "max_segment_download_ratio"
+	ThresholdExpectVariants  = "expect_variants"
+	ThresholdMinSegmentSize  = "min_segment_size_bytes"
+	ThresholdMaxPCRInterval  = "max_pcr_interval_seconds"
+	ThresholdMaxPCRJitter    = "max_pcr_jitter_seconds"
+	ThresholdSizeAnomaly     = "size_anomaly_ratio"
+	ThresholdDailyByteBudget = "daily_byte_budget_bytes"
+)
+
+// thresholds возвращает заданные пороги стрима. Незаданные (нулевые) пороги
+// не публикуются, чтобы линия на графике означала действующую проверку.
+func thresholds(cfg models.StreamConfig) map[string]float64 {
+	values := make(map[string]float64)
+	set := func(name string, value float64) {
+		if value > 0 {
+			values[name] = value
+		}
+	}
+
+	set(ThresholdTimeout, cfg.Timeout.Seconds())
+	set(ThresholdExpectVariants, float64(cfg.ExpectVariants))
+	set(ThresholdDailyByteBudget, float64(cfg.DailyByteBudget))
+	if slo := cfg.SLO; slo != nil {
+		set(ThresholdPlaylistLatency, slo.MaxPlaylistLatency.Seconds())
+		set(ThresholdSegmentDownload, slo.MaxSegmentDownloadRatio)
+	}
+	if mv := cfg.MediaValidation; mv != nil {
+		set(ThresholdMinSegmentSize, float64(mv.MinSegmentSize))
+		set(ThresholdMaxPCRInterval, mv.MaxPCRInterval.Seconds())
+		set(ThresholdMaxPCRJitter, mv.MaxPCRJitter.Seconds())
+	}
+	if cfg.SizeAnomaly != nil {
+		set(ThresholdSizeAnomaly, cfg.SizeAnomaly.WithDefaults().Threshold)
+	}
+	return values
+}
@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThresholds(t *testing.T) {
+	cfg := testStream("test")
+	cfg.Timeout = 10 * time.Second
+	cfg.ExpectVariants = 3
+	cfg.SLO = &models.SLOConfig{MaxPlaylistLatency: 500 * time.Millisecond}
+	cfg.MediaValidation = &models.MediaValidation{MinSegmentSize: 1024}
+	cfg.SizeAnomaly = &models.SizeAnomalyConfig{}
+
+	assert.Equal(t, map[string]float64{
+		ThresholdTimeout:         10,
+		ThresholdExpectVariants:  3,
+		ThresholdPlaylistLatency: 0.5,
+		ThresholdMinSegmentSize:  1024,
+		// Незаданный порог size_anomaly публикуется со значением по умолчанию
+		ThresholdSizeAnomaly: models.DefaultSizeAnomalyThreshold,
+	}, thresholds(cfg))
+
+	// Незаданные пороги не публикуются
+	assert.Empty(t, thresholds(models.StreamConfig{Name: "bare"}))
+}
@@ -0,0 +1,144 @@
+// Package silence применяет правила подавления оповещений (silences) к
+// результатам проверок на время плановых работ, без настройки Alertmanager.
+package silence
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/cron"
+	"github.com/iudanet/hls_exporter/internal/labels"
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.Silencer = (*Set)(nil)
+
+// LabelErrorType тип ошибки проверки (пусто для успешной проверки) в дополнение к меткам стрима
+const LabelErrorType = "error_type"
+
+// Rule разобранное правило подавления
+type Rule struct {
+	Name     string
+	selector labels.Selector
+	start    time.Time
+	end      time.Time
+	schedule *cron.Schedule
+	duration time.Duration
+}
+
+// Compile разбирает и проверяет правило из конфигурации. known проверяет имена
+// меток стримов (labels.Configured), метка error_type доступна всегда.
+func Compile(cfg models.SilenceRule, known func(label string) bool) (*Rule, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	selector, err := labels.ParseSelector(cfg.Matchers, func(label string) bool {
+		return label == LabelErrorType || known(label)
+	})
+	if err != nil {
+		return nil, err
+	}
+	rule := &Rule{Name: cfg.Name, selector: selector, duration: cfg.Duration}
+
+	if cfg.Start != "" {
+		if rule.start, err = time.Parse(time.RFC3339, cfg.Start); err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+	}
+	if cfg.End != "" {
+		if rule.end, err = time.Parse(time.RFC3339, cfg.End); err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+	}
+	if !rule.start.IsZero() && !rule.end.IsZero() && !rule.end.After(rule.start) {
+		return nil, fmt.Errorf("end must be after start")
+	}
+
+	switch {
+	case cfg.Cron != "":
+		if rule.schedule, err = cron.Parse(cfg.Cron); err != nil {
+			return nil, err
+		}
+		if cfg.Duration <= 0 {
+			return nil, fmt.Errorf("duration must be greater than 0 with cron")
+		}
+	case cfg.Duration != 0:
+		return nil, fmt.Errorf("duration requires cron")
+	}
+	return rule, nil
+}
+
+// Active сообщает, что время now попадает в окно действия правила
+func (r *Rule) Active(now time.Time) bool {
+	if !r.start.IsZero() && now.Before(r.start) {
+		return false
+	}
+	if !r.end.IsZero() && !now.Before(r.end) {
+		return false
+	}
+	if r.schedule == nil {
+		return true
+	}
+
+	// Окно действует, если последнее срабатывание было не раньше now-duration
+	fired := r.schedule.Next(now.Add(-r.duration))
+	return !fired.IsZero() && !fired.After(now)
+}
+
+// Matches сравнивает правило с метками результата
+func (r *Rule) Matches(values map[string]string) bool {
+	return r.selector.Matches(values)
+}
+
+// Set набор правил подавления
+type Set struct {
+	mu    sync.RWMutex
+	rules []*Rule
+	now   func() time.Time
+}
+
+// New разбирает правила из конфигурации. known проверяет имена меток стримов.
+func New(cfgs []models.SilenceRule, known func(label string) bool) (*Set, error) {
+	s := &Set{now: time.Now}
+	if err := s.SetRules(cfgs, known); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetRules заменяет набор правил. При ошибке разбора набор не меняется.
+func (s *Set) SetRules(cfgs []models.SilenceRule, known func(label string) bool) error {
+	rules := make([]*Rule, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		rule, err := Compile(cfg, known)
+		if err != nil {
+			return fmt.Errorf("silences[%d]: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = rules
+	return nil
+}
+
+// Silence возвращает имя первого действующего правила, совпавшего с результатом
+func (s *Set) Silence(stream models.StreamConfig, result *models.CheckResult) (string, bool) {
+	values := labels.StreamLabels(stream)
+	if result != nil && result.Error != nil {
+		values[LabelErrorType] = string(result.Error.Type)
+	}
+
+	now := s.now()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, rule := range s.rules {
+		if rule.Active(now) && rule.Matches(values) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
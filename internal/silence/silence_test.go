@@ -0,0 +1,92 @@
+package silence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/internal/labels"
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.SilenceRule
+		want string
+	}{
+		{name: "no name", rule: models.SilenceRule{}, want: "name is required"},
+		{name: "unknown label", rule: models.SilenceRule{Name: "r", Matchers: []string{`tenant="a"`}}, want: "unknown label"},
+		{name: "bad start", rule: models.SilenceRule{Name: "r", Start: "tomorrow"}, want: "invalid start"},
+		{
+			name: "end before start",
+			rule: models.SilenceRule{Name: "r", Start: "2025-01-02T00:00:00Z", End: "2025-01-01T00:00:00Z"},
+			want: "end must be after start",
+		},
+		{name: "cron without duration", rule: models.SilenceRule{Name: "r", Cron: "0 3 * * *"}, want: "duration must be"},
+		{name: "duration without cron", rule: models.SilenceRule{Name: "r", Duration: time.Hour}, want: "duration requires cron"},
+		{name: "bad cron", rule: models.SilenceRule{Name: "r", Cron: "0 3 * *", Duration: time.Hour}, want: "invalid cron"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.rule, labels.Known)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestCompile_CustomLabel(t *testing.T) {
+	known := labels.Configured([]models.StreamConfig{{Name: "news", Labels: map[string]string{"tenant": "media"}}})
+	rule, err := Compile(models.SilenceRule{Name: "r", Matchers: []string{`tenant="media"`}}, known)
+	require.NoError(t, err)
+
+	assert.True(t, rule.Matches(labels.StreamLabels(models.StreamConfig{Name: "news", Labels: map[string]string{"tenant": "media"}})))
+	assert.False(t, rule.Matches(labels.StreamLabels(models.StreamConfig{Name: "sport"})))
+}
+
+func TestRule_Active(t *testing.T) {
+	rule, err := Compile(models.SilenceRule{
+		Name:     "nightly",
+		Start:    "2025-01-01T00:00:00Z",
+		End:      "2025-02-01T00:00:00Z",
+		Cron:     "0 3 * * *",
+		Duration: 30 * time.Minute,
+	}, labels.Known)
+	require.NoError(t, err)
+
+	// Выражение cron действует в локальном часовом поясе
+	local := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.Local)
+	}
+
+	assert.True(t, rule.Active(local(time.January, 10, 3, 0)))
+	assert.True(t, rule.Active(local(time.January, 10, 3, 29)))
+	assert.False(t, rule.Active(local(time.January, 10, 3, 30)))
+	assert.False(t, rule.Active(local(time.January, 10, 2, 59)))
+	// За пределами [start, end) окно cron не действует
+	assert.False(t, rule.Active(local(time.February, 10, 3, 10)))
+}
+
+func TestSet_Silence(t *testing.T) {
+	set, err := New([]models.SilenceRule{
+		{Name: "segments", Matchers: []string{`stream=~"news_.*"`, `error_type="segment_download"`}},
+		{Name: "sport", Matchers: []string{`stream="sport"`}, End: "2025-01-01T00:00:00Z"},
+	}, labels.Known)
+	require.NoError(t, err)
+	set.now = func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	failed := &models.CheckResult{Error: &models.CheckError{Type: models.ErrSegmentDownload}}
+
+	name, ok := set.Silence(models.StreamConfig{Name: "news_hd"}, failed)
+	assert.True(t, ok)
+	assert.Equal(t, "segments", name)
+
+	// Успешная проверка не совпадает с правилом по типу ошибки
+	_, ok = set.Silence(models.StreamConfig{Name: "news_hd"}, &models.CheckResult{Success: true})
+	assert.False(t, ok)
+
+	// Окно правила закончилось
+	_, ok = set.Silence(models.StreamConfig{Name: "sport"}, failed)
+	assert.False(t, ok)
+}
@@ -0,0 +1,232 @@
+// Package soak отслеживает потребление ресурсов процесса при длительных
+// прогонах и экспортирует его вместе с порогами для алертов.
+package soak
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	MetricGoroutines        = "hls_exporter_soak_goroutines"
+	MetricGoroutinesGrowth  = "hls_exporter_soak_goroutines_growth"
+	MetricHeapBytes         = "hls_exporter_soak_heap_bytes"
+	MetricOpenFDs           = "hls_exporter_soak_open_fds"
+	MetricThreshold         = "hls_exporter_soak_threshold"
+	MetricThresholdExceeded = "hls_exporter_soak_threshold_exceeded"
+	MetricCheckTasksActive  = "hls_exporter_check_tasks_active"
+	MetricCheckTaskLeaks    = "hls_exporter_check_task_leaks_total"
+)
+
+const (
+	resourceGoroutines = "goroutines"
+	resourceHeapBytes  = "heap_bytes"
+	resourceOpenFDs    = "open_fds"
+
+	fdDir = "/proc/self/fd"
+	// fdUnavailable число дескрипторов недоступно на платформе
+	fdUnavailable = -1
+)
+
+// Sample снимок потребления ресурсов
+type Sample struct {
+	Goroutines int
+	HeapBytes  int64
+	// OpenFDs -1, если число дескрипторов недоступно на платформе
+	OpenFDs     int
+	ActiveTasks int64
+}
+
+// Monitor периодически снимает показатели процесса и сравнивает их с порогами
+type Monitor struct {
+	cfg    models.SoakConfig
+	logger *zap.Logger
+
+	goroutines        prometheus.Gauge
+	goroutinesGrowth  prometheus.Gauge
+	heapBytes         prometheus.Gauge
+	openFDs           prometheus.Gauge
+	threshold         *prometheus.GaugeVec
+	thresholdExceeded *prometheus.GaugeVec
+	tasksActive       prometheus.Gauge
+	taskLeaks         *prometheus.CounterVec
+
+	mu       sync.Mutex
+	baseline int
+	tasks    func() int64
+	sample   func() Sample
+}
+
+func NewMonitor(cfg models.SoakConfig, reg prometheus.Registerer, logger *zap.Logger) *Monitor {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	m := &Monitor{
+		cfg:    cfg,
+		logger: logger,
+		goroutines: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricGoroutines,
+			Help: "Number of goroutines sampled in soak mode",
+		}),
+		goroutinesGrowth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricGoroutinesGrowth,
+			Help: "Goroutine count change since soak monitor start",
+		}),
+		heapBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricHeapBytes,
+			Help: "In-use heap bytes sampled in soak mode",
+		}),
+		openFDs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricOpenFDs,
+			Help: "Number of open file descriptors sampled in soak mode",
+		}),
+		threshold: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: MetricThreshold,
+			Help: "Configured soak alert threshold per resource",
+		}, []string{"resource"}),
+		thresholdExceeded: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: MetricThresholdExceeded,
+			Help: "Whether the resource exceeds its soak threshold (1 = exceeded)",
+		}, []string{"resource"}),
+		tasksActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: MetricCheckTasksActive,
+			Help: "Number of check tasks currently running on the worker pool",
+		}),
+		taskLeaks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: MetricCheckTaskLeaks,
+			Help: "Check tasks still running after their check returned",
+		}, []string{"name"}),
+		tasks: func() int64 { return 0 },
+	}
+	m.sample = m.collect
+
+	reg.MustRegister(
+		m.goroutines,
+		m.goroutinesGrowth,
+		m.heapBytes,
+		m.openFDs,
+		m.threshold,
+		m.thresholdExceeded,
+		m.tasksActive,
+		m.taskLeaks,
+	)
+
+	m.setThreshold(resourceGoroutines, float64(cfg.MaxGoroutines))
+	m.setThreshold(resourceHeapBytes, float64(cfg.MaxHeapBytes))
+	m.setThreshold(resourceOpenFDs, float64(cfg.MaxOpenFDs))
+
+	return m
+}
+
+// SetTaskSource задает источник числа выполняющихся задач проверок
+func (m *Monitor) SetTaskSource(fn func() int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tasks = fn
+}
+
+// RecordLeak учитывает задачи, пережившие свою проверку
+func (m *Monitor) RecordLeak(stream string, leaked int64) {
+	m.taskLeaks.WithLabelValues(stream).Add(float64(leaked))
+}
+
+// Run снимает показатели с заданным интервалом до отмены контекста
+func (m *Monitor) Run(ctx context.Context) {
+	m.mu.Lock()
+	m.baseline = m.sample().Goroutines
+	m.mu.Unlock()
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	m.Observe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Observe()
+		}
+	}
+}
+
+// Observe снимает показатели и обновляет метрики
+func (m *Monitor) Observe() Sample {
+	m.mu.Lock()
+	s := m.sample()
+	s.ActiveTasks = m.tasks()
+	baseline := m.baseline
+	m.mu.Unlock()
+
+	m.goroutines.Set(float64(s.Goroutines))
+	m.goroutinesGrowth.Set(float64(s.Goroutines - baseline))
+	m.heapBytes.Set(float64(s.HeapBytes))
+	m.tasksActive.Set(float64(s.ActiveTasks))
+	if s.OpenFDs != fdUnavailable {
+		m.openFDs.Set(float64(s.OpenFDs))
+	}
+
+	m.checkThreshold(resourceGoroutines, float64(s.Goroutines), float64(m.cfg.MaxGoroutines))
+	m.checkThreshold(resourceHeapBytes, float64(s.HeapBytes), float64(m.cfg.MaxHeapBytes))
+	if s.OpenFDs != fdUnavailable {
+		m.checkThreshold(resourceOpenFDs, float64(s.OpenFDs), float64(m.cfg.MaxOpenFDs))
+	}
+
+	return s
+}
+
+func (m *Monitor) setThreshold(resource string, value float64) {
+	if value > 0 {
+		m.threshold.WithLabelValues(resource).Set(value)
+	}
+}
+
+func (m *Monitor) checkThreshold(resource string, value, limit float64) {
+	if limit <= 0 {
+		return
+	}
+
+	exceeded := value > limit
+	gauge := m.thresholdExceeded.WithLabelValues(resource)
+	if exceeded {
+		gauge.Set(1)
+		m.logger.Warn("Soak threshold exceeded",
+			zap.String("resource", resource),
+			zap.Float64("value", value),
+			zap.Float64("threshold", limit))
+		return
+	}
+	gauge.Set(0)
+}
+
+// collect снимает показатели текущего процесса
+func (m *Monitor) collect() Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	return Sample{
+		Goroutines: runtime.NumGoroutine(),
+		HeapBytes:  int64(ms.HeapInuse), // #nosec G115 -- размер кучи не превышает int64
+		OpenFDs:    countOpenFDs(),
+	}
+}
+
+// countOpenFDs возвращает число открытых дескрипторов или -1, если /proc недоступен
+func countOpenFDs() int {
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return fdUnavailable
+	}
+	return len(entries)
+}
@@ -0,0 +1,99 @@
+package soak
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// value возвращает текущее значение gauge или counter
+func value(t *testing.T, c prometheus.Metric) float64 {
+	t.Helper()
+
+	var metric dto.Metric
+	require.NoError(t, c.Write(&metric))
+	if metric.Counter != nil {
+		return metric.Counter.GetValue()
+	}
+	return metric.Gauge.GetValue()
+}
+
+func TestMonitor_Observe(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMonitor(models.SoakConfig{
+		Enabled:       true,
+		Interval:      time.Second,
+		MaxGoroutines: 100,
+		MaxHeapBytes:  1 << 20,
+		MaxOpenFDs:    50,
+	}, reg, nil)
+
+	m.sample = func() Sample {
+		return Sample{Goroutines: 150, HeapBytes: 512 << 10, OpenFDs: fdUnavailable}
+	}
+	m.baseline = 120
+	m.SetTaskSource(func() int64 { return 3 })
+
+	s := m.Observe()
+	assert.Equal(t, int64(3), s.ActiveTasks)
+
+	assert.Equal(t, float64(150), value(t, m.goroutines))
+	assert.Equal(t, float64(30), value(t, m.goroutinesGrowth))
+	assert.Equal(t, float64(3), value(t, m.tasksActive))
+	assert.Equal(t, float64(100), value(t, m.threshold.WithLabelValues(resourceGoroutines)))
+	assert.Equal(t, float64(1), value(t, m.thresholdExceeded.WithLabelValues(resourceGoroutines)))
+	assert.Equal(t, float64(0), value(t, m.thresholdExceeded.WithLabelValues(resourceHeapBytes)))
+
+	// Недоступное число дескрипторов не экспортируется и не сравнивается с порогом
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, mf := range families {
+		if mf.GetName() == MetricThresholdExceeded {
+			assert.Len(t, mf.GetMetric(), 2)
+		}
+	}
+}
+
+func TestMonitor_RecordLeak(t *testing.T) {
+	m := NewMonitor(models.SoakConfig{Interval: time.Second}, prometheus.NewRegistry(), nil)
+
+	m.RecordLeak("stream_1", 2)
+	m.RecordLeak("stream_1", 1)
+	assert.Equal(t, float64(3), value(t, m.taskLeaks.WithLabelValues("stream_1")))
+}
+
+func TestMonitor_Run(t *testing.T) {
+	m := NewMonitor(models.SoakConfig{Interval: 10 * time.Millisecond}, prometheus.NewRegistry(), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return value(t, m.goroutines) > 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should stop on context cancel")
+	}
+}
+
+func TestCountOpenFDs(t *testing.T) {
+	n := countOpenFDs()
+	if n == fdUnavailable {
+		t.Skip("/proc is not available")
+	}
+	assert.Positive(t, n)
+}
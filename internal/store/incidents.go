@@ -0,0 +1,161 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.IncidentStore = (*IncidentStore)(nil)
+
+// IncidentStore хранит в памяти текущий инцидент каждого стрима
+type IncidentStore struct {
+	mu        sync.RWMutex
+	incidents map[string]*models.Incident
+}
+
+func NewIncidentStore() *IncidentStore {
+	return &IncidentStore{
+		incidents: make(map[string]*models.Incident),
+	}
+}
+
+// Observe учитывает результат проверки. Неуспешная проверка с отпечатком текущего
+// инцидента продлевает его, с другим отпечатком - открывает новый инцидент.
+// Успешная проверка закрывает инцидент стрима.
+func (s *IncidentStore) Observe(result *models.CheckResult) {
+	if result == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if result.Success {
+		delete(s.incidents, result.StreamName)
+		return
+	}
+
+	next := newIncident(result)
+	if cur, ok := s.incidents[result.StreamName]; ok && cur.Fingerprint == next.Fingerprint {
+		next.FirstSeen = cur.FirstSeen
+		next.Occurrences = cur.Occurrences + 1
+	}
+	s.incidents[result.StreamName] = next
+}
+
+// Get возвращает текущий инцидент стрима
+func (s *IncidentStore) Get(name string) (*models.Incident, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incident, ok := s.incidents[name]
+	return incident, ok
+}
+
+// List возвращает текущие инциденты всех стримов, отсортированные по имени
+func (s *IncidentStore) List() []*models.Incident {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]*models.Incident, 0, len(s.incidents))
+	for _, incident := range s.incidents {
+		list = append(list, incident)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Stream < list[j].Stream
+	})
+	return list
+}
+
+// Delete удаляет инцидент стрима
+func (s *IncidentStore) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.incidents, name)
+}
+
+// newIncident строит инцидент из одной неуспешной проверки.
+// Инциденты хранятся неизменяемыми: Observe заменяет их целиком.
+func newIncident(result *models.CheckResult) *models.Incident {
+	incident := &models.Incident{
+		Stream:      result.StreamName,
+		Variants:    affectedVariants(result),
+		FirstSeen:   result.Timestamp,
+		LastSeen:    result.Timestamp,
+		Occurrences: 1,
+		LastCheckID: result.CheckID,
+	}
+	if result.Error != nil {
+		incident.ErrorType = result.Error.Type
+		incident.StatusCode = result.Error.StatusCode
+		incident.Message = result.Error.Message
+	}
+	if incident.StatusCode == 0 {
+		incident.StatusCode = failedStatusCode(result)
+	}
+	incident.Fingerprint = fingerprint(incident)
+	return incident
+}
+
+// fingerprint хеширует тип ошибки, код ответа и затронутые варианты. Сообщение об ошибке
+// не учитывается: оно содержит меняющиеся от проверки к проверке URL сегментов.
+func fingerprint(incident *models.Incident) string {
+	key := strings.Join([]string{
+		string(incident.ErrorType),
+		strconv.Itoa(incident.StatusCode),
+		strings.Join(incident.Variants, ","),
+	}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// affectedVariants возвращает отсортированные каталоги неуспешных сегментов и рендишенов.
+// Каталог вместо URL сохраняет отпечаток, пока окно плейлиста сдвигается.
+func affectedVariants(result *models.CheckResult) []string {
+	var variants []string
+	for _, seg := range result.Segments.Details {
+		if !seg.Success {
+			variants = append(variants, urlPattern(seg.URL))
+		}
+	}
+	for _, rendition := range result.Renditions {
+		if !rendition.Success {
+			variants = append(variants, urlPattern(rendition.URL))
+		}
+	}
+	slices.Sort(variants)
+	return slices.Compact(variants)
+}
+
+// urlPattern отбрасывает из URL имя файла и параметры запроса
+func urlPattern(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host + path.Dir(u.Path)
+}
+
+// failedStatusCode возвращает код ответа первого неуспешного сегмента или рендишена
+func failedStatusCode(result *models.CheckResult) int {
+	for _, seg := range result.Segments.Details {
+		if seg.Error != nil && seg.Error.StatusCode != 0 {
+			return seg.Error.StatusCode
+		}
+	}
+	for _, rendition := range result.Renditions {
+		if rendition.Error != nil && rendition.Error.StatusCode != 0 {
+			return rendition.Error.StatusCode
+		}
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failedSegments(ts time.Time, urls ...string) *models.CheckResult {
+	result := &models.CheckResult{
+		StreamName: "stream_a",
+		Timestamp:  ts,
+		Error:      &models.CheckError{Type: models.ErrSegmentDownload, Message: "segment failed: " + urls[0]},
+	}
+	for _, u := range urls {
+		result.Segments.Details = append(result.Segments.Details, models.SegmentCheck{
+			URL:   u,
+			Error: &models.CheckError{Type: models.ErrSegmentDownload, StatusCode: http.StatusNotFound},
+		})
+	}
+	return result
+}
+
+func TestIncidentStore(t *testing.T) {
+	s := NewIncidentStore()
+	start := time.Unix(1_700_000_000, 0)
+
+	s.Observe(nil)
+	s.Observe(&models.CheckResult{StreamName: "stream_a", Success: true, Timestamp: start})
+	_, ok := s.Get("stream_a")
+	assert.False(t, ok, "successful check should not open an incident")
+
+	// Окно плейлиста сдвигается, но проблема та же
+	s.Observe(failedSegments(start, "http://cdn/live/720p/seg1.ts?token=a"))
+	s.Observe(failedSegments(start.Add(10*time.Second), "http://cdn/live/720p/seg3.ts?token=b"))
+	incident, ok := s.Get("stream_a")
+	require.True(t, ok)
+	assert.Equal(t, 2, incident.Occurrences)
+	assert.Equal(t, start, incident.FirstSeen)
+	assert.Equal(t, start.Add(10*time.Second), incident.LastSeen)
+	assert.Equal(t, http.StatusNotFound, incident.StatusCode)
+	assert.Equal(t, []string{"cdn/live/720p"}, incident.Variants)
+	assert.Len(t, incident.Fingerprint, 16)
+	fingerprint := incident.Fingerprint
+
+	// Затронут другой вариант: новый инцидент
+	s.Observe(failedSegments(start.Add(20*time.Second),
+		"http://cdn/live/720p/seg5.ts", "http://cdn/live/1080p/seg5.ts"))
+	incident, ok = s.Get("stream_a")
+	require.True(t, ok)
+	assert.NotEqual(t, fingerprint, incident.Fingerprint)
+	assert.Equal(t, 1, incident.Occurrences)
+	assert.Equal(t, start.Add(20*time.Second), incident.FirstSeen)
+	assert.Equal(t, []string{"cdn/live/1080p", "cdn/live/720p"}, incident.Variants)
+
+	s.Observe(&models.CheckResult{StreamName: "stream_b", Timestamp: start,
+		Error: &models.CheckError{Type: models.ErrPlaylistDownload, StatusCode: http.StatusServiceUnavailable}})
+	list := s.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "stream_a", list[0].Stream)
+	assert.Equal(t, "stream_b", list[1].Stream)
+
+	// Успешная проверка закрывает инцидент
+	s.Observe(&models.CheckResult{StreamName: "stream_a", Success: true, Timestamp: start.Add(30 * time.Second)})
+	_, ok = s.Get("stream_a")
+	assert.False(t, ok)
+
+	s.Delete("stream_b")
+	assert.Empty(t, s.List())
+}
+
+func TestFingerprint_StatusCode(t *testing.T) {
+	unavailable := newIncident(&models.CheckResult{StreamName: "stream_a",
+		Error: &models.CheckError{Type: models.ErrPlaylistDownload, StatusCode: http.StatusServiceUnavailable}})
+	forbidden := newIncident(&models.CheckResult{StreamName: "stream_a",
+		Error: &models.CheckError{Type: models.ErrPlaylistDownload, StatusCode: http.StatusForbidden}})
+	assert.NotEqual(t, unavailable.Fingerprint, forbidden.Fingerprint)
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.EventJournal = (*Journal)(nil)
+
+// journalSize число хранимых записей журнала, старые записи вытесняются
+const journalSize = 1000
+
+// Journal хранит в памяти последние записи журнала действий admin API
+type Journal struct {
+	mu     sync.RWMutex
+	events []models.AuditEvent
+	// next позиция следующей записи в кольцевом буфере
+	next int
+	size int
+}
+
+func NewJournal() *Journal {
+	return newJournal(journalSize)
+}
+
+func newJournal(size int) *Journal {
+	return &Journal{events: make([]models.AuditEvent, 0, size), size: size}
+}
+
+// Record добавляет запись, вытесняя самую старую при заполнении журнала
+func (j *Journal) Record(event models.AuditEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if len(j.events) < j.size {
+		j.events = append(j.events, event)
+		return
+	}
+	j.events[j.next] = event
+	j.next = (j.next + 1) % j.size
+}
+
+// Events возвращает записи от новых к старым (stream "" - все, limit 0 - без ограничения)
+func (j *Journal) Events(stream string, limit int) []models.AuditEvent {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	var events []models.AuditEvent
+	for i := range len(j.events) {
+		// Обход от последней записи к первой с учетом сдвига кольцевого буфера
+		event := j.events[(j.next-1-i+2*len(j.events))%len(j.events)]
+		if stream != "" && event.Stream != stream {
+			continue
+		}
+		events = append(events, event)
+		if limit > 0 && len(events) == limit {
+			break
+		}
+	}
+	return events
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	j := newJournal(3)
+	assert.Empty(t, j.Events("", 0))
+
+	for _, stream := range []string{"a", "b", "a", "c"} {
+		j.Record(models.AuditEvent{Action: models.AuditCheckTriggered, Stream: stream})
+	}
+
+	// Самая старая запись вытеснена, записи идут от новых к старым
+	events := j.Events("", 0)
+	assert.Equal(t, []string{"c", "a", "b"}, streams(events))
+
+	assert.Equal(t, []string{"a"}, streams(j.Events("a", 0)))
+	assert.Equal(t, []string{"c", "a"}, streams(j.Events("", 2)))
+}
+
+func streams(events []models.AuditEvent) []string {
+	names := make([]string, 0, len(events))
+	for _, e := range events {
+		names = append(names, e.Stream)
+	}
+	return names
+}
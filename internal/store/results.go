@@ -0,0 +1,125 @@
+package store
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var (
+	_ models.ResultStore = (*ResultStore)(nil)
+	_ models.ResultFeed  = (*ResultStore)(nil)
+)
+
+// recentSamples число последних проверок стрима, хранимых для графиков
+const recentSamples = 60
+
+// subscriberBuffer размер буфера канала подписчика
+const subscriberBuffer = 64
+
+// ResultStore хранит в памяти результат последней проверки каждого стрима
+// и краткие сводки нескольких предыдущих
+type ResultStore struct {
+	mu      sync.RWMutex
+	results map[string]*models.CheckResult
+	recent  map[string][]models.ResultSample
+	// Подписчики на новые результаты (gRPC WatchResults, CheckNow)
+	subscribers map[chan *models.CheckResult]struct{}
+}
+
+func NewResultStore() *ResultStore {
+	return &ResultStore{
+		results: make(map[string]*models.CheckResult),
+		recent:  make(map[string][]models.ResultSample),
+
+		subscribers: make(map[chan *models.CheckResult]struct{}),
+	}
+}
+
+// Save сохраняет результат, заменяя предыдущий результат стрима
+func (s *ResultStore) Save(result *models.CheckResult) {
+	if result == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[result.StreamName] = result
+
+	samples := s.recent[result.StreamName]
+	if len(samples) >= recentSamples {
+		samples = append(samples[:0], samples[len(samples)-recentSamples+1:]...)
+	}
+	s.recent[result.StreamName] = append(samples, models.ResultSample{
+		Timestamp: result.Timestamp,
+		Duration:  result.Duration,
+		Success:   result.Success,
+		Bitrate:   result.Bitrate,
+		Error:     result.Error,
+	})
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- result:
+		default:
+		}
+	}
+}
+
+// Subscribe подписывает на результаты, сохраняемые после вызова
+func (s *ResultStore) Subscribe() (<-chan *models.CheckResult, func()) {
+	ch := make(chan *models.CheckResult, subscriberBuffer)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, ch)
+			s.mu.Unlock()
+		})
+	}
+}
+
+// Recent возвращает сводки последних проверок стрима, старые первыми
+func (s *ResultStore) Recent(name string) []models.ResultSample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.recent[name])
+}
+
+// Get возвращает последний результат стрима
+func (s *ResultStore) Get(name string) (*models.CheckResult, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result, ok := s.results[name]
+	return result, ok
+}
+
+// List возвращает последние результаты всех стримов, отсортированные по имени
+func (s *ResultStore) List() []*models.CheckResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]*models.CheckResult, 0, len(s.results))
+	for _, result := range s.results {
+		list = append(list, result)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StreamName < list[j].StreamName
+	})
+	return list
+}
+
+// Delete удаляет результаты стрима
+func (s *ResultStore) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.results, name)
+	delete(s.recent, name)
+}
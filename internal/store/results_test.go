@@ -0,0 +1,82 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultStore(t *testing.T) {
+	s := NewResultStore()
+
+	_, ok := s.Get("stream_a")
+	assert.False(t, ok)
+
+	s.Save(nil)
+	s.Save(&models.CheckResult{StreamName: "stream_b", Success: true})
+	s.Save(&models.CheckResult{StreamName: "stream_a", Success: false})
+	s.Save(&models.CheckResult{StreamName: "stream_a", Success: true})
+
+	result, ok := s.Get("stream_a")
+	require.True(t, ok)
+	assert.True(t, result.Success, "latest result should replace the previous one")
+
+	list := s.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "stream_a", list[0].StreamName)
+	assert.Equal(t, "stream_b", list[1].StreamName)
+
+	recent := s.Recent("stream_a")
+	require.Len(t, recent, 2)
+	assert.False(t, recent[0].Success)
+	assert.True(t, recent[1].Success)
+
+	s.Delete("stream_a")
+	_, ok = s.Get("stream_a")
+	assert.False(t, ok)
+	assert.Len(t, s.List(), 1)
+	assert.Empty(t, s.Recent("stream_a"))
+}
+
+func TestResultStore_RecentLimit(t *testing.T) {
+	s := NewResultStore()
+	for i := range recentSamples + 5 {
+		s.Save(&models.CheckResult{StreamName: "stream_a", Duration: time.Duration(i)})
+	}
+
+	recent := s.Recent("stream_a")
+	require.Len(t, recent, recentSamples)
+	assert.Equal(t, time.Duration(5), recent[0].Duration, "oldest samples are dropped")
+	assert.Equal(t, time.Duration(recentSamples+4), recent[recentSamples-1].Duration)
+}
+
+func TestResultStore_Subscribe(t *testing.T) {
+	s := NewResultStore()
+	s.Save(&models.CheckResult{StreamName: "before"})
+
+	ch, cancel := s.Subscribe()
+	s.Save(&models.CheckResult{StreamName: "stream_a"})
+	select {
+	case result := <-ch:
+		assert.Equal(t, "stream_a", result.StreamName, "results saved before Subscribe are not sent")
+	default:
+		t.Fatal("saved result was not sent to the subscriber")
+	}
+
+	// Не читающий подписчик не блокирует Save
+	for range subscriberBuffer + 5 {
+		s.Save(&models.CheckResult{StreamName: "stream_a"})
+	}
+	assert.Len(t, ch, subscriberBuffer)
+
+	cancel()
+	cancel()
+	for len(ch) > 0 {
+		<-ch
+	}
+	s.Save(&models.CheckResult{StreamName: "stream_a"})
+	assert.Empty(t, ch, "unsubscribed channel receives no results")
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/iudanet/hls_exporter/pkg/models"
+)
+
+var _ models.TransactionStore = (*TransactionStore)(nil)
+
+// TransactionStore хранит в памяти журнал транзакций последней неуспешной проверки каждого стрима
+type TransactionStore struct {
+	mu      sync.RWMutex
+	bundles map[string]*models.TransactionBundle
+}
+
+func NewTransactionStore() *TransactionStore {
+	return &TransactionStore{
+		bundles: make(map[string]*models.TransactionBundle),
+	}
+}
+
+// Save сохраняет журнал, заменяя предыдущий журнал стрима
+func (s *TransactionStore) Save(bundle *models.TransactionBundle) {
+	if bundle == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bundles[bundle.Stream] = bundle
+}
+
+// Get возвращает журнал последней неуспешной проверки стрима
+func (s *TransactionStore) Get(stream string) (*models.TransactionBundle, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bundle, ok := s.bundles[stream]
+	return bundle, ok
+}
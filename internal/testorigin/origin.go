@@ -0,0 +1,404 @@
+// Package testorigin реализует синтетический HLS-источник для интеграционных
+// тестов и ручной проверки экспортера: живое окно сегментов, разрывы,
+// зашифрованные варианты, LL-HLS части и управляемые сбои.
+package testorigin
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Variant описывает вариант потока
+type Variant struct {
+	Name       string
+	Bandwidth  int
+	Resolution string
+	Encrypted  bool
+}
+
+// Config параметры синтетического источника
+type Config struct {
+	Variants        []Variant
+	SegmentDuration time.Duration
+	WindowSize      int
+	// DiscontinuityEvery добавляет EXT-X-DISCONTINUITY перед каждым N-м сегментом
+	DiscontinuityEvery int
+	// LowLatency включает LL-HLS части для последнего сегмента
+	LowLatency   bool
+	PartDuration time.Duration
+	// MaxSegmentPackets ограничивает размер сегмента в TS-пакетах
+	MaxSegmentPackets int
+	// Start момент появления первого сегмента
+	Start time.Time
+}
+
+// Faults управляемые сбои источника
+type Faults struct {
+	// StalePlaylist замораживает медиаплейлисты на момент включения
+	StalePlaylist bool
+	// TruncateSegments обрывает передачу сегментов на середине
+	TruncateSegments bool
+	// PlaylistStatus и SegmentStatus подменяют код ответа, если не 0
+	PlaylistStatus int
+	SegmentStatus  int
+	// Latency задержка перед каждым ответом
+	Latency time.Duration
+	// RemapPIDs меняет PID элементарных потоков в PMT сегментов
+	RemapPIDs bool
+	// DropPackets выбрасывает видеопакет из сегментов, нарушая счетчик непрерывности
+	DropPackets bool
+}
+
+// Origin синтетический HLS-источник
+type Origin struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.RWMutex
+	faults   Faults
+	frozenAt time.Time
+}
+
+// DefaultConfig возвращает конфигурацию с двумя вариантами
+func DefaultConfig() Config {
+	return Config{
+		Variants: []Variant{
+			{Name: "720p", Bandwidth: 2_000_000, Resolution: "1280x720"},
+			{Name: "360p", Bandwidth: 800_000, Resolution: "640x360"},
+		},
+		SegmentDuration:   2 * time.Second,
+		WindowSize:        6,
+		PartDuration:      500 * time.Millisecond,
+		MaxSegmentPackets: 500,
+	}
+}
+
+// New создает источник
+func New(cfg Config) *Origin {
+	def := DefaultConfig()
+	if len(cfg.Variants) == 0 {
+		cfg.Variants = def.Variants
+	}
+	if cfg.SegmentDuration <= 0 {
+		cfg.SegmentDuration = def.SegmentDuration
+	}
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = def.WindowSize
+	}
+	if cfg.PartDuration <= 0 {
+		cfg.PartDuration = def.PartDuration
+	}
+	if cfg.MaxSegmentPackets <= 0 {
+		cfg.MaxSegmentPackets = def.MaxSegmentPackets
+	}
+	if cfg.Start.IsZero() {
+		// Окно заполнено сразу после старта
+		cfg.Start = time.Now().Add(-time.Duration(cfg.WindowSize) * cfg.SegmentDuration)
+	}
+
+	return &Origin{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// SetClock подменяет источник времени (для тестов)
+func (o *Origin) SetClock(now func() time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.now = now
+}
+
+// SetFaults задает активные сбои
+func (o *Origin) SetFaults(f Faults) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if f.StalePlaylist && !o.faults.StalePlaylist {
+		o.frozenAt = o.now()
+	}
+	o.faults = f
+}
+
+// Faults возвращает активные сбои
+func (o *Origin) Faults() Faults {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.faults
+}
+
+// MasterURL путь мастер-плейлиста относительно корня источника
+func (o *Origin) MasterURL() string {
+	return "/master.m3u8"
+}
+
+// MediaURL путь медиаплейлиста варианта
+func (o *Origin) MediaURL(variant string) string {
+	return "/" + variant + "/index.m3u8"
+}
+
+// ServeHTTP обрабатывает запросы:
+//
+//	/master.m3u8
+//	/{variant}/index.m3u8
+//	/{variant}/seg_{seq}.ts
+//	/{variant}/part_{seq}_{part}.ts
+//	/{variant}/key.bin
+func (o *Origin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	faults := o.Faults()
+	if faults.Latency > 0 {
+		select {
+		case <-time.After(faults.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == "master.m3u8" {
+		if faults.PlaylistStatus != 0 {
+			http.Error(w, http.StatusText(faults.PlaylistStatus), faults.PlaylistStatus)
+			return
+		}
+		o.writePlaylist(w, o.masterPlaylist())
+		return
+	}
+
+	name, file, ok := strings.Cut(path, "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	variant, ok := o.variant(name)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case file == "index.m3u8":
+		if faults.PlaylistStatus != 0 {
+			http.Error(w, http.StatusText(faults.PlaylistStatus), faults.PlaylistStatus)
+			return
+		}
+		o.writePlaylist(w, o.mediaPlaylist(variant))
+	case file == "key.bin":
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(variantKey(variant.Name))
+	case strings.HasPrefix(file, "seg_") && strings.HasSuffix(file, ".ts"):
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(file, "seg_"), ".ts"), 10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		o.serveMedia(w, r, faults, variant, seq, o.cfg.SegmentDuration)
+	case strings.HasPrefix(file, "part_") && strings.HasSuffix(file, ".ts"):
+		seqStr, _, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(file, "part_"), ".ts"), "_")
+		seq, err := strconv.ParseUint(seqStr, 10, 64)
+		if !ok || err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		o.serveMedia(w, r, faults, variant, seq, o.cfg.PartDuration)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (o *Origin) variant(name string) (Variant, bool) {
+	for _, v := range o.cfg.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+func (o *Origin) writePlaylist(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write([]byte(body))
+}
+
+func (o *Origin) masterPlaylist() string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, v := range o.cfg.Variants {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d", v.Bandwidth)
+		if v.Resolution != "" {
+			fmt.Fprintf(&b, ",RESOLUTION=%s", v.Resolution)
+		}
+		b.WriteString(",CODECS=\"avc1.64001f,mp4a.40.2\"\n")
+		b.WriteString(v.Name + "/index.m3u8\n")
+	}
+	return b.String()
+}
+
+// clock возвращает текущее время источника с учетом заморозки плейлиста
+func (o *Origin) clock() time.Time {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if o.faults.StalePlaylist {
+		return o.frozenAt
+	}
+	return o.now()
+}
+
+// liveEdge возвращает номер последнего завершенного сегмента и долю
+// прошедшего времени текущего сегмента
+func (o *Origin) liveEdge(now time.Time) (uint64, time.Duration) {
+	elapsed := now.Sub(o.cfg.Start)
+	if elapsed < o.cfg.SegmentDuration {
+		return 0, 0
+	}
+	completed := uint64(elapsed / o.cfg.SegmentDuration)
+	return completed - 1, elapsed % o.cfg.SegmentDuration
+}
+
+func (o *Origin) mediaPlaylist(v Variant) string {
+	now := o.clock()
+	last, inProgress := o.liveEdge(now)
+
+	first := uint64(0)
+	if last+1 > uint64(o.cfg.WindowSize) {
+		first = last + 1 - uint64(o.cfg.WindowSize)
+	}
+
+	segSeconds := o.cfg.SegmentDuration.Seconds()
+	version := 3
+	if o.cfg.LowLatency {
+		version = 9
+	}
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#EXT-X-VERSION:%d\n", version)
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(o.cfg.SegmentDuration.Round(time.Second)/time.Second))
+	if o.cfg.LowLatency {
+		partSeconds := o.cfg.PartDuration.Seconds()
+		fmt.Fprintf(&b, "#EXT-X-SERVER-CONTROL:CAN-BLOCK-RELOAD=NO,PART-HOLD-BACK=%.3f\n", 3*partSeconds)
+		fmt.Fprintf(&b, "#EXT-X-PART-INF:PART-TARGET=%.3f\n", partSeconds)
+	}
+	fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", first)
+	if o.cfg.DiscontinuityEvery > 0 {
+		fmt.Fprintf(&b, "#EXT-X-DISCONTINUITY-SEQUENCE:%d\n", first/uint64(o.cfg.DiscontinuityEvery))
+	}
+	if v.Encrypted {
+		fmt.Fprintf(&b, "#EXT-X-KEY:METHOD=AES-128,URI=\"key.bin\"\n")
+	}
+
+	for seq := first; seq <= last; seq++ {
+		if o.cfg.DiscontinuityEvery > 0 && seq > first && seq%uint64(o.cfg.DiscontinuityEvery) == 0 {
+			b.WriteString("#EXT-X-DISCONTINUITY\n")
+		}
+		pdt := o.cfg.Start.Add(time.Duration(seq) * o.cfg.SegmentDuration).UTC()
+		fmt.Fprintf(&b, "#EXT-X-PROGRAM-DATE-TIME:%s\n", pdt.Format("2006-01-02T15:04:05.000Z"))
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n", segSeconds)
+		fmt.Fprintf(&b, "seg_%d.ts\n", seq)
+	}
+
+	if o.cfg.LowLatency {
+		// Части сегмента, который еще формируется
+		next := last + 1
+		parts := int(inProgress / o.cfg.PartDuration)
+		for p := 0; p < parts; p++ {
+			fmt.Fprintf(&b, "#EXT-X-PART:DURATION=%.3f,URI=\"part_%d_%d.ts\"", o.cfg.PartDuration.Seconds(), next, p)
+			if p == 0 {
+				b.WriteString(",INDEPENDENT=YES")
+			}
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "#EXT-X-PRELOAD-HINT:TYPE=PART,URI=\"part_%d_%d.ts\"\n", next, parts)
+	}
+
+	return b.String()
+}
+
+// segmentBody формирует содержимое сегмента или части
+func (o *Origin) segmentBody(v Variant, seq uint64, duration time.Duration, faults Faults) ([]byte, error) {
+	packets := int(float64(v.Bandwidth) * duration.Seconds() / 8 / tsPacketSize)
+	if packets > o.cfg.MaxSegmentPackets {
+		packets = o.cfg.MaxSegmentPackets
+	}
+	pcrBase := seq * uint64(o.cfg.SegmentDuration.Seconds()*27_000_000)
+	layout := defaultLayout
+	if faults.RemapPIDs {
+		layout = remappedLayout
+	}
+	body := generateTS(packets, pcrBase, duration.Seconds(), layout)
+	if faults.DropPackets {
+		body = dropPacket(body, droppedPacket)
+	}
+
+	if !v.Encrypted {
+		return body, nil
+	}
+	return encryptSegment(body, variantKey(v.Name), seq)
+}
+
+func (o *Origin) serveMedia(w http.ResponseWriter, r *http.Request, faults Faults, v Variant, seq uint64, duration time.Duration) {
+	if faults.SegmentStatus != 0 {
+		http.Error(w, http.StatusText(faults.SegmentStatus), faults.SegmentStatus)
+		return
+	}
+
+	last, _ := o.liveEdge(o.clock())
+	if seq > last+1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := o.segmentBody(v, seq, duration, faults)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "video/mp2t")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	if faults.TruncateSegments {
+		// Заявленная длина больше переданной: клиент получит обрыв соединения
+		body = body[:len(body)/2+1]
+	}
+	_, _ = w.Write(body)
+}
+
+// variantKey детерминированный ключ AES-128 варианта
+func variantKey(name string) []byte {
+	sum := sha256.Sum256([]byte("testorigin:" + name))
+	return sum[:aes.BlockSize]
+}
+
+// encryptSegment шифрует сегмент AES-128-CBC с IV из номера сегмента (RFC 8216, 5.2)
+func encryptSegment(body, key []byte, seq uint64) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], seq)
+
+	pad := aes.BlockSize - len(body)%aes.BlockSize
+	padded := make([]byte, len(body)+pad)
+	copy(padded, body)
+	for i := len(body); i < len(padded); i++ {
+		padded[i] = byte(pad)
+	}
+
+	out := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, padded)
+	return out, nil
+}
@@ -0,0 +1,202 @@
+package testorigin
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafov/m3u8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrigin(t *testing.T, cfg Config) (*Origin, *httptest.Server, *time.Time) {
+	t.Helper()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg.Start = start
+	o := New(cfg)
+	now := start.Add(20 * time.Second)
+	o.SetClock(func() time.Time { return now })
+
+	srv := httptest.NewServer(o)
+	t.Cleanup(srv.Close)
+	return o, srv, &now
+}
+
+func get(t *testing.T, url string) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := http.Get(url) // #nosec G107 -- адрес тестового сервера
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, body
+}
+
+func decodeMedia(t *testing.T, body []byte) *m3u8.MediaPlaylist {
+	t.Helper()
+
+	p, listType, err := m3u8.DecodeFrom(strings.NewReader(string(body)), false)
+	require.NoError(t, err)
+	require.Equal(t, m3u8.MEDIA, listType)
+	return p.(*m3u8.MediaPlaylist)
+}
+
+func TestOrigin_MasterPlaylist(t *testing.T) {
+	_, srv, _ := newTestOrigin(t, Config{})
+
+	resp, body := get(t, srv.URL+"/master.m3u8")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	p, listType, err := m3u8.DecodeFrom(strings.NewReader(string(body)), false)
+	require.NoError(t, err)
+	require.Equal(t, m3u8.MASTER, listType)
+
+	master := p.(*m3u8.MasterPlaylist)
+	require.Len(t, master.Variants, 2)
+	assert.Equal(t, "720p/index.m3u8", master.Variants[0].URI)
+	assert.Equal(t, uint32(2_000_000), master.Variants[0].Bandwidth)
+}
+
+func TestOrigin_RollingWindow(t *testing.T) {
+	_, srv, now := newTestOrigin(t, Config{WindowSize: 4})
+
+	// 20 секунд при 2-секундных сегментах: завершены сегменты 0..9
+	_, body := get(t, srv.URL+"/720p/index.m3u8")
+	media := decodeMedia(t, body)
+	assert.Equal(t, uint64(6), media.SeqNo)
+	assert.Equal(t, uint(4), media.Count())
+	assert.Equal(t, "seg_9.ts", media.Segments[3].URI)
+	assert.False(t, media.Segments[0].ProgramDateTime.IsZero())
+
+	*now = now.Add(4 * time.Second)
+	_, body = get(t, srv.URL+"/720p/index.m3u8")
+	media = decodeMedia(t, body)
+	assert.Equal(t, uint64(8), media.SeqNo)
+}
+
+func TestOrigin_Discontinuity(t *testing.T) {
+	_, srv, _ := newTestOrigin(t, Config{WindowSize: 6, DiscontinuityEvery: 3})
+
+	_, body := get(t, srv.URL+"/720p/index.m3u8")
+	media := decodeMedia(t, body)
+
+	var discontinuities int
+	for i := uint(0); i < media.Count(); i++ {
+		if media.Segments[i].Discontinuity {
+			discontinuities++
+		}
+	}
+	assert.Equal(t, 2, discontinuities)
+	assert.Contains(t, string(body), "#EXT-X-DISCONTINUITY-SEQUENCE:1")
+}
+
+func TestOrigin_Segment(t *testing.T) {
+	_, srv, _ := newTestOrigin(t, Config{})
+
+	resp, body := get(t, srv.URL+"/720p/seg_9.ts")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "video/mp2t", resp.Header.Get("Content-Type"))
+	require.Zero(t, len(body)%tsPacketSize)
+
+	// PAT и PMT в начале сегмента
+	assert.Equal(t, byte(tsSyncByte), body[0])
+	assert.Equal(t, patPID, uint16(body[1]&0x1F)<<8|uint16(body[2]))
+	assert.Equal(t, pmtPID, uint16(body[tsPacketSize+1]&0x1F)<<8|uint16(body[tsPacketSize+2]))
+
+	// Сегменты из будущего недоступны
+	resp, _ = get(t, srv.URL+"/720p/seg_100.ts")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestOrigin_Encrypted(t *testing.T) {
+	_, srv, _ := newTestOrigin(t, Config{
+		Variants: []Variant{{Name: "enc", Bandwidth: 500_000, Encrypted: true}},
+	})
+
+	_, playlist := get(t, srv.URL+"/enc/index.m3u8")
+	media := decodeMedia(t, playlist)
+	require.NotNil(t, media.Key)
+	assert.Equal(t, "AES-128", media.Key.Method)
+
+	_, key := get(t, srv.URL+"/enc/key.bin")
+	_, body := get(t, srv.URL+"/enc/seg_9.ts")
+	require.Zero(t, len(body)%aes.BlockSize)
+
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	iv := make([]byte, aes.BlockSize)
+	binary.BigEndian.PutUint64(iv[8:], 9)
+	plain := make([]byte, len(body))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, body)
+	assert.Equal(t, byte(tsSyncByte), plain[0])
+}
+
+func TestOrigin_LowLatency(t *testing.T) {
+	_, srv, now := newTestOrigin(t, Config{LowLatency: true})
+	*now = now.Add(1100 * time.Millisecond)
+
+	_, body := get(t, srv.URL+"/720p/index.m3u8")
+	text := string(body)
+	assert.Contains(t, text, "#EXT-X-PART-INF:PART-TARGET=0.500")
+	assert.Contains(t, text, `#EXT-X-PART:DURATION=0.500,URI="part_10_0.ts",INDEPENDENT=YES`)
+	assert.Contains(t, text, `#EXT-X-PART:DURATION=0.500,URI="part_10_1.ts"`)
+	assert.Contains(t, text, `#EXT-X-PRELOAD-HINT:TYPE=PART,URI="part_10_2.ts"`)
+
+	resp, _ := get(t, srv.URL+"/720p/part_10_0.ts")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestOrigin_Faults(t *testing.T) {
+	o, srv, now := newTestOrigin(t, Config{})
+
+	t.Run("stale playlist", func(t *testing.T) {
+		o.SetFaults(Faults{StalePlaylist: true})
+		defer o.SetFaults(Faults{})
+
+		_, before := get(t, srv.URL+"/720p/index.m3u8")
+		*now = now.Add(10 * time.Second)
+		_, after := get(t, srv.URL+"/720p/index.m3u8")
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("status codes", func(t *testing.T) {
+		o.SetFaults(Faults{PlaylistStatus: http.StatusServiceUnavailable, SegmentStatus: http.StatusForbidden})
+		defer o.SetFaults(Faults{})
+
+		resp, _ := get(t, srv.URL+"/master.m3u8")
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		resp, _ = get(t, srv.URL+"/720p/seg_1.ts")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("truncated segment", func(t *testing.T) {
+		o.SetFaults(Faults{TruncateSegments: true})
+		defer o.SetFaults(Faults{})
+
+		resp, err := http.Get(srv.URL + "/720p/seg_1.ts") // #nosec G107 -- адрес тестового сервера
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("unknown variant", func(t *testing.T) {
+		resp, _ := get(t, srv.URL+"/1080p/index.m3u8")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestCRC32MPEG2(t *testing.T) {
+	// Контрольное значение CRC-32/MPEG-2 для "123456789"
+	assert.Equal(t, uint32(0x0376E6E7), crc32MPEG2([]byte("123456789")))
+}
@@ -0,0 +1,205 @@
+package testorigin
+
+import (
+	"encoding/binary"
+)
+
+const (
+	tsPacketSize = 188
+	tsSyncByte   = 0x47
+
+	patPID uint16 = 0x0000
+	pmtPID uint16 = 0x1000
+
+	streamTypeH264 = 0x1B
+	streamTypeAAC  = 0x0F
+
+	// pcrEvery PCR передается в каждом N-м видеопакете
+	pcrEvery = 10
+	// audioEvery каждый N-й пакет - аудио
+	audioEvery = 8
+	// droppedPacket индекс видеопакета, выбрасываемого при сбое DropPackets
+	droppedPacket = 3
+)
+
+// tsLayout PID элементарных потоков программы
+type tsLayout struct {
+	video uint16
+	audio uint16
+}
+
+var (
+	defaultLayout = tsLayout{video: 0x0100, audio: 0x0101}
+	// remappedLayout раскладка после перенастройки мультиплексора
+	remappedLayout = tsLayout{video: 0x0200, audio: 0x0201}
+)
+
+// tsWriter формирует синтетический MPEG-TS: PAT, PMT, видео с PCR и аудио,
+// с корректными счетчиками непрерывности
+type tsWriter struct {
+	buf    []byte
+	cc     map[uint16]byte
+	layout tsLayout
+}
+
+func newTSWriter(packets int, layout tsLayout) *tsWriter {
+	return &tsWriter{
+		buf:    make([]byte, 0, packets*tsPacketSize),
+		cc:     make(map[uint16]byte),
+		layout: layout,
+	}
+}
+
+// generateTS создает сегмент из указанного числа пакетов. pcrBase задает
+// начальное значение PCR (в тиках 27 МГц) для непрерывности между сегментами.
+func generateTS(packets int, pcrBase uint64, duration float64, layout tsLayout) []byte {
+	if packets < 3 {
+		packets = 3
+	}
+
+	w := newTSWriter(packets, layout)
+	w.writePSI(patPID, patSection())
+	w.writePSI(pmtPID, pmtSection(layout))
+
+	media := packets - 2
+	videoPackets := media - media/audioEvery
+	pcrStep := uint64(0)
+	if videoPackets > 0 {
+		pcrStep = uint64(duration * 27_000_000 / float64(videoPackets))
+	}
+
+	video := 0
+	for i := 0; i < media; i++ {
+		if i%audioEvery == audioEvery-1 {
+			w.writePES(layout.audio, i == audioEvery-1, nil)
+			continue
+		}
+		var pcr *uint64
+		if video%pcrEvery == 0 {
+			v := pcrBase + uint64(video)*pcrStep
+			pcr = &v
+		}
+		w.writePES(layout.video, video == 0, pcr)
+		video++
+	}
+
+	return w.buf
+}
+
+// dropPacket удаляет пакет с указанным индексом, если он есть в сегменте
+func dropPacket(body []byte, index int) []byte {
+	start := index * tsPacketSize
+	if start+tsPacketSize > len(body) {
+		return body
+	}
+	return append(body[:start:start], body[start+tsPacketSize:]...)
+}
+
+func (w *tsWriter) nextCC(pid uint16) byte {
+	cc := w.cc[pid]
+	w.cc[pid] = (cc + 1) & 0x0F
+	return cc
+}
+
+func (w *tsWriter) header(pid uint16, pusi bool, adaptation bool) []byte {
+	pkt := make([]byte, 4, tsPacketSize)
+	pkt[0] = tsSyncByte
+	pkt[1] = byte(pid>>8) & 0x1F
+	if pusi {
+		pkt[1] |= 0x40
+	}
+	pkt[2] = byte(pid)
+	control := byte(0x10) // только payload
+	if adaptation {
+		control = 0x30 // adaptation field + payload
+	}
+	pkt[3] = control | w.nextCC(pid)
+	return pkt
+}
+
+// writePSI записывает PSI-секцию в один пакет с pointer_field и заполнением 0xFF
+func (w *tsWriter) writePSI(pid uint16, section []byte) {
+	pkt := w.header(pid, true, false)
+	pkt = append(pkt, 0x00) // pointer_field
+	pkt = append(pkt, section...)
+	for len(pkt) < tsPacketSize {
+		pkt = append(pkt, 0xFF)
+	}
+	w.buf = append(w.buf, pkt...)
+}
+
+// writePES записывает пакет элементарного потока; первый пакет начинается с заголовка PES
+func (w *tsWriter) writePES(pid uint16, pusi bool, pcr *uint64) {
+	pkt := w.header(pid, pusi, pcr != nil)
+	if pcr != nil {
+		base := *pcr / 300
+		ext := *pcr % 300
+		pkt = append(pkt,
+			7,    // adaptation_field_length
+			0x10, // PCR_flag
+			byte(base>>25), byte(base>>17), byte(base>>9), byte(base>>1),
+			byte(base<<7)|0x7E|byte(ext>>8), byte(ext),
+		)
+	}
+	if pusi {
+		streamID := byte(0xE0)
+		if pid == w.layout.audio {
+			streamID = 0xC0
+		}
+		pkt = append(pkt, 0x00, 0x00, 0x01, streamID, 0x00, 0x00, 0x80, 0x00, 0x00)
+	}
+	for len(pkt) < tsPacketSize {
+		pkt = append(pkt, 0xAA)
+	}
+	w.buf = append(w.buf, pkt...)
+}
+
+func patSection() []byte {
+	section := []byte{
+		0x00,       // table_id: PAT
+		0xB0, 0x0D, // section_syntax_indicator + section_length = 13
+		0x00, 0x01, // transport_stream_id
+		0xC1,       // version 0, current_next_indicator
+		0x00, 0x00, // section_number, last_section_number
+		0x00, 0x01, // program_number = 1
+		0xE0 | byte(pmtPID>>8), byte(pmtPID & 0xFF),
+	}
+	return appendCRC(section)
+}
+
+func pmtSection(layout tsLayout) []byte {
+	section := []byte{
+		0x02,       // table_id: PMT
+		0xB0, 0x17, // section_length = 23
+		0x00, 0x01, // program_number = 1
+		0xC1,
+		0x00, 0x00,
+		0xE0 | byte(layout.video>>8), byte(layout.video & 0xFF), // PCR_PID
+		0xF0, 0x00, // program_info_length = 0
+		streamTypeH264, 0xE0 | byte(layout.video>>8), byte(layout.video & 0xFF), 0xF0, 0x00,
+		streamTypeAAC, 0xE0 | byte(layout.audio>>8), byte(layout.audio & 0xFF), 0xF0, 0x00,
+	}
+	return appendCRC(section)
+}
+
+func appendCRC(section []byte) []byte {
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32MPEG2(section))
+	return append(section, crc...)
+}
+
+// crc32MPEG2 CRC-32/MPEG-2, используемый в PSI-таблицах
+func crc32MPEG2(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,105 @@
+// Package webauth защищает собственный HTTP-сервер экспортера Basic-авторизацией
+// с паролями в виде хешей bcrypt
+package webauth
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxCachedLogins ограничивает кэш успешных проверок паролей
+const maxCachedLogins = 1024
+
+// BasicAuth проверяет учетные данные запросов по хешам bcrypt пользователей
+type BasicAuth struct {
+	users map[string][]byte
+	// bearerPrefix путь, запросы к которому с Authorization: Bearer пропускаются
+	// без Basic-авторизации: их токены проверяет API (пусто - не пропускаются)
+	bearerPrefix string
+	// exempt пути без авторизации, например проверка здоровья для балансировщика
+	exempt map[string]bool
+
+	// Проверка bcrypt намеренно медленная, а Prometheus повторяет одни и те же
+	// учетные данные при каждом сборе: успешные проверки кэшируются
+	mu     sync.Mutex
+	logins map[[sha256.Size]byte]struct{}
+}
+
+// New создает проверку для пользователей users (имя -> хеш bcrypt). Запросы с токеном
+// Bearer к путям с префиксом bearerPrefix авторизует обработчик API, пути exempt
+// доступны без авторизации.
+func New(users map[string]string, bearerPrefix string, exempt ...string) *BasicAuth {
+	a := &BasicAuth{
+		users:        make(map[string][]byte, len(users)),
+		bearerPrefix: bearerPrefix,
+		exempt:       make(map[string]bool, len(exempt)),
+		logins:       make(map[[sha256.Size]byte]struct{}),
+	}
+	for user, hash := range users {
+		a.users[user] = []byte(hash)
+	}
+	for _, path := range exempt {
+		a.exempt[path] = true
+	}
+	return a
+}
+
+// Wrap возвращает обработчик, отвечающий 401 на запросы без верных учетных данных
+func (a *BasicAuth) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.exempt[r.URL.Path] || a.bearer(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		user, password, ok := r.BasicAuth()
+		if !ok || !a.Verify(user, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="hls_exporter", charset="UTF-8"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearer сообщает, что запрос к API несет токен Bearer
+func (a *BasicAuth) bearer(r *http.Request) bool {
+	return a.bearerPrefix != "" &&
+		strings.HasPrefix(r.URL.Path, a.bearerPrefix) &&
+		strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// Verify сверяет пароль пользователя с хешем bcrypt
+func (a *BasicAuth) Verify(user, password string) bool {
+	hash, ok := a.users[user]
+	if !ok {
+		// Сравнение с фиктивным хешем выравнивает время ответа для неизвестных пользователей
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+
+	key := sha256.Sum256([]byte(user + "\x00" + password + "\x00" + string(hash)))
+	a.mu.Lock()
+	_, cached := a.logins[key]
+	a.mu.Unlock()
+	if cached {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil {
+		return false
+	}
+	a.mu.Lock()
+	if len(a.logins) >= maxCachedLogins {
+		clear(a.logins)
+	}
+	a.logins[key] = struct{}{}
+	a.mu.Unlock()
+	return true
+}
+
+// dummyHash хеш bcrypt пароля, которого нет ни у одного пользователя
+var dummyHash = []byte("$2a$10$qrL1jgmG4kkIcPzoJg6zXOascAshf19KGmC.t17U2DESxC7DNzOeK")
@@ -0,0 +1,67 @@
+package webauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testHash хеш bcrypt пароля "secret" с минимальной стоимостью
+const testHash = "$2a$04$ivk2um3g3owgVk4kJmeUIOwBrLpDkY1cSbJTeES7XT1l46mwdoJzG"
+
+func TestBasicAuth_Wrap(t *testing.T) {
+	auth := New(map[string]string{"prometheus": testHash}, "/api/v1/", "/health")
+	handler := auth.Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		path       string
+		user       string
+		password   string
+		bearer     string
+		wantStatus int
+	}{
+		{name: "valid credentials", path: "/metrics", user: "prometheus", password: "secret", wantStatus: http.StatusOK},
+		{name: "wrong password", path: "/metrics", user: "prometheus", password: "wrong", wantStatus: http.StatusUnauthorized},
+		{name: "unknown user", path: "/metrics", user: "admin", password: "secret", wantStatus: http.StatusUnauthorized},
+		{name: "no credentials", path: "/metrics", wantStatus: http.StatusUnauthorized},
+		{name: "exempt path", path: "/health", wantStatus: http.StatusOK},
+		{name: "bearer token to API", path: "/api/v1/streams", bearer: "token", wantStatus: http.StatusOK},
+		{name: "bearer token outside API", path: "/metrics", bearer: "token", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Basic")
+			}
+		})
+	}
+}
+
+func TestBasicAuth_CachesLogins(t *testing.T) {
+	auth := New(map[string]string{"prometheus": testHash}, "")
+
+	assert.True(t, auth.Verify("prometheus", "secret"))
+	assert.Len(t, auth.logins, 1)
+	assert.True(t, auth.Verify("prometheus", "secret"))
+	assert.Len(t, auth.logins, 1)
+
+	// Неверные пароли не кэшируются
+	assert.False(t, auth.Verify("prometheus", "wrong"))
+	assert.Len(t, auth.logins, 1)
+}
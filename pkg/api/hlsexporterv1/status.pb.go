@@ -0,0 +1,1606 @@
+// gRPC-версия API результатов (/api/v1/results, /api/v1/streams).
+// Поля CheckResult соответствуют JSON-представлению pkg/models.CheckResult.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: hlsexporter/v1/status.proto
+
+package hlsexporterv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListStreamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStreamsRequest) Reset() {
+	*x = ListStreamsRequest{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStreamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStreamsRequest) ProtoMessage() {}
+
+func (x *ListStreamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStreamsRequest.ProtoReflect.Descriptor instead.
+func (*ListStreamsRequest) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{0}
+}
+
+type ListStreamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Streams       []*StreamState         `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStreamsResponse) Reset() {
+	*x = ListStreamsResponse{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStreamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStreamsResponse) ProtoMessage() {}
+
+func (x *ListStreamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStreamsResponse.ProtoReflect.Descriptor instead.
+func (*ListStreamsResponse) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListStreamsResponse) GetStreams() []*StreamState {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+type GetStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStreamRequest) Reset() {
+	*x = GetStreamRequest{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStreamRequest) ProtoMessage() {}
+
+func (x *GetStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStreamRequest.ProtoReflect.Descriptor instead.
+func (*GetStreamRequest) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetStreamRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// limit 0 - вся сохраненная история
+	Limit uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// since и until границы интервала (не задано - без границы)
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	Until *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=until,proto3" json:"until,omitempty"`
+	// failed_only только неуспешные проверки
+	FailedOnly    bool `protobuf:"varint,5,opt,name=failed_only,json=failedOnly,proto3" json:"failed_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetHistoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetHistoryRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *GetHistoryRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *GetHistoryRequest) GetFailedOnly() bool {
+	if x != nil {
+		return x.FailedOnly
+	}
+	return false
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*HistoryRecord       `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetHistoryResponse) GetRecords() []*HistoryRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// HistoryRecord запись истории проверок, соответствует pkg/models.HistoryRecord
+type HistoryRecord struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Stream          string                 `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
+	CheckId         string                 `protobuf:"bytes,2,opt,name=check_id,json=checkId,proto3" json:"check_id,omitempty"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Duration        *durationpb.Duration   `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Success         bool                   `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorType       string                 `protobuf:"bytes,6,opt,name=error_type,json=errorType,proto3" json:"error_type,omitempty"`
+	ErrorMessage    string                 `protobuf:"bytes,7,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	StatusCode      int32                  `protobuf:"varint,8,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	SegmentsChecked int32                  `protobuf:"varint,9,opt,name=segments_checked,json=segmentsChecked,proto3" json:"segments_checked,omitempty"`
+	SegmentsFailed  int32                  `protobuf:"varint,10,opt,name=segments_failed,json=segmentsFailed,proto3" json:"segments_failed,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HistoryRecord) Reset() {
+	*x = HistoryRecord{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRecord) ProtoMessage() {}
+
+func (x *HistoryRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRecord.ProtoReflect.Descriptor instead.
+func (*HistoryRecord) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HistoryRecord) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *HistoryRecord) GetCheckId() string {
+	if x != nil {
+		return x.CheckId
+	}
+	return ""
+}
+
+func (x *HistoryRecord) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *HistoryRecord) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *HistoryRecord) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *HistoryRecord) GetErrorType() string {
+	if x != nil {
+		return x.ErrorType
+	}
+	return ""
+}
+
+func (x *HistoryRecord) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *HistoryRecord) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *HistoryRecord) GetSegmentsChecked() int32 {
+	if x != nil {
+		return x.SegmentsChecked
+	}
+	return 0
+}
+
+func (x *HistoryRecord) GetSegmentsFailed() int32 {
+	if x != nil {
+		return x.SegmentsFailed
+	}
+	return 0
+}
+
+type CheckNowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckNowRequest) Reset() {
+	*x = CheckNowRequest{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckNowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckNowRequest) ProtoMessage() {}
+
+func (x *CheckNowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckNowRequest.ProtoReflect.Descriptor instead.
+func (*CheckNowRequest) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CheckNowRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type WatchResultsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// names пустой список - все стримы
+	Names         []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchResultsRequest) Reset() {
+	*x = WatchResultsRequest{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchResultsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResultsRequest) ProtoMessage() {}
+
+func (x *WatchResultsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResultsRequest.ProtoReflect.Descriptor instead.
+func (*WatchResultsRequest) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchResultsRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type StreamState struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url      string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Interval *durationpb.Duration   `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	Paused   bool                   `protobuf:"varint,4,opt,name=paused,proto3" json:"paused,omitempty"`
+	// owned false - стрим проверяет другой экземпляр кластера
+	Owned         bool         `protobuf:"varint,5,opt,name=owned,proto3" json:"owned,omitempty"`
+	LastResult    *CheckResult `protobuf:"bytes,6,opt,name=last_result,json=lastResult,proto3" json:"last_result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamState) Reset() {
+	*x = StreamState{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamState) ProtoMessage() {}
+
+func (x *StreamState) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamState.ProtoReflect.Descriptor instead.
+func (*StreamState) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StreamState) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *StreamState) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *StreamState) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *StreamState) GetOwned() bool {
+	if x != nil {
+		return x.Owned
+	}
+	return false
+}
+
+func (x *StreamState) GetLastResult() *CheckResult {
+	if x != nil {
+		return x.LastResult
+	}
+	return nil
+}
+
+type CheckResult struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	CheckId                string                 `protobuf:"bytes,1,opt,name=check_id,json=checkId,proto3" json:"check_id,omitempty"`
+	Success                bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	StreamStatus           *StreamStatus          `protobuf:"bytes,3,opt,name=stream_status,json=streamStatus,proto3" json:"stream_status,omitempty"`
+	StreamName             string                 `protobuf:"bytes,4,opt,name=stream_name,json=streamName,proto3" json:"stream_name,omitempty"`
+	Segments               *SegmentResults        `protobuf:"bytes,5,opt,name=segments,proto3" json:"segments,omitempty"`
+	Duration               *durationpb.Duration   `protobuf:"bytes,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	Timestamp              *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Error                  *CheckError            `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	Renditions             []*RenditionCheck      `protobuf:"bytes,9,rep,name=renditions,proto3" json:"renditions,omitempty"`
+	BytesDownloaded        int64                  `protobuf:"varint,10,opt,name=bytes_downloaded,json=bytesDownloaded,proto3" json:"bytes_downloaded,omitempty"`
+	BudgetExceeded         bool                   `protobuf:"varint,11,opt,name=budget_exceeded,json=budgetExceeded,proto3" json:"budget_exceeded,omitempty"`
+	LiveEdgeLatencySeconds float64                `protobuf:"fixed64,12,opt,name=live_edge_latency_seconds,json=liveEdgeLatencySeconds,proto3" json:"live_edge_latency_seconds,omitempty"`
+	Stale                  bool                   `protobuf:"varint,13,opt,name=stale,proto3" json:"stale,omitempty"`
+	UnknownTags            []string               `protobuf:"bytes,14,rep,name=unknown_tags,json=unknownTags,proto3" json:"unknown_tags,omitempty"`
+	BitrateBps             float64                `protobuf:"fixed64,15,opt,name=bitrate_bps,json=bitrateBps,proto3" json:"bitrate_bps,omitempty"`
+	Variants               []*VariantBitrate      `protobuf:"bytes,16,rep,name=variants,proto3" json:"variants,omitempty"`
+	CcErrors               int32                  `protobuf:"varint,17,opt,name=cc_errors,json=ccErrors,proto3" json:"cc_errors,omitempty"`
+	PcrMaxInterval         *durationpb.Duration   `protobuf:"bytes,18,opt,name=pcr_max_interval,json=pcrMaxInterval,proto3" json:"pcr_max_interval,omitempty"`
+	PcrJitter              *durationpb.Duration   `protobuf:"bytes,19,opt,name=pcr_jitter,json=pcrJitter,proto3" json:"pcr_jitter,omitempty"`
+	NullPacketRatio        float64                `protobuf:"fixed64,20,opt,name=null_packet_ratio,json=nullPacketRatio,proto3" json:"null_packet_ratio,omitempty"`
+	DeepCheck              bool                   `protobuf:"varint,21,opt,name=deep_check,json=deepCheck,proto3" json:"deep_check,omitempty"`
+	Warnings               []string               `protobuf:"bytes,22,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Dash                   *DASHCheck             `protobuf:"bytes,23,opt,name=dash,proto3" json:"dash,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *CheckResult) Reset() {
+	*x = CheckResult{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResult) ProtoMessage() {}
+
+func (x *CheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResult.ProtoReflect.Descriptor instead.
+func (*CheckResult) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckResult) GetCheckId() string {
+	if x != nil {
+		return x.CheckId
+	}
+	return ""
+}
+
+func (x *CheckResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CheckResult) GetStreamStatus() *StreamStatus {
+	if x != nil {
+		return x.StreamStatus
+	}
+	return nil
+}
+
+func (x *CheckResult) GetStreamName() string {
+	if x != nil {
+		return x.StreamName
+	}
+	return ""
+}
+
+func (x *CheckResult) GetSegments() *SegmentResults {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *CheckResult) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *CheckResult) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *CheckResult) GetError() *CheckError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *CheckResult) GetRenditions() []*RenditionCheck {
+	if x != nil {
+		return x.Renditions
+	}
+	return nil
+}
+
+func (x *CheckResult) GetBytesDownloaded() int64 {
+	if x != nil {
+		return x.BytesDownloaded
+	}
+	return 0
+}
+
+func (x *CheckResult) GetBudgetExceeded() bool {
+	if x != nil {
+		return x.BudgetExceeded
+	}
+	return false
+}
+
+func (x *CheckResult) GetLiveEdgeLatencySeconds() float64 {
+	if x != nil {
+		return x.LiveEdgeLatencySeconds
+	}
+	return 0
+}
+
+func (x *CheckResult) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *CheckResult) GetUnknownTags() []string {
+	if x != nil {
+		return x.UnknownTags
+	}
+	return nil
+}
+
+func (x *CheckResult) GetBitrateBps() float64 {
+	if x != nil {
+		return x.BitrateBps
+	}
+	return 0
+}
+
+func (x *CheckResult) GetVariants() []*VariantBitrate {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *CheckResult) GetCcErrors() int32 {
+	if x != nil {
+		return x.CcErrors
+	}
+	return 0
+}
+
+func (x *CheckResult) GetPcrMaxInterval() *durationpb.Duration {
+	if x != nil {
+		return x.PcrMaxInterval
+	}
+	return nil
+}
+
+func (x *CheckResult) GetPcrJitter() *durationpb.Duration {
+	if x != nil {
+		return x.PcrJitter
+	}
+	return nil
+}
+
+func (x *CheckResult) GetNullPacketRatio() float64 {
+	if x != nil {
+		return x.NullPacketRatio
+	}
+	return 0
+}
+
+func (x *CheckResult) GetDeepCheck() bool {
+	if x != nil {
+		return x.DeepCheck
+	}
+	return false
+}
+
+func (x *CheckResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *CheckResult) GetDash() *DASHCheck {
+	if x != nil {
+		return x.Dash
+	}
+	return nil
+}
+
+type StreamStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsLive        bool                   `protobuf:"varint,1,opt,name=is_live,json=isLive,proto3" json:"is_live,omitempty"`
+	VariantsCount int32                  `protobuf:"varint,2,opt,name=variants_count,json=variantsCount,proto3" json:"variants_count,omitempty"`
+	SegmentsCount int32                  `protobuf:"varint,3,opt,name=segments_count,json=segmentsCount,proto3" json:"segments_count,omitempty"`
+	TotalDuration float64                `protobuf:"fixed64,4,opt,name=total_duration,json=totalDuration,proto3" json:"total_duration,omitempty"`
+	LastModified  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_modified,json=lastModified,proto3" json:"last_modified,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStatus) Reset() {
+	*x = StreamStatus{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStatus) ProtoMessage() {}
+
+func (x *StreamStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStatus.ProtoReflect.Descriptor instead.
+func (*StreamStatus) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamStatus) GetIsLive() bool {
+	if x != nil {
+		return x.IsLive
+	}
+	return false
+}
+
+func (x *StreamStatus) GetVariantsCount() int32 {
+	if x != nil {
+		return x.VariantsCount
+	}
+	return 0
+}
+
+func (x *StreamStatus) GetSegmentsCount() int32 {
+	if x != nil {
+		return x.SegmentsCount
+	}
+	return 0
+}
+
+func (x *StreamStatus) GetTotalDuration() float64 {
+	if x != nil {
+		return x.TotalDuration
+	}
+	return 0
+}
+
+func (x *StreamStatus) GetLastModified() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastModified
+	}
+	return nil
+}
+
+type SegmentResults struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Checked int32                  `protobuf:"varint,1,opt,name=checked,proto3" json:"checked,omitempty"`
+	Failed  int32                  `protobuf:"varint,2,opt,name=failed,proto3" json:"failed,omitempty"`
+	Total   int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Details []*SegmentCheck        `protobuf:"bytes,4,rep,name=details,proto3" json:"details,omitempty"`
+	// listed сегменты в окнах загруженных медиаплейлистов
+	Listed        int32 `protobuf:"varint,5,opt,name=listed,proto3" json:"listed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SegmentResults) Reset() {
+	*x = SegmentResults{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SegmentResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SegmentResults) ProtoMessage() {}
+
+func (x *SegmentResults) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SegmentResults.ProtoReflect.Descriptor instead.
+func (*SegmentResults) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SegmentResults) GetChecked() int32 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *SegmentResults) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *SegmentResults) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SegmentResults) GetDetails() []*SegmentCheck {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *SegmentResults) GetListed() int32 {
+	if x != nil {
+		return x.Listed
+	}
+	return 0
+}
+
+type SegmentCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Bytes         int64                  `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	CcErrors      int32                  `protobuf:"varint,6,opt,name=cc_errors,json=ccErrors,proto3" json:"cc_errors,omitempty"`
+	TsPackets     int32                  `protobuf:"varint,7,opt,name=ts_packets,json=tsPackets,proto3" json:"ts_packets,omitempty"`
+	NullPackets   int32                  `protobuf:"varint,8,opt,name=null_packets,json=nullPackets,proto3" json:"null_packets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SegmentCheck) Reset() {
+	*x = SegmentCheck{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SegmentCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SegmentCheck) ProtoMessage() {}
+
+func (x *SegmentCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SegmentCheck.ProtoReflect.Descriptor instead.
+func (*SegmentCheck) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SegmentCheck) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SegmentCheck) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SegmentCheck) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *SegmentCheck) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *SegmentCheck) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *SegmentCheck) GetCcErrors() int32 {
+	if x != nil {
+		return x.CcErrors
+	}
+	return 0
+}
+
+func (x *SegmentCheck) GetTsPackets() int32 {
+	if x != nil {
+		return x.TsPackets
+	}
+	return 0
+}
+
+func (x *SegmentCheck) GetNullPackets() int32 {
+	if x != nil {
+		return x.NullPackets
+	}
+	return 0
+}
+
+type CheckError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// type значение models.ErrorType (playlist_download, segment_download, ...)
+	Type       string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Message    string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	StatusCode int32  `protobuf:"varint,3,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Retryable  bool   `protobuf:"varint,4,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// retry_after пауза Retry-After источника
+	RetryAfter    *durationpb.Duration `protobuf:"bytes,5,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckError) Reset() {
+	*x = CheckError{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckError) ProtoMessage() {}
+
+func (x *CheckError) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckError.ProtoReflect.Descriptor instead.
+func (*CheckError) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CheckError) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CheckError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CheckError) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *CheckError) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *CheckError) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+type RenditionCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	GroupId       string                 `protobuf:"bytes,2,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Language      string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	Url           string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	Success       bool                   `protobuf:"varint,6,opt,name=success,proto3" json:"success,omitempty"`
+	Error         *CheckError            `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenditionCheck) Reset() {
+	*x = RenditionCheck{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenditionCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenditionCheck) ProtoMessage() {}
+
+func (x *RenditionCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenditionCheck.ProtoReflect.Descriptor instead.
+func (*RenditionCheck) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RenditionCheck) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RenditionCheck) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *RenditionCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RenditionCheck) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *RenditionCheck) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RenditionCheck) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RenditionCheck) GetError() *CheckError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type VariantBitrate struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Url                string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Bandwidth          uint32                 `protobuf:"varint,2,opt,name=bandwidth,proto3" json:"bandwidth,omitempty"`
+	Resolution         string                 `protobuf:"bytes,3,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	MeasuredBitrateBps float64                `protobuf:"fixed64,4,opt,name=measured_bitrate_bps,json=measuredBitrateBps,proto3" json:"measured_bitrate_bps,omitempty"`
+	DeviationRatio     float64                `protobuf:"fixed64,5,opt,name=deviation_ratio,json=deviationRatio,proto3" json:"deviation_ratio,omitempty"`
+	SizeAnomaly        bool                   `protobuf:"varint,6,opt,name=size_anomaly,json=sizeAnomaly,proto3" json:"size_anomaly,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *VariantBitrate) Reset() {
+	*x = VariantBitrate{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantBitrate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantBitrate) ProtoMessage() {}
+
+func (x *VariantBitrate) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantBitrate.ProtoReflect.Descriptor instead.
+func (*VariantBitrate) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *VariantBitrate) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *VariantBitrate) GetBandwidth() uint32 {
+	if x != nil {
+		return x.Bandwidth
+	}
+	return 0
+}
+
+func (x *VariantBitrate) GetResolution() string {
+	if x != nil {
+		return x.Resolution
+	}
+	return ""
+}
+
+func (x *VariantBitrate) GetMeasuredBitrateBps() float64 {
+	if x != nil {
+		return x.MeasuredBitrateBps
+	}
+	return 0
+}
+
+func (x *VariantBitrate) GetDeviationRatio() float64 {
+	if x != nil {
+		return x.DeviationRatio
+	}
+	return 0
+}
+
+func (x *VariantBitrate) GetSizeAnomaly() bool {
+	if x != nil {
+		return x.SizeAnomaly
+	}
+	return false
+}
+
+type DASHCheck struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Url                       string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Success                   bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error                     *CheckError            `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	LiveEdgeLatencySeconds    float64                `protobuf:"fixed64,4,opt,name=live_edge_latency_seconds,json=liveEdgeLatencySeconds,proto3" json:"live_edge_latency_seconds,omitempty"`
+	LiveEdgeDivergenceSeconds float64                `protobuf:"fixed64,5,opt,name=live_edge_divergence_seconds,json=liveEdgeDivergenceSeconds,proto3" json:"live_edge_divergence_seconds,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *DASHCheck) Reset() {
+	*x = DASHCheck{}
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DASHCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DASHCheck) ProtoMessage() {}
+
+func (x *DASHCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_hlsexporter_v1_status_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DASHCheck.ProtoReflect.Descriptor instead.
+func (*DASHCheck) Descriptor() ([]byte, []int) {
+	return file_hlsexporter_v1_status_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DASHCheck) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DASHCheck) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DASHCheck) GetError() *CheckError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *DASHCheck) GetLiveEdgeLatencySeconds() float64 {
+	if x != nil {
+		return x.LiveEdgeLatencySeconds
+	}
+	return 0
+}
+
+func (x *DASHCheck) GetLiveEdgeDivergenceSeconds() float64 {
+	if x != nil {
+		return x.LiveEdgeDivergenceSeconds
+	}
+	return 0
+}
+
+var File_hlsexporter_v1_status_proto protoreflect.FileDescriptor
+
+const file_hlsexporter_v1_status_proto_rawDesc = "" +
+	"\n" +
+	"\x1bhlsexporter/v1/status.proto\x12\x0ehlsexporter.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x14\n" +
+	"\x12ListStreamsRequest\"L\n" +
+	"\x13ListStreamsResponse\x125\n" +
+	"\astreams\x18\x01 \x03(\v2\x1b.hlsexporter.v1.StreamStateR\astreams\"&\n" +
+	"\x10GetStreamRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xc2\x01\n" +
+	"\x11GetHistoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\rR\x05limit\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1f\n" +
+	"\vfailed_only\x18\x05 \x01(\bR\n" +
+	"failedOnly\"M\n" +
+	"\x12GetHistoryResponse\x127\n" +
+	"\arecords\x18\x01 \x03(\v2\x1d.hlsexporter.v1.HistoryRecordR\arecords\"\x86\x03\n" +
+	"\rHistoryRecord\x12\x16\n" +
+	"\x06stream\x18\x01 \x01(\tR\x06stream\x12\x19\n" +
+	"\bcheck_id\x18\x02 \x01(\tR\acheckId\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x125\n" +
+	"\bduration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x18\n" +
+	"\asuccess\x18\x05 \x01(\bR\asuccess\x12\x1d\n" +
+	"\n" +
+	"error_type\x18\x06 \x01(\tR\terrorType\x12#\n" +
+	"\rerror_message\x18\a \x01(\tR\ferrorMessage\x12\x1f\n" +
+	"\vstatus_code\x18\b \x01(\x05R\n" +
+	"statusCode\x12)\n" +
+	"\x10segments_checked\x18\t \x01(\x05R\x0fsegmentsChecked\x12'\n" +
+	"\x0fsegments_failed\x18\n" +
+	" \x01(\x05R\x0esegmentsFailed\"%\n" +
+	"\x0fCheckNowRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"+\n" +
+	"\x13WatchResultsRequest\x12\x14\n" +
+	"\x05names\x18\x01 \x03(\tR\x05names\"\xd6\x01\n" +
+	"\vStreamState\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x125\n" +
+	"\binterval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x16\n" +
+	"\x06paused\x18\x04 \x01(\bR\x06paused\x12\x14\n" +
+	"\x05owned\x18\x05 \x01(\bR\x05owned\x12<\n" +
+	"\vlast_result\x18\x06 \x01(\v2\x1b.hlsexporter.v1.CheckResultR\n" +
+	"lastResult\"\x9c\b\n" +
+	"\vCheckResult\x12\x19\n" +
+	"\bcheck_id\x18\x01 \x01(\tR\acheckId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12A\n" +
+	"\rstream_status\x18\x03 \x01(\v2\x1c.hlsexporter.v1.StreamStatusR\fstreamStatus\x12\x1f\n" +
+	"\vstream_name\x18\x04 \x01(\tR\n" +
+	"streamName\x12:\n" +
+	"\bsegments\x18\x05 \x01(\v2\x1e.hlsexporter.v1.SegmentResultsR\bsegments\x125\n" +
+	"\bduration\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\bduration\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x120\n" +
+	"\x05error\x18\b \x01(\v2\x1a.hlsexporter.v1.CheckErrorR\x05error\x12>\n" +
+	"\n" +
+	"renditions\x18\t \x03(\v2\x1e.hlsexporter.v1.RenditionCheckR\n" +
+	"renditions\x12)\n" +
+	"\x10bytes_downloaded\x18\n" +
+	" \x01(\x03R\x0fbytesDownloaded\x12'\n" +
+	"\x0fbudget_exceeded\x18\v \x01(\bR\x0ebudgetExceeded\x129\n" +
+	"\x19live_edge_latency_seconds\x18\f \x01(\x01R\x16liveEdgeLatencySeconds\x12\x14\n" +
+	"\x05stale\x18\r \x01(\bR\x05stale\x12!\n" +
+	"\funknown_tags\x18\x0e \x03(\tR\vunknownTags\x12\x1f\n" +
+	"\vbitrate_bps\x18\x0f \x01(\x01R\n" +
+	"bitrateBps\x12:\n" +
+	"\bvariants\x18\x10 \x03(\v2\x1e.hlsexporter.v1.VariantBitrateR\bvariants\x12\x1b\n" +
+	"\tcc_errors\x18\x11 \x01(\x05R\bccErrors\x12C\n" +
+	"\x10pcr_max_interval\x18\x12 \x01(\v2\x19.google.protobuf.DurationR\x0epcrMaxInterval\x128\n" +
+	"\n" +
+	"pcr_jitter\x18\x13 \x01(\v2\x19.google.protobuf.DurationR\tpcrJitter\x12*\n" +
+	"\x11null_packet_ratio\x18\x14 \x01(\x01R\x0fnullPacketRatio\x12\x1d\n" +
+	"\n" +
+	"deep_check\x18\x15 \x01(\bR\tdeepCheck\x12\x1a\n" +
+	"\bwarnings\x18\x16 \x03(\tR\bwarnings\x12-\n" +
+	"\x04dash\x18\x17 \x01(\v2\x19.hlsexporter.v1.DASHCheckR\x04dash\"\xdd\x01\n" +
+	"\fStreamStatus\x12\x17\n" +
+	"\ais_live\x18\x01 \x01(\bR\x06isLive\x12%\n" +
+	"\x0evariants_count\x18\x02 \x01(\x05R\rvariantsCount\x12%\n" +
+	"\x0esegments_count\x18\x03 \x01(\x05R\rsegmentsCount\x12%\n" +
+	"\x0etotal_duration\x18\x04 \x01(\x01R\rtotalDuration\x12?\n" +
+	"\rlast_modified\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\flastModified\"\xa8\x01\n" +
+	"\x0eSegmentResults\x12\x18\n" +
+	"\achecked\x18\x01 \x01(\x05R\achecked\x12\x16\n" +
+	"\x06failed\x18\x02 \x01(\x05R\x06failed\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x126\n" +
+	"\adetails\x18\x04 \x03(\v2\x1c.hlsexporter.v1.SegmentCheckR\adetails\x12\x16\n" +
+	"\x06listed\x18\x05 \x01(\x05R\x06listed\"\xfa\x01\n" +
+	"\fSegmentCheck\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x125\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x14\n" +
+	"\x05bytes\x18\x04 \x01(\x03R\x05bytes\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x1b\n" +
+	"\tcc_errors\x18\x06 \x01(\x05R\bccErrors\x12\x1d\n" +
+	"\n" +
+	"ts_packets\x18\a \x01(\x05R\ttsPackets\x12!\n" +
+	"\fnull_packets\x18\b \x01(\x05R\vnullPackets\"\xb5\x01\n" +
+	"\n" +
+	"CheckError\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vstatus_code\x18\x03 \x01(\x05R\n" +
+	"statusCode\x12\x1c\n" +
+	"\tretryable\x18\x04 \x01(\bR\tretryable\x12:\n" +
+	"\vretry_after\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryAfter\"\xcd\x01\n" +
+	"\x0eRenditionCheck\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x19\n" +
+	"\bgroup_id\x18\x02 \x01(\tR\agroupId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x18\n" +
+	"\asuccess\x18\x06 \x01(\bR\asuccess\x120\n" +
+	"\x05error\x18\a \x01(\v2\x1a.hlsexporter.v1.CheckErrorR\x05error\"\xde\x01\n" +
+	"\x0eVariantBitrate\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1c\n" +
+	"\tbandwidth\x18\x02 \x01(\rR\tbandwidth\x12\x1e\n" +
+	"\n" +
+	"resolution\x18\x03 \x01(\tR\n" +
+	"resolution\x120\n" +
+	"\x14measured_bitrate_bps\x18\x04 \x01(\x01R\x12measuredBitrateBps\x12'\n" +
+	"\x0fdeviation_ratio\x18\x05 \x01(\x01R\x0edeviationRatio\x12!\n" +
+	"\fsize_anomaly\x18\x06 \x01(\bR\vsizeAnomaly\"\xe5\x01\n" +
+	"\tDASHCheck\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x120\n" +
+	"\x05error\x18\x03 \x01(\v2\x1a.hlsexporter.v1.CheckErrorR\x05error\x129\n" +
+	"\x19live_edge_latency_seconds\x18\x04 \x01(\x01R\x16liveEdgeLatencySeconds\x12?\n" +
+	"\x1clive_edge_divergence_seconds\x18\x05 \x01(\x01R\x19liveEdgeDivergenceSeconds2\xa6\x03\n" +
+	"\rStatusService\x12V\n" +
+	"\vListStreams\x12\".hlsexporter.v1.ListStreamsRequest\x1a#.hlsexporter.v1.ListStreamsResponse\x12J\n" +
+	"\tGetStream\x12 .hlsexporter.v1.GetStreamRequest\x1a\x1b.hlsexporter.v1.StreamState\x12S\n" +
+	"\n" +
+	"GetHistory\x12!.hlsexporter.v1.GetHistoryRequest\x1a\".hlsexporter.v1.GetHistoryResponse\x12H\n" +
+	"\bCheckNow\x12\x1f.hlsexporter.v1.CheckNowRequest\x1a\x1b.hlsexporter.v1.CheckResult\x12R\n" +
+	"\fWatchResults\x12#.hlsexporter.v1.WatchResultsRequest\x1a\x1b.hlsexporter.v1.CheckResult0\x01B7Z5github.com/iudanet/hls_exporter/pkg/api/hlsexporterv1b\x06proto3"
+
+var (
+	file_hlsexporter_v1_status_proto_rawDescOnce sync.Once
+	file_hlsexporter_v1_status_proto_rawDescData []byte
+)
+
+func file_hlsexporter_v1_status_proto_rawDescGZIP() []byte {
+	file_hlsexporter_v1_status_proto_rawDescOnce.Do(func() {
+		file_hlsexporter_v1_status_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hlsexporter_v1_status_proto_rawDesc), len(file_hlsexporter_v1_status_proto_rawDesc)))
+	})
+	return file_hlsexporter_v1_status_proto_rawDescData
+}
+
+var file_hlsexporter_v1_status_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_hlsexporter_v1_status_proto_goTypes = []any{
+	(*ListStreamsRequest)(nil),    // 0: hlsexporter.v1.ListStreamsRequest
+	(*ListStreamsResponse)(nil),   // 1: hlsexporter.v1.ListStreamsResponse
+	(*GetStreamRequest)(nil),      // 2: hlsexporter.v1.GetStreamRequest
+	(*GetHistoryRequest)(nil),     // 3: hlsexporter.v1.GetHistoryRequest
+	(*GetHistoryResponse)(nil),    // 4: hlsexporter.v1.GetHistoryResponse
+	(*HistoryRecord)(nil),         // 5: hlsexporter.v1.HistoryRecord
+	(*CheckNowRequest)(nil),       // 6: hlsexporter.v1.CheckNowRequest
+	(*WatchResultsRequest)(nil),   // 7: hlsexporter.v1.WatchResultsRequest
+	(*StreamState)(nil),           // 8: hlsexporter.v1.StreamState
+	(*CheckResult)(nil),           // 9: hlsexporter.v1.CheckResult
+	(*StreamStatus)(nil),          // 10: hlsexporter.v1.StreamStatus
+	(*SegmentResults)(nil),        // 11: hlsexporter.v1.SegmentResults
+	(*SegmentCheck)(nil),          // 12: hlsexporter.v1.SegmentCheck
+	(*CheckError)(nil),            // 13: hlsexporter.v1.CheckError
+	(*RenditionCheck)(nil),        // 14: hlsexporter.v1.RenditionCheck
+	(*VariantBitrate)(nil),        // 15: hlsexporter.v1.VariantBitrate
+	(*DASHCheck)(nil),             // 16: hlsexporter.v1.DASHCheck
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 18: google.protobuf.Duration
+}
+var file_hlsexporter_v1_status_proto_depIdxs = []int32{
+	8,  // 0: hlsexporter.v1.ListStreamsResponse.streams:type_name -> hlsexporter.v1.StreamState
+	17, // 1: hlsexporter.v1.GetHistoryRequest.since:type_name -> google.protobuf.Timestamp
+	17, // 2: hlsexporter.v1.GetHistoryRequest.until:type_name -> google.protobuf.Timestamp
+	5,  // 3: hlsexporter.v1.GetHistoryResponse.records:type_name -> hlsexporter.v1.HistoryRecord
+	17, // 4: hlsexporter.v1.HistoryRecord.timestamp:type_name -> google.protobuf.Timestamp
+	18, // 5: hlsexporter.v1.HistoryRecord.duration:type_name -> google.protobuf.Duration
+	18, // 6: hlsexporter.v1.StreamState.interval:type_name -> google.protobuf.Duration
+	9,  // 7: hlsexporter.v1.StreamState.last_result:type_name -> hlsexporter.v1.CheckResult
+	10, // 8: hlsexporter.v1.CheckResult.stream_status:type_name -> hlsexporter.v1.StreamStatus
+	11, // 9: hlsexporter.v1.CheckResult.segments:type_name -> hlsexporter.v1.SegmentResults
+	18, // 10: hlsexporter.v1.CheckResult.duration:type_name -> google.protobuf.Duration
+	17, // 11: hlsexporter.v1.CheckResult.timestamp:type_name -> google.protobuf.Timestamp
+	13, // 12: hlsexporter.v1.CheckResult.error:type_name -> hlsexporter.v1.CheckError
+	14, // 13: hlsexporter.v1.CheckResult.renditions:type_name -> hlsexporter.v1.RenditionCheck
+	15, // 14: hlsexporter.v1.CheckResult.variants:type_name -> hlsexporter.v1.VariantBitrate
+	18, // 15: hlsexporter.v1.CheckResult.pcr_max_interval:type_name -> google.protobuf.Duration
+	18, // 16: hlsexporter.v1.CheckResult.pcr_jitter:type_name -> google.protobuf.Duration
+	16, // 17: hlsexporter.v1.CheckResult.dash:type_name -> hlsexporter.v1.DASHCheck
+	17, // 18: hlsexporter.v1.StreamStatus.last_modified:type_name -> google.protobuf.Timestamp
+	12, // 19: hlsexporter.v1.SegmentResults.details:type_name -> hlsexporter.v1.SegmentCheck
+	18, // 20: hlsexporter.v1.SegmentCheck.duration:type_name -> google.protobuf.Duration
+	18, // 21: hlsexporter.v1.CheckError.retry_after:type_name -> google.protobuf.Duration
+	13, // 22: hlsexporter.v1.RenditionCheck.error:type_name -> hlsexporter.v1.CheckError
+	13, // 23: hlsexporter.v1.DASHCheck.error:type_name -> hlsexporter.v1.CheckError
+	0,  // 24: hlsexporter.v1.StatusService.ListStreams:input_type -> hlsexporter.v1.ListStreamsRequest
+	2,  // 25: hlsexporter.v1.StatusService.GetStream:input_type -> hlsexporter.v1.GetStreamRequest
+	3,  // 26: hlsexporter.v1.StatusService.GetHistory:input_type -> hlsexporter.v1.GetHistoryRequest
+	6,  // 27: hlsexporter.v1.StatusService.CheckNow:input_type -> hlsexporter.v1.CheckNowRequest
+	7,  // 28: hlsexporter.v1.StatusService.WatchResults:input_type -> hlsexporter.v1.WatchResultsRequest
+	1,  // 29: hlsexporter.v1.StatusService.ListStreams:output_type -> hlsexporter.v1.ListStreamsResponse
+	8,  // 30: hlsexporter.v1.StatusService.GetStream:output_type -> hlsexporter.v1.StreamState
+	4,  // 31: hlsexporter.v1.StatusService.GetHistory:output_type -> hlsexporter.v1.GetHistoryResponse
+	9,  // 32: hlsexporter.v1.StatusService.CheckNow:output_type -> hlsexporter.v1.CheckResult
+	9,  // 33: hlsexporter.v1.StatusService.WatchResults:output_type -> hlsexporter.v1.CheckResult
+	29, // [29:34] is the sub-list for method output_type
+	24, // [24:29] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
+}
+
+func init() { file_hlsexporter_v1_status_proto_init() }
+func file_hlsexporter_v1_status_proto_init() {
+	if File_hlsexporter_v1_status_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hlsexporter_v1_status_proto_rawDesc), len(file_hlsexporter_v1_status_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hlsexporter_v1_status_proto_goTypes,
+		DependencyIndexes: file_hlsexporter_v1_status_proto_depIdxs,
+		MessageInfos:      file_hlsexporter_v1_status_proto_msgTypes,
+	}.Build()
+	File_hlsexporter_v1_status_proto = out.File
+	file_hlsexporter_v1_status_proto_goTypes = nil
+	file_hlsexporter_v1_status_proto_depIdxs = nil
+}
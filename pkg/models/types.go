@@ -1,9 +1,17 @@
 package models
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafov/m3u8"
@@ -17,6 +25,8 @@ type Checker interface {
 	// Управление жизненным циклом
 	Start() error
 	Stop() error
+	// Удаление состояния, накопленного между проверками удаленного стрима
+	Forget(name string)
 }
 
 type Validator interface {
@@ -33,6 +43,8 @@ type HTTPClient interface {
 	GetPlaylist(ctx context.Context, url string) (*PlaylistResponse, error)
 	// Загрузка и валидация сегмента
 	GetSegment(ctx context.Context, url string, validate bool) (*SegmentResponse, error)
+	// Загрузка ключа AES-128 сегментов; ответ в том же виде, что у плейлиста
+	GetKey(ctx context.Context, url string) (*PlaylistResponse, error)
 	// Конфигурация клиента
 	SetTimeout(timeout time.Duration)
 	Close() error
@@ -41,20 +53,238 @@ type HTTPClient interface {
 type MetricsCollector interface {
 	// Основные метрики
 	SetStreamUp(name string, up bool)
-	RecordResponseTime(name string, duration float64)
+	// Метаданные настроенного стрима (hls_stream_info)
+	SetStreamInfo(stream StreamConfig)
+	// Удаление всех серий удаленного стрима
+	Reset(name string)
+	// Основная причина недоступности стрима (пусто - стрим доступен)
+	SetStreamDownReason(name, reason string)
+	// EVENT-плейлист удалил (removed) или изменил (modified) опубликованные сегменты
+	RecordEventViolation(name, variant, kind string)
+	// Распределение размеров и длительностей (EXTINF) проверенных сегментов
+	RecordSegmentSize(name string, bytes int64)
+	RecordSegmentDuration(name string, seconds float64)
+	// Отношение времени загрузки сегмента к его длительности
+	RecordSegmentDownloadRatio(name string, ratio float64)
+	// Запрос ключа AES-128 сегмента: из кэша (hit) или с сервера ключей
+	RecordKeyCacheRequest(name string, hit bool)
+	// Время ответа по типам запросов проверки (models.ResponseTime*)
+	RecordResponseTime(name, phase string, duration float64, traceID string)
+	// Длительность проверки целиком: загрузка, разбор и валидация
+	RecordCheckDuration(name string, duration float64, traceID string)
+	// Ответ HTTP на запрос проверки типа phase (models.ResponseTime*) с кодом code
+	RecordHTTPResponse(name, phase string, code int)
+	// Источник ограничил частоту запросов типа phase: ответ 429 или 503 с Retry-After
+	RecordRateLimited(name, phase string)
+	// Нарушение правила спецификации HLS (models.SpecRule*)
+	RecordSpecViolation(name, rule string)
+	// Длительность этапа проверки (models.Stage*)
+	RecordStageDuration(name, stage string, duration float64)
 	RecordSegmentCheck(name string, success bool)
+	// Результат проверки для счетчиков и скользящих долей успешных проверок (SLA)
+	RecordCheckResult(name string, success bool, timestamp time.Time)
 	// Детальные метрики
 	SetStreamBitrate(name string, bitrate float64)
+	SetVariantBitrate(name, bandwidth, resolution string, declared, measured float64)
 	SetSegmentsCount(name string, count int)
 	RecordError(name, errorType string)
 	// Служебные метрики
 	SetLastCheckTime(name string, timestamp time.Time)
+	// Время последней успешной проверки
+	SetLastSuccessTime(name string, timestamp time.Time)
 	SetActiveChecks(count int)
+	// Загрузка пула воркеров: занятые воркеры и задачи, ожидающие свободного воркера
+	SetWorkerPool(busy, queued int)
+	// Суммарное ожидание свободного воркера задачами проверки
+	RecordQueueWait(name string, seconds float64)
+	SetSchedulingDrift(name string, drift float64)
+	// Учет трафика
+	AddDownloadedBytes(name string, bytes int64)
+	SetBudgetExceeded(name string, exceeded bool)
+	// Альтернативные рендишены (EXT-X-MEDIA)
+	SetRenditionUp(name, renditionType, groupID, rendition string, up bool)
+	// Отставание live-края по EXT-X-PROGRAM-DATE-TIME
+	SetLiveEdgeLatency(name, variant string, latency float64)
+	// Расхождение часов упаковщика (EXT-X-PROGRAM-DATE-TIME) и заголовка Date сервера
+	SetPackagerClockSkew(name, variant string, skew float64)
+	// Нарушения RFC 8216 в строгом режиме
+	RecordConformanceViolation(name, rule string)
+	// Проблемы разбора плейлиста в режимах warn/strict
+	RecordParseIssue(name, kind string)
+	// Нестандартный тег, встреченный в плейлистах стрима
+	SetUnknownTag(name, tag string)
+	// Зависание live-плейлиста между проверками
+	SetPlaylistStale(name string, stale bool)
+	// Тип стрима (live или VOD) и наибольшая длительность медиаплейлистов в секундах
+	SetPlaylistType(name string, live bool, duration float64)
+	// Смена программ или PID элементарных потоков MPEG-TS
+	RecordPIDChange(name, kind string)
+	// Число тегов EXT-X-DISCONTINUITY и EXT-X-GAP в окне медиаплейлиста
+	SetPlaylistMarkers(name, variant string, discontinuities, gaps int)
+	// Новые разрывы медиаплейлиста с предыдущей проверки
+	AddDiscontinuities(name, variant string, count int)
+	// EXT-X-MEDIA-SEQUENCE медиаплейлиста
+	SetMediaSequence(name, variant string, sequence uint64)
+	// Заголовок Age ответа медиаплейлиста
+	SetPlaylistAge(name, variant string, age time.Duration)
+	// Причина ошибки, установленная диагностической проверкой
+	RecordErrorCause(name, cause string)
+	// Ожидание запроса к хосту из-за ограничения частоты (checks.host_budget)
+	AddHostBudgetWait(host string, wait time.Duration)
+	// Ожидание запроса из-за общего ограничения частоты (checks.max_requests_per_second)
+	AddRequestLimitWait(wait time.Duration)
+	// Записи EXT-X-SESSION-DATA мастер-плейлиста, заменяют опубликованные ранее
+	SetSessionData(name string, entries []SessionData)
+	// Число рекламных пауз и некорректных рекламных меток в окне медиаплейлиста
+	SetAdMarkers(name, variant string, breaks, malformed int)
+	// Результат последней проверки стрима с User-Agent из user_agents
+	SetUserAgentUp(name, userAgent string, up bool)
+	// Время последней рекламной метки медиаплейлиста
+	SetLastAdCue(name, variant string, timestamp time.Time)
+	// Кодек из TS-сегментов варианта, не объявленный в CODECS
+	RecordCodecMismatch(name, bandwidth, resolution, codec string)
+	// Нарушения счетчиков непрерывности TS-пакетов
+	AddCCErrors(name string, count int)
+	// Наибольшие интервал между PCR и джиттер PCR за проверку, секунды
+	SetPCRStats(name string, maxInterval, jitter float64)
+	// Доля null-пакетов (заполнения) в TS-сегментах за проверку
+	SetNullPacketRatio(name string, ratio float64)
+	// Время последней углубленной проверки по расписанию deep_check
+	SetLastDeepCheckTime(name string, timestamp time.Time)
+	// Действующий интервал проверок с учетом backoff
+	SetCheckInterval(name string, interval float64)
+	// Устойчивое падение размеров сегментов варианта относительно базовой линии
+	SetVariantSizeAnomaly(name, bandwidth, resolution string, anomaly bool)
+	// Циклический повтор одних и тех же сегментов в live-плейлистах стрима
+	SetContentLooping(name string, looping bool)
+	// Доступность DASH-версии канала и расхождение ее live-края с HLS
+	SetDASHUp(name string, up bool)
+	SetLiveEdgeDivergence(name string, divergence float64)
+	// Запуски проверок, пропущенные из-за проверки дольше интервала
+	AddChecksSkipped(name string, count int)
+	// Действующее правило silences для стрима
+	SetStreamSilenced(name string, silenced bool)
+	// Нарушение цели SLO стрима по результату последней проверки
+	SetSLOViolation(name, slo string, violated bool)
+	// Внешняя команда hook стрима завершилась с ненулевым кодом
+	SetStreamDegraded(name string, degraded bool)
+	// Пороги стрима из конфигурации: имя порога -> значение
+	SetThresholds(name string, thresholds map[string]float64)
+	// Метрики отдельных вариантов (ступеней ABR) мастер-плейлиста
+	RecordVariantSegmentCheck(name, bandwidth, resolution string, success bool)
+	RecordVariantResponseTime(name, bandwidth, resolution string, duration float64, traceID string)
+}
+
+// ResultStore хранит результаты последних проверок стримов
+type ResultStore interface {
+	Save(result *CheckResult)
+	Get(name string) (*CheckResult, bool)
+	List() []*CheckResult
+	// Recent краткие сводки последних проверок стрима, старые первыми
+	Recent(name string) []ResultSample
+	Delete(name string)
+}
+
+// ResultFeed рассылает результаты проверок подписчикам по мере сохранения
+type ResultFeed interface {
+	// Subscribe возвращает канал результатов и функцию отмены подписки.
+	// Результаты для не успевающего подписчика отбрасываются.
+	Subscribe() (<-chan *CheckResult, func())
+}
+
+// TransactionStore хранит журналы транзакций последних неуспешных проверок стримов
+type TransactionStore interface {
+	Save(bundle *TransactionBundle)
+	Get(stream string) (*TransactionBundle, bool)
+}
+
+// IncidentStore отслеживает текущие инциденты стримов по результатам проверок
+type IncidentStore interface {
+	// Observe учитывает результат: неуспешный открывает или продолжает инцидент, успешный закрывает
+	Observe(result *CheckResult)
+	Get(name string) (*Incident, bool)
+	List() []*Incident
+	Delete(name string)
+}
+
+// ResultRecorder записывает результат каждой проверки во внешнее хранилище
+type ResultRecorder interface {
+	Record(result *CheckResult) error
+}
+
+// HistoryStore сохраняет результаты всех проверок для разбора инцидентов
+type HistoryStore interface {
+	ResultRecorder
+	Query(ctx context.Context, q HistoryQuery) ([]HistoryRecord, error)
+}
+
+// FaultInjector внедряет задержки и ошибки на этапах загрузки
+type FaultInjector interface {
+	// Inject выполняет задержку и возвращает *InjectedFault, ошибку контекста или nil
+	Inject(ctx context.Context, stage FaultStage, url string) error
+}
+
+// TagInventory накапливает нестандартные теги, встреченные в плейлистах стримов
+type TagInventory interface {
+	UnknownTags(stream string) []UnknownTag
+}
+
+// StreamManager управляет набором проверяемых стримов во время работы
+type StreamManager interface {
+	Streams() []StreamConfig
+	Stream(name string) (StreamConfig, bool)
+	Add(stream StreamConfig) error
+	Update(stream StreamConfig) error
+	Remove(name string) error
+	// Приостановленные стримы не проверяются, но остаются в наборе
+	SetPaused(name string, paused bool) error
+	Paused(name string) bool
+	// Стримы других экземпляров кластера остаются в наборе, но не проверяются
+	Owns(name string) bool
+	// Внеочередная проверка стрима вне расписания
+	TriggerCheck(name string) error
+	// Время следующей плановой проверки (false - проверка не запланирована)
+	NextCheck(name string) (time.Time, bool)
+}
+
+// EventJournal журнал действий admin API с указанием инициатора
+type EventJournal interface {
+	Record(event AuditEvent)
+	// Events возвращает записи от новых к старым (stream "" - все, limit 0 - без ограничения)
+	Events(stream string, limit int) []AuditEvent
+}
+
+// ShardFilter определяет стримы, которые проверяет текущий экземпляр
+type ShardFilter interface {
+	Owns(stream string) bool
+}
+
+// Silencer подбирает правило silences, подавляющее оповещения о результате проверки
+type Silencer interface {
+	// Silence возвращает имя первого действующего правила, совпавшего с результатом
+	Silence(stream StreamConfig, result *CheckResult) (string, bool)
+}
+
+// ResultHook передает результат проверки внешней команде стрима (hook)
+type ResultHook interface {
+	// Run запускает команду и отмечает результат деградировавшим при ненулевом коде выхода.
+	// Ошибка возвращается, если команду не удалось запустить.
+	Run(ctx context.Context, stream StreamConfig, result *CheckResult) error
 }
 
 type ConfigLoader interface {
 	LoadConfig(path string) (*Config, error)
 }
+
+// ExternalValidator внешний валидатор, подключаемый как Go plugin или модуль WASM. Go plugin
+// экспортирует переменную Validator, реализующую интерфейс. Ошибка валидатора считается сбоем проверки.
+type ExternalValidator interface {
+	// Проверка загруженного плейлиста (мастер или медиа)
+	ValidatePlaylist(url string, body []byte) error
+	// Проверка сегмента, загруженного с валидацией содержимого
+	ValidateSegment(segment *SegmentData) error
+}
+
 type ConfigValidator interface {
 	Validate(cfg *Config) error
 	ValidateStream(stream *StreamConfig, index int) error
@@ -72,68 +302,1087 @@ type Config struct {
 	Checks  CheckConfig   `yaml:"checks" mapstructure:"checks"`
 	Logging LoggingConfig `yaml:"logging" mapstructure:"logging"`
 
-	HTTPClient HTTPConfig     `yaml:"http_client" mapstructure:"http_client"`
-	Streams    []StreamConfig `yaml:"streams" mapstructure:"streams"`
+	Soak SoakConfig `yaml:"soak" mapstructure:"soak"`
+
+	// Распределение стримов между экземплярами экспортера
+	Cluster ClusterConfig `yaml:"cluster" mapstructure:"cluster"`
+
+	HTTPClient HTTPConfig               `yaml:"http_client" mapstructure:"http_client"`
+	Profiles   map[string]ProfileConfig `yaml:"profiles" mapstructure:"profiles"`
+	Streams    []StreamConfig           `yaml:"streams" mapstructure:"streams"`
+
+	// Именованные устройства: наборы заголовков запросов конкретных плееров
+	Devices map[string]DeviceConfig `yaml:"devices,omitempty" mapstructure:"devices"`
+
+	// Правила внедрения сбоев, учитываются только в debug-сборке
+	FaultInjection []FaultRule `yaml:"fault_injection,omitempty" mapstructure:"fault_injection"`
+
+	// Правила подавления оповещений на время плановых работ
+	Silences []SilenceRule `yaml:"silences,omitempty" mapstructure:"silences"`
+
+	// Внешние валидаторы, подключаемые к стримам по имени
+	Plugins []PluginConfig `yaml:"plugins,omitempty" mapstructure:"plugins"`
+
+	Tracing TracingConfig `yaml:"tracing" mapstructure:"tracing"`
+
+	History HistoryConfig `yaml:"history" mapstructure:"history"`
+
+	EventLog EventLogConfig `yaml:"event_log" mapstructure:"event_log"`
+}
+
+// EventLogConfig журнал результатов проверок в формате JSON Lines для SIEM
+type EventLogConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+	Path    string `yaml:"path" mapstructure:"path"`
+	// Ротация при превышении размера файла в байтах (0 - без ограничения)
+	MaxSize int64 `yaml:"max_size" mapstructure:"max_size"`
+	// Ротация по границам периода, например 24h - в полночь UTC (0 - без ротации по времени)
+	RotateInterval time.Duration `yaml:"rotate_interval" mapstructure:"rotate_interval"`
+	// Число хранимых ротированных файлов (0 - все)
+	MaxBackups int `yaml:"max_backups" mapstructure:"max_backups"`
+}
+
+// HistoryConfig хранение истории проверок во встроенной базе SQLite
+type HistoryConfig struct {
+	Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+	Path    string `yaml:"path" mapstructure:"path"`
+	// Retention срок хранения записей (0 - без ограничения)
+	Retention time.Duration `yaml:"retention" mapstructure:"retention"`
+}
+
+// TracingConfig трассировка проверок: каждой проверке назначается trace_id, который
+// передается источнику в заголовке traceparent (W3C Trace Context) и прикрепляется
+// exemplar к гистограммам времени ответа и длительности проверки
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+}
+
+// PluginConfig внешний валидатор из Go plugin (.so)
+type PluginConfig struct {
+	Name string `yaml:"name" mapstructure:"name"`
+	Path string `yaml:"path" mapstructure:"path"`
+}
+
+// SilenceRule правило подавления оповещений о результатах проверок.
+// Правило действует, если совпали все выражения Matchers и текущее время
+// попадает в окно [Start, End) и, при заданном Cron, в окно Duration после срабатывания.
+type SilenceRule struct {
+	Name string `yaml:"name" mapstructure:"name"`
+	// Выражения вида stream=~"news_.*" или error_type!="segment_download"
+	Matchers []string `yaml:"matchers" mapstructure:"matchers"`
+	// Start и End в формате RFC 3339 (пусто - без ограничения)
+	Start string `yaml:"start,omitempty" mapstructure:"start"`
+	End   string `yaml:"end,omitempty" mapstructure:"end"`
+	// Повторяющееся окно: выражение cron из пяти полей и его длительность
+	Cron     string        `yaml:"cron,omitempty" mapstructure:"cron"`
+	Duration time.Duration `yaml:"duration,omitempty" mapstructure:"duration"`
+	Comment  string        `yaml:"comment,omitempty" mapstructure:"comment"`
+}
+
+// FaultStage этап загрузки, на котором внедряется сбой
+type FaultStage string
+
+const (
+	FaultStagePlaylist FaultStage = "playlist"
+	FaultStageSegment  FaultStage = "segment"
+	FaultStageKey      FaultStage = "key"
+)
+
+// FaultRule правило внедрения сбоя для хаос-тестирования
+type FaultRule struct {
+	Stage FaultStage `yaml:"stage" mapstructure:"stage"`
+	// URLContains ограничивает правило адресами с подстрокой (пусто - все)
+	URLContains string        `yaml:"url_contains" mapstructure:"url_contains"`
+	Delay       time.Duration `yaml:"delay" mapstructure:"delay"`
+	// StatusCode подменяет ответ кодом HTTP, Error - ошибкой транспорта
+	StatusCode int    `yaml:"status_code" mapstructure:"status_code"`
+	Error      string `yaml:"error" mapstructure:"error"`
+	// Probability вероятность срабатывания (0 - всегда)
+	Probability float64 `yaml:"probability" mapstructure:"probability"`
 }
 type ServerConfig struct {
 	Port        int    `yaml:"port" mapstructure:"port"`
 	MetricsPath string `yaml:"metrics_path" mapstructure:"metrics_path"`
 	HealthPath  string `yaml:"health_path" mapstructure:"health_path"`
+	// Условие готовности /-/ready (по умолчанию ReadinessChecked)
+	ReadinessMode string `yaml:"readiness_mode" mapstructure:"readiness_mode"`
+	// Включает изменяющие состояние эндпоинты /api/v1
+	AdminAPI bool `yaml:"admin_api" mapstructure:"admin_api"`
+	// Сжатие ответа /metrics (gzip/zstd по Accept-Encoding)
+	MetricsCompression bool `yaml:"metrics_compression" mapstructure:"metrics_compression"`
+	// Время кэширования собранных метрик (0 - без кэша)
+	MetricsCacheTTL time.Duration `yaml:"metrics_cache_ttl" mapstructure:"metrics_cache_ttl"`
+	// Токены доступа к /api/v1 (пусто - API без авторизации)
+	APITokens []APIToken `yaml:"api_tokens,omitempty" mapstructure:"api_tokens"`
+	// Сертификат и ключ TLS в PEM для HTTPS (пусто - HTTP)
+	TLSCertFile string `yaml:"tls_cert_file,omitempty" mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file,omitempty" mapstructure:"tls_key_file"`
+	// Пользователи Basic-авторизации: имя -> хеш bcrypt пароля (пусто - без авторизации)
+	BasicAuthUsers map[string]string `yaml:"basic_auth_users,omitempty" mapstructure:"basic_auth_users"`
+	// Файл web config в формате prometheus/exporter-toolkit вместо параметров выше
+	WebConfigFile string `yaml:"web_config_file,omitempty" mapstructure:"web_config_file"`
+	// Адрес gRPC-сервера StatusService, например ":9091" (пусто - gRPC выключен)
+	GRPCAddress string `yaml:"grpc_address,omitempty" mapstructure:"grpc_address"`
+}
+
+// APIToken токен доступа к API с областью видимости стримов
+type APIToken struct {
+	Name  string `yaml:"name" mapstructure:"name"`
+	Token string `yaml:"token" mapstructure:"token"`
+	// Выражения меток видимых стримов, например stream=~"news_.*" (пусто - все стримы)
+	Selector []string `yaml:"selector,omitempty" mapstructure:"selector"`
+	// Разрешает изменяющие запросы admin API
+	Admin bool `yaml:"admin" mapstructure:"admin"`
 }
+
+// Условия готовности экспортера на /-/ready
+const (
+	// Каждый проверяемый стрим прошел хотя бы одну проверку
+	ReadinessChecked = "checked"
+	// Дополнительно хотя бы один стрим доступен: при недоступности всех стримов
+	// балансировщик уводит трафик с экземпляра, например при отказе его сети
+	ReadinessAnyUp = "any_up"
+)
+
 type LoggingConfig struct {
 	Level       string `yaml:"level" mapstructure:"level"`
 	Encoding    string `yaml:"encoding" mapstructure:"encoding"`
 	Development bool   `yaml:"development" mapstructure:"development"`
 }
+
+// SoakConfig режим длительного прогона с контролем утечек ресурсов
+type SoakConfig struct {
+	Enabled  bool          `yaml:"enabled" mapstructure:"enabled"`
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+	// Пороги для алертов (0 - без порога)
+	MaxGoroutines int   `yaml:"max_goroutines" mapstructure:"max_goroutines"`
+	MaxHeapBytes  int64 `yaml:"max_heap_bytes" mapstructure:"max_heap_bytes"`
+	MaxOpenFDs    int   `yaml:"max_open_fds" mapstructure:"max_open_fds"`
+}
+
+// ClusterConfig режим кластера: каждый экземпляр проверяет только свою часть стримов.
+// Задается либо ShardCount/ShardIndex, либо Peers/Self (хэш-кольцо).
+type ClusterConfig struct {
+	// Статическое шардирование: стрим принадлежит экземпляру с номером hash(name) % ShardCount
+	ShardCount int `yaml:"shard_count" mapstructure:"shard_count"`
+	ShardIndex int `yaml:"shard_index" mapstructure:"shard_index"`
+	// Хэш-кольцо: при изменении списка узлов переезжает только часть стримов
+	Peers []string `yaml:"peers" mapstructure:"peers"`
+	Self  string   `yaml:"self" mapstructure:"self"`
+}
+
+// Enabled сообщает, включен ли режим кластера
+func (c ClusterConfig) Enabled() bool {
+	return c.ShardCount > 0 || len(c.Peers) > 0
+}
+
 type CheckConfig struct {
 	Workers       int           `yaml:"workers" mapstructure:"workers"`
 	RetryAttempts int           `yaml:"retry_attempts" mapstructure:"retry_attempts"`
 	RetryDelay    time.Duration `yaml:"retry_delay" mapstructure:"retry_delay"`
 	SegmentSample int           `yaml:"segment_sample" mapstructure:"segment_sample"`
+	// Максимум одновременных загрузок в рамках одной проверки (0 - размер пула воркеров)
+	MaxConcurrencyPerCheck int `yaml:"max_concurrency_per_check" mapstructure:"max_concurrency_per_check"`
+	// Порог коэффициента вариации длительностей сегментов плейлиста (0 - без проверки)
+	SegmentDurationMaxCV float64 `yaml:"segment_duration_max_cv" mapstructure:"segment_duration_max_cv"`
+	// Проверка EXTINF <= EXT-X-TARGETDURATION по RFC 8216 для всех медиаплейлистов
+	TargetDurationCheck bool `yaml:"target_duration_check" mapstructure:"target_duration_check"`
+	// Допустимое отклонение EXTINF от EXT-X-TARGETDURATION, доля (0 - без проверки)
+	SegmentDurationTolerance float64 `yaml:"segment_duration_tolerance" mapstructure:"segment_duration_tolerance"`
+	// CollectOnScrape запускает проверки при запросе метрик вместо периодических:
+	// результат, полученный не раньше interval назад, используется повторно
+	CollectOnScrape bool `yaml:"collect_on_scrape" mapstructure:"collect_on_scrape"`
+	// Максимум одновременных проверок при сборе метрик (0 - число воркеров)
+	ScrapeConcurrency int `yaml:"scrape_concurrency" mapstructure:"scrape_concurrency"`
+	// StaggerStart распределяет первые проверки стримов при запуске по их интервалам
+	StaggerStart bool `yaml:"stagger_start" mapstructure:"stagger_start"`
+	// Jitter случайное отклонение каждого интервала, доля от 0 до 0.5
+	Jitter float64 `yaml:"jitter" mapstructure:"jitter"`
+	// OverrunPolicy поведение, когда проверка длится дольше интервала
+	OverrunPolicy string `yaml:"overrun_policy" mapstructure:"overrun_policy"`
+	// TransactionLog сохраняет HTTP-транзакции неуспешных проверок для debug API
+	TransactionLog TransactionLogConfig `yaml:"transaction_log" mapstructure:"transaction_log"`
+	// HostBudget общее ограничение частоты запросов к хосту источника для всех его стримов
+	HostBudget HostBudgetConfig `yaml:"host_budget" mapstructure:"host_budget"`
+	// MaxRequestsPerSecond общее ограничение частоты всех запросов проверок (0 - без ограничения)
+	MaxRequestsPerSecond float64 `yaml:"max_requests_per_second" mapstructure:"max_requests_per_second"`
+	// KeyCacheTTL время хранения ключей AES-128 сегментов (0 - ключ загружается для каждого сегмента)
+	KeyCacheTTL time.Duration `yaml:"key_cache_ttl" mapstructure:"key_cache_ttl"`
+	// DurationBuckets границы гистограммы hls_check_duration_seconds в секундах
+	// (пусто - metrics.DefaultCheckDurationBuckets)
+	DurationBuckets []float64 `yaml:"duration_buckets,omitempty" mapstructure:"duration_buckets"`
+	// SLAWindows окна скользящей доли успешных проверок hls_stream_success_ratio
+	// (пусто - metrics.DefaultSLAWindows)
+	SLAWindows []time.Duration `yaml:"sla_windows,omitempty" mapstructure:"sla_windows"`
+	// SilencedLabel добавляет метку silenced="true|false" ко всем метрикам стримов
+	// при заданных silences. Смена значения начинает новые серии.
+	SilencedLabel bool `yaml:"silenced_label,omitempty" mapstructure:"silenced_label"`
+	// MaxFastRetryStreams максимум недоступных стримов, одновременно перепроверяемых
+	// с backoff fast_retry; остальные проверяются с обычным интервалом (0 - без ограничения)
+	MaxFastRetryStreams int `yaml:"max_fast_retry_streams" mapstructure:"max_fast_retry_streams"`
+}
+
+// HostBudgetConfig ограничение частоты запросов к одному хосту. Проверки стримов
+// с общим хостом дополнительно распределяются по их интервалу.
+type HostBudgetConfig struct {
+	// Запросов в секунду к одному хосту (0 - без ограничения)
+	RequestsPerSecond float64 `yaml:"requests_per_second" mapstructure:"requests_per_second"`
+	// Допустимый всплеск запросов (0 - requests_per_second, не меньше 1)
+	Burst int `yaml:"burst" mapstructure:"burst"`
 }
 
+// DefaultTransactionBodyBytes число сохраняемых байт тела ответа по умолчанию
+const DefaultTransactionBodyBytes = 16 * 1024
+
+// TransactionLogConfig журнал HTTP-транзакций неуспешных проверок
+type TransactionLogConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Число сохраняемых байт тела ответа (0 - DefaultTransactionBodyBytes)
+	MaxBodyBytes int `yaml:"max_body_bytes" mapstructure:"max_body_bytes"`
+}
+
+// Поведение при проверке дольше интервала
+const (
+	// Следующая проверка запускается сразу после завершения текущей
+	OverrunQueue = "queue"
+	// Пропущенные за время проверки запуски отбрасываются, следующая проверка
+	// выполняется в ближайшее время по исходной сетке интервала
+	OverrunSkip = "skip"
+)
+
 type HTTPConfig struct {
 	Timeout      time.Duration `yaml:"timeout" mapstructure:"timeout"`
 	KeepAlive    bool          `yaml:"keep_alive" mapstructure:"keep_alive"`
 	MaxIdleConns int           `yaml:"max_idle_conns" mapstructure:"max_idle_conns"`
 	TLSVerify    bool          `yaml:"tls_verify" mapstructure:"tls_verify"`
 	UserAgent    string        `yaml:"user_agent" mapstructure:"user_agent"`
+	// Заголовок с идентификатором проверки во всех запросах (пусто - не передается)
+	RequestIDHeader string `yaml:"request_id_header" mapstructure:"request_id_header"`
+	// Наибольший размер сегмента при проверке содержимого (0 - без ограничения)
+	MaxSegmentBytes int64 `yaml:"max_segment_bytes" mapstructure:"max_segment_bytes"`
+	// Параметры TLS по умолчанию для всех стримов
+	TLSConfig `yaml:",inline" mapstructure:",squash"`
+}
+
+// TLSConfig параметры TLS-соединений с источником
+type TLSConfig struct {
+	// Файл с сертификатами доверенных CA (PEM) вместо системных
+	CAFile string `yaml:"ca_file,omitempty" mapstructure:"ca_file" json:"ca_file,omitempty"`
+	// Клиентский сертификат и ключ для mTLS (PEM)
+	CertFile           string `yaml:"cert_file,omitempty" mapstructure:"cert_file" json:"cert_file,omitempty"`
+	KeyFile            string `yaml:"key_file,omitempty" mapstructure:"key_file" json:"key_file,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty" mapstructure:"insecure_skip_verify" json:"insecure_skip_verify,omitempty"`
+	// Минимальная версия TLS: 1.0, 1.1, 1.2 или 1.3 (пусто - по умолчанию Go)
+	MinTLSVersion string `yaml:"min_tls_version,omitempty" mapstructure:"min_tls_version" json:"min_tls_version,omitempty"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseMinTLSVersion возвращает константу crypto/tls для MinTLSVersion (0, если не задана)
+func (t TLSConfig) ParseMinTLSVersion() (uint16, error) {
+	if t.MinTLSVersion == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[t.MinTLSVersion]
+	if !ok {
+		return 0, fmt.Errorf("invalid min_tls_version: %s", t.MinTLSVersion)
+	}
+	return version, nil
+}
+
+// Merge возвращает параметры с переопределениями стрима: заданные поля
+// override заменяют значения по умолчанию
+func (t TLSConfig) Merge(override *TLSConfig) TLSConfig {
+	if override == nil {
+		return t
+	}
+	if override.CAFile != "" {
+		t.CAFile = override.CAFile
+	}
+	// Клиентский сертификат переопределяется только парой
+	if override.CertFile != "" {
+		t.CertFile = override.CertFile
+		t.KeyFile = override.KeyFile
+	}
+	if override.InsecureSkipVerify {
+		t.InsecureSkipVerify = true
+	}
+	if override.MinTLSVersion != "" {
+		t.MinTLSVersion = override.MinTLSVersion
+	}
+	return t
+}
+
+// ParseResolve разбирает записи resolve "host:port:addr" в соответствие
+// адреса соединения "host:port" адресу "addr:port". Имена хостов приводятся к нижнему регистру.
+func ParseResolve(entries []string) (map[string]string, error) {
+	addrs := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid resolve entry %q: expected host:port:addr", entry)
+		}
+		host, port, addr := parts[0], parts[1], strings.Trim(parts[2], "[]")
+
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return nil, fmt.Errorf("invalid resolve entry %q: invalid port %q", entry, port)
+		}
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid resolve entry %q: invalid address %q", entry, addr)
+		}
+		addrs[net.JoinHostPort(strings.ToLower(host), port)] = net.JoinHostPort(addr, port)
+	}
+	return addrs, nil
+}
+
+type resolveKey struct{}
+
+// WithResolve привязывает к контексту подмену адресов стрима
+func WithResolve(ctx context.Context, entries []string) context.Context {
+	return context.WithValue(ctx, resolveKey{}, entries)
+}
+
+// ResolveFrom возвращает привязанную к контексту подмену адресов
+func ResolveFrom(ctx context.Context) []string {
+	entries, _ := ctx.Value(resolveKey{}).([]string)
+	return entries
+}
+
+type checkIDKey struct{}
+
+// WithCheckID привязывает к контексту идентификатор проверки
+func WithCheckID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, checkIDKey{}, id)
+}
+
+// CheckIDFrom возвращает привязанный к контексту идентификатор проверки
+func CheckIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(checkIDKey{}).(string)
+	return id
+}
+
+type traceIDKey struct{}
+
+// WithTraceID привязывает к контексту идентификатор трассировки проверки
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, id)
+}
+
+// TraceIDFrom возвращает привязанный к контексту идентификатор трассировки
+func TraceIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+type segmentRangeKey struct{}
+
+// WithSegmentRange привязывает к контексту число первых байт сегмента для проверки содержимого
+func WithSegmentRange(ctx context.Context, bytes int64) context.Context {
+	return context.WithValue(ctx, segmentRangeKey{}, bytes)
+}
+
+// SegmentRangeFrom возвращает привязанное к контексту число байт выборки (0 - сегмент целиком)
+func SegmentRangeFrom(ctx context.Context) int64 {
+	bytes, _ := ctx.Value(segmentRangeKey{}).(int64)
+	return bytes
+}
+
+type segmentKeyKey struct{}
+
+// SegmentKey ключ и вектор инициализации сегмента, зашифрованного METHOD=AES-128
+type SegmentKey struct {
+	Key []byte
+	IV  []byte
+}
+
+// WithSegmentKey привязывает к контексту ключ расшифровки сегмента перед проверкой содержимого
+func WithSegmentKey(ctx context.Context, key SegmentKey) context.Context {
+	return context.WithValue(ctx, segmentKeyKey{}, key)
+}
+
+// SegmentKeyFrom возвращает привязанный к контексту ключ расшифровки сегмента
+func SegmentKeyFrom(ctx context.Context) (SegmentKey, bool) {
+	key, ok := ctx.Value(segmentKeyKey{}).(SegmentKey)
+	return key, ok
+}
+
+type streamTLSKey struct{}
+
+// WithStreamTLS привязывает к контексту переопределения TLS стрима
+func WithStreamTLS(ctx context.Context, override *TLSConfig) context.Context {
+	return context.WithValue(ctx, streamTLSKey{}, override)
+}
+
+// StreamTLSFrom возвращает привязанные к контексту переопределения TLS (nil, если их нет)
+func StreamTLSFrom(ctx context.Context) *TLSConfig {
+	override, _ := ctx.Value(streamTLSKey{}).(*TLSConfig)
+	return override
 }
 
 type StreamConfig struct {
 	Name            string           `yaml:"name" mapstructure:"name"`
 	URL             string           `yaml:"url" mapstructure:"url"`
+	Profile         string           `yaml:"profile,omitempty" mapstructure:"profile"`
 	CheckMode       string           `yaml:"check_mode" mapstructure:"check_mode"`
 	Interval        time.Duration    `yaml:"interval" mapstructure:"interval"`
 	Timeout         time.Duration    `yaml:"timeout" mapstructure:"timeout"`
 	ValidateContent bool             `yaml:"validate_content" mapstructure:"validate_content"`
 	MediaValidation *MediaValidation `yaml:"media_validation,omitempty" mapstructure:"media_validation"`
+	// Суточный лимит загруженных байт (0 - без ограничений)
+	DailyByteBudget int64 `yaml:"daily_byte_budget" mapstructure:"daily_byte_budget"`
+	// Проверка содержимого по первым RangeBytes байтам сегмента через Range-запрос (0 - сегмент целиком)
+	RangeBytes int64 `yaml:"range_bytes,omitempty" mapstructure:"range_bytes"`
+	// Число сегментов для режимов random и newest_n (0 - checks.segment_sample)
+	SegmentSample int `yaml:"segment_sample,omitempty" mapstructure:"segment_sample"`
+	// Строгий режим: проверка плейлистов на соответствие RFC 8216
+	Strict bool `yaml:"strict" mapstructure:"strict"`
+	// Выбор правил строгого режима (nil - правила по умолчанию)
+	ConformanceRules *ConformanceRulesConfig `yaml:"conformance_rules,omitempty" mapstructure:"conformance_rules"`
+	// Режим разбора плейлистов: lenient, warn или strict (пусто - lenient)
+	ParseMode string `yaml:"parse_mode,omitempty" mapstructure:"parse_mode"`
+	// Заголовки и авторизация запросов плейлистов и сегментов стрима
+	Headers     map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	BasicAuth   *BasicAuth        `yaml:"basic_auth,omitempty" mapstructure:"basic_auth"`
+	BearerToken string            `yaml:"bearer_token,omitempty" mapstructure:"bearer_token"`
+	// Переопределения параметров TLS из http_client
+	TLS *TLSConfig `yaml:"tls,omitempty" mapstructure:"tls"`
+	// Подмена адресов в формате curl --resolve: "host:port:addr".
+	// Host и SNI запросов сохраняются, соединение устанавливается с addr.
+	Resolve []string `yaml:"resolve,omitempty" mapstructure:"resolve"`
+	// Расписание углубленных проверок поверх обычных
+	DeepCheck *DeepCheckConfig `yaml:"deep_check,omitempty" mapstructure:"deep_check"`
+	// Изменение интервала проверок, пока стрим недоступен
+	Backoff *BackoffConfig `yaml:"backoff,omitempty" mapstructure:"backoff"`
+	// Обнаружение устойчивого падения размеров сегментов вариантов
+	SizeAnomaly *SizeAnomalyConfig `yaml:"size_anomaly,omitempty" mapstructure:"size_anomaly"`
+	// MPD-манифест MPEG-DASH того же канала для сравнения доступности и live-края с HLS
+	DASHURL string `yaml:"dash_url,omitempty" mapstructure:"dash_url"`
+	// Ограничение проверки отдельными вариантами мастер-плейлиста
+	VariantFilter *VariantFilter `yaml:"variant_filter,omitempty" mapstructure:"variant_filter"`
+	// Ожидаемая лестница вариантов мастер-плейлиста: число вариантов, набор разрешений
+	// (WxH) и семейств кодеков из CODECS (avc1, mp4a). Пустые значения не проверяются.
+	ExpectVariants    int      `yaml:"expect_variants,omitempty" mapstructure:"expect_variants"`
+	ExpectResolutions []string `yaml:"expect_resolutions,omitempty" mapstructure:"expect_resolutions"`
+	ExpectCodecs      []string `yaml:"expect_codecs,omitempty" mapstructure:"expect_codecs"`
+	// Имена внешних валидаторов из plugins
+	Validators []string `yaml:"validators,omitempty" mapstructure:"validators"`
+	// Внешняя команда, получающая результат каждой проверки
+	Hook *HookConfig `yaml:"hook,omitempty" mapstructure:"hook"`
+	// Цели по задержкам, нарушение которых публикуется отдельно от сбоев
+	SLO *SLOConfig `yaml:"slo,omitempty" mapstructure:"slo"`
+	// Разбор рекламных меток SCTE-35 (EXT-X-DATERANGE, EXT-X-CUE-OUT/CUE-IN) медиаплейлистов
+	AdMarkers bool `yaml:"ad_markers,omitempty" mapstructure:"ad_markers"`
+	// User-Agent плееров, по очереди используемые в проверках: строки или имена
+	// applecoremedia, exoplayer, hlsjs. Результаты публикуются отдельно по каждому.
+	UserAgents []string `yaml:"user_agents,omitempty" mapstructure:"user_agents"`
+	// Стрим должен быть live: медиаплейлист с EXT-X-ENDLIST или EXT-X-PLAYLIST-TYPE:VOD
+	// считается ошибкой unexpected_vod
+	ExpectLive bool `yaml:"expect_live,omitempty" mapstructure:"expect_live"`
+	// Имя устройства из devices, заголовки которого добавляются к запросам стрима
+	Device string `yaml:"device,omitempty" mapstructure:"device"`
+	// Загрузка и проверка JSON-документов, на которые ссылаются URI EXT-X-SESSION-DATA
+	ValidateSessionData bool `yaml:"validate_session_data,omitempty" mapstructure:"validate_session_data"`
+	// Группа стрима (редакция, пакет каналов) для дашбордов и селекторов меток
+	Group string `yaml:"group,omitempty" mapstructure:"group"`
+	// Пользовательские метки (region, cdn, tenant), добавляемые ко всем метрикам стрима
+	Labels map[string]string `yaml:"labels,omitempty" mapstructure:"labels"`
+}
+
+// DeviceConfig заголовки запросов, воспроизводящие конкретный плеер или устройство,
+// например для origin, выбирающих рекламу или путь доставки по заголовкам
+type DeviceConfig struct {
+	// User-Agent устройства; не применяется к стримам с user_agents
+	UserAgent string `yaml:"user_agent,omitempty" mapstructure:"user_agent"`
+	// Заголовки устройства: Accept, Origin, Referer и другие
+	Headers map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+}
+
+// Цели SLO стрима
+const (
+	SLOPlaylistLatency = "playlist_latency"
+	SLOSegmentDownload = "segment_download"
+)
+
+// SLOConfig цели по задержкам стрима. Нулевые цели не проверяются.
+type SLOConfig struct {
+	// Наибольшее время ответа на запрос плейлиста
+	MaxPlaylistLatency time.Duration `yaml:"max_playlist_latency,omitempty" mapstructure:"max_playlist_latency" json:"max_playlist_latency,omitempty"`
+	// Наибольшее время загрузки сегмента относительно его длительности (0.5 - за половину длительности).
+	// Проверяется только при validate_content без range_bytes.
+	MaxSegmentDownloadRatio float64 `yaml:"max_segment_download_ratio,omitempty" mapstructure:"max_segment_download_ratio" json:"max_segment_download_ratio,omitempty"`
+}
+
+// ConformanceRulesConfig правила строгого режима стрима: к правилам по умолчанию
+// добавляются Enable и убираются Disable
+type ConformanceRulesConfig struct {
+	Enable  []string `yaml:"enable,omitempty" mapstructure:"enable" json:"enable,omitempty"`
+	Disable []string `yaml:"disable,omitempty" mapstructure:"disable" json:"disable,omitempty"`
+}
+
+// DefaultHookTimeout время выполнения команды hook по умолчанию
+const DefaultHookTimeout = 10 * time.Second
+
+// HookConfig внешняя команда, получающая JSON результата проверки на stdin.
+// Ненулевой код выхода отмечает стрим деградировавшим.
+type HookConfig struct {
+	// Команда и аргументы, запускается без оболочки
+	Command []string `yaml:"command" mapstructure:"command" json:"command"`
+	// Время выполнения (0 - DefaultHookTimeout)
+	Timeout time.Duration `yaml:"timeout,omitempty" mapstructure:"timeout" json:"timeout,omitempty"`
+}
+
+// Выбор вариантов из прошедших фильтр
+const (
+	VariantSelectAll     = "all"
+	VariantSelectLowest  = "lowest"
+	VariantSelectHighest = "highest"
+)
+
+// VariantFilter ограничивает проверку стрима отдельными вариантами (ступенями ABR).
+// Сначала применяются пределы BANDWIDTH и выражение URI, затем из оставшихся
+// вариантов выбираются все, с наименьшим или с наибольшим BANDWIDTH.
+type VariantFilter struct {
+	// Пределы BANDWIDTH варианта включительно (0 - без ограничения)
+	MinBandwidth uint32 `yaml:"min_bandwidth,omitempty" mapstructure:"min_bandwidth" json:"min_bandwidth,omitempty"`
+	MaxBandwidth uint32 `yaml:"max_bandwidth,omitempty" mapstructure:"max_bandwidth" json:"max_bandwidth,omitempty"`
+	// Регулярное выражение для URI варианта из мастер-плейлиста
+	URIRegex string `yaml:"uri_regex,omitempty" mapstructure:"uri_regex" json:"uri_regex,omitempty"`
+	// all (по умолчанию), lowest или highest
+	Select string `yaml:"select,omitempty" mapstructure:"select" json:"select,omitempty"`
+}
+
+// Значения SizeAnomalyConfig по умолчанию
+const (
+	DefaultSizeAnomalyWindow    = 10
+	DefaultSizeAnomalyThreshold = 0.5
+	DefaultSizeAnomalyChecks    = 3
+)
+
+// SizeAnomalyConfig обнаружение падения битрейта варианта, измеренного по размерам
+// сегментов, относительно скользящего среднего предыдущих проверок (например, при
+// переходе кодировщика на заставку или черный экран). Нулевые поля - значения по умолчанию.
+type SizeAnomalyConfig struct {
+	// Window число проверок в базовой линии
+	Window int `yaml:"window,omitempty" mapstructure:"window" json:"window,omitempty"`
+	// Threshold доля базовой линии, ниже которой битрейт считается упавшим
+	Threshold float64 `yaml:"threshold,omitempty" mapstructure:"threshold" json:"threshold,omitempty"`
+	// Checks число проверок подряд с упавшим битрейтом до срабатывания
+	Checks int `yaml:"checks,omitempty" mapstructure:"checks" json:"checks,omitempty"`
+}
+
+// WithDefaults возвращает копию с заполненными значениями по умолчанию
+func (c SizeAnomalyConfig) WithDefaults() SizeAnomalyConfig {
+	c.Window = cmp.Or(c.Window, DefaultSizeAnomalyWindow)
+	c.Threshold = cmp.Or(c.Threshold, DefaultSizeAnomalyThreshold)
+	c.Checks = cmp.Or(c.Checks, DefaultSizeAnomalyChecks)
+	return c
 }
+
+// Политики интервала проверок недоступного стрима
+const (
+	BackoffExponential = "exponential"
+	BackoffFastRetry   = "fast_retry"
+)
+
+// BackoffConfig интервал проверок после неудачных проверок подряд.
+// После первой успешной проверки действует обычный интервал.
+type BackoffConfig struct {
+	Policy string `yaml:"policy" mapstructure:"policy" json:"policy"`
+	// exponential: интервал умножается на Multiplier (по умолчанию 2) после каждой
+	// неудачи, но не превышает MaxInterval
+	Multiplier  float64       `yaml:"multiplier,omitempty" mapstructure:"multiplier" json:"multiplier,omitempty"`
+	MaxInterval time.Duration `yaml:"max_interval,omitempty" mapstructure:"max_interval" json:"max_interval,omitempty"`
+	// fast_retry: пока стрим недоступен, проверки повторяются через RetryInterval
+	RetryInterval time.Duration `yaml:"retry_interval,omitempty" mapstructure:"retry_interval" json:"retry_interval,omitempty"`
+}
+
+// Interval возвращает интервал до следующей проверки после failures неудачных проверок подряд
+func (b *BackoffConfig) Interval(base time.Duration, failures int) time.Duration {
+	if b == nil || failures <= 0 {
+		return base
+	}
+
+	switch b.Policy {
+	case BackoffExponential:
+		multiplier := b.Multiplier
+		if multiplier == 0 {
+			multiplier = 2
+		}
+		limit := max(b.MaxInterval, base)
+		interval := float64(base)
+		for range failures {
+			interval *= multiplier
+			if interval >= float64(limit) {
+				return limit
+			}
+		}
+		return time.Duration(interval)
+	case BackoffFastRetry:
+		return b.RetryInterval
+	}
+	return base
+}
+
+// DeepCheckConfig расписание углубленной проверки: по интервалу или по cron
+// она заменяет очередную обычную проверку стрима
+type DeepCheckConfig struct {
+	Interval time.Duration `yaml:"interval,omitempty" mapstructure:"interval" json:"interval,omitempty"`
+	// Выражение cron из пяти полей в локальном часовом поясе
+	Cron string `yaml:"cron,omitempty" mapstructure:"cron" json:"cron,omitempty"`
+	// Режим выбора сегментов (пусто - all). Содержимое сегментов проверяется всегда.
+	CheckMode string `yaml:"check_mode,omitempty" mapstructure:"check_mode" json:"check_mode,omitempty"`
+}
+
+// Apply возвращает конфигурацию стрима для углубленной проверки
+func (d DeepCheckConfig) Apply(stream StreamConfig) StreamConfig {
+	stream.CheckMode = CheckModeAll
+	if d.CheckMode != "" {
+		stream.CheckMode = d.CheckMode
+	}
+	stream.ValidateContent = true
+	return stream
+}
+
+// RequestAuth возвращает заголовки и учетные данные для запросов стрима
+func (s StreamConfig) RequestAuth() RequestAuth {
+	return RequestAuth{
+		Headers:     s.Headers,
+		BasicAuth:   s.BasicAuth,
+		BearerToken: s.BearerToken,
+	}
+}
+
+// BasicAuth учетные данные HTTP Basic
+type BasicAuth struct {
+	Username string `yaml:"username" mapstructure:"username" json:"username"`
+	Password string `yaml:"password" mapstructure:"password" json:"password"`
+}
+
+// RequestAuth заголовки и учетные данные, которые HTTP-клиент добавляет к запросам
+type RequestAuth struct {
+	Headers     map[string]string
+	BasicAuth   *BasicAuth
+	BearerToken string
+	// User-Agent проверки вместо http_client.user_agent
+	UserAgent string
+}
+
+// IsZero сообщает, что заголовки и учетные данные не заданы
+func (a RequestAuth) IsZero() bool {
+	return len(a.Headers) == 0 && a.BasicAuth == nil && a.BearerToken == "" && a.UserAgent == ""
+}
+
+type requestAuthKey struct{}
+
+// WithRequestAuth привязывает к контексту заголовки и учетные данные запросов стрима
+func WithRequestAuth(ctx context.Context, auth RequestAuth) context.Context {
+	return context.WithValue(ctx, requestAuthKey{}, auth)
+}
+
+// RequestAuthFrom возвращает привязанные к контексту заголовки и учетные данные
+func RequestAuthFrom(ctx context.Context) RequestAuth {
+	auth, _ := ctx.Value(requestAuthKey{}).(RequestAuth)
+	return auth
+}
+
+// HTTPTransaction запрос к источнику, записанный в журнал транзакций проверки
+type HTTPTransaction struct {
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Start      time.Time `json:"start"`
+	// Время до получения заголовков ответа и до закрытия тела
+	FirstByte time.Duration `json:"first_byte"`
+	Duration  time.Duration `json:"duration"`
+	// Заголовки с учетными данными заменяются на "REDACTED"
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	// Начало текстового тела ответа; двоичные тела (сегменты) не сохраняются
+	Body          string `json:"body,omitempty"`
+	BodyBytes     int64  `json:"body_bytes"`
+	BodyTruncated bool   `json:"body_truncated,omitempty"`
+}
+
+// TransactionLog журнал HTTP-транзакций одной проверки, заполняется HTTP-клиентом
+type TransactionLog struct {
+	mu           sync.Mutex
+	maxBodyBytes int
+	transactions []HTTPTransaction
+}
+
+// NewTransactionLog создает журнал, сохраняющий до maxBodyBytes байт тела ответа
+func NewTransactionLog(maxBodyBytes int) *TransactionLog {
+	return &TransactionLog{maxBodyBytes: maxBodyBytes}
+}
+
+// MaxBodyBytes число сохраняемых байт тела ответа
+func (l *TransactionLog) MaxBodyBytes() int {
+	return l.maxBodyBytes
+}
+
+// Add добавляет завершенную транзакцию
+func (l *TransactionLog) Add(tx HTTPTransaction) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.transactions = append(l.transactions, tx)
+}
+
+// Transactions возвращает транзакции в порядке начала запросов
+func (l *TransactionLog) Transactions() []HTTPTransaction {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	transactions := slices.Clone(l.transactions)
+	slices.SortStableFunc(transactions, func(a, b HTTPTransaction) int {
+		return a.Start.Compare(b.Start)
+	})
+	return transactions
+}
+
+type transactionLogKey struct{}
+
+// WithTransactionLog привязывает к контексту журнал транзакций проверки
+func WithTransactionLog(ctx context.Context, log *TransactionLog) context.Context {
+	return context.WithValue(ctx, transactionLogKey{}, log)
+}
+
+// TransactionLogFrom возвращает привязанный к контексту журнал транзакций или nil
+func TransactionLogFrom(ctx context.Context) *TransactionLog {
+	log, _ := ctx.Value(transactionLogKey{}).(*TransactionLog)
+	return log
+}
+
+// TransactionBundle HTTP-транзакции неуспешной проверки для передачи поставщику CDN
+type TransactionBundle struct {
+	Stream       string            `json:"stream"`
+	CheckID      string            `json:"check_id"`
+	TraceID      string            `json:"trace_id,omitempty"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Error        *CheckError       `json:"error,omitempty"`
+	Transactions []HTTPTransaction `json:"transactions"`
+}
+
+// HistoryRecord запись истории проверок стрима
+type HistoryRecord struct {
+	Stream          string        `json:"stream"`
+	CheckID         string        `json:"check_id,omitempty"`
+	Timestamp       time.Time     `json:"timestamp"`
+	Duration        time.Duration `json:"duration"`
+	Success         bool          `json:"success"`
+	ErrorType       ErrorType     `json:"error_type,omitempty"`
+	ErrorMessage    string        `json:"error_message,omitempty"`
+	StatusCode      int           `json:"status_code,omitempty"`
+	SegmentsChecked int           `json:"segments_checked"`
+	SegmentsFailed  int           `json:"segments_failed"`
+}
+
+// ResultSample краткая сводка проверки для графиков панели мониторинга
+type ResultSample struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Duration  time.Duration `json:"duration"`
+	Success   bool          `json:"success"`
+	// Наибольший измеренный битрейт среди вариантов, бит/с
+	Bitrate float64     `json:"bitrate_bps,omitempty"`
+	Error   *CheckError `json:"error,omitempty"`
+}
+
+// HistoryQuery выборка истории проверок стрима, новые записи первыми
+type HistoryQuery struct {
+	Stream string
+	// Границы интервала (нулевое значение - без границы)
+	Since time.Time
+	Until time.Time
+	// Limit наибольшее число записей (0 - без ограничения)
+	Limit int
+	// FailedOnly только неуспешные проверки
+	FailedOnly bool
+}
+
+// Incident текущая проблема стрима: неуспешные проверки подряд с одинаковым отпечатком.
+// Отпечаток строится по типу ошибки, коду ответа и затронутым вариантам, поэтому
+// повторы одной проблемы не порождают новых инцидентов.
+type Incident struct {
+	Stream      string    `json:"stream"`
+	Fingerprint string    `json:"fingerprint"`
+	ErrorType   ErrorType `json:"error_type,omitempty"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	// Каталоги неуспешных сегментов и рендишены с ошибками
+	Variants []string `json:"variants,omitempty"`
+	// Сообщение об ошибке последней проверки
+	Message     string    `json:"message,omitempty"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	Occurrences int       `json:"occurrences"`
+	LastCheckID string    `json:"last_check_id,omitempty"`
+}
+
+// ProfileConfig именованный набор параметров проверки, общий для нескольких стримов.
+// Значения профиля применяются к незаданным полям стрима.
+type ProfileConfig struct {
+	CheckMode       string           `yaml:"check_mode" mapstructure:"check_mode"`
+	Interval        time.Duration    `yaml:"interval" mapstructure:"interval"`
+	Timeout         time.Duration    `yaml:"timeout" mapstructure:"timeout"`
+	ValidateContent bool             `yaml:"validate_content" mapstructure:"validate_content"`
+	MediaValidation *MediaValidation `yaml:"media_validation,omitempty" mapstructure:"media_validation"`
+	DailyByteBudget int64            `yaml:"daily_byte_budget" mapstructure:"daily_byte_budget"`
+	RangeBytes      int64            `yaml:"range_bytes,omitempty" mapstructure:"range_bytes"`
+	SegmentSample   int              `yaml:"segment_sample,omitempty" mapstructure:"segment_sample"`
+	Strict          bool             `yaml:"strict" mapstructure:"strict"`
+	ParseMode       string           `yaml:"parse_mode,omitempty" mapstructure:"parse_mode"`
+	// Выбор правил строгого режима профиля используется, если в стриме он не задан
+	ConformanceRules *ConformanceRulesConfig `yaml:"conformance_rules,omitempty" mapstructure:"conformance_rules"`
+	// Заголовки профиля дополняют заголовки стрима
+	Headers     map[string]string  `yaml:"headers,omitempty" mapstructure:"headers"`
+	BasicAuth   *BasicAuth         `yaml:"basic_auth,omitempty" mapstructure:"basic_auth"`
+	BearerToken string             `yaml:"bearer_token,omitempty" mapstructure:"bearer_token"`
+	TLS         *TLSConfig         `yaml:"tls,omitempty" mapstructure:"tls"`
+	DeepCheck   *DeepCheckConfig   `yaml:"deep_check,omitempty" mapstructure:"deep_check"`
+	Backoff     *BackoffConfig     `yaml:"backoff,omitempty" mapstructure:"backoff"`
+	SizeAnomaly *SizeAnomalyConfig `yaml:"size_anomaly,omitempty" mapstructure:"size_anomaly"`
+	// Фильтр вариантов профиля используется, если в стриме фильтр не задан
+	VariantFilter *VariantFilter `yaml:"variant_filter,omitempty" mapstructure:"variant_filter"`
+	Hook          *HookConfig    `yaml:"hook,omitempty" mapstructure:"hook"`
+	SLO           *SLOConfig     `yaml:"slo,omitempty" mapstructure:"slo"`
+	AdMarkers     bool           `yaml:"ad_markers,omitempty" mapstructure:"ad_markers"`
+	UserAgents    []string       `yaml:"user_agents,omitempty" mapstructure:"user_agents"`
+	ExpectLive    bool           `yaml:"expect_live,omitempty" mapstructure:"expect_live"`
+	Device        string         `yaml:"device,omitempty" mapstructure:"device"`
+
+	ValidateSessionData bool `yaml:"validate_session_data,omitempty" mapstructure:"validate_session_data"`
+}
+
 type MediaValidation struct {
-	ContainerType  []string `yaml:"container_type" mapstructure:"container_type"`
-	MinSegmentSize int64    `yaml:"min_segment_size" mapstructure:"min_segment_size"`
-	CheckAudio     bool     `yaml:"check_audio" mapstructure:"check_audio"`
-	CheckVideo     bool     `yaml:"check_video" mapstructure:"check_video"`
+	ContainerType  []string `yaml:"container_type" mapstructure:"container_type" json:"container_type"`
+	MinSegmentSize int64    `yaml:"min_segment_size" mapstructure:"min_segment_size" json:"min_segment_size"`
+	CheckAudio     bool     `yaml:"check_audio" mapstructure:"check_audio" json:"check_audio"`
+	CheckVideo     bool     `yaml:"check_video" mapstructure:"check_video" json:"check_video"`
+	// Пределы интервала между PCR и джиттера PCR для TS (0 - без проверки)
+	MaxPCRInterval time.Duration `yaml:"max_pcr_interval" mapstructure:"max_pcr_interval" json:"max_pcr_interval,omitempty"`
+	MaxPCRJitter   time.Duration `yaml:"max_pcr_jitter" mapstructure:"max_pcr_jitter" json:"max_pcr_jitter,omitempty"`
 }
 
 // Структуры результатов
 
 type CheckResult struct {
-	Success      bool
-	StreamStatus StreamStatus
-	StreamName   string
-	Segments     SegmentResults
-	Duration     time.Duration
-	Timestamp    time.Time
-	Error        *CheckError
+	// CheckID уникальный идентификатор проверки, передается источнику в заголовке запросов
+	CheckID         string           `json:"check_id,omitempty"`
+	Success         bool             `json:"success"`
+	StreamStatus    StreamStatus     `json:"stream_status"`
+	StreamName      string           `json:"stream_name"`
+	Segments        SegmentResults   `json:"segments"`
+	Duration        time.Duration    `json:"duration"`
+	Timestamp       time.Time        `json:"timestamp"`
+	Error           *CheckError      `json:"error,omitempty"`
+	Renditions      []RenditionCheck `json:"renditions,omitempty"`
+	BytesDownloaded int64            `json:"bytes_downloaded"`
+	BudgetExceeded  bool             `json:"budget_exceeded,omitempty"`
+	// Наибольшее отставание live-края среди вариантов, секунды
+	LiveEdgeLatency float64 `json:"live_edge_latency_seconds,omitempty"`
+	// Наибольшее по модулю расхождение EXT-X-PROGRAM-DATE-TIME live-края и заголовка Date, секунды
+	PackagerClockSkew float64 `json:"packager_clock_skew_seconds,omitempty"`
+	// Live-плейлист не продвигается между проверками
+	Stale bool `json:"stale,omitempty"`
+	// Причина зависания: StaleCauseUnchanged или StaleCauseCachedPlaylist
+	StaleCause string `json:"stale_cause,omitempty"`
+	// Нарушения RFC 8216, найденные в строгом режиме
+	Conformance []ConformanceViolation `json:"conformance_violations,omitempty"`
+	// Неизвестные теги и некорректные списки атрибутов (parse_mode warn/strict)
+	ParseIssues []ParseIssue `json:"parse_issues,omitempty"`
+	// Нестандартные теги, встреченные за проверку
+	UnknownTags []string `json:"unknown_tags,omitempty"`
+	// Наибольший измеренный битрейт среди вариантов, бит/с
+	Bitrate float64 `json:"bitrate_bps,omitempty"`
+	// Заявленный и измеренный битрейт вариантов мастер-плейлиста
+	Variants []VariantBitrate `json:"variants,omitempty"`
+	// Смены номеров программ и PID элементарных потоков MPEG-TS
+	PIDChanges []PIDChange `json:"pid_changes,omitempty"`
+	// Длительности этапов проверки по models.Stage*
+	Stages map[string]time.Duration `json:"stages,omitempty"`
+	// Суммарное ожидание задачами проверки свободного воркера пула
+	QueueWait time.Duration `json:"queue_wait,omitempty"`
+	// Наибольшее время ответа на запрос плейлиста (корневого и медиаплейлистов вариантов)
+	PlaylistResponseTime time.Duration `json:"playlist_response_time,omitempty"`
+	// Нарушенные цели SLO стрима, не влияют на успешность проверки
+	SLOViolations []string `json:"slo_violations,omitempty"`
+	// Число тегов EXT-X-DISCONTINUITY и EXT-X-GAP в окнах проверенных медиаплейлистов
+	Discontinuities int `json:"discontinuities,omitempty"`
+	Gaps            int `json:"gaps,omitempty"`
+	// Рекламные метки медиаплейлистов (при ad_markers)
+	AdMarkers *AdMarkersCheck `json:"ad_markers,omitempty"`
+	// User-Agent проверки из user_agents
+	UserAgent string `json:"user_agent,omitempty"`
+	// Идентификатор трассировки проверки (tracing.enabled), 32 hex-символа
+	TraceID string `json:"trace_id,omitempty"`
+	// Метаданные EXT-X-SESSION-DATA мастер-плейлиста
+	SessionData []SessionData `json:"session_data,omitempty"`
+	// Расхождения CODECS вариантов с элементарными потоками TS-сегментов
+	CodecMismatches []CodecMismatch `json:"codec_mismatches,omitempty"`
+	// Нарушения счетчиков непрерывности TS во всех проверенных сегментах
+	CCErrors int `json:"cc_errors,omitempty"`
+	// Наибольшие интервал между PCR и джиттер PCR среди проверенных сегментов
+	PCRMaxInterval time.Duration `json:"pcr_max_interval,omitempty"`
+	PCRJitter      time.Duration `json:"pcr_jitter,omitempty"`
+	// Доля null-пакетов среди TS-пакетов проверенных сегментов
+	NullPacketRatio float64 `json:"null_packet_ratio,omitempty"`
+	// Углубленная проверка по расписанию deep_check
+	DeepCheck bool `json:"deep_check,omitempty"`
+	// Предупреждения, не влияющие на успешность проверки
+	Warnings []string `json:"warnings,omitempty"`
+	// Сравнение с DASH-версией канала (dash_url), не влияет на успешность проверки
+	DASH *DASHCheck `json:"dash,omitempty"`
+	// Правило silences, подавившее оповещения о результате
+	Silence string `json:"silence,omitempty"`
+	// Внешняя команда hook отметила стрим деградировавшим, не влияет на успешность проверки
+	Degraded       bool   `json:"degraded,omitempty"`
+	DegradedReason string `json:"degraded_reason,omitempty"`
+}
+
+// Этапы проверки для CheckResult.Stages. Загрузки плейлистов и сегментов измеряются
+// по времени этапа с учетом параллельных запросов и включают валидацию, а validation -
+// суммарное время валидаторов плейлистов и сегментов.
+const (
+	StageMasterFetch     = "master_fetch"
+	StageVariantFetch    = "variant_fetch"
+	StageSegmentDownload = "segment_download"
+	StageValidation      = "validation"
+)
+
+// Типы запросов проверки: метка type времени ответа и phase счетчика ответов HTTP
+const (
+	// Загрузка мастер-плейлиста
+	ResponseTimeMaster = "master_playlist"
+	// Загрузка медиаплейлиста варианта или корневого медиаплейлиста стрима без мастер-плейлиста
+	ResponseTimeVariant = "variant_playlist"
+	// Загрузка сегмента (HEAD без validate_content)
+	ResponseTimeSegment = "segment"
+	// Загрузка ключа AES-128 сегментов с сервера ключей
+	ResponseTimeKey = "key"
+)
+
+// AdMarkersCheck рекламные метки в окнах проверенных медиаплейлистов
+type AdMarkersCheck struct {
+	// Наибольшее число рекламных пауз в окне среди медиаплейлистов
+	Breaks int `json:"breaks"`
+	// Время последней рекламной метки
+	LastCue time.Time `json:"last_cue,omitzero"`
+	// Описания некорректных меток
+	Malformed []string `json:"malformed,omitempty"`
+}
+
+// Предупреждения результата проверки
+const (
+	// Live-плейлист продвигается, но циклически повторяет одни и те же сегменты
+	WarningContentLooping = "content_looping"
+	// EXT-X-MEDIA-SEQUENCE live-плейлиста уменьшился с предыдущей проверки
+	WarningSequenceReset = "media_sequence_reset"
+	// Окно live-плейлиста сократилось больше чем вдвое с предыдущей проверки
+	WarningWindowShrink = "window_shrink"
+	// EVENT-плейлист удалил или изменил опубликованные сегменты с предыдущей проверки
+	WarningEventModified = "event_playlist_modified"
+	// Документ URI EXT-X-SESSION-DATA не загружается или не является JSON
+	WarningSessionData = "session_data_invalid"
+)
+
+// Причины зависания live-плейлиста
+const (
+	// Плейлист не меняется между проверками
+	StaleCauseUnchanged = "unchanged"
+	// CDN отдает закешированный плейлист: заголовок Age растет между проверками
+	StaleCauseCachedPlaylist = "cached_playlist"
+)
+
+// AddWarning добавляет предупреждение, если его еще нет в результате
+func (r *CheckResult) AddWarning(warning string) {
+	if !slices.Contains(r.Warnings, warning) {
+		r.Warnings = append(r.Warnings, warning)
+	}
+}
+
+// PIDChangeKind вид изменения структуры MPEG-TS
+type PIDChangeKind string
+
+const (
+	PIDChangeProgram    PIDChangeKind = "program_number"
+	PIDChangeElementary PIDChangeKind = "elementary_pid"
+)
+
+// CodecMismatch расхождение кодеков, объявленных в CODECS варианта, с найденными в сегментах
+type CodecMismatch struct {
+	URL string `json:"url"`
+	// Значение атрибута CODECS
+	Declared string `json:"declared"`
+	// Семейства кодеков из PMT сегментов, не объявленные в CODECS
+	Undeclared []string `json:"undeclared"`
+}
+
+// PIDChange изменение программ MPEG-TS относительно предыдущего сегмента варианта
+type PIDChange struct {
+	Kind PIDChangeKind `json:"kind"`
+	// URL сегмента, в котором обнаружено изменение
+	URL      string `json:"url"`
+	Previous string `json:"previous"`
+	Current  string `json:"current"`
+}
+
+// VariantBitrate сравнение заявленного BANDWIDTH варианта с измеренным по сегментам
+type VariantBitrate struct {
+	URL        string `json:"url"`
+	Bandwidth  uint32 `json:"bandwidth"`
+	Resolution string `json:"resolution,omitempty"`
+	// Битрейт по размерам и длительностям проверенных сегментов, бит/с
+	MeasuredBitrate float64 `json:"measured_bitrate_bps"`
+	// Отношение измеренного битрейта к заявленному (0, если BANDWIDTH не задан)
+	DeviationRatio float64 `json:"deviation_ratio"`
+	// Битрейт устойчиво ниже базовой линии предыдущих проверок (size_anomaly)
+	SizeAnomaly bool `json:"size_anomaly,omitempty"`
+}
+
+// UnknownTag нестандартный тег в плейлистах стрима
+type UnknownTag struct {
+	Tag       string    `json:"tag"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	// Checks число проверок, в которых встречался тег
+	Checks int64 `json:"checks"`
+}
+
+// ParseIssue строка плейлиста, которую m3u8-парсер молча пропускает
+type ParseIssue struct {
+	Kind    ParseIssueKind `json:"kind"`
+	Tag     string         `json:"tag"`
+	URL     string         `json:"url,omitempty"`
+	Line    int            `json:"line"`
+	Message string         `json:"message"`
+}
+
+type ParseIssueKind string
+
+const (
+	ParseIssueUnknownTag          ParseIssueKind = "unknown_tag"
+	ParseIssueMalformedAttributes ParseIssueKind = "malformed_attributes"
+)
+
+// ConformanceViolation нарушение RFC 8216 в плейлисте
+type ConformanceViolation struct {
+	Rule    string `json:"rule"`
+	URL     string `json:"url,omitempty"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
 }
 
 type StreamStatus struct {
-	IsLive        bool
-	VariantsCount int
-	SegmentsCount int
-	TotalDuration float64
-	LastModified  time.Time
+	// Ни один медиаплейлист не завершен EXT-X-ENDLIST и не имеет EXT-X-PLAYLIST-TYPE:VOD
+	IsLive        bool `json:"is_live"`
+	VariantsCount int  `json:"variants_count"`
+	SegmentsCount int  `json:"segments_count"`
+	// Наибольшая сумма EXTINF среди медиаплейлистов: длительность VOD или окна live, секунды
+	TotalDuration float64   `json:"total_duration"`
+	LastModified  time.Time `json:"last_modified"`
 }
 
 type SegmentResults struct {
@@ -141,13 +1390,29 @@ type SegmentResults struct {
 	Failed  int            `json:"failed"`
 	Total   int            `json:"total"`
 	Details []SegmentCheck `json:"details,omitempty"`
+	// Сегменты в окнах загруженных медиаплейлистов
+	Listed int `json:"listed"`
 }
 
 type SegmentCheck struct {
-	URL      string
-	Success  bool
-	Duration time.Duration
-	Error    *CheckError
+	URL      string        `json:"url"`
+	Success  bool          `json:"success"`
+	Duration time.Duration `json:"duration"`
+	Bytes    int64         `json:"bytes"`
+	// Размер сегмента по ответу сервера (для HEAD-запроса - по Content-Length)
+	Size int64 `json:"size"`
+	// Время загрузки сегмента целиком относительно его длительности из EXTINF
+	DownloadRatio float64 `json:"download_ratio,omitempty"`
+	// Программы MPEG-TS сегмента (при валидации контента)
+	Programs []TSProgram `json:"programs,omitempty"`
+	CCErrors int         `json:"cc_errors,omitempty"`
+	// Число TS-пакетов и null-пакетов (заполнения) в сегменте
+	Packets     int `json:"ts_packets,omitempty"`
+	NullPackets int `json:"null_packets,omitempty"`
+	// Наибольший интервал между PCR и джиттер PCR в сегменте
+	PCRMaxInterval time.Duration `json:"pcr_max_interval,omitempty"`
+	PCRJitter      time.Duration `json:"pcr_jitter,omitempty"`
+	Error          *CheckError   `json:"error,omitempty"`
 }
 
 func (sc SegmentCheck) String() string {
@@ -158,6 +1423,38 @@ func (sc SegmentCheck) String() string {
 	return result
 }
 
+// RenditionCheck результат проверки альтернативного рендишена из EXT-X-MEDIA
+type RenditionCheck struct {
+	Type     string      `json:"type"`
+	GroupID  string      `json:"group_id"`
+	Name     string      `json:"name"`
+	Language string      `json:"language,omitempty"`
+	URL      string      `json:"url"`
+	Success  bool        `json:"success"`
+	Error    *CheckError `json:"error,omitempty"`
+}
+
+// SessionData запись EXT-X-SESSION-DATA мастер-плейлиста
+type SessionData struct {
+	DataID   string `json:"data_id"`
+	Value    string `json:"value,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Language string `json:"language,omitempty"`
+	// Ошибка загрузки или разбора документа URI (validate_session_data)
+	Error *CheckError `json:"error,omitempty"`
+}
+
+// DASHCheck результат проверки MPD-манифеста DASH-версии канала
+type DASHCheck struct {
+	URL     string      `json:"url"`
+	Success bool        `json:"success"`
+	Error   *CheckError `json:"error,omitempty"`
+	// Отставание live-края по SegmentTimeline, секунды
+	LiveEdgeLatency float64 `json:"live_edge_latency_seconds,omitempty"`
+	// Разница отставаний live-края DASH и HLS, секунды (> 0 - DASH отстает)
+	LiveEdgeDivergence float64 `json:"live_edge_divergence_seconds,omitempty"`
+}
+
 type SegmentData struct {
 	URI       string
 	Duration  float64
@@ -172,6 +1469,30 @@ type MediaInfo struct {
 	HasVideo   bool
 	HasAudio   bool
 	IsComplete bool
+	// Программы MPEG-TS из PAT/PMT (только для TS)
+	Programs []TSProgram
+	// Нарушения счетчиков непрерывности TS-пакетов
+	CCErrors int
+	// Число TS-пакетов и null-пакетов (заполнения) среди них
+	Packets     int
+	NullPackets int
+	// Число PCR, наибольший интервал между ними и джиттер PCR
+	PCRCount       int
+	PCRMaxInterval time.Duration
+	PCRJitter      time.Duration
+}
+
+// TSProgram программа MPEG-TS: номер из PAT и элементарные потоки из PMT
+type TSProgram struct {
+	Number  uint16     `json:"number"`
+	PMTPID  uint16     `json:"pmt_pid"`
+	Streams []TSStream `json:"streams,omitempty"`
+}
+
+// TSStream элементарный поток программы
+type TSStream struct {
+	PID        uint16 `json:"pid"`
+	StreamType uint8  `json:"stream_type"`
 }
 
 // Структуры ответов
@@ -181,6 +1502,8 @@ type PlaylistResponse struct {
 	StatusCode int
 	Headers    http.Header
 	Duration   time.Duration
+	// Пауза из Retry-After ответа 429 или 503 (0 - не задана)
+	RetryAfter time.Duration
 }
 
 type SegmentResponse struct {
@@ -188,17 +1511,30 @@ type SegmentResponse struct {
 	StatusCode int
 	Size       int64
 	Duration   time.Duration
+	// Пауза из Retry-After ответа 429 или 503 (0 - не задана)
+	RetryAfter time.Duration
 }
 
 // Структуры ошибок
 
 type CheckError struct {
-	Type       ErrorType
-	Message    string
-	StatusCode int
-	Retryable  bool
+	Type       ErrorType `json:"type"`
+	Message    string    `json:"message"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Retryable  bool      `json:"retryable"`
+	// Причина, установленная диагностической проверкой (Cause*)
+	Cause string `json:"cause,omitempty"`
+	// Источник ограничил частоту запросов (Retry-After): следующая проверка
+	// откладывается не меньше чем на эту паузу
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
 }
 
+// Причины ошибок, установленные диагностическими проверками
+const (
+	// CDN отдает закешированный ответ 404 сегмента, который уже доступен в обход кеша
+	CauseCDNNegativeCache = "cdn_negative_cache"
+)
+
 type ErrorType string
 
 const (
@@ -207,6 +1543,109 @@ const (
 	ErrSegmentDownload  ErrorType = "segment_download"
 	ErrSegmentValidate  ErrorType = "segment_validate"
 	ErrMediaContainer   ErrorType = "media_container"
+	ErrRendition        ErrorType = "rendition_playlist"
+	ErrSegmentDuration  ErrorType = "segment_duration_variation"
+	ErrPlaylistStale    ErrorType = "playlist_stale"
+	ErrDASHManifest     ErrorType = "dash_manifest"
+	ErrVariantFilter    ErrorType = "variant_filter"
+	ErrLadderMismatch   ErrorType = "ladder_mismatch"
+	ErrPluginValidate   ErrorType = "plugin_validate"
+	ErrSpecViolation    ErrorType = "spec_violation"
+	ErrUnexpectedVOD    ErrorType = "unexpected_vod"
+	ErrSessionData      ErrorType = "session_data"
+	ErrKeyFetch         ErrorType = "key_fetch"
+
+	// Сетевые ошибки загрузки плейлиста или сегмента вместо playlist_download и segment_download
+	ErrDNS               ErrorType = "dns_failure"
+	ErrConnectionRefused ErrorType = "connection_refused"
+	ErrConnectionReset   ErrorType = "connection_reset"
+	ErrTLSHandshake      ErrorType = "tls_handshake"
+	ErrTimeout           ErrorType = "timeout"
+)
+
+// Правила соответствия длительностей сегментов спецификации HLS
+const (
+	// Округленная длительность EXTINF больше EXT-X-TARGETDURATION (RFC 8216, 4.3.3.1)
+	SpecRuleTargetDuration = "extinf_exceeds_target"
+	// Длительность EXTINF отклоняется от EXT-X-TARGETDURATION больше допуска
+	SpecRuleDurationTolerance = "duration_tolerance"
+)
+
+// SpecViolationError длительность сегмента нарушает правило спецификации HLS
+type SpecViolationError struct {
+	Rule     string
+	URI      string
+	Duration float64
+	Target   float64
+	// Допустимое отклонение от TARGETDURATION, доля (для SpecRuleDurationTolerance)
+	Tolerance float64
+}
+
+func (e *SpecViolationError) Error() string {
+	if e.Rule == SpecRuleDurationTolerance {
+		return fmt.Sprintf("segment %s duration %.3fs deviates from target duration %gs by more than %.0f%%",
+			e.URI, e.Duration, e.Target, e.Tolerance*100)
+	}
+	return fmt.Sprintf("segment %s duration %.3fs exceeds target duration %gs", e.URI, e.Duration, e.Target)
+}
+
+// SegmentDurationError разброс длительностей сегментов превышает порог,
+// обычно признак неверной настройки GOP на энкодере
+type SegmentDurationError struct {
+	CV        float64
+	Threshold float64
+	Min       float64
+	Max       float64
+}
+
+func (e *SegmentDurationError) Error() string {
+	return fmt.Sprintf("segment duration variation %.2f exceeds threshold %.2f (min %.3fs, max %.3fs)",
+		e.CV, e.Threshold, e.Min, e.Max)
+}
+
+// InjectedFault сбой, внедренный вместо реального ответа
+type InjectedFault struct {
+	Stage      FaultStage
+	StatusCode int
+	Message    string
+}
+
+func (f *InjectedFault) Error() string {
+	if f.StatusCode != 0 {
+		return fmt.Sprintf("injected %s fault: status %d", f.Stage, f.StatusCode)
+	}
+	return fmt.Sprintf("injected %s fault: %s", f.Stage, f.Message)
+}
+
+// Ошибки управления набором стримов
+var (
+	ErrStreamExists   = errors.New("stream already exists")
+	ErrStreamNotFound = errors.New("stream not found")
+	// Стрим приостановлен, проверяется другим экземпляром или при сборе метрик
+	ErrStreamInactive = errors.New("stream checks are not scheduled by this instance")
+)
+
+// AuditEvent запись журнала действий admin API
+type AuditEvent struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Stream string    `json:"stream,omitempty"`
+	// Principal пользователь из учетных данных запроса (пусто - анонимный запрос)
+	Principal  string `json:"principal,omitempty"`
+	RemoteAddr string `json:"remote_addr"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// Действия журнала admin API
+const (
+	AuditStreamAdded     = "stream_added"
+	AuditStreamUpdated   = "stream_updated"
+	AuditStreamPaused    = "stream_paused"
+	AuditStreamResumed   = "stream_resumed"
+	AuditStreamRemoved   = "stream_removed"
+	AuditOverrideSet     = "override_set"
+	AuditOverrideRemoved = "override_removed"
+	AuditCheckTriggered  = "check_triggered"
 )
 
 type ValidationError struct {
@@ -226,6 +1665,19 @@ const (
 	ErrNoVideo   ValidationType = "no_video"
 	ErrNoAudio   ValidationType = "no_audio"
 	ErrCorrupted ValidationType = "corrupted_media"
+	// Тайминг транспортного потока
+	ErrPCRInterval ValidationType = "pcr_interval"
+	ErrPCRJitter   ValidationType = "pcr_jitter"
+)
+
+// Режимы разбора плейлистов
+const (
+	// Неизвестные и некорректные строки игнорируются (поведение m3u8-парсера)
+	ParseModeLenient = "lenient"
+	// Проблемы разбора учитываются в метриках, проверка остается успешной
+	ParseModeWarn = "warn"
+	// Проблемы разбора проваливают проверку
+	ParseModeStrict = "strict"
 )
 
 // Константы для режимов проверки
@@ -233,8 +1685,17 @@ const (
 	CheckModeAll       = "all"
 	CheckModeFirstLast = "first_last"
 	CheckModeRandom    = "random"
+	// Последние segment_sample сегментов у live-края
+	CheckModeNewestN = "newest_n"
+	// Только плейлисты, без загрузки сегментов
+	CheckModePlaylistOnly = "playlist_only"
 )
 
+// SkipsSegments сообщает, что режим проверяет только плейлисты
+func SkipsSegments(mode string) bool {
+	return mode == CheckModePlaylistOnly
+}
+
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Type, e.Message)
 }
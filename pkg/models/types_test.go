@@ -1,7 +1,107 @@
 package models
 
-import "testing"
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestTypes(_ *testing.T) {
 	// TODO: implement tests
 }
+
+func TestTLSConfig_Merge(t *testing.T) {
+	defaults := TLSConfig{CAFile: "ca.pem", CertFile: "a.pem", KeyFile: "a-key.pem", MinTLSVersion: "1.2"}
+
+	assert.Equal(t, defaults, defaults.Merge(nil))
+	assert.Equal(t, TLSConfig{
+		CAFile:             "ca.pem",
+		CertFile:           "b.pem",
+		KeyFile:            "b-key.pem",
+		InsecureSkipVerify: true,
+		MinTLSVersion:      "1.3",
+	}, defaults.Merge(&TLSConfig{
+		CertFile:           "b.pem",
+		KeyFile:            "b-key.pem",
+		InsecureSkipVerify: true,
+		MinTLSVersion:      "1.3",
+	}))
+}
+
+func TestTLSConfig_ParseMinTLSVersion(t *testing.T) {
+	version, err := TLSConfig{}.ParseMinTLSVersion()
+	assert.NoError(t, err)
+	assert.Zero(t, version)
+
+	version, err = TLSConfig{MinTLSVersion: "1.3"}.ParseMinTLSVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+
+	_, err = TLSConfig{MinTLSVersion: "TLS1.2"}.ParseMinTLSVersion()
+	assert.Error(t, err)
+}
+
+func TestParseResolve(t *testing.T) {
+	addrs, err := ParseResolve([]string{"CDN.example.com:443:203.0.113.10", "cdn.example.com:80:[2001:db8::1]"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cdn.example.com:443": "203.0.113.10:443",
+		"cdn.example.com:80":  "[2001:db8::1]:80",
+	}, addrs)
+
+	for _, entry := range []string{"cdn.example.com:443", ":443:203.0.113.10", "cdn.example.com:https:203.0.113.10", "cdn.example.com:443:edge"} {
+		_, err := ParseResolve([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestDeepCheckConfig_Apply(t *testing.T) {
+	stream := StreamConfig{CheckMode: CheckModePlaylistOnly}
+
+	deep := DeepCheckConfig{Interval: time.Minute}.Apply(stream)
+	assert.Equal(t, CheckModeAll, deep.CheckMode)
+	assert.True(t, deep.ValidateContent)
+
+	deep = DeepCheckConfig{Interval: time.Minute, CheckMode: CheckModeRandom}.Apply(stream)
+	assert.Equal(t, CheckModeRandom, deep.CheckMode)
+}
+
+func TestBackoffConfig_Interval(t *testing.T) {
+	base := 30 * time.Second
+	exponential := &BackoffConfig{Policy: BackoffExponential, MaxInterval: 5 * time.Minute}
+	fastRetry := &BackoffConfig{Policy: BackoffFastRetry, RetryInterval: 5 * time.Second}
+
+	tests := []struct {
+		name     string
+		backoff  *BackoffConfig
+		failures int
+		want     time.Duration
+	}{
+		{name: "no backoff", failures: 3, want: base},
+		{name: "no failures", backoff: exponential, want: base},
+		{name: "exponential first failure", backoff: exponential, failures: 1, want: time.Minute},
+		{name: "exponential third failure", backoff: exponential, failures: 3, want: 4 * time.Minute},
+		{name: "exponential capped", backoff: exponential, failures: 10, want: 5 * time.Minute},
+		{
+			name:     "custom multiplier",
+			backoff:  &BackoffConfig{Policy: BackoffExponential, Multiplier: 1.5, MaxInterval: time.Hour},
+			failures: 2,
+			want:     67500 * time.Millisecond,
+		},
+		{
+			name:     "cap below interval",
+			backoff:  &BackoffConfig{Policy: BackoffExponential, MaxInterval: 10 * time.Second},
+			failures: 1,
+			want:     base,
+		},
+		{name: "fast retry", backoff: fastRetry, failures: 1, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.backoff.Interval(base, tt.failures))
+		})
+	}
+}